@@ -0,0 +1,240 @@
+// Command mcpctl is an operator tool for debugging AI provider configuration.
+//
+// It uses the same AI_* environment variables as the mcp package:
+//
+//	mcpctl chat [prompt]        one-shot prompt, or interactive chat without arguments
+//	mcpctl models list          list models served by the provider
+//	mcpctl config validate      check configuration without calling the provider
+//	mcpctl probe                health check (reachability, auth, model availability)
+//	mcpctl cost report <file>   aggregate an audit log (JSONL) by provider and model
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"nofx/mcp"
+)
+
+const usage = `usage: mcpctl [flags] <command>
+
+commands:
+  chat [prompt]         one-shot prompt, or interactive chat without arguments
+  models list           list models served by the provider
+  config validate       check configuration without calling the provider
+  probe                 health check of the provider
+  cost report <file>    aggregate an audit log (JSONL) by provider and model
+
+flags:
+`
+
+func main() {
+	flags := flag.NewFlagSet("mcpctl", flag.ExitOnError)
+	provider := flags.String("provider", "", "provider name (default: $"+mcp.EnvProvider+" or deepseek)")
+	model := flags.String("model", "", "model name (default: $"+mcp.EnvModel+" or provider default)")
+	baseURL := flags.String("base-url", "", "API base URL (default: $"+mcp.EnvBaseURL+" or provider default)")
+	system := flags.String("system", "", "system prompt for chat")
+	transcript := flags.String("transcript", "", "file interactive chat transcripts are saved to (.json or Markdown)")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// cost report works on files only, no client needed
+	if args[0] == "cost" {
+		if len(args) != 3 || args[1] != "report" {
+			fail(errors.New("usage: mcpctl cost report <audit.jsonl>"))
+		}
+		if err := costReport(os.Stdout, args[2]); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	envProvider, opts := mcp.EnvOptions()
+	if *provider == "" {
+		*provider = envProvider
+	}
+	if *baseURL != "" {
+		opts = append(opts, mcp.WithBaseURL(*baseURL))
+	}
+	if *model != "" {
+		opts = append(opts, mcp.WithModel(*model))
+	}
+	aiClient, err := mcp.NewProviderClient(*provider, opts...)
+	if err != nil {
+		fail(err)
+	}
+	client, ok := mcp.BaseClient(aiClient)
+	if !ok {
+		fail(fmt.Errorf("provider %q has no base client", *provider))
+	}
+	defer client.Close(context.Background())
+
+	switch {
+	case args[0] == "chat":
+		err = chat(ctx, client, *system, strings.Join(args[1:], " "), *transcript)
+	case args[0] == "models" && len(args) == 2 && args[1] == "list":
+		err = listModels(ctx, client)
+	case args[0] == "config" && len(args) == 2 && args[1] == "validate":
+		err = validate(client)
+	case args[0] == "probe":
+		err = probe(ctx, client)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "mcpctl:", err)
+	os.Exit(1)
+}
+
+// chat streams answer of prompt, or runs an interactive session (mcp.RunREPL) when prompt is empty
+func chat(ctx context.Context, client *mcp.Client, system, prompt, transcript string) error {
+	if prompt == "" {
+		return mcp.RunREPL(client, mcp.REPLOptions{Context: ctx, SystemPrompt: system, TranscriptPath: transcript})
+	}
+
+	request, err := mcp.NewRequestBuilder().WithSystemPrompt(system).WithUserPrompt(prompt).Build()
+	if err != nil {
+		return err
+	}
+	response, err := client.CallStreamTo(ctx, request, os.Stdout)
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "[%s %s, %d+%d tokens, %s]\n", response.Provider, response.Model,
+		response.Usage.PromptTokens, response.Usage.CompletionTokens, response.Latency.Round(time.Millisecond))
+	return nil
+}
+
+func listModels(ctx context.Context, client *mcp.Client) error {
+	models, err := client.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	for _, model := range models {
+		marker := " "
+		if model == client.Model {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, model)
+	}
+	return nil
+}
+
+func validate(client *mcp.Client) error {
+	fmt.Printf("provider: %s\nmodel:    %s\nbase URL: %s\n", client.Provider, client.Model, client.BaseURL)
+	if err := client.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	fmt.Println("configuration OK")
+	return nil
+}
+
+func probe(ctx context.Context, client *mcp.Client) error {
+	status := client.HealthCheck(ctx)
+	fmt.Printf("provider:        %s\nmodel:           %s\nmethod:          %s\nstatus code:     %d\nlatency:         %s\n",
+		status.Provider, status.Model, status.Method, status.StatusCode, status.Latency.Round(time.Millisecond))
+	fmt.Printf("reachable:       %t\nauthenticated:   %t\nmodel available: %t\n",
+		status.Reachable, status.Authenticated, status.ModelAvailable)
+	if !status.Healthy() {
+		if status.Error != nil {
+			return fmt.Errorf("unhealthy: %w", status.Error)
+		}
+		return errors.New("unhealthy")
+	}
+	fmt.Println("healthy")
+	return nil
+}
+
+// costTotals aggregated audit records of one provider/model
+type costTotals struct {
+	provider, model  string
+	calls, errors    int
+	promptTokens     int
+	completionTokens int
+	cost             float64
+	latency          time.Duration
+}
+
+// costReport aggregates audit log written by mcp.NewFileAuditSink
+func costReport(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	totals := make(map[string]*costTotals)
+	decoder := json.NewDecoder(file)
+	for {
+		var record mcp.AuditRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to parse audit log: %w", err)
+		}
+		key := record.Provider + "/" + record.Model
+		t, ok := totals[key]
+		if !ok {
+			t = &costTotals{provider: record.Provider, model: record.Model}
+			totals[key] = t
+		}
+		t.calls++
+		if record.Outcome == mcp.AuditOutcomeError {
+			t.errors++
+		}
+		t.promptTokens += record.Usage.PromptTokens
+		t.completionTokens += record.Usage.CompletionTokens
+		t.cost += record.Cost
+		t.latency += record.Latency
+	}
+
+	rows := make([]*costTotals, 0, len(totals))
+	for _, t := range totals {
+		rows = append(rows, t)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].cost > rows[j].cost })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "PROVIDER\tMODEL\tCALLS\tERRORS\tPROMPT\tCOMPLETION\tAVG LATENCY\tCOST (USD)\t")
+	var total costTotals
+	for _, t := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%s\t%.4f\t\n", t.provider, t.model, t.calls, t.errors,
+			t.promptTokens, t.completionTokens, (t.latency / time.Duration(t.calls)).Round(time.Millisecond), t.cost)
+		total.calls += t.calls
+		total.errors += t.errors
+		total.promptTokens += t.promptTokens
+		total.completionTokens += t.completionTokens
+		total.cost += t.cost
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t%d\t%d\t%d\t\t%.4f\t\n", total.calls, total.errors,
+		total.promptTokens, total.completionTokens, total.cost)
+	return tw.Flush()
+}
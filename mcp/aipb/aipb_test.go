@@ -0,0 +1,152 @@
+package aipb
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"nofx/mcp"
+	"nofx/mcp/mcptest"
+)
+
+// dialServer serves backend over an in-memory listener and returns a Client calling it
+func dialServer(t *testing.T, backend mcp.AIClient) *Client {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	RegisterAIServiceServer(server, NewServer(backend))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+func newBackend(baseURL string) *mcp.Client {
+	return mcp.NewClient(
+		mcp.WithProvider(mcp.ProviderOpenAI),
+		mcp.WithBaseURL(baseURL),
+		mcp.WithModel("test-model"),
+		mcp.WithAPIKey("test-key"),
+		mcp.WithMaxRetries(1),
+		mcp.WithLogger(mcp.NewNoopLogger()),
+	).(*mcp.Client)
+}
+
+func TestChat_RoundTrip(t *testing.T) {
+	provider := mcptest.NewServer()
+	defer provider.Close()
+	provider.Enqueue(
+		mcptest.ServerReply{FinishReason: "tool_calls", ToolCalls: []mcptest.ToolCall{{ID: "call_1", Name: "get_price", Arguments: `{"symbol":"BTC"}`}}},
+		mcptest.ServerReply{Content: "BTC is at 65000"},
+	)
+	client := dialServer(t, newBackend(provider.OpenAIBaseURL()))
+
+	tools := []mcp.Tool{{Type: "function", Function: mcp.FunctionDef{Name: "get_price", Parameters: map[string]any{"type": "object"}}}}
+	req := &mcp.Request{Messages: []mcp.Message{mcp.NewUserMessage("BTC price?")}, Tools: tools}
+	response, err := client.Call(mcp.ContextWithRequestID(context.Background(), "req-1"), req)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call_1" || response.ToolCalls[0].Function.Arguments != `{"symbol":"BTC"}` {
+		t.Fatalf("unexpected tool calls %+v", response.ToolCalls)
+	}
+	if response.Usage.TotalTokens == 0 || response.Model != "test-model" {
+		t.Errorf("usage and model should round-trip, got %+v", response)
+	}
+	if got := provider.LastRequest().Headers.Get("X-Request-ID"); got != "req-1" {
+		t.Errorf("request ID should reach the provider, got %q", got)
+	}
+
+	req.Messages = append(req.Messages,
+		mcp.NewToolCallsMessage("", response.ToolCalls),
+		mcp.NewToolMessage("call_1", `{"price":65000}`))
+	answer, err := client.CallWithRequest(req)
+	if err != nil || answer != "BTC is at 65000" {
+		t.Fatalf("expected final answer, got %q (%v)", answer, err)
+	}
+	messages := provider.LastRequest().Body["messages"].([]any)
+	toolResult := messages[2].(map[string]any)
+	if toolResult["role"] != "tool" || toolResult["tool_call_id"] != "call_1" {
+		t.Errorf("tool result should reach the provider, got %v", toolResult)
+	}
+	if calls, _ := messages[1].(map[string]any)["tool_calls"].([]any); len(calls) != 1 {
+		t.Errorf("assistant tool calls should reach the provider, got %v", messages[1])
+	}
+}
+
+func TestChatStream_Events(t *testing.T) {
+	provider := mcptest.NewServer()
+	defer provider.Close()
+	provider.Enqueue(mcptest.ServerReply{Content: "hello streaming world"})
+	client := dialServer(t, newBackend(provider.OpenAIBaseURL()))
+
+	var text string
+	var done bool
+	response, err := client.CallStream(context.Background(), &mcp.Request{Messages: []mcp.Message{mcp.NewUserMessage("hi")}},
+		func(event mcp.StreamEvent) error {
+			switch event.Type {
+			case mcp.StreamTextDelta:
+				text += event.Text
+			case mcp.StreamDone:
+				done = event.Response != nil
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("CallStream failed: %v", err)
+	}
+	if text != "hello streaming world" || response.Text != text || !done {
+		t.Errorf("unexpected stream: text %q, response %q, done %v", text, response.Text, done)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"model": "text-embedding-3-small",
+			"data":  []map[string]any{{"index": 1, "embedding": []float64{0.3, 0.4}}, {"index": 0, "embedding": []float64{0.1, 0.2}}},
+			"usage": map[string]any{"prompt_tokens": 4, "total_tokens": 4},
+		})
+	}))
+	defer provider.Close()
+	client := dialServer(t, newBackend(provider.URL+"/v1"))
+
+	result, err := client.Embed(context.Background(), []string{"BTC up", "ETH down"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(result.Vectors) != 2 || result.Vectors[0][0] != 0.1 || result.Vectors[1][1] != 0.4 || result.Usage.TotalTokens != 4 {
+		t.Errorf("unexpected embeddings %+v", result)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	backend := mcptest.NewMockClient().QueueError(mcp.ErrBudgetExceeded)
+	client := dialServer(t, backend)
+
+	if _, err := client.CallWithMessages("sys", "user"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("budget errors should map to ResourceExhausted, got %v", err)
+	}
+	if _, err := client.Embed(context.Background(), []string{"BTC"}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("backends without Embed should be Unimplemented, got %v", err)
+	}
+	if _, err := client.CallWithRequest(&mcp.Request{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty messages should be InvalidArgument, got %v", err)
+	}
+}
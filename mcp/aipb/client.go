@@ -0,0 +1,147 @@
+package aipb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"nofx/mcp"
+)
+
+// Client mcp.AIClient calling a remote AIService
+//
+// Besides the AIClient methods it offers Call, CallStream and Embed with the same
+// signatures as *mcp.Client, so it can back a Gateway, RouterClient or another
+// Server. Errors are gRPC status errors (see status.Code).
+type Client struct {
+	rpc     AIServiceClient
+	timeout time.Duration
+}
+
+// NewClient creates client calling AIService over conn
+//
+// Usage example:
+//   conn, err := grpc.NewClient("ai.internal:9090", grpc.WithTransportCredentials(creds))
+//   client := aipb.NewClient(conn)
+//   answer, err := client.CallWithMessages(systemPrompt, userPrompt)
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{rpc: NewAIServiceClient(conn)}
+}
+
+// SetAPIKey is a no-op: the server's client holds the provider credentials
+func (c *Client) SetAPIKey(apiKey string, customURL string, customModel string) {}
+
+// SetTimeout sets deadline of every call (0 = none)
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// CallWithMessages calls with system and user prompt
+func (c *Client) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	var messages []mcp.Message
+	if systemPrompt != "" {
+		messages = append(messages, mcp.NewSystemMessage(systemPrompt))
+	}
+	messages = append(messages, mcp.NewUserMessage(userPrompt))
+	return c.CallWithRequestContext(context.Background(), &mcp.Request{Messages: messages})
+}
+
+// CallWithRequest calls with req
+func (c *Client) CallWithRequest(req *mcp.Request) (string, error) {
+	return c.CallWithRequestContext(context.Background(), req)
+}
+
+// CallWithRequestContext calls with req, honoring ctx cancellation
+func (c *Client) CallWithRequestContext(ctx context.Context, req *mcp.Request) (string, error) {
+	response, err := c.Call(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return response.Text, nil
+}
+
+// Call runs one completion on the server
+func (c *Client) Call(ctx context.Context, req *mcp.Request) (*mcp.Response, error) {
+	in, err := fromMCPRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	out, err := c.rpc.Chat(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return toMCPResponse(out), nil
+}
+
+// CallStream runs one completion on the server, delivering events as they arrive
+func (c *Client) CallStream(ctx context.Context, req *mcp.Request, onEvent func(mcp.StreamEvent) error) (*mcp.Response, error) {
+	in, err := fromMCPRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	stream, err := c.rpc.ChatStream(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *mcp.Response
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		eventType, ok := toMCPStreamEventType(event.GetType())
+		if !ok {
+			continue
+		}
+		mcpEvent := mcp.StreamEvent{Type: eventType, Text: event.GetText(), Index: int(event.GetIndex())}
+		if event.GetToolCall() != nil {
+			call := toMCPToolCall(event.GetToolCall())
+			mcpEvent.ToolCall = &call
+		}
+		if event.GetResponse() != nil {
+			response = toMCPResponse(event.GetResponse())
+			mcpEvent.Response = response
+		}
+		if err := onEvent(mcpEvent); err != nil {
+			return nil, err
+		}
+	}
+	if response == nil {
+		return nil, fmt.Errorf("%w: stream ended without a response", mcp.ErrEmptyStream)
+	}
+	return response, nil
+}
+
+// Embed returns embedding vectors of inputs, in input order
+func (c *Client) Embed(ctx context.Context, inputs []string) (*mcp.EmbeddingResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	out, err := c.rpc.Embed(ctx, &EmbedRequest{Inputs: inputs})
+	if err != nil {
+		return nil, err
+	}
+	result := &mcp.EmbeddingResponse{Model: out.GetModel(), Usage: toMCPUsage(out.GetUsage())}
+	for _, embedding := range out.GetEmbeddings() {
+		result.Vectors = append(result.Vectors, embedding.GetValues())
+	}
+	return result, nil
+}
+
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,226 @@
+package aipb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"nofx/mcp"
+)
+
+// toMCPRequest converts a ChatRequest to an mcp request
+func toMCPRequest(in *ChatRequest) (*mcp.Request, error) {
+	if len(in.GetMessages()) == 0 {
+		return nil, errors.New("messages must not be empty")
+	}
+	req := &mcp.Request{
+		Temperature: in.Temperature,
+		TopP:        in.TopP,
+		Stop:        in.GetStop(),
+		ToolChoice:  in.GetToolChoice(),
+	}
+	if in.MaxTokens != nil {
+		maxTokens := int(in.GetMaxTokens())
+		req.MaxTokens = &maxTokens
+	}
+	for _, message := range in.GetMessages() {
+		req.Messages = append(req.Messages, mcp.Message{
+			Role:       message.GetRole(),
+			Content:    message.GetContent(),
+			ToolCalls:  toMCPToolCalls(message.GetToolCalls()),
+			ToolCallID: message.GetToolCallId(),
+		})
+	}
+	for _, tool := range in.GetTools() {
+		definition := mcp.Tool{Type: "function", Function: mcp.FunctionDef{Name: tool.GetName(), Description: tool.GetDescription()}}
+		if tool.GetParametersJson() != "" {
+			if err := json.Unmarshal([]byte(tool.GetParametersJson()), &definition.Function.Parameters); err != nil {
+				return nil, fmt.Errorf("tool %q: invalid parameters_json: %w", tool.GetName(), err)
+			}
+		}
+		req.Tools = append(req.Tools, definition)
+	}
+	if schema := in.GetResponseSchemaJson(); schema != "" {
+		req.ResponseFormat = &mcp.ResponseFormat{}
+		if schema != "{}" {
+			if err := json.Unmarshal([]byte(schema), &req.ResponseFormat.Schema); err != nil {
+				return nil, fmt.Errorf("invalid response_schema_json: %w", err)
+			}
+		}
+	}
+	return req, nil
+}
+
+// fromMCPRequest converts an mcp request to a ChatRequest
+func fromMCPRequest(ctx context.Context, req *mcp.Request) (*ChatRequest, error) {
+	out := &ChatRequest{
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
+		ToolChoice:  req.ToolChoice,
+		RequestId:   mcp.RequestIDFromContext(ctx),
+	}
+	if req.MaxTokens != nil {
+		maxTokens := int32(*req.MaxTokens)
+		out.MaxTokens = &maxTokens
+	}
+	for _, message := range req.Messages {
+		out.Messages = append(out.Messages, &Message{
+			Role:       message.Role,
+			Content:    message.Content,
+			ToolCalls:  fromMCPToolCalls(message.ToolCalls),
+			ToolCallId: message.ToolCallID,
+		})
+	}
+	for _, tool := range req.Tools {
+		definition := &Tool{Name: tool.Function.Name, Description: tool.Function.Description}
+		if tool.Function.Parameters != nil {
+			parameters, err := json.Marshal(tool.Function.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("tool %q: %w", tool.Function.Name, err)
+			}
+			definition.ParametersJson = string(parameters)
+		}
+		out.Tools = append(out.Tools, definition)
+	}
+	if format := req.ResponseFormat; format != nil {
+		out.ResponseSchemaJson = "{}"
+		if format.Schema != nil {
+			schema, err := json.Marshal(format.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("response schema: %w", err)
+			}
+			out.ResponseSchemaJson = string(schema)
+		}
+	}
+	return out, nil
+}
+
+func toMCPToolCalls(calls []*ToolCall) []mcp.ToolCall {
+	var out []mcp.ToolCall
+	for _, call := range calls {
+		out = append(out, toMCPToolCall(call))
+	}
+	return out
+}
+
+func toMCPToolCall(call *ToolCall) mcp.ToolCall {
+	return mcp.ToolCall{
+		ID:       call.GetId(),
+		Type:     "function",
+		Function: mcp.ToolCallFunction{Name: call.GetName(), Arguments: call.GetArgumentsJson()},
+	}
+}
+
+func fromMCPToolCalls(calls []mcp.ToolCall) []*ToolCall {
+	var out []*ToolCall
+	for i := range calls {
+		out = append(out, fromMCPToolCall(&calls[i]))
+	}
+	return out
+}
+
+func fromMCPToolCall(call *mcp.ToolCall) *ToolCall {
+	if call == nil {
+		return nil
+	}
+	return &ToolCall{Id: call.ID, Name: call.Function.Name, ArgumentsJson: call.Function.Arguments}
+}
+
+func toMCPUsage(usage *Usage) mcp.TokenUsage {
+	return mcp.TokenUsage{
+		PromptTokens:     int(usage.GetPromptTokens()),
+		CompletionTokens: int(usage.GetCompletionTokens()),
+		TotalTokens:      int(usage.GetTotalTokens()),
+		CacheReadTokens:  int(usage.GetCacheReadTokens()),
+		CacheWriteTokens: int(usage.GetCacheWriteTokens()),
+	}
+}
+
+func fromMCPUsage(usage mcp.TokenUsage) *Usage {
+	return &Usage{
+		PromptTokens:     int32(usage.PromptTokens),
+		CompletionTokens: int32(usage.CompletionTokens),
+		TotalTokens:      int32(usage.TotalTokens),
+		CacheReadTokens:  int32(usage.CacheReadTokens),
+		CacheWriteTokens: int32(usage.CacheWriteTokens),
+	}
+}
+
+func toMCPResponse(in *ChatResponse) *mcp.Response {
+	usage := toMCPUsage(in.GetUsage())
+	usage.Provider, usage.Model = in.GetProvider(), in.GetModel()
+	return &mcp.Response{
+		Text:         in.GetText(),
+		Reasoning:    in.GetReasoning(),
+		ToolCalls:    toMCPToolCalls(in.GetToolCalls()),
+		FinishReason: in.GetFinishReason(),
+		Usage:        usage,
+		Model:        in.GetModel(),
+		Provider:     in.GetProvider(),
+		RequestID:    in.GetRequestId(),
+		Latency:      time.Duration(in.GetLatencyMs()) * time.Millisecond,
+	}
+}
+
+func fromMCPResponse(response *mcp.Response) *ChatResponse {
+	if response == nil {
+		return nil
+	}
+	return &ChatResponse{
+		Text:         response.Text,
+		Reasoning:    response.Reasoning,
+		ToolCalls:    fromMCPToolCalls(response.ToolCalls),
+		FinishReason: response.FinishReason,
+		Usage:        fromMCPUsage(response.Usage),
+		Model:        response.Model,
+		Provider:     response.Provider,
+		RequestId:    response.RequestID,
+		LatencyMs:    response.Latency.Milliseconds(),
+	}
+}
+
+// streamEventTypes mcp stream event types and their wire equivalents (StreamError is the RPC error)
+var streamEventTypes = map[mcp.StreamEventType]StreamEvent_Type{
+	mcp.StreamTextDelta:        StreamEvent_TEXT_DELTA,
+	mcp.StreamReasoningDelta:   StreamEvent_REASONING_DELTA,
+	mcp.StreamToolCallDelta:    StreamEvent_TOOL_CALL_DELTA,
+	mcp.StreamToolCallComplete: StreamEvent_TOOL_CALL_COMPLETE,
+	mcp.StreamDone:             StreamEvent_DONE,
+	mcp.StreamRestarted:        StreamEvent_RESTARTED,
+}
+
+func toMCPStreamEventType(t StreamEvent_Type) (mcp.StreamEventType, bool) {
+	for mcpType, wireType := range streamEventTypes {
+		if wireType == t {
+			return mcpType, true
+		}
+	}
+	return 0, false
+}
+
+// errorStatus maps an mcp error to a gRPC status error
+func errorStatus(err error) error {
+	var apiErr *mcp.APIError
+	code := codes.Unavailable
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, mcp.ErrGuardrailBlocked), errors.Is(err, mcp.ErrPromptInjection):
+		code = codes.InvalidArgument
+	case errors.Is(err, mcp.ErrQueueFull), errors.Is(err, mcp.ErrBudgetExceeded), errors.Is(err, mcp.ErrQuotaExhausted):
+		code = codes.ResourceExhausted
+	case errors.As(err, &apiErr) && apiErr.StatusCode == 429:
+		code = codes.ResourceExhausted
+	case errors.Is(err, mcp.ErrEmbeddingsUnsupported), errors.Is(err, mcp.ErrChatUnsupported):
+		code = codes.Unimplemented
+	}
+	return status.Error(code, err.Error())
+}
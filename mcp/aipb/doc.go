@@ -0,0 +1,12 @@
+// Package aipb holds the gRPC contract of the nofx AI service (ai.proto).
+//
+// The service mirrors the mcp client operations (Chat = Client.Call, ChatStream =
+// Client.CallStream, Embed = Client.Embed) so components in other languages call the
+// AI layer with strong typing. Go stubs are generated with protoc-gen-go and
+// protoc-gen-go-grpc; the mcp package itself stays dependency-free, the adapters live
+// with the generated code:
+//   - Server serves AIService with any mcp.AIClient (NewServer)
+//   - Client is an mcp.AIClient calling a remote AIService (NewClient)
+package aipb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ai.proto
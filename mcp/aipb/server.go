@@ -0,0 +1,122 @@
+package aipb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"nofx/mcp"
+)
+
+// Server serves AIService with an mcp client
+//
+// Calls go through the client exactly like in-process calls (guardrails, retries,
+// budgets, audit). ChatStream and Embed need a client with CallStream and Embed
+// (every provider *mcp.Client has them), otherwise they fail with Unimplemented.
+type Server struct {
+	UnimplementedAIServiceServer
+	client mcp.AIClient
+}
+
+// NewServer creates AIService server backed by client
+//
+// Usage example:
+//   server := grpc.NewServer()
+//   aipb.RegisterAIServiceServer(server, aipb.NewServer(mcp.NewDeepSeekClientWithOptions()))
+//   server.Serve(listener)
+func NewServer(client mcp.AIClient) *Server {
+	return &Server{client: client}
+}
+
+// Chat runs one completion
+func (s *Server) Chat(ctx context.Context, in *ChatRequest) (*ChatResponse, error) {
+	req, err := toMCPRequest(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx = withRequestID(ctx, in)
+
+	if caller, ok := s.client.(interface {
+		Call(ctx context.Context, req *mcp.Request) (*mcp.Response, error)
+	}); ok {
+		response, err := caller.Call(ctx, req)
+		if err != nil {
+			return nil, errorStatus(err)
+		}
+		return fromMCPResponse(response), nil
+	}
+
+	var text string
+	if caller, ok := s.client.(interface {
+		CallWithRequestContext(ctx context.Context, req *mcp.Request) (string, error)
+	}); ok {
+		text, err = caller.CallWithRequestContext(ctx, req)
+	} else {
+		text, err = s.client.CallWithRequest(req)
+	}
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return &ChatResponse{Text: text, FinishReason: mcp.FinishReasonStop, Usage: &Usage{}}, nil
+}
+
+// ChatStream runs one completion, sending events as they arrive
+func (s *Server) ChatStream(in *ChatRequest, stream grpc.ServerStreamingServer[StreamEvent]) error {
+	streamer, ok := s.client.(interface {
+		CallStream(ctx context.Context, req *mcp.Request, onEvent func(mcp.StreamEvent) error) (*mcp.Response, error)
+	})
+	if !ok {
+		return status.Error(codes.Unimplemented, "backend client does not support streaming")
+	}
+	req, err := toMCPRequest(in)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	_, err = streamer.CallStream(withRequestID(stream.Context(), in), req, func(event mcp.StreamEvent) error {
+		wireType, ok := streamEventTypes[event.Type]
+		if !ok {
+			return nil
+		}
+		return stream.Send(&StreamEvent{
+			Type:     wireType,
+			Text:     event.Text,
+			ToolCall: fromMCPToolCall(event.ToolCall),
+			Index:    int32(event.Index),
+			Response: fromMCPResponse(event.Response),
+		})
+	})
+	if err != nil {
+		return errorStatus(err)
+	}
+	return nil
+}
+
+// Embed returns embedding vectors of the inputs
+func (s *Server) Embed(ctx context.Context, in *EmbedRequest) (*EmbedResponse, error) {
+	embedder, ok := s.client.(interface {
+		Embed(ctx context.Context, inputs []string) (*mcp.EmbeddingResponse, error)
+	})
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "backend client does not support embeddings")
+	}
+	result, err := embedder.Embed(ctx, in.GetInputs())
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	out := &EmbedResponse{Model: result.Model, Usage: fromMCPUsage(result.Usage)}
+	for _, vector := range result.Vectors {
+		out.Embeddings = append(out.Embeddings, &Embedding{Values: vector})
+	}
+	return out, nil
+}
+
+// withRequestID adds the caller's request ID to ctx
+func withRequestID(ctx context.Context, in *ChatRequest) context.Context {
+	if id := in.GetRequestId(); id != "" {
+		return mcp.ContextWithRequestID(ctx, id)
+	}
+	return ctx
+}
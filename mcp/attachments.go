@@ -0,0 +1,270 @@
+package mcp
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// File attachment limits
+const (
+	MaxFileBytes       = 32 << 20  // Largest file UploadFile accepts
+	MaxInlineFileBytes = 100 << 10 // Extracted text inlined per file for providers without file inputs
+)
+
+// MIME types with dedicated handling
+const (
+	MIMEPDF = "application/pdf"
+	MIMECSV = "text/csv"
+)
+
+// ErrUnsupportedFile is returned when no text can be extracted from a file
+var ErrUnsupportedFile = errors.New("unsupported file")
+
+// fileInputProviders providers accepting PDF file content parts in chat messages
+var fileInputProviders = map[string]bool{
+	ProviderOpenAI: true, // Uploaded with the Files API, referenced by ID
+	ProviderGemini: true, // Sent inline as base64 file data
+}
+
+// FileRef file attachable to messages
+type FileRef struct {
+	ID   string // Provider file ID ("" when the file is sent inline)
+	Name string
+	MIME string
+	Size int
+
+	data []byte
+}
+
+// UploadFile prepares a file (PDF, CSV or other text) for attaching to messages
+//
+// PDFs are uploaded with the OpenAI Files API, or sent inline to Gemini. Providers
+// without file inputs, and all non-PDF files, get the file's text extracted locally
+// and inlined into the message (PDF extraction is best effort: scanned or font-encoded
+// PDFs fail with ErrUnsupportedFile).
+//
+// Usage example:
+//   f, _ := os.Open("reports/q3_pnl.pdf")
+//   file, err := client.UploadFile(ctx, f, mcp.MIMEPDF)
+//   req := &mcp.Request{Messages: []mcp.Message{mcp.NewUserMessageWithFiles("Summarize this report", file)}}
+func (client *Client) UploadFile(ctx context.Context, r io.Reader, mimeType string) (*FileRef, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > MaxFileBytes {
+		return nil, fmt.Errorf("file exceeds %d bytes", MaxFileBytes)
+	}
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MIME type %q: %w", mimeType, err)
+	}
+
+	file := &FileRef{Name: "upload" + fileExtension(mediaType), MIME: mediaType, Size: len(data), data: data}
+	if mediaType == MIMEPDF && client.Provider == ProviderOpenAI {
+		if file.ID, err = client.uploadUserFile(ctx, file); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// NewUserMessageWithFiles creates a user message with attached files
+func NewUserMessageWithFiles(content string, files ...*FileRef) Message {
+	return Message{
+		Role:    "user",
+		Content: content,
+		Files:   files,
+	}
+}
+
+// uploadUserFile uploads file with purpose=user_data and returns its ID
+func (client *Client) uploadUserFile(ctx context.Context, file *FileRef) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("purpose", "user_data")
+	part, err := writer.CreateFormFile("file", file.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to build file upload: %w", err)
+	}
+	part.Write(file.data)
+	writer.Close()
+
+	var uploaded struct {
+		ID string `json:"id"`
+	}
+	if err := client.batchAPI(ctx, http.MethodPost, client.endpointURL(EndpointFiles), writer.FormDataContentType(), &body, &uploaded); err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	return uploaded.ID, nil
+}
+
+// messageContent returns API content of msg: its text, or content parts when files are attached
+func (client *Client) messageContent(msg Message) any {
+	if len(msg.Files) == 0 || !fileInputProviders[client.Provider] {
+		return msg.Content
+	}
+	parts := []map[string]any{{"type": "text", "text": msg.Content}}
+	for _, file := range msg.Files {
+		switch {
+		case file.MIME != MIMEPDF:
+			parts[0]["text"] = parts[0]["text"].(string) + inlineFileText(file)
+		case file.ID != "":
+			parts = append(parts, map[string]any{"type": "file", "file": map[string]any{"file_id": file.ID}})
+		default:
+			parts = append(parts, map[string]any{"type": "file", "file": map[string]any{
+				"filename":  file.Name,
+				"file_data": "data:" + file.MIME + ";base64," + base64.StdEncoding.EncodeToString(file.data),
+			}})
+		}
+	}
+	return parts
+}
+
+// inlineFiles returns messages with the text of attached files appended to their content
+// (providers without file inputs; others keep the attachments)
+func (client *Client) inlineFiles(messages []Message) []Message {
+	if fileInputProviders[client.Provider] {
+		return messages
+	}
+	var inlined []Message
+	for i, msg := range messages {
+		if len(msg.Files) == 0 {
+			continue
+		}
+		if inlined == nil {
+			inlined = append([]Message(nil), messages...)
+		}
+		for _, file := range msg.Files {
+			inlined[i].Content += inlineFileText(file)
+		}
+		inlined[i].Files = nil
+	}
+	if inlined == nil {
+		return messages
+	}
+	return inlined
+}
+
+// inlineFileText returns file's extracted text framed for inlining into a message
+func inlineFileText(file *FileRef) string {
+	text, err := ExtractFileText(file.data, file.MIME)
+	if err != nil {
+		text = fmt.Sprintf("[content unavailable: %v]", err)
+	}
+	if len(text) > MaxInlineFileBytes {
+		text = truncateBytes(text, MaxInlineFileBytes)
+	}
+	return fmt.Sprintf("\n\n--- Attached file: %s (%s) ---\n%s\n--- End of %s ---", file.Name, file.MIME, text, file.Name)
+}
+
+// ExtractFileText returns the text of a PDF or text file (CSV, JSON, plain text, ...)
+func ExtractFileText(data []byte, mimeType string) (string, error) {
+	switch {
+	case mimeType == MIMEPDF:
+		return extractPDFText(data)
+	case strings.HasPrefix(mimeType, "text/"), mimeType == "application/json":
+		return strings.ToValidUTF8(string(data), "�"), nil
+	}
+	return "", fmt.Errorf("%w: cannot extract text from %s", ErrUnsupportedFile, mimeType)
+}
+
+var (
+	pdfStreamPattern = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\n?endstream`)
+	// Text showing operators (Tj, ', ", TJ) and operators starting a new line
+	pdfTextPattern   = regexp.MustCompile(`\((?:\\.|[^\\)])*\)\s*(?:Tj|'|")|\[(?:\\.|[^\\\]])*\]\s*TJ|\b(?:T\*|Td|TD|ET)\b`)
+	pdfStringPattern = regexp.MustCompile(`\((?:\\.|[^\\)])*\)`)
+)
+
+// extractPDFText extracts literal strings shown by the content streams of a PDF
+func extractPDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return "", fmt.Errorf("%w: not a PDF", ErrUnsupportedFile)
+	}
+	var sb strings.Builder
+	for _, match := range pdfStreamPattern.FindAllSubmatch(data, -1) {
+		content := match[1]
+		if reader, err := zlib.NewReader(bytes.NewReader(content)); err == nil {
+			if inflated, err := io.ReadAll(reader); err == nil || len(inflated) > 0 {
+				content = inflated
+			}
+		}
+		for _, op := range pdfTextPattern.FindAll(content, -1) {
+			if op[0] != '(' && op[0] != '[' {
+				if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+					sb.WriteByte('\n')
+				}
+				continue
+			}
+			for _, literal := range pdfStringPattern.FindAll(op, -1) {
+				sb.WriteString(unescapePDFString(literal[1 : len(literal)-1]))
+			}
+		}
+	}
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return "", fmt.Errorf("%w: no extractable text in PDF (scanned or encoded fonts)", ErrUnsupportedFile)
+	}
+	return text, nil
+}
+
+// unescapePDFString decodes escape sequences of a PDF literal string
+func unescapePDFString(s []byte) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'b', 'f':
+		case '\n':
+			// Line continuation
+		default:
+			if c >= '0' && c <= '7' {
+				end := i + 1
+				for end < len(s) && end < i+3 && s[end] >= '0' && s[end] <= '7' {
+					end++
+				}
+				code, _ := strconv.ParseUint(string(s[i:end]), 8, 8)
+				sb.WriteByte(byte(code))
+				i = end - 1
+				continue
+			}
+			sb.WriteByte(c) // \( \) \\
+		}
+	}
+	return sb.String()
+}
+
+// fileExtension returns the usual extension of mediaType ("" if unknown)
+func fileExtension(mediaType string) string {
+	switch mediaType {
+	case MIMEPDF:
+		return ".pdf"
+	case MIMECSV:
+		return ".csv"
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// testPDF builds a minimal PDF with one compressed content stream
+func testPDF(content string) []byte {
+	var stream bytes.Buffer
+	w := zlib.NewWriter(&stream)
+	w.Write([]byte(content))
+	w.Close()
+	return []byte(fmt.Sprintf("%%PDF-1.4\n4 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n%%%%EOF", stream.Len(), stream.Bytes()))
+}
+
+func TestExtractFileText_PDF(t *testing.T) {
+	pdf := testPDF("BT /F1 12 Tf 72 712 Td (Q3 PnL: \\(net\\) +12%) Tj 0 -14 Td [(Max dr) -20 (awdown 4%)] TJ ET")
+	text, err := ExtractFileText(pdf, MIMEPDF)
+	if err != nil {
+		t.Fatalf("extract failed: %v", err)
+	}
+	if text != "Q3 PnL: (net) +12%\nMax drawdown 4%" {
+		t.Errorf("unexpected text %q", text)
+	}
+	if _, err := ExtractFileText(testPDF("q 1 0 0 1 0 0 cm Q"), MIMEPDF); !errors.Is(err, ErrUnsupportedFile) {
+		t.Errorf("expected ErrUnsupportedFile for PDF without text, got %v", err)
+	}
+}
+
+func TestUploadFile_OpenAINative(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	var chatBody map[string]any
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		answer := `{"choices":[{"message":{"content":"ok"}}]}`
+		if strings.HasSuffix(req.URL.Path, "/files") {
+			if !bytes.Contains(data, []byte("user_data")) || !bytes.Contains(data, []byte("%PDF")) {
+				t.Errorf("unexpected upload body %q", data)
+			}
+			answer = `{"id":"file-abc123"}`
+		} else {
+			json.Unmarshal(data, &chatBody)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(answer)), Header: make(http.Header)}, nil
+	}
+	client := NewOpenAIClientWithOptions(WithHTTPClient(mockHTTP.ToHTTPClient()), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()))
+	base, _ := BaseClient(client)
+
+	ctx := context.Background()
+	pdf, err := base.UploadFile(ctx, bytes.NewReader(testPDF("BT (hello) Tj ET")), MIMEPDF)
+	if err != nil || pdf.ID != "file-abc123" || pdf.Name != "upload.pdf" {
+		t.Fatalf("upload = %+v, %v", pdf, err)
+	}
+	csv, _ := base.UploadFile(ctx, strings.NewReader("symbol,pnl\nBTC,120\n"), "text/csv; charset=utf-8")
+
+	req := &Request{Messages: []Message{NewUserMessageWithFiles("Summarize", pdf, csv)}}
+	if _, err := base.CallWithRequestContext(ctx, req); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	parts := chatBody["messages"].([]any)[0].(map[string]any)["content"].([]any)
+	text := parts[0].(map[string]any)["text"].(string)
+	if !strings.Contains(text, "Summarize") || !strings.Contains(text, "BTC,120") {
+		t.Errorf("expected CSV inlined into text part, got %q", text)
+	}
+	if file := parts[1].(map[string]any)["file"].(map[string]any); file["file_id"] != "file-abc123" {
+		t.Errorf("unexpected file part %v", parts[1])
+	}
+}
+
+func TestUploadFile_InlineFallback(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	ctx := context.Background()
+	pdf, _ := client.UploadFile(ctx, bytes.NewReader(testPDF("BT (Sharpe 1.8) Tj ET")), MIMEPDF)
+	if pdf.ID != "" {
+		t.Errorf("DeepSeek has no file API, got ID %q", pdf.ID)
+	}
+	req := &Request{Messages: []Message{NewUserMessageWithFiles("Summarize", pdf)}}
+	if _, err := client.CallWithRequestContext(ctx, req); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	content := bodies[0]["messages"].([]any)[0].(map[string]any)["content"].(string)
+	if !strings.HasPrefix(content, "Summarize") || !strings.Contains(content, "--- Attached file: upload.pdf (application/pdf) ---\nSharpe 1.8") {
+		t.Errorf("unexpected inlined content %q", content)
+	}
+}
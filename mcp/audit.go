@@ -0,0 +1,407 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditContentMode controls how prompt and response text are stored in audit records
+type AuditContentMode int
+
+const (
+	// AuditContentHash stores only SHA-256 hashes of prompt and response (default)
+	AuditContentHash AuditContentMode = iota
+	// AuditContentFull stores full prompt and response text alongside hashes
+	AuditContentFull
+	// AuditContentNone stores neither text nor hashes
+	AuditContentNone
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeError   = "error"
+)
+
+// AuditRecord one AI interaction
+type AuditRecord struct {
+	Timestamp    time.Time         `json:"timestamp"`
+	RequestID    string            `json:"request_id,omitempty"`
+	Tenant       string            `json:"tenant,omitempty"`
+	Provider     string            `json:"provider"`
+	Model        string            `json:"model"`
+	URL          string            `json:"url"`
+	PromptHash   string            `json:"prompt_hash,omitempty"`
+	Prompt       []Message         `json:"prompt,omitempty"`
+	ResponseHash string            `json:"response_hash,omitempty"`
+	Response     string            `json:"response,omitempty"`
+	Usage        TokenUsage        `json:"usage"`
+	Cost         float64           `json:"cost"`
+	Latency      time.Duration     `json:"latency"`
+	StatusCode   int               `json:"status_code,omitempty"`
+	Outcome      string            `json:"outcome"`
+	Error        string            `json:"error,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+
+	// Model reasoning, recorded per WithReasoningRetention
+	Reasoning          string     `json:"reasoning,omitempty"`
+	ReasoningHash      string     `json:"reasoning_hash,omitempty"`
+	ReasoningExpiresAt *time.Time `json:"reasoning_expires_at,omitempty"`
+
+	// Prompt template of the call (see PromptTemplate.Context)
+	PromptName    string `json:"prompt_name,omitempty"`
+	PromptVersion string `json:"prompt_version,omitempty"`
+}
+
+// AuditSink receives one record per AI interaction
+type AuditSink interface {
+	Write(record AuditRecord) error
+	Close() error
+}
+
+// WithAuditSink sends an audit record of every AI interaction to sink
+//
+// Usage example:
+//   sink, _ := mcp.NewFileAuditSink("logs/ai_audit.jsonl")
+//   client := mcp.NewClient(mcp.WithAuditSink(sink))
+func WithAuditSink(sink AuditSink) ClientOption {
+	return func(c *Config) {
+		c.AuditSink = sink
+	}
+}
+
+// WithAuditContent sets how prompt/response text is stored in audit records
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithAuditSink(sink), mcp.WithAuditContent(mcp.AuditContentFull))
+func WithAuditContent(mode AuditContentMode) ClientOption {
+	return func(c *Config) {
+		c.AuditContent = mode
+	}
+}
+
+// WithAuditTags sets caller-supplied tags attached to every audit record
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithAuditSink(sink), mcp.WithAuditTags(map[string]string{"strategy": "scalper"}))
+func WithAuditTags(tags map[string]string) ClientOption {
+	return func(c *Config) {
+		c.AuditTags = tags
+	}
+}
+
+// audit builds audit record of one interaction and writes it to the configured sink
+//
+// usage is parsed from the response body, or aggregated from the stream for streamed calls.
+func (client *Client) audit(ctx context.Context, url string, requestBody map[string]any, usage TokenUsage, statusCode int, result, reasoning string, latency time.Duration, callErr error) {
+	sink := client.config.AuditSink
+	if sink == nil {
+		return
+	}
+
+	usage.Provider = client.Provider
+	usage.Model = client.Model
+
+	record := AuditRecord{
+		Timestamp:  time.Now(),
+		RequestID:  RequestIDFromContext(ctx),
+		Tenant:     client.tenantFor(ctx),
+		Provider:   client.Provider,
+		Model:      bodyModel(requestBody, client.Model),
+		URL:        url,
+		Usage:      usage,
+		Cost:       EstimateCost(usage),
+		Latency:    latency,
+		StatusCode: statusCode,
+		Outcome:    AuditOutcomeSuccess,
+		Tags:       client.tagsFor(ctx),
+	}
+	record.PromptName, record.PromptVersion = promptVersionFromContext(ctx)
+	if callErr != nil {
+		record.Outcome = AuditOutcomeError
+		record.Error = callErr.Error()
+	}
+
+	if client.config.AuditContent != AuditContentNone {
+		prompt := promptMessages(requestBody)
+		promptJSON, _ := json.Marshal(prompt)
+		record.PromptHash = hashText(string(promptJSON))
+		if result != "" {
+			record.ResponseHash = hashText(result)
+		}
+		if client.config.AuditContent == AuditContentFull {
+			record.Prompt = prompt
+			record.Response = result
+		}
+	}
+
+	client.applyReasoningRetention(&record, reasoning)
+	if err := encryptAuditRecord(&record, client.config.ContentCipher); err != nil {
+		// Never fall back to storing plaintext
+		client.logger.Warnf("⚠️  [%s] Failed to encrypt audit record, not written: %v", client.String(), err)
+		return
+	}
+
+	if err := sink.Write(record); err != nil {
+		client.logger.Warnf("⚠️  [%s] Failed to write audit record: %v", client.String(), err)
+	}
+}
+
+// promptMessages extracts prompt messages from a provider request body
+func promptMessages(requestBody map[string]any) []Message {
+	var messages []Message
+	if system, ok := requestBody["system"].(string); ok && system != "" {
+		messages = append(messages, NewSystemMessage(system))
+	}
+
+	raw, err := json.Marshal(requestBody["messages"])
+	if err != nil {
+		return messages
+	}
+	var bodyMessages []Message
+	if err := json.Unmarshal(raw, &bodyMessages); err == nil {
+		messages = append(messages, bodyMessages...)
+	}
+	return messages
+}
+
+// hashText returns hex SHA-256 of text
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// encryptAuditRecord encrypts prompt, response, reasoning and error text of record (see WithContentEncryption)
+func encryptAuditRecord(record *AuditRecord, c ContentCipher) error {
+	if c == nil {
+		return nil
+	}
+	return transformAuditRecord(record, func(text string) (string, error) {
+		return encryptString(c, text)
+	})
+}
+
+// DecryptAuditRecord decrypts content of a record written with WithContentEncryption
+//
+// Records written without encryption are left unchanged.
+//
+// Usage example:
+//   var record mcp.AuditRecord
+//   json.Unmarshal(line, &record)
+//   if err := mcp.DecryptAuditRecord(&record, cipher); err != nil {
+//       return err
+//   }
+func DecryptAuditRecord(record *AuditRecord, cipher ContentCipher) error {
+	return transformAuditRecord(record, func(text string) (string, error) {
+		return decryptString(cipher, text)
+	})
+}
+
+// transformAuditRecord applies transform to message contents and tool call arguments,
+// response, reasoning and error of record
+func transformAuditRecord(record *AuditRecord, transform func(string) (string, error)) error {
+	if len(record.Prompt) > 0 {
+		prompt, err := transformMessages(record.Prompt, transform)
+		if err != nil {
+			return err
+		}
+		record.Prompt = prompt
+	}
+	// Errors can carry provider response bodies, which may quote the prompt
+	for _, field := range []*string{&record.Response, &record.Reasoning, &record.Error} {
+		value, err := transform(*field)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// ============================================================
+// File Sink (JSON Lines)
+// ============================================================
+
+// FileAuditSink appends audit records as JSON lines to a file
+type FileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink opens (or creates) JSONL audit file at path
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &FileAuditSink{file: file}, nil
+}
+
+func (s *FileAuditSink) Write(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to serialize audit record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Flush commits written records to stable storage
+func (s *FileAuditSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Sync()
+}
+
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// ============================================================
+// SQL Sink (SQLite)
+// ============================================================
+
+// SQLAuditSink stores audit records in a SQL table
+//
+// The database handle is injected so mcp stays free of driver dependencies;
+// open it with the sqlite driver already used by the store package.
+type SQLAuditSink struct {
+	db *sql.DB
+}
+
+// NewSQLAuditSink creates sink and the ai_audit_log table if missing
+func NewSQLAuditSink(db *sql.DB) (*SQLAuditSink, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ai_audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp DATETIME NOT NULL,
+		provider TEXT NOT NULL,
+		model TEXT NOT NULL,
+		url TEXT,
+		prompt_hash TEXT,
+		prompt TEXT,
+		response_hash TEXT,
+		response TEXT,
+		prompt_tokens INTEGER,
+		completion_tokens INTEGER,
+		total_tokens INTEGER,
+		cost REAL,
+		latency_ms INTEGER,
+		status_code INTEGER,
+		outcome TEXT NOT NULL,
+		error TEXT,
+		tags TEXT,
+		request_id TEXT,
+		reasoning TEXT,
+		reasoning_hash TEXT,
+		reasoning_expires_at DATETIME
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit table: %w", err)
+	}
+	// Tables created before request IDs were recorded lack the column (error = already exists)
+	db.Exec(`ALTER TABLE ai_audit_log ADD COLUMN request_id TEXT`)
+	db.Exec(`ALTER TABLE ai_audit_log ADD COLUMN reasoning TEXT`)
+	db.Exec(`ALTER TABLE ai_audit_log ADD COLUMN reasoning_hash TEXT`)
+	db.Exec(`ALTER TABLE ai_audit_log ADD COLUMN reasoning_expires_at DATETIME`)
+	db.Exec(`ALTER TABLE ai_audit_log ADD COLUMN prompt_name TEXT`)
+	db.Exec(`ALTER TABLE ai_audit_log ADD COLUMN prompt_version TEXT`)
+	db.Exec(`ALTER TABLE ai_audit_log ADD COLUMN tenant TEXT`)
+	return &SQLAuditSink{db: db}, nil
+}
+
+func (s *SQLAuditSink) Write(record AuditRecord) error {
+	var prompt []byte
+	if len(record.Prompt) > 0 {
+		prompt, _ = json.Marshal(record.Prompt)
+	}
+	tags, _ := json.Marshal(record.Tags)
+
+	_, err := s.db.Exec(`INSERT INTO ai_audit_log (
+		timestamp, provider, model, url, prompt_hash, prompt, response_hash, response,
+		prompt_tokens, completion_tokens, total_tokens, cost, latency_ms, status_code, outcome, error, tags, request_id,
+		reasoning, reasoning_hash, reasoning_expires_at, prompt_name, prompt_version, tenant
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.Timestamp, record.Provider, record.Model, record.URL,
+		record.PromptHash, string(prompt), record.ResponseHash, record.Response,
+		record.Usage.PromptTokens, record.Usage.CompletionTokens, record.Usage.TotalTokens,
+		record.Cost, record.Latency.Milliseconds(), record.StatusCode,
+		record.Outcome, record.Error, string(tags), record.RequestID,
+		record.Reasoning, record.ReasoningHash, record.ReasoningExpiresAt, record.PromptName, record.PromptVersion, record.Tenant,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit record: %w", err)
+	}
+	return nil
+}
+
+// Close does nothing: the database handle is owned by the caller
+func (s *SQLAuditSink) Close() error {
+	return nil
+}
+
+// ============================================================
+// Webhook Sink
+// ============================================================
+
+// WebhookAuditSink POSTs every audit record as JSON to a URL
+type WebhookAuditSink struct {
+	URL        string
+	Headers    http.Header
+	HTTPClient *http.Client
+}
+
+// NewWebhookAuditSink creates webhook sink posting to url
+func NewWebhookAuditSink(url string) *WebhookAuditSink {
+	return &WebhookAuditSink{
+		URL:        url,
+		Headers:    make(http.Header),
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *WebhookAuditSink) Write(record AuditRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to serialize audit record: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("fail to build audit webhook request: %w", err)
+	}
+	for name, values := range s.Headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send audit webhook: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *WebhookAuditSink) Close() error {
+	s.HTTPClient.CloseIdleConnections()
+	return nil
+}
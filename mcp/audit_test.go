@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// ============================================================
+// Test Audit Log
+// ============================================================
+
+// memoryAuditSink collects audit records in memory
+type memoryAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (s *memoryAuditSink) Write(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *memoryAuditSink) Close() error { return nil }
+
+func (s *memoryAuditSink) Records() []AuditRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]AuditRecord{}, s.records...)
+}
+
+func TestAudit_HashModeByDefault(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.Response = `{"choices":[{"message":{"content":"buy"}}],"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12}}`
+
+	RegisterModelPrice("audit-test-model", ModelPrice{InputPerMillion: 1, OutputPerMillion: 2})
+
+	sink := &memoryAuditSink{}
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithProvider(ProviderCustom),
+		WithModel("audit-test-model"),
+		WithAuditSink(sink),
+		WithAuditTags(map[string]string{"strategy": "scalper"}),
+	)
+
+	if _, err := client.CallWithMessages("system", "should I buy?"); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	records := sink.Records()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	r := records[0]
+	if r.Outcome != AuditOutcomeSuccess {
+		t.Errorf("expected success outcome, got %s", r.Outcome)
+	}
+	if r.PromptHash == "" || r.ResponseHash != hashText("buy") {
+		t.Error("hashes should be recorded")
+	}
+	if r.Prompt != nil || r.Response != "" {
+		t.Error("full text should not be recorded in hash mode")
+	}
+	if r.Usage.TotalTokens != 12 {
+		t.Errorf("expected 12 tokens, got %d", r.Usage.TotalTokens)
+	}
+	expectedCost := 10.0/1e6*1 + 2.0/1e6*2
+	if math.Abs(r.Cost-expectedCost) > 1e-12 {
+		t.Errorf("expected cost %v, got %v", expectedCost, r.Cost)
+	}
+	if r.Tags["strategy"] != "scalper" {
+		t.Error("tags should be recorded")
+	}
+}
+
+func TestAudit_FullModeAndErrors(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetErrorResponse(400, "bad request")
+
+	sink := &memoryAuditSink{}
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithAuditSink(sink),
+		WithAuditContent(AuditContentFull),
+	)
+
+	client.CallWithMessages("system", "user prompt")
+
+	records := sink.Records()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	r := records[0]
+	if r.Outcome != AuditOutcomeError || r.StatusCode != 400 {
+		t.Errorf("expected error outcome with status 400, got %s/%d", r.Outcome, r.StatusCode)
+	}
+	if len(r.Prompt) != 2 || r.Prompt[1].Content != "user prompt" {
+		t.Errorf("full prompt should be recorded, got %+v", r.Prompt)
+	}
+}
+
+func TestAudit_StreamedUsage(t *testing.T) {
+	RegisterModelPrice("audit-stream-model", ModelPrice{InputPerMillion: 1000, OutputPerMillion: 1000})
+	stream := sseLines(
+		`{"choices":[{"delta":{"content":"hold"}}]}`,
+		`{"choices":[{"delta":{},"finish_reason":"stop"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+		`[DONE]`,
+	)
+	sink := &memoryAuditSink{}
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithProvider(ProviderOpenAI),
+		WithAPIKey("sk-test-key"),
+		WithModel("audit-stream-model"),
+		WithLogger(NewNoopLogger()),
+		WithAuditSink(sink),
+	).(*Client)
+
+	if _, err := client.CallStream(context.Background(), &Request{Messages: []Message{NewUserMessage("BTC?")}}, func(StreamEvent) error { return nil }); err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	records := sink.Records()
+	if len(records) != 1 || records[0].Usage.TotalTokens != 15 || records[0].Cost <= 0 {
+		t.Fatalf("expected streamed usage and cost in the audit record, got %+v", records)
+	}
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path)
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	sink.Write(AuditRecord{Provider: "p", Model: "m", Outcome: AuditOutcomeSuccess})
+	sink.Write(AuditRecord{Provider: "p", Model: "m", Outcome: AuditOutcomeError})
+	sink.Close()
+
+	file, _ := os.Open(path)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	lines := 0
+	for scanner.Scan() {
+		var r AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Errorf("invalid JSON line: %v", err)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("expected 2 lines, got %d", lines)
+	}
+}
+
+func TestSQLAuditSink(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	sink, err := NewSQLAuditSink(db)
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	err = sink.Write(AuditRecord{
+		Provider: "deepseek",
+		Model:    "deepseek-chat",
+		Usage:    TokenUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+		Outcome:  AuditOutcomeSuccess,
+		Tags:     map[string]string{"symbol": "BTCUSDT"},
+		Tenant:   "desk-a",
+	})
+	if err != nil {
+		t.Fatalf("write should not error: %v", err)
+	}
+
+	var count, total int
+	db.QueryRow(`SELECT COUNT(*), SUM(total_tokens) FROM ai_audit_log`).Scan(&count, &total)
+	if count != 1 || total != 3 {
+		t.Errorf("expected 1 row with 3 tokens, got %d rows / %d tokens", count, total)
+	}
+	var tenant string
+	db.QueryRow(`SELECT tenant FROM ai_audit_log`).Scan(&tenant)
+	if tenant != "desk-a" {
+		t.Errorf("expected tenant desk-a, got %q", tenant)
+	}
+}
+
+func TestParseUsage_Shapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"openai", `{"usage":{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}}`, 7},
+		{"anthropic", `{"usage":{"input_tokens":5,"output_tokens":6}}`, 11},
+		{"ollama", `{"prompt_eval_count":1,"eval_count":2}`, 3},
+		{"invalid", `not json`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseUsage([]byte(tt.body)).TotalTokens; got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
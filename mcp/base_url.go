@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidBaseURL base URL can't be used to build request URLs
+var ErrInvalidBaseURL = errors.New("invalid base URL")
+
+// versionSegmentPattern API version path segment ("v1", "v1beta", "v3")
+var versionSegmentPattern = regexp.MustCompile(`^v\d+[a-z0-9]*$`)
+
+// NormalizeBaseURL cleans up and validates a provider base URL
+//
+// Surrounding whitespace, trailing slashes and empty path segments are removed, and a
+// repeated version segment ("https://host/v1/v1", a common copy-paste mistake) is
+// collapsed. The URL must be absolute http(s) with a host and without query or fragment
+// (use WithExtraQuery for query parameters). A trailing "#" marks a full endpoint URL
+// (see SetAPIKey): its path and query are kept as is, and so is the marker. Errors wrap
+// ErrInvalidBaseURL.
+//
+// Usage example:
+//   baseURL, err := mcp.NormalizeBaseURL("https://api.openai.com/v1/v1/")
+//   // baseURL == "https://api.openai.com/v1"
+func NormalizeBaseURL(raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if full, ok := strings.CutSuffix(trimmed, "#"); ok {
+		normalized, err := normalizeURL(raw, full, true)
+		if err != nil {
+			return "", err
+		}
+		return normalized + "#", nil
+	}
+	return normalizeURL(raw, trimmed, false)
+}
+
+// normalizeURL normalizes trimmed (raw is only used in errors); full endpoint URLs keep path and query
+func normalizeURL(raw, trimmed string, fullURL bool) (string, error) {
+	if trimmed == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidBaseURL)
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidBaseURL, raw, err)
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("%w %q: scheme must be http or https", ErrInvalidBaseURL, raw)
+	}
+	if parsed.Host == "" || parsed.Hostname() == "" {
+		return "", fmt.Errorf("%w %q: missing host", ErrInvalidBaseURL, raw)
+	}
+	if parsed.Fragment != "" {
+		return "", fmt.Errorf("%w %q: fragment is not allowed", ErrInvalidBaseURL, raw)
+	}
+	if fullURL {
+		return parsed.String(), nil
+	}
+	if parsed.RawQuery != "" {
+		return "", fmt.Errorf("%w %q: query is not allowed (use WithExtraQuery)", ErrInvalidBaseURL, raw)
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if segment == "" {
+			continue
+		}
+		if n := len(segments); n > 0 && segments[n-1] == segment && versionSegmentPattern.MatchString(segment) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	parsed.Path = ""
+	parsed.RawPath = ""
+	if len(segments) > 0 {
+		parsed.Path = "/" + strings.Join(segments, "/")
+	}
+	return parsed.String(), nil
+}
+
+// normalizedBaseURL returns the normalized form of raw, or raw itself with the problem
+//
+// fullURL is the UseFullURL mode, in which raw is a whole endpoint URL (query allowed).
+func normalizedBaseURL(raw string, fullURL bool) (string, error) {
+	normalized, err := normalizeURL(raw, strings.TrimSpace(raw), fullURL)
+	if err != nil {
+		return raw, err
+	}
+	return normalized, nil
+}
+
+// setBaseURL sets BaseURL, remembering a validation problem to fail calls with
+func (client *Client) setBaseURL(raw string) {
+	client.BaseURL, client.baseURLErr = normalizedBaseURL(raw, client.UseFullURL)
+	if client.baseURLErr != nil {
+		client.logger.Warnf("⚠️  [%s] %v", client.String(), client.baseURLErr)
+	}
+}
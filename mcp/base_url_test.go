@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"https://api.openai.com/v1", "https://api.openai.com/v1", false},
+		{" https://api.openai.com/v1/ ", "https://api.openai.com/v1", false},
+		{"https://api.openai.com/v1/v1", "https://api.openai.com/v1", false},
+		{"HTTPS://gateway.local//llm//proxy/v1///", "https://gateway.local/llm/proxy/v1", false},
+		{"https://generativelanguage.googleapis.com/v1beta/v1beta/openai/", "https://generativelanguage.googleapis.com/v1beta/openai", false},
+		{"http://localhost:11434", "http://localhost:11434", false},
+		{"https://host/api/api", "https://host/api/api", false},
+		{"", "", true},
+		{"api.openai.com/v1", "", true},
+		{"ftp://api.openai.com/v1", "", true},
+		{"https:///v1", "", true},
+		{"https://api.openai.com/v1?key=x", "", true},
+		{"https://res.openai.azure.com/openai/deployments/gpt4/chat/completions?api-version=2024-02-01#",
+			"https://res.openai.azure.com/openai/deployments/gpt4/chat/completions?api-version=2024-02-01#", false},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBaseURL(tt.raw)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidBaseURL) {
+				t.Errorf("NormalizeBaseURL(%q): expected ErrInvalidBaseURL, got %q (%v)", tt.raw, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeBaseURL(%q): expected %q, got %q (%v)", tt.raw, tt.want, got, err)
+		}
+	}
+}
+
+func TestBaseURL_ConstructionError(t *testing.T) {
+	if _, err := NewProviderClient("openai", WithAPIKey("sk-test-key"), WithBaseURL("api.openai.com/v1")); !errors.Is(err, ErrInvalidBaseURL) {
+		t.Errorf("expected construction error, got %v", err)
+	}
+
+	client := NewClient(WithProvider(ProviderCustom), WithAPIKey("sk-test-key"), WithModel("m"), WithLogger(NewNoopLogger()),
+		WithBaseURL("localhost:8080/v1")).(*Client)
+	if err := client.Validate(); !errors.Is(err, ErrInvalidBaseURL) {
+		t.Errorf("Validate should report the base URL, got %v", err)
+	}
+	if _, err := client.CallMessages(context.Background(), "system", "user"); !errors.Is(err, ErrInvalidBaseURL) {
+		t.Errorf("calls should fail with ErrInvalidBaseURL, got %v", err)
+	}
+
+	client.SetAPIKey("sk-test-key", "https://gateway.local/v1/v1/", "m")
+	if client.baseURLErr != nil || client.buildUrl() != "https://gateway.local/v1/chat/completions" {
+		t.Errorf("SetAPIKey should normalize the URL, got %q (%v)", client.buildUrl(), client.baseURLErr)
+	}
+}
+
+func TestBaseURL_FullURLKeepsQuery(t *testing.T) {
+	const endpoint = "https://res.openai.azure.com/openai/deployments/gpt4/chat/completions?api-version=2024-02-01"
+
+	client := NewClient(WithProvider(ProviderCustom), WithAPIKey("sk-test-key"), WithModel("m"), WithLogger(NewNoopLogger())).(*Client)
+	client.SetAPIKey("sk-test-key", endpoint+"#", "m")
+	if client.baseURLErr != nil || !client.UseFullURL || client.buildUrl() != endpoint {
+		t.Errorf("SetAPIKey: expected %q, got %q (%v)", endpoint, client.buildUrl(), client.baseURLErr)
+	}
+
+	for name, opts := range map[string][]ClientOption{
+		"marker":                   {WithBaseURL(endpoint + "#")},
+		"WithUseFullURL after URL": {WithBaseURL(endpoint), WithUseFullURL(true)},
+		"WithUseFullURL before":    {WithUseFullURL(true), WithBaseURL(endpoint)},
+	} {
+		opts = append([]ClientOption{WithProvider(ProviderCustom), WithAPIKey("sk-test-key"), WithModel("m"), WithLogger(NewNoopLogger())}, opts...)
+		client := NewClient(opts...).(*Client)
+		if client.baseURLErr != nil || client.buildUrl() != endpoint {
+			t.Errorf("%s: expected %q, got %q (%v)", name, endpoint, client.buildUrl(), client.baseURLErr)
+		}
+	}
+
+	if client := NewClient(WithProvider(ProviderCustom), WithBaseURL(endpoint)).(*Client); !errors.Is(client.baseURLErr, ErrInvalidBaseURL) {
+		t.Errorf("query without full-URL mode should be rejected, got %v", client.baseURLErr)
+	}
+}
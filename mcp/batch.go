@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Prompt one input of a batch call
+//
+// If Request is set it is used as-is, otherwise SystemPrompt/UserPrompt are sent.
+type Prompt struct {
+	SystemPrompt string
+	UserPrompt   string
+	Request      *Request
+}
+
+// BatchResult result of one batch prompt (same index as the input)
+type BatchResult struct {
+	Index   int
+	Text    string
+	Err     error
+	Latency time.Duration
+}
+
+// CallBatch sends prompts through a bounded worker pool
+//
+// Results preserve input order. A failing prompt doesn't fail the batch: its error
+// is stored in the corresponding result (use BatchErrors to aggregate them).
+// Requests still go through the client rate limiter and retry policy. Prompts not
+// started before ctx is cancelled fail with ctx.Err().
+//
+// Usage example:
+//   results := client.CallBatch(ctx, []mcp.Prompt{
+//       {SystemPrompt: sys, UserPrompt: "Analyze BTC"},
+//       {SystemPrompt: sys, UserPrompt: "Analyze ETH"},
+//   }, 4)
+//   if err := mcp.BatchErrors(results); err != nil {
+//       log.Printf("partial failure: %v", err)
+//   }
+func (client *Client) CallBatch(ctx context.Context, prompts []Prompt, concurrency int) []BatchResult {
+	results := make([]BatchResult, len(prompts))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(prompts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = client.callPrompt(ctx, i, prompts[i])
+			}
+		}()
+	}
+
+	for i := range prompts {
+		if ctx.Err() != nil {
+			results[i] = BatchResult{Index: i, Err: ctx.Err()}
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			results[i] = BatchResult{Index: i, Err: ctx.Err()}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// callPrompt executes one batch prompt
+func (client *Client) callPrompt(ctx context.Context, index int, prompt Prompt) BatchResult {
+	start := time.Now()
+
+	req := prompt.Request
+	if req == nil {
+		var err error
+		req, err = NewRequestBuilder().
+			WithSystemPrompt(prompt.SystemPrompt).
+			WithUserPrompt(prompt.UserPrompt).
+			Build()
+		if err != nil {
+			return BatchResult{Index: index, Err: err}
+		}
+	}
+
+	text, err := client.CallWithRequestContext(ctx, req)
+	return BatchResult{Index: index, Text: text, Err: err, Latency: time.Since(start)}
+}
+
+// BatchErrors joins errors of failed batch results (nil if all succeeded)
+func BatchErrors(results []BatchResult) error {
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("prompt %d: %w", r.Index, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Batch Calls and Rate Limiter
+// ============================================================
+
+// echoResponseFunc answers with the user prompt, failing prompts containing "fail"
+func echoResponseFunc(inFlight, maxInFlight *int32) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(inFlight, 1)
+		for {
+			max := atomic.LoadInt32(maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(inFlight, -1)
+
+		var body struct {
+			Messages []Message `json:"messages"`
+		}
+		data, _ := io.ReadAll(req.Body)
+		json.Unmarshal(data, &body)
+		prompt := body.Messages[len(body.Messages)-1].Content
+
+		if strings.Contains(prompt, "fail") {
+			return &http.Response{StatusCode: 400, Body: io.NopCloser(bytes.NewBufferString("bad prompt")), Header: make(http.Header)}, nil
+		}
+		reply, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"message": map[string]any{"content": "echo:" + prompt}}}})
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(reply)), Header: make(http.Header)}, nil
+	}
+}
+
+func TestCallBatch_OrderAndPartialFailures(t *testing.T) {
+	var inFlight, maxInFlight int32
+	transport := unlockedTransport(echoResponseFunc(&inFlight, &maxInFlight))
+
+	client := NewClient(
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+	).(*Client)
+
+	prompts := []Prompt{
+		{UserPrompt: "a"}, {UserPrompt: "b"}, {UserPrompt: "fail"}, {UserPrompt: "d"}, {UserPrompt: "e"}, {UserPrompt: "f"},
+	}
+	results := client.CallBatch(context.Background(), prompts, 2)
+
+	if len(results) != len(prompts) {
+		t.Fatalf("expected %d results, got %d", len(prompts), len(results))
+	}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("result %d has index %d", i, r.Index)
+		}
+		if i == 2 {
+			if r.Err == nil {
+				t.Error("failing prompt should have error")
+			}
+			continue
+		}
+		if r.Err != nil || r.Text != "echo:"+prompts[i].UserPrompt {
+			t.Errorf("result %d: unexpected %q / %v", i, r.Text, r.Err)
+		}
+	}
+
+	if err := BatchErrors(results); err == nil || !strings.Contains(err.Error(), "prompt 2") {
+		t.Errorf("BatchErrors should report prompt 2, got %v", err)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("concurrency should be bounded to 2, got %d", maxInFlight)
+	}
+}
+
+func TestCallBatch_CancelledContext(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("ok")
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+	).(*Client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := client.CallBatch(ctx, []Prompt{{UserPrompt: "a"}, {UserPrompt: "b"}}, 1)
+	for _, r := range results {
+		if r.Err == nil {
+			t.Error("cancelled batch should fail every prompt")
+		}
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(50, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("wait should not error: %v", err)
+		}
+	}
+	// First token is free, the other two take ~20ms each
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("limiter should throttle, took only %v", elapsed)
+	}
+
+	var nilLimiter *RateLimiter
+	if err := nilLimiter.Wait(context.Background()); err != nil {
+		t.Error("nil limiter should never block")
+	}
+}
+
+// unlockedTransport RoundTripper calling fn concurrently (MockHTTPClient serializes calls)
+type unlockedTransport func(req *http.Request) (*http.Response, error)
+
+func (f unlockedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
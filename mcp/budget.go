@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBudgetExceeded is returned by calls made after the client budget was used up
+var ErrBudgetExceeded = errors.New("AI budget exceeded")
+
+// Budget spending limit in USD, based on EstimateCost of every response
+//
+// A nil *Budget is unlimited. One Budget can be shared by several clients to cap
+// their combined spend. Requests already in flight when the limit is reached still
+// complete, so spend can overshoot by up to one call per client.
+type Budget struct {
+	Limit float64 // USD
+
+	mu    sync.Mutex
+	spent float64
+}
+
+// NewBudget creates budget of limit USD
+func NewBudget(limit float64) *Budget {
+	return &Budget{Limit: limit}
+}
+
+// WithBudget fails calls with ErrBudgetExceeded once budget is spent
+//
+// Usage example:
+//   daily := mcp.NewBudget(5)
+//   client := mcp.NewClient(mcp.WithBudget(daily))
+//   // reset every day: daily.Reset()
+func WithBudget(budget *Budget) ClientOption {
+	return func(c *Config) {
+		c.Budget = budget
+	}
+}
+
+// Spent returns USD spent so far
+func (b *Budget) Spent() float64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// Remaining returns USD left (never negative)
+func (b *Budget) Remaining() float64 {
+	if b == nil {
+		return 0
+	}
+	return max(b.Limit-b.Spent(), 0)
+}
+
+// Reset sets spend back to zero (e.g. at the start of a new budget period)
+func (b *Budget) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spent = 0
+}
+
+// check fails when budget is used up
+func (b *Budget) check() error {
+	if b == nil {
+		return nil
+	}
+	if spent := b.Spent(); spent >= b.Limit {
+		return fmt.Errorf("%w: spent $%.4f of $%.4f", ErrBudgetExceeded, spent, b.Limit)
+	}
+	return nil
+}
+
+// spend adds estimated cost of usage by model
+func (b *Budget) spend(model string, usage TokenUsage) {
+	if b == nil {
+		return
+	}
+	usage.Model = model
+	cost := EstimateCost(usage)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spent += cost
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"time"
+)
+
+// RequestEvent a request about to be sent (one per attempt)
+type RequestEvent struct {
+	RequestID string
+	Tenant    string
+	Tags      map[string]string
+	Provider  string
+	Model     string
+	Body      map[string]any // Provider request body; changes are sent (e.g. rewritten prompts)
+}
+
+// ResponseEvent a successful request
+type ResponseEvent struct {
+	RequestID string
+	Tenant    string
+	Tags      map[string]string
+	Provider  string
+	Model     string
+	Response  *Response
+	Latency   time.Duration
+}
+
+// ErrorEvent a failed request (it may still be retried, see RetryEvent)
+type ErrorEvent struct {
+	RequestID string
+	Tenant    string
+	Tags      map[string]string
+	Provider  string
+	Model     string
+	Err       error
+	Latency   time.Duration
+}
+
+// RetryEvent a failed attempt about to be retried
+type RetryEvent struct {
+	RequestID  string
+	Tags       map[string]string
+	Provider   string
+	Model      string
+	Attempt    int // Attempt that failed (1-based)
+	MaxRetries int
+	Wait       time.Duration // Delay before the next attempt
+	Err        error
+}
+
+// Hooks callbacks run synchronously at call lifecycle points (nil fields are skipped)
+//
+// Requests and their outcomes are reported per attempt, streams included. Callbacks run
+// on the calling goroutine, so keep them fast and hand slow work off.
+type Hooks struct {
+	OnRequest  func(RequestEvent)
+	OnResponse func(ResponseEvent)
+	OnError    func(ErrorEvent)
+	OnRetry    func(RetryEvent)
+}
+
+// WithHooks adds lifecycle callbacks (may be used several times, hooks run in order)
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithHooks(mcp.Hooks{
+//       OnResponse: func(e mcp.ResponseEvent) { latency.Observe(e.Latency.Seconds()) },
+//       OnError:    func(e mcp.ErrorEvent) { alerts.Notify(e.Provider, e.Err) },
+//   }))
+func WithHooks(hooks Hooks) ClientOption {
+	return func(c *Config) {
+		c.Hooks = append(c.Hooks, hooks)
+	}
+}
+
+// fireRequest runs OnRequest hooks for a request body about to be sent
+func (client *Client) fireRequest(ctx context.Context, requestBody map[string]any) {
+	for _, h := range client.config.Hooks {
+		if h.OnRequest != nil {
+			h.OnRequest(RequestEvent{
+				RequestID: RequestIDFromContext(ctx),
+				Tenant:    client.tenantFor(ctx),
+				Tags:      client.tagsFor(ctx),
+				Provider:  client.Provider,
+				Model:     bodyModel(requestBody, client.Model),
+				Body:      requestBody,
+			})
+		}
+	}
+}
+
+// fireResult runs OnResponse or OnError hooks for the outcome of a request
+func (client *Client) fireResult(ctx context.Context, model string, response *Response, latency time.Duration, err error) {
+	for _, h := range client.config.Hooks {
+		switch {
+		case err != nil && h.OnError != nil:
+			h.OnError(ErrorEvent{
+				RequestID: RequestIDFromContext(ctx),
+				Tenant:    client.tenantFor(ctx),
+				Tags:      client.tagsFor(ctx),
+				Provider:  client.Provider,
+				Model:     model,
+				Err:       err,
+				Latency:   latency,
+			})
+		case err == nil && h.OnResponse != nil:
+			h.OnResponse(ResponseEvent{
+				RequestID: RequestIDFromContext(ctx),
+				Tenant:    client.tenantFor(ctx),
+				Tags:      client.tagsFor(ctx),
+				Provider:  client.Provider,
+				Model:     model,
+				Response:  response,
+				Latency:   latency,
+			})
+		}
+	}
+}
+
+// fireRetry runs OnRetry hooks before waiting for the next attempt
+func (client *Client) fireRetry(ctx context.Context, model string, attempt, maxRetries int, wait time.Duration, err error) {
+	for _, h := range client.config.Hooks {
+		if h.OnRetry != nil {
+			h.OnRetry(RetryEvent{
+				RequestID:  RequestIDFromContext(ctx),
+				Tags:       client.tagsFor(ctx),
+				Provider:   client.Provider,
+				Model:      model,
+				Attempt:    attempt,
+				MaxRetries: maxRetries,
+				Wait:       wait,
+				Err:        err,
+			})
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithHooks_Lifecycle(t *testing.T) {
+	mock := NewMockHTTPClient()
+	calls := 0
+	mock.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`)),
+			Header:     make(http.Header),
+		}, nil
+	}
+
+	var events []string
+	var retry RetryEvent
+	hooks := Hooks{
+		OnRequest: func(e RequestEvent) {
+			events = append(events, "request")
+			messages := e.Body["messages"].([]map[string]string)
+			messages[len(messages)-1]["content"] += " (answer briefly)"
+		},
+		OnResponse: func(e ResponseEvent) { events = append(events, "response:"+e.Response.Text) },
+		OnError:    func(e ErrorEvent) { events = append(events, "error") },
+		OnRetry: func(e RetryEvent) {
+			events = append(events, "retry")
+			retry = e
+		},
+	}
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(mock.ToHTTPClient()),
+		WithRetryWaitBase(time.Millisecond), WithHooks(hooks))
+
+	if _, err := client.(*Client).CallMessages(context.Background(), "system", "BTC?"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(events, ","); got != "request,error,retry,request,response:ok" {
+		t.Errorf("unexpected hook order %s", got)
+	}
+	if retry.Attempt != 1 || retry.Wait != time.Millisecond || retry.Err == nil || retry.RequestID == "" {
+		t.Errorf("unexpected retry event %+v", retry)
+	}
+	sent, _ := io.ReadAll(mock.GetLastRequest().Body)
+	if !strings.Contains(string(sent), "BTC? (answer briefly)") {
+		t.Errorf("OnRequest changes should be sent, got %s", sent)
+	}
+}
@@ -0,0 +1,397 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// capabilityCacheTTL probed capabilities of a server and model are reused this long
+const capabilityCacheTTL = time.Hour
+
+// probeImage 1x1 PNG sent by the vision probe
+const probeImage = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mP8z8BQDwAEhQGAhKmMIQAAAABJRU5ErkJggg=="
+
+// ErrCapabilityUnsupported is returned when a request needs a feature the server lacks (see WithCapabilityProbe)
+var ErrCapabilityUnsupported = errors.New("server does not support requested capability")
+
+// ServerCapabilities features a server supports for a model
+type ServerCapabilities struct {
+	Model        string
+	Streaming    bool
+	Tools        bool
+	JSONMode     bool
+	Vision       bool
+	FromMetadata []string // Capabilities read from /models metadata (the others were tested with tiny requests)
+	CheckedAt    time.Time
+}
+
+// Supports reports whether capability (Capability* constant) is supported
+func (c *ServerCapabilities) Supports(capability string) bool {
+	switch capability {
+	case CapabilityStreaming:
+		return c.Streaming
+	case CapabilityTools:
+		return c.Tools
+	case CapabilityJSONMode:
+		return c.JSONMode
+	case CapabilityVision:
+		return c.Vision
+	}
+	return false
+}
+
+var (
+	capabilityMu    sync.Mutex
+	capabilityCache = map[string]*ServerCapabilities{}
+)
+
+// WithCapabilityProbe detects the features of an unknown OpenAI-compatible server and adapts requests
+//
+// On the first call the server's capabilities are probed (see ProbeCapabilities) and
+// cached per base URL and model. Requests are then shaped to fit: JSON mode falls back
+// to a prompt instruction (responses are still validated), streamed calls are sent
+// non-streamed and delivered as single events, and requests needing tools or image
+// inputs fail early with ErrCapabilityUnsupported. When probing fails, requests are
+// sent unchanged.
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithProvider(mcp.ProviderCustom),
+//       mcp.WithBaseURL("http://gpu-box:8000/v1"),
+//       mcp.WithCapabilityProbe(),
+//   )
+func WithCapabilityProbe() ClientOption {
+	return func(c *Config) {
+		c.CapabilityProbe = true
+	}
+}
+
+// ProbeCapabilities returns the features the server supports for the client's model
+//
+// Model metadata of GET /models is used where servers publish it (LM Studio, Ollama,
+// OpenRouter, LiteLLM); remaining capabilities are tested with tiny requests (a
+// few tokens each). A request rejected with 400/422 means unsupported; other failures
+// abort the probe. Results are cached for an hour.
+//
+// Usage example:
+//   capabilities, err := client.ProbeCapabilities(ctx)
+//   if err == nil && !capabilities.Tools {
+//       log.Printf("%s cannot call tools", capabilities.Model)
+//   }
+func (client *Client) ProbeCapabilities(ctx context.Context) (*ServerCapabilities, error) {
+	key := client.BaseURL + " " + client.Model
+	capabilityMu.Lock()
+	cached, ok := capabilityCache[key]
+	capabilityMu.Unlock()
+	if ok && time.Since(cached.CheckedAt) < capabilityCacheTTL {
+		return cached, nil
+	}
+
+	capabilities, err := client.probeCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	capabilityMu.Lock()
+	capabilityCache[key] = capabilities
+	capabilityMu.Unlock()
+	client.logger.Infof("🔎 [%s] Server capabilities of %s: streaming=%v tools=%v json=%v vision=%v",
+		client.String(), client.Model, capabilities.Streaming, capabilities.Tools, capabilities.JSONMode, capabilities.Vision)
+	return capabilities, nil
+}
+
+func (client *Client) probeCapabilities(ctx context.Context) (*ServerCapabilities, error) {
+	capabilities := &ServerCapabilities{Model: client.Model, CheckedAt: time.Now()}
+
+	// Metadata is optional: most servers only list model IDs
+	known := map[string]bool{}
+	if body, err := client.fetchModels(ctx); err == nil {
+		known = modelMetadataCapabilities(body, client.Model)
+	}
+
+	probes := []struct {
+		capability string
+		supported  *bool
+		extend     func(body map[string]any)
+		accept     func(resp *http.Response, body []byte) bool
+	}{
+		{CapabilityStreaming, &capabilities.Streaming, func(body map[string]any) {
+			body["stream"] = true
+		}, func(resp *http.Response, body []byte) bool {
+			return strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") || bytes.HasPrefix(bytes.TrimSpace(body), []byte("data:"))
+		}},
+		{CapabilityTools, &capabilities.Tools, func(body map[string]any) {
+			body["tools"] = []Tool{{Type: "function", Function: FunctionDef{
+				Name:        "ping",
+				Description: "Capability probe, do not call",
+				Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+			}}}
+		}, nil},
+		{CapabilityJSONMode, &capabilities.JSONMode, func(body map[string]any) {
+			body["response_format"] = map[string]any{"type": "json_object"}
+		}, nil},
+		{CapabilityVision, &capabilities.Vision, func(body map[string]any) {
+			body["messages"] = []map[string]any{{"role": "user", "content": []map[string]any{
+				{"type": "text", "text": "Reply with a JSON object: {\"ok\":true}"},
+				{"type": "image_url", "image_url": map[string]any{"url": probeImage}},
+			}}}
+		}, nil},
+	}
+	for _, probe := range probes {
+		if supported, ok := known[probe.capability]; ok {
+			*probe.supported = supported
+			capabilities.FromMetadata = append(capabilities.FromMetadata, probe.capability)
+			continue
+		}
+		supported, err := client.probeChat(ctx, probe.extend, probe.accept)
+		if err != nil {
+			return nil, fmt.Errorf("capability probe (%s) failed: %w", probe.capability, err)
+		}
+		*probe.supported = supported
+	}
+	return capabilities, nil
+}
+
+// probeChat sends a tiny chat request, reporting whether the server accepted it
+func (client *Client) probeChat(ctx context.Context, extend func(map[string]any), accept func(*http.Response, []byte) bool) (bool, error) {
+	requestBody := map[string]any{
+		"model":      client.Model,
+		"messages":   []map[string]string{{"role": "user", "content": "Reply with a JSON object: {\"ok\":true}"}},
+		"max_tokens": 8,
+	}
+	omitEmptyModel(requestBody)
+	extend(requestBody)
+
+	jsonData, err := client.hooks.marshalRequestBody(requestBody)
+	if err != nil {
+		return false, err
+	}
+	req, err := client.hooks.buildRequest(withExtraQuery(client.hooks.buildUrl(), client.config.ExtraQuery), jsonData)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return accept == nil || accept(resp, body), nil
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		return false, nil // Rejected parameter
+	default:
+		return false, client.newAPIError(resp.StatusCode, body)
+	}
+}
+
+// modelMetadataCapabilities reads capabilities published in a /models listing for model
+//
+// Understands capabilities lists or maps (LM Studio, Ollama), LM Studio's "vlm" type,
+// OpenRouter supported_parameters and input_modalities, and LiteLLM model_info.
+func modelMetadataCapabilities(body []byte, model string) map[string]bool {
+	type metadata struct {
+		ID                  string          `json:"id"`
+		Type                string          `json:"type"`
+		Capabilities        json.RawMessage `json:"capabilities"`
+		SupportedParameters []string        `json:"supported_parameters"`
+		Architecture        struct {
+			InputModalities []string `json:"input_modalities"`
+		} `json:"architecture"`
+		ModelInfo struct {
+			SupportsVision          *bool `json:"supports_vision"`
+			SupportsFunctionCalling *bool `json:"supports_function_calling"`
+			SupportsResponseSchema  *bool `json:"supports_response_schema"`
+		} `json:"model_info"`
+	}
+	var list struct {
+		Data []metadata `json:"data"`
+	}
+	known := map[string]bool{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return known
+	}
+	var entry *metadata
+	for i := range list.Data {
+		if list.Data[i].ID == model || (model == "" && len(list.Data) == 1) {
+			entry = &list.Data[i]
+			break
+		}
+	}
+	if entry == nil {
+		return known
+	}
+
+	aliases := map[string]string{
+		"tools": CapabilityTools, "tool_use": CapabilityTools, "function_calling": CapabilityTools,
+		"vision": CapabilityVision, "image_input": CapabilityVision,
+		"json_mode": CapabilityJSONMode, "structured_outputs": CapabilityJSONMode, "response_format": CapabilityJSONMode,
+	}
+	var names []string
+	var flags map[string]bool
+	if json.Unmarshal(entry.Capabilities, &names) == nil && names != nil {
+		// A capability list names what is supported: tools and vision are otherwise absent
+		known[CapabilityTools], known[CapabilityVision] = false, false
+		for _, name := range names {
+			if capability, ok := aliases[strings.ToLower(name)]; ok {
+				known[capability] = true
+			}
+		}
+	} else if json.Unmarshal(entry.Capabilities, &flags) == nil {
+		for name, supported := range flags {
+			if capability, ok := aliases[strings.ToLower(name)]; ok {
+				known[capability] = supported
+			}
+		}
+	}
+	switch entry.Type {
+	case "vlm":
+		known[CapabilityVision] = true
+	case "llm":
+		known[CapabilityVision] = false
+	}
+	if entry.SupportedParameters != nil {
+		known[CapabilityTools] = slices.Contains(entry.SupportedParameters, "tools")
+		known[CapabilityJSONMode] = slices.Contains(entry.SupportedParameters, "response_format") ||
+			slices.Contains(entry.SupportedParameters, "structured_outputs")
+	}
+	if entry.Architecture.InputModalities != nil {
+		known[CapabilityVision] = slices.Contains(entry.Architecture.InputModalities, "image")
+	}
+	for capability, supported := range map[string]*bool{
+		CapabilityVision:   entry.ModelInfo.SupportsVision,
+		CapabilityTools:    entry.ModelInfo.SupportsFunctionCalling,
+		CapabilityJSONMode: entry.ModelInfo.SupportsResponseSchema,
+	} {
+		if supported != nil {
+			known[capability] = *supported
+		}
+	}
+	return known
+}
+
+// gateCapabilities adapts or rejects a request body the server cannot handle (WithCapabilityProbe)
+//
+// Returns the server's capabilities (nil when probing is disabled or failed).
+func (client *Client) gateCapabilities(ctx context.Context, requestBody map[string]any) (*ServerCapabilities, error) {
+	if !client.config.CapabilityProbe {
+		return nil, nil
+	}
+	capabilities, err := client.ProbeCapabilities(ctx)
+	if err != nil {
+		client.logger.Warnf("⚠️  [%s] %v, sending request unchanged", client.String(), err)
+		return nil, nil
+	}
+
+	if _, ok := requestBody["tools"]; ok && !capabilities.Tools {
+		return capabilities, fmt.Errorf("%w: %s cannot call tools", ErrCapabilityUnsupported, capabilities.Model)
+	}
+	if !capabilities.Vision && hasImageContent(requestBody["messages"]) {
+		return capabilities, fmt.Errorf("%w: %s cannot read images", ErrCapabilityUnsupported, capabilities.Model)
+	}
+	if format, ok := requestBody["response_format"].(map[string]any); ok && !capabilities.JSONMode {
+		delete(requestBody, "response_format")
+		requestBody["messages"] = withSystemInstruction(requestBody["messages"], jsonInstruction(format))
+	}
+	return capabilities, nil
+}
+
+// jsonInstruction asks for JSON output in the prompt, for servers without JSON mode
+func jsonInstruction(format map[string]any) string {
+	instruction := "Respond with a single JSON object only, without any other text or code fences."
+	if spec, ok := format["json_schema"].(map[string]any); ok && spec["schema"] != nil {
+		if schema, err := json.Marshal(spec["schema"]); err == nil {
+			instruction += " The object must conform to this JSON Schema: " + string(schema)
+		}
+	}
+	return instruction
+}
+
+// withSystemInstruction appends instruction to the system message, adding one if there is none
+func withSystemInstruction(messages any, instruction string) any {
+	switch messages := messages.(type) {
+	case []map[string]string:
+		if len(messages) > 0 && messages[0]["role"] == "system" {
+			messages[0]["content"] += "\n\n" + instruction
+			return messages
+		}
+		return append([]map[string]string{{"role": "system", "content": instruction}}, messages...)
+	case []map[string]any:
+		if len(messages) > 0 && messages[0]["role"] == "system" {
+			if content, ok := messages[0]["content"].(string); ok {
+				messages[0]["content"] = content + "\n\n" + instruction
+				return messages
+			}
+		}
+		return append([]map[string]any{{"role": "system", "content": instruction}}, messages...)
+	}
+	return messages
+}
+
+// hasImageContent reports whether messages carry image content parts
+func hasImageContent(messages any) bool {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return false
+	}
+	var decoded []struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if json.Unmarshal(data, &decoded) != nil {
+		return false
+	}
+	for _, msg := range decoded {
+		var parts []struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(msg.Content, &parts) != nil {
+			continue
+		}
+		for _, part := range parts {
+			if part.Type == "image_url" || part.Type == "input_image" || part.Type == "image" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// emulateStream sends a streamed request non-streamed and delivers the answer as single events
+//
+// Used for servers that cannot stream (see WithCapabilityProbe).
+func (client *Client) emulateStream(ctx context.Context, requestBody map[string]any, vault *piiVault, onEvent func(StreamEvent) error) (*Response, error) {
+	delete(requestBody, "stream")
+	delete(requestBody, "stream_options")
+	response, err := client.send(ctx, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Reasoning != "" {
+		if err := onEvent(StreamEvent{Type: StreamReasoningDelta, Text: vault.restore(response.Reasoning)}); err != nil {
+			return nil, err
+		}
+	}
+	if response.Text != "" {
+		if err := onEvent(StreamEvent{Type: StreamTextDelta, Text: vault.restore(response.Text)}); err != nil {
+			return nil, err
+		}
+	}
+	calls := vault.restoreToolCalls(response.ToolCalls)
+	for i := range calls {
+		if err := onEvent(StreamEvent{Type: StreamToolCallComplete, ToolCall: &calls[i], Index: i}); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test capability probing
+// ============================================================
+
+// probeServer answers like an OpenAI-compatible server without streaming and JSON mode support
+func probeServer(chatBodies *[]map[string]any) *MockHTTPClient {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		respond := func(status int, body string) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}
+		if strings.HasSuffix(req.URL.Path, "/models") {
+			return respond(http.StatusOK, `{"data":[{"id":"local-model"}]}`)
+		}
+		data, _ := io.ReadAll(req.Body)
+		var body map[string]any
+		json.Unmarshal(data, &body)
+		*chatBodies = append(*chatBodies, body)
+		if body["response_format"] != nil {
+			return respond(http.StatusBadRequest, `{"error":{"message":"response_format is not supported"}}`)
+		}
+		return respond(http.StatusOK, `{"choices":[{"message":{"content":"{\"action\":\"hold\"}"},"finish_reason":"stop"}]}`)
+	}
+	return mockHTTP
+}
+
+func TestClient_CapabilityProbeGatesRequests(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL("http://probe-gates.local/v1"),
+		WithAPIKey("sk-test"),
+		WithModel("local-model"),
+		WithHTTPClient(probeServer(&bodies).ToHTTPClient()),
+		WithCapabilityProbe(),
+		WithMaxRetries(1),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	capabilities, err := client.ProbeCapabilities(t.Context())
+	if err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	// The mock answers streamed probes with plain JSON, so streaming counts as unsupported
+	if capabilities.Streaming || !capabilities.Tools || capabilities.JSONMode || !capabilities.Vision {
+		t.Errorf("unexpected capabilities %+v", capabilities)
+	}
+	if probes := len(bodies); probes != 4 {
+		t.Errorf("expected 4 probe requests, got %d", probes)
+	}
+
+	bodies = nil
+	request := NewRequestBuilder().
+		WithSystemPrompt("You are a trader.").
+		WithUserPrompt("BTC?").
+		WithJSONResponse(map[string]any{"type": "object", "required": []any{"action"}}).
+		MustBuild()
+	var deltas []string
+	response, err := client.CallStream(t.Context(), request, func(event StreamEvent) error {
+		if event.Type == StreamTextDelta {
+			deltas = append(deltas, event.Text)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("cached capabilities must not be probed again, got %d requests", len(bodies))
+	}
+	body := bodies[0]
+	if body["response_format"] != nil || body["stream"] != nil {
+		t.Errorf("unsupported features must be removed, got %v", body)
+	}
+	system := body["messages"].([]any)[0].(map[string]any)["content"].(string)
+	if !strings.HasPrefix(system, "You are a trader.") || !strings.Contains(system, `"required":["action"]`) {
+		t.Errorf("expected JSON instruction in system prompt, got %q", system)
+	}
+	if response.Text != `{"action":"hold"}` || len(deltas) != 1 || deltas[0] != response.Text {
+		t.Errorf("unexpected emulated stream: text %q deltas %v", response.Text, deltas)
+	}
+}
+
+func TestClient_CapabilityProbeRejectsTools(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body := `{"data":[{"id":"small-model","type":"llm","capabilities":[]}]}`
+		status := http.StatusOK
+		if !strings.HasSuffix(req.URL.Path, "/models") {
+			body, status = `{"error":"boom"}`, http.StatusInternalServerError
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL("http://probe-tools.local/v1"),
+		WithAPIKey("sk-test"),
+		WithModel("small-model"),
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithCapabilityProbe(),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	// Metadata says no tools and no vision, but streaming still needs a probe request
+	if _, err := client.ProbeCapabilities(t.Context()); err == nil {
+		t.Fatal("expected inconclusive probe to fail on server error")
+	}
+
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body := `{"data":[{"id":"small-model","type":"llm","capabilities":[]}]}`
+		if !strings.HasSuffix(req.URL.Path, "/models") {
+			body = "data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\ndata: [DONE]\n\n"
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+	request := NewRequestBuilder().
+		WithUserPrompt("BTC?").
+		AddFunction("get_price", "Get price", map[string]any{"type": "object"}).
+		MustBuild()
+	if _, err := client.Call(t.Context(), request); !errors.Is(err, ErrCapabilityUnsupported) {
+		t.Errorf("expected ErrCapabilityUnsupported, got %v", err)
+	}
+	capabilities, _ := client.ProbeCapabilities(t.Context())
+	if !capabilities.Streaming || capabilities.Tools || capabilities.Vision || len(capabilities.FromMetadata) != 2 {
+		t.Errorf("unexpected capabilities %+v", capabilities)
+	}
+}
+
+func TestModelMetadataCapabilities(t *testing.T) {
+	openRouter := `{"data":[{"id":"vendor/model","supported_parameters":["tools","max_tokens"],"architecture":{"input_modalities":["text","image"]}}]}`
+	known := modelMetadataCapabilities([]byte(openRouter), "vendor/model")
+	if !known[CapabilityTools] || known[CapabilityJSONMode] || !known[CapabilityVision] {
+		t.Errorf("unexpected OpenRouter capabilities %v", known)
+	}
+
+	liteLLM := `{"data":[{"id":"gpt","model_info":{"supports_vision":false,"supports_function_calling":true}}]}`
+	known = modelMetadataCapabilities([]byte(liteLLM), "gpt")
+	if _, ok := known[CapabilityJSONMode]; ok || !known[CapabilityTools] || known[CapabilityVision] {
+		t.Errorf("unexpected LiteLLM capabilities %v", known)
+	}
+
+	if known := modelMetadataCapabilities([]byte(openRouter), "other"); len(known) != 0 {
+		t.Errorf("unknown model must yield no capabilities, got %v", known)
+	}
+}
@@ -54,7 +54,7 @@ func (c *ClaudeClient) SetAPIKey(apiKey string, customURL string, customModel st
 		c.logger.Infof("🔧 [MCP] Claude API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
 	}
 	if customURL != "" {
-		c.BaseURL = customURL
+		c.setBaseURL(customURL)
 		c.logger.Infof("🔧 [MCP] Claude using custom BaseURL: %s", customURL)
 	} else {
 		c.logger.Infof("🔧 [MCP] Claude using default BaseURL: %s", c.BaseURL)
@@ -73,11 +73,6 @@ func (c *ClaudeClient) setAuthHeader(reqHeaders http.Header) {
 	reqHeaders.Set("anthropic-version", "2023-06-01")
 }
 
-// buildUrl Claude uses /messages endpoint
-func (c *ClaudeClient) buildUrl() string {
-	return fmt.Sprintf("%s/messages", c.BaseURL)
-}
-
 // buildMCPRequestBody Claude has different request format
 func (c *ClaudeClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any {
 	requestBody := map[string]any{
@@ -88,6 +83,10 @@ func (c *ClaudeClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[
 			{"role": "user", "content": userPrompt},
 		},
 	}
+	if c.config.ResponseFormat != nil {
+		anthropicJSONTool(requestBody, c.config.ResponseFormat)
+	}
+	c.applyPromptCaching(requestBody, nil)
 
 	return requestBody
 }
@@ -96,13 +95,11 @@ func (c *ClaudeClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[
 func (c *ClaudeClient) parseMCPResponse(body []byte) (string, error) {
 	var response struct {
 		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
 		} `json:"content"`
-		Usage struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-		} `json:"usage"`
 		Error *struct {
 			Type    string `json:"type"`
 			Message string `json:"message"`
@@ -121,16 +118,18 @@ func (c *ClaudeClient) parseMCPResponse(body []byte) (string, error) {
 		return "", fmt.Errorf("Claude returned empty content, body: %s", string(body))
 	}
 
-	// Report token usage if callback is set
-	totalTokens := response.Usage.InputTokens + response.Usage.OutputTokens
-	if TokenUsageCallback != nil && totalTokens > 0 {
-		TokenUsageCallback(TokenUsage{
-			Provider:         c.Provider,
-			Model:            c.Model,
-			PromptTokens:     response.Usage.InputTokens,
-			CompletionTokens: response.Usage.OutputTokens,
-			TotalTokens:      totalTokens,
-		})
+	// Report token usage if callback is set (input includes cache reads and writes)
+	if usage := parseUsage(body); TokenUsageCallback != nil && usage.TotalTokens > 0 {
+		usage.Provider = c.Provider
+		usage.Model = c.Model
+		TokenUsageCallback(usage)
+	}
+
+	// Forced JSON tool call carries the structured answer
+	for _, content := range response.Content {
+		if content.Type == "tool_use" && content.Name == jsonResponseTool {
+			return string(content.Input), nil
+		}
 	}
 
 	// Find text content
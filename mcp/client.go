@@ -2,11 +2,13 @@ package mcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -40,9 +42,11 @@ var (
 type TokenUsage struct {
 	Provider         string
 	Model            string
-	PromptTokens     int
+	PromptTokens     int // Input tokens, including cached ones
 	CompletionTokens int
 	TotalTokens      int
+	CacheReadTokens  int // Input tokens served from the provider prompt cache
+	CacheWriteTokens int // Input tokens written to the prompt cache (Anthropic)
 }
 
 // Client AI API configuration
@@ -58,6 +62,22 @@ type Client struct {
 	logger     Logger // Logger (replaceable)
 	config     *Config // Config object (stores all configurations)
 
+	ownedTransport *http.Transport // Transport created for this client alone (closed by Close)
+
+	lastMu        sync.Mutex
+	lastLatency   LatencyBreakdown // Latency breakdown of the most recent call
+	lastRequestID string           // Request ID of the most recent call
+
+	lastFingerprint string // System fingerprint of the most recent response (deterministic mode)
+
+	lifecycle lifecycle        // In-flight call tracking for graceful Close
+	warm      warmupState      // Coalesces concurrent Warmup calls
+	shadow    *shadowState     // Shadow traffic (nil = disabled)
+	hedge     *hedgeState      // Hedged requests (nil = disabled)
+	webhook   *WebhookNotifier // Lifecycle event delivery (nil = disabled)
+
+	baseURLErr error // Problem with BaseURL, returned by every call (nil = valid)
+
 	// hooks are used to implement dynamic dispatch (polymorphism)
 	// When DeepSeekClient embeds Client, hooks point to DeepSeekClient
 	// This way methods called in call() are automatically dispatched to the overridden version in subclass
@@ -97,8 +117,10 @@ func NewClient(opts ...ClientOption) AIClient {
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	filterResidencyTargets(cfg)
 
 	// 3. Create client instance
+	httpClient, ownedTransport := newHTTPClient(cfg)
 	client := &Client{
 		Provider:   cfg.Provider,
 		APIKey:     cfg.APIKey,
@@ -106,9 +128,15 @@ func NewClient(opts ...ClientOption) AIClient {
 		Model:      cfg.Model,
 		MaxTokens:  cfg.MaxTokens,
 		UseFullURL: cfg.UseFullURL,
-		httpClient: cfg.HTTPClient,
+		httpClient: httpClient,
 		logger:     cfg.Logger,
 		config:     cfg,
+		shadow:     newShadowState(cfg),
+		hedge:      newHedgeState(cfg),
+		webhook:    newWebhookNotifier(cfg),
+		baseURLErr: cfg.baseURLErr,
+
+		ownedTransport: ownedTransport,
 	}
 
 	// 4. Set default Provider (if not set)
@@ -120,6 +148,16 @@ func NewClient(opts ...ClientOption) AIClient {
 
 	// 5. Set hooks to point to self
 	client.hooks = client
+	client.warnNondeterministic()
+	client.resolveResidentEndpoint()
+
+	// 6. Output moderation runs after user guardrails
+	if cfg.ModerateOutputs {
+		cfg.Guardrails = append(cfg.Guardrails, GuardrailRule{
+			Guardrail: &outputModerationGuardrail{client: client},
+			Stage:     GuardOutput,
+		})
+	}
 
 	return client
 }
@@ -131,11 +169,11 @@ func (client *Client) SetAPIKey(apiKey, apiURL, customModel string) {
 
 	// Check if URL ends with #, if so use full URL (without appending /chat/completions)
 	if strings.HasSuffix(apiURL, "#") {
-		client.BaseURL = strings.TrimSuffix(apiURL, "#")
 		client.UseFullURL = true
+		client.setBaseURL(strings.TrimSuffix(apiURL, "#"))
 	} else {
-		client.BaseURL = apiURL
 		client.UseFullURL = false
+		client.setBaseURL(apiURL)
 	}
 
 	client.Model = customModel
@@ -147,9 +185,61 @@ func (client *Client) SetTimeout(timeout time.Duration) {
 
 // CallWithMessages template method - fixed retry flow (cannot be overridden)
 func (client *Client) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
-	if client.APIKey == "" {
-		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	response, err := client.CallMessages(context.Background(), systemPrompt, userPrompt)
+	if err != nil {
+		return "", err
 	}
+	return response.Text, nil
+}
+
+// CallMessages calls AI API with system/user prompts and returns the response with its metadata
+//
+// Same retry flow as CallWithMessages, honoring ctx cancellation, but keeps what the
+// string-returning method discards (usage, finish reason, tool calls, ...).
+//
+// Usage example:
+//   response, err := client.CallMessages(ctx, systemPrompt, userPrompt)
+//   if err == nil {
+//       log.Printf("%s answered in %v using %d tokens", response.Model, response.Latency, response.Usage.TotalTokens)
+//   }
+func (client *Client) CallMessages(ctx context.Context, systemPrompt, userPrompt string) (response *Response, err error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+	if client.handlingRefusals(ctx) {
+		return client.handleRefusals(ctx, userPrompt, func(ctx context.Context, userPrompt string) (*Response, error) {
+			return client.CallMessages(ctx, systemPrompt, userPrompt)
+		}, delegateMessages(systemPrompt, userPrompt))
+	}
+	if client.enforcingLanguage(ctx) {
+		return client.enforceLanguage(ctx, userPrompt, func(ctx context.Context, userPrompt string) (*Response, error) {
+			return client.CallMessages(ctx, systemPrompt, userPrompt)
+		})
+	}
+	if client.hedging(ctx) {
+		return client.hedged(ctx, func(ctx context.Context) (*Response, error) {
+			return client.CallMessages(ctx, systemPrompt, userPrompt)
+		}, delegateMessages(systemPrompt, userPrompt))
+	}
+	if err := client.lifecycle.begin(); err != nil {
+		return nil, err
+	}
+	defer client.lifecycle.end()
+
+	// One request ID for all attempts of this call
+	ctx, requestID := client.ensureRequestID(ctx)
+	ctx, finishWebhook := client.startWebhookCall(ctx)
+	defer func() { finishWebhook(err) }()
+	start := time.Now()
+
+	// Sensitive data is replaced by placeholders, then input guardrails may block or redact
+	vault := client.newPIIVault()
+	systemPrompt, userPrompt = vault.redact(systemPrompt), vault.redact(userPrompt)
+	violations, err := client.runGuardrails(ctx, GuardInput, &systemPrompt, &userPrompt)
+	if err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+	userPrompt, compression := client.compressPrompt(userPrompt)
 
 	// Fixed retry flow
 	var lastErr error
@@ -161,35 +251,53 @@ func (client *Client) CallWithMessages(systemPrompt, userPrompt string) (string,
 		}
 
 		// Call the fixed single-call flow
-		result, err := client.hooks.call(systemPrompt, userPrompt)
+		response, err := client.hooks.call(ctx, systemPrompt, userPrompt)
 		if err == nil {
 			if attempt > 1 {
 				client.logger.Infof("✓ AI API retry succeeded")
 			}
-			return result, nil
+			response.Compression = compression
+			return client.finishResponse(ctx, response, start, vault, violations, shadowMessages(systemPrompt, userPrompt))
 		}
 
 		lastErr = err
 		// Check if error is retryable via hooks (supports custom retry strategy in subclass)
-		if !client.hooks.isRetryableError(err) {
-			return "", err
+		if ctx.Err() != nil || !client.hooks.isRetryableError(err) {
+			return nil, withRequestID(vault.restorePartial(err), requestID)
 		}
 
 		// Wait before retry
 		if attempt < maxRetries {
-			waitTime := client.config.RetryWaitBase * time.Duration(attempt)
+			waitTime := client.retryWait(attempt, err)
 			client.logger.Infof("⏳ Waiting %v before retry...", waitTime)
-			time.Sleep(waitTime)
+			client.fireRetry(ctx, client.Model, attempt, maxRetries, waitTime, err)
+			select {
+			case <-time.After(waitTime):
+			case <-ctx.Done():
+				return nil, withRequestID(ctx.Err(), requestID)
+			}
 		}
 	}
 
-	return "", fmt.Errorf("still failed after %d retries: %w", maxRetries, lastErr)
+	return nil, withRequestID(vault.restorePartial(fmt.Errorf("still failed after %d retries: %w", maxRetries, lastErr)), requestID)
 }
 
 func (client *Client) setAuthHeader(reqHeader http.Header) {
+	// Zero-auth servers get no header rather than an empty Bearer token
+	if client.APIKey == "" {
+		return
+	}
 	reqHeader.Set("Authorization", fmt.Sprintf("Bearer %s", client.APIKey))
 }
 
+// checkAPIKey fails calls made before an API key is set (unless WithZeroAuth)
+func (client *Client) checkAPIKey() error {
+	if client.APIKey == "" && !client.config.ZeroAuth {
+		return fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	}
+	return nil
+}
+
 func (client *Client) buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any {
 	// Build messages array
 	messages := []map[string]string{}
@@ -219,16 +327,44 @@ func (client *Client) buildMCPRequestBody(systemPrompt, userPrompt string) map[s
 	} else {
 		requestBody["max_tokens"] = client.MaxTokens
 	}
+	if client.config.ResponseFormat != nil {
+		requestBody["response_format"] = openAIResponseFormat(client.config.ResponseFormat)
+	}
+	client.applyPromptCaching(requestBody, nil)
 	return requestBody
 }
 
+// messageTurn role and text of a chat message
+type messageTurn struct{ role, content string }
+
+// messageTurns reads role and content of request body messages (non-text content is printed as is)
+func messageTurns(messages any) []messageTurn {
+	var turns []messageTurn
+	switch messages := messages.(type) {
+	case []map[string]string:
+		for _, msg := range messages {
+			turns = append(turns, messageTurn{msg["role"], msg["content"]})
+		}
+	case []map[string]any:
+		for _, msg := range messages {
+			role, _ := msg["role"].(string)
+			content, ok := msg["content"].(string)
+			if !ok {
+				content = fmt.Sprint(msg["content"])
+			}
+			turns = append(turns, messageTurn{role, content})
+		}
+	}
+	return turns
+}
+
 // can be used to marshal the request body and can be overridden
 func (client *Client) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
-	jsonData, err := json.Marshal(requestBody)
+	data, err := client.codec().Marshal(requestBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize request: %w", err)
 	}
-	return jsonData, nil
+	return data, nil
 }
 
 func (client *Client) parseMCPResponse(body []byte) (string, error) {
@@ -250,18 +386,15 @@ func (client *Client) parseMCPResponse(body []byte) (string, error) {
 	}
 
 	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("API returned empty response")
+		return client.parseAlternativeShapes(body)
 	}
 
 	// Report token usage if callback is set
 	if TokenUsageCallback != nil && result.Usage.TotalTokens > 0 {
-		TokenUsageCallback(TokenUsage{
-			Provider:         client.Provider,
-			Model:            client.Model,
-			PromptTokens:     result.Usage.PromptTokens,
-			CompletionTokens: result.Usage.CompletionTokens,
-			TotalTokens:      result.Usage.TotalTokens,
-		})
+		usage := parseUsage(body)
+		usage.Provider = client.Provider
+		usage.Model = client.Model
+		TokenUsageCallback(usage)
 	}
 
 	return result.Choices[0].Message.Content, nil
@@ -271,26 +404,31 @@ func (client *Client) buildUrl() string {
 	if client.UseFullURL {
 		return client.BaseURL
 	}
-	return fmt.Sprintf("%s/chat/completions", client.BaseURL)
+	return client.endpointURL(EndpointChat)
 }
 
 func (client *Client) buildRequest(url string, jsonData []byte) (*http.Request, error) {
+	if client.baseURLErr != nil {
+		return nil, client.baseURLErr
+	}
+
 	// Create HTTP request
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("fail to build request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", client.codec().ContentType())
 
 	// Set auth header via hooks (supports overriding in subclass)
 	client.hooks.setAuthHeader(req.Header)
+	client.setClientHeaders(req.Header)
 
 	return req, nil
 }
 
 // call single AI API call (fixed flow, cannot be overridden)
-func (client *Client) call(systemPrompt, userPrompt string) (string, error) {
+func (client *Client) call(ctx context.Context, systemPrompt, userPrompt string) (*Response, error) {
 	// Print current AI configuration
 	client.logger.Infof("📡 [%s] Request AI Server: BaseURL: %s", client.String(), client.BaseURL)
 	client.logger.Debugf("[%s] UseFullURL: %v", client.String(), client.UseFullURL)
@@ -301,50 +439,172 @@ func (client *Client) call(systemPrompt, userPrompt string) (string, error) {
 	// Step 1: Build request body (via hooks for dynamic dispatch)
 	requestBody := client.hooks.buildMCPRequestBody(systemPrompt, userPrompt)
 
+	// Steps 2-11: Send request and parse response
+	return client.send(ctx, requestBody)
+}
+
+// send sends request body and parses response (fixed flow shared by all call paths)
+func (client *Client) send(ctx context.Context, requestBody map[string]any) (response *Response, err error) {
+	if err := client.checkBudgets(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := client.gateCapabilities(ctx, requestBody); err != nil {
+		return nil, err
+	}
+
+	var (
+		url        string
+		body       []byte
+		statusCode int
+		shared     bool
+	)
+	attempt := client.webhookAttempt(ctx, bodyModel(requestBody, client.Model))
+	tracker := newLatencyTracker()
+	defer func() {
+		var result, reasoning string
+		if response != nil {
+			result, reasoning = response.Text, response.Reasoning
+		}
+		client.audit(ctx, url, requestBody, parseUsage(body), statusCode, result, reasoning, time.Since(tracker.start), err)
+		// A coalesced call is charged to the caller that made the upstream request only
+		var usage TokenUsage
+		if response != nil && !shared {
+			usage = response.Usage
+		}
+		attempt(usage, err)
+		client.fireResult(ctx, bodyModel(requestBody, client.Model), response, time.Since(tracker.start), err)
+		client.spendBudgets(ctx, bodyModel(requestBody, client.Model), usage, err)
+	}()
+
+	client.decorateBody(ctx, requestBody)
+	client.fireRequest(ctx, requestBody)
+
 	// Step 2: Serialize request body (via hooks for dynamic dispatch)
 	jsonData, err := client.hooks.marshalRequestBody(requestBody)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Step 3: Build URL (via hooks for dynamic dispatch)
-	url := client.hooks.buildUrl()
-	client.logger.Infof("📡 [MCP %s] Request URL: %s", client.String(), url)
+	url = withExtraQuery(client.hooks.buildUrl(), client.config.ExtraQuery)
+	if tags := TagsFromContext(ctx); len(tags) > 0 {
+		client.logger.Infof("📡 [MCP %s] Request URL: %s (request_id: %s, tags: %s)", client.String(), url, RequestIDFromContext(ctx), formatTags(tags))
+	} else {
+		client.logger.Infof("📡 [MCP %s] Request URL: %s (request_id: %s)", client.String(), url, RequestIDFromContext(ctx))
+	}
+
+	// Steps 4-8: Perform request, sharing it with concurrent identical calls if coalescing is enabled
+	key := coalesceKey(url, client.APIKey, jsonData)
+	var out coalescedResponse
+	out, shared = client.config.Coalescer.do(ctx, key, func() coalescedResponse {
+		return client.exchange(ctx, tracker, url, jsonData)
+	})
+	if shared {
+		client.logger.Debugf("[%s] Coalesced with identical in-flight request", client.String())
+	} else {
+		// Only the caller that performed the request reports its usage
+		body = out.body
+	}
+	statusCode = out.statusCode
+	if out.err != nil {
+		return nil, out.err
+	}
 
+	// Step 9: Build response with metadata (reasoning separated from answer text)
+	response = client.buildResponse(out.body, out.result)
+	response.StatusCode = out.statusCode
+	response.Header = redactHeaders(out.header)
+	client.observeFingerprint(response)
+	if err := client.checkResponse(response, out.body); err != nil {
+		return nil, err
+	}
+
+	// Continuation segments are stitched and validated by the request that asked for them
+	if isContinuation(ctx) {
+		return response, nil
+	}
+
+	// Step 10: Handle output truncated by the token limit (per truncation policy)
+	if response, err = client.handleTruncation(ctx, requestBody, response); err != nil {
+		return nil, err
+	}
+
+	// Step 11: Validate structured output (if JSON response was requested)
+	if format := client.responseFormatFor(ctx); format != nil {
+		if err := client.migrateJSONResponse(response); err != nil {
+			return nil, err
+		}
+		if err := validateJSONResponse(response.Text, format); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// exchange performs one HTTP request and parses the response
+func (client *Client) exchange(ctx context.Context, tracker *latencyTracker, url string, jsonData []byte) (out coalescedResponse) {
 	// Step 4: Create HTTP request (fixed logic)
 	req, err := client.hooks.buildRequest(url, jsonData)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		out.err = fmt.Errorf("failed to create request: %w", err)
+		return out
 	}
 
 	// Step 5: Send HTTP request (fixed logic)
+	if err := client.config.RateLimiter.Wait(ctx); err != nil {
+		out.err = fmt.Errorf("rate limiter: %w", err)
+		return out
+	}
+	client.setRequestIDHeaders(ctx, req)
+	client.setTenantHeader(ctx, req)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req = tracker.attach(req.WithContext(ctx))
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		out.err = fmt.Errorf("failed to send request: %w", err)
+		return out
 	}
+	resp.Body = withIdleTimeout(ctx, resp.Body, client.config.IdleTimeout, cancel)
 	defer resp.Body.Close()
+	out.statusCode = resp.StatusCode
+	out.header = resp.Header
+	if resp.StatusCode == http.StatusOK {
+		signalFirstByte(ctx)
+	}
 
 	// Step 6: Read response body (fixed logic)
-	body, err := io.ReadAll(resp.Body)
+	out.body, err = io.ReadAll(limitBody(resp.Body, client.config.MaxResponseBytes))
+	client.observeLatency(tracker.finish())
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		out.err = client.salvagePartial(fmt.Errorf("failed to read response: %w", err), client.partialBodyResponse(out.body))
+		return out
 	}
 
 	// Step 7: Check HTTP status code (fixed logic)
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
+		out.err = client.newAPIError(resp.StatusCode, out.body)
+		return out
 	}
 
 	// Step 8: Parse response (via hooks for dynamic dispatch)
-	result, err := client.hooks.parseMCPResponse(body)
+	out.result, err = client.hooks.parseMCPResponse(out.body)
 	if err != nil {
-		return "", fmt.Errorf("fail to parse AI server response: %w", err)
+		out.err = &ResponseParseError{Reason: "fail to parse AI server response", Body: out.body, Err: err}
 	}
+	return out
+}
 
-	return result, nil
+// base returns the underlying Client (promoted to provider clients embedding it)
+func (client *Client) base() *Client {
+	return client
 }
 
 func (client *Client) String() string {
+	if len(client.config.Tags) > 0 {
+		return fmt.Sprintf("[Provider: %s, Model: %s, %s]",
+			client.Provider, client.Model, formatTags(client.config.Tags))
+	}
 	return fmt.Sprintf("[Provider: %s, Model: %s]",
 		client.Provider, client.Model)
 }
@@ -371,7 +631,7 @@ func (client *Client) isRetryableError(err error) bool {
 // - Multi-turn conversation history
 // - Fine-grained parameter control (temperature, top_p, penalties, etc.)
 // - Function Calling / Tools
-// - Streaming response (future support)
+// - Streaming response (see CallStream)
 //
 // Usage example:
 //   request := NewRequestBuilder().
@@ -381,15 +641,73 @@ func (client *Client) isRetryableError(err error) bool {
 //       Build()
 //   result, err := client.CallWithRequest(request)
 func (client *Client) CallWithRequest(req *Request) (string, error) {
-	if client.APIKey == "" {
-		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	return client.CallWithRequestContext(context.Background(), req)
+}
+
+// CallWithRequestContext calls AI API using Request object, honoring ctx cancellation
+//
+// Cancelling ctx aborts the in-flight HTTP request and any pending retry wait.
+func (client *Client) CallWithRequestContext(ctx context.Context, req *Request) (string, error) {
+	response, err := client.Call(ctx, req)
+	if err != nil {
+		return "", err
 	}
+	return response.Text, nil
+}
+
+// Call calls AI API using Request object and returns the response with its metadata
+//
+// Same retry flow as CallWithRequestContext, but keeps what the string-returning
+// methods discard (e.g. the model's reasoning).
+//
+// Usage example:
+//   response, err := client.Call(ctx, request)
+//   if err == nil && response.Reasoning != "" {
+//       log.Printf("model rationale: %s", response.Reasoning)
+//   }
+func (client *Client) Call(ctx context.Context, req *Request) (response *Response, err error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+	if client.handlingRefusals(ctx) {
+		return client.handleRefusals(ctx, lastUserPrompt(req), func(ctx context.Context, userPrompt string) (*Response, error) {
+			return client.Call(ctx, withUserPrompt(req, userPrompt))
+		}, delegateRequest(req))
+	}
+	if client.enforcingLanguage(ctx) {
+		return client.enforceLanguage(ctx, lastUserPrompt(req), func(ctx context.Context, userPrompt string) (*Response, error) {
+			return client.Call(ctx, withUserPrompt(req, userPrompt))
+		})
+	}
+	if client.hedging(ctx) {
+		return client.hedged(ctx, func(ctx context.Context) (*Response, error) {
+			return client.Call(ctx, cloneRequest(req))
+		}, delegateRequest(req))
+	}
+	if err := client.lifecycle.begin(); err != nil {
+		return nil, err
+	}
+	defer client.lifecycle.end()
 
 	// If Model is not set in Request, use Client's Model
 	if req.Model == "" {
 		req.Model = client.Model
 	}
 
+	// One request ID for all attempts of this call (caller-supplied if present)
+	ctx, requestID := client.ensureRequestID(ctx)
+	ctx, finishWebhook := client.startWebhookCall(ctx)
+	defer func() { finishWebhook(err) }()
+	start := time.Now()
+
+	// Sensitive data is replaced by placeholders, then input guardrails may block or redact
+	vault := client.newPIIVault()
+	req, violations, err := client.guardRequest(ctx, vault.redactRequest(req))
+	if err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+	req, compression := client.compressRequest(req)
+
 	// Fixed retry flow
 	var lastErr error
 	maxRetries := client.config.MaxRetries
@@ -400,92 +718,70 @@ func (client *Client) CallWithRequest(req *Request) (string, error) {
 		}
 
 		// Call single request
-		result, err := client.callWithRequest(req)
+		response, err := client.callWithRequest(ctx, req)
 		if err == nil {
 			if attempt > 1 {
 				client.logger.Infof("✓ AI API retry succeeded")
 			}
-			return result, nil
+			response.Compression = compression
+			return client.finishResponse(ctx, response, start, vault, violations, shadowCall(req))
 		}
 
 		lastErr = err
-		// Check if error is retryable
-		if !client.hooks.isRetryableError(err) {
-			return "", err
+		// Check if error is retryable (never retry once the caller gave up)
+		if ctx.Err() != nil || !client.hooks.isRetryableError(err) {
+			return nil, withRequestID(vault.restorePartial(err), requestID)
 		}
 
 		// Wait before retry
 		if attempt < maxRetries {
-			waitTime := client.config.RetryWaitBase * time.Duration(attempt)
+			waitTime := client.retryWait(attempt, err)
 			client.logger.Infof("⏳ Waiting %v before retry...", waitTime)
-			time.Sleep(waitTime)
+			client.fireRetry(ctx, req.Model, attempt, maxRetries, waitTime, err)
+			select {
+			case <-time.After(waitTime):
+			case <-ctx.Done():
+				return nil, withRequestID(ctx.Err(), requestID)
+			}
 		}
 	}
 
-	return "", fmt.Errorf("still failed after %d retries: %w", maxRetries, lastErr)
+	return nil, withRequestID(vault.restorePartial(fmt.Errorf("still failed after %d retries: %w", maxRetries, lastErr)), requestID)
 }
 
 // callWithRequest single AI API call (using Request object)
-func (client *Client) callWithRequest(req *Request) (string, error) {
+func (client *Client) callWithRequest(ctx context.Context, req *Request) (*Response, error) {
 	// Print current AI configuration
 	client.logger.Infof("📡 [%s] Request AI Server with Builder: BaseURL: %s", client.String(), client.BaseURL)
 	client.logger.Debugf("[%s] Messages count: %d", client.String(), len(req.Messages))
 
 	// Build request body (from Request object)
 	requestBody := client.buildRequestBodyFromRequest(req)
-
-	// Serialize request body
-	jsonData, err := client.hooks.marshalRequestBody(requestBody)
-	if err != nil {
-		return "", err
-	}
-
-	// Build URL
-	url := client.hooks.buildUrl()
-	client.logger.Infof("📡 [MCP %s] Request URL: %s", client.String(), url)
-
-	// Create HTTP request
-	httpReq, err := client.hooks.buildRequest(url, jsonData)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Send HTTP request
-	resp, err := client.httpClient.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Check HTTP status code
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
-	result, err := client.hooks.parseMCPResponse(body)
-	if err != nil {
-		return "", fmt.Errorf("fail to parse AI server response: %w", err)
+	if req.ResponseFormat != nil {
+		ctx = context.WithValue(ctx, responseFormatKey{}, req.ResponseFormat)
 	}
 
-	return result, nil
+	// Send request and parse response
+	return client.send(ctx, requestBody)
 }
 
 // buildRequestBodyFromRequest builds request body from Request object
 func (client *Client) buildRequestBodyFromRequest(req *Request) map[string]any {
-	// Convert Message to API format
-	messages := make([]map[string]string, 0, len(req.Messages))
-	for _, msg := range req.Messages {
-		messages = append(messages, map[string]string{
+	// Convert Message to API format (attached files become content parts or inlined text)
+	reqMessages := client.inlineFiles(req.Messages)
+	messages := make([]map[string]any, 0, len(reqMessages))
+	for _, msg := range reqMessages {
+		message := map[string]any{
 			"role":    msg.Role,
-			"content": msg.Content,
-		})
+			"content": client.messageContent(msg),
+		}
+		if len(msg.ToolCalls) > 0 {
+			message["tool_calls"] = openAIToolCalls(msg.ToolCalls)
+		}
+		if msg.ToolCallID != "" {
+			message["tool_call_id"] = msg.ToolCallID
+		}
+		messages = append(messages, message)
 	}
 
 	// Build basic request body
@@ -542,5 +838,27 @@ func (client *Client) buildRequestBodyFromRequest(req *Request) map[string]any {
 		requestBody["stream"] = true
 	}
 
+	client.applyReasoningParams(requestBody, req)
+	client.applyLogprobParams(requestBody, req)
+	client.applyPromptCaching(requestBody, reqMessages)
+
+	format := req.ResponseFormat
+	if format == nil {
+		format = client.config.ResponseFormat
+	}
+	if format != nil {
+		switch client.Provider {
+		case ProviderClaude:
+			anthropicJSONTool(requestBody, format)
+		case ProviderCohere:
+			requestBody["response_format"] = cohereResponseFormat(format)
+		default:
+			requestBody["response_format"] = openAIResponseFormat(format)
+		}
+	}
+	if client.Provider == ProviderCohere {
+		adaptCohereBody(requestBody)
+	}
+
 	return requestBody
 }
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Profile configuration of one named client in a ClientSet
+type Profile struct {
+	Provider  string         // Provider name ("" = deepseek)
+	Options   []ClientOption // Model, temperature, rate limiter, ... (applied after the shared options)
+	BudgetUSD float64        // Spending limit of the profile (0 = unlimited)
+}
+
+// ProfileMetrics usage of one profile
+type ProfileMetrics struct {
+	Calls            int
+	Errors           int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64 // Estimated USD
+	BudgetRemaining  float64 // USD (0 when unlimited)
+}
+
+// ClientSet named clients with isolated budgets and metrics
+//
+// Every profile gets its own client, so model, parameters, rate limits and budget
+// are independent, while the shared options (audit sink, coalescer, ...) and the
+// default HTTP transport are common to all of them.
+type ClientSet struct {
+	shared []ClientOption
+
+	mu       sync.RWMutex
+	profiles map[string]*profileEntry
+}
+
+// profileEntry client of a profile with its accounting
+type profileEntry struct {
+	client AIClient
+	budget *Budget
+	meter  *usageMeter
+}
+
+// NewClientSet creates empty set whose profiles all get shared options
+//
+// Usage example:
+//   set := mcp.NewClientSet(mcp.WithAuditSink(sink))
+//   set.Add("scalper", mcp.Profile{Provider: "deepseek", BudgetUSD: 2, Options: []mcp.ClientOption{mcp.WithTemperature(0.2)}})
+//   set.Add("news-analyst", mcp.Profile{Provider: "qwen", Options: []mcp.ClientOption{mcp.WithModel("qwen-max")}})
+//   scalper, _ := set.Get("scalper")
+func NewClientSet(shared ...ClientOption) *ClientSet {
+	return &ClientSet{shared: shared, profiles: make(map[string]*profileEntry)}
+}
+
+// Add creates client of profile name (an existing profile of that name is replaced)
+func (s *ClientSet) Add(name string, profile Profile) (AIClient, error) {
+	provider := profile.Provider
+	if provider == "" {
+		provider = ProviderDeepSeek
+	}
+	entry := &profileEntry{meter: &usageMeter{}}
+	if profile.BudgetUSD > 0 {
+		entry.budget = NewBudget(profile.BudgetUSD)
+	}
+
+	opts := append(append([]ClientOption(nil), s.shared...), profile.Options...)
+	opts = append(opts, WithBudget(entry.budget), func(c *Config) {
+		// Meter every interaction, still forwarding records to a shared sink
+		entry.meter.next = c.AuditSink
+		c.AuditSink = entry.meter
+	})
+	client, err := NewProviderClient(provider, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	entry.client = client
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[name] = entry
+	return client, nil
+}
+
+// Get returns client of profile name
+func (s *ClientSet) Get(name string) (AIClient, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.profiles[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.client, true
+}
+
+// Profiles returns profile names, sorted
+func (s *ClientSet) Profiles() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Metrics returns usage of every profile
+func (s *ClientSet) Metrics() map[string]ProfileMetrics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	metrics := make(map[string]ProfileMetrics, len(s.profiles))
+	for name, entry := range s.profiles {
+		m := entry.meter.snapshot()
+		m.BudgetRemaining = entry.budget.Remaining()
+		metrics[name] = m
+	}
+	return metrics
+}
+
+// Close closes the clients of all profiles
+func (s *ClientSet) Close(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var errs []error
+	for name, entry := range s.profiles {
+		if client, ok := BaseClient(entry.client); ok {
+			if err := client.Close(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("profile %s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// usageMeter AuditSink aggregating interactions of one profile, forwarding to next
+type usageMeter struct {
+	next AuditSink
+
+	mu      sync.Mutex
+	metrics ProfileMetrics
+}
+
+func (m *usageMeter) Write(record AuditRecord) error {
+	m.mu.Lock()
+	m.metrics.Calls++
+	if record.Outcome == AuditOutcomeError {
+		m.metrics.Errors++
+	}
+	m.metrics.PromptTokens += record.Usage.PromptTokens
+	m.metrics.CompletionTokens += record.Usage.CompletionTokens
+	m.metrics.Cost += record.Cost
+	m.mu.Unlock()
+
+	if m.next != nil {
+		return m.next.Write(record)
+	}
+	return nil
+}
+
+// Flush flushes the forwarded sink if it supports flushing
+func (m *usageMeter) Flush() error {
+	if flusher, ok := m.next.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close does nothing: the forwarded sink is shared and owned by the caller
+func (m *usageMeter) Close() error {
+	return nil
+}
+
+func (m *usageMeter) snapshot() ProfileMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.metrics
+}
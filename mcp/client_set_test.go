@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const clientSetResponse = `{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1000,"completion_tokens":500,"total_tokens":1500}}`
+
+func TestClientSet_IsolatedMetricsAndSharedSink(t *testing.T) {
+	RegisterModelPrice("set-test-model", ModelPrice{InputPerMillion: 1000, OutputPerMillion: 1000})
+	shared := &memoryAuditSink{}
+	var bodies []map[string]any
+	set := NewClientSet(WithAuditSink(shared), WithHTTPClient(captureBodyClient(clientSetResponse, &bodies)))
+
+	for _, name := range []string{"scalper", "swing"} {
+		if _, err := set.Add(name, Profile{
+			Provider: ProviderCustom,
+			Options:  []ClientOption{WithBaseURL("https://api.example.com/v1"), WithAPIKey("k"), WithModel("set-test-model")},
+		}); err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+	}
+	scalper, _ := set.Get("scalper")
+	for i := 0; i < 2; i++ {
+		if _, err := scalper.CallWithMessages("sys", "buy?"); err != nil {
+			t.Fatalf("call: %v", err)
+		}
+	}
+
+	metrics := set.Metrics()
+	if metrics["scalper"].Calls != 2 || metrics["scalper"].PromptTokens != 2000 || metrics["scalper"].Cost <= 0 {
+		t.Errorf("unexpected scalper metrics %+v", metrics["scalper"])
+	}
+	if metrics["swing"].Calls != 0 {
+		t.Errorf("swing metrics should be untouched, got %+v", metrics["swing"])
+	}
+	if len(shared.Records()) != 2 {
+		t.Errorf("shared sink should receive every record, got %d", len(shared.Records()))
+	}
+	if got := set.Profiles(); !reflect.DeepEqual(got, []string{"scalper", "swing"}) {
+		t.Errorf("unexpected profiles %v", got)
+	}
+	if err := set.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestClientSet_BudgetExceeded(t *testing.T) {
+	// 1500 tokens at $1000/M = $1.50 per call
+	RegisterModelPrice("set-test-model", ModelPrice{InputPerMillion: 1000, OutputPerMillion: 1000})
+	var bodies []map[string]any
+	set := NewClientSet(WithHTTPClient(captureBodyClient(clientSetResponse, &bodies)))
+	client, err := set.Add("capped", Profile{
+		Provider:  ProviderCustom,
+		BudgetUSD: 2,
+		Options:   []ClientOption{WithBaseURL("https://api.example.com/v1"), WithAPIKey("k"), WithModel("set-test-model")},
+	})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.CallWithMessages("sys", "hi"); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if _, err := client.CallWithMessages("sys", "hi"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Errorf("over-budget call must not be sent, got %d requests", len(bodies))
+	}
+	if remaining := set.Metrics()["capped"].BudgetRemaining; remaining != 0 {
+		t.Errorf("expected no budget left, got %g", remaining)
+	}
+}
+
+func TestClientSet_UnknownProvider(t *testing.T) {
+	if _, err := NewClientSet().Add("x", Profile{Provider: "nope"}); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"maps"
+	"slices"
+)
+
+// With returns a copy of the client with opts applied on top of its configuration
+//
+// The copy shares the HTTP transport (warm connections), rate limiter, recorder and other
+// attached components, and its provider type (With on a ClaudeClient returns a
+// ClaudeClient). Use it instead of mutating a shared client with SetAPIKey-style setters.
+// Transport-level options (TLS, compression, signing, recording) are only applied when
+// opts also include WithHTTPClient; otherwise create a new client for them.
+//
+// Usage example:
+//   reviewer := client.With(mcp.WithModel("deepseek-reasoner"), mcp.WithTemperature(0))
+func (client *Client) With(opts ...ClientOption) AIClient {
+	before := *client.config
+	cfg := before
+	cfg.ExtraBody = maps.Clone(before.ExtraBody)
+	cfg.ExtraQuery = maps.Clone(before.ExtraQuery)
+	cfg.PathTemplates = maps.Clone(before.PathTemplates)
+	cfg.AuditTags = maps.Clone(before.AuditTags)
+	cfg.Tags = maps.Clone(before.Tags)
+	cfg.Guardrails = slices.Clip(before.Guardrails)
+	cfg.PIIPolicies = slices.Clip(before.PIIPolicies)
+	cfg.Hooks = slices.Clip(before.Hooks)
+	httpClient := *client.httpClient // Copy so WithTimeout doesn't change the original
+	cfg.HTTPClient = &httpClient
+	cfg.defaultHTTPClient = nil
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	filterResidencyTargets(&cfg)
+
+	clone := &Client{
+		Provider:   client.Provider,
+		APIKey:     client.APIKey,
+		BaseURL:    client.BaseURL,
+		Model:      client.Model,
+		MaxTokens:  client.MaxTokens,
+		UseFullURL: client.UseFullURL,
+		httpClient: cfg.HTTPClient,
+		logger:     cfg.Logger,
+		config:     &cfg,
+		shadow:     client.shadow,
+		hedge:      client.hedge,
+		webhook:    client.webhook,
+		baseURLErr: client.baseURLErr,
+	}
+
+	// Client fields only follow the config where opts changed it
+	if cfg.Provider != before.Provider {
+		clone.Provider = cfg.Provider
+	}
+	if cfg.APIKey != before.APIKey {
+		clone.APIKey = cfg.APIKey
+	}
+	if cfg.BaseURL != before.BaseURL || cfg.baseURLErr != before.baseURLErr {
+		clone.BaseURL, clone.baseURLErr = cfg.BaseURL, cfg.baseURLErr
+	}
+	if cfg.Model != before.Model {
+		clone.Model = cfg.Model
+	}
+	if cfg.MaxTokens != before.MaxTokens {
+		clone.MaxTokens = cfg.MaxTokens
+	}
+	if cfg.UseFullURL != before.UseFullURL {
+		clone.UseFullURL = cfg.UseFullURL
+	}
+	if cfg.HTTPClient != &httpClient {
+		clone.httpClient, clone.ownedTransport = newHTTPClient(&cfg)
+	} else if cfg.totalTimeout && cfg.Timeout != before.Timeout {
+		httpClient.Timeout = cfg.Timeout // The copy is the clone's own
+	}
+	if cfg.Shadow != before.Shadow || cfg.ShadowSampleRate != before.ShadowSampleRate {
+		clone.shadow = newShadowState(&cfg)
+	}
+	if cfg.HedgeDelay != before.HedgeDelay || cfg.HedgeMaxExtra != before.HedgeMaxExtra || len(cfg.HedgeTargets) != len(before.HedgeTargets) {
+		clone.hedge = newHedgeState(&cfg)
+	}
+	if webhookChanged(&cfg, &before) {
+		clone.webhook = newWebhookNotifier(&cfg)
+	}
+	if cfg.ModerateOutputs && !before.ModerateOutputs {
+		cfg.Guardrails = append(cfg.Guardrails, GuardrailRule{
+			Guardrail: &outputModerationGuardrail{client: clone},
+			Stage:     GuardOutput,
+		})
+	}
+	clone.warnNondeterministic()
+	clone.resolveResidentEndpoint()
+
+	// Keep the provider type, so its hooks still apply
+	var wrapped AIClient
+	switch client.hooks.(type) {
+	case *DeepSeekClient:
+		wrapped = &DeepSeekClient{Client: clone}
+	case *QwenClient:
+		wrapped = &QwenClient{Client: clone}
+	case *OpenAIClient:
+		wrapped = &OpenAIClient{Client: clone}
+	case *ClaudeClient:
+		wrapped = &ClaudeClient{Client: clone}
+	case *GeminiClient:
+		wrapped = &GeminiClient{Client: clone}
+	case *GrokClient:
+		wrapped = &GrokClient{Client: clone}
+	case *KimiClient:
+		wrapped = &KimiClient{Client: clone}
+	case *CohereClient:
+		wrapped = &CohereClient{Client: clone}
+	case *VoyageClient:
+		wrapped = &VoyageClient{Client: clone}
+	case *HuggingFaceClient:
+		wrapped = &HuggingFaceClient{Client: clone}
+	case *CloudflareClient:
+		wrapped = &CloudflareClient{Client: clone}
+	case *VertexClient:
+		wrapped = &VertexClient{Client: clone}
+	case *DoubaoClient:
+		wrapped = &DoubaoClient{Client: clone}
+	case *ErnieClient:
+		wrapped = &ErnieClient{Client: clone}
+	case *HunyuanClient:
+		wrapped = &HunyuanClient{Client: clone}
+	case *SiliconFlowClient:
+		wrapped = &SiliconFlowClient{Client: clone}
+	case *NovitaClient:
+		wrapped = &NovitaClient{Client: clone}
+	case *LMStudioClient:
+		wrapped = &LMStudioClient{Client: clone}
+	case *TextGenWebUIClient:
+		wrapped = &TextGenWebUIClient{Client: clone}
+	default:
+		clone.hooks = clone
+		return clone
+	}
+	clone.hooks = wrapped.(clientHooks)
+	return wrapped
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientWith_Overrides(t *testing.T) {
+	var bodies []map[string]any
+	httpClient := captureBodyClient(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`, &bodies)
+	original := NewQwenClientWithOptions(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(httpClient),
+		WithTimeout(30*time.Second)).(*QwenClient)
+
+	derived := original.With(WithModel("qwen-max"), WithTemperature(0.1), WithTimeout(5*time.Second), WithChatPath("{base}/proxy/chat"))
+	qwen, ok := derived.(*QwenClient)
+	if !ok {
+		t.Fatalf("expected *QwenClient, got %T", derived)
+	}
+	if qwen.Model != "qwen-max" || qwen.APIKey != "sk-test-key" || qwen.config.Temperature != 0.1 {
+		t.Errorf("overrides not applied: model %q, temperature %v", qwen.Model, qwen.config.Temperature)
+	}
+	if qwen.httpClient.Transport != original.httpClient.Transport || qwen.httpClient.Timeout != 5*time.Second {
+		t.Error("copy should share the transport with its own timeout")
+	}
+	if original.Model == "qwen-max" || original.httpClient.Timeout != 30*time.Second || original.config.PathTemplates != nil {
+		t.Error("original client must not change")
+	}
+
+	if _, err := derived.CallWithMessages("system", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := original.CallWithMessages("system", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if bodies[0]["model"] != "qwen-max" || bodies[1]["model"] == "qwen-max" {
+		t.Errorf("expected models qwen-max then default, got %v and %v", bodies[0]["model"], bodies[1]["model"])
+	}
+	if url := httpClient.Transport.(*MockHTTPClient).GetRequests()[0].URL.String(); url != DefaultQwenBaseURL+"/proxy/chat" {
+		t.Errorf("expected overridden chat path, got %q", url)
+	}
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cloudSignerAPIKey placeholder API key of clients authenticating with a CloudSigner
+const cloudSignerAPIKey = "cloud-signer"
+
+// CloudSigner signs provider requests with cloud access key credentials (AK/SK)
+//
+// Unlike RequestSigner, which signs for an internal gateway, a CloudSigner produces
+// the provider's own Authorization header, so it sees the full request and the
+// exact body sent.
+type CloudSigner interface {
+	SignRequest(req *http.Request, body []byte, now time.Time) error
+}
+
+// WithCloudSigner authenticates every request with access key signatures instead of the API key
+//
+// Usage example:
+//   client := mcp.NewDoubaoClientWithOptions(
+//       mcp.WithCloudSigner(&mcp.VolcengineSigner{AccessKey: ak, SecretKey: sk, Region: "cn-beijing", Service: "ark"}),
+//   )
+func WithCloudSigner(signer CloudSigner) ClientOption {
+	return func(c *Config) {
+		c.CloudSigner = signer
+		if c.APIKey == "" {
+			c.APIKey = cloudSignerAPIKey
+		}
+	}
+}
+
+// cloudSigningTransport http.RoundTripper signing every request with a CloudSigner
+type cloudSigningTransport struct {
+	next   http.RoundTripper
+	signer CloudSigner
+	now    func() time.Time
+}
+
+func (t *cloudSigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	if err := t.signer.SignRequest(out, body, t.now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+	return t.next.RoundTrip(out)
+}
+
+// VolcengineSigner Volcengine (ByteDance cloud) V4 request signature, HMAC-SHA256
+type VolcengineSigner struct {
+	AccessKey string
+	SecretKey string
+	Region    string // e.g. "cn-beijing"
+	Service   string // e.g. "ark"
+}
+
+func (s *VolcengineSigner) SignRequest(req *http.Request, body []byte, now time.Time) error {
+	now = now.UTC()
+	xDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Date", xDate)
+	req.Header.Set("X-Content-Sha256", payloadHash)
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	headers := map[string]string{
+		"content-type":     req.Header.Get("Content-Type"),
+		"host":             req.URL.Host,
+		"x-content-sha256": payloadHash,
+		"x-date":           xDate,
+	}
+	canonicalHeaders, signedHeaders := canonicalHeaderBlock(headers)
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL.EscapedPath()),
+		canonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/" + s.Service + "/request"
+	stringToSign := "HMAC-SHA256\n" + xDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte(s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+	return nil
+}
+
+// TencentCloudSigner Tencent Cloud API 3.0 request signature (TC3-HMAC-SHA256)
+type TencentCloudSigner struct {
+	SecretID  string
+	SecretKey string
+	Service   string // e.g. "hunyuan"
+	Action    string // e.g. "ChatCompletions"
+	Version   string // API version, e.g. "2023-09-01"
+	Region    string // "" = not sent (region-less APIs)
+}
+
+func (s *TencentCloudSigner) SignRequest(req *http.Request, body []byte, now time.Time) error {
+	now = now.UTC()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	date := now.Format("2006-01-02")
+
+	req.Header.Set("X-TC-Action", s.Action)
+	req.Header.Set("X-TC-Version", s.Version)
+	req.Header.Set("X-TC-Timestamp", timestamp)
+	if s.Region != "" {
+		req.Header.Set("X-TC-Region", s.Region)
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	headers := map[string]string{
+		"content-type": strings.ToLower(req.Header.Get("Content-Type")),
+		"host":         req.URL.Host,
+		"x-tc-action":  strings.ToLower(s.Action),
+	}
+	canonicalHeaders, signedHeaders := canonicalHeaderBlock(headers)
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL.EscapedPath()),
+		canonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := date + "/" + s.Service + "/tc3_request"
+	stringToSign := "TC3-HMAC-SHA256\n" + timestamp + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("TC3"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("TC3-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.SecretID, scope, signedHeaders, signature))
+	return nil
+}
+
+// canonicalHeaderBlock returns "name:value\n" lines sorted by name and the signed header list
+func canonicalHeaderBlock(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return block.String(), strings.Join(names, ";")
+}
+
+func canonicalPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// canonicalQuery returns the query string sorted by key, values escaped
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	if len(query) == 0 {
+		return ""
+	}
+	// Encode sorts by key; signature schemes want %20 rather than +
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
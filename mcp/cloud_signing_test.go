@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test CloudSigner
+// ============================================================
+
+func signedRequest(t *testing.T, signer CloudSigner, body string) *http.Request {
+	t.Helper()
+	var sent *http.Request
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		sent = req
+		data, _ := io.ReadAll(req.Body)
+		if string(data) != body {
+			t.Errorf("body changed by signing: %q", data)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}
+	transport := &cloudSigningTransport{
+		next:   mockHTTP,
+		signer: signer,
+		now:    func() time.Time { return time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC) },
+	}
+	req, _ := http.NewRequest(http.MethodPost, "https://signed.example.com/api/v3/chat/completions", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	return sent
+}
+
+func TestVolcengineSigner(t *testing.T) {
+	signer := &VolcengineSigner{AccessKey: "AKLT-test", SecretKey: "secret", Region: "cn-beijing", Service: "ark"}
+	req := signedRequest(t, signer, `{"model":"doubao"}`)
+
+	if req.Header.Get("X-Date") != "20250301T083000Z" || req.Header.Get("X-Content-Sha256") != sha256Hex([]byte(`{"model":"doubao"}`)) {
+		t.Errorf("unexpected signing headers %v", req.Header)
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "HMAC-SHA256 Credential=AKLT-test/20250301/cn-beijing/ark/request, SignedHeaders=content-type;host;x-content-sha256;x-date, Signature=") {
+		t.Errorf("unexpected Authorization %q", auth)
+	}
+
+	// Signature covers the body
+	other := signedRequest(t, signer, `{"model":"other"}`)
+	if other.Header.Get("Authorization") == auth {
+		t.Error("different bodies must produce different signatures")
+	}
+}
+
+func TestTencentCloudSigner(t *testing.T) {
+	signer := &TencentCloudSigner{SecretID: "AKID-test", SecretKey: "secret", Service: "hunyuan", Action: "ChatCompletions", Version: "2023-09-01"}
+	req := signedRequest(t, signer, `{"Model":"hunyuan-lite"}`)
+
+	if req.Header.Get("X-TC-Action") != "ChatCompletions" || req.Header.Get("X-TC-Version") != "2023-09-01" ||
+		req.Header.Get("X-TC-Timestamp") != "1740817800" || req.Header.Get("X-TC-Region") != "" {
+		t.Errorf("unexpected signing headers %v", req.Header)
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "TC3-HMAC-SHA256 Credential=AKID-test/2025-03-01/hunyuan/tc3_request, SignedHeaders=content-type;host;x-tc-action, Signature=") {
+		t.Errorf("unexpected Authorization %q", auth)
+	}
+	if signature := auth[strings.LastIndex(auth, "=")+1:]; len(signature) != 64 {
+		t.Errorf("expected hex SHA-256 signature, got %q", signature)
+	}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"fmt"
+)
+
+const (
+	ProviderCloudflare          = "cloudflare"
+	DefaultCloudflareModel      = "@cf/meta/llama-3.3-70b-instruct-fp8-fast"
+	DefaultCloudflareEmbedModel = "@cf/baai/bge-base-en-v1.5"
+)
+
+type CloudflareClient struct {
+	*Client
+}
+
+// NewCloudflareClient creates Cloudflare Workers AI client (backward compatible)
+func NewCloudflareClient() AIClient {
+	return NewCloudflareClientWithOptions()
+}
+
+// NewCloudflareClientWithOptions creates Cloudflare Workers AI client (supports options pattern)
+//
+// Workers AI URLs are scoped to an account: it is taken from CLOUDFLARE_ACCOUNT_ID
+// unless WithCloudflareAccount is given, and the API token is sent as Bearer token.
+//
+// Usage example:
+//   client := mcp.NewCloudflareClientWithOptions(
+//       mcp.WithCloudflareAccount(accountID),
+//       mcp.WithAPIKey(apiToken),
+//   )
+func NewCloudflareClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Cloudflare preset options
+	cloudflareOpts := []ClientOption{
+		WithProvider(ProviderCloudflare),
+		WithModel(DefaultCloudflareModel),
+		WithCloudflareAccount(getEnvString("CLOUDFLARE_ACCOUNT_ID", "")),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(cloudflareOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+	if baseClient.BaseURL == cloudflareBaseURL("") {
+		baseClient.logger.Warnf("⚠️  [%s] Cloudflare account not set, use WithCloudflareAccount or CLOUDFLARE_ACCOUNT_ID", baseClient.String())
+	}
+
+	// 4. Create Cloudflare client
+	cloudflareClient := &CloudflareClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to CloudflareClient (implement dynamic dispatch)
+	baseClient.hooks = cloudflareClient
+
+	return cloudflareClient
+}
+
+// WithCloudflareAccount sets the Cloudflare account Workers AI requests are made for
+func WithCloudflareAccount(accountID string) ClientOption {
+	return WithBaseURL(cloudflareBaseURL(accountID))
+}
+
+// cloudflareBaseURL OpenAI-compatible Workers AI endpoint of an account
+func cloudflareBaseURL(accountID string) string {
+	return fmt.Sprintf("https://api.cloudflare.com/client/v4/accounts/%s/ai/v1", accountID)
+}
+
+func (c *CloudflareClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] Cloudflare API Token: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Cloudflare using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Cloudflare using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] Cloudflare using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] Cloudflare using default Model: %s", c.Model)
+	}
+}
@@ -0,0 +1,31 @@
+package mcp
+
+import (
+	"testing"
+)
+
+// ============================================================
+// Test CloudflareClient
+// ============================================================
+
+func TestCloudflareClient_AccountScopedURL(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Hold BTC.")
+	client := NewCloudflareClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithCloudflareAccount("0123abcd"),
+		WithAPIKey("cf_test_token"),
+		WithLogger(NewNoopLogger()),
+	)
+
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	req := mockHTTP.GetLastRequest()
+	if req.URL.String() != "https://api.cloudflare.com/client/v4/accounts/0123abcd/ai/v1/chat/completions" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if req.Header.Get("Authorization") != "Bearer cf_test_token" {
+		t.Errorf("unexpected Authorization %q", req.Header.Get("Authorization"))
+	}
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Coalescer shares one upstream request between concurrent identical calls
+//
+// Calls are identical when they go to the same URL with the same API key and the
+// same serialized request body (provider, model, parameters and prompt). The first
+// caller performs the request; callers arriving while it is in flight wait for it and
+// receive the same response. Completed results are not cached.
+//
+// A nil *Coalescer disables coalescing. One Coalescer can be shared by several
+// clients (e.g. one per strategy) so that they coalesce with each other.
+type Coalescer struct {
+	mu       sync.Mutex
+	inflight map[string]*coalescedCall
+
+	shared int // Number of calls served by another caller's request
+}
+
+// coalescedCall one in-flight upstream request
+type coalescedCall struct {
+	done     chan struct{}
+	resp     coalescedResponse
+	canceled bool // The leader's context ended, the response is not shared
+}
+
+// coalescedResponse outcome of the shared upstream request
+type coalescedResponse struct {
+	body       []byte
+	statusCode int
+	header     http.Header
+	result     string
+	err        error
+}
+
+// NewCoalescer creates coalescer
+func NewCoalescer() *Coalescer {
+	return &Coalescer{inflight: make(map[string]*coalescedCall)}
+}
+
+// WithCoalescing makes concurrent identical calls share one upstream request
+//
+// Pass the same Coalescer to several clients to coalesce across them. The shared
+// request runs with the context of the caller that started it; waiting callers stop
+// waiting when their own context ends, and send the request again (one of them
+// leading it) when the caller that started it is cancelled.
+//
+// Usage example:
+//   coalescer := mcp.NewCoalescer()
+//   clientA := mcp.NewDeepSeekClientWithOptions(mcp.WithCoalescing(coalescer))
+//   clientB := mcp.NewDeepSeekClientWithOptions(mcp.WithCoalescing(coalescer))
+func WithCoalescing(c *Coalescer) ClientOption {
+	return func(cfg *Config) {
+		cfg.Coalescer = c
+	}
+}
+
+// Shared returns number of calls that were served by another caller's request
+func (c *Coalescer) Shared() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.shared
+}
+
+// do runs fn once per key among concurrent callers; shared reports whether the
+// response came from another caller's request
+//
+// ctx is the caller's context: waiting ends with ctx.Err() when it is done. fn runs
+// with the context of the caller that leads; when that one is cancelled, waiting
+// callers run fn again instead of failing with its cancellation.
+func (c *Coalescer) do(ctx context.Context, key string, fn func() coalescedResponse) (resp coalescedResponse, shared bool) {
+	if c == nil {
+		return fn(), false
+	}
+
+	c.mu.Lock()
+	for {
+		call, ok := c.inflight[key]
+		if !ok {
+			break
+		}
+		c.shared++
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			c.unshare()
+			return coalescedResponse{err: ctx.Err()}, true
+		}
+		if !call.canceled {
+			return call.resp, true
+		}
+		c.unshare()
+		c.mu.Lock()
+	}
+	call := &coalescedCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.resp = fn()
+	call.canceled = ctx.Err() != nil
+	return call.resp, false
+}
+
+// unshare uncounts a waiting caller that did not get the shared response
+func (c *Coalescer) unshare() {
+	c.mu.Lock()
+	c.shared--
+	c.mu.Unlock()
+}
+
+// coalesceKey identifies identical calls
+func coalesceKey(url, apiKey string, jsonData []byte) string {
+	return hashText(url + "\n" + hashText(apiKey) + "\n" + string(jsonData))
+}
@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Request Coalescing
+// ============================================================
+
+func TestCoalescing_ConcurrentIdenticalCallsShareRequest(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.Write([]byte(`{"choices":[{"message":{"content":"shared answer"}}]}`))
+	}))
+	defer server.Close()
+
+	coalescer := NewCoalescer()
+	newClient := func() AIClient {
+		return NewClient(
+			WithProvider(ProviderCustom),
+			WithBaseURL(server.URL),
+			WithAPIKey("sk-test-key"),
+			WithModel("test-model"),
+			WithLogger(NewNoopLogger()),
+			WithCoalescing(coalescer),
+		)
+	}
+	// Two clients sharing one coalescer, as two strategies would
+	clients := []AIClient{newClient(), newClient()}
+
+	const callers = 6
+	results := make([]string, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = clients[i%2].CallWithMessages("system", "same question")
+		}(i)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for coalescer.Shared() < callers-1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if hits.Load() != 1 {
+		t.Errorf("expected 1 upstream request, got %d", hits.Load())
+	}
+	for i, r := range results {
+		if r != "shared answer" {
+			t.Errorf("caller %d: expected 'shared answer', got %q", i, r)
+		}
+	}
+}
+
+func TestCoalescing_ChargesOnce(t *testing.T) {
+	RegisterModelPrice("coalesce-test-model", ModelPrice{InputPerMillion: 1000, OutputPerMillion: 1000})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"choices":[{"message":{"content":"shared answer"}}],"usage":{"prompt_tokens":100,"completion_tokens":100,"total_tokens":200}}`))
+	}))
+	defer server.Close()
+
+	coalescer := NewCoalescer()
+	budget := NewBudget(100)
+	accounts := NewTenantAccounts(0)
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL(server.URL),
+		WithAPIKey("sk-test-key"),
+		WithModel("coalesce-test-model"),
+		WithLogger(NewNoopLogger()),
+		WithCoalescing(coalescer),
+		WithBudget(budget),
+		WithTenant("desk-1"),
+		WithTenantAccounts(accounts),
+	)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.CallWithMessages("system", "same question")
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for coalescer.Shared() < callers-1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if spent := budget.Spent(); spent < 0.19 || spent > 0.21 {
+		t.Errorf("one upstream request should be charged once, spent %.2f", spent)
+	}
+	if usage := accounts.Usage("desk-1"); usage.PromptTokens != 100 || usage.CompletionTokens != 100 {
+		t.Errorf("tenant should be charged for one request, got %+v", usage)
+	}
+}
+
+func TestCoalescing_DifferentPromptsNotShared(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("answer")
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithCoalescing(NewCoalescer()),
+	)
+
+	client.CallWithMessages("system", "question A")
+	client.CallWithMessages("system", "question B")
+	client.CallWithMessages("system", "question A")
+
+	if n := len(mockHTTP.GetRequests()); n != 3 {
+		t.Errorf("sequential and distinct calls should not be coalesced, expected 3 requests, got %d", n)
+	}
+}
+
+func TestCoalescing_KeyIncludesAPIKey(t *testing.T) {
+	body := []byte(`{"model":"m"}`)
+	if coalesceKey("u", "key-a", body) == coalesceKey("u", "key-b", body) {
+		t.Error("calls with different API keys must not be coalesced")
+	}
+	if coalesceKey("u", "key-a", body) != coalesceKey("u", "key-a", body) {
+		t.Error("identical calls should have identical keys")
+	}
+}
+
+func TestCoalescer_FollowerCancellation(t *testing.T) {
+	coalescer := NewCoalescer()
+	release := make(chan struct{})
+	leaderDone := make(chan coalescedResponse)
+	go func() {
+		resp, _ := coalescer.do(context.Background(), "k", func() coalescedResponse {
+			<-release
+			return coalescedResponse{result: "leader"}
+		})
+		leaderDone <- resp
+	}()
+	for !coalescerHas(coalescer, "k") {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	resp, shared := coalescer.do(ctx, "k", func() coalescedResponse { return coalescedResponse{result: "follower"} })
+	if !shared || !errors.Is(resp.err, context.DeadlineExceeded) {
+		t.Errorf("follower should stop waiting on its own deadline, got %+v (shared %v)", resp, shared)
+	}
+	if coalescer.Shared() != 0 {
+		t.Errorf("a follower that gave up was not served, got Shared() = %d", coalescer.Shared())
+	}
+	close(release)
+	if resp := <-leaderDone; resp.result != "leader" {
+		t.Errorf("leader got %+v", resp)
+	}
+}
+
+func TestCoalescer_LeaderCancellationNotShared(t *testing.T) {
+	coalescer := NewCoalescer()
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	go coalescer.do(leaderCtx, "k", func() coalescedResponse {
+		<-leaderCtx.Done()
+		return coalescedResponse{err: leaderCtx.Err()}
+	})
+	for !coalescerHas(coalescer, "k") {
+		time.Sleep(time.Millisecond)
+	}
+
+	followerDone := make(chan coalescedResponse)
+	go func() {
+		resp, _ := coalescer.do(context.Background(), "k", func() coalescedResponse { return coalescedResponse{result: "reissued"} })
+		followerDone <- resp
+	}()
+	for coalescer.Shared() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancelLeader()
+	if resp := <-followerDone; resp.err != nil || resp.result != "reissued" {
+		t.Errorf("follower should reissue the request instead of inheriting the leader's cancellation, got %+v", resp)
+	}
+}
+
+// coalescerHas reports whether a request for key is in flight
+func coalescerHas(c *Coalescer, key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.inflight[key]
+	return ok
+}
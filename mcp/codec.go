@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"sort"
+)
+
+// Codec encodes request bodies built by the client for the wire
+//
+// JSONCodec is the default; FormCodec and MultipartCodec cover form and file uploads.
+// Other formats (e.g. protobuf for Vertex) plug in by implementing Codec.
+type Codec interface {
+	ContentType() string // Content-Type header of encoded bodies
+	Marshal(body map[string]any) ([]byte, error)
+}
+
+// JSONCodec encodes bodies as JSON
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return "application/json" }
+
+func (JSONCodec) Marshal(body map[string]any) ([]byte, error) {
+	return json.Marshal(body)
+}
+
+// FormCodec encodes bodies as URL-encoded forms (nested values as JSON strings)
+type FormCodec struct{}
+
+func (FormCodec) ContentType() string { return "application/x-www-form-urlencoded" }
+
+func (FormCodec) Marshal(body map[string]any) ([]byte, error) {
+	form := url.Values{}
+	for key, value := range body {
+		text, err := formValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", key, err)
+		}
+		form.Set(key, text)
+	}
+	return []byte(form.Encode()), nil
+}
+
+// MultipartCodec encodes bodies as multipart/form-data
+//
+// []byte and *FileRef values become file parts, other values form fields (nested values
+// as JSON strings). The boundary is fixed per codec, so equal bodies encode identically.
+type MultipartCodec struct {
+	Boundary string
+}
+
+// NewMultipartCodec creates multipart codec with a random boundary
+func NewMultipartCodec() *MultipartCodec {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return &MultipartCodec{Boundary: "nofx-" + hex.EncodeToString(b)}
+}
+
+func (c *MultipartCodec) ContentType() string {
+	return "multipart/form-data; boundary=" + c.Boundary
+}
+
+func (c *MultipartCodec) Marshal(body map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(c.Boundary); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var err error
+		switch value := body[key].(type) {
+		case []byte:
+			err = writeFilePart(writer, key, key, "application/octet-stream", value)
+		case *FileRef:
+			err = writeFilePart(writer, key, value.Name, value.MIME, value.data)
+		default:
+			var text string
+			if text, err = formValue(value); err == nil {
+				err = writer.WriteField(key, text)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", key, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFilePart writes one file part of a multipart body
+func writeFilePart(writer *multipart.Writer, field, name, mime string, data []byte) error {
+	if mime == "" {
+		mime = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, name))
+	header.Set("Content-Type", mime)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(data)
+	return err
+}
+
+// formValue formats a body value as form text
+func formValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	case bool, int, int64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+}
+
+// WithCodec sets the encoding of chat request bodies (nil = JSONCodec)
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithCodec(mcp.FormCodec{}))
+func WithCodec(codec Codec) ClientOption {
+	return func(c *Config) {
+		c.Codec = codec
+	}
+}
+
+// codec returns the codec of request bodies
+func (client *Client) codec() Codec {
+	if client.config.Codec != nil {
+		return client.config.Codec
+	}
+	return JSONCodec{}
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"testing"
+)
+
+func TestFormCodec(t *testing.T) {
+	data, err := FormCodec{}.Marshal(map[string]any{"model": "m", "temperature": 0.5, "stream": false, "stop": []string{"\n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("model") != "m" || form.Get("temperature") != "0.5" || form.Get("stream") != "false" || form.Get("stop") != `["\n"]` {
+		t.Errorf("unexpected form %v", form)
+	}
+}
+
+func TestMultipartCodec(t *testing.T) {
+	codec := NewMultipartCodec()
+	body := map[string]any{
+		"model": "whisper-1",
+		"file":  &FileRef{Name: "call.mp3", MIME: "audio/mpeg", data: []byte("ID3")},
+		"raw":   []byte{1, 2},
+	}
+	data, err := codec.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := codec.Marshal(body)
+	if !bytes.Equal(data, again) {
+		t.Error("equal bodies should encode identically")
+	}
+
+	_, params, err := mime.ParseMediaType(codec.ContentType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := multipart.NewReader(bytes.NewReader(data), params["boundary"])
+	parts := map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(part)
+		parts[part.FormName()] = part.FileName() + ":" + part.Header.Get("Content-Type") + ":" + string(content)
+	}
+	if parts["file"] != "call.mp3:audio/mpeg:ID3" || parts["raw"] != "raw:application/octet-stream:\x01\x02" || parts["model"] != "::whisper-1" {
+		t.Errorf("unexpected parts %q", parts)
+	}
+}
+
+func TestWithCodec_ContentType(t *testing.T) {
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(mock.ToHTTPClient()), WithCodec(FormCodec{}))
+	if _, err := client.(*Client).CallMessages(context.Background(), "system", "user"); err != nil {
+		t.Fatal(err)
+	}
+	req := mock.GetLastRequest()
+	if req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Errorf("expected form content type, got %q", req.Header.Get("Content-Type"))
+	}
+	sent, _ := io.ReadAll(req.Body)
+	if form, err := url.ParseQuery(string(sent)); err != nil || form.Get("model") == "" {
+		t.Errorf("expected form body, got %s", sent)
+	}
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	ProviderCohere          = "cohere"
+	DefaultCohereBaseURL    = "https://api.cohere.com/v2"
+	DefaultCohereModel      = "command-a-03-2025"
+	DefaultCohereEmbedModel = "embed-v4.0"
+)
+
+type CohereClient struct {
+	*Client
+}
+
+// NewCohereClient creates Cohere client (backward compatible)
+func NewCohereClient() AIClient {
+	return NewCohereClientWithOptions()
+}
+
+// NewCohereClientWithOptions creates Cohere client (supports options pattern)
+//
+// Chat, Embed and Rerank use Cohere's native v2 API: chat parameters are mapped to
+// Cohere names (stop_sequences, p) and embeddings are requested as float vectors.
+//
+// Usage example:
+//   client := mcp.NewCohereClientWithOptions(mcp.WithAPIKey(key)).(*mcp.CohereClient)
+//   ranked, err := client.Rerank(ctx, "BTC ETF inflows", headlines, 5)
+func NewCohereClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Cohere preset options
+	cohereOpts := []ClientOption{
+		WithProvider(ProviderCohere),
+		WithModel(DefaultCohereModel),
+		WithBaseURL(DefaultCohereBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(cohereOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create Cohere client
+	cohereClient := &CohereClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to CohereClient (implement dynamic dispatch)
+	baseClient.hooks = cohereClient
+
+	return cohereClient
+}
+
+func (c *CohereClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] Cohere API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Cohere using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Cohere using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] Cohere using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] Cohere using default Model: %s", c.Model)
+	}
+}
+
+// buildMCPRequestBody Cohere v2 chat takes OpenAI-style messages with its own parameter names
+func (c *CohereClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any {
+	requestBody := c.Client.buildMCPRequestBody(systemPrompt, userPrompt)
+	if c.config.ResponseFormat != nil {
+		requestBody["response_format"] = cohereResponseFormat(c.config.ResponseFormat)
+	}
+	adaptCohereBody(requestBody)
+	return requestBody
+}
+
+// parseMCPResponse Cohere returns the answer as content blocks of a top-level message
+func (c *CohereClient) parseMCPResponse(body []byte) (string, error) {
+	var response struct {
+		Message struct {
+			Content []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"content"`
+			ToolCalls []json.RawMessage `json:"tool_calls"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse Cohere response: %w, body: %s", err, string(body))
+	}
+
+	if usage := parseUsage(body); TokenUsageCallback != nil && usage.TotalTokens > 0 {
+		usage.Provider = c.Provider
+		usage.Model = c.Model
+		TokenUsageCallback(usage)
+	}
+
+	var text strings.Builder
+	for _, content := range response.Message.Content {
+		if content.Type == "text" {
+			text.WriteString(content.Text)
+		}
+	}
+	if text.Len() == 0 && len(response.Message.ToolCalls) == 0 {
+		return "", fmt.Errorf("Cohere returned empty content, body: %s", string(body))
+	}
+	return text.String(), nil
+}
+
+// adaptCohereBody renames OpenAI-style chat parameters to their Cohere v2 names
+func adaptCohereBody(requestBody map[string]any) {
+	for from, to := range map[string]string{
+		"stop":                  "stop_sequences",
+		"top_p":                 "p",
+		"max_completion_tokens": "max_tokens",
+	} {
+		if value, ok := requestBody[from]; ok {
+			requestBody[to] = value
+			delete(requestBody, from)
+		}
+	}
+	if choice, ok := requestBody["tool_choice"].(string); ok {
+		// Cohere only knows REQUIRED and NONE; "auto" is its default
+		switch choice {
+		case "required", "none":
+			requestBody["tool_choice"] = strings.ToUpper(choice)
+		default:
+			delete(requestBody, "tool_choice")
+		}
+	}
+}
+
+// cohereResponseFormat builds Cohere response_format value (json_object with optional schema)
+func cohereResponseFormat(format *ResponseFormat) map[string]any {
+	value := map[string]any{"type": "json_object"}
+	if format.Schema != nil {
+		value["json_schema"] = format.Schema
+	}
+	return value
+}
+
+// embedCohere embeds inputs via Cohere's native /embed endpoint
+func (client *Client) embedCohere(ctx context.Context, model string, inputs []string) (*EmbeddingResponse, error) {
+	body, requestID, err := client.postAPI(ctx, EndpointEmbeddings, map[string]any{
+		"model":           model,
+		"texts":           inputs,
+		"input_type":      "search_document",
+		"embedding_types": []string{"float"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Embeddings struct {
+			Float [][]float64 `json:"float"`
+		} `json:"embeddings"`
+		Meta struct {
+			BilledUnits struct {
+				InputTokens int `json:"input_tokens"`
+			} `json:"billed_units"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, withRequestID(fmt.Errorf("failed to parse embedding response: %w", err), requestID)
+	}
+	if len(result.Embeddings.Float) != len(inputs) {
+		return nil, withRequestID(fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(result.Embeddings.Float)), requestID)
+	}
+	tokens := result.Meta.BilledUnits.InputTokens
+	return &EmbeddingResponse{
+		Vectors: result.Embeddings.Float,
+		Model:   model,
+		Usage:   TokenUsage{Provider: client.Provider, Model: model, PromptTokens: tokens, TotalTokens: tokens},
+	}, nil
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+// ============================================================
+// Test CohereClient
+// ============================================================
+
+func TestCohereClient_Chat(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"id":"c1","finish_reason":"COMPLETE","message":{"role":"assistant","content":[{"type":"text","text":"Hold BTC."}]},"usage":{"billed_units":{"input_tokens":12,"output_tokens":3},"tokens":{"input_tokens":40,"output_tokens":3}}}`
+	client := NewCohereClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("co-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*CohereClient)
+
+	request := NewRequestBuilder().
+		WithSystemPrompt("You are a trader.").
+		WithUserPrompt("BTC?").
+		WithStopSequences([]string{"END"}).
+		WithTopP(0.9).
+		MustBuild()
+	response, err := client.Call(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if response.Text != "Hold BTC." || response.FinishReason != FinishReasonStop {
+		t.Errorf("unexpected response %q finish %q", response.Text, response.FinishReason)
+	}
+	if response.Usage.PromptTokens != 40 || response.Usage.CompletionTokens != 3 {
+		t.Errorf("unexpected usage %+v", response.Usage)
+	}
+
+	body := bodies[0]
+	if body["stop_sequences"] == nil || body["p"] != 0.9 || body["stop"] != nil || body["top_p"] != nil {
+		t.Errorf("parameters should use Cohere names, got %v", body)
+	}
+	if body["model"] != DefaultCohereModel {
+		t.Errorf("expected default model, got %v", body["model"])
+	}
+}
+
+func TestCohereClient_JSONResponseFormat(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"finish_reason":"COMPLETE","message":{"content":[{"type":"text","text":"{\"action\":\"buy\",\"confidence\":0.7}"}]}}`
+	client := NewCohereClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("co-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithJSONResponse(decisionSchema),
+	)
+
+	if _, err := client.CallWithMessages("system", "decide"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	format, _ := bodies[0]["response_format"].(map[string]any)
+	if format["type"] != "json_object" || format["json_schema"] == nil {
+		t.Errorf("expected Cohere json_object format with schema, got %v", bodies[0]["response_format"])
+	}
+}
+
+func TestCohereClient_Stream(t *testing.T) {
+	var sent []map[string]any
+	client := NewCohereClientWithOptions(WithAPIKey("co-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(sequenceSSEClient(&sent,
+		sseLines(
+			`{"type":"message-start","id":"m1","delta":{"message":{"role":"assistant","content":[]}}}`,
+			`{"type":"content-start","index":0,"delta":{"message":{"content":{"type":"text","text":""}}}}`,
+			`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"Funding is "}}}}`,
+			`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"negative."}}}}`,
+			`{"type":"content-end","index":0}`,
+			`{"type":"message-end","delta":{"finish_reason":"COMPLETE","usage":{"tokens":{"input_tokens":9,"output_tokens":4}}}}`,
+		),
+	))).(*CohereClient)
+
+	text, response, err := streamText(t, client.Client)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if text != "Funding is negative." || response.Text != text {
+		t.Errorf("unexpected stream text %q, response %q", text, response.Text)
+	}
+	if response.FinishReason != FinishReasonStop || response.Usage.CompletionTokens != 4 {
+		t.Errorf("unexpected finish %q usage %+v", response.FinishReason, response.Usage)
+	}
+}
+
+func TestCohereClient_Embed(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"id":"e1","embeddings":{"float":[[0.1,0.2],[0.3,0.4]]},"meta":{"billed_units":{"input_tokens":6}}}`
+	client := NewCohereClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("co-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*CohereClient)
+
+	result, err := client.Embed(context.Background(), []string{"BTC up", "ETH down"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(result.Vectors) != 2 || result.Vectors[1][1] != 0.4 || result.Usage.TotalTokens != 6 {
+		t.Errorf("unexpected embeddings %+v", result)
+	}
+	if bodies[0]["texts"] == nil || bodies[0]["model"] != DefaultCohereEmbedModel || bodies[0]["input_type"] != "search_document" {
+		t.Errorf("unexpected embed request %v", bodies[0])
+	}
+}
+
+func TestCohereClient_Rerank(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"results":[{"index":2,"relevance_score":0.9},{"index":0,"relevance_score":0.4}],"meta":{"billed_units":{"search_units":1}}}`
+	client := NewCohereClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("co-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*CohereClient)
+
+	ranked, err := client.Rerank(context.Background(), "BTC ETF", []string{"ETF inflows", "ETH upgrade", "BTC ETF record"}, 2)
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if len(ranked.Results) != 2 || ranked.Results[0].Document != "BTC ETF record" || ranked.Results[0].Score != 0.9 {
+		t.Errorf("unexpected results %+v", ranked.Results)
+	}
+	if bodies[0]["top_n"] != float64(2) || bodies[0]["model"] != "rerank-v3.5" {
+		t.Errorf("unexpected rerank request %v", bodies[0])
+	}
+}
+
+func TestRerank_UnsupportedProvider(t *testing.T) {
+	client := NewOpenAIClientWithOptions(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger())).(*OpenAIClient)
+	if _, err := client.Rerank(context.Background(), "q", []string{"d"}, 1); err != ErrRerankUnsupported {
+		t.Errorf("expected ErrRerankUnsupported, got %v", err)
+	}
+}
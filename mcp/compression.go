@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// minCompressBytes request bodies smaller than this are sent uncompressed
+const minCompressBytes = 1024
+
+// WithCompression enables gzip request bodies and compressed responses
+//
+// Responses are requested with "Accept-Encoding: gzip, deflate" and decoded
+// transparently. Request bodies of 1KB or more (e.g. RAG-stuffed prompts) are sent
+// gzip-compressed; if an endpoint answers 415 Unsupported Media Type the request is
+// resent uncompressed and request compression stays off for that host.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithCompression(true))
+func WithCompression(enabled bool) ClientOption {
+	return func(c *Config) {
+		c.Compression = enabled
+	}
+}
+
+// compressionTransport http.RoundTripper compressing requests and decoding responses
+type compressionTransport struct {
+	next http.RoundTripper
+
+	mu          sync.Mutex
+	unsupported map[string]bool // Hosts rejecting compressed request bodies
+}
+
+func newCompressionTransport(next http.RoundTripper) *compressionTransport {
+	return &compressionTransport{next: next, unsupported: make(map[string]bool)}
+}
+
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Header.Get("Content-Encoding") == "" {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	compress := len(body) >= minCompressBytes && !t.isUnsupported(req.URL.Host)
+	resp, err := t.send(req, body, compress)
+	if err != nil || !compress || resp.StatusCode != http.StatusUnsupportedMediaType {
+		return resp, err
+	}
+
+	// Endpoint doesn't accept compressed bodies: remember and resend as-is
+	resp.Body.Close()
+	t.mu.Lock()
+	t.unsupported[req.URL.Host] = true
+	t.mu.Unlock()
+	return t.send(req, body, false)
+}
+
+// send sends req with body (gzip-compressed if compress) and decodes the response
+func (t *compressionTransport) send(req *http.Request, body []byte, compress bool) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		payload := body
+		if compress {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write(body)
+			gz.Close()
+			payload = buf.Bytes()
+			out.Header.Set("Content-Encoding", "gzip")
+		}
+		out.Body = io.NopCloser(bytes.NewReader(payload))
+		out.ContentLength = int64(len(payload))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(payload)), nil
+		}
+	}
+	out.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResponse(resp)
+}
+
+// isUnsupported reports whether host rejected compressed request bodies before
+func (t *compressionTransport) isUnsupported(host string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.unsupported[host]
+}
+
+// decodeResponse replaces a gzip/deflate encoded body with its decoded stream
+func decodeResponse(resp *http.Response) (*http.Response, error) {
+	var (
+		decoded io.ReadCloser
+		err     error
+	)
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip":
+		decoded, err = gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode gzip response: %w", err)
+		}
+	case "deflate":
+		decoded = flate.NewReader(resp.Body)
+	default:
+		return resp, nil
+	}
+
+	resp.Body = &decodedBody{ReadCloser: decoded, raw: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// decodedBody closes both the decoder and the underlying body
+type decodedBody struct {
+	io.ReadCloser
+	raw io.ReadCloser
+}
+
+func (b *decodedBody) Close() error {
+	b.ReadCloser.Close()
+	return b.raw.Close()
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// ============================================================
+// Test Compression
+// ============================================================
+
+const compressionTestResponse = `{"choices":[{"message":{"content":"compressed answer"}}]}`
+
+func newCompressionTestClient(url string) AIClient {
+	return NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL(url),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(1),
+		WithCompression(true),
+	)
+}
+
+func TestCompression_GzipRequestAndResponse(t *testing.T) {
+	longPrompt := strings.Repeat("market context ", 200)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("large request body should be gzip-compressed")
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("request body is not gzip: %v", err)
+		}
+		body, _ := io.ReadAll(gz)
+		if !strings.Contains(string(body), "market context") {
+			t.Error("decompressed body should contain prompt")
+		}
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Error("client should advertise gzip support")
+		}
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(compressionTestResponse))
+		zw.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	result, err := newCompressionTestClient(server.URL).CallWithMessages("system", longPrompt)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != "compressed answer" {
+		t.Errorf("expected 'compressed answer', got %q", result)
+	}
+}
+
+func TestCompression_SmallRequestNotCompressed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "" {
+			t.Error("small request body should not be compressed")
+		}
+		w.Write([]byte(compressionTestResponse))
+	}))
+	defer server.Close()
+
+	if _, err := newCompressionTestClient(server.URL).CallWithMessages("system", "short"); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+}
+
+func TestCompression_FallbackOnUnsupportedMediaType(t *testing.T) {
+	var compressed atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			compressed.Add(1)
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		w.Write([]byte(compressionTestResponse))
+	}))
+	defer server.Close()
+
+	client := newCompressionTestClient(server.URL)
+	longPrompt := strings.Repeat("x", 2*minCompressBytes)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.CallWithMessages("system", longPrompt); err != nil {
+			t.Fatalf("call %d should fall back to uncompressed body: %v", i, err)
+		}
+	}
+	if compressed.Load() != 1 {
+		t.Errorf("compression should be disabled for host after 415, got %d compressed attempts", compressed.Load())
+	}
+}
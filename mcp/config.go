@@ -1,7 +1,9 @@
 package mcp
 
 import (
+	"crypto/tls"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"time"
@@ -17,6 +19,9 @@ type Config struct {
 	BaseURL  string
 	Model    string
 
+	// Authentication configuration
+	ZeroAuth bool // Server needs no API key: calls without one are sent unauthenticated
+
 	// Behavior configuration
 	MaxTokens   int
 	Temperature float64
@@ -28,15 +33,202 @@ type Config struct {
 	RetryableErrors []string
 
 	// Timeout configuration
-	Timeout time.Duration
+	Timeout     time.Duration // Whole call including reading the body
+	IdleTimeout time.Duration // Max gap between response body chunks (0 = unlimited)
+
+	// Response size configuration
+	MaxResponseBytes int64 // Max response body size in bytes (0 = unlimited)
+
+	// Rate limiting configuration
+	RateLimiter *RateLimiter // Limits outgoing requests (nil = unlimited)
+
+	// Reasoning configuration
+	RetainReasoning bool // Keep inline <think> blocks in response text
+
+	// Prompt compression configuration
+	CompressionRatio     float64 // Fraction of tokens kept in long user messages (0 = disabled)
+	CompressionMinTokens int     // Messages shorter than this are sent as is (0 = DefaultCompressionMinTokens)
+
+	// Output language configuration
+	OutputLanguage string // ISO 639-1 code answers must be written in ("" = any)
+
+	// Hugging Face configuration
+	TextGenerationAPI bool // Use TGI's native generate API instead of chat/completions
+
+	// Capability probing configuration
+	CapabilityProbe bool // Detect server features on first call and shape requests to fit
+
+	// Parsing configuration
+	StrictParsing bool // Fail on unknown finish reasons or missing usage instead of warning
+
+	// Stream resumption configuration
+	StreamResumes int             // Re-requests after an interrupted stream (0 = disabled)
+	StreamRetry   StreamRetryMode // Recover failed streams under the retry policy (overrides StreamResumes)
+
+	// Stream latency configuration
+	StreamSLO *StreamSLO // First-token latency and throughput objectives of streamed calls (nil = metrics only)
+
+	// Partial response configuration
+	PartialMinChars int // Answer characters a failed call must have received to return them in *PartialResponseError (0 = DefaultPartialMinChars, negative = never)
+
+	// Truncation configuration
+	TruncationPolicy TruncationPolicy // What to do with output cut off by the token limit
+	MaxContinuations int              // Max continuation requests (TruncationContinue)
+	ContinuePrompt   string           // Prompt asking to continue ("" = DefaultContinuePrompt)
+
+	// Reproducibility configuration
+	Deterministic bool  // Temperature 0 and fixed seed on every request
+	Seed          int64 // Sampling seed, sent where supported (0 = none)
+
+	// Refusal handling configuration
+	RefusalHandling bool     // Detect refusals and fail them with ErrRefusal
+	RefusalRephrase string   // Template retried once after a refusal ("" = no rephrase)
+	RefusalFallback AIClient // Model tried after refusals (nil = none)
+
+	// Response processing configuration
+	ResponseProcessors []ResponseProcessor // Applied in order to every response text (nil = none)
+
+	// Structured output configuration
+	ResponseFormat   *ResponseFormat   // Force JSON responses validated against a schema (nil = free text)
+	SchemaMigrations *SchemaMigrations // Upgrade JSON responses of older schema versions before validation (nil = none)
+
+	// Embedding configuration
+	EmbeddingModel string // Model used by Embed ("" = provider default)
+	RerankModel    string // Model used by Rerank ("" = provider default)
+
+	// Passthrough configuration (provider-specific parameters without first-class options)
+	ExtraBody  map[string]any // Merged into every request body, overriding built fields
+	ExtraQuery url.Values     // Added to the query string of every request URL
+
+	// Identification configuration
+	UserAgent           string // User-Agent header ("" = nofx-mcp/<Version> with Go version and provider)
+	ClientVersionHeader bool   // Also send X-Client-Version
+
+	// Warm-up configuration
+	WarmupAuthCheck bool // Warmup also verifies the API key
+
+	// Serialization configuration
+	Codec Codec // Encoding of chat request bodies (nil = JSONCodec)
+
+	// Path configuration
+	PathTemplates map[string]string // URL templates by endpoint, overriding provider paths (see WithPathTemplate)
+
+	// Prompt caching configuration
+	PromptCaching  bool   // Send system prompt and cached context as Anthropic cache breakpoints
+	PromptCacheKey string // OpenAI prompt_cache_key routing hint ("" = none)
+
+	// PII redaction configuration
+	PIIPolicies []PIIPolicy // Sensitive data replaced by placeholders before sending (nil = disabled)
+
+	// Guardrail configuration
+	Guardrails []GuardrailRule // Input/output validators run in order around every call
+
+	// Moderation configuration
+	Moderation      *ModerationConfig // Moderation service used by Moderate (nil = by provider)
+	ModerateOutputs bool              // Moderate every response before returning it
+
+	// Shadow traffic configuration
+	Shadow           AIClient         // Secondary model receiving mirrored calls (nil = disabled)
+	ShadowSampleRate float64          // Fraction of calls mirrored (0-1)
+	OnShadowPair     func(ShadowPair) // Optional callback for recorded pairs
+
+	// Request ID configuration
+	IdempotencyKeys bool // Also send request ID as Idempotency-Key header
+
+	// Deduplication configuration
+	Coalescer *Coalescer // Shares one request between concurrent identical calls (nil = disabled)
+
+	// Latency monitoring configuration
+	SlowThreshold time.Duration  // Calls slower than this are reported (0 = disabled)
+	OnSlowCall    func(SlowCall) // Optional callback for slow calls
+
+	// Compression configuration
+	Compression bool // Gzip request bodies and accept compressed responses
+
+	// Connection pool configuration (applies to the default HTTPClient only)
+	Transport TransportConfig
+
+	// Request signing configuration
+	RequestSigner RequestSigner // Signs every request for gateway verification (nil = unsigned)
+	CloudSigner   CloudSigner   // Signs provider requests with cloud access keys instead of the API key (nil = API key)
+
+	// OAuth configuration
+	TokenSource     TokenSource // Supplies Bearer access tokens instead of the API key (nil = API key)
+	TokenQueryParam string      // Send access tokens as this query parameter instead of the Authorization header ("" = header)
+
+	// Endpoint security configuration
+	AllowedHosts []string // Hosts requests may go to (nil = any)
+	PinnedCerts  []string // Accepted server certificate fingerprints (nil = no pinning)
+
+	// Data residency configuration
+	DataResidency []string // Regions of registry endpoints prompts may be sent to (nil = any)
+
+	// TLS configuration (applied to a private copy of the transport)
+	TLSConfig      *tls.Config // Custom TLS settings (nil = transport default)
+	ClientCertFile string      // PEM client certificate for mutual TLS
+	ClientKeyFile  string      // PEM private key of ClientCertFile
 
 	// Dependency injection
 	Logger     Logger
 	HTTPClient *http.Client
+
+	defaultHTTPClient *http.Client // HTTPClient created by DefaultConfig (gets the shared transport)
+	baseURLErr        error        // Problem with the URL given to WithBaseURL
+	totalTimeout      bool         // Timeout set by WithTimeouts, overriding HTTPClient's own
+
+	// Recording configuration
+	Recorder *Recorder // Records/replays request-response pairs (nil = disabled)
+
+	// Debug configuration
+	DebugDump *DebugDumper // Dumps raw payloads of every call (nil = disabled)
+
+	// Chaos testing configuration
+	FaultInjector *FaultInjector // Injects faults into responses (nil = disabled)
+
+	// Audit configuration
+	AuditSink    AuditSink         // Receives one record per AI interaction (nil = disabled)
+	AuditContent AuditContentMode  // How prompt/response text is stored
+	AuditTags    map[string]string // Caller-supplied tags attached to every record
+
+	// Reasoning retention configuration (audit records)
+	ReasoningRetention ReasoningRetention // How model reasoning is recorded
+	ReasoningRetainFor time.Duration      // Retention of full reasoning (0 = forever)
+
+	// Encryption at rest configuration (audit records, cassettes)
+	ContentCipher ContentCipher // Encrypts stored prompts and responses (nil = plaintext)
+
+	// Risk review configuration (VerifiedCall)
+	DecisionVerifier AIClient // Model reviewing decisions (nil = the client itself)
+	RiskRules        []string // Rules given to the verifier (nil = DefaultRiskRules)
+
+	// Budget configuration
+	Budget *Budget // Spending limit (nil = unlimited)
+
+	// Tag configuration
+	Tags map[string]string // Metadata tags attached to logs, audit records, webhooks and hook events
+
+	// Tenant configuration
+	Tenant         string          // Tenant calls are attributed to ("" = none)
+	TenantAccounts *TenantAccounts // Per-tenant usage and budgets (nil = disabled)
+
+	// Hedging configuration
+	HedgeDelay    time.Duration // Wait for a response before firing an extra request (0 = disabled)
+	HedgeMaxExtra int           // Max extra requests per call
+	HedgeTargets  []AIClient    // Clients receiving extra requests (nil = same provider)
+
+	// Lifecycle hooks configuration
+	Hooks []Hooks // Callbacks run around every request (nil = none)
+
+	// Webhook configuration
+	WebhookURL    string         // Receives call lifecycle events ("" = disabled)
+	WebhookEvents []WebhookEvent // Events sent (nil = all)
+	WebhookSecret string         // Signs payloads ("" = unsigned)
+	WebhookBudget float64        // USD of cumulative cost sending WebhookBudgetThreshold (0 = disabled)
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
+	httpClient := &http.Client{Timeout: DefaultTimeout}
 	return &Config{
 		// Default values
 		MaxTokens:      getEnvInt("AI_MAX_TOKENS", 2000),
@@ -45,10 +237,13 @@ func DefaultConfig() *Config {
 		RetryWaitBase:  2 * time.Second,
 		Timeout:        DefaultTimeout,
 		RetryableErrors: retryableErrors,
+		Transport:      DefaultTransportConfig(),
 
 		// Default dependencies (use global logger)
 		Logger:     logger.NewMCPLogger(),
-		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+		HTTPClient: httpClient,
+
+		defaultHTTPClient: httpClient,
 	}
 }
 
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by EnvOptions
+const (
+	EnvProvider = "AI_PROVIDER" // Provider name (deepseek, qwen, openai, claude, cohere, ...)
+	EnvAPIKey   = "AI_API_KEY"
+	EnvBaseURL  = "AI_BASE_URL"
+	EnvModel    = "AI_MODEL"
+	EnvTimeout  = "AI_TIMEOUT" // Go duration, e.g. "90s"
+)
+
+// providerConstructors provider-specific client constructors by provider name
+var providerConstructors = map[string]func(opts ...ClientOption) AIClient{
+	ProviderDeepSeek:     NewDeepSeekClientWithOptions,
+	ProviderQwen:         NewQwenClientWithOptions,
+	ProviderOpenAI:       NewOpenAIClientWithOptions,
+	ProviderClaude:       NewClaudeClientWithOptions,
+	ProviderGemini:       NewGeminiClientWithOptions,
+	ProviderGrok:         NewGrokClientWithOptions,
+	ProviderKimi:         NewKimiClientWithOptions,
+	ProviderCohere:       NewCohereClientWithOptions,
+	ProviderVoyage:       NewVoyageClientWithOptions,
+	ProviderHuggingFace:  NewHuggingFaceClientWithOptions,
+	ProviderCloudflare:   NewCloudflareClientWithOptions,
+	ProviderVertex:       NewVertexClientWithOptions,
+	ProviderDoubao:       NewDoubaoClientWithOptions,
+	ProviderErnie:        NewErnieClientWithOptions,
+	ProviderHunyuan:      NewHunyuanClientWithOptions,
+	ProviderSiliconFlow:  NewSiliconFlowClientWithOptions,
+	ProviderNovita:       NewNovitaClientWithOptions,
+	ProviderLMStudio:     NewLMStudioClientWithOptions,
+	ProviderTextGenWebUI: NewTextGenWebUIClientWithOptions,
+}
+
+// loadedModelProviders local servers answering with the loaded model when none is named
+var loadedModelProviders = map[string]bool{
+	ProviderLMStudio:     true,
+	ProviderTextGenWebUI: true,
+}
+
+// NewProviderClient creates the client of provider by name ("custom" = OpenAI-compatible endpoint)
+//
+// Usage example:
+//   client, err := mcp.NewProviderClient("qwen", mcp.WithAPIKey(key))
+func NewProviderClient(provider string, opts ...ClientOption) (AIClient, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == ProviderCustom {
+		client := NewClient(append([]ClientOption{WithProvider(ProviderCustom)}, opts...)...).(*Client)
+		if client.baseURLErr != nil {
+			return nil, client.baseURLErr
+		}
+		return client, nil
+	}
+	constructor, ok := providerConstructors[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported AI provider %q", provider)
+	}
+	client := constructor(opts...)
+	if c, ok := BaseClient(client); ok && c.baseURLErr != nil {
+		return nil, c.baseURLErr
+	}
+	return client, nil
+}
+
+// EnvOptions returns provider name and client options from AI_* environment variables
+//
+// Only variables that are set produce options, so provider defaults stay in effect.
+// AI_MAX_TOKENS is applied by DefaultConfig.
+//
+// Usage example:
+//   provider, opts := mcp.EnvOptions()
+//   client, err := mcp.NewProviderClient(provider, opts...)
+func EnvOptions() (provider string, opts []ClientOption) {
+	provider = getEnvString(EnvProvider, ProviderDeepSeek)
+	if key := os.Getenv(EnvAPIKey); key != "" {
+		opts = append(opts, WithAPIKey(key))
+	}
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" {
+		opts = append(opts, WithBaseURL(baseURL))
+	}
+	if model := os.Getenv(EnvModel); model != "" {
+		opts = append(opts, WithModel(model))
+	}
+	if timeout, err := time.ParseDuration(os.Getenv(EnvTimeout)); err == nil && timeout > 0 {
+		opts = append(opts, WithTimeout(timeout))
+	}
+	return provider, opts
+}
+
+// BaseClient returns the *Client behind a provider client (DeepSeekClient, ClaudeClient, ...)
+func BaseClient(client AIClient) (*Client, bool) {
+	if c, ok := client.(interface{ base() *Client }); ok {
+		return c.base(), true
+	}
+	return nil, false
+}
+
+// Validate checks client configuration without contacting the provider
+//
+// All problems are reported at once (errors.Join).
+func (client *Client) Validate() error {
+	var problems []error
+	if client.APIKey == "" && !client.config.ZeroAuth {
+		problems = append(problems, errors.New("API key is not set"))
+	}
+	if client.Model == "" && !loadedModelProviders[client.Provider] {
+		problems = append(problems, errors.New("model is not set"))
+	}
+	if client.baseURLErr != nil {
+		problems = append(problems, client.baseURLErr)
+	} else if parsed, err := url.Parse(client.BaseURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		problems = append(problems, fmt.Errorf("base URL %q is not an http(s) URL", client.BaseURL))
+	} else if len(client.config.AllowedHosts) > 0 && !hostAllowed(client.config.AllowedHosts, parsed.Host) {
+		problems = append(problems, fmt.Errorf("base URL host %q is not in the allowed hosts", parsed.Host))
+	}
+	if client.MaxTokens <= 0 {
+		problems = append(problems, fmt.Errorf("max tokens must be positive, got %d", client.MaxTokens))
+	}
+	if t := client.config.Temperature; t < 0 || t > 2 {
+		problems = append(problems, fmt.Errorf("temperature must be between 0 and 2, got %g", t))
+	}
+	if client.config.MaxRetries < 1 {
+		problems = append(problems, fmt.Errorf("max retries must be at least 1, got %d", client.config.MaxRetries))
+	}
+	if client.config.Timeout < 0 { // 0 = unlimited, as with WithTimeouts
+		problems = append(problems, errors.New("timeout must not be negative"))
+	}
+	if _, err := parsePins(client.config.PinnedCerts); err != nil {
+		problems = append(problems, err)
+	}
+	return errors.Join(problems...)
+}
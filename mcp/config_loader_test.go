@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewProviderClientFromEnv(t *testing.T) {
+	t.Setenv(EnvProvider, "Qwen")
+	t.Setenv(EnvAPIKey, "sk-env")
+	t.Setenv(EnvModel, "qwen-max")
+	t.Setenv(EnvTimeout, "42s")
+
+	provider, opts := EnvOptions()
+	aiClient, err := NewProviderClient(provider, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, ok := BaseClient(aiClient)
+	if !ok {
+		t.Fatal("BaseClient failed for provider client")
+	}
+	if client.Provider != ProviderQwen || client.APIKey != "sk-env" || client.Model != "qwen-max" {
+		t.Errorf("client = %s/%s key %q", client.Provider, client.Model, client.APIKey)
+	}
+	if client.config.Timeout.Seconds() != 42 {
+		t.Errorf("timeout = %v", client.config.Timeout)
+	}
+
+	if _, err := NewProviderClient("nope"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL("localhost:8080"), WithMaxTokens(0)).(*Client)
+	client.config.Temperature = 3
+
+	err := client.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"API key", "base URL", "max tokens", "temperature"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't mention %s", err, want)
+		}
+	}
+
+	valid := NewOpenAIClientWithOptions(WithAPIKey("sk-test")).(*OpenAIClient)
+	if err := valid.Validate(); err != nil {
+		t.Errorf("default OpenAI config invalid: %v", err)
+	}
+	unlimited := NewOpenAIClientWithOptions(WithAPIKey("sk-test"), WithTimeouts(Timeouts{Total: 0})).(*OpenAIClient)
+	if err := unlimited.Validate(); err != nil {
+		t.Errorf("unlimited timeout rejected: %v", err)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"gpt-b"},{"id":"gpt-a"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL(server.URL), WithAPIKey("sk-test")).(*Client)
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(models, []string{"gpt-a", "gpt-b"}) {
+		t.Errorf("models = %v", models)
+	}
+}
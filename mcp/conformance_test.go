@@ -0,0 +1,33 @@
+package mcp_test
+
+import (
+	"testing"
+
+	"nofx/mcp"
+	"nofx/mcp/mcptest"
+)
+
+// ============================================================
+// Provider Conformance (every built-in provider against the same spec)
+// ============================================================
+
+func TestProviderConformance(t *testing.T) {
+	quiet := mcp.WithLogger(mcp.NewNoopLogger())
+
+	providers := map[string]mcp.AIClient{
+		"client":   mcp.NewClient(quiet),
+		"deepseek": mcp.NewDeepSeekClientWithOptions(quiet),
+		"qwen":     mcp.NewQwenClientWithOptions(quiet),
+		"openai":   mcp.NewOpenAIClientWithOptions(quiet),
+		"claude":   mcp.NewClaudeClientWithOptions(quiet),
+		"gemini":   mcp.NewGeminiClientWithOptions(quiet),
+		"grok":     mcp.NewGrokClientWithOptions(quiet),
+		"kimi":     mcp.NewKimiClientWithOptions(quiet),
+	}
+
+	for name, client := range providers {
+		t.Run(name, func(t *testing.T) {
+			mcptest.RunConformance(t, client)
+		})
+	}
+}
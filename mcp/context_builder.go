@@ -0,0 +1,236 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// EstimateTokens returns approximate token count of text
+//
+// Roughly 4 characters per token for Latin text and one token per CJK character,
+// close enough for budgeting without a provider tokenizer.
+func EstimateTokens(text string) int {
+	var cjk, other int
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// Context section names, in output order (inclusion priority is the same order)
+const (
+	ContextPositions  = "positions"
+	ContextPrices     = "prices"
+	ContextIndicators = "indicators"
+	ContextNews       = "news"
+)
+
+var contextSectionOrder = []string{ContextPositions, ContextPrices, ContextIndicators, ContextNews}
+
+var contextSectionTitles = map[string]string{
+	ContextPositions:  "Open positions",
+	ContextPrices:     "Prices",
+	ContextIndicators: "Indicators",
+	ContextNews:       "News",
+}
+
+// ContextPosition open position included in the market context
+type ContextPosition struct {
+	Symbol        string
+	Side          string // "long" or "short"
+	Size          float64
+	EntryPrice    float64
+	UnrealizedPnL float64
+}
+
+// ContextManifestSection what a section of the built context contains
+type ContextManifestSection struct {
+	Name     string
+	Included []string // Item keys (symbol, symbol/indicator, news source/time)
+	Dropped  []string // Item keys cut by the token budget
+	Tokens   int
+}
+
+// ContextManifest audit record of a built context
+type ContextManifest struct {
+	Budget   int
+	Tokens   int
+	Sections []ContextManifestSection
+}
+
+// Truncated returns true if any item was dropped to fit the budget
+func (m ContextManifest) Truncated() bool {
+	for _, s := range m.Sections {
+		if len(s.Dropped) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// contextItem one rendered line of a section
+type contextItem struct {
+	key, sortKey, line string
+}
+
+// ContextBuilder composes market data into a token-budgeted prompt section
+//
+// Output is deterministic: sections always appear in the same order (positions,
+// prices, indicators, news) and items are sorted within a section (news newest first).
+// When the budget is exceeded, items are dropped from the lowest-priority section
+// first (news, then indicators, prices and positions), from the end of the section.
+type ContextBuilder struct {
+	budget     int
+	items      map[string][]contextItem
+	indicators map[string]map[string]float64
+}
+
+// NewContextBuilder creates builder whose output stays within tokenBudget (0 = unlimited)
+//
+// Usage example:
+//   builder := mcp.NewContextBuilder(1500)
+//   builder.AddPrice("BTCUSDT", 67012.5, 2.1)
+//   builder.AddIndicator("BTCUSDT", "rsi_14", 61.2)
+//   builder.AddNews(time.Now(), "CoinDesk", "Spot ETF inflows hit weekly high")
+//   section, manifest := builder.Build()
+func NewContextBuilder(tokenBudget int) *ContextBuilder {
+	return &ContextBuilder{
+		budget:     tokenBudget,
+		items:      make(map[string][]contextItem),
+		indicators: make(map[string]map[string]float64),
+	}
+}
+
+// AddPrice adds last price and 24h change (percent) of symbol
+func (b *ContextBuilder) AddPrice(symbol string, price, change24h float64) *ContextBuilder {
+	line := fmt.Sprintf("- %s %s (24h %+.2f%%)", symbol, formatContextNumber(price), change24h)
+	b.items[ContextPrices] = append(b.items[ContextPrices], contextItem{key: symbol, sortKey: symbol, line: line})
+	return b
+}
+
+// AddIndicator adds indicator value of symbol (indicators of a symbol share one line)
+func (b *ContextBuilder) AddIndicator(symbol, name string, value float64) *ContextBuilder {
+	if b.indicators[symbol] == nil {
+		b.indicators[symbol] = make(map[string]float64)
+	}
+	b.indicators[symbol][name] = value
+	return b
+}
+
+// AddPosition adds an open position
+func (b *ContextBuilder) AddPosition(position ContextPosition) *ContextBuilder {
+	line := fmt.Sprintf("- %s %s size=%s entry=%s pnl=%+.2f", position.Symbol, position.Side,
+		formatContextNumber(position.Size), formatContextNumber(position.EntryPrice), position.UnrealizedPnL)
+	key := position.Symbol + "/" + position.Side
+	b.items[ContextPositions] = append(b.items[ContextPositions], contextItem{key: key, sortKey: key, line: line})
+	return b
+}
+
+// AddNews adds a news snippet
+func (b *ContextBuilder) AddNews(at time.Time, source, text string) *ContextBuilder {
+	stamp := at.UTC().Format("2006-01-02 15:04")
+	line := fmt.Sprintf("- [%s] %s: %s", stamp, source, strings.Join(strings.Fields(text), " "))
+	// Newest first: invert the timestamp in the sort key
+	sortKey := fmt.Sprintf("%020d|%s|%s", math.MaxInt64-at.UnixNano(), source, text)
+	b.items[ContextNews] = append(b.items[ContextNews], contextItem{key: source + "@" + stamp, sortKey: sortKey, line: line})
+	return b
+}
+
+// Build renders the context and returns it with a manifest of what was included
+func (b *ContextBuilder) Build() (string, ContextManifest) {
+	sections := make(map[string][]contextItem, len(contextSectionOrder))
+	for _, name := range contextSectionOrder {
+		items := append([]contextItem(nil), b.items[name]...)
+		if name == ContextIndicators {
+			items = b.indicatorItems()
+		}
+		sort.SliceStable(items, func(i, j int) bool { return items[i].sortKey < items[j].sortKey })
+		sections[name] = items
+	}
+
+	// Fill the budget in priority order
+	manifest := ContextManifest{Budget: b.budget}
+	included := make(map[string][]contextItem, len(sections))
+	used := 0
+	for _, name := range contextSectionOrder {
+		items := sections[name]
+		if len(items) == 0 {
+			continue
+		}
+		section := ContextManifestSection{Name: name}
+		header := EstimateTokens(contextSectionHeader(name))
+		for _, item := range items {
+			cost := EstimateTokens(item.line) + 1
+			if len(included[name]) == 0 {
+				cost += header
+			}
+			if b.budget > 0 && used+cost > b.budget {
+				section.Dropped = append(section.Dropped, item.key)
+				continue
+			}
+			used += cost
+			section.Tokens += cost
+			included[name] = append(included[name], item)
+			section.Included = append(section.Included, item.key)
+		}
+		manifest.Sections = append(manifest.Sections, section)
+	}
+	manifest.Tokens = used
+
+	var out strings.Builder
+	for _, name := range contextSectionOrder {
+		if len(included[name]) == 0 {
+			continue
+		}
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString(contextSectionHeader(name))
+		for _, item := range included[name] {
+			out.WriteString(item.line)
+			out.WriteString("\n")
+		}
+	}
+	return out.String(), manifest
+}
+
+// indicatorItems renders one line per symbol with its indicators sorted by name
+func (b *ContextBuilder) indicatorItems() []contextItem {
+	items := make([]contextItem, 0, len(b.indicators))
+	for symbol, values := range b.indicators {
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = name + "=" + formatContextNumber(values[name])
+		}
+		items = append(items, contextItem{key: symbol, sortKey: symbol, line: "- " + symbol + ": " + strings.Join(parts, ", ")})
+	}
+	return items
+}
+
+func contextSectionHeader(name string) string {
+	return "## " + contextSectionTitles[name] + "\n"
+}
+
+// formatContextNumber formats v with at most 6 significant decimals, without trailing zeros
+func formatContextNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 6, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContextBuilder_DeterministicOrder(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	build := func(reverse bool) string {
+		b := NewContextBuilder(0)
+		symbols := []string{"ETHUSDT", "BTCUSDT"}
+		if reverse {
+			symbols = []string{"BTCUSDT", "ETHUSDT"}
+		}
+		for _, s := range symbols {
+			b.AddPrice(s, 100.5, -1.234)
+			b.AddIndicator(s, "rsi_14", 55)
+			b.AddIndicator(s, "ema_20", 99.25)
+		}
+		b.AddNews(now.Add(-time.Hour), "Reuters", "older")
+		b.AddNews(now, "CoinDesk", "newer")
+		b.AddPosition(ContextPosition{Symbol: "BTCUSDT", Side: "long", Size: 0.5, EntryPrice: 65000, UnrealizedPnL: 12.5})
+		text, _ := b.Build()
+		return text
+	}
+
+	text := build(false)
+	if text != build(true) {
+		t.Fatal("output depends on insertion order")
+	}
+	want := `## Open positions
+- BTCUSDT long size=0.5 entry=65000 pnl=+12.50
+
+## Prices
+- BTCUSDT 100.5 (24h -1.23%)
+- ETHUSDT 100.5 (24h -1.23%)
+
+## Indicators
+- BTCUSDT: ema_20=99.25, rsi_14=55
+- ETHUSDT: ema_20=99.25, rsi_14=55
+
+## News
+- [2026-10-16 12:00] CoinDesk: newer
+- [2026-10-16 11:00] Reuters: older
+`
+	if text != want {
+		t.Errorf("unexpected context:\n%s", text)
+	}
+}
+
+func TestContextBuilder_BudgetDropsLowPriorityFirst(t *testing.T) {
+	b := NewContextBuilder(40)
+	b.AddPrice("BTCUSDT", 67000, 1)
+	b.AddPrice("ETHUSDT", 2600, 1)
+	for i := 0; i < 10; i++ {
+		b.AddNews(time.Unix(int64(i), 0), "src", strings.Repeat("headline ", 5))
+	}
+
+	text, manifest := b.Build()
+	if !manifest.Truncated() || manifest.Tokens > 40 {
+		t.Fatalf("expected truncation within budget, got %+v", manifest)
+	}
+	if !strings.Contains(text, "ETHUSDT") {
+		t.Error("prices should be kept before news")
+	}
+	news := manifest.Sections[1]
+	if news.Name != ContextNews || len(news.Dropped) == 0 || len(news.Included)+len(news.Dropped) != 10 {
+		t.Errorf("unexpected news manifest %+v", news)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("abcdefgh"); got != 2 {
+		t.Errorf("expected 2 tokens, got %d", got)
+	}
+	if got := EstimateTokens("比特币"); got != 3 {
+		t.Errorf("expected 3 tokens for CJK, got %d", got)
+	}
+}
@@ -0,0 +1,318 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrConversationNotFound is returned by ConversationStore.Load for unknown IDs
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// Conversation multi-turn session persisted between process restarts
+type Conversation struct {
+	ID        string
+	Messages  []Message
+	Metadata  map[string]string // Free-form labels (trader ID, strategy, ...)
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ConversationInfo conversation listing entry (without messages)
+type ConversationInfo struct {
+	ID           string
+	MessageCount int
+	Metadata     map[string]string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ConversationPrunePolicy limits applied automatically on every save (zero = unlimited)
+type ConversationPrunePolicy struct {
+	MaxAge           time.Duration // Delete conversations not updated for longer
+	MaxConversations int           // Keep only the most recently updated ones
+	MaxMessages      int           // Keep only the latest messages of a conversation (system messages are kept)
+}
+
+// ConversationStore persists conversations
+type ConversationStore interface {
+	Save(ctx context.Context, conversation *Conversation) error
+	Load(ctx context.Context, id string) (*Conversation, error)
+	List(ctx context.Context) ([]ConversationInfo, error) // Most recently updated first
+	Delete(ctx context.Context, id string) error
+	Prune(ctx context.Context, policy ConversationPrunePolicy) (int, error)
+}
+
+// SQL dialects supported by SQLConversationStore
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// SQLConversationStore stores conversations in the ai_conversations table
+//
+// As with SQLAuditSink the database handle is injected, so mcp stays free of driver dependencies.
+type SQLConversationStore struct {
+	db      *sql.DB
+	dialect string
+	policy  ConversationPrunePolicy
+	cipher  ContentCipher // Encrypts stored messages (nil = plaintext)
+}
+
+// NewSQLiteConversationStore creates store on a SQLite database
+//
+// Usage example:
+//   db, _ := sql.Open("sqlite", "data/conversations.db")
+//   store, err := mcp.NewSQLiteConversationStore(db, mcp.ConversationPrunePolicy{MaxAge: 30 * 24 * time.Hour})
+func NewSQLiteConversationStore(db *sql.DB, policy ConversationPrunePolicy) (*SQLConversationStore, error) {
+	return newSQLConversationStore(db, DialectSQLite, policy)
+}
+
+// NewPostgresConversationStore creates store on a PostgreSQL database
+func NewPostgresConversationStore(db *sql.DB, policy ConversationPrunePolicy) (*SQLConversationStore, error) {
+	return newSQLConversationStore(db, DialectPostgres, policy)
+}
+
+func newSQLConversationStore(db *sql.DB, dialect string, policy ConversationPrunePolicy) (*SQLConversationStore, error) {
+	intType := "INTEGER"
+	if dialect == DialectPostgres {
+		intType = "BIGINT"
+	}
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ai_conversations (
+		id TEXT PRIMARY KEY,
+		messages TEXT NOT NULL,
+		metadata TEXT,
+		message_count ` + intType + ` NOT NULL,
+		created_at ` + intType + ` NOT NULL,
+		updated_at ` + intType + ` NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conversation table: %w", err)
+	}
+	return &SQLConversationStore{db: db, dialect: dialect, policy: policy}, nil
+}
+
+// WithCipher encrypts messages at rest with cipher (metadata stays readable for listing)
+//
+// Conversations saved before encryption was enabled can still be loaded and are
+// encrypted on their next save.
+//
+// Usage example:
+//   store, _ := mcp.NewSQLiteConversationStore(db, mcp.ConversationPrunePolicy{})
+//   store = store.WithCipher(cipher)
+func (s *SQLConversationStore) WithCipher(cipher ContentCipher) *SQLConversationStore {
+	s.cipher = cipher
+	return s
+}
+
+// query rewrites ? placeholders for the dialect ($1, $2, ... on Postgres)
+func (s *SQLConversationStore) query(q string) string {
+	if s.dialect != DialectPostgres {
+		return q
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Save inserts or replaces conversation, then applies the store prune policy
+func (s *SQLConversationStore) Save(ctx context.Context, conversation *Conversation) error {
+	if conversation.ID == "" {
+		conversation.ID = uuid.NewString()
+	}
+	now := time.Now()
+	if conversation.CreatedAt.IsZero() {
+		conversation.CreatedAt = now
+	}
+	conversation.UpdatedAt = now
+	conversation.Messages = trimMessages(conversation.Messages, s.policy.MaxMessages)
+
+	messages, err := json.Marshal(conversation.Messages)
+	if err != nil {
+		return fmt.Errorf("failed to serialize conversation: %w", err)
+	}
+	stored, err := encryptString(s.cipher, string(messages))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt conversation: %w", err)
+	}
+	metadata, _ := json.Marshal(conversation.Metadata)
+
+	_, err = s.db.ExecContext(ctx, s.query(`INSERT INTO ai_conversations (id, messages, metadata, message_count, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET messages = excluded.messages, metadata = excluded.metadata,
+		message_count = excluded.message_count, updated_at = excluded.updated_at`),
+		conversation.ID, stored, string(metadata), len(conversation.Messages),
+		conversation.CreatedAt.UnixMilli(), conversation.UpdatedAt.UnixMilli(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save conversation: %w", err)
+	}
+
+	if s.policy.MaxAge > 0 || s.policy.MaxConversations > 0 {
+		if _, err := s.Prune(ctx, s.policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLConversationStore) Load(ctx context.Context, id string) (*Conversation, error) {
+	var messages, metadata sql.NullString
+	var createdAt, updatedAt int64
+	err := s.db.QueryRowContext(ctx, s.query(`SELECT messages, metadata, created_at, updated_at FROM ai_conversations WHERE id = ?`), id).
+		Scan(&messages, &metadata, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrConversationNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation: %w", err)
+	}
+
+	conversation := &Conversation{
+		ID:        id,
+		CreatedAt: time.UnixMilli(createdAt),
+		UpdatedAt: time.UnixMilli(updatedAt),
+	}
+	plaintext, err := decryptString(s.cipher, messages.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt conversation %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(plaintext), &conversation.Messages); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation %s: %w", id, err)
+	}
+	if metadata.Valid {
+		json.Unmarshal([]byte(metadata.String), &conversation.Metadata)
+	}
+	return conversation, nil
+}
+
+func (s *SQLConversationStore) List(ctx context.Context) ([]ConversationInfo, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, metadata, message_count, created_at, updated_at FROM ai_conversations ORDER BY updated_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var list []ConversationInfo
+	for rows.Next() {
+		var info ConversationInfo
+		var metadata sql.NullString
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&info.ID, &metadata, &info.MessageCount, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to list conversations: %w", err)
+		}
+		if metadata.Valid {
+			json.Unmarshal([]byte(metadata.String), &info.Metadata)
+		}
+		info.CreatedAt = time.UnixMilli(createdAt)
+		info.UpdatedAt = time.UnixMilli(updatedAt)
+		list = append(list, info)
+	}
+	return list, rows.Err()
+}
+
+func (s *SQLConversationStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, s.query(`DELETE FROM ai_conversations WHERE id = ?`), id); err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes conversations exceeding policy age/count limits and returns how many were removed
+func (s *SQLConversationStore) Prune(ctx context.Context, policy ConversationPrunePolicy) (int, error) {
+	var removed int64
+	if policy.MaxAge > 0 {
+		result, err := s.db.ExecContext(ctx, s.query(`DELETE FROM ai_conversations WHERE updated_at < ?`),
+			time.Now().Add(-policy.MaxAge).UnixMilli())
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune conversations: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		removed += n
+	}
+	if policy.MaxConversations > 0 {
+		result, err := s.db.ExecContext(ctx, s.query(`DELETE FROM ai_conversations WHERE id NOT IN (
+			SELECT id FROM ai_conversations ORDER BY updated_at DESC, id LIMIT ?)`), policy.MaxConversations)
+		if err != nil {
+			return int(removed), fmt.Errorf("failed to prune conversations: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		removed += n
+	}
+	return int(removed), nil
+}
+
+// Close does nothing: the database handle is owned by the caller
+func (s *SQLConversationStore) Close() error {
+	return nil
+}
+
+// trimMessages keeps system messages and the latest max other messages (max <= 0 = all)
+func trimMessages(messages []Message, max int) []Message {
+	if max <= 0 {
+		return messages
+	}
+	others := 0
+	for _, m := range messages {
+		if m.Role != "system" {
+			others++
+		}
+	}
+	if others <= max {
+		return messages
+	}
+	drop := others - max
+	trimmed := make([]Message, 0, len(messages)-drop)
+	for _, m := range messages {
+		if m.Role != "system" && drop > 0 {
+			drop--
+			continue
+		}
+		trimmed = append(trimmed, m)
+	}
+	return trimmed
+}
+
+// CallConversation continues stored conversation id with userPrompt and saves the answer
+//
+// Unknown IDs start a new conversation, so sessions can be resumed after a restart
+// with the same ID.
+//
+// Usage example:
+//   response, err := client.CallConversation(ctx, store, "trader-42", "How did BTC react to the CPI print?")
+func (client *Client) CallConversation(ctx context.Context, store ConversationStore, id, userPrompt string) (*Response, error) {
+	conversation, err := store.Load(ctx, id)
+	if errors.Is(err, ErrConversationNotFound) {
+		conversation, err = &Conversation{ID: id}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages := append(append([]Message(nil), conversation.Messages...), NewUserMessage(userPrompt))
+	response, err := client.Call(ctx, &Request{Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+
+	conversation.Messages = append(messages, NewAssistantMessage(response.Text))
+	if err := store.Save(ctx, conversation); err != nil {
+		return response, err
+	}
+	return response, nil
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestConversationStore(t *testing.T, policy ConversationPrunePolicy) *SQLConversationStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "conversations.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := NewSQLiteConversationStore(db, policy)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	return store
+}
+
+func TestSQLConversationStore_SaveLoadListDelete(t *testing.T) {
+	ctx := context.Background()
+	store := newTestConversationStore(t, ConversationPrunePolicy{})
+
+	conversation := &Conversation{
+		ID:       "trader-1",
+		Messages: []Message{NewSystemMessage("You are a trader"), NewUserMessage("hi")},
+		Metadata: map[string]string{"symbol": "BTCUSDT"},
+	}
+	if err := store.Save(ctx, conversation); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	conversation.Messages = append(conversation.Messages, NewAssistantMessage("hello"))
+	if err := store.Save(ctx, conversation); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	loaded, err := store.Load(ctx, "trader-1")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(loaded.Messages) != 3 || loaded.Messages[2].Content != "hello" || loaded.Metadata["symbol"] != "BTCUSDT" {
+		t.Errorf("unexpected conversation: %+v", loaded)
+	}
+
+	list, err := store.List(ctx)
+	if err != nil || len(list) != 1 || list[0].MessageCount != 3 {
+		t.Fatalf("unexpected list %+v (err %v)", list, err)
+	}
+
+	if err := store.Delete(ctx, "trader-1"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := store.Load(ctx, "trader-1"); !errors.Is(err, ErrConversationNotFound) {
+		t.Errorf("expected ErrConversationNotFound, got %v", err)
+	}
+}
+
+func TestSQLConversationStore_Pruning(t *testing.T) {
+	ctx := context.Background()
+	store := newTestConversationStore(t, ConversationPrunePolicy{MaxConversations: 2, MaxMessages: 2})
+
+	for _, id := range []string{"a", "b", "c"} {
+		err := store.Save(ctx, &Conversation{ID: id, Messages: []Message{
+			NewSystemMessage("sys"), NewUserMessage("1"), NewAssistantMessage("2"), NewUserMessage("3"),
+		}})
+		if err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	list, _ := store.List(ctx)
+	if len(list) != 2 || list[0].ID != "c" || list[1].ID != "b" {
+		t.Fatalf("expected newest 2 conversations kept, got %+v", list)
+	}
+	loaded, _ := store.Load(ctx, "c")
+	if len(loaded.Messages) != 3 || loaded.Messages[0].Role != "system" || loaded.Messages[1].Content != "2" {
+		t.Errorf("expected system + 2 latest messages, got %+v", loaded.Messages)
+	}
+
+	removed, err := store.Prune(ctx, ConversationPrunePolicy{MaxAge: time.Nanosecond})
+	if err != nil || removed != 2 {
+		t.Errorf("expected 2 conversations pruned by age, got %d (err %v)", removed, err)
+	}
+}
+
+func TestSQLConversationStore_PostgresPlaceholders(t *testing.T) {
+	store := &SQLConversationStore{dialect: DialectPostgres}
+	if got := store.query(`UPDATE t SET a = ? WHERE b = ?`); got != `UPDATE t SET a = $1 WHERE b = $2` {
+		t.Errorf("unexpected query %q", got)
+	}
+}
+
+func TestCallConversation_ResumesHistory(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"noted"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+	ctx := context.Background()
+	store := newTestConversationStore(t, ConversationPrunePolicy{})
+
+	for _, prompt := range []string{"first", "second"} {
+		if _, err := client.CallConversation(ctx, store, "session", prompt); err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+	}
+
+	if messages := bodies[1]["messages"].([]any); len(messages) != 3 {
+		t.Errorf("second call should send history (3 messages), got %d", len(messages))
+	}
+	loaded, _ := store.Load(ctx, "session")
+	if len(loaded.Messages) != 4 || loaded.Messages[3].Content != "noted" {
+		t.Errorf("unexpected stored history %+v", loaded.Messages)
+	}
+}
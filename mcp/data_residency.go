@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ErrNoCompliantEndpoint is returned when no endpoint in an allowed data-residency region is available
+var ErrNoCompliantEndpoint = errors.New("no endpoint in an allowed data-residency region")
+
+// ModelEndpoint endpoint serving a model, tagged with the region data is processed in
+type ModelEndpoint struct {
+	BaseURL string
+	Region  string // e.g. "eu", "us", "apac"
+}
+
+// WithDataResidency only sends prompts to endpoints tagged with one of regions in the model registry
+//
+// Region tags come from ModelInfo.Endpoints. When the configured BaseURL is not tagged
+// with an allowed region, the client switches to a compliant endpoint of its model;
+// when there is none, every call fails with ErrNoCompliantEndpoint (so does
+// NewProviderClient). Requests to untagged hosts are refused at the transport, which
+// also covers embeddings, moderation and redirects. Fallback clients (WithRefusalRetry,
+// StreamSLO.Failover, hedge targets, shadow and decision verifier) that are not on a
+// compliant endpoint are dropped with a warning. For routers see RouterConfig.DataResidency.
+//
+// Usage example:
+//   mcp.RegisterModel(mcp.ModelInfo{Name: "gpt-4o", Provider: "openai", Endpoints: []mcp.ModelEndpoint{
+//       {BaseURL: "https://eu.api.openai.com/v1", Region: "eu"},
+//       {BaseURL: "https://api.openai.com/v1", Region: "us"},
+//   }})
+//   client := mcp.NewOpenAIClientWithOptions(mcp.WithModel("gpt-4o"), mcp.WithDataResidency("eu"))
+func WithDataResidency(regions ...string) ClientOption {
+	return func(c *Config) {
+		c.DataResidency = regions
+	}
+}
+
+// endpointsIn returns endpoints of the model in one of regions
+func (m ModelInfo) endpointsIn(regions []string) []ModelEndpoint {
+	var endpoints []ModelEndpoint
+	for _, endpoint := range m.Endpoints {
+		if regionAllowed(regions, endpoint.Region) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+func regionAllowed(regions []string, region string) bool {
+	return region != "" && slices.ContainsFunc(regions, func(allowed string) bool {
+		return strings.EqualFold(allowed, region)
+	})
+}
+
+// endpointHost returns the lower-cased host (with port) of a base URL
+func endpointHost(baseURL string) string {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Host)
+}
+
+// hostInRegions reports whether a registered endpoint on host is tagged with one of regions
+func hostInRegions(regions []string, host string) bool {
+	host = strings.ToLower(host)
+	if host == "" {
+		return false
+	}
+	modelMu.RLock()
+	defer modelMu.RUnlock()
+	for _, info := range modelRegistry {
+		for _, endpoint := range info.endpointsIn(regions) {
+			if endpointHost(endpoint.BaseURL) == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// residencyCompliant reports whether client only sends requests to endpoints in regions
+//
+// Clients whose endpoint cannot be determined are not compliant.
+func residencyCompliant(client AIClient, regions []string) bool {
+	if router, ok := client.(*RouterClient); ok {
+		return residencyCompliant(router.config.Simple, regions) && residencyCompliant(router.config.Complex, regions)
+	}
+	base, ok := BaseClient(client)
+	if !ok {
+		return false
+	}
+	return base.baseURLErr == nil && hostInRegions(regions, endpointHost(base.BaseURL))
+}
+
+// filterResidencyTargets drops fallback clients outside the allowed regions from cfg
+func filterResidencyTargets(cfg *Config) {
+	regions := cfg.DataResidency
+	if len(regions) == 0 {
+		return
+	}
+	compliant := func(name string, target AIClient) bool {
+		if target == nil || residencyCompliant(target, regions) {
+			return true
+		}
+		if cfg.Logger != nil {
+			cfg.Logger.Warnf("⚠️  [MCP] %s (%T) is not in data-residency regions %v, not using it", name, target, regions)
+		}
+		return false
+	}
+
+	if !compliant("refusal fallback", cfg.RefusalFallback) {
+		cfg.RefusalFallback = nil
+	}
+	if !compliant("shadow", cfg.Shadow) {
+		cfg.Shadow = nil
+	}
+	if !compliant("decision verifier", cfg.DecisionVerifier) {
+		cfg.DecisionVerifier = nil
+	}
+	if cfg.StreamSLO != nil && !compliant("stream failover", cfg.StreamSLO.Failover) {
+		slo := *cfg.StreamSLO
+		slo.Failover = nil
+		cfg.StreamSLO = &slo
+	}
+	if len(cfg.HedgeTargets) > 0 {
+		cfg.HedgeTargets = slices.DeleteFunc(slices.Clone(cfg.HedgeTargets), func(target AIClient) bool {
+			return !compliant("hedge target", target)
+		})
+	}
+}
+
+// resolveResidentEndpoint moves the client to an endpoint in the allowed regions, or fails it closed
+func (client *Client) resolveResidentEndpoint() {
+	regions := client.config.DataResidency
+	if len(regions) == 0 || client.baseURLErr != nil || hostInRegions(regions, endpointHost(client.BaseURL)) {
+		return
+	}
+	if info, ok := LookupModel(client.Model); ok {
+		if endpoints := info.endpointsIn(regions); len(endpoints) > 0 {
+			client.logger.Infof("🌍 [%s] Data residency %v: using %s endpoint %s instead of %s",
+				client.String(), regions, endpoints[0].Region, endpoints[0].BaseURL, client.BaseURL)
+			client.setBaseURL(endpoints[0].BaseURL)
+			return
+		}
+	}
+	client.baseURLErr = fmt.Errorf("%w: %s (model %s) is not tagged with region %s in the model registry",
+		ErrNoCompliantEndpoint, client.BaseURL, client.Model, strings.Join(regions, ", "))
+	client.logger.Warnf("⚠️  [%s] %v", client.String(), client.baseURLErr)
+}
+
+// residencyTransport refuses requests to hosts without an endpoint in the allowed regions
+type residencyTransport struct {
+	next    http.RoundTripper
+	regions []string
+}
+
+func (t *residencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !hostInRegions(t.regions, req.URL.Host) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: refusing to send request to %s", ErrNoCompliantEndpoint, req.URL.Host)
+	}
+	return t.next.RoundTrip(req)
+}
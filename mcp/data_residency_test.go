@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+func registerResidencyTestModels() {
+	RegisterModel(ModelInfo{Name: "residency-model", Provider: "p1", Endpoints: []ModelEndpoint{
+		{BaseURL: "https://eu.residency.example/v1", Region: "eu"},
+		{BaseURL: "https://us.residency.example/v1", Region: "us"},
+	}})
+	RegisterModel(ModelInfo{Name: "residency-us-only", Provider: "p2", Endpoints: []ModelEndpoint{
+		{BaseURL: "https://us-only.residency.example/v1", Region: "us"},
+	}})
+}
+
+func newResidencyClient(mock *MockHTTPClient, model, baseURL string, opts ...ClientOption) *Client {
+	opts = append([]ClientOption{
+		WithProvider(ProviderCustom), WithAPIKey("k"), WithModel(model), WithBaseURL(baseURL),
+		WithHTTPClient(mock.ToHTTPClient()), WithLogger(NewNoopLogger()), WithMaxRetries(1),
+	}, opts...)
+	return NewClient(opts...).(*Client)
+}
+
+func TestDataResidency_SwitchesToCompliantEndpoint(t *testing.T) {
+	registerResidencyTestModels()
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+	client := newResidencyClient(mock, "residency-model", "https://us.residency.example/v1", WithDataResidency("EU"))
+
+	if _, err := client.CallWithMessages("", "hi"); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if host := mock.GetLastRequest().URL.Host; host != "eu.residency.example" {
+		t.Errorf("expected request to the eu endpoint, got %s", host)
+	}
+}
+
+func TestDataResidency_FailsClosed(t *testing.T) {
+	registerResidencyTestModels()
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+
+	client := newResidencyClient(mock, "residency-us-only", "https://us-only.residency.example/v1", WithDataResidency("eu"))
+	if _, err := client.CallWithMessages("", "hi"); !errors.Is(err, ErrNoCompliantEndpoint) {
+		t.Fatalf("expected ErrNoCompliantEndpoint, got %v", err)
+	}
+
+	// An endpoint changed after construction is refused at the transport
+	client = newResidencyClient(mock, "residency-model", "https://eu.residency.example/v1", WithDataResidency("eu"))
+	client.BaseURL = "https://elsewhere.example/v1"
+	if _, err := client.CallWithMessages("", "hi"); !errors.Is(err, ErrNoCompliantEndpoint) {
+		t.Fatalf("expected ErrNoCompliantEndpoint, got %v", err)
+	}
+	if len(mock.GetRequests()) != 0 {
+		t.Errorf("expected no request to leave, got %d", len(mock.GetRequests()))
+	}
+}
+
+func TestDataResidency_DropsNonCompliantFallbacks(t *testing.T) {
+	registerResidencyTestModels()
+	mock := NewMockHTTPClient()
+	eu := newResidencyClient(mock, "residency-model", "https://eu.residency.example/v1")
+	us := newResidencyClient(mock, "residency-model", "https://us.residency.example/v1")
+
+	client := newResidencyClient(mock, "residency-model", "https://eu.residency.example/v1",
+		WithDataResidency("eu"), WithRefusalRetry("", us), WithHedgeTargets(us, eu), WithShadow(us, 1))
+	if client.config.RefusalFallback != nil || client.config.Shadow != nil {
+		t.Error("expected non-compliant refusal fallback and shadow to be dropped")
+	}
+	if len(client.config.HedgeTargets) != 1 || client.config.HedgeTargets[0] != AIClient(eu) {
+		t.Errorf("expected only the eu hedge target, got %v", client.config.HedgeTargets)
+	}
+
+	clone := client.With(WithRefusalRetry("", us)).(*Client)
+	if clone.config.RefusalFallback != nil {
+		t.Error("expected With to drop non-compliant refusal fallback")
+	}
+}
+
+func TestDataResidency_Router(t *testing.T) {
+	registerResidencyTestModels()
+	euMock, usMock := NewMockHTTPClient(), NewMockHTTPClient()
+	euMock.SetSuccessResponse("eu")
+	usMock.SetSuccessResponse("us")
+	eu := newResidencyClient(euMock, "residency-model", "https://eu.residency.example/v1")
+	us := newResidencyClient(usMock, "residency-model", "https://us.residency.example/v1")
+
+	router := NewRouterClient(RouterConfig{Simple: us, Complex: eu, DataResidency: []string{"eu"}})
+	answer, err := router.CallWithMessages("", "BTC price?")
+	if err != nil || answer != "eu" || len(usMock.GetRequests()) != 0 {
+		t.Fatalf("expected simple route to be served by eu, got %q (%v)", answer, err)
+	}
+
+	router = NewRouterClient(RouterConfig{Simple: us, Complex: us, DataResidency: []string{"eu"}})
+	if _, err := router.CallWithMessages("", "BTC price?"); !errors.Is(err, ErrNoCompliantEndpoint) {
+		t.Fatalf("expected ErrNoCompliantEndpoint, got %v", err)
+	}
+}
+
+func TestSelectModel_Regions(t *testing.T) {
+	registerResidencyTestModels()
+	model, err := SelectModel(ModelRequirements{Regions: []string{"eu"}, Providers: []string{"p1", "p2"}})
+	if err != nil || model.Name != "residency-model" {
+		t.Fatalf("expected residency-model, got %s (%v)", model.Name, err)
+	}
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DebugDumper writes raw request/response payloads of every call to a writer
+//
+// Output contains the endpoint chosen by buildUrl, redacted headers, pretty-printed
+// bodies and a timing breakdown (DNS/connect/TLS/TTFB). Response bodies are copied
+// as the caller reads them (streams are not delayed, WithMaxResponseBytes still
+// applies) and the dump is written once the body is read or closed, keeping the first
+// 1 MiB of the body. It can be switched on and off at runtime via Client.SetDebugDump.
+type DebugDumper struct {
+	w       io.Writer
+	mu      sync.Mutex
+	enabled atomic.Bool
+}
+
+// NewDebugDumper creates enabled debug dumper writing to w
+func NewDebugDumper(w io.Writer) *DebugDumper {
+	d := &DebugDumper{w: w}
+	d.enabled.Store(true)
+	return d
+}
+
+// WithDebugDump dumps raw payloads of every call to w
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithDebugDump(os.Stderr))
+func WithDebugDump(w io.Writer) ClientOption {
+	return func(c *Config) {
+		c.DebugDump = NewDebugDumper(w)
+	}
+}
+
+// SetEnabled toggles dumping at runtime
+func (d *DebugDumper) SetEnabled(enabled bool) {
+	d.enabled.Store(enabled)
+}
+
+// Enabled reports whether dumping is on
+func (d *DebugDumper) Enabled() bool {
+	return d.enabled.Load()
+}
+
+// SetDebugDump toggles debug dump at runtime (requires WithDebugDump)
+func (client *Client) SetDebugDump(enabled bool) {
+	if client.config.DebugDump == nil {
+		client.logger.Warnf("⚠️  [%s] Debug dump not configured, use WithDebugDump first", client.String())
+		return
+	}
+	client.config.DebugDump.SetEnabled(enabled)
+}
+
+// wrap wraps next transport with payload dumping
+func (d *DebugDumper) wrap(next http.RoundTripper) http.RoundTripper {
+	return &debugDumpTransport{dumper: d, next: next}
+}
+
+// debugDumpTransport http.RoundTripper dumping payloads
+type debugDumpTransport struct {
+	dumper *DebugDumper
+	next   http.RoundTripper
+}
+
+func (t *debugDumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.dumper.Enabled() {
+		return t.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("debug dump failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	tracker := newLatencyTracker()
+	req = tracker.attach(req)
+
+	resp, err := t.next.RoundTrip(req)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "==================== MCP DEBUG DUMP ====================\n")
+	fmt.Fprintf(&buf, ">>> %s %s\n", req.Method, redactURL(req.URL))
+	writeHeaders(&buf, redactHeaders(req.Header))
+	fmt.Fprintf(&buf, "%s\n", prettyJSON(reqBody))
+
+	if err != nil {
+		fmt.Fprintf(&buf, "<<< ERROR: %v\n", err)
+		t.write(&buf, tracker)
+		return nil, err
+	}
+	fmt.Fprintf(&buf, "<<< %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+	writeHeaders(&buf, redactHeaders(resp.Header))
+	// The body is dumped as the caller reads it, so streams are not held back
+	resp.Body = &debugDumpBody{ReadCloser: resp.Body, transport: t, dump: &buf, tracker: tracker}
+	return resp, nil
+}
+
+// write writes a finished dump with the timing breakdown
+func (t *debugDumpTransport) write(buf *bytes.Buffer, tracker *latencyTracker) {
+	timing := tracker.finish()
+	fmt.Fprintf(buf, "--- timing: dns=%v connect=%v tls=%v ttfb=%v total=%v reused_conn=%v\n",
+		timing.DNS, timing.Connect, timing.TLS, timing.TTFB, timing.Total, timing.ReusedConn)
+
+	t.dumper.mu.Lock()
+	t.dumper.w.Write(buf.Bytes())
+	t.dumper.mu.Unlock()
+}
+
+// debugDumpMaxBody response body bytes included in a dump at most
+const debugDumpMaxBody = 1 << 20
+
+// debugDumpBody response body copying what the caller reads, dumped at EOF or Close
+type debugDumpBody struct {
+	io.ReadCloser
+	transport *debugDumpTransport
+	dump      *bytes.Buffer
+	tracker   *latencyTracker
+	body      bytes.Buffer
+	truncated bool
+	readErr   error
+	written   bool
+}
+
+func (b *debugDumpBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := debugDumpMaxBody - b.body.Len(); n > room {
+		b.body.Write(p[:room])
+		b.truncated = true
+	} else {
+		b.body.Write(p[:n])
+	}
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			b.readErr = err
+		}
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *debugDumpBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+// finish writes the dump once
+func (b *debugDumpBody) finish() {
+	if b.written {
+		return
+	}
+	b.written = true
+	fmt.Fprintf(b.dump, "%s\n", prettyJSON(b.body.Bytes()))
+	if b.truncated {
+		fmt.Fprintf(b.dump, "... (truncated after %d bytes)\n", debugDumpMaxBody)
+	}
+	if b.readErr != nil {
+		fmt.Fprintf(b.dump, "<<< ERROR: failed to read response body: %v\n", b.readErr)
+	}
+	b.transport.write(b.dump, b.tracker)
+}
+
+// writeHeaders writes headers in sorted order
+func writeHeaders(buf *bytes.Buffer, headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(buf, "%s: %s\n", name, strings.Join(headers[name], ", "))
+	}
+}
+
+// prettyJSON indents body if it is JSON, otherwise returns it as-is
+func prettyJSON(body []byte) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		return string(body)
+	}
+	return out.String()
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test Debug Dump
+// ============================================================
+
+func TestDebugDump_WritesRedactedPayloads(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("dumped")
+
+	var out bytes.Buffer
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("sk-very-secret-key"),
+		WithProvider(ProviderCustom),
+		WithBaseURL("https://api.test.com"),
+		WithDebugDump(&out),
+	)
+
+	if _, err := client.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	dump := out.String()
+	if !strings.Contains(dump, "POST https://api.test.com/chat/completions") {
+		t.Errorf("dump should contain endpoint, got:\n%s", dump)
+	}
+	if strings.Contains(dump, "sk-very-secret-key") {
+		t.Error("dump should not contain API key")
+	}
+	if !strings.Contains(dump, `"content": "user"`) {
+		t.Error("dump should contain pretty-printed request body")
+	}
+	if !strings.Contains(dump, "--- timing:") {
+		t.Error("dump should contain timing breakdown")
+	}
+}
+
+func TestDebugDump_ToggleAtRuntime(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("ok")
+
+	var out bytes.Buffer
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithDebugDump(&out),
+	).(*Client)
+
+	client.SetDebugDump(false)
+	client.CallWithMessages("system", "user")
+	if out.Len() != 0 {
+		t.Error("disabled dump should not write output")
+	}
+
+	client.SetDebugDump(true)
+	client.CallWithMessages("system", "user")
+	if out.Len() == 0 {
+		t.Error("enabled dump should write output")
+	}
+}
+
+func TestDebugDump_StreamsBody(t *testing.T) {
+	pr, pw := io.Pipe()
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: pr, Header: make(http.Header)}, nil
+	}
+	var out bytes.Buffer
+	transport := NewDebugDumper(&out).wrap(mockHTTP.ToHTTPClient().Transport)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.test.com/chat/completions", strings.NewReader(`{}`))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	go func() {
+		pw.Write([]byte("data: first\n\n"))
+		pw.Write([]byte("data: second\n\n"))
+		pw.Close()
+	}()
+	first := make([]byte, len("data: first\n\n"))
+	if _, err := io.ReadFull(resp.Body, first); err != nil || out.Len() != 0 {
+		t.Fatalf("body should stream before the dump is written, got %q (%v), dump %q", first, err, out.String())
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if dump := out.String(); !strings.Contains(dump, "data: first\n\ndata: second") || strings.Count(dump, "--- timing:") != 1 {
+		t.Errorf("dump should be written once with the whole body, got:\n%s", dump)
+	}
+}
@@ -65,7 +65,7 @@ func (dsClient *DeepSeekClient) SetAPIKey(apiKey string, customURL string, custo
 		dsClient.logger.Infof("🔧 [MCP] DeepSeek API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
 	}
 	if customURL != "" {
-		dsClient.BaseURL = customURL
+		dsClient.setBaseURL(customURL)
 		dsClient.logger.Infof("🔧 [MCP] DeepSeek using custom BaseURL: %s", customURL)
 	} else {
 		dsClient.logger.Infof("🔧 [MCP] DeepSeek using default BaseURL: %s", dsClient.BaseURL)
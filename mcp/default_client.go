@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNoDefaultClient no default client is set and none could be created from the environment
+var ErrNoDefaultClient = errors.New("no default AI client")
+
+var (
+	defaultMu     sync.RWMutex
+	defaultClient AIClient
+)
+
+// SetDefault sets the client used by the top-level Chat and Embed functions
+//
+// Meant for small tools and scripts; services should pass clients explicitly.
+//
+// Usage example:
+//   mcp.SetDefault(mcp.NewDeepSeekClientWithOptions(mcp.WithAPIKey(key)))
+//   answer, err := mcp.Chat(ctx, "You are a trading assistant.", "Summarize BTC today")
+func SetDefault(client AIClient) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultClient = client
+}
+
+// Default returns the default client, creating it from AI_* environment variables on first use
+//
+// Returns nil if none is set and AI_PROVIDER names an unknown provider.
+func Default() AIClient {
+	defaultMu.RLock()
+	client := defaultClient
+	defaultMu.RUnlock()
+	if client != nil {
+		return client
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultClient == nil {
+		provider, opts := EnvOptions()
+		if created, err := NewProviderClient(provider, opts...); err == nil {
+			defaultClient = created
+		}
+	}
+	return defaultClient
+}
+
+// Chat calls the default client with system and user prompts, returning the answer text
+func Chat(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	client := Default()
+	if client == nil {
+		return "", ErrNoDefaultClient
+	}
+	if c, ok := client.(interface {
+		CallMessages(ctx context.Context, systemPrompt, userPrompt string) (*Response, error)
+	}); ok {
+		response, err := c.CallMessages(ctx, systemPrompt, userPrompt)
+		if err != nil {
+			return "", err
+		}
+		return response.Text, nil
+	}
+	return client.CallWithMessages(systemPrompt, userPrompt)
+}
+
+// Embed embeds inputs with the default client (ErrEmbeddingsUnsupported if it can't)
+func Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error) {
+	client := Default()
+	if client == nil {
+		return nil, ErrNoDefaultClient
+	}
+	embedder, ok := client.(Embedder)
+	if !ok {
+		return nil, ErrEmbeddingsUnsupported
+	}
+	return embedder.Embed(ctx, inputs)
+}
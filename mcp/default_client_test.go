@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestDefaultClient_TopLevelAPI(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+
+	SetDefault(&stubClient{answer: "bullish"})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if answer, err := Chat(context.Background(), "system", "BTC?"); err != nil || answer != "bullish" {
+				t.Errorf("expected default client answer, got %q (%v)", answer, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := Embed(context.Background(), []string{"BTC"}); !errors.Is(err, ErrEmbeddingsUnsupported) {
+		t.Errorf("expected ErrEmbeddingsUnsupported, got %v", err)
+	}
+
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+	SetDefault(NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(mock.ToHTTPClient())))
+	if answer, err := Chat(context.Background(), "system", "user"); err != nil || answer != "ok" {
+		t.Errorf("expected response text, got %q (%v)", answer, err)
+	}
+}
+
+func TestDefaultClient_FromEnvironment(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+	SetDefault(nil)
+
+	t.Setenv(EnvProvider, "qwen")
+	t.Setenv(EnvAPIKey, "sk-env-key")
+	if c, ok := BaseClient(Default()); !ok || c.Provider != ProviderQwen || c.APIKey != "sk-env-key" {
+		t.Fatalf("expected client from environment, got %+v", Default())
+	}
+
+	SetDefault(nil)
+	t.Setenv(EnvProvider, "unknown")
+	if _, err := Chat(context.Background(), "system", "user"); !errors.Is(err, ErrNoDefaultClient) {
+		t.Errorf("expected ErrNoDefaultClient, got %v", err)
+	}
+}
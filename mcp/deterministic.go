@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"encoding/json"
+)
+
+// DefaultSeed sampling seed sent by WithDeterministic
+const DefaultSeed = 42
+
+// seedProviders providers accepting a sampling seed (OpenAI-compatible "seed")
+var seedProviders = map[string]bool{
+	ProviderOpenAI:      true,
+	ProviderQwen:        true,
+	ProviderGrok:        true,
+	ProviderGemini:      true,
+	ProviderCustom:      true,
+	ProviderHuggingFace: true,
+}
+
+// WithDeterministic makes outputs as reproducible as the provider allows (e.g. for backtests)
+//
+// Every request is sent with temperature 0 and, where supported, a fixed seed
+// (DefaultSeed unless WithSeed is given). Responses carry the provider's
+// system_fingerprint; a warning is logged when it changes between calls, and at
+// construction when the provider cannot take a seed. Even with a seed, providers only
+// promise best-effort determinism.
+//
+// Usage example:
+//   client := mcp.NewOpenAIClientWithOptions(mcp.WithDeterministic())
+//   response, _ := client.(*mcp.OpenAIClient).Call(ctx, request)
+//   log.Printf("model %s fingerprint %s", response.Model, response.SystemFingerprint)
+func WithDeterministic() ClientOption {
+	return func(c *Config) {
+		c.Deterministic = true
+		if c.Seed == 0 {
+			c.Seed = DefaultSeed
+		}
+	}
+}
+
+// WithSeed sets sampling seed sent to providers that support it (0 = none)
+func WithSeed(seed int64) ClientOption {
+	return func(c *Config) {
+		c.Seed = seed
+	}
+}
+
+// applySampling adds seed and deterministic sampling parameters to request body
+func (client *Client) applySampling(requestBody map[string]any) {
+	if client.config.Seed != 0 && seedProviders[client.Provider] {
+		requestBody["seed"] = client.config.Seed
+	}
+	if !client.config.Deterministic {
+		return
+	}
+	// Extended thinking doesn't allow modified temperature
+	if _, thinking := requestBody["thinking"]; !thinking {
+		requestBody["temperature"] = 0
+	}
+}
+
+// warnNondeterministic warns once at construction when deterministic mode cannot be honored
+func (client *Client) warnNondeterministic() {
+	if !client.config.Deterministic || seedProviders[client.Provider] {
+		return
+	}
+	client.logger.Warnf("⚠️  [%s] Deterministic mode: provider %s does not support a sampling seed, outputs may still vary at temperature 0",
+		client.String(), client.Provider)
+}
+
+// observeFingerprint warns when the provider backend changed between deterministic calls
+func (client *Client) observeFingerprint(response *Response) {
+	if !client.config.Deterministic || response.SystemFingerprint == "" {
+		return
+	}
+	client.lastMu.Lock()
+	previous := client.lastFingerprint
+	client.lastFingerprint = response.SystemFingerprint
+	client.lastMu.Unlock()
+
+	if previous != "" && previous != response.SystemFingerprint {
+		client.logger.Warnf("⚠️  [%s] Deterministic mode: system fingerprint changed from %s to %s (model %s), outputs may differ from earlier runs",
+			client.String(), previous, response.SystemFingerprint, response.Model)
+	}
+}
+
+// parseSystemFingerprint extracts system_fingerprint from an OpenAI-compatible response body
+func parseSystemFingerprint(body []byte) string {
+	var response struct {
+		SystemFingerprint string `json:"system_fingerprint"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	return response.SystemFingerprint
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithDeterministic_RequestParams(t *testing.T) {
+	tests := []struct {
+		provider string
+		wantSeed bool
+	}{
+		{ProviderCustom, true},
+		{ProviderClaude, false},
+		{ProviderDeepSeek, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			var bodies []map[string]any
+			logger := NewMockLogger()
+			client, err := NewProviderClient(tt.provider, WithBaseURL("https://api.example.com/v1"), WithAPIKey("k"),
+				WithTemperature(0.9), WithDeterministic(), WithLogger(logger),
+				WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"}}],"content":[{"type":"text","text":"ok"}]}`, &bodies)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.CallWithMessages("sys", "hi"); err != nil {
+				t.Fatalf("call: %v", err)
+			}
+			if bodies[0]["temperature"] != float64(0) {
+				t.Errorf("expected temperature 0, got %v", bodies[0]["temperature"])
+			}
+			if _, ok := bodies[0]["seed"]; ok != tt.wantSeed {
+				t.Errorf("seed sent = %v, want %v", ok, tt.wantSeed)
+			}
+			if warned := warningCount(logger, "does not support a sampling seed") > 0; warned == tt.wantSeed {
+				t.Errorf("unexpected warning state for %s: %v", tt.provider, logger.GetLogsByLevel("WARN"))
+			}
+		})
+	}
+}
+
+func TestWithDeterministic_FingerprintChange(t *testing.T) {
+	logger := NewMockLogger()
+	fingerprints := []string{"fp_a", "fp_a", "fp_b"}
+	mock := NewMockHTTPClient()
+	calls := 0
+	mock.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body := `{"model":"gpt-test-0613","system_fingerprint":"` + fingerprints[calls] + `","choices":[{"message":{"content":"ok"}}]}`
+		calls++
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL("https://api.example.com/v1"), WithAPIKey("k"),
+		WithDeterministic(), WithSeed(7), WithLogger(logger), WithHTTPClient(mock.ToHTTPClient())).(*Client)
+
+	for i := range fingerprints {
+		response, err := client.CallMessages(t.Context(), "sys", "hi")
+		if err != nil {
+			t.Fatalf("call: %v", err)
+		}
+		if response.SystemFingerprint != fingerprints[i] || response.Model != "gpt-test-0613" {
+			t.Errorf("unexpected fingerprint %q model %q", response.SystemFingerprint, response.Model)
+		}
+	}
+	if n := warningCount(logger, "system fingerprint changed from fp_a to fp_b"); n != 1 {
+		t.Errorf("expected one fingerprint warning, got %d: %v", n, logger.GetLogsByLevel("WARN"))
+	}
+}
+
+// warningCount returns number of warnings containing text
+func warningCount(logger *MockLogger, text string) int {
+	n := 0
+	for _, entry := range logger.GetLogsByLevel("WARN") {
+		if strings.Contains(entry.Message, text) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+package mcp
+
+const (
+	ProviderDoubao          = "doubao"
+	DefaultDoubaoBaseURL    = "https://ark.cn-beijing.volces.com/api/v3"
+	DefaultDoubaoModel      = "doubao-seed-1-6-250615"
+	DefaultDoubaoEmbedModel = "doubao-embedding-text-240715"
+	DefaultVolcengineRegion = "cn-beijing"
+)
+
+type DoubaoClient struct {
+	*Client
+}
+
+// NewDoubaoClient creates Volcengine Doubao (Ark) client (backward compatible)
+func NewDoubaoClient() AIClient {
+	return NewDoubaoClientWithOptions()
+}
+
+// NewDoubaoClientWithOptions creates Doubao client (supports options pattern)
+//
+// Volcengine Ark serves an OpenAI-compatible API. It authenticates with an Ark API
+// key, or with Volcengine access keys signed per request (WithVolcengineCredentials).
+// Models can also be given as inference endpoint IDs ("ep-2025...").
+//
+// Usage example:
+//   client := mcp.NewDoubaoClientWithOptions(mcp.WithVolcengineCredentials(ak, sk))
+//   answer, err := client.CallWithMessages(systemPrompt, userPrompt)
+func NewDoubaoClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Doubao preset options
+	doubaoOpts := []ClientOption{
+		WithProvider(ProviderDoubao),
+		WithModel(DefaultDoubaoModel),
+		WithBaseURL(DefaultDoubaoBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(doubaoOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create Doubao client
+	doubaoClient := &DoubaoClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to DoubaoClient (implement dynamic dispatch)
+	baseClient.hooks = doubaoClient
+
+	return doubaoClient
+}
+
+// WithVolcengineCredentials signs Ark requests with Volcengine access keys (AK/SK) instead of an API key
+func WithVolcengineCredentials(accessKey, secretKey string) ClientOption {
+	return WithCloudSigner(&VolcengineSigner{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Region:    DefaultVolcengineRegion,
+		Service:   "ark",
+	})
+}
+
+func (c *DoubaoClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] Doubao API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Doubao using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Doubao using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] Doubao using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] Doubao using default Model: %s", c.Model)
+	}
+}
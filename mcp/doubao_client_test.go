@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test DoubaoClient
+// ============================================================
+
+func TestDoubaoClient_APIKey(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Hold BTC.")
+	client := NewDoubaoClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("ark-test-key"),
+		WithLogger(NewNoopLogger()),
+	)
+
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	req := mockHTTP.GetLastRequest()
+	if req.URL.String() != DefaultDoubaoBaseURL+"/chat/completions" || req.Header.Get("Authorization") != "Bearer ark-test-key" {
+		t.Errorf("unexpected request %s %v", req.URL, req.Header)
+	}
+}
+
+func TestDoubaoClient_VolcengineCredentials(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Hold BTC.")
+	client := NewDoubaoClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithVolcengineCredentials("AKLT-test", "secret"),
+		WithLogger(NewNoopLogger()),
+	)
+
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	req := mockHTTP.GetLastRequest()
+	if auth := req.Header.Get("Authorization"); !strings.HasPrefix(auth, "HMAC-SHA256 Credential=AKLT-test/") || !strings.Contains(auth, "/cn-beijing/ark/request") {
+		t.Errorf("expected Volcengine signature, got %q", auth)
+	}
+	if req.Header.Get("X-Date") == "" {
+		t.Error("X-Date header not set")
+	}
+}
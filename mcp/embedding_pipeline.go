@@ -0,0 +1,359 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Embedding pipeline defaults
+const (
+	DefaultEmbeddingBatchSize   = 100 // Inputs per provider request
+	DefaultEmbeddingConcurrency = 4   // Provider requests in flight
+)
+
+// EmbeddingCache stores full-size embedding vectors by content hash
+type EmbeddingCache interface {
+	Get(key string) ([]float64, bool)
+	Put(key string, vector []float64)
+}
+
+// MemoryEmbeddingCache in-process EmbeddingCache (default)
+type MemoryEmbeddingCache struct {
+	mu      sync.RWMutex
+	vectors map[string][]float64
+}
+
+// NewMemoryEmbeddingCache creates empty in-memory embedding cache
+func NewMemoryEmbeddingCache() *MemoryEmbeddingCache {
+	return &MemoryEmbeddingCache{vectors: make(map[string][]float64)}
+}
+
+func (c *MemoryEmbeddingCache) Get(key string) ([]float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	vector, ok := c.vectors[key]
+	return vector, ok
+}
+
+func (c *MemoryEmbeddingCache) Put(key string, vector []float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.vectors[key] = vector
+}
+
+// EmbeddingPipelineConfig embedding pipeline configuration
+type EmbeddingPipelineConfig struct {
+	Embedder    Embedder       // Underlying embedder, e.g. a Client (required)
+	Namespace   string         // Mixed into cache keys, e.g. the embedding model name
+	Cache       EmbeddingCache // Vectors by content hash (default: MemoryEmbeddingCache)
+	BatchSize   int            // Inputs per provider request (default 100)
+	Concurrency int            // Provider requests in flight (default 4)
+
+	// Dimensionality reduction (applied after caching, so cached vectors stay full size)
+	Dimensions int  // Matryoshka truncation to this many dimensions, renormalized (0 = keep)
+	Projection *PCA // PCA projection applied instead of truncation (nil = none)
+}
+
+// EmbeddingPipelineStats cumulative pipeline counters
+type EmbeddingPipelineStats struct {
+	Inputs    int64 // Inputs requested
+	Duplicate int64 // Inputs repeated within a call
+	CacheHits int64 // Unique inputs served from the cache
+	Embedded  int64 // Unique inputs sent to the embedder
+	Batches   int64 // Embedder requests made
+}
+
+// EmbeddingPipeline embeds inputs with deduplication, caching, batching and dimensionality reduction
+//
+// Repeated inputs are embedded once, known inputs come from the cache keyed by
+// content hash, the rest is split into provider-sized batches embedded
+// concurrently. Vectors can be shortened before storage: Matryoshka truncation
+// for models trained for it (OpenAI text-embedding-3), PCA for any model.
+// EmbeddingPipeline is itself an Embedder.
+//
+// Usage example:
+//   pipeline := mcp.NewEmbeddingPipeline(mcp.EmbeddingPipelineConfig{
+//       Embedder:   client,
+//       Namespace:  "text-embedding-3-large",
+//       Dimensions: 256,
+//   })
+//   result, err := pipeline.Embed(ctx, headlines)
+type EmbeddingPipeline struct {
+	config EmbeddingPipelineConfig
+
+	inputs, duplicate, cacheHits, embedded, batches atomic.Int64
+}
+
+// NewEmbeddingPipeline creates embedding pipeline
+func NewEmbeddingPipeline(config EmbeddingPipelineConfig) *EmbeddingPipeline {
+	if config.Cache == nil {
+		config.Cache = NewMemoryEmbeddingCache()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultEmbeddingBatchSize
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultEmbeddingConcurrency
+	}
+	return &EmbeddingPipeline{config: config}
+}
+
+// Embed returns one (reduced) vector per input, in input order
+func (p *EmbeddingPipeline) Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error) {
+	p.inputs.Add(int64(len(inputs)))
+
+	// Deduplicate and look up the cache
+	vectors := make(map[string][]float64, len(inputs))
+	var missing []string
+	for _, input := range inputs {
+		if _, seen := vectors[input]; seen {
+			p.duplicate.Add(1)
+			continue
+		}
+		vector, ok := p.config.Cache.Get(p.cacheKey(input))
+		if ok {
+			p.cacheHits.Add(1)
+		} else {
+			missing = append(missing, input)
+		}
+		vectors[input] = vector
+	}
+
+	response := &EmbeddingResponse{Vectors: make([][]float64, len(inputs))}
+	if len(missing) > 0 {
+		if err := p.embedMissing(ctx, missing, vectors, response); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, input := range inputs {
+		response.Vectors[i] = p.reduce(vectors[input])
+	}
+	return response, nil
+}
+
+// Stats returns cumulative pipeline counters
+func (p *EmbeddingPipeline) Stats() EmbeddingPipelineStats {
+	return EmbeddingPipelineStats{
+		Inputs:    p.inputs.Load(),
+		Duplicate: p.duplicate.Load(),
+		CacheHits: p.cacheHits.Load(),
+		Embedded:  p.embedded.Load(),
+		Batches:   p.batches.Load(),
+	}
+}
+
+// embedMissing embeds inputs in concurrent batches, storing vectors and usage
+func (p *EmbeddingPipeline) embedMissing(ctx context.Context, inputs []string, vectors map[string][]float64, response *EmbeddingResponse) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		slots    = make(chan struct{}, p.config.Concurrency)
+	)
+	for start := 0; start < len(inputs); start += p.config.BatchSize {
+		batch := inputs[start:min(start+p.config.BatchSize, len(inputs))]
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			p.batches.Add(1)
+			result, err := p.config.Embedder.Embed(ctx, batch)
+			if err == nil && len(result.Vectors) != len(batch) {
+				err = fmt.Errorf("expected %d embeddings, got %d", len(batch), len(result.Vectors))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("embedding batch failed: %w", err)
+					cancel()
+				}
+				return
+			}
+			for i, input := range batch {
+				vectors[input] = result.Vectors[i]
+				p.config.Cache.Put(p.cacheKey(input), result.Vectors[i])
+			}
+			p.embedded.Add(int64(len(batch)))
+			response.Model = result.Model
+			response.Usage.Provider, response.Usage.Model = result.Usage.Provider, result.Usage.Model
+			response.Usage.PromptTokens += result.Usage.PromptTokens
+			response.Usage.TotalTokens += result.Usage.TotalTokens
+		}()
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	return firstErr
+}
+
+// cacheKey content hash of input within the namespace
+func (p *EmbeddingPipeline) cacheKey(input string) string {
+	sum := sha256.Sum256([]byte(p.config.Namespace + "\x00" + input))
+	return hex.EncodeToString(sum[:])
+}
+
+// reduce applies the configured dimensionality reduction to vector
+func (p *EmbeddingPipeline) reduce(vector []float64) []float64 {
+	switch {
+	case p.config.Projection != nil:
+		return p.config.Projection.Transform(vector)
+	case p.config.Dimensions > 0 && p.config.Dimensions < len(vector):
+		return normalizeVector(append([]float64(nil), vector[:p.config.Dimensions]...))
+	}
+	return vector
+}
+
+// normalizeVector scales vector to unit length in place
+func normalizeVector(vector []float64) []float64 {
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm = math.Sqrt(norm); norm > 0 {
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
+
+// ============================================================
+// PCA Projection
+// ============================================================
+
+// PCA linear projection onto the principal components of a sample of vectors
+type PCA struct {
+	Mean       []float64   // Sample mean subtracted before projecting
+	Components [][]float64 // Unit principal axes, most variance first
+}
+
+// FitPCA computes the top dims principal components of sample
+//
+// Components are found by power iteration with deflation on the covariance
+// matrix, which is accurate enough for embedding compression and needs no
+// linear algebra dependency. Fit on a representative sample of stored vectors.
+//
+// Usage example:
+//   pca, err := mcp.FitPCA(sample.Vectors, 128)
+//   pipeline := mcp.NewEmbeddingPipeline(mcp.EmbeddingPipelineConfig{Embedder: client, Projection: pca})
+func FitPCA(sample [][]float64, dims int) (*PCA, error) {
+	if len(sample) < 2 {
+		return nil, fmt.Errorf("PCA needs at least 2 sample vectors, got %d", len(sample))
+	}
+	size := len(sample[0])
+	if dims <= 0 || dims > size {
+		return nil, fmt.Errorf("PCA dimensions %d out of range 1-%d", dims, size)
+	}
+
+	mean := make([]float64, size)
+	for _, vector := range sample {
+		if len(vector) != size {
+			return nil, fmt.Errorf("PCA sample vectors differ in size (%d and %d)", size, len(vector))
+		}
+		for i, v := range vector {
+			mean[i] += v / float64(len(sample))
+		}
+	}
+	covariance := make([][]float64, size)
+	for i := range covariance {
+		covariance[i] = make([]float64, size)
+	}
+	for _, vector := range sample {
+		for i := range size {
+			di := vector[i] - mean[i]
+			for j := i; j < size; j++ {
+				covariance[i][j] += di * (vector[j] - mean[j])
+			}
+		}
+	}
+	for i := range size {
+		for j := i; j < size; j++ {
+			covariance[i][j] /= float64(len(sample) - 1)
+			covariance[j][i] = covariance[i][j]
+		}
+	}
+
+	pca := &PCA{Mean: mean}
+	for range dims {
+		component, eigenvalue := powerIteration(covariance)
+		pca.Components = append(pca.Components, component)
+		for i := range size {
+			for j := range size {
+				covariance[i][j] -= eigenvalue * component[i] * component[j]
+			}
+		}
+	}
+	return pca, nil
+}
+
+// Transform projects vector onto the principal components
+func (p *PCA) Transform(vector []float64) []float64 {
+	projected := make([]float64, len(p.Components))
+	for c, component := range p.Components {
+		for i, v := range vector {
+			if i < len(component) {
+				projected[c] += (v - p.Mean[i]) * component[i]
+			}
+		}
+	}
+	return projected
+}
+
+// powerIteration returns the dominant unit eigenvector of symmetric matrix and its eigenvalue
+func powerIteration(matrix [][]float64) ([]float64, float64) {
+	size := len(matrix)
+	vector := make([]float64, size)
+	for i := range vector {
+		vector[i] = 1 / math.Sqrt(float64(size)+float64(i)) // Deterministic, not orthogonal to typical axes
+	}
+	normalizeVector(vector)
+
+	var eigenvalue float64
+	next := make([]float64, size)
+	for range 200 {
+		for i := range size {
+			next[i] = 0
+			for j := range size {
+				next[i] += matrix[i][j] * vector[j]
+			}
+		}
+		var norm, delta float64
+		for _, v := range next {
+			norm += v * v
+		}
+		if norm = math.Sqrt(norm); norm == 0 {
+			return vector, 0
+		}
+		for i := range size {
+			next[i] /= norm
+			delta += math.Abs(next[i] - vector[i])
+		}
+		vector, next, eigenvalue = next, vector, norm
+		if delta < 1e-10 {
+			break
+		}
+	}
+	return append([]float64(nil), vector...), eigenvalue
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+// countingEmbedder embeds text as [len, 1, 0, 0] and records batches
+type countingEmbedder struct {
+	mu      sync.Mutex
+	batches [][]string
+	fail    bool
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.fail {
+		return nil, errors.New("rate limited")
+	}
+	e.batches = append(e.batches, inputs)
+	result := &EmbeddingResponse{Model: "test-embed", Usage: TokenUsage{PromptTokens: len(inputs), TotalTokens: len(inputs)}}
+	for _, input := range inputs {
+		result.Vectors = append(result.Vectors, []float64{float64(len(input)), 1, 0, 0})
+	}
+	return result, nil
+}
+
+func TestEmbeddingPipeline_DedupesCachesAndBatches(t *testing.T) {
+	embedder := &countingEmbedder{}
+	pipeline := NewEmbeddingPipeline(EmbeddingPipelineConfig{Embedder: embedder, BatchSize: 2, Concurrency: 2})
+
+	result, err := pipeline.Embed(context.Background(), []string{"a", "bb", "a", "ccc", "dddd", "bb"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(result.Vectors) != 6 || result.Vectors[2][0] != 1 || result.Vectors[5][0] != 2 {
+		t.Errorf("vectors not in input order: %v", result.Vectors)
+	}
+	if len(embedder.batches) != 2 {
+		t.Errorf("expected 4 unique inputs in 2 batches, got %v", embedder.batches)
+	}
+	if result.Usage.TotalTokens != 4 || result.Model != "test-embed" {
+		t.Errorf("unexpected usage %+v model %s", result.Usage, result.Model)
+	}
+
+	if _, err := pipeline.Embed(context.Background(), []string{"bb", "eeeee"}); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if last := embedder.batches[len(embedder.batches)-1]; len(last) != 1 || last[0] != "eeeee" {
+		t.Errorf("cached input re-embedded: %v", last)
+	}
+	stats := pipeline.Stats()
+	if stats.Inputs != 8 || stats.Duplicate != 2 || stats.CacheHits != 1 || stats.Embedded != 5 || stats.Batches != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestEmbeddingPipeline_Errors(t *testing.T) {
+	pipeline := NewEmbeddingPipeline(EmbeddingPipelineConfig{Embedder: &countingEmbedder{fail: true}})
+	if _, err := pipeline.Embed(context.Background(), []string{"a"}); err == nil {
+		t.Error("expected embedder error")
+	}
+}
+
+func TestEmbeddingPipeline_MatryoshkaTruncation(t *testing.T) {
+	pipeline := NewEmbeddingPipeline(EmbeddingPipelineConfig{Embedder: &countingEmbedder{}, Dimensions: 2})
+	result, err := pipeline.Embed(context.Background(), []string{"abc"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	vector := result.Vectors[0]
+	if len(vector) != 2 || math.Abs(vector[0]*vector[0]+vector[1]*vector[1]-1) > 1e-9 {
+		t.Errorf("expected unit vector of 2 dimensions, got %v", vector)
+	}
+}
+
+func TestFitPCA(t *testing.T) {
+	// Points spread along (1, 1, 0) with little noise elsewhere
+	sample := [][]float64{{1, 1, 0.1}, {2, 2, -0.1}, {3, 3, 0}, {-1, -1, 0.05}, {-2, -2, -0.05}}
+	pca, err := FitPCA(sample, 1)
+	if err != nil {
+		t.Fatalf("FitPCA failed: %v", err)
+	}
+	axis := pca.Components[0]
+	if math.Abs(math.Abs(axis[0])-math.Sqrt2/2) > 1e-3 || math.Abs(math.Abs(axis[1])-math.Sqrt2/2) > 1e-3 {
+		t.Errorf("expected principal axis along (1,1,0), got %v", axis)
+	}
+	if projected := pca.Transform([]float64{3, 3, 0}); len(projected) != 1 {
+		t.Errorf("expected 1 dimension, got %v", projected)
+	}
+
+	if _, err := FitPCA(sample[:1], 1); err == nil {
+		t.Error("single vector sample should fail")
+	}
+	if _, err := FitPCA(sample, 4); err == nil {
+		t.Error("too many dimensions should fail")
+	}
+}
@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrEmbeddingsUnsupported is returned by Embed for providers without an embeddings API
+var ErrEmbeddingsUnsupported = errors.New("provider has no embeddings API")
+
+// defaultEmbeddingModels embedding model used per provider when none is configured
+var defaultEmbeddingModels = map[string]string{
+	ProviderOpenAI:      "text-embedding-3-small",
+	ProviderQwen:        "text-embedding-v3",
+	ProviderGemini:      "text-embedding-004",
+	ProviderCohere:      DefaultCohereEmbedModel,
+	ProviderVoyage:      DefaultVoyageEmbedModel,
+	ProviderCloudflare:  DefaultCloudflareEmbedModel,
+	ProviderDoubao:      DefaultDoubaoEmbedModel,
+	ProviderSiliconFlow: DefaultSiliconFlowEmbedModel,
+}
+
+// EmbeddingResponse embedding vectors with metadata
+type EmbeddingResponse struct {
+	Vectors [][]float64 // One vector per input, in input order
+	Model   string
+	Usage   TokenUsage
+}
+
+// WithEmbeddingModel sets model used by Embed (default: provider's standard embedding model)
+//
+// Usage example:
+//   client := mcp.NewOpenAIClientWithOptions(mcp.WithEmbeddingModel("text-embedding-3-large"))
+func WithEmbeddingModel(model string) ClientOption {
+	return func(c *Config) {
+		c.EmbeddingModel = model
+	}
+}
+
+// Embed returns embedding vectors of inputs via the OpenAI-compatible /embeddings endpoint
+//
+// Usage example:
+//   result, err := client.Embed(ctx, []string{"BTC breaks resistance", "ETH funding turns negative"})
+//   if err == nil {
+//       similarity := cosine(result.Vectors[0], result.Vectors[1])
+//   }
+func (client *Client) Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+	if client.Provider == ProviderClaude {
+		return nil, ErrEmbeddingsUnsupported
+	}
+	model := client.config.EmbeddingModel
+	if model == "" {
+		model = defaultEmbeddingModels[client.Provider]
+	}
+	if model == "" {
+		return nil, fmt.Errorf("no embedding model for provider %q, use WithEmbeddingModel", client.Provider)
+	}
+	if len(inputs) == 0 {
+		return &EmbeddingResponse{Model: model}, nil
+	}
+	if client.Provider == ProviderCohere {
+		return client.embedCohere(ctx, model, inputs)
+	}
+	body, requestID, err := client.postAPI(ctx, EndpointEmbeddings, map[string]any{"model": model, "input": inputs})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, withRequestID(fmt.Errorf("failed to parse embedding response: %w", err), requestID)
+	}
+	if len(result.Data) != len(inputs) {
+		return nil, withRequestID(fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(result.Data)), requestID)
+	}
+
+	response := &EmbeddingResponse{
+		Vectors: make([][]float64, len(inputs)),
+		Model:   result.Model,
+		Usage:   parseUsage(body),
+	}
+	for i, item := range result.Data {
+		index := item.Index
+		if index < 0 || index >= len(inputs) {
+			index = i
+		}
+		response.Vectors[index] = item.Embedding
+	}
+	if response.Model == "" {
+		response.Model = model
+	}
+	response.Usage.Provider = client.Provider
+	response.Usage.Model = response.Model
+	return response, nil
+}
+
+// postAPI posts payload as JSON to an OpenAI-compatible endpoint, returning the response body
+//
+// Errors are annotated with the request ID, which is also returned for errors found in the body.
+func (client *Client) postAPI(ctx context.Context, endpoint string, payload any) ([]byte, string, error) {
+	if err := client.lifecycle.begin(); err != nil {
+		return nil, "", err
+	}
+	defer client.lifecycle.end()
+
+	ctx, requestID := client.ensureRequestID(ctx)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, requestID, fmt.Errorf("failed to serialize request: %w", err)
+	}
+	if client.baseURLErr != nil {
+		return nil, requestID, client.baseURLErr
+	}
+	url := withExtraQuery(client.endpointURL(endpoint), client.config.ExtraQuery)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, requestID, fmt.Errorf("fail to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client.hooks.setAuthHeader(req.Header)
+	client.setClientHeaders(req.Header)
+	client.setRequestIDHeaders(ctx, req)
+	client.setTenantHeader(ctx, req)
+	if err := client.config.RateLimiter.Wait(ctx); err != nil {
+		return nil, requestID, withRequestID(fmt.Errorf("rate limiter: %w", err), requestID)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, requestID, withRequestID(fmt.Errorf("failed to send request: %w", err), requestID)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(limitBody(resp.Body, client.config.MaxResponseBytes))
+	if err != nil {
+		return nil, requestID, withRequestID(fmt.Errorf("failed to read response: %w", err), requestID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, requestID, withRequestID(client.newAPIError(resp.StatusCode, body), requestID)
+	}
+	return body, requestID, nil
+}
@@ -0,0 +1,373 @@
+package mcp
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// encryptedPrefix marks values encrypted by a ContentCipher (unprefixed values are plaintext)
+	encryptedPrefix = "enc:"
+	// encryptedV1 values sealed without associated data (read only)
+	encryptedV1 = "enc:v1:"
+	// encryptedV2 values whose header (version and key) is bound to the ciphertext as associated data
+	encryptedV2 = "enc:v2:"
+)
+
+// DefaultDataKeyRotation how long KMSCipher encrypts with one data key
+const DefaultDataKeyRotation = 24 * time.Hour
+
+// DefaultKMSKeyCacheEntries unwrapped data keys a KMSCipher keeps in memory
+const DefaultKMSKeyCacheEntries = 1024
+
+// ErrContentDecrypt is returned when stored content cannot be decrypted (unknown key, tampering)
+var ErrContentDecrypt = errors.New("failed to decrypt content")
+
+// ContentCipher encrypts prompts and responses before they are stored
+//
+// Encrypted values are printable tokens prefixed "enc:", safe in JSON and TEXT
+// columns. Stored values without the prefix are read as plaintext, so stores written
+// before encryption was enabled stay readable.
+type ContentCipher interface {
+	Encrypt(plaintext []byte) (string, error)
+	Decrypt(token string) ([]byte, error)
+}
+
+// WithContentEncryption encrypts prompts and responses written to the audit sink and recorder cassettes
+//
+// Message contents and tool call arguments, response text, reasoning and errors of
+// audit records are encrypted (hashes, usage and cost stay readable for reports);
+// cassettes keep their request and response bodies encrypted and are decrypted on
+// replay. Read them back with DecryptAuditRecord and Cassette.Decrypt. For
+// conversations see SQLConversationStore.WithCipher, for jobs JobRunnerConfig.Cipher.
+//
+// Usage example:
+//   cipher, err := mcp.NewAESGCMCipher(key) // 32 random bytes kept outside the database
+//   client := mcp.NewClient(
+//       mcp.WithAuditSink(sink),
+//       mcp.WithAuditContent(mcp.AuditContentFull),
+//       mcp.WithContentEncryption(cipher),
+//   )
+func WithContentEncryption(cipher ContentCipher) ClientOption {
+	return func(c *Config) {
+		c.ContentCipher = cipher
+	}
+}
+
+// encryptString encrypts text with c (nil cipher or empty text = unchanged)
+func encryptString(c ContentCipher, text string) (string, error) {
+	if c == nil || text == "" {
+		return text, nil
+	}
+	return c.Encrypt([]byte(text))
+}
+
+// transformMessages returns copy of messages with transform applied to contents and tool call arguments
+func transformMessages(messages []Message, transform func(string) (string, error)) ([]Message, error) {
+	out := make([]Message, len(messages))
+	for i, msg := range messages {
+		content, err := transform(msg.Content)
+		if err != nil {
+			return nil, err
+		}
+		msg.Content = content
+		if len(msg.ToolCalls) > 0 {
+			msg.ToolCalls = slices.Clone(msg.ToolCalls)
+			for j := range msg.ToolCalls {
+				if msg.ToolCalls[j].Function.Arguments, err = transform(msg.ToolCalls[j].Function.Arguments); err != nil {
+					return nil, err
+				}
+			}
+		}
+		out[i] = msg
+	}
+	return out, nil
+}
+
+// decryptString decrypts a value written by encryptString (plaintext values are returned as is)
+func decryptString(c ContentCipher, value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("%w: content is encrypted but no cipher is configured", ErrContentDecrypt)
+	}
+	plaintext, err := c.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// splitEncrypted splits a token into its header (prefix up to the last field) and sealed data
+//
+// fields is the number of ":"-separated fields after the version (key ID, ...). v1
+// tokens carry no associated data, so a nil aad is returned for them.
+func splitEncrypted(token string, fields int) (parts []string, aad []byte, err error) {
+	version := encryptedV2
+	if strings.HasPrefix(token, encryptedV1) {
+		version = encryptedV1
+	} else if !strings.HasPrefix(token, encryptedV2) {
+		return nil, nil, fmt.Errorf("%w: malformed value", ErrContentDecrypt)
+	}
+	parts = strings.Split(strings.TrimPrefix(token, version), ":")
+	if len(parts) != fields+1 {
+		return nil, nil, fmt.Errorf("%w: malformed value", ErrContentDecrypt)
+	}
+	if version == encryptedV2 {
+		aad = []byte(token[:len(token)-len(parts[fields])])
+	}
+	return parts, aad, nil
+}
+
+// sealGCM encrypts plaintext as base64(nonce || ciphertext), authenticating aad with it
+func sealGCM(aead cipher.AEAD, plaintext, aad []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, aad)), nil
+}
+
+// openGCM decrypts a value written by sealGCM
+func openGCM(aead cipher.AEAD, sealed string, aad []byte) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: malformed value", ErrContentDecrypt)
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrContentDecrypt, err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// ============================================================
+// AES-GCM with a local key
+// ============================================================
+
+// AESGCMCipher encrypts with AES-GCM under a user-supplied key
+//
+// Values carry an ID of the key that encrypted them, so keys can be rotated: pass the
+// new key first and older keys after it, which are then only used for decryption.
+// The key ID is authenticated with the ciphertext, so it can't be swapped.
+type AESGCMCipher struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewAESGCMCipher creates cipher encrypting with key (16, 24 or 32 bytes) and decrypting with key or previous
+//
+// Usage example:
+//   key, _ := base64.StdEncoding.DecodeString(os.Getenv("AI_ENCRYPTION_KEY"))
+//   cipher, err := mcp.NewAESGCMCipher(key)
+func NewAESGCMCipher(key []byte, previous ...[]byte) (*AESGCMCipher, error) {
+	c := &AESGCMCipher{keys: make(map[string]cipher.AEAD)}
+	for i, k := range append([][]byte{key}, previous...) {
+		aead, err := newGCM(k)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(k)
+		id := hex.EncodeToString(sum[:4])
+		c.keys[id] = aead
+		if i == 0 {
+			c.primary = id
+		}
+	}
+	return c, nil
+}
+
+// Encrypt returns "enc:v2:<key id>:<nonce and ciphertext>"
+func (c *AESGCMCipher) Encrypt(plaintext []byte) (string, error) {
+	header := encryptedV2 + c.primary + ":"
+	sealed, err := sealGCM(c.keys[c.primary], plaintext, []byte(header))
+	if err != nil {
+		return "", err
+	}
+	return header + sealed, nil
+}
+
+// Decrypt decrypts v2 values and v1 values written before associated data was bound
+func (c *AESGCMCipher) Decrypt(token string) ([]byte, error) {
+	parts, aad, err := splitEncrypted(token, 1)
+	if err != nil {
+		return nil, err
+	}
+	aead, ok := c.keys[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %s", ErrContentDecrypt, parts[0])
+	}
+	return openGCM(aead, parts[1], aad)
+}
+
+// ============================================================
+// Envelope encryption with a KMS
+// ============================================================
+
+// KeyWrapper key management service protecting data keys (AWS KMS, Google Cloud KMS, Vault transit, ...)
+//
+// mcp stays free of SDK dependencies: implement it with the service's Encrypt and
+// Decrypt calls.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// KMSCipher envelope encryption: content is encrypted with AES-256-GCM data keys wrapped by a KMS
+//
+// The master key never leaves the KMS. A data key is generated and wrapped once per
+// rotation period and stored, wrapped, in every value it encrypted; the most recent
+// unwrapped keys are cached, so the KMS is called about once per data key rather than
+// per record. KMS calls are made without holding the cipher's lock, so a slow KMS
+// doesn't stall values encrypted with cached keys.
+type KMSCipher struct {
+	kms           KeyWrapper
+	rotate        time.Duration
+	MaxCachedKeys int // Unwrapped data keys kept in memory, oldest evicted first (0 = DefaultKMSKeyCacheEntries)
+
+	mu        sync.Mutex
+	current   cipher.AEAD
+	wrapped   string // Wrapped current data key (base64)
+	createdAt time.Time
+	unwrapped map[string]cipher.AEAD
+	order     []string // Cached wrapped keys by insertion, oldest first
+}
+
+// NewKMSCipher creates envelope cipher using kms, with a new data key every rotate (0 = DefaultDataKeyRotation)
+//
+// Usage example:
+//   cipher := mcp.NewKMSCipher(awsKMSWrapper{client: kmsClient, keyID: "alias/nofx-audit"}, 0)
+//   client := mcp.NewClient(mcp.WithAuditSink(sink), mcp.WithContentEncryption(cipher))
+func NewKMSCipher(kms KeyWrapper, rotate time.Duration) *KMSCipher {
+	if rotate <= 0 {
+		rotate = DefaultDataKeyRotation
+	}
+	return &KMSCipher{kms: kms, rotate: rotate, unwrapped: make(map[string]cipher.AEAD)}
+}
+
+// Encrypt returns "enc:v2:kms:<wrapped data key>:<nonce and ciphertext>"
+func (c *KMSCipher) Encrypt(plaintext []byte) (string, error) {
+	aead, wrapped, err := c.dataKey()
+	if err != nil {
+		return "", err
+	}
+	header := encryptedV2 + "kms:" + wrapped + ":"
+	sealed, err := sealGCM(aead, plaintext, []byte(header))
+	if err != nil {
+		return "", err
+	}
+	return header + sealed, nil
+}
+
+// Decrypt decrypts v2 values and v1 values written before associated data was bound
+func (c *KMSCipher) Decrypt(token string) ([]byte, error) {
+	parts, aad, err := splitEncrypted(token, 2)
+	if err != nil {
+		return nil, err
+	}
+	if parts[0] != "kms" {
+		return nil, fmt.Errorf("%w: malformed value", ErrContentDecrypt)
+	}
+	aead, err := c.unwrap(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return openGCM(aead, parts[2], aad)
+}
+
+// dataKey returns the current data key, generating and wrapping a new one when it is due
+func (c *KMSCipher) dataKey() (cipher.AEAD, string, error) {
+	c.mu.Lock()
+	if c.current != nil && time.Since(c.createdAt) < c.rotate {
+		defer c.mu.Unlock()
+		return c.current, c.wrapped, nil
+	}
+	c.mu.Unlock()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrappedKey, err := c.kms.WrapKey(context.Background(), key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Another call may have rotated while the KMS was wrapping; keep its key then
+	if c.current == nil || time.Since(c.createdAt) >= c.rotate {
+		c.current, c.wrapped, c.createdAt = aead, base64.RawURLEncoding.EncodeToString(wrappedKey), time.Now()
+		c.cache(c.wrapped, aead)
+	}
+	return c.current, c.wrapped, nil
+}
+
+// unwrap returns the data key of a wrapped key, asking the KMS once per key
+func (c *KMSCipher) unwrap(wrapped string) (cipher.AEAD, error) {
+	c.mu.Lock()
+	aead, ok := c.unwrapped[wrapped]
+	c.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+	wrappedKey, err := base64.RawURLEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed data key", ErrContentDecrypt)
+	}
+	key, err := c.kms.UnwrapKey(context.Background(), wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unwrap data key: %v", ErrContentDecrypt, err)
+	}
+	aead, err = newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache(wrapped, aead)
+	return aead, nil
+}
+
+// cache stores an unwrapped data key, evicting the oldest beyond MaxCachedKeys (caller holds mu)
+func (c *KMSCipher) cache(wrapped string, aead cipher.AEAD) {
+	if _, ok := c.unwrapped[wrapped]; ok {
+		return
+	}
+	c.unwrapped[wrapped] = aead
+	c.order = append(c.order, wrapped)
+
+	maxEntries := c.MaxCachedKeys
+	if maxEntries <= 0 {
+		maxEntries = DefaultKMSKeyCacheEntries
+	}
+	for len(c.order) > maxEntries {
+		delete(c.unwrapped, c.order[0])
+		c.order = c.order[1:]
+	}
+}
@@ -0,0 +1,338 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test encryption at rest
+// ============================================================
+
+func testCipher(t *testing.T, key byte, previous ...[]byte) *AESGCMCipher {
+	t.Helper()
+	cipher, err := NewAESGCMCipher(bytes.Repeat([]byte{key}, 32), previous...)
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	return cipher
+}
+
+func TestAESGCMCipher_RoundTripAndRotation(t *testing.T) {
+	old := testCipher(t, 1)
+	token, err := old.Encrypt([]byte("long BTC at 68k"))
+	if err != nil || !strings.HasPrefix(token, encryptedPrefix) || strings.Contains(token, "68k") {
+		t.Fatalf("unexpected token %q (%v)", token, err)
+	}
+
+	rotated := testCipher(t, 2, bytes.Repeat([]byte{1}, 32))
+	if plaintext, err := decryptString(rotated, token); err != nil || plaintext != "long BTC at 68k" {
+		t.Errorf("previous key must still decrypt, got %q (%v)", plaintext, err)
+	}
+	if _, err := decryptString(testCipher(t, 3), token); !errors.Is(err, ErrContentDecrypt) {
+		t.Errorf("unknown key must fail with ErrContentDecrypt, got %v", err)
+	}
+	tampered := token[:len(token)-2] + "AA"
+	if _, err := rotated.Decrypt(tampered); !errors.Is(err, ErrContentDecrypt) {
+		t.Errorf("tampered value must fail with ErrContentDecrypt, got %v", err)
+	}
+	if plaintext, err := decryptString(rotated, "plain text"); err != nil || plaintext != "plain text" {
+		t.Errorf("plaintext must pass through, got %q (%v)", plaintext, err)
+	}
+}
+
+func TestAESGCMCipher_BindsHeader(t *testing.T) {
+	cipher := testCipher(t, 1, bytes.Repeat([]byte{2}, 32))
+	token, _ := cipher.Encrypt([]byte("long BTC at 68k"))
+	if !strings.HasPrefix(token, encryptedV2) {
+		t.Fatalf("new values must use the v2 format, got %q", token)
+	}
+	downgraded := encryptedV1 + strings.TrimPrefix(token, encryptedV2)
+	if _, err := cipher.Decrypt(downgraded); !errors.Is(err, ErrContentDecrypt) {
+		t.Errorf("version must be authenticated, got %v", err)
+	}
+
+	// Values written before associated data was bound stay readable
+	sealed, _ := sealGCM(cipher.keys[cipher.primary], []byte("legacy"), nil)
+	if plaintext, err := cipher.Decrypt(encryptedV1 + cipher.primary + ":" + sealed); err != nil || string(plaintext) != "legacy" {
+		t.Errorf("v1 values must decrypt, got %q (%v)", plaintext, err)
+	}
+}
+
+// xorKeyWrapper test KMS counting its calls
+type xorKeyWrapper struct {
+	wraps, unwraps int
+}
+
+func (w *xorKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	w.wraps++
+	return xorBytes(dataKey), nil
+}
+
+func (w *xorKeyWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	w.unwraps++
+	return xorBytes(wrapped), nil
+}
+
+func xorBytes(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ 0x5a
+	}
+	return out
+}
+
+func TestKMSCipher_EnvelopeEncryption(t *testing.T) {
+	kms := &xorKeyWrapper{}
+	writer := NewKMSCipher(kms, 0)
+	first, _ := writer.Encrypt([]byte("short ETH"))
+	second, _ := writer.Encrypt([]byte("close ETH"))
+	if kms.wraps != 1 {
+		t.Errorf("data key must be wrapped once per rotation, got %d", kms.wraps)
+	}
+
+	reader := NewKMSCipher(kms, 0)
+	for token, want := range map[string]string{first: "short ETH", second: "close ETH"} {
+		if plaintext, err := reader.Decrypt(token); err != nil || string(plaintext) != want {
+			t.Errorf("got %q (%v), want %q", plaintext, err, want)
+		}
+	}
+	if kms.unwraps != 1 {
+		t.Errorf("unwrapped data keys must be cached, got %d unwraps", kms.unwraps)
+	}
+}
+
+func TestContentEncryption_AuditAndConversations(t *testing.T) {
+	cipher := testCipher(t, 7)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path)
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Buy 2 BTC at market")
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithAuditSink(sink),
+		WithAuditContent(AuditContentFull),
+		WithContentEncryption(cipher),
+	)
+	if _, err := client.CallWithMessages("You manage the secret grid strategy", "Position: 5 BTC long"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	sink.Close()
+
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"secret grid", "5 BTC long", "Buy 2 BTC"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("audit log must not contain %q in plaintext: %s", secret, data)
+		}
+	}
+	var record AuditRecord
+	json.Unmarshal(bytes.TrimSpace(data), &record)
+	if err := DecryptAuditRecord(&record, cipher); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if record.Prompt[1].Content != "Position: 5 BTC long" || record.Response != "Buy 2 BTC at market" {
+		t.Errorf("unexpected decrypted record %+v", record)
+	}
+
+	ctx := context.Background()
+	store := newTestConversationStore(t, ConversationPrunePolicy{})
+	plain := &Conversation{ID: "legacy", Messages: []Message{NewUserMessage("old plaintext")}}
+	store.Save(ctx, plain)
+	store.WithCipher(cipher)
+	store.Save(ctx, &Conversation{ID: "trader-1", Messages: []Message{NewUserMessage("Position: 5 BTC long")}})
+
+	var stored string
+	store.db.QueryRow(`SELECT messages FROM ai_conversations WHERE id = 'trader-1'`).Scan(&stored)
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		t.Errorf("messages must be stored encrypted, got %q", stored)
+	}
+	for id, want := range map[string]string{"trader-1": "Position: 5 BTC long", "legacy": "old plaintext"} {
+		conversation, err := store.Load(ctx, id)
+		if err != nil || conversation.Messages[0].Content != want {
+			t.Errorf("load %s: got %+v (%v)", id, conversation, err)
+		}
+	}
+}
+
+func TestContentEncryption_AuditErrorsAndToolCalls(t *testing.T) {
+	cipher := testCipher(t, 7)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path)
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader(`{"error":"cannot size order for 5 BTC long"}`)),
+		}, nil
+	}
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithAuditSink(sink),
+		WithAuditContent(AuditContentFull),
+		WithContentEncryption(cipher),
+	).(*Client)
+	call := ToolCall{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "size_order", Arguments: `{"position":"5 BTC long"}`}}
+	req := &Request{Messages: []Message{
+		NewUserMessage("Size my order"),
+		{Role: "assistant", ToolCalls: []ToolCall{call}},
+		{Role: "tool", Content: "filled", ToolCallID: "call_1"},
+	}}
+	if _, err := client.Call(context.Background(), req); err == nil {
+		t.Fatal("expected call to fail")
+	}
+	sink.Close()
+
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("5 BTC long")) {
+		t.Errorf("audit log must not contain error or tool arguments in plaintext: %s", data)
+	}
+	var record AuditRecord
+	json.Unmarshal(bytes.TrimSpace(data), &record)
+	if err := DecryptAuditRecord(&record, cipher); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if !strings.Contains(record.Error, "5 BTC long") {
+		t.Errorf("expected decrypted error, got %q", record.Error)
+	}
+	if len(record.Prompt) != 3 || len(record.Prompt[1].ToolCalls) != 1 || record.Prompt[1].ToolCalls[0] != call || record.Prompt[2].ToolCallID != "call_1" {
+		t.Errorf("expected tool calls to survive encryption, got %+v", record.Prompt)
+	}
+}
+
+func TestContentEncryption_Jobs(t *testing.T) {
+	cipher := testCipher(t, 8)
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Buy 2 BTC at market")
+	client := NewClient(WithHTTPClient(mockHTTP.ToHTTPClient()), WithLogger(NewNoopLogger()), WithAPIKey("test-key"))
+	store := NewMemoryJobStore()
+	completed := make(chan Job, 1)
+	runner := NewJobRunner(client, JobRunnerConfig{Store: store, Cipher: cipher, OnComplete: func(job Job) { completed <- job }})
+	defer runner.Close(context.Background())
+	ctx := context.Background()
+
+	id, err := runner.SubmitJob(ctx, Prompt{SystemPrompt: "sys", UserPrompt: "Position: 5 BTC long"})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	<-completed
+
+	stored, _ := store.LoadJob(ctx, id)
+	if !strings.HasPrefix(stored.Prompt.UserPrompt, encryptedPrefix) || !strings.HasPrefix(stored.Result, encryptedPrefix) {
+		t.Errorf("job must be stored encrypted, got %+v", stored)
+	}
+	if result, err := runner.JobResult(ctx, id); err != nil || result != "Buy 2 BTC at market" {
+		t.Errorf("expected decrypted result, got %q (%v)", result, err)
+	}
+	if job, err := runner.JobStatus(ctx, id); err != nil || job.Prompt.UserPrompt != "Position: 5 BTC long" {
+		t.Errorf("expected decrypted prompt, got %+v (%v)", job, err)
+	}
+}
+
+func TestContentEncryption_Cassettes(t *testing.T) {
+	cipher := testCipher(t, 9)
+	dir := t.TempDir()
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Hedge with 3 ETH puts")
+	newClient := func(httpClient *MockHTTPClient, opts ...ClientOption) AIClient {
+		return NewClient(append([]ClientOption{
+			WithHTTPClient(httpClient.ToHTTPClient()),
+			WithLogger(NewNoopLogger()),
+			WithAPIKey("test-key"),
+			WithBaseURL("https://api.test.com"),
+		}, opts...)...)
+	}
+
+	if _, err := newClient(mockHTTP, WithRecorder(dir), WithContentEncryption(cipher)).CallWithMessages("system", "Portfolio: 40 ETH"); err != nil {
+		t.Fatalf("record call failed: %v", err)
+	}
+	files, _ := os.ReadDir(dir)
+	data, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if bytes.Contains(data, []byte("40 ETH")) || bytes.Contains(data, []byte("ETH puts")) {
+		t.Errorf("cassette must not contain plaintext: %s", data)
+	}
+
+	offline := NewMockHTTPClient()
+	offline.SetNetworkError(errors.New("network disabled"))
+	result, err := newClient(offline, WithReplay(dir), WithContentEncryption(cipher)).CallWithMessages("system", "Portfolio: 40 ETH")
+	if err != nil || result != "Hedge with 3 ETH puts" {
+		t.Errorf("replay got %q (%v)", result, err)
+	}
+}
+
+// blockingKeyWrapper test KMS whose unwraps block until release is closed
+type blockingKeyWrapper struct {
+	xorKeyWrapper
+	release chan struct{}
+}
+
+func (w *blockingKeyWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	<-w.release
+	return xorBytes(wrapped), nil
+}
+
+func TestKMSCipher_UnwrapsOutsideLock(t *testing.T) {
+	kms := &blockingKeyWrapper{release: make(chan struct{})}
+	defer close(kms.release)
+	writer := NewKMSCipher(&xorKeyWrapper{}, 0)
+	token, _ := writer.Encrypt([]byte("short ETH"))
+
+	cipher := NewKMSCipher(kms, 0)
+	go cipher.Decrypt(token) // Blocks in the KMS
+	time.Sleep(20 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cipher.Encrypt([]byte("close ETH"))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("encrypt failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("encrypt must not wait for a pending KMS unwrap")
+	}
+}
+
+func TestKMSCipher_KeyCacheBounded(t *testing.T) {
+	var tokens []string
+	for i := 0; i < 3; i++ {
+		token, _ := NewKMSCipher(&xorKeyWrapper{}, 0).Encrypt([]byte("ETH"))
+		tokens = append(tokens, token)
+	}
+
+	kms := &xorKeyWrapper{}
+	reader := NewKMSCipher(kms, 0)
+	reader.MaxCachedKeys = 2
+	for _, token := range append(tokens, tokens[2], tokens[0]) {
+		if _, err := reader.Decrypt(token); err != nil {
+			t.Fatalf("decrypt failed: %v", err)
+		}
+	}
+	if len(reader.unwrapped) != 2 {
+		t.Errorf("cache must hold at most 2 keys, got %d", len(reader.unwrapped))
+	}
+	if kms.unwraps != 4 {
+		t.Errorf("evicted key must be unwrapped again, got %d unwraps", kms.unwraps)
+	}
+}
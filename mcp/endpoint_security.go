@@ -0,0 +1,249 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrHostNotAllowed is matched (errors.Is) by HostNotAllowedError
+	ErrHostNotAllowed = errors.New("host not in allowlist")
+	// ErrCertificatePinMismatch is returned when no server certificate matches a pin
+	ErrCertificatePinMismatch = errors.New("server certificate does not match any pinned fingerprint")
+)
+
+// HostNotAllowedError request to a host outside of the allowlist was refused
+type HostNotAllowedError struct {
+	Host string
+}
+
+func (e *HostNotAllowedError) Error() string {
+	return fmt.Sprintf("refusing to send request to %s: host not in allowlist", e.Host)
+}
+
+func (e *HostNotAllowedError) Is(target error) bool {
+	return target == ErrHostNotAllowed
+}
+
+// WithAllowedHosts refuses requests (including redirects) to hosts not in the list
+//
+// Entries are host names, optionally with port ("gateway.internal:8443") or a leading
+// wildcard ("*.openai.azure.com"). A misconfigured or tampered BaseURL then fails with a
+// *HostNotAllowedError before any prompt leaves the process.
+//
+// Usage example:
+//   client := mcp.NewDeepSeekClientWithOptions(mcp.WithAllowedHosts([]string{"api.deepseek.com"}))
+func WithAllowedHosts(hosts []string) ClientOption {
+	return func(c *Config) {
+		c.AllowedHosts = hosts
+	}
+}
+
+// WithPinnedCert only accepts TLS servers presenting a certificate matching a fingerprint
+//
+// Fingerprints are either "sha256/<base64>" of the certificate's SubjectPublicKeyInfo
+// (survives certificate renewal with the same key) or the hex SHA-256 of the whole
+// certificate (colons optional). Any certificate of the verified chain may match, so
+// pinning an intermediate CA works too. The client gets its own copy of the transport;
+// an injected HTTPClient must use *http.Transport, otherwise every call fails.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithPinnedCert("sha256/YLh1dUR9y6Kja30RrAn7JKnbQG/uEtLMkBgFF2Fuihg="))
+func WithPinnedCert(fingerprints ...string) ClientOption {
+	return func(c *Config) {
+		c.PinnedCerts = fingerprints
+	}
+}
+
+// hostAllowed reports whether host (with optional port) matches an allowlist entry
+func hostAllowed(allowed []string, hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	hostport = strings.ToLower(hostport)
+
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == host || entry == hostport:
+			return true
+		case strings.HasPrefix(entry, "*.") && strings.HasSuffix(host, entry[1:]):
+			return true
+		}
+	}
+	return false
+}
+
+// allowlistTransport refuses requests to hosts outside of the allowlist
+type allowlistTransport struct {
+	next    http.RoundTripper
+	allowed []string
+}
+
+func (t *allowlistTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !hostAllowed(t.allowed, req.URL.Host) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &HostNotAllowedError{Host: req.URL.Host}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// failingTransport fails every request (transport-level configuration error)
+type failingTransport struct {
+	err error
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
+
+// customizeTLS returns a private copy of transport with TLS settings of cfg applied
+//
+// Returns transport and false when no TLS setting is configured.
+func customizeTLS(transport http.RoundTripper, cfg *Config) (http.RoundTripper, bool) {
+	if len(cfg.PinnedCerts) == 0 && cfg.TLSConfig == nil && cfg.ClientCertFile == "" {
+		return transport, false
+	}
+
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		return &failingTransport{err: fmt.Errorf("TLS settings require *http.Transport, got %T", transport)}, true
+	}
+	// Clone so clients sharing the pooled transport are unaffected
+	custom := base.Clone()
+	if cfg.TLSConfig != nil {
+		custom.TLSClientConfig = cfg.TLSConfig.Clone()
+	}
+	if custom.TLSClientConfig == nil {
+		custom.TLSClientConfig = &tls.Config{}
+	}
+
+	if cfg.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return &failingTransport{err: fmt.Errorf("failed to load client certificate: %w", err)}, true
+		}
+		custom.TLSClientConfig.Certificates = append(custom.TLSClientConfig.Certificates, cert)
+	}
+
+	if len(cfg.PinnedCerts) > 0 {
+		pins, err := parsePins(cfg.PinnedCerts)
+		if err != nil {
+			return &failingTransport{err: err}, true
+		}
+		verify := custom.TLSClientConfig.VerifyConnection
+		custom.TLSClientConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if verify != nil {
+				if err := verify(state); err != nil {
+					return err
+				}
+			}
+			return verifyPins(pins, state)
+		}
+	}
+	return custom, true
+}
+
+// certPin one parsed pin
+type certPin struct {
+	spki bool // true = SPKI hash, false = whole certificate hash
+	hash [sha256.Size]byte
+}
+
+// parsePins parses "sha256/<base64 SPKI hash>" and hex certificate fingerprints
+func parsePins(fingerprints []string) ([]certPin, error) {
+	pins := make([]certPin, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		var (
+			pin certPin
+			raw []byte
+			err error
+		)
+		if encoded, ok := strings.CutPrefix(strings.TrimSpace(fingerprint), "sha256/"); ok {
+			pin.spki = true
+			raw, err = base64.StdEncoding.DecodeString(encoded)
+		} else {
+			raw, err = hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+		}
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("invalid certificate pin %q", fingerprint)
+		}
+		copy(pin.hash[:], raw)
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+// verifyPins checks that a certificate of a verified chain matches a pin
+//
+// Extra certificates the server sent are ignored: a server could append a public,
+// pinned intermediate to a mis-issued chain. Without verification (InsecureSkipVerify)
+// only the leaf is checked, the one certificate the server proved it holds the key of.
+func verifyPins(pins []certPin, state tls.ConnectionState) error {
+	chains := state.VerifiedChains
+	if len(chains) == 0 && len(state.PeerCertificates) > 0 {
+		chains = [][]*x509.Certificate{state.PeerCertificates[:1]}
+	}
+	for _, chain := range chains {
+		for _, cert := range chain {
+			spki := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			whole := sha256.Sum256(cert.Raw)
+			for _, pin := range pins {
+				if (pin.spki && pin.hash == spki) || (!pin.spki && pin.hash == whole) {
+					return nil
+				}
+			}
+		}
+	}
+	return ErrCertificatePinMismatch
+}
+
+// WithClientCertificate presents a client certificate for mutual TLS
+//
+// certFile and keyFile are PEM files; they are loaded when the client is created and
+// a load failure makes every call fail. Only this client's transport is affected.
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithBaseURL("https://ai-gateway.corp.internal/v1"),
+//       mcp.WithClientCertificate("/etc/nofx/client.crt", "/etc/nofx/client.key"),
+//   )
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(c *Config) {
+		c.ClientCertFile = certFile
+		c.ClientKeyFile = keyFile
+	}
+}
+
+// WithTLSConfig uses a custom TLS configuration (e.g. private CA pool, client certificates)
+//
+// The config is cloned into a private copy of the transport, so other clients sharing
+// the process keep their settings. Combines with WithClientCertificate and WithPinnedCert.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Config) {
+		c.TLSConfig = config
+	}
+}
+
+// CertificateFingerprint returns the "sha256/<base64>" SPKI pin of a certificate
+//
+// Useful to compute pins for WithPinnedCert from a PEM file or a live connection.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(hash[:])
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAllowedHosts(t *testing.T) {
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{"api.deepseek.com", true},
+		{"API.DeepSeek.com:443", true},
+		{"my-resource.openai.azure.com", true},
+		{"gateway.internal:8443", true},
+		{"gateway.internal:9000", false},
+		{"evil.example", false},
+		{"openai.azure.com.evil.example", false},
+	}
+	allowed := []string{"api.deepseek.com", "*.openai.azure.com", "gateway.internal:8443"}
+
+	for _, tt := range tests {
+		if got := hostAllowed(allowed, tt.host); got != tt.allowed {
+			t.Errorf("hostAllowed(%q) = %v, want %v", tt.host, got, tt.allowed)
+		}
+	}
+}
+
+func TestAllowedHosts_RefusesRequest(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("leaked")
+
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithBaseURL("https://attacker.example/v1"),
+		WithAllowedHosts([]string{"api.deepseek.com"}),
+		WithMaxRetries(1),
+	)
+
+	_, err := client.CallWithMessages("system", "strategy details")
+	var hostErr *HostNotAllowedError
+	if !errors.As(err, &hostErr) || !errors.Is(err, ErrHostNotAllowed) || hostErr.Host != "attacker.example" {
+		t.Fatalf("expected HostNotAllowedError, got %v", err)
+	}
+	if len(mockHTTP.GetRequests()) != 0 {
+		t.Error("refused request must not reach the transport")
+	}
+}
+
+func TestPinnedCert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"pinned ok"}}]}`))
+	}))
+	defer server.Close()
+
+	newClient := func(pins ...string) AIClient {
+		return NewClient(
+			WithProvider(ProviderCustom),
+			WithHTTPClient(server.Client()),
+			WithBaseURL(server.URL),
+			WithAPIKey("sk-test-key"),
+			WithLogger(NewNoopLogger()),
+			WithMaxRetries(1),
+			WithPinnedCert(pins...),
+		)
+	}
+
+	spki := CertificateFingerprint(server.Certificate())
+	whole := sha256.Sum256(server.Certificate().Raw)
+	for _, pin := range []string{spki, hex.EncodeToString(whole[:])} {
+		if result, err := newClient(pin).CallWithMessages("system", "user"); err != nil || result != "pinned ok" {
+			t.Errorf("pin %s should be accepted, got %q, %v", pin, result, err)
+		}
+	}
+
+	other := sha256.Sum256([]byte("other key"))
+	if _, err := newClient(hex.EncodeToString(other[:])).CallWithMessages("system", "user"); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("expected pin mismatch, got %v", err)
+	}
+	if _, err := newClient("not-a-pin").CallWithMessages("system", "user"); err == nil {
+		t.Error("invalid pin should fail closed")
+	}
+
+	if server.Client().Transport.(*http.Transport).TLSClientConfig.VerifyConnection != nil {
+		t.Error("injected transport must not be modified")
+	}
+}
+
+func TestPinnedCert_IgnoresUnverifiedCertificates(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"pinned ok"}}]}`))
+	}))
+	server.StartTLS()
+	defer server.Close()
+
+	// The server appends a certificate outside its verified chain, matching the pin
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Pinned Intermediate"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	extra, _ := x509.ParseCertificate(der)
+	cert := server.TLS.Certificates[0]
+	cert.Certificate = append(cert.Certificate[:1:1], der)
+	server.TLS.Certificates = []tls.Certificate{cert}
+
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithHTTPClient(server.Client()),
+		WithBaseURL(server.URL),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(1),
+		WithPinnedCert(CertificateFingerprint(extra)),
+	)
+	if _, err := client.CallWithMessages("system", "user"); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("pin matching an unverified certificate must fail, got %v", err)
+	}
+}
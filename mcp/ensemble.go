@@ -0,0 +1,332 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnsembleStrategy how an EnsembleClient resolves member answers into a consensus
+type EnsembleStrategy int
+
+const (
+	// EnsembleMajority picks the answer given by the largest (weighted) number of members
+	EnsembleMajority EnsembleStrategy = iota
+	// EnsembleJudge asks a judge model to pick the best answer
+	EnsembleJudge
+	// EnsembleConfidenceWeighted picks the answer with the highest sum of weight × confidence
+	EnsembleConfidenceWeighted
+)
+
+func (s EnsembleStrategy) String() string {
+	switch s {
+	case EnsembleMajority:
+		return "majority"
+	case EnsembleJudge:
+		return "judge"
+	case EnsembleConfidenceWeighted:
+		return "confidence_weighted"
+	}
+	return fmt.Sprintf("strategy(%d)", int(s))
+}
+
+// ErrNoConsensus is returned when too few members answered to reach a consensus
+var ErrNoConsensus = errors.New("ensemble could not reach consensus")
+
+// EnsembleMember one model of an ensemble
+type EnsembleMember struct {
+	Name   string   // Label used in results (e.g. "deepseek")
+	Client AIClient // Client of the model
+	Weight float64  // Vote weight (0 = 1)
+}
+
+// EnsembleConfig ensemble configuration
+type EnsembleConfig struct {
+	Strategy EnsembleStrategy
+	Judge    AIClient // Judge model (EnsembleJudge)
+	Quorum   int      // Min successful answers required (0 = 1)
+
+	// Normalize maps an answer to its voting key (nil = trim and lowercase)
+	Normalize func(answer string) string
+	// Confidence extracts confidence in [0,1] from an answer
+	// (nil = "confidence" field of a JSON answer, else 1)
+	Confidence func(answer string) float64
+}
+
+// ModelAnswer answer of one ensemble member
+type ModelAnswer struct {
+	Name       string
+	Text       string
+	Err        error
+	Latency    time.Duration
+	Confidence float64 // Extracted confidence (EnsembleConfidenceWeighted)
+}
+
+// EnsembleResult per-model answers plus the consensus
+type EnsembleResult struct {
+	Consensus string        // Resolved answer
+	Winner    string        // Name of the member whose answer was chosen
+	Agreement float64       // Share of successful (weighted) votes backing the consensus
+	Answers   []ModelAnswer // Answers in member order
+	Rationale string        // Judge explanation (EnsembleJudge)
+}
+
+// EnsembleClient sends the same prompt to several models and resolves a consensus
+//
+// Members are called concurrently. EnsembleClient implements AIClient, returning the
+// consensus, so it can stand in wherever a single client drives a trade.
+type EnsembleClient struct {
+	members []EnsembleMember
+	config  EnsembleConfig
+}
+
+// NewEnsembleClient creates ensemble of members
+//
+// Usage example:
+//   ensemble := mcp.NewEnsembleClient([]mcp.EnsembleMember{
+//       {Name: "deepseek", Client: deepseek},
+//       {Name: "claude", Client: claude, Weight: 2},
+//       {Name: "qwen", Client: qwen},
+//   }, mcp.EnsembleConfig{Strategy: mcp.EnsembleMajority, Quorum: 2})
+//   result, err := ensemble.Call(ctx, systemPrompt, userPrompt)
+//   log.Printf("consensus %s (agreement %.0f%%)", result.Consensus, result.Agreement*100)
+func NewEnsembleClient(members []EnsembleMember, config EnsembleConfig) *EnsembleClient {
+	if config.Normalize == nil {
+		config.Normalize = func(answer string) string {
+			return strings.ToLower(strings.TrimSpace(answer))
+		}
+	}
+	if config.Confidence == nil {
+		config.Confidence = jsonConfidence
+	}
+	if config.Quorum <= 0 {
+		config.Quorum = 1
+	}
+	return &EnsembleClient{members: members, config: config}
+}
+
+// Call sends system/user prompt to all members and resolves the consensus
+//
+// The result (with per-model answers) is returned alongside ErrNoConsensus when fewer
+// than Quorum members answered.
+func (e *EnsembleClient) Call(ctx context.Context, systemPrompt, userPrompt string) (*EnsembleResult, error) {
+	return e.run(ctx, func(client AIClient) (string, error) {
+		return client.CallWithMessages(systemPrompt, userPrompt)
+	})
+}
+
+// CallRequest sends req to all members and resolves the consensus
+func (e *EnsembleClient) CallRequest(ctx context.Context, req *Request) (*EnsembleResult, error) {
+	return e.run(ctx, func(client AIClient) (string, error) {
+		return callRequest(ctx, client, req)
+	})
+}
+
+// SetAPIKey is a no-op: members are configured individually
+func (e *EnsembleClient) SetAPIKey(apiKey string, customURL string, customModel string) {}
+
+// SetTimeout sets timeout of every member
+func (e *EnsembleClient) SetTimeout(timeout time.Duration) {
+	for _, member := range e.members {
+		member.Client.SetTimeout(timeout)
+	}
+}
+
+// CallWithMessages returns the consensus answer
+func (e *EnsembleClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	result, err := e.Call(context.Background(), systemPrompt, userPrompt)
+	if err != nil {
+		return "", err
+	}
+	return result.Consensus, nil
+}
+
+// CallWithRequest returns the consensus answer
+func (e *EnsembleClient) CallWithRequest(req *Request) (string, error) {
+	result, err := e.CallRequest(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+	return result.Consensus, nil
+}
+
+// run calls all members concurrently and resolves their answers
+func (e *EnsembleClient) run(ctx context.Context, call func(AIClient) (string, error)) (*EnsembleResult, error) {
+	result := &EnsembleResult{Answers: make([]ModelAnswer, len(e.members))}
+
+	var wg sync.WaitGroup
+	for i, member := range e.members {
+		wg.Add(1)
+		go func(i int, member EnsembleMember) {
+			defer wg.Done()
+			start := time.Now()
+			text, err := call(member.Client)
+			result.Answers[i] = ModelAnswer{Name: member.Name, Text: text, Err: err, Latency: time.Since(start)}
+		}(i, member)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	succeeded := 0
+	for _, answer := range result.Answers {
+		if answer.Err == nil {
+			succeeded++
+		}
+	}
+	if succeeded < e.config.Quorum {
+		return result, fmt.Errorf("%w: %d of %d members answered (quorum %d)", ErrNoConsensus, succeeded, len(e.members), e.config.Quorum)
+	}
+
+	if e.config.Strategy == EnsembleJudge {
+		if err := e.arbitrate(ctx, result); err != nil {
+			return result, err
+		}
+		return result, nil
+	}
+	e.vote(result)
+	return result, nil
+}
+
+// vote resolves consensus by (confidence-)weighted vote over normalized answers
+func (e *EnsembleClient) vote(result *EnsembleResult) {
+	scores := make(map[string]float64)
+	first := make(map[string]int) // Voting key -> index of first member giving it
+	total := 0.0
+	for i := range result.Answers {
+		answer := &result.Answers[i]
+		if answer.Err != nil {
+			continue
+		}
+		score := e.weight(i)
+		if e.config.Strategy == EnsembleConfidenceWeighted {
+			answer.Confidence = e.config.Confidence(answer.Text)
+			score *= answer.Confidence
+		}
+		key := e.config.Normalize(answer.Text)
+		if _, ok := first[key]; !ok {
+			first[key] = i
+		}
+		scores[key] += score
+		total += score
+	}
+
+	// Highest score wins; ties go to the earlier member
+	best := -1
+	var bestKey string
+	for key, index := range first {
+		if best < 0 || scores[key] > scores[bestKey] || (scores[key] == scores[bestKey] && index < first[bestKey]) {
+			best, bestKey = index, key
+		}
+	}
+	winner := result.Answers[best]
+	result.Consensus = winner.Text
+	result.Winner = winner.Name
+	if total > 0 {
+		result.Agreement = scores[bestKey] / total
+	}
+}
+
+// judgeChoicePattern fallback extraction of the chosen answer number
+var judgeChoicePattern = regexp.MustCompile(`\d+`)
+
+// arbitrate asks the judge model to pick the best answer
+func (e *EnsembleClient) arbitrate(ctx context.Context, result *EnsembleResult) error {
+	if e.config.Judge == nil {
+		return fmt.Errorf("%w: judge strategy requires EnsembleConfig.Judge", ErrNoConsensus)
+	}
+
+	var candidates []int
+	var prompt strings.Builder
+	prompt.WriteString("Candidate answers:\n")
+	for i, answer := range result.Answers {
+		if answer.Err != nil {
+			continue
+		}
+		candidates = append(candidates, i)
+		fmt.Fprintf(&prompt, "\n### Answer %d\n%s\n", len(candidates), answer.Text)
+	}
+
+	systemPrompt := "You are an impartial judge. Several models answered the same task. " +
+		"Pick the single most correct and well-reasoned answer. " +
+		`Reply only with JSON: {"choice": <answer number>, "reason": "<one sentence>"}`
+	reply, err := e.config.Judge.CallWithMessages(systemPrompt, prompt.String())
+	if err != nil {
+		return fmt.Errorf("judge call failed: %w", err)
+	}
+
+	var verdict struct {
+		Choice int    `json:"choice"`
+		Reason string `json:"reason"`
+	}
+	if json.Unmarshal([]byte(extractJSONObject(reply)), &verdict) != nil || verdict.Choice == 0 {
+		verdict.Choice, _ = strconv.Atoi(judgeChoicePattern.FindString(reply))
+		verdict.Reason = strings.TrimSpace(reply)
+	}
+	if verdict.Choice < 1 || verdict.Choice > len(candidates) {
+		return fmt.Errorf("%w: judge returned invalid choice: %s", ErrNoConsensus, reply)
+	}
+
+	winner := result.Answers[candidates[verdict.Choice-1]]
+	result.Consensus = winner.Text
+	result.Winner = winner.Name
+	result.Rationale = verdict.Reason
+	matches := 0
+	for _, i := range candidates {
+		if e.config.Normalize(result.Answers[i].Text) == e.config.Normalize(winner.Text) {
+			matches++
+		}
+	}
+	result.Agreement = float64(matches) / float64(len(candidates))
+	return nil
+}
+
+// weight returns vote weight of member i
+func (e *EnsembleClient) weight(i int) float64 {
+	if w := e.members[i].Weight; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// jsonConfidence reads "confidence" of a JSON answer (1 when absent)
+func jsonConfidence(answer string) float64 {
+	var parsed struct {
+		Confidence *float64 `json:"confidence"`
+	}
+	if json.Unmarshal([]byte(extractJSONObject(answer)), &parsed) != nil || parsed.Confidence == nil {
+		return 1
+	}
+	confidence := *parsed.Confidence
+	if confidence > 1 {
+		confidence /= 100 // Percent scale
+	}
+	if confidence < 0 {
+		return 0
+	}
+	return confidence
+}
+
+// extractJSONObject returns the outermost {...} of text (text when none)
+func extractJSONObject(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return text
+	}
+	return text[start : end+1]
+}
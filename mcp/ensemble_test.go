@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Ensemble Client
+// ============================================================
+
+// stubClient AIClient returning a fixed answer
+type stubClient struct {
+	mu      sync.Mutex
+	answer  string
+	err     error
+	prompts []string
+}
+
+func (c *stubClient) SetAPIKey(apiKey string, customURL string, customModel string) {}
+func (c *stubClient) SetTimeout(timeout time.Duration)                              {}
+
+func (c *stubClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	c.mu.Lock()
+	c.prompts = append(c.prompts, userPrompt)
+	c.mu.Unlock()
+	return c.answer, c.err
+}
+
+func (c *stubClient) CallWithRequest(req *Request) (string, error) {
+	return c.CallWithMessages("", req.Messages[len(req.Messages)-1].Content)
+}
+
+func TestEnsemble_MajorityVote(t *testing.T) {
+	ensemble := NewEnsembleClient([]EnsembleMember{
+		{Name: "a", Client: &stubClient{answer: "SELL"}},
+		{Name: "b", Client: &stubClient{answer: "buy"}},
+		{Name: "c", Client: &stubClient{answer: " Buy "}},
+		{Name: "d", Client: &stubClient{err: errors.New("down")}},
+	}, EnsembleConfig{Strategy: EnsembleMajority})
+
+	result, err := ensemble.Call(context.Background(), "system", "decide")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Winner != "b" || result.Consensus != "buy" {
+		t.Errorf("expected b's buy to win, got %s from %s", result.Consensus, result.Winner)
+	}
+	if result.Agreement < 0.66 || result.Agreement > 0.67 {
+		t.Errorf("expected 2/3 agreement, got %v", result.Agreement)
+	}
+	if len(result.Answers) != 4 || result.Answers[3].Err == nil {
+		t.Errorf("expected per-model answers including failure, got %+v", result.Answers)
+	}
+}
+
+func TestEnsemble_WeightsAndQuorum(t *testing.T) {
+	ensemble := NewEnsembleClient([]EnsembleMember{
+		{Name: "a", Client: &stubClient{answer: "buy"}},
+		{Name: "b", Client: &stubClient{answer: "buy"}},
+		{Name: "c", Client: &stubClient{answer: "hold"}, Weight: 3},
+	}, EnsembleConfig{})
+
+	answer, err := ensemble.CallWithMessages("system", "decide")
+	if err != nil || answer != "hold" {
+		t.Errorf("weighted member should win, got %q, %v", answer, err)
+	}
+
+	failing := NewEnsembleClient([]EnsembleMember{
+		{Name: "a", Client: &stubClient{answer: "buy"}},
+		{Name: "b", Client: &stubClient{err: errors.New("down")}},
+	}, EnsembleConfig{Quorum: 2})
+	result, err := failing.Call(context.Background(), "system", "decide")
+	if !errors.Is(err, ErrNoConsensus) {
+		t.Fatalf("expected ErrNoConsensus, got %v", err)
+	}
+	if result == nil || result.Answers[0].Text != "buy" {
+		t.Error("answers should still be returned without consensus")
+	}
+}
+
+func TestEnsemble_ConfidenceWeighted(t *testing.T) {
+	ensemble := NewEnsembleClient([]EnsembleMember{
+		{Name: "a", Client: &stubClient{answer: `{"action":"buy","confidence":0.3}`}},
+		{Name: "b", Client: &stubClient{answer: `{"action":"buy","confidence":0.2}`}},
+		{Name: "c", Client: &stubClient{answer: `{"action":"sell","confidence":90}`}},
+	}, EnsembleConfig{
+		Strategy: EnsembleConfidenceWeighted,
+		Normalize: func(answer string) string {
+			return strings.Split(answer, ",")[0]
+		},
+	})
+
+	result, err := ensemble.Call(context.Background(), "system", "decide")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Winner != "c" {
+		t.Errorf("confident sell should outweigh two weak buys, got %s", result.Winner)
+	}
+	if result.Answers[2].Confidence != 0.9 {
+		t.Errorf("percent confidence should be normalized, got %v", result.Answers[2].Confidence)
+	}
+}
+
+func TestEnsemble_Judge(t *testing.T) {
+	judge := &stubClient{answer: "```json\n{\"choice\": 2, \"reason\": \"better risk control\"}\n```"}
+	ensemble := NewEnsembleClient([]EnsembleMember{
+		{Name: "a", Client: &stubClient{answer: "buy 10x leverage"}},
+		{Name: "b", Client: &stubClient{answer: "buy 2x leverage"}},
+	}, EnsembleConfig{Strategy: EnsembleJudge, Judge: judge})
+
+	result, err := ensemble.Call(context.Background(), "system", "decide")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Winner != "b" || result.Rationale != "better risk control" {
+		t.Errorf("judge choice not applied: %+v", result)
+	}
+	if !strings.Contains(judge.prompts[0], "buy 10x leverage") || !strings.Contains(judge.prompts[0], "### Answer 2") {
+		t.Errorf("judge should see all candidates, got %q", judge.prompts[0])
+	}
+
+	judge.answer = "I cannot decide"
+	if _, err := ensemble.Call(context.Background(), "system", "decide"); !errors.Is(err, ErrNoConsensus) {
+		t.Errorf("invalid judge reply should fail with ErrNoConsensus, got %v", err)
+	}
+}
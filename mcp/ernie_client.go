@@ -0,0 +1,246 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderErnie           = "ernie"
+	DefaultErnieBaseURL     = "https://qianfan.baidubce.com/v2" // Qianfan OpenAI-compatible API, API key
+	DefaultErnieModel       = "ernie-4.5-turbo-128k"
+	DefaultErnieWorkshopURL = "https://aip.baidubce.com/rpc/2.0/ai_custom/v1/wenxinworkshop/chat" // Legacy API, access tokens
+	defaultBaiduTokenURL    = "https://aip.baidubce.com/oauth/2.0/token"
+	ernieMinTemperature     = 0.01 // ERNIE rejects temperature 0
+)
+
+type ErnieClient struct {
+	*Client
+}
+
+// NewErnieClient creates Baidu ERNIE (Qianfan) client (backward compatible)
+func NewErnieClient() AIClient {
+	return NewErnieClientWithOptions()
+}
+
+// NewErnieClientWithOptions creates ERNIE client (supports options pattern)
+//
+// With a Qianfan API key requests go to the OpenAI-compatible v2 API. With an
+// application's API Key/Secret Key (WithBaiduCredentials) they go to the ERNIE
+// workshop API instead: access tokens are exchanged and refreshed automatically, and
+// the model is the workshop endpoint name (e.g. "ernie-4.0-turbo-8k", "completions_pro").
+//
+// Usage example:
+//   client := mcp.NewErnieClientWithOptions(
+//       mcp.WithBaiduCredentials(apiKey, secretKey),
+//       mcp.WithModel("ernie-4.0-turbo-8k"),
+//   )
+func NewErnieClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create ERNIE preset options
+	ernieOpts := []ClientOption{
+		WithProvider(ProviderErnie),
+		WithModel(DefaultErnieModel),
+		WithBaseURL(DefaultErnieBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(ernieOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create ERNIE client
+	ernieClient := &ErnieClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to ErnieClient (implement dynamic dispatch)
+	baseClient.hooks = ernieClient
+
+	return ernieClient
+}
+
+// WithBaiduCredentials calls the ERNIE workshop API with access tokens exchanged for an API Key/Secret Key pair
+func WithBaiduCredentials(apiKey, secretKey string) ClientOption {
+	return func(c *Config) {
+		WithTokenSource(&baiduTokenSource{
+			apiKey:     apiKey,
+			secretKey:  secretKey,
+			tokenURL:   defaultBaiduTokenURL,
+			httpClient: &http.Client{Timeout: 30 * time.Second},
+		})(c)
+		c.TokenQueryParam = "access_token"
+		WithBaseURL(DefaultErnieWorkshopURL)(c)
+	}
+}
+
+func (c *ErnieClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] ERNIE API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] ERNIE using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] ERNIE using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] ERNIE using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] ERNIE using default Model: %s", c.Model)
+	}
+}
+
+// workshopAPI reports whether requests go to the legacy workshop API
+func (c *ErnieClient) workshopAPI() bool {
+	return c.config.TokenQueryParam != ""
+}
+
+// buildUrl workshop API addresses models by endpoint path
+func (c *ErnieClient) buildUrl() string {
+	if c.workshopAPI() && !c.UseFullURL {
+		return strings.TrimSuffix(c.BaseURL, "/") + "/" + url.PathEscape(c.Model)
+	}
+	return c.Client.buildUrl()
+}
+
+// marshalRequestBody converts the chat body to workshop parameters
+func (c *ErnieClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	if c.workshopAPI() {
+		requestBody = ernieWorkshopBody(requestBody)
+	}
+	return c.Client.marshalRequestBody(requestBody)
+}
+
+// parseMCPResponse workshop API answers {"result"} and reports errors with HTTP 200
+func (c *ErnieClient) parseMCPResponse(body []byte) (string, error) {
+	if !c.workshopAPI() {
+		return c.Client.parseMCPResponse(body)
+	}
+
+	var result struct {
+		Result    *string `json:"result"`
+		ErrorCode int     `json:"error_code"`
+		ErrorMsg  string  `json:"error_msg"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse ERNIE response: %w, body: %s", err, string(body))
+	}
+	if result.ErrorCode != 0 {
+		return "", fmt.Errorf("ERNIE API error: %d - %s", result.ErrorCode, result.ErrorMsg)
+	}
+	if result.Result == nil {
+		return "", fmt.Errorf("ERNIE returned no result, body: %s", string(body))
+	}
+
+	if usage := parseUsage(body); TokenUsageCallback != nil && usage.TotalTokens > 0 {
+		usage.Provider = c.Provider
+		usage.Model = c.Model
+		TokenUsageCallback(usage)
+	}
+	return *result.Result, nil
+}
+
+// ernieWorkshopBody builds workshop parameters from an OpenAI-style chat body
+//
+// System messages move to the "system" field, the model goes in the URL and
+// temperature is kept within ERNIE's (0, 1] range.
+func ernieWorkshopBody(requestBody map[string]any) map[string]any {
+	var system []string
+	var messages []map[string]string
+	for _, turn := range messageTurns(requestBody["messages"]) {
+		if turn.role == "system" {
+			system = append(system, turn.content)
+			continue
+		}
+		messages = append(messages, map[string]string{"role": turn.role, "content": turn.content})
+	}
+
+	workshopBody := map[string]any{"messages": messages}
+	if len(system) > 0 {
+		workshopBody["system"] = strings.Join(system, "\n\n")
+	}
+	for from, to := range map[string]string{
+		"max_tokens":            "max_output_tokens",
+		"max_completion_tokens": "max_output_tokens",
+		"top_p":                 "top_p",
+		"stop":                  "stop",
+		"stream":                "stream",
+		"frequency_penalty":     "penalty_score",
+	} {
+		if value, ok := requestBody[from]; ok {
+			workshopBody[to] = value
+		}
+	}
+	if temperature, ok := numberValue(requestBody["temperature"]); ok {
+		workshopBody["temperature"] = min(max(temperature, ernieMinTemperature), 1)
+	}
+	if format, ok := requestBody["response_format"].(map[string]any); ok && format["type"] != "text" {
+		workshopBody["response_format"] = "json_object"
+	}
+	return workshopBody
+}
+
+// numberValue returns a numeric request body value as float64
+func numberValue(value any) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	}
+	return 0, false
+}
+
+// baiduTokenSource exchanges an API Key/Secret Key pair for Baidu access tokens
+type baiduTokenSource struct {
+	apiKey     string
+	secretKey  string
+	tokenURL   string
+	httpClient *http.Client
+}
+
+func (s *baiduTokenSource) Token(ctx context.Context) (*OAuthToken, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.apiKey},
+		"client_secret": {s.secretKey},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL+"?"+form.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fail to build token request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.AccessToken == "" {
+		if result.Error != "" {
+			return nil, fmt.Errorf("token request failed: %s - %s", result.Error, result.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+	token := &OAuthToken{AccessToken: result.AccessToken}
+	if result.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
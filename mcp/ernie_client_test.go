@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test ErnieClient
+// ============================================================
+
+func TestErnieClient_WorkshopAPI(t *testing.T) {
+	var body map[string]any
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		json.Unmarshal(data, &body)
+		answer := `{"id":"as-1","result":"Hold BTC.","is_truncated":false,"finish_reason":"normal","usage":{"prompt_tokens":10,"completion_tokens":4,"total_tokens":14}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(answer)), Header: make(http.Header)}, nil
+	}
+	client := NewErnieClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithBaiduCredentials("api-key", "secret-key"),
+		WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+			return &OAuthToken{AccessToken: "24.access"}, nil
+		})),
+		WithModel("ernie-4.0-turbo-8k"),
+		WithDeterministic(),
+		WithLogger(NewNoopLogger()),
+	).(*ErnieClient)
+
+	response, err := client.Call(t.Context(), NewRequestBuilder().WithSystemPrompt("You are a trader.").WithUserPrompt("BTC?").MustBuild())
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if response.Text != "Hold BTC." || response.FinishReason != FinishReasonStop || response.Usage.TotalTokens != 14 {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	req := mockHTTP.GetLastRequest()
+	if req.URL.Path != "/rpc/2.0/ai_custom/v1/wenxinworkshop/chat/ernie-4.0-turbo-8k" || req.URL.Query().Get("access_token") != "24.access" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("access token must not be sent as header, got %q", req.Header.Get("Authorization"))
+	}
+	if body["system"] != "You are a trader." || body["model"] != nil || body["temperature"] != ernieMinTemperature {
+		t.Errorf("unexpected workshop body %v", body)
+	}
+	if messages, _ := body["messages"].([]any); len(messages) != 1 {
+		t.Errorf("system prompt must leave messages, got %v", body["messages"])
+	}
+}
+
+func TestErnieClient_WorkshopError(t *testing.T) {
+	client := NewErnieClientWithOptions(
+		WithHTTPClient(captureBodyClient(`{"error_code":336003,"error_msg":"the length of messages must be an odd number"}`, new([]map[string]any))),
+		WithBaiduCredentials("api-key", "secret-key"),
+		WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+			return &OAuthToken{AccessToken: "24.access"}, nil
+		})),
+		WithMaxRetries(1),
+		WithLogger(NewNoopLogger()),
+	)
+
+	_, err := client.CallWithMessages("", "BTC?")
+	if err == nil || !strings.Contains(err.Error(), "336003") {
+		t.Errorf("expected ERNIE error, got %v", err)
+	}
+}
+
+func TestErnieClient_WorkshopStream(t *testing.T) {
+	var sent []map[string]any
+	client := NewErnieClientWithOptions(
+		WithHTTPClient(sequenceSSEClient(&sent, sseLines(
+			`{"id":"as-1","result":"Hold ","is_end":false}`,
+			`{"id":"as-1","result":"BTC.","is_end":true,"finish_reason":"normal","usage":{"prompt_tokens":10,"completion_tokens":4,"total_tokens":14}}`,
+		))),
+		WithBaiduCredentials("api-key", "secret-key"),
+		WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+			return &OAuthToken{AccessToken: "24.access"}, nil
+		})),
+		WithLogger(NewNoopLogger()),
+	).(*ErnieClient)
+
+	response, err := client.CallStream(t.Context(), NewRequestBuilder().WithUserPrompt("BTC?").MustBuild(), func(StreamEvent) error { return nil })
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if response.Text != "Hold BTC." || response.FinishReason != FinishReasonStop || response.Usage.TotalTokens != 14 {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if sent[0]["stream"] != true {
+		t.Errorf("expected stream flag, got %v", sent[0])
+	}
+}
+
+func TestBaiduTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("grant_type") != "client_credentials" || query.Get("client_id") != "api-key" || query.Get("client_secret") != "secret-key" {
+			io.WriteString(w, `{"error":"invalid_client","error_description":"unknown client id"}`)
+			return
+		}
+		io.WriteString(w, `{"access_token":"24.access","expires_in":2592000}`)
+	}))
+	defer server.Close()
+
+	source := &baiduTokenSource{apiKey: "api-key", secretKey: "secret-key", tokenURL: server.URL, httpClient: server.Client()}
+	token, err := source.Token(context.Background())
+	if err != nil || token.AccessToken != "24.access" || time.Until(token.Expiry) < 29*24*time.Hour {
+		t.Errorf("unexpected token %v, %v", token, err)
+	}
+
+	source.secretKey = "wrong"
+	if _, err := source.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("expected invalid_client error, got %v", err)
+	}
+}
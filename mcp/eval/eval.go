@@ -0,0 +1,73 @@
+// Package eval runs datasets of prompts against AI clients and scores the outputs
+//
+// A dataset is a JSONL file with one Case per line. Runner sends every case to one
+// or more mcp.AIClient targets, scores the outputs with exact-match, regex or
+// LLM-judge scorers and produces a Report with pass rate, latency and cost per case,
+// so prompt and model changes can be regression-tested like code.
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Case one evaluation case
+type Case struct {
+	ID       string   `json:"id"`
+	System   string   `json:"system,omitempty"`   // System prompt
+	Prompt   string   `json:"prompt"`             // User prompt
+	Expected string   `json:"expected,omitempty"` // Expected output (ExactMatch, Similarity)
+	Pattern  string   `json:"pattern,omitempty"`  // Regular expression output must match (RegexMatch)
+	Criteria string   `json:"criteria,omitempty"` // Grading criteria for the judge model (LLMJudge)
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// LoadDataset reads JSONL dataset from path
+//
+// Usage example:
+//
+//	cases, err := eval.LoadDataset("testdata/decisions.jsonl")
+func LoadDataset(path string) ([]Case, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer file.Close()
+	return ReadDataset(file)
+}
+
+// ReadDataset reads JSONL dataset (blank lines and lines starting with # are skipped)
+//
+// Cases without an ID get "case-<line>".
+func ReadDataset(r io.Reader) ([]Case, error) {
+	var cases []Case
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var c Case
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("dataset line %d: %w", line, err)
+		}
+		if c.Prompt == "" {
+			return nil, fmt.Errorf("dataset line %d: prompt is required", line)
+		}
+		if c.ID == "" {
+			c.ID = fmt.Sprintf("case-%d", line)
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return cases, nil
+}
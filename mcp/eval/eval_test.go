@@ -0,0 +1,146 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"nofx/mcp"
+	"nofx/mcp/mcptest"
+)
+
+const dataset = `
+# trading decisions
+{"id":"bull","prompt":"BTC broke resistance","expected":"buy"}
+{"id":"bear","prompt":"BTC lost support","pattern":"(?i)sell|short"}
+{"prompt":"explain risk","criteria":"mentions stop loss"}
+`
+
+func TestReadDataset(t *testing.T) {
+	cases, err := ReadDataset(strings.NewReader(dataset))
+	if err != nil {
+		t.Fatalf("failed to read dataset: %v", err)
+	}
+	if len(cases) != 3 {
+		t.Fatalf("expected 3 cases, got %d", len(cases))
+	}
+	if cases[2].ID != "case-5" {
+		t.Errorf("missing ID should default to line number, got %q", cases[2].ID)
+	}
+
+	if _, err := ReadDataset(strings.NewReader(`{"id":"x"}`)); err == nil {
+		t.Error("case without prompt should be rejected")
+	}
+	if _, err := ReadDataset(strings.NewReader(`not json`)); err == nil {
+		t.Error("malformed line should be rejected")
+	}
+}
+
+func TestRunner_ScoresAndReport(t *testing.T) {
+	cases, _ := ReadDataset(strings.NewReader(dataset))
+
+	good := mcptest.NewMockClient().SetHandler(func(call mcptest.Call) (string, error) {
+		switch {
+		case strings.Contains(call.UserPrompt, "resistance"):
+			return " Buy ", nil
+		case strings.Contains(call.UserPrompt, "support"):
+			return "SELL now", nil
+		}
+		return "always use a stop loss", nil
+	})
+	bad := mcptest.NewMockClient().SetHandler(func(call mcptest.Call) (string, error) {
+		if strings.Contains(call.UserPrompt, "support") {
+			return "", errors.New("provider down")
+		}
+		return "hold", nil
+	})
+	judge := mcptest.NewMockClient().SetHandler(func(call mcptest.Call) (string, error) {
+		if strings.Contains(call.UserPrompt, "Candidate output:\nalways use a stop loss") {
+			return `{"score": 9, "reason": "mentions stop loss"}`, nil
+		}
+		return `{"score": 2, "reason": "no stop loss"}`, nil
+	})
+
+	runner := NewRunner(ExactMatch(), RegexMatch(), LLMJudge(judge, 0.7))
+	runner.Concurrency = 2
+	report := runner.Run(context.Background(), cases,
+		Target{Name: "good", Client: good},
+		Target{Name: "bad", Client: bad},
+	)
+
+	if len(report.Targets) != 2 {
+		t.Fatalf("expected 2 target reports, got %d", len(report.Targets))
+	}
+	if rate := report.Targets[0].PassRate; rate != 1 {
+		t.Errorf("good target should pass everything, got %v: %+v", rate, report.Targets[0].Cases)
+	}
+	badReport := report.Targets[1]
+	if badReport.PassRate != 0 || badReport.Errors != 1 {
+		t.Errorf("bad target should fail all cases with one error, got %+v", badReport)
+	}
+	if score := badReport.Cases[0].Scores["regex"]; !score.Skipped {
+		t.Error("regex scorer should be skipped for cases without pattern")
+	}
+	if score := badReport.Cases[2].Scores["llm_judge"]; score.Value != 0.2 || score.Detail != "no stop loss" {
+		t.Errorf("unexpected judge score %+v", score)
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteText(&out); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{"good", "100.0%", "FAIL bad/bull: exact_match", "FAIL bad/bear: provider down"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestRunner_CostFromUsage(t *testing.T) {
+	server := mcptest.NewServer()
+	defer server.Close()
+	server.Enqueue(mcptest.ServerReply{Content: "buy"})
+
+	mcp.RegisterModelPrice("eval-model", mcp.ModelPrice{InputPerMillion: 1e6, OutputPerMillion: 1e6})
+	client := mcp.NewClient(
+		mcp.WithProvider(mcp.ProviderCustom),
+		mcp.WithBaseURL(server.OpenAIBaseURL()),
+		mcp.WithModel("eval-model"),
+		mcp.WithAPIKey("sk-eval"),
+		mcp.WithLogger(mcp.NewNoopLogger()),
+	)
+
+	report := NewRunner(ExactMatch()).Run(context.Background(), []Case{{ID: "c", Prompt: "go", Expected: "buy"}}, Target{Name: "deepseek", Client: client})
+	result := report.Targets[0].Cases[0]
+	if !result.Pass {
+		t.Fatalf("case should pass: %+v", result)
+	}
+	if result.Cost <= 0 {
+		t.Errorf("expected cost from reported usage, got %v", result.Cost)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	scorer := Similarity(nil, 0.5)
+	tests := []struct {
+		expected, output string
+		pass             bool
+	}{
+		{`{"action":"open_long","symbol":"BTCUSDT"}`, `Decision: {"symbol":"BTCUSDT","action":"open_long"}`, true},
+		{`{"action":"open_long","symbol":"BTCUSDT"}`, `{"action":"wait","symbol":"BTCUSDT"}`, false},
+		{"hold the position", "Hold the position for now", true},
+		{"hold the position", "close everything", false},
+	}
+	for _, tt := range tests {
+		score, err := scorer.Score(context.Background(), Case{Expected: tt.expected}, tt.output)
+		if err != nil || score.Pass != tt.pass {
+			t.Errorf("%q vs %q: expected pass=%v, got %+v (%v)", tt.expected, tt.output, tt.pass, score, err)
+		}
+	}
+	if score, _ := scorer.Score(context.Background(), Case{}, "x"); !score.Skipped {
+		t.Error("case without expected output should be skipped")
+	}
+}
@@ -0,0 +1,122 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// CaseResult outcome of one case on one target
+type CaseResult struct {
+	CaseID  string           `json:"case_id"`
+	Output  string           `json:"output"`
+	Error   string           `json:"error,omitempty"`
+	Pass    bool             `json:"pass"`
+	Latency time.Duration    `json:"latency"`
+	Cost    float64          `json:"cost"` // USD (0 when unknown)
+	Scores  map[string]Score `json:"scores,omitempty"`
+}
+
+// TargetReport results of one target
+type TargetReport struct {
+	Name       string        `json:"name"`
+	Cases      []CaseResult  `json:"cases"`
+	PassRate   float64       `json:"pass_rate"`
+	Errors     int           `json:"errors"`
+	AvgLatency time.Duration `json:"avg_latency"`
+	P95Latency time.Duration `json:"p95_latency"`
+	TotalCost  float64       `json:"total_cost"`
+}
+
+// Report results of an evaluation run
+type Report struct {
+	Started  time.Time      `json:"started"`
+	Duration time.Duration  `json:"duration"`
+	Targets  []TargetReport `json:"targets"`
+}
+
+// newTargetReport aggregates case results
+func newTargetReport(name string, results []CaseResult) TargetReport {
+	report := TargetReport{Name: name, Cases: results}
+	if len(results) == 0 {
+		return report
+	}
+
+	passed := 0
+	var total time.Duration
+	latencies := make([]time.Duration, 0, len(results))
+	for _, result := range results {
+		if result.Pass {
+			passed++
+		}
+		if result.Error != "" {
+			report.Errors++
+		}
+		total += result.Latency
+		report.TotalCost += result.Cost
+		latencies = append(latencies, result.Latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	report.PassRate = float64(passed) / float64(len(results))
+	report.AvgLatency = total / time.Duration(len(results))
+	report.P95Latency = latencies[(len(latencies)*95+99)/100-1]
+	return report
+}
+
+// Failures returns cases that did not pass
+func (t TargetReport) Failures() []CaseResult {
+	var failures []CaseResult
+	for _, result := range t.Cases {
+		if !result.Pass {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// WriteText writes a human-readable summary followed by the failed cases
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tPASS RATE\tERRORS\tAVG LATENCY\tP95 LATENCY\tCOST")
+	for _, target := range r.Targets {
+		fmt.Fprintf(tw, "%s\t%.1f%%\t%d\t%v\t%v\t$%.4f\n", target.Name, target.PassRate*100, target.Errors,
+			target.AvgLatency.Round(time.Millisecond), target.P95Latency.Round(time.Millisecond), target.TotalCost)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, target := range r.Targets {
+		for _, failure := range target.Failures() {
+			reason := failure.Error
+			if reason == "" {
+				reason = failedScores(failure.Scores)
+			}
+			if _, err := fmt.Fprintf(w, "FAIL %s/%s: %s\n", target.Name, failure.CaseID, reason); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// failedScores describes failed scorers in name order
+func failedScores(scores map[string]Score) string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		if score := scores[name]; !score.Skipped && !score.Pass {
+			parts = append(parts, fmt.Sprintf("%s (%s)", name, score.Detail))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,119 @@
+package eval
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"nofx/mcp"
+)
+
+// Target client under evaluation
+type Target struct {
+	Name   string
+	Client mcp.AIClient
+}
+
+// Runner runs datasets against targets
+type Runner struct {
+	Scorers     []Scorer
+	Concurrency int // Cases evaluated in parallel per target (0 = 1)
+}
+
+// NewRunner creates runner scoring with scorers
+//
+// Usage example:
+//
+//	runner := eval.NewRunner(eval.ExactMatch(), eval.RegexMatch(), eval.LLMJudge(judge, 0.7))
+//	report := runner.Run(ctx, cases,
+//		eval.Target{Name: "deepseek", Client: deepseek},
+//		eval.Target{Name: "claude", Client: claude},
+//	)
+//	report.WriteText(os.Stdout)
+func NewRunner(scorers ...Scorer) *Runner {
+	return &Runner{Scorers: scorers}
+}
+
+// Run evaluates every case against every target
+//
+// A case passes when the call succeeds and every applicable scorer passes.
+func (r *Runner) Run(ctx context.Context, cases []Case, targets ...Target) *Report {
+	report := &Report{Started: time.Now()}
+	for _, target := range targets {
+		report.Targets = append(report.Targets, r.runTarget(ctx, cases, target))
+	}
+	report.Duration = time.Since(report.Started)
+	return report
+}
+
+// runTarget evaluates all cases against one target
+func (r *Runner) runTarget(ctx context.Context, cases []Case, target Target) TargetReport {
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]CaseResult, len(cases))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, c := range cases {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, c Case) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = r.runCase(ctx, c, target.Client)
+		}(i, c)
+	}
+	wg.Wait()
+
+	return newTargetReport(target.Name, results)
+}
+
+// runCase calls client with one case and scores the output
+func (r *Runner) runCase(ctx context.Context, c Case, client mcp.AIClient) CaseResult {
+	result := CaseResult{CaseID: c.ID, Scores: make(map[string]Score)}
+
+	start := time.Now()
+	output, cost, err := call(ctx, client, c)
+	result.Latency = time.Since(start)
+	result.Output = output
+	result.Cost = cost
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Pass = true
+	for _, scorer := range r.Scorers {
+		score, err := scorer.Score(ctx, c, output)
+		if err != nil {
+			score = Score{Detail: err.Error()}
+		}
+		result.Scores[scorer.Name()] = score
+		if !score.Skipped && !score.Pass {
+			result.Pass = false
+		}
+	}
+	return result
+}
+
+// call sends case to client; cost is known for clients reporting usage (*mcp.Client)
+func call(ctx context.Context, client mcp.AIClient, c Case) (string, float64, error) {
+	if caller, ok := client.(interface {
+		Call(ctx context.Context, req *mcp.Request) (*mcp.Response, error)
+	}); ok {
+		req, err := mcp.NewRequestBuilder().WithSystemPrompt(c.System).WithUserPrompt(c.Prompt).Build()
+		if err != nil {
+			return "", 0, err
+		}
+		response, err := caller.Call(ctx, req)
+		if err != nil {
+			return "", 0, err
+		}
+		return response.Text, mcp.EstimateCost(response.Usage), nil
+	}
+
+	output, err := client.CallWithMessages(c.System, c.Prompt)
+	return output, 0, err
+}
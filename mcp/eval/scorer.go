@@ -0,0 +1,133 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"nofx/mcp"
+)
+
+// Score result of one scorer on one output
+type Score struct {
+	Pass    bool    `json:"pass"`
+	Value   float64 `json:"value"`             // Normalized score in [0,1]
+	Detail  string  `json:"detail,omitempty"`  // Explanation (e.g. judge rationale)
+	Skipped bool    `json:"skipped,omitempty"` // Case has no data for this scorer
+}
+
+// Scorer grades an output of a case
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, c Case, output string) (Score, error)
+}
+
+// ExactMatch scores outputs equal to Case.Expected (trimmed, case-insensitive)
+func ExactMatch() Scorer {
+	return exactMatch{}
+}
+
+type exactMatch struct{}
+
+func (exactMatch) Name() string { return "exact_match" }
+
+func (exactMatch) Score(ctx context.Context, c Case, output string) (Score, error) {
+	if c.Expected == "" {
+		return Score{Skipped: true}, nil
+	}
+	if strings.EqualFold(strings.TrimSpace(output), strings.TrimSpace(c.Expected)) {
+		return Score{Pass: true, Value: 1}, nil
+	}
+	return Score{Detail: fmt.Sprintf("expected %q", c.Expected)}, nil
+}
+
+// RegexMatch scores outputs matching Case.Pattern
+func RegexMatch() Scorer {
+	return regexMatch{}
+}
+
+type regexMatch struct{}
+
+func (regexMatch) Name() string { return "regex" }
+
+func (regexMatch) Score(ctx context.Context, c Case, output string) (Score, error) {
+	if c.Pattern == "" {
+		return Score{Skipped: true}, nil
+	}
+	re, err := regexp.Compile(c.Pattern)
+	if err != nil {
+		return Score{}, fmt.Errorf("invalid pattern of case %s: %w", c.ID, err)
+	}
+	if re.MatchString(output) {
+		return Score{Pass: true, Value: 1}, nil
+	}
+	return Score{Detail: fmt.Sprintf("no match for /%s/", c.Pattern)}, nil
+}
+
+// LLMJudge scores outputs against Case.Criteria with a judge model (see mcp.JudgeWith)
+//
+// An output passes when its normalized score is at least threshold (e.g. 0.7).
+func LLMJudge(judge mcp.AIClient, threshold float64) Scorer {
+	return &llmJudge{judge: judge, threshold: threshold}
+}
+
+type llmJudge struct {
+	judge     mcp.AIClient
+	threshold float64
+}
+
+func (j *llmJudge) Name() string { return "llm_judge" }
+
+func (j *llmJudge) Score(ctx context.Context, c Case, output string) (Score, error) {
+	if c.Criteria == "" {
+		return Score{Skipped: true}, nil
+	}
+
+	criteria := fmt.Sprintf("%s\n\n(The output answers this task: %s)", c.Criteria, c.Prompt)
+	verdict, err := mcp.JudgeWith(ctx, j.judge, criteria, output)
+	if err != nil {
+		return Score{}, err
+	}
+	return Score{Pass: verdict.Score >= j.threshold, Value: verdict.Score, Detail: verdict.Rationale}, nil
+}
+
+// Similarity scores outputs by similarity to Case.Expected (see mcp.CompareResponses)
+//
+// JSON outputs pass when every field matches; other outputs pass when the text
+// similarity is at least threshold. With a non-nil embedder the similarity is semantic,
+// else it is the word overlap.
+func Similarity(embedder mcp.Embedder, threshold float64) Scorer {
+	return &similarity{embedder: embedder, threshold: threshold}
+}
+
+type similarity struct {
+	embedder  mcp.Embedder
+	threshold float64
+}
+
+func (s *similarity) Name() string { return "similarity" }
+
+func (s *similarity) Score(ctx context.Context, c Case, output string) (Score, error) {
+	if c.Expected == "" {
+		return Score{Skipped: true}, nil
+	}
+
+	expected, actual := mcp.Response{Text: c.Expected}, mcp.Response{Text: output}
+	diff := mcp.CompareResponses(expected, actual)
+	if s.embedder != nil && !diff.JSON {
+		var err error
+		if diff, err = mcp.CompareResponsesWith(ctx, s.embedder, expected, actual); err != nil {
+			return Score{}, err
+		}
+	}
+
+	if diff.JSON {
+		var fields []string
+		for _, field := range diff.Fields {
+			fields = append(fields, fmt.Sprintf("%s: expected %s, got %s", field.Path, field.A, field.B))
+		}
+		return Score{Pass: len(fields) == 0, Value: diff.Similarity, Detail: strings.Join(fields, "; ")}, nil
+	}
+	return Score{Pass: diff.Similarity >= s.threshold, Value: diff.Similarity, Detail: fmt.Sprintf("%s similarity %.2f", diff.SimilarityMethod, diff.Similarity)}, nil
+}
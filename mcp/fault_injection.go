@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FaultKind kind of injected fault
+type FaultKind string
+
+const (
+	FaultTimeout     FaultKind = "timeout"
+	FaultRateLimit   FaultKind = "rate_limit"
+	FaultServerError FaultKind = "server_error"
+	FaultMalformed   FaultKind = "malformed_json"
+	FaultTruncated   FaultKind = "truncated_stream"
+	FaultLatency     FaultKind = "latency"
+)
+
+// FaultPolicy probabilities (0-1) of each injected fault
+//
+// Faults are mutually exclusive per request and evaluated in field order, so the
+// sum of error rates should not exceed 1. Latency is injected independently.
+type FaultPolicy struct {
+	TimeoutRate     float64 // Fail with a timeout error without contacting the provider
+	RateLimitRate   float64 // Answer 429 Too Many Requests
+	ServerErrorRate float64 // Answer 500 Internal Server Error
+	MalformedRate   float64 // Answer 200 with invalid JSON
+	TruncateRate    float64 // Forward to provider but cut the response body midway
+
+	LatencyRate float64       // Probability of adding extra latency
+	Latency     time.Duration // Extra latency added when triggered
+
+	Seed int64 // Random seed (0 = time-based)
+}
+
+// FaultInjector injects faults into HTTP responses according to a policy
+type FaultInjector struct {
+	policy FaultPolicy
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	stats map[FaultKind]int
+}
+
+// NewFaultInjector creates fault injector
+func NewFaultInjector(policy FaultPolicy) *FaultInjector {
+	seed := policy.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &FaultInjector{
+		policy: policy,
+		rng:    rand.New(rand.NewSource(seed)),
+		stats:  make(map[FaultKind]int),
+	}
+}
+
+// WithFaultInjection injects faults into responses for chaos testing
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithFaultInjection(mcp.FaultPolicy{
+//       RateLimitRate:   0.1,
+//       ServerErrorRate: 0.05,
+//       TimeoutRate:     0.05,
+//   }))
+func WithFaultInjection(policy FaultPolicy) ClientOption {
+	return func(c *Config) {
+		c.FaultInjector = NewFaultInjector(policy)
+	}
+}
+
+// Stats returns number of injected faults by kind
+func (f *FaultInjector) Stats() map[FaultKind]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stats := make(map[FaultKind]int, len(f.stats))
+	for k, v := range f.stats {
+		stats[k] = v
+	}
+	return stats
+}
+
+// roll picks the fault for one request ("" = none) and whether to add latency
+func (f *FaultInjector) roll() (FaultKind, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	addLatency := f.policy.Latency > 0 && f.rng.Float64() < f.policy.LatencyRate
+	if addLatency {
+		f.stats[FaultLatency]++
+	}
+
+	draw := f.rng.Float64()
+	threshold := 0.0
+	for _, candidate := range []struct {
+		kind FaultKind
+		rate float64
+	}{
+		{FaultTimeout, f.policy.TimeoutRate},
+		{FaultRateLimit, f.policy.RateLimitRate},
+		{FaultServerError, f.policy.ServerErrorRate},
+		{FaultMalformed, f.policy.MalformedRate},
+		{FaultTruncated, f.policy.TruncateRate},
+	} {
+		threshold += candidate.rate
+		if draw < threshold {
+			f.stats[candidate.kind]++
+			return candidate.kind, addLatency
+		}
+	}
+	return "", addLatency
+}
+
+// wrap wraps next transport with fault injection
+func (f *FaultInjector) wrap(next http.RoundTripper) http.RoundTripper {
+	return &faultTransport{injector: f, next: next}
+}
+
+// faultTimeoutError injected timeout (implements net.Error)
+type faultTimeoutError struct{}
+
+func (faultTimeoutError) Error() string   { return "injected fault: i/o timeout" }
+func (faultTimeoutError) Timeout() bool   { return true }
+func (faultTimeoutError) Temporary() bool { return true }
+
+// faultTransport http.RoundTripper injecting faults
+type faultTransport struct {
+	injector *FaultInjector
+	next     http.RoundTripper
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	kind, addLatency := t.injector.roll()
+
+	if addLatency {
+		select {
+		case <-time.After(t.injector.policy.Latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	switch kind {
+	case FaultTimeout:
+		return nil, faultTimeoutError{}
+	case FaultRateLimit:
+		resp := faultResponse(req, http.StatusTooManyRequests, `{"error":{"message":"injected fault: rate limit exceeded","type":"rate_limit_error"}}`)
+		resp.Header.Set("Retry-After", "1")
+		return resp, nil
+	case FaultServerError:
+		return faultResponse(req, http.StatusInternalServerError, `{"error":{"message":"injected fault: INTERNAL_ERROR","type":"server_error"}}`), nil
+	case FaultMalformed:
+		return faultResponse(req, http.StatusOK, `{"choices": [{"message": {"content": "inj`), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || kind != FaultTruncated {
+		return resp, err
+	}
+
+	resp.Body = &truncatedBody{body: resp.Body, remaining: 16}
+	return resp, nil
+}
+
+// faultResponse builds synthetic response
+func faultResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// truncatedBody returns the first bytes of the body then fails with unexpected EOF
+type truncatedBody struct {
+	body      io.ReadCloser
+	remaining int
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= n
+	if err == io.EOF {
+		return n, err
+	}
+	return n, nil
+}
+
+func (b *truncatedBody) Close() error {
+	return b.body.Close()
+}
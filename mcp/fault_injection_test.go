@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test Fault Injection
+// ============================================================
+
+func newFaultTestClient(policy FaultPolicy) *Client {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("healthy response with enough content to be truncated")
+	return NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithMaxRetries(1),
+		WithFaultInjection(policy),
+	).(*Client)
+}
+
+func TestFaultInjection_EachKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   FaultPolicy
+		kind     FaultKind
+		errorHas string
+	}{
+		{"timeout", FaultPolicy{TimeoutRate: 1}, FaultTimeout, "timeout"},
+		{"rate limit", FaultPolicy{RateLimitRate: 1}, FaultRateLimit, "status 429"},
+		{"server error", FaultPolicy{ServerErrorRate: 1}, FaultServerError, "status 500"},
+		{"malformed", FaultPolicy{MalformedRate: 1}, FaultMalformed, "parse"},
+		{"truncated", FaultPolicy{TruncateRate: 1}, FaultTruncated, "unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFaultTestClient(tt.policy)
+
+			_, err := client.CallWithMessages("system", "user")
+			if err == nil || !strings.Contains(err.Error(), tt.errorHas) {
+				t.Errorf("expected error containing %q, got %v", tt.errorHas, err)
+			}
+			if client.config.FaultInjector.Stats()[tt.kind] != 1 {
+				t.Errorf("expected 1 %s fault, got %v", tt.kind, client.config.FaultInjector.Stats())
+			}
+		})
+	}
+}
+
+func TestFaultInjection_ZeroPolicyPassesThrough(t *testing.T) {
+	client := newFaultTestClient(FaultPolicy{})
+
+	result, err := client.CallWithMessages("system", "user")
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !strings.HasPrefix(result, "healthy response") {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestFaultInjection_RateIsApproximate(t *testing.T) {
+	injector := NewFaultInjector(FaultPolicy{ServerErrorRate: 0.3, Seed: 42})
+	for i := 0; i < 1000; i++ {
+		injector.roll()
+	}
+	if n := injector.Stats()[FaultServerError]; n < 250 || n > 350 {
+		t.Errorf("expected ~300 server errors, got %d", n)
+	}
+}
@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Few-shot defaults
+const (
+	DefaultFewShotK      = 3    // Examples injected per prompt
+	DefaultFewShotTokens = 1500 // Token cap of injected examples
+)
+
+// FewShotExample labeled example: an input and the output expected for it
+type FewShotExample struct {
+	Input  string
+	Output string // Expected answer, e.g. the exact JSON decision
+
+	vector []float64
+}
+
+// FewShotSet stores labeled examples and injects the most relevant ones into prompts
+//
+// Relevance is embedding similarity to the current input when an embedder is set,
+// word overlap otherwise. Examples are injected as user/assistant turns before the
+// final user message, which steadies structured output without hand-edited prompts.
+//
+// Usage example:
+//   examples := mcp.NewFewShotSet(client)
+//   examples.Add(ctx, mcp.FewShotExample{Input: pastMarket, Output: `{"action":"wait"}`})
+//   req, err := examples.Inject(ctx, request)
+type FewShotSet struct {
+	Embedder  Embedder // nil = word overlap
+	K         int      // Examples per prompt (0 = DefaultFewShotK)
+	MaxTokens int      // Token cap of injected examples (0 = DefaultFewShotTokens)
+
+	mu       sync.RWMutex
+	examples []FewShotExample
+}
+
+// NewFewShotSet creates empty few-shot set (embedder may be nil)
+func NewFewShotSet(embedder Embedder) *FewShotSet {
+	return &FewShotSet{Embedder: embedder}
+}
+
+// Add stores examples, embedding their inputs in one batch
+func (s *FewShotSet) Add(ctx context.Context, examples ...FewShotExample) error {
+	if s.Embedder != nil && len(examples) > 0 {
+		inputs := make([]string, len(examples))
+		for i, example := range examples {
+			inputs[i] = example.Input
+		}
+		embeddings, err := s.Embedder.Embed(ctx, inputs)
+		if err != nil {
+			return fmt.Errorf("few-shot embedding failed: %w", err)
+		}
+		for i := range examples {
+			if i < len(embeddings.Vectors) {
+				examples[i].vector = embeddings.Vectors[i]
+			}
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.examples = append(s.examples, examples...)
+	return nil
+}
+
+// Len returns the number of stored examples
+func (s *FewShotSet) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.examples)
+}
+
+// Select returns the k examples most relevant to input, most relevant first
+func (s *FewShotSet) Select(ctx context.Context, input string, k int) ([]FewShotExample, error) {
+	s.mu.RLock()
+	examples := append([]FewShotExample(nil), s.examples...)
+	s.mu.RUnlock()
+	if len(examples) == 0 || k <= 0 {
+		return nil, nil
+	}
+
+	var inputVector []float64
+	if s.Embedder != nil {
+		embeddings, err := s.Embedder.Embed(ctx, []string{input})
+		if err != nil {
+			return nil, fmt.Errorf("few-shot embedding failed: %w", err)
+		}
+		if len(embeddings.Vectors) > 0 {
+			inputVector = embeddings.Vectors[0]
+		}
+	}
+
+	type scored struct {
+		example FewShotExample
+		score   float64
+	}
+	ranked := make([]scored, 0, len(examples))
+	for _, example := range examples {
+		score := tokenOverlap(input, example.Input)
+		if inputVector != nil && example.vector != nil {
+			score = cosineSimilarity(inputVector, example.vector)
+		}
+		ranked = append(ranked, scored{example, score})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+
+	selected := make([]FewShotExample, 0, min(len(ranked), k))
+	for _, r := range ranked[:min(len(ranked), k)] {
+		selected = append(selected, r.example)
+	}
+	return selected, nil
+}
+
+// Inject returns a copy of req with the examples most relevant to its last user prompt
+// added as user/assistant turns before it, within the token cap
+func (s *FewShotSet) Inject(ctx context.Context, req *Request) (*Request, error) {
+	k, budget := s.K, s.MaxTokens
+	if k <= 0 {
+		k = DefaultFewShotK
+	}
+	if budget <= 0 {
+		budget = DefaultFewShotTokens
+	}
+	examples, err := s.Select(ctx, lastUserPrompt(req), k)
+	if err != nil || len(examples) == 0 {
+		return req, err
+	}
+
+	var turns []Message
+	for _, example := range examples {
+		if budget -= EstimateTokens(example.Input) + EstimateTokens(example.Output); budget < 0 {
+			break
+		}
+		turns = append(turns, NewUserMessage(example.Input), NewAssistantMessage(example.Output))
+	}
+	if len(turns) == 0 {
+		return req, nil
+	}
+
+	injected := cloneRequest(req)
+	at := len(injected.Messages)
+	for i := len(injected.Messages) - 1; i >= 0; i-- {
+		if injected.Messages[i].Role == "user" {
+			at = i
+			break
+		}
+	}
+	injected.Messages = append(injected.Messages[:at], append(turns, injected.Messages[at:]...)...)
+	return injected, nil
+}
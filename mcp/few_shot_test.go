@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFewShotSetSelectsBySimilarity(t *testing.T) {
+	embedder := vectorEmbedder{
+		"BTC breaks out":   {1, 0},
+		"ETH funding flat": {0, 1},
+		"BTC rallies hard": {0.9, 0.1},
+	}
+	set := NewFewShotSet(embedder)
+	if err := set.Add(context.Background(),
+		FewShotExample{Input: "ETH funding flat", Output: `{"action":"wait"}`},
+		FewShotExample{Input: "BTC breaks out", Output: `{"action":"open_long"}`},
+	); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	selected, err := set.Select(context.Background(), "BTC rallies hard", 1)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(selected) != 1 || selected[0].Output != `{"action":"open_long"}` {
+		t.Errorf("expected breakout example, got %+v", selected)
+	}
+}
+
+func TestFewShotSetInjectsTurnsBeforeLastUserMessage(t *testing.T) {
+	set := NewFewShotSet(nil)
+	set.Add(context.Background(),
+		FewShotExample{Input: "BTC breaks out above resistance", Output: `{"action":"open_long"}`},
+		FewShotExample{Input: "ETH funding flat", Output: `{"action":"wait"}`},
+	)
+	set.K = 1
+
+	req := &Request{Messages: []Message{
+		NewSystemMessage("You are a trader."),
+		NewUserMessage("BTC breaks out again"),
+	}}
+	injected, err := set.Inject(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Inject failed: %v", err)
+	}
+	if len(req.Messages) != 2 {
+		t.Errorf("original request modified: %d messages", len(req.Messages))
+	}
+	if len(injected.Messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(injected.Messages))
+	}
+	if injected.Messages[1].Role != "user" || injected.Messages[2].Role != "assistant" ||
+		injected.Messages[2].Content != `{"action":"open_long"}` {
+		t.Errorf("unexpected example turns: %+v", injected.Messages[1:3])
+	}
+	if injected.Messages[3].Content != "BTC breaks out again" {
+		t.Errorf("last user message moved: %+v", injected.Messages[3])
+	}
+}
+
+func TestFewShotSetRespectsTokenBudget(t *testing.T) {
+	set := NewFewShotSet(nil)
+	set.Add(context.Background(), FewShotExample{Input: "BTC breaks out", Output: strings.Repeat("word ", 1000)})
+	set.MaxTokens = 50
+
+	req := &Request{Messages: []Message{NewUserMessage("BTC breaks out")}}
+	injected, err := set.Inject(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Inject failed: %v", err)
+	}
+	if len(injected.Messages) != 1 {
+		t.Errorf("expected no examples within budget, got %d messages", len(injected.Messages))
+	}
+}
@@ -0,0 +1,526 @@
+package mcp
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxGatewayRequestBytes max size of a request body accepted by the gateway
+const maxGatewayRequestBytes = 8 << 20
+
+// Gateway OpenAI-compatible HTTP server in front of an AI client
+//
+// Serves POST /v1/chat/completions (streaming included), POST /v1/embeddings and
+// GET /v1/models, translating requests to whichever provider the client is configured
+// for. The client's model is always used; the "model" field of requests is ignored.
+// Callers authenticate with "Authorization: Bearer <key>" using keys registered with
+// WithKey, each with its own rate limit. A gateway without keys or a signature
+// verifier rejects every call unless opened with WithAllowAnonymous.
+type Gateway struct {
+	client AIClient
+
+	mu             sync.RWMutex
+	keys           map[string]*gatewayKey
+	allowAnonymous bool // Accept calls without a key when none are registered
+
+	verifier RequestVerifier // Checks request signatures (nil = unsigned requests accepted)
+	maxSkew  time.Duration
+}
+
+// gatewayKey local API key with its rate limit
+type gatewayKey struct {
+	name    string
+	limiter *RateLimiter
+}
+
+// NewGateway creates gateway serving client
+//
+// Usage example:
+//   gateway := mcp.NewGateway(client).
+//       WithKey("sk-local-backtester", "backtester", 5, 10).
+//       WithKey("sk-local-dashboard", "dashboard", 1, 2)
+//   http.ListenAndServe(":8090", gateway)
+func NewGateway(client AIClient) *Gateway {
+	return &Gateway{client: client, keys: make(map[string]*gatewayKey)}
+}
+
+// WithKey registers local API key allowed rps requests per second (bursts up to burst, rps <= 0 = unlimited)
+func (g *Gateway) WithKey(key, name string, rps float64, burst int) *Gateway {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.keys[key] = &gatewayKey{name: name, limiter: NewRateLimiter(rps, burst)}
+	return g
+}
+
+// WithAllowAnonymous accepts calls without an API key while no keys are registered
+// (local development only: anyone reaching the port spends the upstream key)
+func (g *Gateway) WithAllowAnonymous() *Gateway {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.allowAnonymous = true
+	return g
+}
+
+// WithSignatureVerifier requires requests signed by WithRequestSigner, at most maxSkew old
+func (g *Gateway) WithSignatureVerifier(verifier RequestVerifier, maxSkew time.Duration) *Gateway {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.verifier = verifier
+	g.maxSkew = maxSkew
+	return g
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	var handler func(http.ResponseWriter, *http.Request)
+	method := http.MethodPost
+	switch path {
+	case "/v1/chat/completions", "/chat/completions":
+		handler = g.handleChat
+	case "/v1/embeddings", "/embeddings":
+		handler = g.handleEmbeddings
+	case "/v1/models", "/models":
+		handler, method = g.handleModels, http.MethodGet
+	default:
+		writeGatewayError(w, http.StatusNotFound, "invalid_request_error", fmt.Sprintf("unknown endpoint %s", r.URL.Path))
+		return
+	}
+	if r.Method != method {
+		writeGatewayError(w, http.StatusMethodNotAllowed, "invalid_request_error", fmt.Sprintf("%s requires %s", path, method))
+		return
+	}
+	if status, message := decodeGatewayBody(r); status != http.StatusOK {
+		writeGatewayError(w, status, "invalid_request_error", message)
+		return
+	}
+	if status, kind, message := g.authorize(r); status != http.StatusOK {
+		writeGatewayError(w, status, kind, message)
+		return
+	}
+	handler(w, r)
+}
+
+// decodeGatewayBody decompresses gzip request bodies (WithCompression), so signatures
+// and JSON decoding see the plain payload
+func decodeGatewayBody(r *http.Request) (status int, message string) {
+	switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return http.StatusOK, ""
+	case "gzip":
+		decoded, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return http.StatusBadRequest, fmt.Sprintf("invalid gzip body: %v", err)
+		}
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{decoded, r.Body}
+		r.Header.Del("Content-Encoding")
+		r.ContentLength = -1
+		return http.StatusOK, ""
+	default:
+		// Compressing clients resend uncompressed after 415
+		return http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported Content-Encoding %q", encoding)
+	}
+}
+
+// authorize checks the request signature, the caller's key and its rate limit
+func (g *Gateway) authorize(r *http.Request) (status int, kind, message string) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.verifier != nil {
+		if _, err := VerifySignedRequest(r, g.verifier, g.maxSkew); errors.Is(err, ErrRequestBodyTooLarge) {
+			return http.StatusRequestEntityTooLarge, "invalid_request_error", err.Error()
+		} else if err != nil {
+			return http.StatusUnauthorized, "authentication_error", err.Error()
+		}
+	}
+	if len(g.keys) == 0 {
+		if g.verifier != nil || g.allowAnonymous {
+			return http.StatusOK, "", ""
+		}
+		return http.StatusUnauthorized, "authentication_error", "no API keys registered on the gateway"
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	key, found := g.keys[strings.TrimSpace(token)]
+	if !ok || !found {
+		return http.StatusUnauthorized, "authentication_error", "invalid API key"
+	}
+	if !key.limiter.Allow() {
+		return http.StatusTooManyRequests, "rate_limit_error", fmt.Sprintf("rate limit exceeded for key %q", key.name)
+	}
+	return http.StatusOK, "", ""
+}
+
+// gatewayChatRequest OpenAI chat completion request (fields the gateway understands)
+type gatewayChatRequest struct {
+	Messages []struct {
+		Role       string          `json:"role"`
+		Content    json.RawMessage `json:"content"`
+		ToolCalls  []ToolCall      `json:"tool_calls"`
+		ToolCallID string          `json:"tool_call_id"`
+	} `json:"messages"`
+	Stream              bool            `json:"stream"`
+	Temperature         *float64        `json:"temperature"`
+	TopP                *float64        `json:"top_p"`
+	MaxTokens           *int            `json:"max_tokens"`
+	MaxCompletionTokens *int            `json:"max_completion_tokens"`
+	FrequencyPenalty    *float64        `json:"frequency_penalty"`
+	PresencePenalty     *float64        `json:"presence_penalty"`
+	Stop                json.RawMessage `json:"stop"`
+	Tools               []Tool          `json:"tools"`
+	ToolChoice          json.RawMessage `json:"tool_choice"`
+	ReasoningEffort     string          `json:"reasoning_effort"`
+	ResponseFormat      *struct {
+		Type       string `json:"type"`
+		JSONSchema *struct {
+			Name   string         `json:"name"`
+			Schema map[string]any `json:"schema"`
+		} `json:"json_schema"`
+	} `json:"response_format"`
+}
+
+func (g *Gateway) handleChat(w http.ResponseWriter, r *http.Request) {
+	var body gatewayChatRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxGatewayRequestBytes)).Decode(&body); err != nil {
+		writeGatewayError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	req, err := body.toRequest()
+	if err != nil {
+		writeGatewayError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	if body.Stream {
+		g.streamChat(r.Context(), w, req)
+		return
+	}
+
+	response, err := g.call(r.Context(), req)
+	if err != nil {
+		writeGatewayUpstreamError(w, err)
+		return
+	}
+	message := map[string]any{"role": "assistant", "content": response.Text}
+	if response.Reasoning != "" {
+		message["reasoning_content"] = response.Reasoning
+	}
+	if len(response.ToolCalls) > 0 {
+		message["tool_calls"] = openAIToolCalls(response.ToolCalls)
+	}
+	writeGatewayJSON(w, http.StatusOK, map[string]any{
+		"id":      "chatcmpl-" + response.RequestID,
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   response.Model,
+		"choices": []map[string]any{{"index": 0, "message": message, "finish_reason": gatewayFinishReason(response.FinishReason)}},
+		"usage":   openAIUsage(response.Usage),
+	})
+}
+
+// toRequest converts OpenAI request body to a Request
+func (body *gatewayChatRequest) toRequest() (*Request, error) {
+	if len(body.Messages) == 0 {
+		return nil, errors.New("messages must not be empty")
+	}
+	req := &Request{
+		Temperature:      body.Temperature,
+		TopP:             body.TopP,
+		MaxTokens:        body.MaxTokens,
+		FrequencyPenalty: body.FrequencyPenalty,
+		PresencePenalty:  body.PresencePenalty,
+		Tools:            body.Tools,
+		ReasoningEffort:  body.ReasoningEffort,
+	}
+	if req.MaxTokens == nil {
+		req.MaxTokens = body.MaxCompletionTokens
+	}
+	for _, msg := range body.Messages {
+		content, err := messageText(msg.Content)
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", msg.Role, err)
+		}
+		if msg.Role == "tool" && msg.ToolCallID == "" {
+			return nil, errors.New("tool messages require tool_call_id")
+		}
+		req.Messages = append(req.Messages, Message{Role: msg.Role, Content: content, ToolCalls: msg.ToolCalls, ToolCallID: msg.ToolCallID})
+	}
+	if len(body.Stop) > 0 {
+		var stop string
+		if json.Unmarshal(body.Stop, &stop) == nil {
+			req.Stop = []string{stop}
+		} else if err := json.Unmarshal(body.Stop, &req.Stop); err != nil {
+			return nil, errors.New("stop must be a string or an array of strings")
+		}
+	}
+	if len(body.ToolChoice) > 0 {
+		// Only the string forms ("auto", "none", "required") are supported
+		json.Unmarshal(body.ToolChoice, &req.ToolChoice)
+	}
+	if format := body.ResponseFormat; format != nil {
+		switch format.Type {
+		case "json_object":
+			req.ResponseFormat = &ResponseFormat{}
+		case "json_schema":
+			if format.JSONSchema == nil {
+				return nil, errors.New("response_format json_schema requires a schema")
+			}
+			req.ResponseFormat = &ResponseFormat{Name: format.JSONSchema.Name, Schema: format.JSONSchema.Schema}
+		}
+	}
+	return req, nil
+}
+
+// messageText returns message content given as a string or an array of text parts
+func messageText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text, nil
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", errors.New("content must be a string or an array of parts")
+	}
+	var b strings.Builder
+	for _, part := range parts {
+		if part.Type != "text" {
+			return "", fmt.Errorf("unsupported content part %q", part.Type)
+		}
+		b.WriteString(part.Text)
+	}
+	return b.String(), nil
+}
+
+// call performs a non-streaming call, keeping response metadata when the client supports it
+func (g *Gateway) call(ctx context.Context, req *Request) (*Response, error) {
+	if c, ok := g.client.(interface {
+		Call(ctx context.Context, req *Request) (*Response, error)
+	}); ok {
+		return c.Call(ctx, req)
+	}
+	text, err := callRequest(ctx, g.client, req)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Text: text, FinishReason: FinishReasonStop}, nil
+}
+
+// streamChat relays a streaming call as OpenAI chat.completion.chunk events
+func (g *Gateway) streamChat(ctx context.Context, w http.ResponseWriter, req *Request) {
+	streamer, ok := g.client.(interface {
+		CallStream(ctx context.Context, req *Request, onEvent func(StreamEvent) error) (*Response, error)
+	})
+	if !ok {
+		writeGatewayError(w, http.StatusBadRequest, "invalid_request_error", "backend client does not support streaming")
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	created := time.Now().Unix()
+	id := fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+	started := false
+	announced := make(map[int]bool) // Tool calls whose id and name were sent
+	writeChunk := func(delta map[string]any, extra map[string]any) error {
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		chunk := map[string]any{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"choices": []map[string]any{{"index": 0, "delta": delta, "finish_reason": nil}},
+		}
+		for k, v := range extra {
+			chunk[k] = v
+		}
+		data, _ := json.Marshal(chunk)
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	// Chunks sent to the HTTP client can't be taken back, so streams resume rather than restart
+	_, err := streamer.CallStream(withAppendOnlyStream(ctx), req, func(event StreamEvent) error {
+		switch event.Type {
+		case StreamRestarted:
+			return ErrStreamNotRestartable
+		case StreamTextDelta:
+			return writeChunk(map[string]any{"content": event.Text}, nil)
+		case StreamReasoningDelta:
+			return writeChunk(map[string]any{"reasoning_content": event.Text}, nil)
+		case StreamToolCallDelta, StreamToolCallComplete:
+			arguments := event.Text
+			if event.Type == StreamToolCallComplete {
+				if announced[event.Index] {
+					return nil
+				}
+				arguments = event.ToolCall.Function.Arguments
+			}
+			call := map[string]any{"index": event.Index, "function": map[string]any{"arguments": arguments}}
+			if !announced[event.Index] {
+				// First fragment announces the call
+				announced[event.Index] = true
+				call["id"] = event.ToolCall.ID
+				call["type"] = "function"
+				call["function"].(map[string]any)["name"] = event.ToolCall.Function.Name
+			}
+			return writeChunk(map[string]any{"tool_calls": []map[string]any{call}}, nil)
+		case StreamDone:
+			response := event.Response
+			return writeChunk(map[string]any{}, map[string]any{
+				"model":   response.Model,
+				"choices": []map[string]any{{"index": 0, "delta": map[string]any{}, "finish_reason": gatewayFinishReason(response.FinishReason)}},
+				"usage":   openAIUsage(response.Usage),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			writeGatewayUpstreamError(w, err)
+			return
+		}
+		data, _ := json.Marshal(map[string]any{"error": map[string]any{"message": err.Error(), "type": "upstream_error"}})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	if started {
+		io.WriteString(w, "data: [DONE]\n\n")
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func (g *Gateway) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
+	embedder, ok := g.client.(interface {
+		Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error)
+	})
+	if !ok {
+		writeGatewayError(w, http.StatusBadRequest, "invalid_request_error", "backend client does not support embeddings")
+		return
+	}
+
+	var body struct {
+		Input json.RawMessage `json:"input"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxGatewayRequestBytes)).Decode(&body); err != nil {
+		writeGatewayError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	var inputs []string
+	var single string
+	if json.Unmarshal(body.Input, &single) == nil {
+		inputs = []string{single}
+	} else if err := json.Unmarshal(body.Input, &inputs); err != nil || len(inputs) == 0 {
+		writeGatewayError(w, http.StatusBadRequest, "invalid_request_error", "input must be a string or a non-empty array of strings")
+		return
+	}
+
+	result, err := embedder.Embed(r.Context(), inputs)
+	if err != nil {
+		if errors.Is(err, ErrEmbeddingsUnsupported) {
+			writeGatewayError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+			return
+		}
+		writeGatewayUpstreamError(w, err)
+		return
+	}
+	data := make([]map[string]any, len(result.Vectors))
+	for i, vector := range result.Vectors {
+		data[i] = map[string]any{"object": "embedding", "index": i, "embedding": vector}
+	}
+	writeGatewayJSON(w, http.StatusOK, map[string]any{
+		"object": "list",
+		"data":   data,
+		"model":  result.Model,
+		"usage":  map[string]any{"prompt_tokens": result.Usage.PromptTokens, "total_tokens": result.Usage.TotalTokens},
+	})
+}
+
+func (g *Gateway) handleModels(w http.ResponseWriter, r *http.Request) {
+	models := []map[string]any{}
+	if c, ok := g.client.(interface{ base() *Client }); ok {
+		models = append(models, map[string]any{"id": c.base().Model, "object": "model", "owned_by": c.base().Provider})
+	}
+	writeGatewayJSON(w, http.StatusOK, map[string]any{"object": "list", "data": models})
+}
+
+// openAIToolCalls converts tool calls to OpenAI wire format
+func openAIToolCalls(calls []ToolCall) []map[string]any {
+	out := make([]map[string]any, len(calls))
+	for i, call := range calls {
+		out[i] = map[string]any{
+			"id":       call.ID,
+			"type":     "function",
+			"function": map[string]any{"name": call.Function.Name, "arguments": call.Function.Arguments},
+		}
+	}
+	return out
+}
+
+// openAIUsage converts usage to OpenAI wire format
+func openAIUsage(usage TokenUsage) map[string]any {
+	return map[string]any{
+		"prompt_tokens":     usage.PromptTokens,
+		"completion_tokens": usage.CompletionTokens,
+		"total_tokens":      usage.TotalTokens,
+	}
+}
+
+// gatewayFinishReason returns finish reason as reported to OpenAI clients
+func gatewayFinishReason(reason string) string {
+	if reason == "" {
+		return FinishReasonStop
+	}
+	return reason
+}
+
+// writeGatewayUpstreamError reports a failed backend call
+func writeGatewayUpstreamError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrGuardrailBlocked), errors.Is(err, ErrPromptInjection):
+		writeGatewayError(w, http.StatusBadRequest, "content_policy_violation", err.Error())
+	case errors.Is(err, ErrInvalidJSONResponse):
+		writeGatewayError(w, http.StatusBadGateway, "invalid_response_error", err.Error())
+	case errors.Is(err, ErrQueueFull):
+		writeGatewayError(w, http.StatusTooManyRequests, "rate_limit_error", err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		writeGatewayError(w, http.StatusGatewayTimeout, "timeout_error", err.Error())
+	default:
+		writeGatewayError(w, http.StatusBadGateway, "upstream_error", err.Error())
+	}
+}
+
+// writeGatewayError writes OpenAI-style error body
+func writeGatewayError(w http.ResponseWriter, status int, kind, message string) {
+	writeGatewayJSON(w, status, map[string]any{"error": map[string]any{"message": message, "type": kind}})
+}
+
+func writeGatewayJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
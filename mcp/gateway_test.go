@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func gatewayPost(t *testing.T, server *httptest.Server, path, key, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp
+}
+
+func TestGateway_ChatCompletion(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"model":"deepseek-chat","choices":[{"message":{"content":"hold"},"finish_reason":"stop"}],"usage":{"prompt_tokens":7,"completion_tokens":1,"total_tokens":8}}`
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-upstream"),
+		WithLogger(NewNoopLogger()),
+	)
+	server := httptest.NewServer(NewGateway(client).WithKey("sk-local", "tests", 0, 0))
+	defer server.Close()
+
+	resp := gatewayPost(t, server, "/v1/chat/completions", "sk-local",
+		`{"model":"gpt-4o","messages":[{"role":"system","content":"sys"},{"role":"user","content":[{"type":"text","text":"BTC?"}]}],"temperature":0.2,"stop":"END"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var completion struct {
+		Object  string `json:"object"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	json.NewDecoder(resp.Body).Decode(&completion)
+	if completion.Object != "chat.completion" || completion.Choices[0].Message.Content != "hold" || completion.Usage.TotalTokens != 8 {
+		t.Errorf("unexpected completion %+v", completion)
+	}
+
+	sent := bodies[0]
+	if sent["model"] != DefaultDeepSeekModel || sent["temperature"] != 0.2 {
+		t.Errorf("upstream request model=%v temperature=%v, want client model and caller temperature", sent["model"], sent["temperature"])
+	}
+	if messages, _ := sent["messages"].([]any); len(messages) != 2 {
+		t.Errorf("upstream messages = %v", sent["messages"])
+	}
+}
+
+func TestGateway_AuthAndRateLimit(t *testing.T) {
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"}}]}`, new([]map[string]any))),
+		WithAPIKey("sk-upstream"),
+		WithLogger(NewNoopLogger()),
+	)
+	server := httptest.NewServer(NewGateway(client).WithKey("sk-local", "tests", 0.001, 1))
+	defer server.Close()
+
+	body := `{"messages":[{"role":"user","content":"hi"}]}`
+	if resp := gatewayPost(t, server, "/v1/chat/completions", "sk-wrong", body); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unknown key status = %d, want 401", resp.StatusCode)
+	}
+	if resp := gatewayPost(t, server, "/v1/chat/completions", "sk-local", body); resp.StatusCode != http.StatusOK {
+		t.Errorf("first request status = %d, want 200", resp.StatusCode)
+	}
+	if resp := gatewayPost(t, server, "/v1/chat/completions", "sk-local", body); resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("over-limit status = %d, want 429", resp.StatusCode)
+	}
+}
+
+func TestGateway_StreamingPassthrough(t *testing.T) {
+	stream := sseLines(
+		`{"choices":[{"delta":{"content":"buy "}}]}`,
+		`{"choices":[{"delta":{"content":"now"},"finish_reason":"stop"}]}`,
+	)
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithAPIKey("sk-upstream"),
+		WithLogger(NewNoopLogger()),
+	)
+	server := httptest.NewServer(NewGateway(client).WithAllowAnonymous())
+	defer server.Close()
+
+	resp := gatewayPost(t, server, "/v1/chat/completions", "", `{"stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	var (
+		text   string
+		finish string
+		done   bool
+	)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+				FinishReason *string `json:"finish_reason"`
+			} `json:"choices"`
+		}
+		json.Unmarshal([]byte(data), &chunk)
+		text += chunk.Choices[0].Delta.Content
+		if chunk.Choices[0].FinishReason != nil {
+			finish = *chunk.Choices[0].FinishReason
+		}
+	}
+	if text != "buy now" || finish != "stop" || !done {
+		t.Errorf("text = %q, finish = %q, done = %v", text, finish, done)
+	}
+}
+
+func TestGateway_Embeddings(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"model":"text-embedding-3-small","data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":4,"total_tokens":4}}`
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithProvider(ProviderOpenAI),
+		WithAPIKey("sk-upstream"),
+		WithLogger(NewNoopLogger()),
+	)
+	server := httptest.NewServer(NewGateway(client).WithAllowAnonymous())
+	defer server.Close()
+
+	resp := gatewayPost(t, server, "/v1/embeddings", "", `{"model":"any","input":["first","second"]}`)
+	defer resp.Body.Close()
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result.Data) != 2 || result.Data[0].Embedding[0] != 1 || result.Data[1].Embedding[1] != 1 {
+		t.Errorf("embeddings not returned in input order: %+v", result.Data)
+	}
+	if bodies[0]["model"] != "text-embedding-3-small" {
+		t.Errorf("upstream embedding model = %v", bodies[0]["model"])
+	}
+}
+
+func TestGateway_RejectsWithoutKeys(t *testing.T) {
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"}}]}`, new([]map[string]any))),
+		WithAPIKey("sk-upstream"),
+		WithLogger(NewNoopLogger()),
+	)
+	server := httptest.NewServer(NewGateway(client))
+	defer server.Close()
+
+	if resp := gatewayPost(t, server, "/v1/chat/completions", "", `{"messages":[{"role":"user","content":"hi"}]}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("gateway without keys status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestGateway_ToolRoundTrip(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"BTC is at 65000"},"finish_reason":"stop"}]}`, &bodies)),
+		WithAPIKey("sk-upstream"),
+		WithLogger(NewNoopLogger()),
+	)
+	server := httptest.NewServer(NewGateway(client).WithKey("sk-local", "tests", 0, 0))
+	defer server.Close()
+
+	resp := gatewayPost(t, server, "/v1/chat/completions", "sk-local", `{"messages":[
+		{"role":"user","content":"BTC price?"},
+		{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_price","arguments":"{\"symbol\":\"BTC\"}"}}]},
+		{"role":"tool","tool_call_id":"call_1","content":"{\"price\":65000}"}],
+		"tools":[{"type":"function","function":{"name":"get_price","parameters":{"type":"object"}}}]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	messages, _ := bodies[0]["messages"].([]any)
+	if len(messages) != 3 {
+		t.Fatalf("upstream messages = %v", bodies[0]["messages"])
+	}
+	calls, _ := messages[1].(map[string]any)["tool_calls"].([]any)
+	if len(calls) != 1 || calls[0].(map[string]any)["id"] != "call_1" {
+		t.Errorf("assistant tool calls not forwarded: %v", messages[1])
+	}
+	if result := messages[2].(map[string]any); result["role"] != "tool" || result["tool_call_id"] != "call_1" {
+		t.Errorf("tool result not forwarded: %v", result)
+	}
+
+	resp = gatewayPost(t, server, "/v1/chat/completions", "sk-local", `{"messages":[{"role":"tool","content":"{}"}]}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("tool message without tool_call_id status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestGateway_StreamRetryDoesNotRepeatChunks(t *testing.T) {
+	var sent []map[string]any
+	client := NewClient(WithAPIKey("sk-upstream"), WithLogger(NewNoopLogger()), WithMaxRetries(3), WithRetryWaitBase(time.Millisecond),
+		WithStreamRetry(StreamRetryRestart),
+		WithHTTPClient(sequenceSSEClient(&sent,
+			sseLines(`{"choices":[{"delta":{"content":"The trend is "}}]}`),
+			sseLines(`{"choices":[{"delta":{"content":"bullish."},"finish_reason":"stop"}]}`)+"data: [DONE]\n\n",
+		)))
+	server := httptest.NewServer(NewGateway(client).WithAllowAnonymous())
+	defer server.Close()
+
+	resp := gatewayPost(t, server, "/v1/chat/completions", "", `{"stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	defer resp.Body.Close()
+	var text string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		json.Unmarshal([]byte(data), &chunk)
+		if len(chunk.Choices) > 0 {
+			text += chunk.Choices[0].Delta.Content
+		}
+	}
+	if text != "The trend is bullish." || len(sent) != 2 {
+		t.Errorf("sent chunks must not repeat, got %q after %d requests", text, len(sent))
+	}
+}
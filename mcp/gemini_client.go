@@ -52,7 +52,7 @@ func (c *GeminiClient) SetAPIKey(apiKey string, customURL string, customModel st
 		c.logger.Infof("🔧 [MCP] Gemini API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
 	}
 	if customURL != "" {
-		c.BaseURL = customURL
+		c.setBaseURL(customURL)
 		c.logger.Infof("🔧 [MCP] Gemini using custom BaseURL: %s", customURL)
 	} else {
 		c.logger.Infof("🔧 [MCP] Gemini using default BaseURL: %s", c.BaseURL)
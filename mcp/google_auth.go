@@ -0,0 +1,283 @@
+package mcp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GoogleCloudScope OAuth scope of Google Cloud APIs (Vertex AI)
+const GoogleCloudScope = "https://www.googleapis.com/auth/cloud-platform"
+
+const (
+	googleTokenURL        = "https://oauth2.googleapis.com/token"
+	googleDefaultMetadata = "metadata.google.internal"
+)
+
+// ErrNoGoogleCredentials is returned when Application Default Credentials are not found
+var ErrNoGoogleCredentials = errors.New("google application default credentials not found")
+
+// googleCredentials credentials file (service account key or gcloud user credentials)
+type googleCredentials struct {
+	Type string `json:"type"` // "service_account" or "authorized_user"
+
+	// service_account
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+
+	// authorized_user
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// googleTokenSource fetches Google OAuth access tokens for one credential
+type googleTokenSource struct {
+	credentials *googleCredentials // nil = GCE metadata server
+	scopes      []string
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// NewGoogleADCTokenSource returns Google Application Default Credentials
+//
+// Credentials are resolved like the Google SDKs do, on first use: the file named by
+// GOOGLE_APPLICATION_CREDENTIALS, then gcloud's application_default_credentials.json,
+// then the metadata server on GCE, GKE and Cloud Run. Service account keys and
+// gcloud user credentials are supported. Scopes default to GoogleCloudScope.
+//
+// Usage example:
+//   client := mcp.NewVertexClientWithOptions(
+//       mcp.WithVertexProject("my-project", "us-central1"),
+//       mcp.WithTokenSource(mcp.NewGoogleADCTokenSource()),
+//   )
+func NewGoogleADCTokenSource(scopes ...string) TokenSource {
+	if len(scopes) == 0 {
+		scopes = []string{GoogleCloudScope}
+	}
+	var (
+		mu     sync.Mutex
+		source TokenSource
+	)
+	return TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+		mu.Lock()
+		if source == nil {
+			credentials, err := findGoogleCredentials()
+			if err != nil {
+				mu.Unlock()
+				return nil, err
+			}
+			source = newGoogleTokenSource(credentials, scopes)
+		}
+		mu.Unlock()
+		return source.Token(ctx)
+	})
+}
+
+// NewGoogleCredentialsTokenSource returns tokens for a service account key or gcloud user credentials JSON
+func NewGoogleCredentialsTokenSource(credentialsJSON []byte, scopes ...string) (TokenSource, error) {
+	if len(scopes) == 0 {
+		scopes = []string{GoogleCloudScope}
+	}
+	credentials, err := parseGoogleCredentials(credentialsJSON)
+	if err != nil {
+		return nil, err
+	}
+	return newGoogleTokenSource(credentials, scopes), nil
+}
+
+func newGoogleTokenSource(credentials *googleCredentials, scopes []string) *googleTokenSource {
+	return &googleTokenSource{
+		credentials: credentials,
+		scopes:      scopes,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+	}
+}
+
+// findGoogleCredentials resolves Application Default Credentials (nil = metadata server)
+func findGoogleCredentials() (*googleCredentials, error) {
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GOOGLE_APPLICATION_CREDENTIALS: %w", err)
+		}
+		return parseGoogleCredentials(data)
+	}
+
+	wellKnown := filepath.Join(os.Getenv("HOME"), ".config", "gcloud", "application_default_credentials.json")
+	if runtime.GOOS == "windows" {
+		wellKnown = filepath.Join(os.Getenv("APPDATA"), "gcloud", "application_default_credentials.json")
+	}
+	if data, err := os.ReadFile(wellKnown); err == nil {
+		return parseGoogleCredentials(data)
+	}
+
+	if os.Getenv("GCE_METADATA_HOST") != "" || onGoogleCloud() {
+		return nil, nil
+	}
+	return nil, ErrNoGoogleCredentials
+}
+
+// onGoogleCloud reports whether the metadata server is reachable
+func onGoogleCloud() bool {
+	client := &http.Client{Timeout: 500 * time.Millisecond}
+	resp, err := client.Get("http://" + googleDefaultMetadata)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.Header.Get("Metadata-Flavor") == "Google"
+}
+
+func parseGoogleCredentials(data []byte) (*googleCredentials, error) {
+	var credentials googleCredentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid google credentials: %w", err)
+	}
+	switch credentials.Type {
+	case "service_account":
+		if credentials.ClientEmail == "" || credentials.PrivateKey == "" {
+			return nil, errors.New("invalid google credentials: service account key needs client_email and private_key")
+		}
+	case "authorized_user":
+		if credentials.RefreshToken == "" {
+			return nil, errors.New("invalid google credentials: authorized_user needs refresh_token")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported google credentials type %q", credentials.Type)
+	}
+	return &credentials, nil
+}
+
+func (s *googleTokenSource) Token(ctx context.Context) (*OAuthToken, error) {
+	switch {
+	case s.credentials == nil:
+		return s.metadataToken(ctx)
+	case s.credentials.Type == "service_account":
+		assertion, err := s.signedJWT()
+		if err != nil {
+			return nil, err
+		}
+		return s.exchange(ctx, s.tokenURL(), url.Values{
+			"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			"assertion":  {assertion},
+		})
+	default:
+		return s.exchange(ctx, s.tokenURL(), url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {s.credentials.ClientID},
+			"client_secret": {s.credentials.ClientSecret},
+			"refresh_token": {s.credentials.RefreshToken},
+		})
+	}
+}
+
+func (s *googleTokenSource) tokenURL() string {
+	if s.credentials.TokenURI != "" {
+		return s.credentials.TokenURI
+	}
+	return googleTokenURL
+}
+
+// signedJWT builds the RS256 assertion of a service account token request
+func (s *googleTokenSource) signedJWT() (string, error) {
+	block, _ := pem.Decode([]byte(s.credentials.PrivateKey))
+	if block == nil {
+		return "", errors.New("invalid service account private key: no PEM block")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if err != nil || !ok {
+		return "", fmt.Errorf("invalid service account private key: %v", err)
+	}
+
+	now := s.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": s.credentials.PrivateKeyID})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   s.credentials.ClientEmail,
+		"scope": strings.Join(s.scopes, " "),
+		"aud":   s.tokenURL(),
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign service account assertion: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// exchange posts an OAuth token request
+func (s *googleTokenSource) exchange(ctx context.Context, tokenURL string, form url.Values) (*OAuthToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("fail to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return s.doTokenRequest(req)
+}
+
+// metadataToken fetches the token of the attached service account from the metadata server
+func (s *googleTokenSource) metadataToken(ctx context.Context) (*OAuthToken, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = googleDefaultMetadata
+	}
+	tokenURL := "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token?scopes=" + url.QueryEscape(strings.Join(s.scopes, ","))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fail to build token request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	return s.doTokenRequest(req)
+}
+
+func (s *googleTokenSource) doTokenRequest(req *http.Request) (*OAuthToken, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.AccessToken == "" {
+		return nil, fmt.Errorf("invalid token response: %s", string(body))
+	}
+	token := &OAuthToken{AccessToken: result.AccessToken}
+	if result.ExpiresIn > 0 {
+		token.Expiry = s.now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test Google credentials
+// ============================================================
+
+func TestGoogleCredentials_ServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+
+	var assertion string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("unexpected grant_type %q", r.Form.Get("grant_type"))
+		}
+		assertion = r.Form.Get("assertion")
+		w.Write([]byte(`{"access_token":"ya29.sa","expires_in":3600,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	credentials, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "bot@project.iam.gserviceaccount.com",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"private_key_id": "key-1",
+		"token_uri":      server.URL,
+	})
+	source, err := NewGoogleCredentialsTokenSource(credentials)
+	if err != nil {
+		t.Fatalf("failed to load credentials: %v", err)
+	}
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("token exchange failed: %v", err)
+	}
+	if token.AccessToken != "ya29.sa" || token.Expiry.IsZero() {
+		t.Errorf("unexpected token %+v", token)
+	}
+
+	// Assertion is an RS256 JWT signed with the service account key
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed assertion %q", assertion)
+	}
+	var claims map[string]any
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	json.Unmarshal(payload, &claims)
+	if claims["iss"] != "bot@project.iam.gserviceaccount.com" || claims["scope"] != GoogleCloudScope || claims["aud"] != server.URL {
+		t.Errorf("unexpected claims %v", claims)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("invalid assertion signature: %v", err)
+	}
+}
+
+func TestGoogleCredentials_AuthorizedUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "1//refresh" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+		w.Write([]byte(`{"access_token":"ya29.user","expires_in":3599}`))
+	}))
+	defer server.Close()
+
+	source, err := NewGoogleCredentialsTokenSource([]byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"1//refresh","token_uri":"` + server.URL + `"}`))
+	if err != nil {
+		t.Fatalf("failed to load credentials: %v", err)
+	}
+	token, err := source.Token(context.Background())
+	if err != nil || token.AccessToken != "ya29.user" {
+		t.Errorf("unexpected token %v, %v", token, err)
+	}
+}
+
+func TestGoogleCredentials_Invalid(t *testing.T) {
+	for _, data := range []string{`not json`, `{"type":"external_account"}`, `{"type":"service_account"}`} {
+		if _, err := NewGoogleCredentialsTokenSource([]byte(data)); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+}
+
+func TestGoogleADC_MetadataServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" || !strings.HasSuffix(r.URL.Path, "/service-accounts/default/token") {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"access_token":"ya29.gce","expires_in":1800}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	token, err := NewGoogleADCTokenSource().Token(context.Background())
+	if err != nil || token.AccessToken != "ya29.gce" {
+		t.Errorf("unexpected token %v, %v", token, err)
+	}
+}
@@ -52,7 +52,7 @@ func (c *GrokClient) SetAPIKey(apiKey string, customURL string, customModel stri
 		c.logger.Infof("🔧 [MCP] Grok API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
 	}
 	if customURL != "" {
-		c.BaseURL = customURL
+		c.setBaseURL(customURL)
 		c.logger.Infof("🔧 [MCP] Grok using custom BaseURL: %s", customURL)
 	} else {
 		c.logger.Infof("🔧 [MCP] Grok using default BaseURL: %s", c.BaseURL)
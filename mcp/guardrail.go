@@ -0,0 +1,327 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GuardrailStage where a guardrail runs (bit flags)
+type GuardrailStage int
+
+const (
+	// GuardInput checks prompts before they are sent
+	GuardInput GuardrailStage = 1 << iota
+	// GuardOutput checks responses before they are returned
+	GuardOutput
+
+	// GuardBoth checks prompts and responses
+	GuardBoth = GuardInput | GuardOutput
+)
+
+func (s GuardrailStage) String() string {
+	switch s {
+	case GuardInput:
+		return "input"
+	case GuardOutput:
+		return "output"
+	case GuardBoth:
+		return "input+output"
+	}
+	return fmt.Sprintf("stage(%d)", int(s))
+}
+
+// GuardrailAction what happens when a guardrail is violated
+type GuardrailAction int
+
+const (
+	// GuardrailBlock fails the call with a *GuardrailError
+	GuardrailBlock GuardrailAction = iota
+	// GuardrailRedact replaces the offending text (falls back to block when the guardrail can't redact)
+	GuardrailRedact
+	// GuardrailAnnotate lets the text through and records the violation (Response.Violations)
+	GuardrailAnnotate
+)
+
+// ErrGuardrailBlocked is matched (errors.Is) by GuardrailError
+var ErrGuardrailBlocked = errors.New("blocked by guardrail")
+
+// GuardrailError call was blocked by a guardrail
+type GuardrailError struct {
+	Guardrail string
+	Stage     GuardrailStage
+	Reason    string
+}
+
+func (e *GuardrailError) Error() string {
+	return fmt.Sprintf("%s blocked by guardrail %s: %s", e.Stage, e.Guardrail, e.Reason)
+}
+
+func (e *GuardrailError) Is(target error) bool {
+	return target == ErrGuardrailBlocked
+}
+
+// GuardrailViolation one guardrail finding
+type GuardrailViolation struct {
+	Guardrail string
+	Stage     GuardrailStage
+	Action    GuardrailAction
+	Reason    string
+}
+
+// GuardrailResult outcome of one guardrail check
+type GuardrailResult struct {
+	Violated bool
+	Reason   string
+	Redacted string // Text with offending parts removed ("" = guardrail can't redact)
+}
+
+// Guardrail validates prompt or response text
+type Guardrail interface {
+	Name() string
+	Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error)
+}
+
+// GuardrailRule guardrail with the stages it runs at and its action on violation
+type GuardrailRule struct {
+	Guardrail Guardrail
+	Stage     GuardrailStage  // 0 = GuardBoth
+	Action    GuardrailAction // Default GuardrailBlock
+}
+
+// WithGuardrails adds guardrails run, in order, before and after every call
+//
+// Input guardrails see every prompt message; output guardrails see the response text.
+// A blocking violation fails the call with a *GuardrailError (errors.Is
+// ErrGuardrailBlocked) without contacting the provider (input) or returning the
+// answer (output). Redactions are applied before the next guardrail runs.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithGuardrails(
+//       mcp.GuardrailRule{Guardrail: mcp.MaxLength(20000), Stage: mcp.GuardInput},
+//       mcp.GuardrailRule{Guardrail: mcp.RegexDenylist("secrets", `sk-[A-Za-z0-9]{20,}`), Action: mcp.GuardrailRedact},
+//       mcp.GuardrailRule{Guardrail: mcp.ProfanityFilter(), Stage: mcp.GuardOutput, Action: mcp.GuardrailAnnotate},
+//   ))
+func WithGuardrails(rules ...GuardrailRule) ClientOption {
+	return func(c *Config) {
+		c.Guardrails = append(c.Guardrails, rules...)
+	}
+}
+
+// guardRequest applies input guardrails to a copy of req's messages
+func (client *Client) guardRequest(ctx context.Context, req *Request) (*Request, []GuardrailViolation, error) {
+	if len(client.config.Guardrails) == 0 {
+		return req, nil, nil
+	}
+	guarded := *req
+	guarded.Messages = append([]Message(nil), req.Messages...)
+	texts := make([]*string, len(guarded.Messages))
+	for i := range guarded.Messages {
+		texts[i] = &guarded.Messages[i].Content
+	}
+	violations, err := client.runGuardrails(ctx, GuardInput, texts...)
+	return &guarded, violations, err
+}
+
+// runGuardrails applies guardrails of stage to texts in place
+func (client *Client) runGuardrails(ctx context.Context, stage GuardrailStage, texts ...*string) ([]GuardrailViolation, error) {
+	var violations []GuardrailViolation
+	for _, rule := range client.config.Guardrails {
+		ruleStage := rule.Stage
+		if ruleStage == 0 {
+			ruleStage = GuardBoth
+		}
+		if ruleStage&stage == 0 {
+			continue
+		}
+
+		name := rule.Guardrail.Name()
+		for _, text := range texts {
+			if *text == "" {
+				continue
+			}
+			result, err := rule.Guardrail.Check(ctx, stage, *text)
+			if err != nil {
+				return violations, fmt.Errorf("guardrail %s failed: %w", name, err)
+			}
+			if !result.Violated {
+				continue
+			}
+
+			violations = append(violations, GuardrailViolation{Guardrail: name, Stage: stage, Action: rule.Action, Reason: result.Reason})
+			switch {
+			case rule.Action == GuardrailAnnotate:
+				client.logger.Warnf("⚠️  [MCP] Guardrail %s flagged %s: %s", name, stage, result.Reason)
+			case rule.Action == GuardrailRedact && result.Redacted != "":
+				*text = result.Redacted
+			default:
+				return violations, &GuardrailError{Guardrail: name, Stage: stage, Reason: result.Reason}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// ============================================================
+// Built-in guardrails
+// ============================================================
+
+// RegexDenylist flags text matching any pattern; redaction replaces matches with [REDACTED]
+//
+// Patterns are compiled once and panic on invalid syntax (like regexp.MustCompile).
+func RegexDenylist(name string, patterns ...string) Guardrail {
+	denylist := &regexGuardrail{name: name}
+	for _, pattern := range patterns {
+		denylist.patterns = append(denylist.patterns, regexp.MustCompile(pattern))
+	}
+	return denylist
+}
+
+type regexGuardrail struct {
+	name     string
+	patterns []*regexp.Regexp
+}
+
+func (g *regexGuardrail) Name() string { return g.name }
+
+func (g *regexGuardrail) Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error) {
+	var result GuardrailResult
+	redacted := text
+	for _, re := range g.patterns {
+		if re.MatchString(redacted) {
+			if !result.Violated {
+				result.Reason = fmt.Sprintf("matches denied pattern %s", re)
+			}
+			result.Violated = true
+			redacted = re.ReplaceAllString(redacted, "[REDACTED]")
+		}
+	}
+	if result.Violated {
+		result.Redacted = redacted
+	}
+	return result, nil
+}
+
+// MaxLength flags text longer than maxChars characters; redaction truncates it
+func MaxLength(maxChars int) Guardrail {
+	return maxLengthGuardrail(maxChars)
+}
+
+type maxLengthGuardrail int
+
+func (g maxLengthGuardrail) Name() string { return "max_length" }
+
+func (g maxLengthGuardrail) Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error) {
+	runes := []rune(text)
+	if len(runes) <= int(g) {
+		return GuardrailResult{}, nil
+	}
+	return GuardrailResult{
+		Violated: true,
+		Reason:   fmt.Sprintf("%d characters exceeds limit of %d", len(runes), int(g)),
+		Redacted: string(runes[:int(g)]),
+	}, nil
+}
+
+// defaultProfanity small built-in word list (extend via ProfanityFilter arguments)
+var defaultProfanity = []string{"fuck", "shit", "bitch", "bastard", "asshole", "cunt"}
+
+// ProfanityFilter flags profanity (built-in list plus extra words, whole words, case-insensitive)
+//
+// Redaction masks the words with asterisks.
+func ProfanityFilter(extra ...string) Guardrail {
+	words := make([]string, 0, len(defaultProfanity)+len(extra))
+	for _, word := range append(append([]string{}, defaultProfanity...), extra...) {
+		words = append(words, regexp.QuoteMeta(strings.ToLower(word)))
+	}
+	return &profanityGuardrail{pattern: regexp.MustCompile(`(?i)\b(` + strings.Join(words, "|") + `)\w*`)}
+}
+
+type profanityGuardrail struct {
+	pattern *regexp.Regexp
+}
+
+func (g *profanityGuardrail) Name() string { return "profanity" }
+
+func (g *profanityGuardrail) Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error) {
+	match := g.pattern.FindString(text)
+	if match == "" {
+		return GuardrailResult{}, nil
+	}
+	return GuardrailResult{
+		Violated: true,
+		Reason:   "contains profanity",
+		Redacted: g.pattern.ReplaceAllStringFunc(text, func(word string) string {
+			return strings.Repeat("*", len([]rune(word)))
+		}),
+	}, nil
+}
+
+// GuardrailFunc adapts a Go validator to a Guardrail; a non-nil error is a violation
+//
+// Usage example:
+//   noLeverage := mcp.GuardrailFunc("max_leverage", func(ctx context.Context, stage mcp.GuardrailStage, text string) error {
+//       if strings.Contains(text, "100x") {
+//           return errors.New("leverage above policy")
+//       }
+//       return nil
+//   })
+func GuardrailFunc(name string, fn func(ctx context.Context, stage GuardrailStage, text string) error) Guardrail {
+	return &funcGuardrail{name: name, fn: fn}
+}
+
+type funcGuardrail struct {
+	name string
+	fn   func(ctx context.Context, stage GuardrailStage, text string) error
+}
+
+func (g *funcGuardrail) Name() string { return g.name }
+
+func (g *funcGuardrail) Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error) {
+	if err := g.fn(ctx, stage, text); err != nil {
+		return GuardrailResult{Violated: true, Reason: err.Error()}, nil
+	}
+	return GuardrailResult{}, nil
+}
+
+// ModerationGuardrail asks a moderation model whether text is safe
+//
+// The model must answer "SAFE" or "UNSAFE: <reason>". Can't redact.
+func ModerationGuardrail(moderator AIClient) Guardrail {
+	return &moderationGuardrail{moderator: moderator}
+}
+
+type moderationGuardrail struct {
+	moderator AIClient
+}
+
+func (g *moderationGuardrail) Name() string { return "moderation_model" }
+
+func (g *moderationGuardrail) Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error) {
+	req, err := NewRequestBuilder().
+		WithSystemPrompt("You are a content moderator. Decide whether the text is safe: no hate, harassment, " +
+			"self-harm, sexual content involving minors, violence instructions or illegal activity. " +
+			`Answer exactly "SAFE" or "UNSAFE: <short reason>".`).
+		WithUserPrompt(text).
+		WithTemperature(0).
+		Build()
+	if err != nil {
+		return GuardrailResult{}, err
+	}
+	verdict, err := callRequest(ctx, g.moderator, req)
+	if err != nil {
+		return GuardrailResult{}, err
+	}
+
+	verdict = strings.TrimSpace(verdict)
+	if strings.HasPrefix(strings.ToUpper(verdict), "UNSAFE") {
+		reason := strings.TrimSpace(strings.TrimLeft(verdict[len("UNSAFE"):], ": "))
+		if reason == "" {
+			reason = "flagged by moderation model"
+		}
+		return GuardrailResult{Violated: true, Reason: reason}, nil
+	}
+	return GuardrailResult{}, nil
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGuardrails_BlockInput(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithGuardrails(GuardrailRule{Guardrail: MaxLength(10), Stage: GuardInput}),
+	)
+
+	_, err := client.CallWithMessages("system", "this prompt is far too long")
+	var guardErr *GuardrailError
+	if !errors.As(err, &guardErr) || !errors.Is(err, ErrGuardrailBlocked) {
+		t.Fatalf("expected GuardrailError, got %v", err)
+	}
+	if guardErr.Guardrail != "max_length" || guardErr.Stage != GuardInput {
+		t.Errorf("unexpected error details %+v", guardErr)
+	}
+	if len(bodies) != 0 {
+		t.Error("blocked prompt must not reach the provider")
+	}
+}
+
+func TestGuardrails_RedactAndAnnotate(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"this shit is bullish"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithGuardrails(
+			GuardrailRule{Guardrail: RegexDenylist("secrets", `sk-[A-Za-z0-9]{8,}`), Stage: GuardInput, Action: GuardrailRedact},
+			GuardrailRule{Guardrail: ProfanityFilter(), Stage: GuardOutput, Action: GuardrailAnnotate},
+		),
+	).(*Client)
+
+	req := NewRequestBuilder().WithUserPrompt("my key is sk-abcdef123456, analyze BTC").MustBuild()
+	response, err := client.Call(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages := bodies[0]["messages"].([]any)
+	sent := messages[0].(map[string]any)["content"].(string)
+	if strings.Contains(sent, "sk-abcdef") || !strings.Contains(sent, "[REDACTED]") {
+		t.Errorf("secret should be redacted before sending, got %q", sent)
+	}
+	if !strings.Contains(req.Messages[0].Content, "sk-abcdef") {
+		t.Error("caller's request must not be modified")
+	}
+
+	if response.Text != "this shit is bullish" {
+		t.Errorf("annotate must not change text, got %q", response.Text)
+	}
+	if len(response.Violations) != 2 || response.Violations[1].Guardrail != "profanity" {
+		t.Errorf("expected redaction and profanity findings, got %+v", response.Violations)
+	}
+}
+
+func TestGuardrails_OutputBlockAndCustom(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"open 100x long"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithGuardrails(GuardrailRule{
+			Guardrail: GuardrailFunc("max_leverage", func(ctx context.Context, stage GuardrailStage, text string) error {
+				if strings.Contains(text, "100x") {
+					return errors.New("leverage above policy")
+				}
+				return nil
+			}),
+			Stage:  GuardOutput,
+			Action: GuardrailRedact, // Can't redact: falls back to block
+		}),
+	)
+
+	_, err := client.CallWithMessages("system", "decide")
+	if !errors.Is(err, ErrGuardrailBlocked) || !strings.Contains(err.Error(), "leverage above policy") {
+		t.Errorf("expected output to be blocked, got %v", err)
+	}
+}
+
+func TestBuiltinGuardrails(t *testing.T) {
+	ctx := context.Background()
+
+	result, _ := ProfanityFilter("scam").Check(ctx, GuardOutput, "Total SCAM coin")
+	if !result.Violated || result.Redacted != "Total **** coin" {
+		t.Errorf("extra words should be masked, got %+v", result)
+	}
+	if result, _ := ProfanityFilter().Check(ctx, GuardOutput, "Scunthorpe assessment"); result.Violated {
+		t.Error("only whole words should match")
+	}
+
+	moderator := &stubClient{answer: "UNSAFE: market manipulation"}
+	result, err := ModerationGuardrail(moderator).Check(ctx, GuardInput, "let's pump and dump")
+	if err != nil || !result.Violated || result.Reason != "market manipulation" {
+		t.Errorf("unexpected moderation result %+v, %v", result, err)
+	}
+	moderator.answer = "SAFE"
+	if result, _ := ModerationGuardrail(moderator).Check(ctx, GuardInput, "hello"); result.Violated {
+		t.Error("SAFE verdict should pass")
+	}
+}
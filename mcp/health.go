@@ -0,0 +1,266 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthStatus structured result of a provider health check
+type HealthStatus struct {
+	Provider       string
+	Model          string
+	Reachable      bool          // Endpoint answered at HTTP level
+	Authenticated  bool          // API key was accepted
+	ModelAvailable bool          // Configured model is served by the endpoint
+	Method         string        // Probe used: "models" or "completion"
+	StatusCode     int           // HTTP status of the probe
+	Latency        time.Duration // Probe round-trip latency
+	CheckedAt      time.Time
+	Error          error // Last error encountered (nil when healthy)
+}
+
+// Healthy returns true if provider is reachable, authenticated and serves the model
+func (s HealthStatus) Healthy() bool {
+	return s.Reachable && s.Authenticated && s.ModelAvailable
+}
+
+// HealthCheck probes provider with a minimal request
+//
+// The models list endpoint is tried first since it costs no tokens; when the
+// provider doesn't expose it, a one-token completion is issued instead.
+//
+// Usage example:
+//   status := client.HealthCheck(ctx)
+//   if !status.Healthy() {
+//       log.Printf("AI provider unhealthy: %v", status.Error)
+//   }
+func (client *Client) HealthCheck(ctx context.Context) HealthStatus {
+	status := HealthStatus{
+		Provider:  client.Provider,
+		Model:     client.Model,
+		CheckedAt: time.Now(),
+	}
+
+	if err := client.checkAPIKey(); err != nil {
+		status.Error = err
+		return status
+	}
+
+	start := time.Now()
+	done, err := client.probeModels(ctx, &status)
+	if !done && err == nil {
+		err = client.probeCompletion(ctx, &status)
+	}
+	status.Latency = time.Since(start)
+	status.Error = err
+
+	return status
+}
+
+// probeModels checks provider via GET {BaseURL}/models
+//
+// Returns done=false when the endpoint is not supported and another probe is needed.
+func (client *Client) probeModels(ctx context.Context, status *HealthStatus) (bool, error) {
+	status.Method = "models"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.endpointURL(EndpointModels), nil)
+	if err != nil {
+		return true, fmt.Errorf("fail to build health check request: %w", err)
+	}
+	client.hooks.setAuthHeader(req.Header)
+	client.setClientHeaders(req.Header)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	status.Reachable = true
+	status.StatusCode = resp.StatusCode
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return true, fmt.Errorf("authentication failed (status %d): %s", resp.StatusCode, string(body))
+	case resp.StatusCode != http.StatusOK:
+		// Endpoint not supported (404/405) or unexpected answer: fall back to completion probe
+		return false, nil
+	}
+
+	status.Authenticated = true
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &models); err != nil || len(models.Data) == 0 {
+		// Unknown list format, let completion probe decide on model availability
+		return false, nil
+	}
+
+	for _, m := range models.Data {
+		if m.ID == client.Model || strings.TrimPrefix(m.ID, "models/") == client.Model {
+			status.ModelAvailable = true
+			return true, nil
+		}
+	}
+	return true, fmt.Errorf("model %s not found in provider model list", client.Model)
+}
+
+// probeCompletion checks provider via a one-token completion
+func (client *Client) probeCompletion(ctx context.Context, status *HealthStatus) error {
+	status.Method = "completion"
+
+	requestBody := client.hooks.buildMCPRequestBody("", "ping")
+	for _, key := range []string{"max_tokens", "max_completion_tokens"} {
+		if _, ok := requestBody[key]; ok {
+			requestBody[key] = 1
+		}
+	}
+
+	jsonData, err := client.hooks.marshalRequestBody(requestBody)
+	if err != nil {
+		return err
+	}
+	req, err := client.hooks.buildRequest(client.hooks.buildUrl(), jsonData)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	status.Reachable = true
+	status.StatusCode = resp.StatusCode
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		status.Authenticated = true
+		status.ModelAvailable = true
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("authentication failed (status %d): %s", resp.StatusCode, string(body))
+	case http.StatusNotFound:
+		status.Authenticated = true
+		return fmt.Errorf("model %s not available (status %d): %s", client.Model, resp.StatusCode, string(body))
+	default:
+		return fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
+	}
+}
+
+// ============================================================
+// Background Health Prober
+// ============================================================
+
+// HealthChecker anything able to report provider health (all provider clients embed Client)
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) HealthStatus
+}
+
+// HealthProber periodically runs health checks in the background
+//
+// Every result is passed to the listener, which can feed a circuit breaker,
+// fallback chain or monitoring system.
+type HealthProber struct {
+	checker  HealthChecker
+	interval time.Duration
+	listener func(HealthStatus)
+
+	mu     sync.RWMutex
+	last   HealthStatus
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewHealthProber creates prober checking client every interval
+//
+// Usage example:
+//   prober := mcp.NewHealthProber(client.(mcp.HealthChecker), time.Minute, func(s mcp.HealthStatus) {
+//       if !s.Healthy() {
+//           alert(s.Error)
+//       }
+//   })
+//   prober.Start(ctx)
+//   defer prober.Stop()
+func NewHealthProber(checker HealthChecker, interval time.Duration, listener func(HealthStatus)) *HealthProber {
+	return &HealthProber{
+		checker:  checker,
+		interval: interval,
+		listener: listener,
+	}
+}
+
+// Start starts probing in background (first probe runs immediately)
+func (p *HealthProber) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		return
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.probe(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops background probing and waits for the running probe to finish
+func (p *HealthProber) Stop() {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.cancel = nil
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Last returns most recent health status
+func (p *HealthProber) Last() HealthStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.last
+}
+
+func (p *HealthProber) probe(ctx context.Context) {
+	status := p.checker.HealthCheck(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.last = status
+	p.mu.Unlock()
+
+	if p.listener != nil {
+		p.listener(status)
+	}
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Health Check
+// ============================================================
+
+func newHealthTestClient(responseFunc func(req *http.Request) (*http.Response, error)) *Client {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = responseFunc
+	return NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+	).(*Client)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+func TestHealthCheck_ModelsEndpoint(t *testing.T) {
+	client := newHealthTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet || req.URL.Path != "/models" {
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		return jsonResponse(200, `{"data":[{"id":"deepseek-chat"},{"id":"deepseek-reasoner"}]}`), nil
+	})
+
+	status := client.HealthCheck(context.Background())
+	if !status.Healthy() {
+		t.Fatalf("expected healthy, got %+v", status)
+	}
+	if status.Method != "models" {
+		t.Errorf("expected models probe, got %s", status.Method)
+	}
+}
+
+func TestHealthCheck_FallbackToCompletion(t *testing.T) {
+	client := newHealthTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			return jsonResponse(404, "not found"), nil
+		}
+		return jsonResponse(200, `{"choices":[{"message":{"content":"p"}}]}`), nil
+	})
+
+	status := client.HealthCheck(context.Background())
+	if !status.Healthy() {
+		t.Fatalf("expected healthy, got %+v", status)
+	}
+	if status.Method != "completion" {
+		t.Errorf("expected completion probe, got %s", status.Method)
+	}
+}
+
+func TestHealthCheck_Unauthorized(t *testing.T) {
+	client := newHealthTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(401, `{"error":"invalid key"}`), nil
+	})
+
+	status := client.HealthCheck(context.Background())
+	if !status.Reachable || status.Authenticated {
+		t.Errorf("expected reachable but unauthenticated, got %+v", status)
+	}
+	if status.Error == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestHealthCheck_Unreachable(t *testing.T) {
+	client := newHealthTestClient(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	status := client.HealthCheck(context.Background())
+	if status.Reachable {
+		t.Error("expected unreachable")
+	}
+}
+
+func TestHealthCheck_ModelMissing(t *testing.T) {
+	client := newHealthTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(200, `{"data":[{"id":"other-model"}]}`), nil
+	})
+
+	status := client.HealthCheck(context.Background())
+	if !status.Authenticated || status.ModelAvailable {
+		t.Errorf("expected authenticated without model, got %+v", status)
+	}
+}
+
+func TestHealthProber_FeedsListener(t *testing.T) {
+	client := newHealthTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(200, `{"data":[{"id":"deepseek-chat"}]}`), nil
+	})
+
+	results := make(chan HealthStatus, 10)
+	prober := NewHealthProber(client, 10*time.Millisecond, func(s HealthStatus) { results <- s })
+	prober.Start(context.Background())
+
+	select {
+	case s := <-results:
+		if !s.Healthy() {
+			t.Errorf("expected healthy status, got %+v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("listener was not called")
+	}
+
+	prober.Stop()
+	if !prober.Last().Healthy() {
+		t.Error("Last should return healthy status")
+	}
+}
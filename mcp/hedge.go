@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HedgeStats outcome counters of hedged calls
+type HedgeStats struct {
+	Calls  int            // Calls made with hedging enabled
+	Hedged int            // Calls that fired at least one extra request
+	Wins   map[string]int // Successful calls by winning request ("primary", "hedge-1", ...)
+}
+
+// hedgeState hedging configuration and counters
+type hedgeState struct {
+	delay    time.Duration
+	maxExtra int
+	targets  []AIClient
+
+	mu    sync.Mutex
+	stats HedgeStats
+}
+
+// hedgeAttemptKey marks contexts of requests made by the hedger (never hedged again)
+type hedgeAttemptKey struct{}
+
+// firstByteKey context key of the callback signalling a successful response start
+type firstByteKey struct{}
+
+// hedgeResult outcome of one hedged request
+type hedgeResult struct {
+	label    string
+	response *Response
+	err      error
+}
+
+// WithHedging fires extra requests when a call produced no first byte within delay
+//
+// Up to maxExtra extra requests are fired, delay apart, while no response has started.
+// The first successful response wins and the others are cancelled. Extra requests go
+// to the same provider unless WithHedgeTargets names other clients. Hedged requests
+// are billed, so expect extra spend on slow calls. Streams are not hedged.
+//
+// Usage example:
+//   backup := mcp.NewQwenClientWithOptions(mcp.WithAPIKey(qwenKey))
+//   client := mcp.NewDeepSeekClientWithOptions(
+//       mcp.WithHedging(3*time.Second, 1),
+//       mcp.WithHedgeTargets(backup),
+//   )
+func WithHedging(delay time.Duration, maxExtra int) ClientOption {
+	return func(c *Config) {
+		c.HedgeDelay = delay
+		c.HedgeMaxExtra = maxExtra
+	}
+}
+
+// WithHedgeTargets sends extra hedged requests to targets, in turn (their own model is used)
+func WithHedgeTargets(targets ...AIClient) ClientOption {
+	return func(c *Config) {
+		c.HedgeTargets = targets
+	}
+}
+
+// HedgeStats returns hedging counters (zero when hedging is disabled)
+func (client *Client) HedgeStats() HedgeStats {
+	if client.hedge == nil {
+		return HedgeStats{}
+	}
+	client.hedge.mu.Lock()
+	defer client.hedge.mu.Unlock()
+	stats := client.hedge.stats
+	stats.Wins = make(map[string]int, len(client.hedge.stats.Wins))
+	for label, wins := range client.hedge.stats.Wins {
+		stats.Wins[label] = wins
+	}
+	return stats
+}
+
+// newHedgeState creates hedge state from config (nil when hedging is disabled)
+func newHedgeState(config *Config) *hedgeState {
+	if config.HedgeDelay <= 0 || config.HedgeMaxExtra <= 0 {
+		return nil
+	}
+	return &hedgeState{
+		delay:    config.HedgeDelay,
+		maxExtra: config.HedgeMaxExtra,
+		targets:  config.HedgeTargets,
+		stats:    HedgeStats{Wins: map[string]int{}},
+	}
+}
+
+// hedging reports whether a call with ctx should be hedged
+func (client *Client) hedging(ctx context.Context) bool {
+	return client.hedge != nil && ctx.Value(hedgeAttemptKey{}) == nil
+}
+
+// hedged runs primary, racing it against extra requests made by extra
+//
+// primary is also used for extra requests when no hedge targets are configured.
+func (client *Client) hedged(ctx context.Context, primary func(context.Context) (*Response, error), extra func(context.Context, AIClient) (*Response, error)) (*Response, error) {
+	h := client.hedge
+	firstByte := make(chan struct{}, 1)
+	attemptCtx := context.WithValue(ctx, hedgeAttemptKey{}, true)
+	attemptCtx = context.WithValue(attemptCtx, firstByteKey{}, func() {
+		select {
+		case firstByte <- struct{}{}:
+		default:
+		}
+	})
+	attemptCtx, cancel := context.WithCancel(attemptCtx)
+	defer cancel() // Cancels the losers
+
+	results := make(chan hedgeResult, 1+h.maxExtra)
+	launch := func(label string, call func(context.Context) (*Response, error)) {
+		go func() {
+			response, err := call(attemptCtx)
+			results <- hedgeResult{label: label, response: response, err: err}
+		}()
+	}
+	launch("primary", primary)
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+	wait := timer.C
+	extras, pending := 0, 1
+	for {
+		select {
+		case <-firstByte:
+			// A response has started, extra requests would only add cost
+			wait = nil
+		case <-wait:
+			call := primary
+			if len(h.targets) > 0 {
+				target := h.targets[extras%len(h.targets)]
+				call = func(ctx context.Context) (*Response, error) { return extra(ctx, target) }
+			}
+			extras++
+			pending++
+			client.logger.Infof("🏇 [%s] No response after %v, firing hedged request %d", client.String(), h.delay*time.Duration(extras), extras)
+			launch(fmt.Sprintf("hedge-%d", extras), call)
+			if extras < h.maxExtra {
+				timer.Reset(h.delay)
+			} else {
+				wait = nil
+			}
+		case result := <-results:
+			pending--
+			if result.err == nil || pending == 0 {
+				h.record(extras > 0, result)
+				if extras > 0 && result.err == nil {
+					client.logger.Infof("🏁 [%s] Hedged call won by %s", client.String(), result.label)
+				}
+				return result.response, result.err
+			}
+		case <-ctx.Done():
+			h.record(extras > 0, hedgeResult{err: ctx.Err()})
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// record counts a finished hedged call
+func (h *hedgeState) record(hedged bool, result hedgeResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stats.Calls++
+	if hedged {
+		h.stats.Hedged++
+	}
+	if result.err == nil {
+		h.stats.Wins[result.label]++
+	}
+}
+
+// signalFirstByte tells the hedger of ctx that a successful response has started
+func signalFirstByte(ctx context.Context) {
+	if signal, ok := ctx.Value(firstByteKey{}).(func()); ok {
+		signal()
+	}
+}
+
+// delegateRequest calls req on another client (hedge target, refusal fallback)
+func delegateRequest(req *Request) func(context.Context, AIClient) (*Response, error) {
+	return func(ctx context.Context, target AIClient) (*Response, error) {
+		targetReq := cloneRequest(req)
+		targetReq.Model = "" // Use the target's model
+		if c, ok := target.(interface {
+			Call(ctx context.Context, req *Request) (*Response, error)
+		}); ok {
+			return c.Call(ctx, targetReq)
+		}
+		text, err := callRequest(ctx, target, targetReq)
+		return &Response{Text: text}, err
+	}
+}
+
+// delegateMessages calls system/user prompts on another client (hedge target, refusal fallback)
+func delegateMessages(systemPrompt, userPrompt string) func(context.Context, AIClient) (*Response, error) {
+	return func(ctx context.Context, target AIClient) (*Response, error) {
+		if c, ok := target.(interface {
+			CallMessages(ctx context.Context, systemPrompt, userPrompt string) (*Response, error)
+		}); ok {
+			return c.CallMessages(ctx, systemPrompt, userPrompt)
+		}
+		text, err := target.CallWithMessages(systemPrompt, userPrompt)
+		return &Response{Text: text}, err
+	}
+}
+
+// cloneRequest copies req so concurrent calls don't share its message slice
+func cloneRequest(req *Request) *Request {
+	clone := *req
+	clone.Messages = slices.Clone(req.Messages)
+	return &clone
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// hedgeServer answers after delay(n) for the n-th request (1-based), honoring cancellation
+func hedgeServer(t *testing.T, answer string, delay func(n int32) time.Duration) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body) // Lets the server notice cancelled requests
+		select {
+		case <-time.After(delay(requests.Add(1))):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"` + answer + `"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newHedgeTestClient(url string, opts ...ClientOption) *Client {
+	opts = append([]ClientOption{WithProvider(ProviderCustom), WithBaseURL(url), WithAPIKey("k"), WithModel("m")}, opts...)
+	return NewClient(opts...).(*Client)
+}
+
+func TestHedging_SecondProviderWins(t *testing.T) {
+	slow, _ := hedgeServer(t, "slow", func(int32) time.Duration { return 2 * time.Second })
+	fast, _ := hedgeServer(t, "fast", func(int32) time.Duration { return 0 })
+	client := newHedgeTestClient(slow.URL, WithHedging(20*time.Millisecond, 1), WithHedgeTargets(newHedgeTestClient(fast.URL)))
+
+	start := time.Now()
+	answer, err := client.CallWithMessages("sys", "decide")
+	if err != nil || answer != "fast" {
+		t.Fatalf("expected hedged answer, got %q (%v)", answer, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("hedged call should not wait for the slow provider, took %v", elapsed)
+	}
+	stats := client.HedgeStats()
+	if stats.Calls != 1 || stats.Hedged != 1 || stats.Wins["hedge-1"] != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestHedging_DuplicateToSameProvider(t *testing.T) {
+	server, requests := hedgeServer(t, "ok", func(n int32) time.Duration {
+		if n == 1 {
+			return 2 * time.Second
+		}
+		return 0
+	})
+	client := newHedgeTestClient(server.URL, WithHedging(20*time.Millisecond, 2))
+
+	response, err := client.Call(context.Background(), NewRequestBuilder().WithUserPrompt("decide").MustBuild())
+	if err != nil || response.Text != "ok" {
+		t.Fatalf("expected answer, got %v (%v)", response, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected one extra request, got %d requests", got)
+	}
+	if wins := client.HedgeStats().Wins; wins["hedge-1"] != 1 {
+		t.Errorf("unexpected wins %v", wins)
+	}
+}
+
+func TestHedging_FastResponseNotHedged(t *testing.T) {
+	server, requests := hedgeServer(t, "ok", func(int32) time.Duration { return 0 })
+	client := newHedgeTestClient(server.URL, WithHedging(500*time.Millisecond, 1))
+
+	if _, err := client.CallWithMessages("sys", "decide"); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	stats := client.HedgeStats()
+	if requests.Load() != 1 || stats.Hedged != 0 || stats.Wins["primary"] != 1 {
+		t.Errorf("unexpected hedging: %d requests, stats %+v", requests.Load(), stats)
+	}
+}
@@ -0,0 +1,248 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderHuggingFace       = "huggingface"
+	DefaultHuggingFaceBaseURL = "https://router.huggingface.co/v1" // Inference Providers router (chat/completions)
+	DefaultHuggingFaceModel   = "meta-llama/Llama-3.1-8B-Instruct"
+)
+
+// maxModelLoadWait longest wait before retrying a model that is still loading
+const maxModelLoadWait = time.Minute
+
+type HuggingFaceClient struct {
+	*Client
+}
+
+// NewHuggingFaceClient creates Hugging Face client (backward compatible)
+func NewHuggingFaceClient() AIClient {
+	return NewHuggingFaceClientWithOptions()
+}
+
+// NewHuggingFaceClientWithOptions creates Hugging Face client (supports options pattern)
+//
+// By default the OpenAI-compatible chat/completions API is used, served by the
+// Inference Providers router, Inference Endpoints and TGI servers (base URL ending
+// in /v1). WithTextGenerationAPI switches to TGI's native generate API instead.
+// Models that are still loading (503) are retried after the estimated load time.
+//
+// Usage example:
+//   // Chat on a self-hosted TGI server
+//   client := mcp.NewHuggingFaceClientWithOptions(mcp.WithBaseURL("http://tgi:8080/v1"), mcp.WithAPIKey(token))
+//
+//   // Native generate API of an Inference Endpoint
+//   client := mcp.NewHuggingFaceClientWithOptions(
+//       mcp.WithBaseURL("https://xyz.endpoints.huggingface.cloud"),
+//       mcp.WithTextGenerationAPI(),
+//   )
+func NewHuggingFaceClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Hugging Face preset options
+	hfOpts := []ClientOption{
+		WithProvider(ProviderHuggingFace),
+		WithModel(DefaultHuggingFaceModel),
+		WithBaseURL(DefaultHuggingFaceBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(hfOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create Hugging Face client
+	hfClient := &HuggingFaceClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to HuggingFaceClient (implement dynamic dispatch)
+	baseClient.hooks = hfClient
+
+	return hfClient
+}
+
+// WithTextGenerationAPI uses TGI's native generate API instead of chat/completions
+//
+// Requests go to the base URL itself (TGI server root, Inference Endpoint URL or
+// api-inference.huggingface.co/models/<model>). Messages are sent as a plain
+// "System/User/Assistant" transcript, so prefer chat/completions for chat-tuned
+// models that rely on their chat template. JSON mode is mapped to a grammar.
+func WithTextGenerationAPI() ClientOption {
+	return func(c *Config) {
+		c.TextGenerationAPI = true
+	}
+}
+
+func (c *HuggingFaceClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] Hugging Face API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Hugging Face using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Hugging Face using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] Hugging Face using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] Hugging Face using default Model: %s", c.Model)
+	}
+}
+
+// setAuthHeader Hugging Face also waits for cold models instead of failing immediately
+func (c *HuggingFaceClient) setAuthHeader(reqHeaders http.Header) {
+	c.Client.setAuthHeader(reqHeaders)
+	reqHeaders.Set("X-Wait-For-Model", "true")
+}
+
+// buildUrl the generate API is served at the base URL itself
+func (c *HuggingFaceClient) buildUrl() string {
+	if c.config.TextGenerationAPI {
+		return c.BaseURL
+	}
+	return c.Client.buildUrl()
+}
+
+// marshalRequestBody converts the chat body to a generate request in generate mode
+func (c *HuggingFaceClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	if c.config.TextGenerationAPI {
+		requestBody = tgiGenerateBody(requestBody)
+	}
+	return c.Client.marshalRequestBody(requestBody)
+}
+
+// parseMCPResponse generate API answers {"generated_text"} (a list of them on the serverless API)
+func (c *HuggingFaceClient) parseMCPResponse(body []byte) (string, error) {
+	if !c.config.TextGenerationAPI {
+		return c.Client.parseMCPResponse(body)
+	}
+
+	type generation struct {
+		GeneratedText *string `json:"generated_text"`
+	}
+	var result generation
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		var results []generation
+		if err := json.Unmarshal(body, &results); err != nil {
+			return "", fmt.Errorf("failed to parse Hugging Face response: %w, body: %s", err, string(body))
+		}
+		if len(results) > 0 {
+			result = results[0]
+		}
+	} else if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse Hugging Face response: %w, body: %s", err, string(body))
+	}
+	if result.GeneratedText == nil {
+		return "", fmt.Errorf("Hugging Face returned no generated_text, body: %s", string(body))
+	}
+
+	if usage := parseUsage(body); TokenUsageCallback != nil && usage.TotalTokens > 0 {
+		usage.Provider = c.Provider
+		usage.Model = c.Model
+		TokenUsageCallback(usage)
+	}
+	return *result.GeneratedText, nil
+}
+
+// isRetryableError a model that is still loading becomes available after a wait
+func (c *HuggingFaceClient) isRetryableError(err error) bool {
+	return modelLoadWait(err) > 0 || c.Client.isRetryableError(err)
+}
+
+// tgiGenerateBody converts an OpenAI-style chat body to a TGI generate request
+func tgiGenerateBody(requestBody map[string]any) map[string]any {
+	parameters := map[string]any{
+		"return_full_text": false,
+		"details":          true,
+	}
+	for from, to := range map[string]string{
+		"max_tokens":            "max_new_tokens",
+		"max_completion_tokens": "max_new_tokens",
+		"top_p":                 "top_p",
+		"stop":                  "stop",
+		"seed":                  "seed",
+		"frequency_penalty":     "frequency_penalty",
+	} {
+		if value, ok := requestBody[from]; ok {
+			parameters[to] = value
+		}
+	}
+	// TGI rejects temperature 0: greedy decoding is the default without sampling
+	if temperature, ok := requestBody["temperature"].(float64); ok && temperature > 0 {
+		parameters["temperature"] = temperature
+		parameters["do_sample"] = true
+	}
+	if format, ok := requestBody["response_format"].(map[string]any); ok {
+		schema := map[string]any{"type": "object"}
+		if spec, ok := format["json_schema"].(map[string]any); ok && spec["schema"] != nil {
+			schema, _ = spec["schema"].(map[string]any)
+		}
+		parameters["grammar"] = map[string]any{"type": "json", "value": schema}
+	}
+
+	generateBody := map[string]any{
+		"inputs":     tgiPrompt(requestBody["messages"]),
+		"parameters": parameters,
+	}
+	if stream, _ := requestBody["stream"].(bool); stream {
+		generateBody["stream"] = true
+	}
+	return generateBody
+}
+
+// tgiPrompt renders chat messages as a plain transcript (a lone user message is sent as is)
+func tgiPrompt(messages any) string {
+	turns := messageTurns(messages)
+	if len(turns) == 1 && turns[0].role == "user" {
+		return turns[0].content
+	}
+
+	var sb strings.Builder
+	for _, t := range turns {
+		if t.role == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s%s: %s\n\n", strings.ToUpper(t.role[:1]), t.role[1:], t.content)
+	}
+	sb.WriteString("Assistant:")
+	return sb.String()
+}
+
+// modelLoadWait returns how long to wait for a model reported as loading (0 = not loading)
+//
+// Hugging Face answers 503 {"error":"Model ... is currently loading","estimated_time":20.5}
+// while a cold model is loaded.
+func modelLoadWait(err error) time.Duration {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+	var body struct {
+		Error         string  `json:"error"`
+		EstimatedTime float64 `json:"estimated_time"`
+	}
+	if json.Unmarshal([]byte(apiErr.Body), &body) != nil || !strings.Contains(strings.ToLower(body.Error), "loading") {
+		return 0
+	}
+	wait := time.Duration(body.EstimatedTime * float64(time.Second))
+	return min(max(wait, time.Second), maxModelLoadWait)
+}
+
+// retryWait returns the wait before retry attempt+1 (model load estimate when reported)
+func (client *Client) retryWait(attempt int, err error) time.Duration {
+	if wait := modelLoadWait(err); wait > 0 {
+		return wait
+	}
+	return client.config.RetryWaitBase * time.Duration(attempt)
+}
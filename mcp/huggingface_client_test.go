@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test HuggingFaceClient
+// ============================================================
+
+func TestHuggingFaceClient_ChatMode(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Hold BTC.")
+	client := NewHuggingFaceClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("hf_test_token"),
+		WithLogger(NewNoopLogger()),
+	)
+
+	result, err := client.CallWithMessages("system", "BTC?")
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if result != "Hold BTC." {
+		t.Errorf("unexpected result %q", result)
+	}
+	req := mockHTTP.GetLastRequest()
+	if req.URL.String() != DefaultHuggingFaceBaseURL+"/chat/completions" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if req.Header.Get("X-Wait-For-Model") != "true" || req.Header.Get("Authorization") != "Bearer hf_test_token" {
+		t.Errorf("unexpected headers %v", req.Header)
+	}
+}
+
+func TestHuggingFaceClient_GenerateMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+	}{
+		{"TGI server", `{"generated_text":" Hold BTC.","details":{"finish_reason":"eos_token","generated_tokens":4}}`},
+		{"serverless API", `[{"generated_text":" Hold BTC.","details":{"finish_reason":"eos_token","generated_tokens":4}}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []map[string]any
+			client := NewHuggingFaceClientWithOptions(
+				WithHTTPClient(captureBodyClient(tt.answer, &bodies)),
+				WithAPIKey("hf_test_token"),
+				WithLogger(NewNoopLogger()),
+				WithBaseURL("http://tgi:8080"),
+				WithTextGenerationAPI(),
+				WithMaxTokens(64),
+			).(*HuggingFaceClient)
+
+			request := NewRequestBuilder().WithSystemPrompt("You are a trader.").WithUserPrompt("BTC?").WithTemperature(0).MustBuild()
+			response, err := client.Call(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			if response.Text != " Hold BTC." {
+				t.Errorf("unexpected text %q", response.Text)
+			}
+
+			body := bodies[0]
+			if body["inputs"] != "System: You are a trader.\n\nUser: BTC?\n\nAssistant:" {
+				t.Errorf("unexpected inputs %q", body["inputs"])
+			}
+			parameters := body["parameters"].(map[string]any)
+			if parameters["max_new_tokens"] != float64(64) || parameters["return_full_text"] != false {
+				t.Errorf("unexpected parameters %v", parameters)
+			}
+			if _, ok := parameters["temperature"]; ok {
+				t.Errorf("temperature 0 should be sent as greedy decoding, got %v", parameters)
+			}
+			if body["messages"] != nil || body["model"] != nil {
+				t.Errorf("chat fields should not be sent, got %v", body)
+			}
+		})
+	}
+}
+
+func TestHuggingFaceClient_GenerateResponseMetadata(t *testing.T) {
+	body := []byte(`{"generated_text":"Hold","details":{"finish_reason":"length","generated_tokens":7}}`)
+	if reason := parseFinishReason(body); reason != FinishReasonLength {
+		t.Errorf("expected length finish reason, got %q", reason)
+	}
+	if usage := parseUsage(body); usage.CompletionTokens != 7 {
+		t.Errorf("expected 7 generated tokens, got %+v", usage)
+	}
+}
+
+func TestHuggingFaceClient_GenerateStream(t *testing.T) {
+	var sent []map[string]any
+	client := NewHuggingFaceClientWithOptions(WithAPIKey("hf_test_token"), WithLogger(NewNoopLogger()), WithTextGenerationAPI(),
+		WithHTTPClient(sequenceSSEClient(&sent, sseLines(
+			`{"index":1,"token":{"id":1,"text":"Hold","logprob":-0.1,"special":false},"generated_text":null,"details":null}`,
+			`{"index":2,"token":{"id":2,"text":" BTC.","logprob":-0.2,"special":false},"generated_text":null,"details":null}`,
+			`{"index":3,"token":{"id":3,"text":"</s>","logprob":0,"special":true},"generated_text":"Hold BTC.","details":{"finish_reason":"eos_token","generated_tokens":3}}`,
+		)))).(*HuggingFaceClient)
+
+	text, response, err := streamText(t, client.Client)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if text != "Hold BTC." || response.FinishReason != FinishReasonStop || response.Usage.CompletionTokens != 3 {
+		t.Errorf("unexpected stream %q finish %q usage %+v", text, response.FinishReason, response.Usage)
+	}
+	if sent[0]["stream"] != true || sent[0]["inputs"] != "outlook?" {
+		t.Errorf("unexpected stream request %v", sent[0])
+	}
+}
+
+func TestHuggingFaceClient_WaitsForLoadingModel(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	calls := 0
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"error":"Model meta-llama/Llama-3.1-8B-Instruct is currently loading","estimated_time":0.5}`)),
+				Header:     make(http.Header),
+			}, nil
+		}
+		data, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"message": map[string]any{"content": "ready"}}}})
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(data)), Header: make(http.Header)}, nil
+	}
+	client := NewHuggingFaceClientWithOptions(WithHTTPClient(mockHTTP.ToHTTPClient()), WithAPIKey("hf_test_token"), WithLogger(NewNoopLogger()))
+
+	start := time.Now()
+	result, err := client.CallWithMessages("system", "hello")
+	if err != nil {
+		t.Fatalf("loading model should be retried: %v", err)
+	}
+	if result != "ready" || calls != 2 {
+		t.Errorf("expected answer after one retry, got %q after %d calls", result, calls)
+	}
+	if waited := time.Since(start); waited < time.Second {
+		t.Errorf("retry should wait at least a second, waited %v", waited)
+	}
+}
+
+func TestModelLoadWait(t *testing.T) {
+	loading := &APIError{StatusCode: http.StatusServiceUnavailable, Body: `{"error":"Model x is currently loading","estimated_time":300}`}
+	if wait := modelLoadWait(loading); wait != maxModelLoadWait {
+		t.Errorf("expected capped wait, got %v", wait)
+	}
+	overloaded := &APIError{StatusCode: http.StatusServiceUnavailable, Body: `{"error":"Service overloaded"}`}
+	if wait := modelLoadWait(overloaded); wait != 0 {
+		t.Errorf("other 503 errors are not model loading, got %v", wait)
+	}
+	if wait := modelLoadWait(errors.New("EOF")); wait != 0 {
+		t.Errorf("non-API errors are not model loading, got %v", wait)
+	}
+}
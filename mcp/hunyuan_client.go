@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	ProviderHunyuan        = "hunyuan"
+	DefaultHunyuanBaseURL  = "https://api.hunyuan.cloud.tencent.com/v1" // OpenAI-compatible, API key
+	DefaultHunyuanModel    = "hunyuan-turbos-latest"
+	DefaultHunyuanCloudURL = "https://hunyuan.tencentcloudapi.com" // Tencent Cloud API 3.0, SecretId/SecretKey
+	hunyuanCloudAPIVersion = "2023-09-01"
+	hunyuanCloudService    = "hunyuan"
+	hunyuanCloudChatAction = "ChatCompletions"
+)
+
+type HunyuanClient struct {
+	*Client
+}
+
+// NewHunyuanClient creates Tencent Hunyuan client (backward compatible)
+func NewHunyuanClient() AIClient {
+	return NewHunyuanClientWithOptions()
+}
+
+// NewHunyuanClientWithOptions creates Hunyuan client (supports options pattern)
+//
+// With an API key requests go to Hunyuan's OpenAI-compatible endpoint. With Tencent
+// Cloud access keys (WithTencentCloudCredentials) they go to the Tencent Cloud API
+// 3.0 ChatCompletions action instead, signed with TC3-HMAC-SHA256; that API takes
+// plain chat messages only (no tools or response formats).
+//
+// Usage example:
+//   client := mcp.NewHunyuanClientWithOptions(mcp.WithTencentCloudCredentials(secretID, secretKey))
+//   answer, err := client.CallWithMessages(systemPrompt, userPrompt)
+func NewHunyuanClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Hunyuan preset options
+	hunyuanOpts := []ClientOption{
+		WithProvider(ProviderHunyuan),
+		WithModel(DefaultHunyuanModel),
+		WithBaseURL(DefaultHunyuanBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(hunyuanOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create Hunyuan client
+	hunyuanClient := &HunyuanClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to HunyuanClient (implement dynamic dispatch)
+	baseClient.hooks = hunyuanClient
+
+	return hunyuanClient
+}
+
+// WithTencentCloudCredentials calls Hunyuan through Tencent Cloud API 3.0 with SecretId/SecretKey signatures
+func WithTencentCloudCredentials(secretID, secretKey string) ClientOption {
+	return func(c *Config) {
+		WithCloudSigner(&TencentCloudSigner{
+			SecretID:  secretID,
+			SecretKey: secretKey,
+			Service:   hunyuanCloudService,
+			Action:    hunyuanCloudChatAction,
+			Version:   hunyuanCloudAPIVersion,
+		})(c)
+		WithBaseURL(DefaultHunyuanCloudURL)(c)
+	}
+}
+
+func (c *HunyuanClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] Hunyuan API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Hunyuan using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Hunyuan using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] Hunyuan using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] Hunyuan using default Model: %s", c.Model)
+	}
+}
+
+// cloudAPI reports whether requests go to Tencent Cloud API 3.0
+func (c *HunyuanClient) cloudAPI() bool {
+	_, ok := c.config.CloudSigner.(*TencentCloudSigner)
+	return ok
+}
+
+// buildUrl Tencent Cloud API 3.0 takes every action at the service root
+func (c *HunyuanClient) buildUrl() string {
+	if c.cloudAPI() {
+		return c.BaseURL
+	}
+	return c.Client.buildUrl()
+}
+
+// marshalRequestBody converts the chat body to Tencent Cloud's PascalCase parameters
+func (c *HunyuanClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	if c.cloudAPI() {
+		requestBody = hunyuanCloudBody(requestBody)
+	}
+	return c.Client.marshalRequestBody(requestBody)
+}
+
+// parseMCPResponse Tencent Cloud wraps answers and errors in {"Response": {...}}
+func (c *HunyuanClient) parseMCPResponse(body []byte) (string, error) {
+	if !c.cloudAPI() {
+		return c.Client.parseMCPResponse(body)
+	}
+
+	var result struct {
+		Response struct {
+			Choices []struct {
+				Message struct {
+					Content string `json:"Content"`
+				} `json:"Message"`
+			} `json:"Choices"`
+			Error *struct {
+				Code    string `json:"Code"`
+				Message string `json:"Message"`
+			} `json:"Error"`
+			RequestID string `json:"RequestId"`
+		} `json:"Response"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse Hunyuan response: %w, body: %s", err, string(body))
+	}
+	if result.Response.Error != nil {
+		return "", fmt.Errorf("Hunyuan API error: %s - %s (request %s)",
+			result.Response.Error.Code, result.Response.Error.Message, result.Response.RequestID)
+	}
+	if len(result.Response.Choices) == 0 {
+		return "", fmt.Errorf("Hunyuan returned empty response, body: %s", string(body))
+	}
+
+	if usage := parseUsage(body); TokenUsageCallback != nil && usage.TotalTokens > 0 {
+		usage.Provider = c.Provider
+		usage.Model = c.Model
+		TokenUsageCallback(usage)
+	}
+	return result.Response.Choices[0].Message.Content, nil
+}
+
+// hunyuanCloudBody builds ChatCompletions parameters from an OpenAI-style chat body
+func hunyuanCloudBody(requestBody map[string]any) map[string]any {
+	var messages []map[string]string
+	for _, turn := range messageTurns(requestBody["messages"]) {
+		messages = append(messages, map[string]string{"Role": turn.role, "Content": turn.content})
+	}
+	cloudBody := map[string]any{
+		"Model":    requestBody["model"],
+		"Messages": messages,
+	}
+	for from, to := range map[string]string{
+		"stream":      "Stream",
+		"temperature": "Temperature",
+		"top_p":       "TopP",
+		"stop":        "Stop",
+		"seed":        "Seed",
+	} {
+		if value, ok := requestBody[from]; ok {
+			cloudBody[to] = value
+		}
+	}
+	return cloudBody
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test HunyuanClient
+// ============================================================
+
+func TestHunyuanClient_TencentCloudAPI(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"Response":{"Choices":[{"FinishReason":"stop","Message":{"Role":"assistant","Content":"Hold BTC."}}],` +
+		`"Usage":{"PromptTokens":12,"CompletionTokens":4,"TotalTokens":16},"RequestId":"req-1"}}`
+	client := NewHunyuanClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithTencentCloudCredentials("AKID-test", "secret"),
+		WithLogger(NewNoopLogger()),
+	).(*HunyuanClient)
+
+	if got := client.buildUrl(); got != DefaultHunyuanCloudURL {
+		t.Errorf("unexpected URL %s", got)
+	}
+	response, err := client.Call(t.Context(), NewRequestBuilder().WithSystemPrompt("You are a trader.").WithUserPrompt("BTC?").MustBuild())
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if response.Text != "Hold BTC." || response.FinishReason != FinishReasonStop || response.Usage.TotalTokens != 16 {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	messages, _ := bodies[0]["Messages"].([]any)
+	if bodies[0]["Model"] != DefaultHunyuanModel || len(messages) != 2 || bodies[0]["messages"] != nil {
+		t.Errorf("expected PascalCase body, got %v", bodies[0])
+	}
+	if first, _ := messages[0].(map[string]any); first["Role"] != "system" {
+		t.Errorf("unexpected first message %v", messages[0])
+	}
+}
+
+func TestHunyuanClient_TencentCloudError(t *testing.T) {
+	client := NewHunyuanClientWithOptions(
+		WithHTTPClient(captureBodyClient(`{"Response":{"Error":{"Code":"AuthFailure.SignatureFailure","Message":"signature mismatch"},"RequestId":"req-2"}}`, new([]map[string]any))),
+		WithTencentCloudCredentials("AKID-test", "secret"),
+		WithMaxRetries(1),
+		WithLogger(NewNoopLogger()),
+	)
+
+	_, err := client.CallWithMessages("", "BTC?")
+	if err == nil || !strings.Contains(err.Error(), "AuthFailure.SignatureFailure") {
+		t.Errorf("expected Tencent Cloud error, got %v", err)
+	}
+}
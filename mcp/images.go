@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrImagesUnsupported is returned by GenerateImage for providers without an image API
+var ErrImagesUnsupported = errors.New("provider has no image generation API")
+
+// defaultImageModels image model used per provider when none is given
+var defaultImageModels = map[string]string{
+	ProviderOpenAI: "gpt-image-1",
+	ProviderGrok:   "grok-2-image",
+}
+
+// ImageFormat how generated images are returned
+type ImageFormat string
+
+const (
+	ImageFormatBytes ImageFormat = "bytes" // Image data (URLs returned by the endpoint are downloaded)
+	ImageFormatURL   ImageFormat = "url"   // Hosted URLs where the endpoint supports them (they expire)
+)
+
+// ImageOptions image generation parameters (zero values use endpoint defaults)
+type ImageOptions struct {
+	Model   string      // "" = provider default (gpt-image-1 on OpenAI)
+	Size    string      // e.g. "1024x1024", "1536x1024"
+	Quality string      // e.g. "low", "medium", "high" (gpt-image), "hd" (dall-e-3)
+	N       int         // Number of images (0 = 1)
+	Format  ImageFormat // "" = ImageFormatBytes
+}
+
+// GeneratedImage one generated image: Data, URL or both are set
+type GeneratedImage struct {
+	Data          []byte
+	URL           string
+	RevisedPrompt string // Prompt as rewritten by the model, if reported
+}
+
+// ImageResponse generated images with metadata
+type ImageResponse struct {
+	Images []GeneratedImage
+	Model  string
+	Usage  TokenUsage
+}
+
+// GenerateImage generates images from prompt via the OpenAI-compatible /images/generations endpoint
+//
+// Usage example:
+//   result, err := client.GenerateImage(ctx, "Annotated BTC 4h chart, breakout above 68k marked", mcp.ImageOptions{Size: "1536x1024"})
+//   if err == nil {
+//       os.WriteFile("report/btc.png", result.Images[0].Data, 0o644)
+//   }
+func (client *Client) GenerateImage(ctx context.Context, prompt string, opts ImageOptions) (*ImageResponse, error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+	model := opts.Model
+	if model == "" {
+		model = defaultImageModels[client.Provider]
+	}
+	if model == "" {
+		if client.Provider != ProviderCustom {
+			return nil, ErrImagesUnsupported
+		}
+		return nil, errors.New("no image model for custom provider, set ImageOptions.Model")
+	}
+
+	payload := map[string]any{"model": model, "prompt": prompt}
+	if opts.Size != "" {
+		payload["size"] = opts.Size
+	}
+	if opts.Quality != "" {
+		payload["quality"] = opts.Quality
+	}
+	if opts.N > 0 {
+		payload["n"] = opts.N
+	}
+	// gpt-image models always return base64 data and reject response_format
+	if !strings.HasPrefix(model, "gpt-image") {
+		payload["response_format"] = "b64_json"
+		if opts.Format == ImageFormatURL {
+			payload["response_format"] = "url"
+		}
+	}
+
+	body, requestID, err := client.postAPI(ctx, EndpointImages, payload)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Data []struct {
+			B64JSON       string `json:"b64_json"`
+			URL           string `json:"url"`
+			RevisedPrompt string `json:"revised_prompt"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, withRequestID(fmt.Errorf("failed to parse image response: %w", err), requestID)
+	}
+	if len(result.Data) == 0 {
+		return nil, withRequestID(errors.New("image response contains no images"), requestID)
+	}
+
+	response := &ImageResponse{Model: model, Usage: parseUsage(body)}
+	for _, item := range result.Data {
+		image := GeneratedImage{URL: item.URL, RevisedPrompt: item.RevisedPrompt}
+		if item.B64JSON != "" {
+			if image.Data, err = base64.StdEncoding.DecodeString(item.B64JSON); err != nil {
+				return nil, withRequestID(fmt.Errorf("failed to decode image: %w", err), requestID)
+			}
+		} else if opts.Format != ImageFormatURL && item.URL != "" {
+			if image.Data, err = client.downloadImage(ctx, item.URL); err != nil {
+				return nil, withRequestID(err, requestID)
+			}
+		}
+		response.Images = append(response.Images, image)
+	}
+	response.Usage.Provider = client.Provider
+	response.Usage.Model = model
+	return response, nil
+}
+
+// downloadImage fetches an image hosted at url
+func (client *Client) downloadImage(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fail to build image download: %w", err)
+	}
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image download returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(limitBody(resp.Body, client.config.MaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGenerateImage_OpenAI(t *testing.T) {
+	png := []byte("\x89PNG fake image")
+	var payload map[string]any
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(req.URL.Path, "/images/generations") {
+			t.Errorf("unexpected path %s", req.URL.Path)
+		}
+		data, _ := io.ReadAll(req.Body)
+		json.Unmarshal(data, &payload)
+		body := `{"data":[{"b64_json":"` + base64.StdEncoding.EncodeToString(png) + `"}],"usage":{"input_tokens":12,"output_tokens":4160,"total_tokens":4172}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+	client, _ := BaseClient(NewOpenAIClientWithOptions(WithHTTPClient(mockHTTP.ToHTTPClient()), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger())))
+
+	result, err := client.GenerateImage(context.Background(), "BTC chart", ImageOptions{Size: "1024x1024"})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if payload["model"] != "gpt-image-1" || payload["size"] != "1024x1024" || payload["response_format"] != nil {
+		t.Errorf("unexpected payload %v", payload)
+	}
+	if len(result.Images) != 1 || string(result.Images[0].Data) != string(png) {
+		t.Errorf("unexpected images %+v", result.Images)
+	}
+}
+
+func TestGenerateImage_URLAndDownload(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body := `{"data":[{"url":"https://img.example.com/1.png","revised_prompt":"a chart"}]}`
+		if req.Method == http.MethodGet {
+			body = "image-bytes"
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+	client := NewClient(WithProvider(ProviderCustom), WithHTTPClient(mockHTTP.ToHTTPClient()), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger())).(*Client)
+	ctx := context.Background()
+
+	if _, err := client.GenerateImage(ctx, "chart", ImageOptions{}); err == nil {
+		t.Error("expected error without model on custom provider")
+	}
+	result, err := client.GenerateImage(ctx, "chart", ImageOptions{Model: "dall-e-3", Format: ImageFormatURL})
+	if err != nil || result.Images[0].URL != "https://img.example.com/1.png" || result.Images[0].Data != nil {
+		t.Errorf("url result = %+v, %v", result, err)
+	}
+	result, err = client.GenerateImage(ctx, "chart", ImageOptions{Model: "dall-e-3"})
+	if err != nil || string(result.Images[0].Data) != "image-bytes" || result.Images[0].RevisedPrompt != "a chart" {
+		t.Errorf("downloaded result = %+v, %v", result, err)
+	}
+
+	deepseek := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger())).(*Client)
+	if _, err := deepseek.GenerateImage(ctx, "chart", ImageOptions{}); !errors.Is(err, ErrImagesUnsupported) {
+		t.Errorf("expected ErrImagesUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InjectionStrictness how suspicious content must be before it is flagged
+type InjectionStrictness int
+
+const (
+	// InjectionBalanced flags any strong indicator (default)
+	InjectionBalanced InjectionStrictness = iota
+	// InjectionLenient flags only content with several strong indicators
+	InjectionLenient
+	// InjectionStrict also flags weak indicators such as hidden characters
+	InjectionStrict
+)
+
+// threshold min injection score flagged at this strictness
+func (s InjectionStrictness) threshold() float64 {
+	switch s {
+	case InjectionLenient:
+		return 0.8
+	case InjectionStrict:
+		return 0.3
+	}
+	return 0.5
+}
+
+// InjectionAction what Screen does with flagged content
+type InjectionAction int
+
+const (
+	// InjectionNeutralize removes the suspicious spans and passes the rest on
+	InjectionNeutralize InjectionAction = iota
+	// InjectionBlock rejects the content with a *PromptInjectionError
+	InjectionBlock
+)
+
+// Injection finding categories
+const (
+	InjectionInstructionOverride = "instruction_override"
+	InjectionRoleSpoofing        = "role_spoofing"
+	InjectionExfiltration        = "exfiltration"
+	InjectionPromptLeak          = "prompt_leak"
+	InjectionHiddenText          = "hidden_text"
+)
+
+// ErrPromptInjection is matched (errors.Is) by PromptInjectionError
+var ErrPromptInjection = errors.New("possible prompt injection")
+
+// PromptInjectionError content was rejected by the injection scanner
+type PromptInjectionError struct {
+	Report InjectionReport
+}
+
+func (e *PromptInjectionError) Error() string {
+	categories := make([]string, 0, len(e.Report.Findings))
+	for _, finding := range e.Report.Findings {
+		categories = append(categories, finding.Category)
+	}
+	return fmt.Sprintf("possible prompt injection in %s (score %.2f): %s", e.Report.Source, e.Report.Score, strings.Join(categories, ", "))
+}
+
+func (e *PromptInjectionError) Is(target error) bool {
+	return target == ErrPromptInjection
+}
+
+// InjectionFinding one matched injection indicator
+type InjectionFinding struct {
+	Category string
+	Match    string // Matched text (truncated)
+}
+
+// InjectionReport result of scanning one piece of content
+type InjectionReport struct {
+	Time     time.Time
+	Source   string  // Where the content came from (e.g. "tool:get_news", "rag:doc-42")
+	Score    float64 // Combined suspicion in [0,1]
+	Flagged  bool    // Score reached the strictness threshold
+	Findings []InjectionFinding
+}
+
+// InjectionConfig injection scanner configuration
+type InjectionConfig struct {
+	Strictness InjectionStrictness
+	Action     InjectionAction
+	OnFlag     func(InjectionReport) // Optional audit callback for flagged content
+}
+
+// injectionRule one detection pattern
+type injectionRule struct {
+	category string
+	weight   float64
+	pattern  *regexp.Regexp
+}
+
+// injectionRules built-in indicators of injected instructions
+var injectionRules = []injectionRule{
+	{InjectionInstructionOverride, 0.6, regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b.{0,30}\b(previous|prior|above|earlier|all|any|the|your)\b.{0,20}\b(instructions?|prompts?|rules|directions|guidelines)`)},
+	{InjectionInstructionOverride, 0.6, regexp.MustCompile(`(?i)\b(new|updated|real) (system )?instructions?\s*:`)},
+	{InjectionInstructionOverride, 0.5, regexp.MustCompile(`(?i)\b(do not|don't)\s+follow\s+(the\s+)?(system|previous|original)`)},
+	{InjectionRoleSpoofing, 0.5, regexp.MustCompile(`(?im)^\s*#*\s*(system|assistant|developer)\s*:`)},
+	{InjectionRoleSpoofing, 0.6, regexp.MustCompile(`(?i)<\|?(im_start|im_end|system|endoftext)\|?>|\[/?(INST|SYS)\]`)},
+	{InjectionRoleSpoofing, 0.5, regexp.MustCompile(`(?i)\b(you are now|from now on,? you are|act as an? (unrestricted|jailbroken)|developer mode)\b`)},
+	{InjectionExfiltration, 0.6, regexp.MustCompile(`(?i)!\[[^\]]*\]\(https?://[^)\s]*[?&][^)\s]*=[^)\s]*\)`)},
+	{InjectionExfiltration, 0.6, regexp.MustCompile(`(?i)\b(send|post|upload|forward|exfiltrate|leak|transfer)\b.{0,40}\b(api[_ -]?keys?|secrets?|credentials?|passwords?|private keys?|seed phrase|wallet|funds)\b`)},
+	{InjectionPromptLeak, 0.5, regexp.MustCompile(`(?i)\b(reveal|print|repeat|output|show)\b.{0,30}\b(system prompt|your (instructions|prompt|rules)|hidden instructions)`)},
+	{InjectionHiddenText, 0.4, regexp.MustCompile(`[\x{200B}-\x{200F}\x{202A}-\x{202E}\x{2060}-\x{2064}\x{FEFF}\x{E0000}-\x{E007F}]`)},
+}
+
+// injectionHistory number of most recent flagged reports kept in memory
+const injectionHistory = 200
+
+// InjectionScanner scans untrusted content (retrieved documents, tool results) for
+// prompt-injection attempts before it is fed back into an agent loop
+//
+// Detected indicators: instruction overrides ("ignore previous instructions"), role
+// spoofing (fake "system:" turns, chat-template tokens), exfiltration (markdown image
+// beacons, requests to send keys or funds), system prompt leaking and hidden
+// zero-width/bidi/tag characters. Flagged reports are kept (Flagged) and passed to
+// InjectionConfig.OnFlag for auditing.
+type InjectionScanner struct {
+	config InjectionConfig
+
+	mu      sync.Mutex
+	flagged []InjectionReport
+}
+
+// NewInjectionScanner creates scanner
+//
+// Usage example:
+//   scanner := mcp.NewInjectionScanner(mcp.InjectionConfig{
+//       Strictness: mcp.InjectionBalanced,
+//       OnFlag:     func(r mcp.InjectionReport) { log.Printf("injection in %s: %+v", r.Source, r.Findings) },
+//   })
+//   toolOutput, err := scanner.Screen("tool:fetch_news", rawOutput)
+//   if errors.Is(err, mcp.ErrPromptInjection) {
+//       toolOutput = "Tool result withheld: suspected prompt injection."
+//   }
+//   messages = append(messages, mcp.NewMessage("tool", toolOutput))
+func NewInjectionScanner(config InjectionConfig) *InjectionScanner {
+	return &InjectionScanner{config: config}
+}
+
+// Scan scores text without changing it
+func (s *InjectionScanner) Scan(source, text string) InjectionReport {
+	report := InjectionReport{Time: time.Now(), Source: source}
+	clean := 1.0
+	for _, rule := range injectionRules {
+		match := rule.pattern.FindString(text)
+		if match == "" {
+			continue
+		}
+		if len(match) > 80 {
+			match = match[:80] + "..."
+		}
+		report.Findings = append(report.Findings, InjectionFinding{Category: rule.category, Match: match})
+		clean *= 1 - rule.weight
+	}
+	report.Score = 1 - clean
+	report.Flagged = report.Score >= s.config.Strictness.threshold()
+
+	if report.Flagged {
+		s.mu.Lock()
+		s.flagged = append(s.flagged, report)
+		if len(s.flagged) > injectionHistory {
+			s.flagged = s.flagged[len(s.flagged)-injectionHistory:]
+		}
+		s.mu.Unlock()
+		if s.config.OnFlag != nil {
+			s.config.OnFlag(report)
+		}
+	}
+	return report
+}
+
+// Screen scans text and applies the configured action to flagged content
+//
+// InjectionNeutralize returns the text with suspicious spans replaced; InjectionBlock
+// returns a *PromptInjectionError. Unflagged text is returned unchanged.
+func (s *InjectionScanner) Screen(source, text string) (string, error) {
+	report := s.Scan(source, text)
+	if !report.Flagged {
+		return text, nil
+	}
+	if s.config.Action == InjectionBlock {
+		return "", &PromptInjectionError{Report: report}
+	}
+	return neutralizeInjection(text), nil
+}
+
+// Flagged returns the most recent flagged reports, oldest first
+func (s *InjectionScanner) Flagged() []InjectionReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]InjectionReport(nil), s.flagged...)
+}
+
+// Guardrail adapts the scanner to the guardrail chain (redaction = neutralization)
+//
+// Note that input guardrails also see the trusted system prompt; prefer Screen on
+// untrusted content when the system prompt itself discusses instructions or roles.
+func (s *InjectionScanner) Guardrail() Guardrail {
+	return &injectionGuardrail{scanner: s}
+}
+
+type injectionGuardrail struct {
+	scanner *InjectionScanner
+}
+
+func (g *injectionGuardrail) Name() string { return "prompt_injection" }
+
+func (g *injectionGuardrail) Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error) {
+	report := g.scanner.Scan(stage.String(), text)
+	if !report.Flagged {
+		return GuardrailResult{}, nil
+	}
+	return GuardrailResult{
+		Violated: true,
+		Reason:   (&PromptInjectionError{Report: report}).Error(),
+		Redacted: neutralizeInjection(text),
+	}, nil
+}
+
+// neutralizeInjection replaces all matched indicators in text
+func neutralizeInjection(text string) string {
+	for _, rule := range injectionRules {
+		replacement := "[removed: possible prompt injection]"
+		if rule.category == InjectionHiddenText {
+			replacement = ""
+		}
+		text = rule.pattern.ReplaceAllString(text, replacement)
+	}
+	return text
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"net/http"
 	"time"
 )
@@ -13,12 +14,25 @@ type AIClient interface {
 	CallWithRequest(req *Request) (string, error) // Builder pattern API (supports advanced features)
 }
 
+// contextCaller AIClient accepting a per-request context (implemented by *Client)
+type contextCaller interface {
+	CallWithRequestContext(ctx context.Context, req *Request) (string, error)
+}
+
+// callRequest calls client with ctx when it supports contexts
+func callRequest(ctx context.Context, client AIClient, req *Request) (string, error) {
+	if c, ok := client.(contextCaller); ok {
+		return c.CallWithRequestContext(ctx, req)
+	}
+	return client.CallWithRequest(req)
+}
+
 // clientHooks internal hook interface (for subclass to override specific steps)
 // These methods are only used inside the package to implement dynamic dispatch
 type clientHooks interface {
 	// Hook methods that can be overridden by subclass
 
-	call(systemPrompt, userPrompt string) (string, error)
+	call(ctx context.Context, systemPrompt, userPrompt string) (*Response, error)
 
 	buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any
 	buildUrl() string
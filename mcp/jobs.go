@@ -0,0 +1,337 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobState lifecycle state of an async job
+type JobState string
+
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+)
+
+var (
+	// ErrJobNotFound is returned for unknown job IDs
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobPending is returned by JobResult while the job is queued or running
+	ErrJobPending = errors.New("job not finished yet")
+	// ErrJobRunnerClosed is returned by SubmitJob after Close
+	ErrJobRunnerClosed = errors.New("job runner is closed")
+)
+
+// Job async AI call and its outcome
+type Job struct {
+	ID          string    `json:"id"`
+	State       JobState  `json:"state"`
+	Prompt      Prompt    `json:"prompt"`
+	Result      string    `json:"result,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	StartedAt   time.Time `json:"started_at,omitempty"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+}
+
+// Done returns true once the job succeeded or failed
+func (j Job) Done() bool {
+	return j.State == JobSucceeded || j.State == JobFailed
+}
+
+// JobStore persists jobs (implement it on a database to keep results across restarts)
+type JobStore interface {
+	SaveJob(ctx context.Context, job Job) error
+	LoadJob(ctx context.Context, id string) (Job, error) // ErrJobNotFound if unknown
+}
+
+// MemoryJobStore in-process JobStore (default)
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]Job
+}
+
+// NewMemoryJobStore creates empty in-memory job store
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]Job)}
+}
+
+func (s *MemoryJobStore) SaveJob(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *MemoryJobStore) LoadJob(ctx context.Context, id string) (Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+// JobRunnerConfig job runner configuration
+type JobRunnerConfig struct {
+	Workers    int           // Concurrent jobs (default 2)
+	QueueSize  int           // Max queued jobs, beyond that SubmitJob fails with ErrQueueFull (default 100)
+	Timeout    time.Duration // Max duration of one job (0 = unlimited)
+	Store      JobStore      // Persistence (default: MemoryJobStore)
+	OnComplete func(Job)     // Called when a job finishes (optional)
+	WebhookURL string        // Finished jobs are POSTed here as JSON (optional)
+
+	// Cipher encrypts prompts, results and errors before they reach Store (nil = plaintext);
+	// JobStatus and JobResult return them decrypted
+	Cipher ContentCipher
+}
+
+// JobRunner runs long AI calls in the background on a worker pool
+//
+// Jobs don't inherit the submitter's context, so a deep-research prompt keeps
+// running after the HTTP request that submitted it has returned.
+type JobRunner struct {
+	client     AIClient
+	config     JobRunnerConfig
+	queue      chan Job
+	httpClient *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewJobRunner creates runner and starts its workers
+//
+// Usage example:
+//   runner := mcp.NewJobRunner(client, mcp.JobRunnerConfig{Workers: 2, Timeout: 10 * time.Minute})
+//   id, err := runner.SubmitJob(ctx, mcp.Prompt{SystemPrompt: sys, UserPrompt: "Deep dive on ETH staking flows"})
+//   ...
+//   result, err := runner.JobResult(ctx, id)
+//   if errors.Is(err, mcp.ErrJobPending) {
+//       // poll again later
+//   }
+func NewJobRunner(client AIClient, config JobRunnerConfig) *JobRunner {
+	if config.Workers < 1 {
+		config.Workers = 2
+	}
+	if config.QueueSize < 1 {
+		config.QueueSize = 100
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryJobStore()
+	}
+
+	r := &JobRunner{
+		client:     client,
+		config:     config,
+		queue:      make(chan Job, config.QueueSize),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	for i := 0; i < config.Workers; i++ {
+		r.wg.Add(1)
+		go r.work()
+	}
+	return r
+}
+
+// SubmitJob queues prompt and returns the job ID
+func (r *JobRunner) SubmitJob(ctx context.Context, prompt Prompt) (string, error) {
+	job := Job{
+		ID:          uuid.NewString(),
+		State:       JobQueued,
+		Prompt:      prompt,
+		SubmittedAt: time.Now(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return "", ErrJobRunnerClosed
+	}
+	if err := r.saveJob(ctx, job); err != nil {
+		return "", fmt.Errorf("failed to save job: %w", err)
+	}
+	select {
+	case r.queue <- job:
+		return job.ID, nil
+	default:
+		job.State, job.Error, job.FinishedAt = JobFailed, ErrQueueFull.Error(), time.Now()
+		r.saveJob(ctx, job)
+		return "", ErrQueueFull
+	}
+}
+
+// JobStatus returns current snapshot of job id
+func (r *JobRunner) JobStatus(ctx context.Context, id string) (Job, error) {
+	return r.loadJob(ctx, id)
+}
+
+// JobResult returns answer of a finished job
+//
+// ErrJobPending is returned while the job is queued or running; the job's error
+// is returned if it failed.
+func (r *JobRunner) JobResult(ctx context.Context, id string) (string, error) {
+	job, err := r.loadJob(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	switch job.State {
+	case JobSucceeded:
+		return job.Result, nil
+	case JobFailed:
+		return "", fmt.Errorf("job %s failed: %s", id, job.Error)
+	default:
+		return "", ErrJobPending
+	}
+}
+
+// Close stops accepting jobs and waits for queued ones to finish
+//
+// When ctx expires first, running jobs are cancelled (and recorded as failed).
+func (r *JobRunner) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work runs queued jobs until the queue is closed
+func (r *JobRunner) work() {
+	defer r.wg.Done()
+	for job := range r.queue {
+		r.run(job)
+	}
+}
+
+// run executes one job and records its outcome
+func (r *JobRunner) run(job Job) {
+	ctx := r.ctx
+	if r.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+		defer cancel()
+	}
+
+	job.State, job.StartedAt = JobRunning, time.Now()
+	r.saveJob(ctx, job)
+
+	text, err := r.call(ctx, job.Prompt)
+	job.FinishedAt = time.Now()
+	if err != nil {
+		job.State, job.Error = JobFailed, err.Error()
+	} else {
+		job.State, job.Result = JobSucceeded, text
+	}
+	// Outcome is saved even if the job context was cancelled
+	r.saveJob(context.WithoutCancel(ctx), job)
+
+	if r.config.OnComplete != nil {
+		r.config.OnComplete(job)
+	}
+	if r.config.WebhookURL != "" {
+		r.notify(job)
+	}
+}
+
+// saveJob saves job, encrypted with the configured cipher (never falling back to plaintext)
+func (r *JobRunner) saveJob(ctx context.Context, job Job) error {
+	if r.config.Cipher != nil {
+		var err error
+		if job, err = transformJob(job, func(text string) (string, error) {
+			return encryptString(r.config.Cipher, text)
+		}); err != nil {
+			return fmt.Errorf("failed to encrypt job: %w", err)
+		}
+	}
+	return r.config.Store.SaveJob(ctx, job)
+}
+
+// loadJob loads job id, decrypting jobs saved with a cipher
+func (r *JobRunner) loadJob(ctx context.Context, id string) (Job, error) {
+	job, err := r.config.Store.LoadJob(ctx, id)
+	if err != nil {
+		return Job{}, err
+	}
+	return transformJob(job, func(text string) (string, error) {
+		return decryptString(r.config.Cipher, text)
+	})
+}
+
+// transformJob returns copy of job with transform applied to its prompt, result and error
+func transformJob(job Job, transform func(string) (string, error)) (Job, error) {
+	var err error
+	for _, field := range []*string{&job.Prompt.SystemPrompt, &job.Prompt.UserPrompt, &job.Result, &job.Error} {
+		if *field, err = transform(*field); err != nil {
+			return Job{}, err
+		}
+	}
+	if job.Prompt.Request != nil {
+		req := *job.Prompt.Request
+		if req.Messages, err = transformMessages(req.Messages, transform); err != nil {
+			return Job{}, err
+		}
+		job.Prompt.Request = &req
+	}
+	return job, nil
+}
+
+// call sends prompt of a job
+func (r *JobRunner) call(ctx context.Context, prompt Prompt) (string, error) {
+	req := prompt.Request
+	if req == nil {
+		var err error
+		req, err = NewRequestBuilder().
+			WithSystemPrompt(prompt.SystemPrompt).
+			WithUserPrompt(prompt.UserPrompt).
+			Build()
+		if err != nil {
+			return "", err
+		}
+	}
+	return callRequest(ctx, r.client, req)
+}
+
+// notify POSTs finished job to the configured webhook (best effort)
+func (r *JobRunner) notify(job Job) {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return
+	}
+	resp, err := r.httpClient.Post(r.config.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJobRunner_SubmitPollResult(t *testing.T) {
+	client := newBlockingClient()
+	completed := make(chan Job, 1)
+	runner := NewJobRunner(client, JobRunnerConfig{Workers: 1, OnComplete: func(job Job) { completed <- job }})
+	defer runner.Close(context.Background())
+	ctx := context.Background()
+
+	id, err := runner.SubmitJob(ctx, Prompt{SystemPrompt: "sys", UserPrompt: "deep research"})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		job, _ := runner.JobStatus(ctx, id)
+		return job.State == JobRunning
+	})
+	if _, err := runner.JobResult(ctx, id); !errors.Is(err, ErrJobPending) {
+		t.Errorf("expected ErrJobPending while running, got %v", err)
+	}
+
+	close(client.release)
+	job := <-completed
+	if job.State != JobSucceeded || job.Result != "ok" {
+		t.Errorf("unexpected completed job %+v", job)
+	}
+	if result, err := runner.JobResult(ctx, id); err != nil || result != "ok" {
+		t.Errorf("expected result ok, got %q (err %v)", result, err)
+	}
+	if _, err := runner.JobStatus(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestJobRunner_QueueFullAndClose(t *testing.T) {
+	client := newBlockingClient()
+	runner := NewJobRunner(client, JobRunnerConfig{Workers: 1, QueueSize: 1})
+	ctx := context.Background()
+
+	running, _ := runner.SubmitJob(ctx, Prompt{UserPrompt: "1"})
+	waitFor(t, func() bool {
+		job, _ := runner.JobStatus(ctx, running)
+		return job.State == JobRunning
+	})
+	runner.SubmitJob(ctx, Prompt{UserPrompt: "2"})
+	if _, err := runner.SubmitJob(ctx, Prompt{UserPrompt: "3"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+
+	close(client.release)
+	if err := runner.Close(ctx); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if _, err := runner.SubmitJob(ctx, Prompt{UserPrompt: "4"}); !errors.Is(err, ErrJobRunnerClosed) {
+		t.Errorf("expected ErrJobRunnerClosed, got %v", err)
+	}
+}
+
+func TestJobRunner_Webhook(t *testing.T) {
+	received := make(chan Job, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job Job
+		json.NewDecoder(r.Body).Decode(&job)
+		received <- job
+	}))
+	defer server.Close()
+
+	runner := NewJobRunner(&stubClient{err: errors.New("provider down")}, JobRunnerConfig{WebhookURL: server.URL})
+	defer runner.Close(context.Background())
+
+	id, _ := runner.SubmitJob(context.Background(), Prompt{UserPrompt: "analyze"})
+	job := <-received
+	if job.ID != id || job.State != JobFailed || job.Error != "provider down" {
+		t.Errorf("unexpected webhook payload %+v", job)
+	}
+}
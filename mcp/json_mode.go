@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// jsonResponseTool name of the tool used to force JSON output on Anthropic
+const jsonResponseTool = "json_response"
+
+// ErrInvalidJSONResponse is matched (errors.Is) by JSONValidationError
+var ErrInvalidJSONResponse = errors.New("response is not valid JSON for requested format")
+
+// ResponseFormat requests JSON output, optionally conforming to a JSON Schema
+type ResponseFormat struct {
+	Name   string         // Schema name sent to the provider (default "response")
+	Schema map[string]any // JSON Schema (nil = any JSON object)
+}
+
+// JSONValidationError response failed JSON parsing or schema validation
+type JSONValidationError struct {
+	Path   string // Location of the violation ("$" = root)
+	Reason string
+}
+
+func (e *JSONValidationError) Error() string {
+	return fmt.Sprintf("invalid JSON response at %s: %s", e.Path, e.Reason)
+}
+
+func (e *JSONValidationError) Is(target error) bool {
+	return target == ErrInvalidJSONResponse
+}
+
+// WithJSONResponse makes every call return JSON conforming to schema (nil = any JSON object)
+//
+// OpenAI-compatible providers receive response_format (json_schema, or json_object when
+// schema is nil); Anthropic is forced to answer through a tool whose input schema is
+// schema. Every response is then validated against the schema, failing with a
+// *JSONValidationError. Use RequestBuilder.WithJSONResponse for a single request.
+//
+// OpenAI's strict mode is requested only when the schema meets its rules: every
+// object lists all its properties in "required" and sets "additionalProperties" to
+// false. Other schemas are sent non-strict (still validated here).
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithJSONResponse(map[string]any{
+//       "type":                 "object",
+//       "required":             []string{"action", "confidence"},
+//       "additionalProperties": false,
+//       "properties": map[string]any{
+//           "action":     map[string]any{"type": "string", "enum": []string{"buy", "sell", "hold"}},
+//           "confidence": map[string]any{"type": "number"},
+//       },
+//   }))
+func WithJSONResponse(schema map[string]any) ClientOption {
+	return func(c *Config) {
+		c.ResponseFormat = &ResponseFormat{Schema: schema}
+	}
+}
+
+// responseFormatKey context key of the per-request response format
+type responseFormatKey struct{}
+
+// responseFormatFor returns response format of the call (request-level first, then client)
+func (client *Client) responseFormatFor(ctx context.Context) *ResponseFormat {
+	if format, ok := ctx.Value(responseFormatKey{}).(*ResponseFormat); ok {
+		return format
+	}
+	return client.config.ResponseFormat
+}
+
+// openAIResponseFormat builds OpenAI-compatible response_format value
+func openAIResponseFormat(format *ResponseFormat) map[string]any {
+	if format.Schema == nil {
+		return map[string]any{"type": "json_object"}
+	}
+	name := format.Name
+	if name == "" {
+		name = "response"
+	}
+	return map[string]any{
+		"type": "json_schema",
+		"json_schema": map[string]any{
+			"name":   name,
+			"schema": format.Schema,
+			"strict": strictSchema(format.Schema),
+		},
+	}
+}
+
+// strictSchema reports whether schema meets OpenAI's strict mode rules: every object
+// requires all its properties and disallows additional ones
+func strictSchema(schema map[string]any) bool {
+	properties, hasProperties := schema["properties"].(map[string]any)
+	if hasProperties || slices.Contains(schemaTypes(schema["type"]), "object") {
+		if additional, ok := schema["additionalProperties"].(bool); !ok || additional {
+			return false
+		}
+		required, _ := toSlice(schema["required"])
+		if len(required) != len(properties) {
+			return false
+		}
+		for _, name := range required {
+			propSchema, ok := properties[fmt.Sprint(name)].(map[string]any)
+			if !ok || !strictSchema(propSchema) {
+				return false
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok && !strictSchema(items) {
+		return false
+	}
+	return true
+}
+
+// anthropicJSONTool adds a forced tool to an Anthropic request body so the answer is JSON
+func anthropicJSONTool(requestBody map[string]any, format *ResponseFormat) {
+	schema := format.Schema
+	if schema == nil {
+		schema = map[string]any{"type": "object"}
+	}
+	requestBody["tools"] = []map[string]any{{
+		"name":         jsonResponseTool,
+		"description":  "Respond with the final answer as structured JSON.",
+		"input_schema": schema,
+	}}
+	requestBody["tool_choice"] = map[string]any{"type": "tool", "name": jsonResponseTool}
+}
+
+// validateJSONResponse checks text is JSON matching format's schema
+func validateJSONResponse(text string, format *ResponseFormat) error {
+	var value any
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return &JSONValidationError{Path: "$", Reason: err.Error()}
+	}
+	if decoder.More() {
+		return &JSONValidationError{Path: "$", Reason: "unexpected data after JSON value"}
+	}
+
+	schema := format.Schema
+	if schema == nil {
+		schema = map[string]any{"type": "object"}
+	}
+	return validateSchema(value, schema, "$")
+}
+
+// validateSchema validates value against the commonly used JSON Schema subset:
+// type, enum, properties, required, additionalProperties (false) and items
+func validateSchema(value any, schema map[string]any, path string) error {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if jsonTypeMatches(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return &JSONValidationError{Path: path, Reason: fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), jsonTypeOf(value))}
+		}
+	}
+
+	if enum, ok := toSlice(schema["enum"]); ok {
+		found := false
+		for _, allowed := range enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &JSONValidationError{Path: path, Reason: fmt.Sprintf("value %v not in enum %v", value, enum)}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		required, _ := toSlice(schema["required"])
+		for _, name := range required {
+			if _, ok := v[fmt.Sprint(name)]; !ok {
+				return &JSONValidationError{Path: path, Reason: fmt.Sprintf("missing required property %q", name)}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			propSchema, ok := properties[key].(map[string]any)
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return &JSONValidationError{Path: path, Reason: fmt.Sprintf("unexpected property %q", key)}
+				}
+				continue
+			}
+			if err := validateSchema(v[key], propSchema, path+"."+key); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateSchema(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypes normalizes "type" (string or list) to a list
+func schemaTypes(t any) []string {
+	if s, ok := t.(string); ok {
+		return []string{s}
+	}
+	list, _ := toSlice(t)
+	types := make([]string, 0, len(list))
+	for _, item := range list {
+		types = append(types, fmt.Sprint(item))
+	}
+	return types
+}
+
+// toSlice converts []any or []string to []any
+func toSlice(v any) ([]any, bool) {
+	switch list := v.(type) {
+	case []any:
+		return list, true
+	case []string:
+		out := make([]any, len(list))
+		for i, s := range list {
+			out[i] = s
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// jsonTypeMatches reports whether decoded value is of JSON Schema type t
+func jsonTypeMatches(value any, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return jsonTypeOf(value) == t
+	}
+}
+
+// jsonTypeOf returns JSON Schema type name of decoded value
+func jsonTypeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
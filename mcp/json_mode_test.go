@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// ============================================================
+// Test JSON Mode / Structured Output
+// ============================================================
+
+var decisionSchema = map[string]any{
+	"type":     "object",
+	"required": []string{"action", "confidence"},
+	"properties": map[string]any{
+		"action":     map[string]any{"type": "string", "enum": []string{"buy", "sell", "hold"}},
+		"confidence": map[string]any{"type": "number"},
+	},
+}
+
+// captureBodyClient returns HTTP client answering with response and storing request bodies
+func captureBodyClient(response string, bodies *[]map[string]any) *http.Client {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		var body map[string]any
+		json.Unmarshal(data, &body)
+		*bodies = append(*bodies, body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(response)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	return mockHTTP.ToHTTPClient()
+}
+
+func TestJSONResponse_OpenAIResponseFormat(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"{\"action\":\"buy\",\"confidence\":0.8}"}}]}`
+
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithJSONResponse(decisionSchema),
+	)
+
+	result, err := client.CallWithMessages("system", "decide")
+	if err != nil {
+		t.Fatalf("valid JSON should pass: %v", err)
+	}
+	if result != `{"action":"buy","confidence":0.8}` {
+		t.Errorf("unexpected result %s", result)
+	}
+
+	format, _ := bodies[0]["response_format"].(map[string]any)
+	if format["type"] != "json_schema" {
+		t.Fatalf("expected response_format json_schema, got %v", bodies[0]["response_format"])
+	}
+	if jsonSchema, _ := format["json_schema"].(map[string]any); jsonSchema["schema"] == nil || jsonSchema["strict"] != false {
+		t.Errorf("schema should be sent non-strict, got %v", jsonSchema)
+	}
+}
+
+func TestStrictSchema(t *testing.T) {
+	strict := map[string]any{
+		"type":                 "object",
+		"required":             []string{"action", "legs"},
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"action": map[string]any{"type": "string"},
+			"legs": map[string]any{"type": "array", "items": map[string]any{
+				"type":                 "object",
+				"required":             []string{"symbol"},
+				"additionalProperties": false,
+				"properties":           map[string]any{"symbol": map[string]any{"type": "string"}},
+			}},
+		},
+	}
+	if !strictSchema(strict) {
+		t.Error("schema meeting the strict rules should be strict")
+	}
+	if strictSchema(decisionSchema) {
+		t.Error("schema allowing additional properties must not be strict")
+	}
+	legs := strict["properties"].(map[string]any)["legs"].(map[string]any)["items"].(map[string]any)
+	legs["properties"].(map[string]any)["size"] = map[string]any{"type": "number"}
+	if strictSchema(strict) {
+		t.Error("nested object with an optional property must not be strict")
+	}
+}
+
+func TestJSONResponse_RequestLevelWithoutSchema(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"not json"}}]}`
+
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	)
+
+	request := NewRequestBuilder().WithUserPrompt("decide").WithJSONResponse(nil).MustBuild()
+	_, err := client.CallWithRequest(request)
+	if !errors.Is(err, ErrInvalidJSONResponse) {
+		t.Errorf("expected ErrInvalidJSONResponse, got %v", err)
+	}
+
+	format, _ := bodies[0]["response_format"].(map[string]any)
+	if format["type"] != "json_object" {
+		t.Errorf("expected response_format json_object, got %v", bodies[0]["response_format"])
+	}
+}
+
+func TestJSONResponse_ClaudeForcedTool(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"content":[{"type":"tool_use","name":"json_response","input":{"action":"hold","confidence":0.5}}]}`
+
+	client := NewClaudeClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithJSONResponse(decisionSchema),
+	)
+
+	result, err := client.CallWithMessages("system", "decide")
+	if err != nil {
+		t.Fatalf("tool output should be returned as JSON: %v", err)
+	}
+	if result != `{"action":"hold","confidence":0.5}` {
+		t.Errorf("unexpected result %s", result)
+	}
+
+	choice, _ := bodies[0]["tool_choice"].(map[string]any)
+	if choice["type"] != "tool" || choice["name"] != jsonResponseTool {
+		t.Errorf("expected forced json_response tool, got %v", bodies[0]["tool_choice"])
+	}
+	if _, ok := bodies[0]["response_format"]; ok {
+		t.Error("Anthropic should not receive response_format")
+	}
+}
+
+func TestValidateJSONResponse(t *testing.T) {
+	format := &ResponseFormat{Schema: decisionSchema}
+
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"valid", `{"action":"sell","confidence":1}`, false},
+		{"not json", `maybe buy`, true},
+		{"trailing data", `{"action":"buy","confidence":1} extra`, true},
+		{"missing required", `{"action":"buy"}`, true},
+		{"wrong type", `{"action":"buy","confidence":"high"}`, true},
+		{"not in enum", `{"action":"short","confidence":1}`, true},
+		{"not an object", `["buy"]`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateJSONResponse(tt.text, format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("wantErr %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_NestedArrays(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"orders": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":                 "object",
+					"required":             []any{"qty"},
+					"properties":           map[string]any{"qty": map[string]any{"type": "integer"}},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+	format := &ResponseFormat{Schema: schema}
+
+	if err := validateJSONResponse(`{"orders":[{"qty":2},{"qty":3}]}`, format); err != nil {
+		t.Errorf("should be valid: %v", err)
+	}
+
+	err := validateJSONResponse(`{"orders":[{"qty":2},{"qty":1.5}]}`, format)
+	var validationErr *JSONValidationError
+	if !errors.As(err, &validationErr) || validationErr.Path != "$.orders[1].qty" {
+		t.Errorf("expected violation at $.orders[1].qty, got %v", err)
+	}
+
+	if err := validateJSONResponse(`{"orders":[{"qty":2,"side":"buy"}]}`, format); err == nil {
+		t.Error("additional property should be rejected")
+	}
+}
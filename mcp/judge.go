@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultJudgeThreshold min normalized score for a passing verdict
+const DefaultJudgeThreshold = 0.7
+
+// judgeSystemPrompt standardized grading rubric
+const judgeSystemPrompt = `You are a strict, impartial grader. Evaluate the candidate output against the criteria.
+
+Scoring rubric (0-10):
+- 9-10: fully meets every criterion, no errors
+- 7-8: meets the criteria with minor issues
+- 4-6: partially meets the criteria, notable gaps or errors
+- 1-3: mostly fails the criteria
+- 0: irrelevant, empty or harmful
+
+Judge only against the criteria, not your own preferences. Reply only with JSON:
+{"score": <integer 0-10>, "rationale": "<one or two sentences>"}`
+
+// JudgeResult structured verdict of a judge model
+type JudgeResult struct {
+	Score     float64 // Normalized score in [0,1]
+	Pass      bool    // Score >= DefaultJudgeThreshold
+	Rationale string  // Judge explanation
+	Raw       string  // Raw judge reply
+}
+
+// Judge grades candidateOutput against criteria using this client as judge model
+//
+// Usage example:
+//   verdict, err := judge.Judge(ctx, "Decision must include a stop loss below entry", decision)
+//   if err == nil && !verdict.Pass {
+//       log.Printf("rejected decision: %s", verdict.Rationale)
+//   }
+func (client *Client) Judge(ctx context.Context, criteria, candidateOutput string) (*JudgeResult, error) {
+	return JudgeWith(ctx, client, criteria, candidateOutput)
+}
+
+// JudgeWith grades candidateOutput against criteria using judge as judge model
+//
+// The judge receives a standardized rubric prompt and must reply with a 0-10 score and
+// rationale; the score is normalized to [0,1].
+func JudgeWith(ctx context.Context, judge AIClient, criteria, candidateOutput string) (*JudgeResult, error) {
+	req, err := NewRequestBuilder().
+		WithSystemPrompt(judgeSystemPrompt).
+		WithUserPrompt(fmt.Sprintf("Criteria:\n%s\n\nCandidate output:\n%s", criteria, candidateOutput)).
+		WithTemperature(0).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := callRequest(ctx, judge, req)
+	if err != nil {
+		return nil, fmt.Errorf("judge call failed: %w", err)
+	}
+	return parseJudgeReply(reply)
+}
+
+// parseJudgeReply parses judge JSON verdict (tolerating code fences and prose around it)
+func parseJudgeReply(reply string) (*JudgeResult, error) {
+	var verdict struct {
+		Score     json.Number `json:"score"`
+		Rationale string      `json:"rationale"`
+		Reason    string      `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(reply)), &verdict); err != nil {
+		return nil, fmt.Errorf("judge returned invalid verdict: %w, reply: %s", err, reply)
+	}
+	score, err := strconv.ParseFloat(verdict.Score.String(), 64)
+	if err != nil || score < 0 || score > 10 {
+		return nil, fmt.Errorf("judge returned invalid score %q", verdict.Score)
+	}
+
+	rationale := verdict.Rationale
+	if rationale == "" {
+		rationale = verdict.Reason
+	}
+	result := &JudgeResult{
+		Score:     score / 10,
+		Rationale: strings.TrimSpace(rationale),
+		Raw:       reply,
+	}
+	result.Pass = result.Score >= DefaultJudgeThreshold
+	return result, nil
+}
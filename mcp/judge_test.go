@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestJudgeWith_StructuredVerdict(t *testing.T) {
+	judge := &stubClient{answer: "```json\n{\"score\": 8, \"rationale\": \"Stop loss present, sizing unclear.\"}\n```"}
+
+	verdict, err := JudgeWith(context.Background(), judge, "must include a stop loss", `{"action":"buy","stop_loss":61000}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verdict.Score != 0.8 || !verdict.Pass {
+		t.Errorf("expected passing 0.8 score, got %+v", verdict)
+	}
+	if verdict.Rationale != "Stop loss present, sizing unclear." {
+		t.Errorf("unexpected rationale %q", verdict.Rationale)
+	}
+	if !strings.Contains(judge.prompts[0], "must include a stop loss") || !strings.Contains(judge.prompts[0], "61000") {
+		t.Errorf("judge should see criteria and candidate, got %q", judge.prompts[0])
+	}
+}
+
+func TestParseJudgeReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		score   float64
+		pass    bool
+		wantErr bool
+	}{
+		{"failing score", `{"score": 3, "rationale": "no stop loss"}`, 0.3, false, false},
+		{"threshold", `Verdict: {"score": 7, "reason": "ok"}`, 0.7, true, false},
+		{"fractional", `{"score": 9.5, "rationale": "great"}`, 0.95, true, false},
+		{"out of range", `{"score": 42}`, 0, false, true},
+		{"no json", `looks fine to me`, 0, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := parseJudgeReply(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wantErr=%v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (verdict.Score != tt.score || verdict.Pass != tt.pass) {
+				t.Errorf("unexpected verdict %+v", verdict)
+			}
+		})
+	}
+}
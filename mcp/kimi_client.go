@@ -52,7 +52,7 @@ func (c *KimiClient) SetAPIKey(apiKey string, customURL string, customModel stri
 		c.logger.Infof("🔧 [MCP] Kimi API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
 	}
 	if customURL != "" {
-		c.BaseURL = customURL
+		c.setBaseURL(customURL)
 		c.logger.Infof("🔧 [MCP] Kimi using custom BaseURL: %s", customURL)
 	} else {
 		c.logger.Infof("🔧 [MCP] Kimi using default BaseURL: %s", c.BaseURL)
@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// LatencyBreakdown per-call latency split by phase
+//
+// Phases that did not happen (e.g. DNS/connect/TLS on a reused connection) are zero.
+type LatencyBreakdown struct {
+	DNS        time.Duration // DNS lookup
+	Connect    time.Duration // TCP connect
+	TLS        time.Duration // TLS handshake
+	TTFB       time.Duration // Request start until first response byte
+	Transfer   time.Duration // First response byte until body fully read (stream duration)
+	Total      time.Duration // Request start until body fully read
+	ReusedConn bool          // Whether an idle keep-alive connection was reused
+}
+
+// Network returns time spent establishing the connection (DNS + connect + TLS)
+func (l LatencyBreakdown) Network() time.Duration {
+	return l.DNS + l.Connect + l.TLS
+}
+
+// ServerWait returns time the provider took to start answering (TTFB minus connection setup)
+func (l LatencyBreakdown) ServerWait() time.Duration {
+	if wait := l.TTFB - l.Network(); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// Dominant returns the phase that dominates total latency: "network", "provider" or "transfer"
+func (l LatencyBreakdown) Dominant() string {
+	network, server, transfer := l.Network(), l.ServerWait(), l.Transfer
+	switch {
+	case network >= server && network >= transfer:
+		return "network"
+	case server >= transfer:
+		return "provider"
+	default:
+		return "transfer"
+	}
+}
+
+// SlowCall information passed to slow call callback
+type SlowCall struct {
+	Provider  string
+	Model     string
+	URL       string
+	Threshold time.Duration
+	Latency   LatencyBreakdown
+}
+
+// WithSlowThreshold sets threshold above which a call is reported as slow (0 = disabled)
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithSlowThreshold(10 * time.Second))
+func WithSlowThreshold(threshold time.Duration) ClientOption {
+	return func(c *Config) {
+		c.SlowThreshold = threshold
+	}
+}
+
+// WithSlowCallCallback sets callback invoked for every call exceeding the slow threshold
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithSlowThreshold(10*time.Second),
+//       mcp.WithSlowCallCallback(func(call mcp.SlowCall) { alert(call) }),
+//   )
+func WithSlowCallCallback(callback func(SlowCall)) ClientOption {
+	return func(c *Config) {
+		c.OnSlowCall = callback
+	}
+}
+
+// LastLatency returns latency breakdown of the most recent call
+func (client *Client) LastLatency() LatencyBreakdown {
+	client.lastMu.Lock()
+	defer client.lastMu.Unlock()
+	return client.lastLatency
+}
+
+// observeLatency stores latency of finished call and reports slow calls
+func (client *Client) observeLatency(latency LatencyBreakdown) {
+	client.lastMu.Lock()
+	client.lastLatency = latency
+	client.lastMu.Unlock()
+
+	threshold := client.config.SlowThreshold
+	if threshold <= 0 || latency.Total < threshold {
+		return
+	}
+
+	client.logger.Warnf("🐢 [%s] Slow AI call: total=%v threshold=%v dominant=%s network=%v ttfb=%v transfer=%v reused_conn=%v",
+		client.String(), latency.Total, threshold, latency.Dominant(),
+		latency.Network(), latency.TTFB, latency.Transfer, latency.ReusedConn)
+
+	if client.config.OnSlowCall != nil {
+		client.config.OnSlowCall(SlowCall{
+			Provider:  client.Provider,
+			Model:     client.Model,
+			URL:       client.hooks.buildUrl(),
+			Threshold: threshold,
+			Latency:   latency,
+		})
+	}
+}
+
+// latencyTracker collects httptrace timestamps for one round trip
+type latencyTracker struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+	reusedConn   bool
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{start: time.Now()}
+}
+
+// attach returns request carrying trace hooks of this tracker
+func (t *latencyTracker) attach(req *http.Request) *http.Request {
+	set := func(field *time.Time) {
+		t.mu.Lock()
+		*field = time.Now()
+		t.mu.Unlock()
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { set(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { set(&t.dnsDone) },
+		ConnectStart:      func(string, string) { set(&t.connectStart) },
+		ConnectDone:       func(string, string, error) { set(&t.connectDone) },
+		TLSHandshakeStart: func() { set(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { set(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reusedConn = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() { set(&t.firstByte) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// finish returns breakdown measured until now
+func (t *latencyTracker) finish() LatencyBreakdown {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	firstByte := t.firstByte
+	if firstByte.IsZero() {
+		// Transports without tracing support (e.g. mocks): count everything as TTFB
+		firstByte = now
+	}
+	return LatencyBreakdown{
+		DNS:        span(t.dnsStart, t.dnsDone),
+		Connect:    span(t.connectStart, t.connectDone),
+		TLS:        span(t.tlsStart, t.tlsDone),
+		TTFB:       span(t.start, firstByte),
+		Transfer:   span(firstByte, now),
+		Total:      now.Sub(t.start),
+		ReusedConn: t.reusedConn,
+	}
+}
+
+// span returns duration between two instants (0 if either is missing)
+func span(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() {
+		return 0
+	}
+	return to.Sub(from)
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Latency Breakdown and Slow Call Detection
+// ============================================================
+
+func TestLatency_SlowCallDetected(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		time.Sleep(30 * time.Millisecond)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"choices":[{"message":{"content":"slow"}}]}`)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	mockLogger := NewMockLogger()
+
+	var slow []SlowCall
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(mockLogger),
+		WithAPIKey("test-key"),
+		WithSlowThreshold(10*time.Millisecond),
+		WithSlowCallCallback(func(call SlowCall) { slow = append(slow, call) }),
+	).(*Client)
+
+	if _, err := client.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	if len(slow) != 1 {
+		t.Fatalf("expected 1 slow call, got %d", len(slow))
+	}
+	if slow[0].Latency.Total < 30*time.Millisecond {
+		t.Errorf("total latency should be >= 30ms, got %v", slow[0].Latency.Total)
+	}
+	if slow[0].Provider != ProviderDeepSeek {
+		t.Errorf("expected provider %s, got %s", ProviderDeepSeek, slow[0].Provider)
+	}
+	if len(mockLogger.GetLogsByLevel("WARN")) == 0 {
+		t.Error("slow call should emit a warning")
+	}
+	if client.LastLatency().Total != slow[0].Latency.Total {
+		t.Error("LastLatency should match reported latency")
+	}
+}
+
+func TestLatency_FastCallNotReported(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("fast")
+
+	called := false
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithSlowThreshold(time.Minute),
+		WithSlowCallCallback(func(SlowCall) { called = true }),
+	)
+
+	client.CallWithMessages("system", "user")
+	if called {
+		t.Error("fast call should not be reported as slow")
+	}
+}
+
+func TestLatencyBreakdown_Dominant(t *testing.T) {
+	tests := []struct {
+		name     string
+		latency  LatencyBreakdown
+		expected string
+	}{
+		{"network", LatencyBreakdown{DNS: 2 * time.Second, Connect: time.Second, TTFB: 3500 * time.Millisecond}, "network"},
+		{"provider", LatencyBreakdown{Connect: 100 * time.Millisecond, TTFB: 5 * time.Second, Transfer: time.Second}, "provider"},
+		{"transfer", LatencyBreakdown{TTFB: time.Second, Transfer: 10 * time.Second}, "transfer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.latency.Dominant(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
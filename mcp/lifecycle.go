@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrClientClosed is returned by calls made after Close
+var ErrClientClosed = errors.New("AI client is closed")
+
+// lifecycle tracks in-flight calls so Close can drain them
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+}
+
+// begin registers an in-flight call (fails after Close)
+func (l *lifecycle) begin() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClientClosed
+	}
+	l.inflight.Add(1)
+	return nil
+}
+
+// end unregisters an in-flight call
+func (l *lifecycle) end() {
+	l.inflight.Done()
+}
+
+// Close shuts the client down gracefully
+//
+// New calls fail with ErrClientClosed immediately. Close then waits for in-flight
+// calls to finish until ctx is done (pending shadow calls are then cancelled), flushes the audit sink and webhook events and closes idle
+// connections of a transport created for this client alone. The audit sink, the shared
+// connection pool and injected HTTP clients are left open since they may be shared.
+// Returns ctx.Err() if in-flight calls didn't finish in time.
+//
+// Usage example:
+//   ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//   defer cancel()
+//   if err := client.Close(ctx); err != nil {
+//       log.Printf("AI client did not drain: %v", err)
+//   }
+func (client *Client) Close(ctx context.Context) error {
+	client.lifecycle.mu.Lock()
+	client.lifecycle.closed = true
+	client.lifecycle.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		client.lifecycle.inflight.Wait()
+		close(drained)
+	}()
+
+	var errs []error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("in-flight AI calls not drained: %w", ctx.Err()))
+		if client.shadow != nil {
+			client.shadow.abort() // Shadow calls only serve offline comparison
+		}
+	}
+
+	if flusher, ok := client.config.AuditSink.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush audit sink: %w", err))
+		}
+	}
+	if client.webhook != nil {
+		if err := client.webhook.flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("webhook events not delivered: %w", err))
+		}
+	}
+	// The shared pool and injected HTTP clients serve other clients too
+	if client.ownedTransport != nil {
+		client.ownedTransport.CloseIdleConnections()
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Graceful Shutdown
+// ============================================================
+
+func TestClose_DrainsInFlightAndRejectsNewCalls(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte(`{"choices":[{"message":{"content":"finished"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL(server.URL),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	type outcome struct {
+		result string
+		err    error
+	}
+	done := make(chan outcome)
+	go func() {
+		result, err := client.CallWithMessages("system", "user")
+		done <- outcome{result, err}
+	}()
+	<-started
+
+	// Deadline expires while the call is still in flight
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while draining, got %v", err)
+	}
+
+	if _, err := client.CallWithMessages("system", "user"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed for new call, got %v", err)
+	}
+
+	close(release)
+	if got := <-done; got.err != nil || got.result != "finished" {
+		t.Errorf("in-flight call should complete normally, got %q / %v", got.result, got.err)
+	}
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Errorf("close after drain should succeed, got %v", err)
+	}
+}
+
+func TestClose_FlushesAuditSink(t *testing.T) {
+	sink := &flushRecordingSink{}
+	client := NewClient(WithLogger(NewNoopLogger()), WithAuditSink(sink)).(*Client)
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("close should succeed: %v", err)
+	}
+	if !sink.flushed {
+		t.Error("audit sink should be flushed on close")
+	}
+	if sink.closed {
+		t.Error("audit sink may be shared and should not be closed")
+	}
+}
+
+// flushRecordingSink audit sink recording Flush/Close calls
+type flushRecordingSink struct {
+	flushed bool
+	closed  bool
+}
+
+func (s *flushRecordingSink) Write(record AuditRecord) error { return nil }
+func (s *flushRecordingSink) Flush() error                   { s.flushed = true; return nil }
+func (s *flushRecordingSink) Close() error                   { s.closed = true; return nil }
+
+// connTracker counts connections opened and closed by an httptest server
+type connTracker struct {
+	mu             sync.Mutex
+	opened, closed int
+}
+
+func (c *connTracker) track(server *httptest.Server) {
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		switch state {
+		case http.StateNew:
+			c.opened++
+		case http.StateClosed:
+			c.closed++
+		}
+	}
+}
+
+func (c *connTracker) counts() (opened, closed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.opened, c.closed
+}
+
+func TestClose_LeavesSharedPoolOpen(t *testing.T) {
+	var tracker connTracker
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	tracker.track(server)
+	server.Start()
+	defer server.Close()
+
+	// Both clients share one pooled transport (pool settings unused by other tests)
+	newClient := func() *Client {
+		return NewClient(WithProvider(ProviderCustom), WithBaseURL(server.URL), WithAPIKey("sk-test-key"),
+			WithLogger(NewNoopLogger()), WithMaxIdleConnsPerHost(7)).(*Client)
+	}
+	closing, serving := newClient(), newClient()
+	if _, err := serving.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if err := closing.Close(context.Background()); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if _, err := serving.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if opened, closed := tracker.counts(); opened != 1 || closed != 0 {
+		t.Errorf("closing one client should keep the shared pool, got %d opened / %d closed connections", opened, closed)
+	}
+}
+
+func TestClose_ClosesOwnedTransport(t *testing.T) {
+	var tracker connTracker
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	tracker.track(server)
+	server.StartTLS()
+	defer server.Close()
+
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL(server.URL), WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()), WithTLSConfig(tlsConfig)).(*Client)
+	if _, err := client.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		_, closed := tracker.counts()
+		return closed == 1
+	})
+}
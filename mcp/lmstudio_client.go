@@ -0,0 +1,80 @@
+package mcp
+
+const (
+	ProviderLMStudio       = "lmstudio"
+	DefaultLMStudioBaseURL = "http://localhost:1234/v1"
+)
+
+type LMStudioClient struct {
+	*Client
+}
+
+// NewLMStudioClient creates LM Studio local server client (backward compatible)
+func NewLMStudioClient() AIClient {
+	return NewLMStudioClientWithOptions()
+}
+
+// NewLMStudioClientWithOptions creates LM Studio client (supports options pattern)
+//
+// LM Studio serves an OpenAI-compatible API on port 1234 and needs no API key, so
+// calls are made without Authorization unless a key is set. Without a model the
+// currently loaded one answers; naming a model loads it on demand.
+//
+// Usage example:
+//   client := mcp.NewLMStudioClientWithOptions(mcp.WithModel("qwen2.5-7b-instruct"))
+//   answer, err := client.CallWithMessages(systemPrompt, userPrompt)
+func NewLMStudioClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create LM Studio preset options
+	lmStudioOpts := []ClientOption{
+		WithProvider(ProviderLMStudio),
+		WithModel(""),
+		WithBaseURL(DefaultLMStudioBaseURL),
+		WithZeroAuth(),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(lmStudioOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create LM Studio client
+	lmStudioClient := &LMStudioClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to LMStudioClient (implement dynamic dispatch)
+	baseClient.hooks = lmStudioClient
+
+	return lmStudioClient
+}
+
+func (c *LMStudioClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] LM Studio using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] LM Studio using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] LM Studio using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] LM Studio using the loaded model")
+	}
+}
+
+// marshalRequestBody leaves the model out when none is named, so the loaded one answers
+func (c *LMStudioClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	omitEmptyModel(requestBody)
+	return c.Client.marshalRequestBody(requestBody)
+}
+
+// omitEmptyModel removes an empty model from a request body
+func omitEmptyModel(requestBody map[string]any) {
+	if model, ok := requestBody["model"].(string); ok && model == "" {
+		delete(requestBody, "model")
+	}
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"testing"
+)
+
+// ============================================================
+// Test LMStudioClient
+// ============================================================
+
+func TestLMStudioClient_ZeroAuth(t *testing.T) {
+	var bodies []map[string]any
+	mockHTTP := captureBodyClient(`{"choices":[{"message":{"content":"Hold BTC."}}]}`, &bodies)
+	client := NewLMStudioClientWithOptions(
+		WithHTTPClient(mockHTTP),
+		WithLogger(NewNoopLogger()),
+	).(*LMStudioClient)
+
+	if err := client.Validate(); err != nil {
+		t.Errorf("key-less local client should validate, got %v", err)
+	}
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call without API key failed: %v", err)
+	}
+	req := mockHTTP.Transport.(*MockHTTPClient).GetLastRequest()
+	if req.URL.String() != DefaultLMStudioBaseURL+"/chat/completions" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if _, ok := req.Header["Authorization"]; ok {
+		t.Errorf("expected no Authorization header, got %q", req.Header.Get("Authorization"))
+	}
+	if _, ok := bodies[0]["model"]; ok {
+		t.Errorf("empty model must be left out, got %v", bodies[0]["model"])
+	}
+
+	// A configured key is still sent
+	client.SetAPIKey("lm-studio-key", "", "qwen2.5-7b-instruct")
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	req = mockHTTP.Transport.(*MockHTTPClient).GetLastRequest()
+	if req.Header.Get("Authorization") != "Bearer lm-studio-key" || bodies[1]["model"] != "qwen2.5-7b-instruct" {
+		t.Errorf("unexpected request %v %v", req.Header, bodies[1])
+	}
+}
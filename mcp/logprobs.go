@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// TokenLogprob log probability of one generated token
+type TokenLogprob struct {
+	Token       string       `json:"token"`
+	Logprob     float64      `json:"logprob"`
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"` // Most likely alternatives at this position
+}
+
+// TopLogprob alternative token and its log probability
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+}
+
+// Probability returns probability (0-1) of the token
+func (t TokenLogprob) Probability() float64 {
+	return math.Exp(t.Logprob)
+}
+
+// Confidence returns geometric mean probability (0-1) of the generated tokens
+//
+// Usable as a rough confidence score of a decision; returns 0 when the response
+// carries no logprobs (not requested or not supported by the provider).
+func (r *Response) Confidence() float64 {
+	if len(r.Logprobs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, t := range r.Logprobs {
+		sum += t.Logprob
+	}
+	return math.Exp(sum / float64(len(r.Logprobs)))
+}
+
+// applyLogprobParams adds logprobs request parameters (OpenAI-compatible format)
+func (client *Client) applyLogprobParams(requestBody map[string]any, req *Request) {
+	// Anthropic has no logprobs support
+	if client.Provider == ProviderClaude || (!req.Logprobs && req.TopLogprobs == 0) {
+		return
+	}
+	requestBody["logprobs"] = true
+	if req.TopLogprobs > 0 {
+		requestBody["top_logprobs"] = req.TopLogprobs
+	}
+}
+
+// parseLogprobs extracts token logprobs of the first choice from response body
+func parseLogprobs(body []byte) []TokenLogprob {
+	var response struct {
+		Choices []struct {
+			Logprobs *struct {
+				Content []TokenLogprob `json:"content"`
+			} `json:"logprobs"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Choices) == 0 {
+		return nil
+	}
+	if response.Choices[0].Logprobs == nil {
+		return nil
+	}
+	return response.Choices[0].Logprobs.Content
+}
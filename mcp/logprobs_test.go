@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// ============================================================
+// Test Logprobs
+// ============================================================
+
+func TestLogprobs_RequestAndParse(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"buy"},"logprobs":{"content":[
+		{"token":"buy","logprob":-0.105,"top_logprobs":[{"token":"buy","logprob":-0.105},{"token":"hold","logprob":-2.4}]}
+	]}}]}`
+
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	request := NewRequestBuilder().WithUserPrompt("decide").WithLogprobs(2).MustBuild()
+	response, err := client.Call(context.Background(), request)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	if bodies[0]["logprobs"] != true || bodies[0]["top_logprobs"] != float64(2) {
+		t.Errorf("expected logprobs=true top_logprobs=2, got %v / %v", bodies[0]["logprobs"], bodies[0]["top_logprobs"])
+	}
+
+	if len(response.Logprobs) != 1 {
+		t.Fatalf("expected 1 token logprob, got %d", len(response.Logprobs))
+	}
+	token := response.Logprobs[0]
+	if token.Token != "buy" || len(token.TopLogprobs) != 2 || token.TopLogprobs[1].Token != "hold" {
+		t.Errorf("unexpected token logprob %+v", token)
+	}
+	if math.Abs(response.Confidence()-math.Exp(-0.105)) > 1e-9 {
+		t.Errorf("unexpected confidence %v", response.Confidence())
+	}
+}
+
+func TestLogprobs_NotRequested(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"buy"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	response, err := client.Call(context.Background(), NewRequestBuilder().WithUserPrompt("decide").MustBuild())
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if _, ok := bodies[0]["logprobs"]; ok {
+		t.Error("logprobs should not be sent unless requested")
+	}
+	if response.Logprobs != nil || response.Confidence() != 0 {
+		t.Error("response without logprobs should have zero confidence")
+	}
+}
+
+func TestLogprobs_NotSentToClaude(t *testing.T) {
+	claude := NewClaudeClientWithOptions(WithLogger(NewNoopLogger())).(*ClaudeClient)
+	body := claude.buildRequestBodyFromRequest(NewRequestBuilder().WithUserPrompt("x").WithLogprobs(5).MustBuild())
+	if _, ok := body["logprobs"]; ok {
+		t.Error("Anthropic doesn't support logprobs")
+	}
+}
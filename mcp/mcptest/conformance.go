@@ -0,0 +1,264 @@
+package mcptest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"nofx/mcp"
+)
+
+const (
+	conformanceKey   = "sk-conformance-key"
+	conformanceModel = "conformance-model"
+)
+
+// wireFormat request/response format spoken by a client
+type wireFormat int
+
+const (
+	formatOpenAI wireFormat = iota
+	formatAnthropic
+)
+
+// RunConformance verifies client against the shared provider behavioral spec
+//
+// The client is pointed at a fresh fake Server for every check via SetAPIKey, so any
+// AIClient implementation (built-in providers or user-defined ones) can be verified:
+//
+//	func TestMyProvider_Conformance(t *testing.T) {
+//		mcptest.RunConformance(t, NewMyProviderClient())
+//	}
+//
+// Covered: request shape, auth header, tool definitions, streaming (when supported),
+// HTTP error mapping, empty and malformed response handling.
+func RunConformance(t *testing.T, client mcp.AIClient) {
+	t.Helper()
+
+	format := detectFormat(t, client)
+
+	t.Run("RequestShape", func(t *testing.T) {
+		server := attach(client)
+		defer server.Close()
+		server.Enqueue(ServerReply{Content: "conformance answer"})
+
+		got, err := client.CallWithMessages("conformance system", "conformance user")
+		if err != nil {
+			t.Fatalf("call should succeed: %v", err)
+		}
+		if got != "conformance answer" {
+			t.Errorf("expected response text 'conformance answer', got %q", got)
+		}
+
+		req := singlePost(t, server)
+		if ct := req.Headers.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("expected JSON Content-Type, got %q", ct)
+		}
+		if req.Body["model"] != conformanceModel {
+			t.Errorf("expected model %q in body, got %v", conformanceModel, req.Body["model"])
+		}
+		if _, ok := req.Body["max_tokens"]; !ok {
+			if _, ok := req.Body["max_completion_tokens"]; !ok {
+				t.Error("body should contain max_tokens or max_completion_tokens")
+			}
+		}
+		if !hasSystemPrompt(req.Body, "conformance system") {
+			t.Error("system prompt missing from request body")
+		}
+		if !hasMessage(req.Body, "user", "conformance user") {
+			t.Error("user message missing from request body")
+		}
+	})
+
+	t.Run("AuthHeader", func(t *testing.T) {
+		server := attach(client)
+		defer server.Close()
+		server.RequireAPIKey(conformanceKey)
+
+		if _, err := client.CallWithMessages("sys", "user"); err != nil {
+			t.Fatalf("authenticated call should succeed: %v", err)
+		}
+
+		req := singlePost(t, server)
+		if req.Headers.Get("Authorization") != "Bearer "+conformanceKey && req.Headers.Get("x-api-key") != conformanceKey {
+			t.Error("API key should be sent as Bearer token or x-api-key header")
+		}
+
+		server.RequireAPIKey("another-key")
+		if _, err := client.CallWithMessages("sys", "user"); err == nil {
+			t.Error("call with rejected key should fail")
+		}
+	})
+
+	t.Run("ToolDefinitions", func(t *testing.T) {
+		server := attach(client)
+		defer server.Close()
+
+		request := mcp.NewRequestBuilder().
+			WithSystemPrompt("sys").
+			WithUserPrompt("what is the BTC price?").
+			AddFunction("get_price", "Get latest price", map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"symbol": map[string]any{"type": "string"}},
+			}).
+			MustBuild()
+
+		client.CallWithRequest(request)
+
+		req := singlePost(t, server)
+		if _, ok := req.Body["tools"]; !ok {
+			t.Fatal("tools should be sent in request body")
+		}
+		if !strings.Contains(string(req.RawBody), "get_price") {
+			t.Error("tool name should be sent in request body")
+		}
+	})
+
+	t.Run("Streaming", func(t *testing.T) {
+		streamer, ok := client.(interface {
+			CallStream(ctx context.Context, req *mcp.Request, onEvent func(mcp.StreamEvent) error) (*mcp.Response, error)
+		})
+		if !ok {
+			t.Skip("client exposes no streaming API")
+		}
+		server := attach(client)
+		defer server.Close()
+		server.Enqueue(ServerReply{Content: "streamed conformance answer"})
+
+		var (
+			deltas int
+			text   string
+			done   bool
+		)
+		request := mcp.NewRequestBuilder().WithSystemPrompt("sys").WithUserPrompt("user").MustBuild()
+		response, err := streamer.CallStream(context.Background(), request, func(event mcp.StreamEvent) error {
+			switch event.Type {
+			case mcp.StreamTextDelta:
+				deltas++
+				text += event.Text
+			case mcp.StreamDone:
+				done = true
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("streaming call should succeed: %v", err)
+		}
+
+		req := singlePost(t, server)
+		if stream, _ := req.Body["stream"].(bool); !stream {
+			t.Error(`streaming request should send "stream": true`)
+		}
+		if deltas < 2 {
+			t.Errorf("expected incremental text deltas, got %d", deltas)
+		}
+		if text != "streamed conformance answer" || response.Text != text {
+			t.Errorf("expected streamed text 'streamed conformance answer', got deltas %q and response %q", text, response.Text)
+		}
+		if !done {
+			t.Error("stream should end with a Done event")
+		}
+	})
+
+	t.Run("ErrorMapping", func(t *testing.T) {
+		for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError} {
+			server := attach(client)
+			server.Enqueue(ServerReply{StatusCode: status, Body: `{"error":{"message":"conformance failure","type":"invalid_request_error"}}`})
+
+			_, err := client.CallWithMessages("sys", "user")
+			server.Close()
+
+			if err == nil {
+				t.Errorf("status %d should produce an error", status)
+				continue
+			}
+			if !strings.Contains(err.Error(), http.StatusText(status)) && !strings.Contains(err.Error(), strconv.Itoa(status)) {
+				t.Errorf("error for status %d should mention the status, got: %v", status, err)
+			}
+		}
+	})
+
+	t.Run("EmptyResponse", func(t *testing.T) {
+		server := attach(client)
+		defer server.Close()
+
+		body := `{"choices":[]}`
+		if format == formatAnthropic {
+			body = `{"content":[]}`
+		}
+		server.Enqueue(ServerReply{Body: body})
+
+		if _, err := client.CallWithMessages("sys", "user"); err == nil {
+			t.Error("empty response should produce an error")
+		}
+	})
+
+	t.Run("MalformedResponse", func(t *testing.T) {
+		server := attach(client)
+		defer server.Close()
+		server.Enqueue(ServerReply{Body: `{"choices": [`})
+
+		if _, err := client.CallWithMessages("sys", "user"); err == nil {
+			t.Error("malformed JSON should produce an error")
+		}
+	})
+}
+
+// attach starts a fake server and points client at it
+func attach(client mcp.AIClient) *Server {
+	server := NewServer()
+	client.SetAPIKey(conformanceKey, server.OpenAIBaseURL(), conformanceModel)
+	return server
+}
+
+// detectFormat issues one call to learn which wire format the client speaks
+func detectFormat(t *testing.T, client mcp.AIClient) wireFormat {
+	t.Helper()
+
+	server := attach(client)
+	defer server.Close()
+	client.CallWithMessages("sys", "user")
+
+	if strings.HasSuffix(server.LastRequest().Path, "/messages") {
+		return formatAnthropic
+	}
+	return formatOpenAI
+}
+
+// singlePost returns the only POST request received by server
+func singlePost(t *testing.T, server *Server) RecordedRequest {
+	t.Helper()
+
+	var posts []RecordedRequest
+	for _, r := range server.Requests() {
+		if r.Method == http.MethodPost {
+			posts = append(posts, r)
+		}
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected exactly 1 POST request, got %d", len(posts))
+	}
+	return posts[0]
+}
+
+// hasSystemPrompt checks top-level "system" field (Anthropic) or a system message
+func hasSystemPrompt(body map[string]any, content string) bool {
+	if system, ok := body["system"].(string); ok && system == content {
+		return true
+	}
+	return hasMessage(body, "system", content)
+}
+
+// hasMessage checks messages array contains a message with role and content
+func hasMessage(body map[string]any, role, content string) bool {
+	messages, _ := body["messages"].([]any)
+	for _, m := range messages {
+		msg, _ := m.(map[string]any)
+		if msg["role"] == role && msg["content"] == content {
+			return true
+		}
+	}
+	return false
+}
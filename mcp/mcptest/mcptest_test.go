@@ -0,0 +1,132 @@
+package mcptest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/mcp"
+)
+
+func TestMockClient_Script(t *testing.T) {
+	boom := errors.New("boom")
+	client := NewMockClient("first").QueueError(boom).SetDefault(Reply{Content: "default"})
+
+	if got, _ := client.CallWithMessages("sys", "one"); got != "first" {
+		t.Errorf("expected 'first', got %q", got)
+	}
+	if _, err := client.CallWithMessages("sys", "two"); !errors.Is(err, boom) {
+		t.Errorf("expected scripted error, got %v", err)
+	}
+	if got, _ := client.CallWithRequest(mcp.NewRequestBuilder().WithUserPrompt("three").MustBuild()); got != "default" {
+		t.Errorf("expected 'default', got %q", got)
+	}
+
+	client.AssertCallCount(t, 3)
+	client.AssertPromptContains(t, "three")
+	client.AssertScriptConsumed(t)
+}
+
+func TestMockClient_ExhaustedScript(t *testing.T) {
+	client := NewMockClient()
+	if _, err := client.CallWithMessages("sys", "user"); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("expected ErrNoResponse, got %v", err)
+	}
+}
+
+func TestMockClient_Latency(t *testing.T) {
+	client := NewMockClient("slow").SetLatency(20 * time.Millisecond)
+	start := time.Now()
+	client.CallWithMessages("sys", "user")
+	if time.Since(start) < 20*time.Millisecond {
+		t.Error("latency should be applied")
+	}
+}
+
+func TestServer_OpenAICompatible(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.RequireAPIKey("test-key")
+	server.Enqueue(ServerReply{Content: "hello from fake"})
+
+	client := mcp.NewClient(
+		mcp.WithProvider(mcp.ProviderCustom),
+		mcp.WithBaseURL(server.OpenAIBaseURL()),
+		mcp.WithModel("test-model"),
+		mcp.WithAPIKey("test-key"),
+		mcp.WithLogger(mcp.NewNoopLogger()),
+	)
+
+	got, err := client.CallWithMessages("sys", "user")
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if got != "hello from fake" {
+		t.Errorf("expected 'hello from fake', got %q", got)
+	}
+
+	req := server.LastRequest()
+	if req.Path != "/v1/chat/completions" || req.Body["model"] != "test-model" {
+		t.Errorf("unexpected request: %s %v", req.Path, req.Body["model"])
+	}
+}
+
+func TestServer_RejectsWrongKey(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.RequireAPIKey("right")
+
+	client := mcp.NewClient(
+		mcp.WithProvider(mcp.ProviderCustom),
+		mcp.WithBaseURL(server.OpenAIBaseURL()),
+		mcp.WithAPIKey("wrong"),
+		mcp.WithLogger(mcp.NewNoopLogger()),
+	)
+
+	if _, err := client.CallWithMessages("sys", "user"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 error, got %v", err)
+	}
+}
+
+func TestServer_StreamingFormats(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Enqueue(ServerReply{Content: "a b c"}, ServerReply{Content: "x y"})
+
+	resp, err := http.Post(server.OpenAIBaseURL()+"/chat/completions", "application/json",
+		bytes.NewBufferString(`{"model":"m","stream":true}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	events := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "data: ") {
+			events++
+		}
+	}
+	resp.Body.Close()
+	// 3 content chunks + finish chunk + [DONE]
+	if events != 5 {
+		t.Errorf("expected 5 SSE events, got %d", events)
+	}
+
+	resp, err = http.Post(server.URL+"/api/chat", "application/json", bytes.NewBufferString(`{"model":"m"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	lines := 0
+	scanner = bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines++
+	}
+	resp.Body.Close()
+	// 2 content chunks + done line
+	if lines != 3 {
+		t.Errorf("expected 3 NDJSON lines, got %d", lines)
+	}
+}
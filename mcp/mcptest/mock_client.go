@@ -0,0 +1,237 @@
+// Package mcptest provides test doubles for code that depends on mcp.AIClient
+//
+// MockClient is a scriptable in-memory AIClient; Server is an httptest-based fake
+// provider speaking OpenAI-compatible and Ollama-native formats, so downstream code
+// can be tested without network access or real keys.
+package mcptest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nofx/mcp"
+)
+
+var (
+	// ErrNoResponse is returned when the script is exhausted and no default response is set
+	ErrNoResponse = errors.New("mcptest: no scripted response left")
+
+	_ mcp.AIClient = (*MockClient)(nil)
+)
+
+// Call one recorded call on MockClient
+type Call struct {
+	SystemPrompt string
+	UserPrompt   string
+	Request      *mcp.Request // Set for CallWithRequest calls
+	Time         time.Time
+}
+
+// Reply one scripted reply
+type Reply struct {
+	Content string
+	Err     error
+	Latency time.Duration // Artificial latency before replying
+}
+
+// MockClient scriptable mcp.AIClient implementation
+type MockClient struct {
+	mu sync.Mutex
+
+	script       []Reply
+	defaultReply *Reply
+	latency      time.Duration
+	handler      func(Call) (string, error)
+	calls        []Call
+	APIKey       string
+	BaseURL      string
+	Model        string
+	Timeout      time.Duration
+}
+
+// NewMockClient creates mock client replying with responses in order
+//
+// Usage example:
+//
+//	client := mcptest.NewMockClient(`{"action":"buy"}`, `{"action":"hold"}`)
+//	engine := NewEngine(client)
+func NewMockClient(responses ...string) *MockClient {
+	m := &MockClient{}
+	for _, r := range responses {
+		m.script = append(m.script, Reply{Content: r})
+	}
+	return m
+}
+
+// QueueResponse appends a successful reply to the script
+func (m *MockClient) QueueResponse(content string) *MockClient {
+	return m.Queue(Reply{Content: content})
+}
+
+// QueueError appends a failing reply to the script
+func (m *MockClient) QueueError(err error) *MockClient {
+	return m.Queue(Reply{Err: err})
+}
+
+// Queue appends a reply to the script
+func (m *MockClient) Queue(reply Reply) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.script = append(m.script, reply)
+	return m
+}
+
+// SetDefault sets reply used once the script is exhausted
+func (m *MockClient) SetDefault(reply Reply) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultReply = &reply
+	return m
+}
+
+// SetLatency adds artificial latency to every call
+func (m *MockClient) SetLatency(latency time.Duration) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = latency
+	return m
+}
+
+// SetHandler replaces the script with a custom function
+func (m *MockClient) SetHandler(handler func(Call) (string, error)) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handler = handler
+	return m
+}
+
+func (m *MockClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.APIKey = apiKey
+	m.BaseURL = customURL
+	m.Model = customModel
+}
+
+func (m *MockClient) SetTimeout(timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Timeout = timeout
+}
+
+func (m *MockClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	return m.do(Call{SystemPrompt: systemPrompt, UserPrompt: userPrompt, Time: time.Now()})
+}
+
+func (m *MockClient) CallWithRequest(req *mcp.Request) (string, error) {
+	call := Call{Request: req, Time: time.Now()}
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system":
+			call.SystemPrompt = msg.Content
+		case "user":
+			call.UserPrompt = msg.Content
+		}
+	}
+	return m.do(call)
+}
+
+func (m *MockClient) do(call Call) (string, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	handler := m.handler
+	latency := m.latency
+
+	var reply Reply
+	switch {
+	case handler != nil:
+	case len(m.script) > 0:
+		reply = m.script[0]
+		m.script = m.script[1:]
+	case m.defaultReply != nil:
+		reply = *m.defaultReply
+	default:
+		reply = Reply{Err: ErrNoResponse}
+	}
+	m.mu.Unlock()
+
+	if latency+reply.Latency > 0 {
+		time.Sleep(latency + reply.Latency)
+	}
+	if handler != nil {
+		return handler(call)
+	}
+	if reply.Err != nil {
+		return "", reply.Err
+	}
+	return reply.Content, nil
+}
+
+// ============================================================
+// Assertions
+// ============================================================
+
+// Calls returns all recorded calls
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call{}, m.calls...)
+}
+
+// CallCount returns number of recorded calls
+func (m *MockClient) CallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// LastCall returns the most recent call (zero Call if none)
+func (m *MockClient) LastCall() Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.calls) == 0 {
+		return Call{}
+	}
+	return m.calls[len(m.calls)-1]
+}
+
+// Reset clears recorded calls and the remaining script
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.script = nil
+}
+
+// AssertCallCount fails the test if the number of calls differs from expected
+func (m *MockClient) AssertCallCount(t testing.TB, expected int) {
+	t.Helper()
+	if got := m.CallCount(); got != expected {
+		t.Errorf("mcptest: expected %d calls, got %d", expected, got)
+	}
+}
+
+// AssertPromptContains fails the test if no call had a system or user prompt containing substr
+func (m *MockClient) AssertPromptContains(t testing.TB, substr string) {
+	t.Helper()
+	for _, call := range m.Calls() {
+		if strings.Contains(call.SystemPrompt, substr) || strings.Contains(call.UserPrompt, substr) {
+			return
+		}
+	}
+	t.Errorf("mcptest: no call prompt contains %q", substr)
+}
+
+// AssertScriptConsumed fails the test if scripted replies were left unused
+func (m *MockClient) AssertScriptConsumed(t testing.TB) {
+	t.Helper()
+	m.mu.Lock()
+	left := len(m.script)
+	m.mu.Unlock()
+	if left > 0 {
+		t.Errorf("mcptest: %d scripted replies were not consumed", left)
+	}
+}
@@ -0,0 +1,423 @@
+package mcptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerReply one scripted reply of the fake provider server
+type ServerReply struct {
+	Content      string        // Assistant text
+	StatusCode   int           // HTTP status (default 200)
+	Body         string        // Raw body overriding the generated one (for malformed/error payloads)
+	Delay        time.Duration // Delay before answering
+	FinishReason string        // Finish reason (default "stop")
+	ToolCalls    []ToolCall    // Tool calls returned instead of / alongside content
+}
+
+// ToolCall tool call returned by the fake server
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string // JSON-encoded arguments
+}
+
+// RecordedRequest request received by the fake server
+type RecordedRequest struct {
+	Method  string
+	Path    string
+	Headers http.Header
+	Body    map[string]any
+	RawBody []byte
+}
+
+// Server httptest-based fake provider
+//
+// Endpoints:
+//   - POST /v1/chat/completions (and /chat/completions): OpenAI-compatible, SSE when "stream": true
+//   - POST /v1/messages: Anthropic Messages API, SSE when "stream": true
+//   - POST /api/chat: Ollama native, NDJSON when "stream" is not false
+//   - GET  /v1/models (and /models): OpenAI-compatible model list
+//
+// Usage example:
+//
+//	server := mcptest.NewServer()
+//	defer server.Close()
+//	server.Enqueue(mcptest.ServerReply{Content: "hello"})
+//	client := mcp.NewClient(mcp.WithProvider(mcp.ProviderCustom), mcp.WithBaseURL(server.OpenAIBaseURL()), mcp.WithAPIKey("test"))
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	script       []ServerReply
+	defaultReply ServerReply
+	requests     []RecordedRequest
+	models       []string
+	apiKey       string
+}
+
+// NewServer starts fake provider server
+func NewServer() *Server {
+	s := &Server{
+		defaultReply: ServerReply{Content: "ok"},
+		models:       []string{"test-model"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", s.handleOpenAIChat)
+	mux.HandleFunc("/chat/completions", s.handleOpenAIChat)
+	mux.HandleFunc("/v1/messages", s.handleAnthropicMessages)
+	mux.HandleFunc("/api/chat", s.handleOllamaChat)
+	mux.HandleFunc("/v1/models", s.handleModels)
+	mux.HandleFunc("/models", s.handleModels)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// OpenAIBaseURL returns base URL for OpenAI-compatible clients
+func (s *Server) OpenAIBaseURL() string {
+	return s.URL + "/v1"
+}
+
+// Enqueue appends scripted replies
+func (s *Server) Enqueue(replies ...ServerReply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append(s.script, replies...)
+}
+
+// SetDefault sets reply used once the script is exhausted
+func (s *Server) SetDefault(reply ServerReply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaultReply = reply
+}
+
+// SetModels sets model list served by /models
+func (s *Server) SetModels(models ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.models = models
+}
+
+// RequireAPIKey makes server reject requests without "Authorization: Bearer <key>"
+// (or "x-api-key: <key>" for the Anthropic endpoint) with 401
+func (s *Server) RequireAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = key
+}
+
+// Requests returns all received requests
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest{}, s.requests...)
+}
+
+// LastRequest returns the most recent request (zero value if none)
+func (s *Server) LastRequest() RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return RecordedRequest{}
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// record records request and pops next reply; returns false if the request was rejected
+func (s *Server) record(w http.ResponseWriter, r *http.Request) (RecordedRequest, ServerReply, bool) {
+	raw, _ := io.ReadAll(r.Body)
+	recorded := RecordedRequest{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Headers: r.Header.Clone(),
+		RawBody: raw,
+	}
+	if len(raw) > 0 {
+		json.Unmarshal(raw, &recorded.Body)
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recorded)
+	apiKey := s.apiKey
+	reply := s.defaultReply
+	if r.Method == http.MethodPost && len(s.script) > 0 {
+		reply = s.script[0]
+		s.script = s.script[1:]
+	}
+	s.mu.Unlock()
+
+	if apiKey != "" && r.Header.Get("Authorization") != "Bearer "+apiKey && r.Header.Get("x-api-key") != apiKey {
+		http.Error(w, `{"error":{"message":"invalid api key","type":"authentication_error"}}`, http.StatusUnauthorized)
+		return recorded, reply, false
+	}
+
+	if reply.Delay > 0 {
+		select {
+		case <-time.After(reply.Delay):
+		case <-r.Context().Done():
+			return recorded, reply, false
+		}
+	}
+
+	if (reply.StatusCode != 0 && reply.StatusCode != http.StatusOK) || reply.Body != "" {
+		status := reply.StatusCode
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, reply.Body)
+		return recorded, reply, false
+	}
+
+	if reply.FinishReason == "" {
+		reply.FinishReason = "stop"
+		if len(reply.ToolCalls) > 0 {
+			reply.FinishReason = "tool_calls"
+		}
+	}
+	return recorded, reply, true
+}
+
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := s.record(w, r); !ok {
+		return
+	}
+
+	s.mu.Lock()
+	data := make([]map[string]any, 0, len(s.models))
+	for _, m := range s.models {
+		data = append(data, map[string]any{"id": m, "object": "model"})
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{"object": "list", "data": data})
+}
+
+func (s *Server) handleOpenAIChat(w http.ResponseWriter, r *http.Request) {
+	req, reply, ok := s.record(w, r)
+	if !ok {
+		return
+	}
+	model, _ := req.Body["model"].(string)
+
+	toolCalls := make([]map[string]any, 0, len(reply.ToolCalls))
+	for i, tc := range reply.ToolCalls {
+		toolCalls = append(toolCalls, map[string]any{
+			"index": i,
+			"id":    tc.ID,
+			"type":  "function",
+			"function": map[string]any{
+				"name":      tc.Name,
+				"arguments": tc.Arguments,
+			},
+		})
+	}
+
+	usage := map[string]any{
+		"prompt_tokens":     len(req.RawBody) / 4,
+		"completion_tokens": len(reply.Content) / 4,
+		"total_tokens":      len(req.RawBody)/4 + len(reply.Content)/4,
+	}
+
+	if stream, _ := req.Body["stream"].(bool); stream {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher, _ := w.(http.Flusher)
+		for _, chunk := range splitChunks(reply.Content) {
+			fmt.Fprintf(w, "data: %s\n\n", mustJSON(map[string]any{
+				"model":   model,
+				"choices": []map[string]any{{"index": 0, "delta": map[string]any{"content": chunk}}},
+			}))
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if len(toolCalls) > 0 {
+			fmt.Fprintf(w, "data: %s\n\n", mustJSON(map[string]any{
+				"model":   model,
+				"choices": []map[string]any{{"index": 0, "delta": map[string]any{"tool_calls": toolCalls}}},
+			}))
+		}
+		fmt.Fprintf(w, "data: %s\n\n", mustJSON(map[string]any{
+			"model":   model,
+			"choices": []map[string]any{{"index": 0, "delta": map[string]any{}, "finish_reason": reply.FinishReason}},
+			"usage":   usage,
+		}))
+		io.WriteString(w, "data: [DONE]\n\n")
+		return
+	}
+
+	message := map[string]any{"role": "assistant", "content": reply.Content}
+	if len(toolCalls) > 0 {
+		message["tool_calls"] = toolCalls
+	}
+	writeJSON(w, map[string]any{
+		"id":      "chatcmpl-mcptest",
+		"object":  "chat.completion",
+		"model":   model,
+		"choices": []map[string]any{{"index": 0, "message": message, "finish_reason": reply.FinishReason}},
+		"usage":   usage,
+	})
+}
+
+func (s *Server) handleOllamaChat(w http.ResponseWriter, r *http.Request) {
+	req, reply, ok := s.record(w, r)
+	if !ok {
+		return
+	}
+	model, _ := req.Body["model"].(string)
+
+	toolCalls := make([]map[string]any, 0, len(reply.ToolCalls))
+	for _, tc := range reply.ToolCalls {
+		var args map[string]any
+		json.Unmarshal([]byte(tc.Arguments), &args)
+		toolCalls = append(toolCalls, map[string]any{
+			"function": map[string]any{"name": tc.Name, "arguments": args},
+		})
+	}
+
+	doneReason := reply.FinishReason
+	if doneReason == "tool_calls" {
+		doneReason = "stop"
+	}
+
+	// Ollama streams by default unless "stream": false is sent
+	stream := true
+	if v, ok := req.Body["stream"].(bool); ok {
+		stream = v
+	}
+
+	if stream {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, chunk := range splitChunks(reply.Content) {
+			fmt.Fprintf(w, "%s\n", mustJSON(map[string]any{
+				"model":   model,
+				"message": map[string]any{"role": "assistant", "content": chunk},
+				"done":    false,
+			}))
+		}
+		final := map[string]any{
+			"model":             model,
+			"message":           map[string]any{"role": "assistant", "content": ""},
+			"done":              true,
+			"done_reason":       doneReason,
+			"prompt_eval_count": len(req.RawBody) / 4,
+			"eval_count":        len(reply.Content) / 4,
+		}
+		if len(toolCalls) > 0 {
+			final["message"].(map[string]any)["tool_calls"] = toolCalls
+		}
+		fmt.Fprintf(w, "%s\n", mustJSON(final))
+		return
+	}
+
+	message := map[string]any{"role": "assistant", "content": reply.Content}
+	if len(toolCalls) > 0 {
+		message["tool_calls"] = toolCalls
+	}
+	writeJSON(w, map[string]any{
+		"model":             model,
+		"message":           message,
+		"done":              true,
+		"done_reason":       doneReason,
+		"prompt_eval_count": len(req.RawBody) / 4,
+		"eval_count":        len(reply.Content) / 4,
+	})
+}
+
+func (s *Server) handleAnthropicMessages(w http.ResponseWriter, r *http.Request) {
+	req, reply, ok := s.record(w, r)
+	if !ok {
+		return
+	}
+	model, _ := req.Body["model"].(string)
+
+	stopReason := reply.FinishReason
+	switch stopReason {
+	case "stop":
+		stopReason = "end_turn"
+	case "length":
+		stopReason = "max_tokens"
+	case "tool_calls":
+		stopReason = "tool_use"
+	}
+
+	content := make([]map[string]any, 0, len(reply.ToolCalls)+1)
+	if reply.Content != "" {
+		content = append(content, map[string]any{"type": "text", "text": reply.Content})
+	}
+	for _, tc := range reply.ToolCalls {
+		var args map[string]any
+		json.Unmarshal([]byte(tc.Arguments), &args)
+		content = append(content, map[string]any{"type": "tool_use", "id": tc.ID, "name": tc.Name, "input": args})
+	}
+
+	usage := map[string]any{
+		"input_tokens":  len(req.RawBody) / 4,
+		"output_tokens": len(reply.Content) / 4,
+	}
+
+	if stream, _ := req.Body["stream"].(bool); stream {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeEvent := func(event string, data map[string]any) {
+			data["type"] = event
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, mustJSON(data))
+		}
+		writeEvent("message_start", map[string]any{"message": map[string]any{"model": model, "usage": usage}})
+		writeEvent("content_block_start", map[string]any{"index": 0, "content_block": map[string]any{"type": "text", "text": ""}})
+		for _, chunk := range splitChunks(reply.Content) {
+			writeEvent("content_block_delta", map[string]any{"index": 0, "delta": map[string]any{"type": "text_delta", "text": chunk}})
+		}
+		writeEvent("content_block_stop", map[string]any{"index": 0})
+		writeEvent("message_delta", map[string]any{"delta": map[string]any{"stop_reason": stopReason}, "usage": usage})
+		writeEvent("message_stop", map[string]any{})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"id":          "msg_mcptest",
+		"type":        "message",
+		"role":        "assistant",
+		"model":       model,
+		"content":     content,
+		"stop_reason": stopReason,
+		"usage":       usage,
+	})
+}
+
+// splitChunks splits content into word-sized stream chunks
+func splitChunks(content string) []string {
+	if content == "" {
+		return nil
+	}
+	words := strings.SplitAfter(content, " ")
+	chunks := make([]string, 0, len(words))
+	for _, w := range words {
+		if w != "" {
+			chunks = append(chunks, w)
+		}
+	}
+	return chunks
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(mustJSON(v))
+}
+
+func mustJSON(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,307 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory defaults
+const (
+	DefaultMemoryMaxFacts     = 100 // Facts kept per scope
+	DefaultMemoryRecall       = 5   // Facts injected per prompt
+	DefaultMemoryInjectTokens = 400 // Token cap of injected memories
+)
+
+// memoryExtractPrompt asks the extractor for durable facts of one turn
+const memoryExtractPrompt = `You maintain long-term memory about a user of a crypto trading assistant.
+From the conversation turn below, extract durable facts worth remembering in future sessions:
+preferences, risk limits, holdings, goals, constraints and corrections. Skip small talk,
+one-off questions and market data that will be stale tomorrow.
+
+Answer with JSON only: {"facts":[{"key":"snake_case_topic","fact":"one sentence"}]}
+Reuse the same key for a fact that updates an earlier one. Answer {"facts":[]} if nothing is durable.`
+
+// MemoryFact one durable fact, keyed by topic within a scope
+type MemoryFact struct {
+	Key       string
+	Text      string
+	Vector    []float64 // Embedding of Text (nil when no embedder is configured)
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ExpiresAt time.Time // Zero = never
+}
+
+// FactStore persists memory facts per scope (user, trader, session, ...)
+type FactStore interface {
+	PutFact(ctx context.Context, scope string, fact MemoryFact) error // Replaces a fact of the same key
+	Facts(ctx context.Context, scope string) ([]MemoryFact, error)
+	DeleteFact(ctx context.Context, scope, key string) error
+}
+
+// MemoryFactStore in-process FactStore (default)
+type MemoryFactStore struct {
+	mu     sync.RWMutex
+	scopes map[string]map[string]MemoryFact
+}
+
+// NewMemoryFactStore creates empty in-memory fact store
+func NewMemoryFactStore() *MemoryFactStore {
+	return &MemoryFactStore{scopes: make(map[string]map[string]MemoryFact)}
+}
+
+func (s *MemoryFactStore) PutFact(ctx context.Context, scope string, fact MemoryFact) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	facts, ok := s.scopes[scope]
+	if !ok {
+		facts = make(map[string]MemoryFact)
+		s.scopes[scope] = facts
+	}
+	facts[fact.Key] = fact
+	return nil
+}
+
+func (s *MemoryFactStore) Facts(ctx context.Context, scope string) ([]MemoryFact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	facts := make([]MemoryFact, 0, len(s.scopes[scope]))
+	for _, fact := range s.scopes[scope] {
+		facts = append(facts, fact)
+	}
+	return facts, nil
+}
+
+func (s *MemoryFactStore) DeleteFact(ctx context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.scopes[scope], key)
+	return nil
+}
+
+// MemoryConfig memory configuration
+type MemoryConfig struct {
+	Extractor     AIClient      // Cheap model extracting facts after each turn (required)
+	Embedder      Embedder      // Embeds facts and prompts for recall (nil = word overlap)
+	Store         FactStore     // Persistence (default: MemoryFactStore)
+	MaxFacts      int           // Facts kept per scope, least recently updated dropped first (default 100)
+	TTL           time.Duration // Lifetime of a fact since its last update (0 = forever)
+	Recall        int           // Max facts injected per prompt (default 5)
+	MinSimilarity float64       // Facts less similar to the prompt are not injected
+	InjectTokens  int           // Token cap of injected memories (default 400)
+}
+
+// Memory extracts durable facts from conversation turns and recalls relevant ones later
+//
+// After each turn Observe asks the extractor for durable facts (preferences, limits,
+// holdings) and stores them under a topic key, so an updated fact replaces the old
+// one. Inject adds the facts most similar to the new prompt to a request.
+type Memory struct {
+	config MemoryConfig
+	now    func() time.Time
+}
+
+// NewMemory creates memory
+//
+// Usage example:
+//   memory := mcp.NewMemory(mcp.MemoryConfig{Extractor: cheapClient, Embedder: client, TTL: 90 * 24 * time.Hour})
+//   req, _ = memory.Inject(ctx, traderID, req)
+//   answer, err := client.CallWithRequestContext(ctx, req)
+//   memory.Observe(ctx, traderID, userPrompt, answer)
+func NewMemory(config MemoryConfig) *Memory {
+	if config.Store == nil {
+		config.Store = NewMemoryFactStore()
+	}
+	if config.MaxFacts <= 0 {
+		config.MaxFacts = DefaultMemoryMaxFacts
+	}
+	if config.Recall <= 0 {
+		config.Recall = DefaultMemoryRecall
+	}
+	if config.InjectTokens <= 0 {
+		config.InjectTokens = DefaultMemoryInjectTokens
+	}
+	return &Memory{config: config, now: time.Now}
+}
+
+// Observe extracts durable facts from one conversation turn and stores them in scope
+func (m *Memory) Observe(ctx context.Context, scope, userPrompt, answer string) error {
+	req := &Request{Messages: []Message{
+		NewSystemMessage(memoryExtractPrompt),
+		NewUserMessage(fmt.Sprintf("User: %s\n\nAssistant: %s", userPrompt, answer)),
+	}}
+	text, err := callRequest(ctx, m.config.Extractor, req)
+	if err != nil {
+		return fmt.Errorf("memory extraction failed: %w", err)
+	}
+	var extracted struct {
+		Facts []struct {
+			Key  string `json:"key"`
+			Fact string `json:"fact"`
+		} `json:"facts"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(text)), &extracted); err != nil {
+		return fmt.Errorf("memory extraction returned invalid JSON: %w", err)
+	}
+
+	var facts []MemoryFact
+	for _, f := range extracted.Facts {
+		if key, text := strings.TrimSpace(f.Key), strings.TrimSpace(f.Fact); key != "" && text != "" {
+			facts = append(facts, MemoryFact{Key: key, Text: text})
+		}
+	}
+	if len(facts) == 0 {
+		return nil
+	}
+	if m.config.Embedder != nil {
+		texts := make([]string, len(facts))
+		for i, fact := range facts {
+			texts[i] = fact.Text
+		}
+		embeddings, err := m.config.Embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("memory embedding failed: %w", err)
+		}
+		for i := range facts {
+			if i < len(embeddings.Vectors) {
+				facts[i].Vector = embeddings.Vectors[i]
+			}
+		}
+	}
+
+	existing, err := m.live(ctx, scope)
+	if err != nil {
+		return err
+	}
+	created := make(map[string]time.Time, len(existing))
+	for _, fact := range existing {
+		created[fact.Key] = fact.CreatedAt
+	}
+	now := m.now()
+	for _, fact := range facts {
+		fact.CreatedAt, fact.UpdatedAt = now, now
+		if at, ok := created[fact.Key]; ok {
+			fact.CreatedAt = at
+		}
+		if m.config.TTL > 0 {
+			fact.ExpiresAt = now.Add(m.config.TTL)
+		}
+		if err := m.config.Store.PutFact(ctx, scope, fact); err != nil {
+			return fmt.Errorf("failed to store memory: %w", err)
+		}
+	}
+	return m.enforceCap(ctx, scope)
+}
+
+// Recall returns up to Recall facts of scope most relevant to query, most relevant first
+func (m *Memory) Recall(ctx context.Context, scope, query string) ([]MemoryFact, error) {
+	facts, err := m.live(ctx, scope)
+	if err != nil || len(facts) == 0 {
+		return nil, err
+	}
+
+	var queryVector []float64
+	if m.config.Embedder != nil {
+		embeddings, err := m.config.Embedder.Embed(ctx, []string{query})
+		if err != nil {
+			return nil, fmt.Errorf("memory embedding failed: %w", err)
+		}
+		if len(embeddings.Vectors) > 0 {
+			queryVector = embeddings.Vectors[0]
+		}
+	}
+
+	type scored struct {
+		fact  MemoryFact
+		score float64
+	}
+	var ranked []scored
+	for _, fact := range facts {
+		score := tokenOverlap(query, fact.Text)
+		if queryVector != nil && fact.Vector != nil {
+			score = cosineSimilarity(queryVector, fact.Vector)
+		}
+		if score >= m.config.MinSimilarity {
+			ranked = append(ranked, scored{fact, score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+
+	recalled := make([]MemoryFact, 0, min(len(ranked), m.config.Recall))
+	for _, r := range ranked[:min(len(ranked), m.config.Recall)] {
+		recalled = append(recalled, r.fact)
+	}
+	return recalled, nil
+}
+
+// Inject returns a copy of req with facts of scope relevant to its last user prompt
+// added as a system message after the existing system messages
+func (m *Memory) Inject(ctx context.Context, scope string, req *Request) (*Request, error) {
+	facts, err := m.Recall(ctx, scope, lastUserPrompt(req))
+	if err != nil || len(facts) == 0 {
+		return req, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Known facts about this user from earlier sessions:")
+	budget := m.config.InjectTokens - EstimateTokens(sb.String())
+	for _, fact := range facts {
+		line := "\n- " + fact.Text
+		if budget -= EstimateTokens(line); budget < 0 {
+			break
+		}
+		sb.WriteString(line)
+	}
+
+	injected := cloneRequest(req)
+	at := 0
+	for at < len(injected.Messages) && injected.Messages[at].Role == "system" {
+		at++
+	}
+	injected.Messages = append(injected.Messages[:at], append([]Message{NewSystemMessage(sb.String())}, injected.Messages[at:]...)...)
+	return injected, nil
+}
+
+// Forget deletes fact key of scope
+func (m *Memory) Forget(ctx context.Context, scope, key string) error {
+	return m.config.Store.DeleteFact(ctx, scope, key)
+}
+
+// live returns unexpired facts of scope, deleting expired ones
+func (m *Memory) live(ctx context.Context, scope string) ([]MemoryFact, error) {
+	facts, err := m.config.Store.Facts(ctx, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load memories: %w", err)
+	}
+	now := m.now()
+	live := facts[:0]
+	for _, fact := range facts {
+		if !fact.ExpiresAt.IsZero() && now.After(fact.ExpiresAt) {
+			if err := m.config.Store.DeleteFact(ctx, scope, fact.Key); err != nil {
+				return nil, fmt.Errorf("failed to delete expired memory: %w", err)
+			}
+			continue
+		}
+		live = append(live, fact)
+	}
+	return live, nil
+}
+
+// enforceCap drops the least recently updated facts of scope beyond MaxFacts
+func (m *Memory) enforceCap(ctx context.Context, scope string) error {
+	facts, err := m.live(ctx, scope)
+	if err != nil || len(facts) <= m.config.MaxFacts {
+		return err
+	}
+	sort.Slice(facts, func(i, j int) bool { return facts[i].UpdatedAt.After(facts[j].UpdatedAt) })
+	for _, fact := range facts[m.config.MaxFacts:] {
+		if err := m.config.Store.DeleteFact(ctx, scope, fact.Key); err != nil {
+			return fmt.Errorf("failed to delete memory: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemory_ObserveRecallInject(t *testing.T) {
+	extractor := &stubClient{answer: `{"facts":[{"key":"max_leverage","fact":"Never use more than 3x leverage"},{"key":"holdings","fact":"Holds 2 ETH in cold storage"}]}`}
+	embedder := vectorEmbedder{
+		"Never use more than 3x leverage": {1, 0},
+		"Holds 2 ETH in cold storage":     {0, 1},
+		"Should I open a 10x long?":       {0.9, 0.1},
+	}
+	memory := NewMemory(MemoryConfig{Extractor: extractor, Embedder: embedder, Recall: 1})
+	ctx := context.Background()
+
+	if err := memory.Observe(ctx, "trader-1", "Keep leverage at 3x max, I hold 2 ETH offline", "Noted."); err != nil {
+		t.Fatalf("observe failed: %v", err)
+	}
+	if !strings.Contains(extractor.prompts[0], "Keep leverage at 3x max") {
+		t.Errorf("turn not sent to extractor: %q", extractor.prompts[0])
+	}
+
+	req := &Request{Messages: []Message{NewSystemMessage("You are a trading assistant."), NewUserMessage("Should I open a 10x long?")}}
+	injected, err := memory.Inject(ctx, "trader-1", req)
+	if err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+	if len(injected.Messages) != 3 || injected.Messages[1].Role != "system" {
+		t.Fatalf("unexpected messages %+v", injected.Messages)
+	}
+	if memo := injected.Messages[1].Content; !strings.Contains(memo, "3x leverage") || strings.Contains(memo, "ETH") {
+		t.Errorf("expected only the leverage fact, got %q", memo)
+	}
+	if len(req.Messages) != 2 {
+		t.Error("original request was modified")
+	}
+	if other, _ := memory.Inject(ctx, "trader-2", req); other != req {
+		t.Error("other scopes must not see trader-1 memories")
+	}
+}
+
+func TestMemory_UpdateCapAndTTL(t *testing.T) {
+	extractor := &stubClient{}
+	store := NewMemoryFactStore()
+	memory := NewMemory(MemoryConfig{Extractor: extractor, Store: store, MaxFacts: 2, TTL: time.Hour})
+	now := time.Now()
+	memory.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	observe := func(answer string) {
+		extractor.answer = answer
+		if err := memory.Observe(ctx, "s", "prompt", "answer"); err != nil {
+			t.Fatalf("observe failed: %v", err)
+		}
+		now = now.Add(time.Minute)
+	}
+	observe(`{"facts":[{"key":"risk","fact":"Risk 1% per trade"}]}`)
+	observe(`{"facts":[{"key":"pairs","fact":"Trades BTC and SOL"}]}`)
+	observe(`{"facts":[{"key":"risk","fact":"Risk 0.5% per trade"}]}`)
+	observe(`{"facts":[{"key":"style","fact":"Swing trader"}]}`)
+
+	facts, _ := store.Facts(ctx, "s")
+	texts := map[string]string{}
+	for _, fact := range facts {
+		texts[fact.Key] = fact.Text
+	}
+	if len(facts) != 2 || texts["risk"] != "Risk 0.5% per trade" || texts["style"] == "" {
+		t.Errorf("expected updated risk and newest fact kept, got %v", texts)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if recalled, _ := memory.Recall(ctx, "s", "risk per trade"); len(recalled) != 0 {
+		t.Errorf("expired facts recalled: %+v", recalled)
+	}
+	if facts, _ := store.Facts(ctx, "s"); len(facts) != 0 {
+		t.Errorf("expired facts not deleted: %+v", facts)
+	}
+}
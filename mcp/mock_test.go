@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -305,6 +306,6 @@ func (m *MockClientHooks) buildRequest(url string, jsonData []byte) (*http.Reque
 	return req, nil
 }
 
-func (m *MockClientHooks) call(systemPrompt, userPrompt string) (string, error) {
-	return "mocked call result", nil
+func (m *MockClientHooks) call(ctx context.Context, systemPrompt, userPrompt string) (*Response, error) {
+	return &Response{Text: "mocked call result"}, nil
 }
@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Model capabilities used by SelectModel
+const (
+	CapabilityTools     = "tools"
+	CapabilityJSONMode  = "json_mode"
+	CapabilityVision    = "vision"
+	CapabilityReasoning = "reasoning"
+	CapabilityStreaming = "streaming"
+)
+
+// LatencyClass coarse response speed of a model (lower is faster)
+type LatencyClass int
+
+const (
+	LatencyFast     LatencyClass = 1
+	LatencyStandard LatencyClass = 2
+	LatencySlow     LatencyClass = 3 // e.g. reasoning models
+)
+
+// ErrNoModelMatches is returned by SelectModel when no registered model meets the requirements
+var ErrNoModelMatches = errors.New("no model matches requirements")
+
+// ModelInfo registry entry of a model
+//
+// Prices come from RegisterModelPrice.
+type ModelInfo struct {
+	Name          string
+	Provider      string
+	ContextWindow int             // Tokens
+	Capabilities  []string        // Capability* constants
+	Latency       LatencyClass    // 0 = LatencyStandard
+	Endpoints     []ModelEndpoint // Regional endpoints serving the model (see WithDataResidency)
+}
+
+// ModelRequirements constraints of SelectModel (zero fields are unconstrained)
+type ModelRequirements struct {
+	Capabilities     []string     // All must be supported
+	MinContextWindow int          // Tokens
+	MaxPricePer1K    float64      // USD per 1k tokens, average of input and output price
+	MaxLatency       LatencyClass // Slowest acceptable class
+	Providers        []string     // Providers that are configured (nil = any)
+	Regions          []string     // Data-residency regions, one of which must have an endpoint (nil = any)
+
+	// Override picks from candidates ranked best first (nil = take the first)
+	Override func(candidates []ModelInfo) ModelInfo
+}
+
+var (
+	modelMu       sync.RWMutex
+	modelRegistry = map[string]ModelInfo{}
+)
+
+// RegisterModel adds model to the registry used by SelectModel
+//
+// Usage example:
+//   mcp.RegisterModel(mcp.ModelInfo{Name: "deepseek-chat", Provider: "deepseek", ContextWindow: 64000,
+//       Capabilities: []string{mcp.CapabilityTools, mcp.CapabilityJSONMode}, Latency: mcp.LatencyFast})
+//   mcp.RegisterModelPrice("deepseek-chat", mcp.ModelPrice{InputPerMillion: 0.27, OutputPerMillion: 1.10})
+func RegisterModel(info ModelInfo) {
+	modelMu.Lock()
+	defer modelMu.Unlock()
+	modelRegistry[info.Name] = info
+}
+
+// LookupModel returns registry entry of a model
+func LookupModel(name string) (ModelInfo, bool) {
+	modelMu.RLock()
+	defer modelMu.RUnlock()
+	info, ok := modelRegistry[name]
+	return info, ok
+}
+
+// Supports reports whether model has capability
+func (m ModelInfo) Supports(capability string) bool {
+	return slices.Contains(m.Capabilities, capability)
+}
+
+// PricePer1K returns average of input and output USD price per 1k tokens
+func (m ModelInfo) PricePer1K() (float64, bool) {
+	price, ok := LookupModelPrice(m.Name)
+	if !ok {
+		return 0, false
+	}
+	return (price.InputPerMillion + price.OutputPerMillion) / 2 / 1000, true
+}
+
+func (m ModelInfo) latency() LatencyClass {
+	if m.Latency == 0 {
+		return LatencyStandard
+	}
+	return m.Latency
+}
+
+// SelectModel returns the cheapest registered model meeting requirements
+//
+// Ties are broken by latency class, then larger context window, then name, so the
+// result is deterministic. Models without a registered price rank last and are
+// excluded when MaxPricePer1K is set.
+//
+// Usage example:
+//   model, err := mcp.SelectModel(mcp.ModelRequirements{
+//       Capabilities:     []string{mcp.CapabilityTools},
+//       MinContextWindow: 32000,
+//   })
+//   client, _ := mcp.NewProviderClient(model.Provider, mcp.WithModel(model.Name))
+func SelectModel(requirements ModelRequirements) (ModelInfo, error) {
+	candidates := matchingModels(requirements)
+	if len(candidates) == 0 {
+		return ModelInfo{}, fmt.Errorf("%w: %+v", ErrNoModelMatches, requirements)
+	}
+	if requirements.Override != nil {
+		return requirements.Override(candidates), nil
+	}
+	return candidates[0], nil
+}
+
+// matchingModels returns models meeting requirements, ranked best first
+func matchingModels(requirements ModelRequirements) []ModelInfo {
+	modelMu.RLock()
+	defer modelMu.RUnlock()
+
+	type ranked struct {
+		info   ModelInfo
+		price  float64
+		priced bool
+	}
+	var candidates []ranked
+	for _, info := range modelRegistry {
+		if !requirements.matches(info) {
+			continue
+		}
+		price, priced := info.PricePer1K()
+		if requirements.MaxPricePer1K > 0 && (!priced || price > requirements.MaxPricePer1K) {
+			continue
+		}
+		candidates = append(candidates, ranked{info: info, price: price, priced: priced})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case a.priced != b.priced:
+			return a.priced
+		case a.price != b.price:
+			return a.price < b.price
+		case a.info.latency() != b.info.latency():
+			return a.info.latency() < b.info.latency()
+		case a.info.ContextWindow != b.info.ContextWindow:
+			return a.info.ContextWindow > b.info.ContextWindow
+		default:
+			return a.info.Name < b.info.Name
+		}
+	})
+	models := make([]ModelInfo, len(candidates))
+	for i, candidate := range candidates {
+		models[i] = candidate.info
+	}
+	return models
+}
+
+// matches checks every constraint except price
+func (r ModelRequirements) matches(info ModelInfo) bool {
+	if len(r.Providers) > 0 && !slices.Contains(r.Providers, info.Provider) {
+		return false
+	}
+	if len(r.Regions) > 0 && len(info.endpointsIn(r.Regions)) == 0 {
+		return false
+	}
+	if info.ContextWindow < r.MinContextWindow {
+		return false
+	}
+	if r.MaxLatency > 0 && info.latency() > r.MaxLatency {
+		return false
+	}
+	for _, capability := range r.Capabilities {
+		if !info.Supports(capability) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+func registerSelectTestModels() {
+	RegisterModel(ModelInfo{Name: "sel-cheap", Provider: "p1", ContextWindow: 8000, Capabilities: []string{CapabilityTools}, Latency: LatencyFast})
+	RegisterModel(ModelInfo{Name: "sel-mid-a", Provider: "p1", ContextWindow: 32000, Capabilities: []string{CapabilityTools}})
+	RegisterModel(ModelInfo{Name: "sel-mid-b", Provider: "p2", ContextWindow: 128000, Capabilities: []string{CapabilityTools, CapabilityVision}})
+	RegisterModel(ModelInfo{Name: "sel-premium", Provider: "p2", ContextWindow: 200000, Capabilities: []string{CapabilityTools, CapabilityReasoning}, Latency: LatencySlow})
+	RegisterModel(ModelInfo{Name: "sel-unpriced", Provider: "p1", ContextWindow: 1000000, Capabilities: []string{CapabilityTools}})
+	RegisterModelPrice("sel-cheap", ModelPrice{InputPerMillion: 0.1, OutputPerMillion: 0.1})
+	RegisterModelPrice("sel-mid-a", ModelPrice{InputPerMillion: 1, OutputPerMillion: 3})
+	RegisterModelPrice("sel-mid-b", ModelPrice{InputPerMillion: 2, OutputPerMillion: 2})
+	RegisterModelPrice("sel-premium", ModelPrice{InputPerMillion: 10, OutputPerMillion: 30})
+}
+
+func TestSelectModel(t *testing.T) {
+	registerSelectTestModels()
+	tests := []struct {
+		name string
+		req  ModelRequirements
+		want string
+	}{
+		{"cheapest with tools and 32k", ModelRequirements{Capabilities: []string{CapabilityTools}, MinContextWindow: 32000, Providers: []string{"p1", "p2"}}, "sel-mid-b"},
+		{"capability", ModelRequirements{Capabilities: []string{CapabilityReasoning}}, "sel-premium"},
+		{"provider filter", ModelRequirements{MinContextWindow: 32000, Providers: []string{"p1"}}, "sel-mid-a"},
+		{"unpriced ranks last", ModelRequirements{MinContextWindow: 300000}, "sel-unpriced"},
+		{"latency", ModelRequirements{MinContextWindow: 150000, MaxLatency: LatencyStandard, Providers: []string{"p2"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectModel(tt.req)
+			if tt.want == "" {
+				if !errors.Is(err, ErrNoModelMatches) {
+					t.Fatalf("expected ErrNoModelMatches, got %v (%s)", err, got.Name)
+				}
+				return
+			}
+			if err != nil || got.Name != tt.want {
+				t.Fatalf("expected %s, got %s (%v)", tt.want, got.Name, err)
+			}
+		})
+	}
+}
+
+func TestSelectModel_TieBreakAndOverride(t *testing.T) {
+	registerSelectTestModels()
+	// sel-mid-a and sel-mid-b both average $2/M: larger context wins
+	got, err := SelectModel(ModelRequirements{MinContextWindow: 32000, MaxPricePer1K: 0.002})
+	if err != nil || got.Name != "sel-mid-b" {
+		t.Fatalf("expected sel-mid-b, got %s (%v)", got.Name, err)
+	}
+
+	var seen []string
+	got, _ = SelectModel(ModelRequirements{
+		MinContextWindow: 32000,
+		Providers:        []string{"p1", "p2"},
+		Override: func(candidates []ModelInfo) ModelInfo {
+			for _, c := range candidates {
+				seen = append(seen, c.Name)
+			}
+			return candidates[len(candidates)-1]
+		},
+	})
+	want := []string{"sel-mid-b", "sel-mid-a", "sel-premium", "sel-unpriced"}
+	if len(seen) != len(want) || got.Name != "sel-unpriced" {
+		t.Fatalf("unexpected override candidates %v (picked %s)", seen, got.Name)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected ranking %v, got %v", want, seen)
+		}
+	}
+}
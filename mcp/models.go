@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ListModels returns model IDs served by the provider (GET {BaseURL}/models), sorted
+//
+// Usage example:
+//   models, err := client.ListModels(ctx)
+func (client *Client) ListModels(ctx context.Context) ([]string, error) {
+	body, err := client.fetchModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// OpenAI-compatible {"data":[{"id":...}]}, Gemini {"models":[{"name":"models/..."}]}
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse model list: %w", err)
+	}
+	models := make([]string, 0, len(list.Data)+len(list.Models))
+	for _, m := range list.Data {
+		models = append(models, strings.TrimPrefix(m.ID, "models/"))
+	}
+	for _, m := range list.Models {
+		models = append(models, strings.TrimPrefix(m.Name, "models/"))
+	}
+	sort.Strings(models)
+	return models, nil
+}
+
+// fetchModels returns the raw model list of the provider (GET {BaseURL}/models)
+func (client *Client) fetchModels(ctx context.Context) ([]byte, error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.endpointURL(EndpointModels), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fail to build request: %w", err)
+	}
+	client.hooks.setAuthHeader(req.Header)
+	client.setClientHeaders(req.Header)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(limitBody(resp.Body, client.config.MaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.newAPIError(resp.StatusCode, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ModerationBackend service used by Moderate
+type ModerationBackend string
+
+const (
+	ModerationOpenAI ModerationBackend = "openai" // OpenAI /moderations
+	ModerationAzure  ModerationBackend = "azure"  // Azure AI Content Safety text:analyze
+	ModerationLocal  ModerationBackend = "local"  // Built-in keyword lists (no network)
+
+	DefaultModerationModel      = "omni-moderation-latest"
+	defaultAzureSafetyVersion   = "2024-09-01"
+	defaultAzureSeverityTrigger = 2
+)
+
+// ModerationConfig moderation service configuration
+type ModerationConfig struct {
+	Backend           ModerationBackend
+	BaseURL           string   // OpenAI: API base URL ("" = client BaseURL); Azure: Content Safety endpoint
+	APIKey            string   // "" = client API key (OpenAI backend)
+	Model             string   // OpenAI moderation model ("" = DefaultModerationModel)
+	SeverityThreshold int      // Azure: min severity flagged (0 = 2)
+	Keywords          []string // Extra terms flagged by the local fallback
+}
+
+// ModerationResult moderation verdict
+type ModerationResult struct {
+	Flagged    bool
+	Categories []string           // Flagged categories, sorted
+	Scores     map[string]float64 // Category scores (OpenAI) or severities (Azure)
+	Backend    ModerationBackend  // Backend that produced the verdict
+	Fallback   bool               // Remote backend failed and the local check was used
+}
+
+// WithModeration configures the moderation service used by Moderate
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithModeration(mcp.ModerationConfig{
+//       Backend: mcp.ModerationAzure,
+//       BaseURL: "https://my-safety.cognitiveservices.azure.com",
+//       APIKey:  os.Getenv("AZURE_CONTENT_SAFETY_KEY"),
+//   }), mcp.WithOutputModeration(true))
+func WithModeration(config ModerationConfig) ClientOption {
+	return func(c *Config) {
+		c.Moderation = &config
+	}
+}
+
+// WithOutputModeration moderates every response before it is returned
+//
+// Flagged responses fail with a *GuardrailError (errors.Is ErrGuardrailBlocked) from
+// the "moderation" guardrail, which runs after the configured guardrails.
+func WithOutputModeration(enabled bool) ClientOption {
+	return func(c *Config) {
+		c.ModerateOutputs = enabled
+	}
+}
+
+// Moderate classifies text with the configured moderation service
+//
+// Without WithModeration, OpenAI clients use OpenAI moderation and other providers the
+// local keyword check. When the remote service fails, the local check is used and the
+// result is marked Fallback.
+//
+// Usage example:
+//   verdict, err := client.Moderate(ctx, userSuppliedStrategyNotes)
+//   if err == nil && verdict.Flagged {
+//       return fmt.Errorf("notes rejected: %v", verdict.Categories)
+//   }
+func (client *Client) Moderate(ctx context.Context, text string) (*ModerationResult, error) {
+	config := client.moderationConfig()
+
+	var (
+		result *ModerationResult
+		err    error
+	)
+	switch config.Backend {
+	case ModerationOpenAI:
+		result, err = client.moderateOpenAI(ctx, config, text)
+	case ModerationAzure:
+		result, err = client.moderateAzure(ctx, config, text)
+	case ModerationLocal:
+		return moderateLocal(config, text), nil
+	default:
+		return nil, fmt.Errorf("unknown moderation backend %q", config.Backend)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		client.logger.Warnf("⚠️  [MCP] %s moderation failed, using local fallback: %v", config.Backend, err)
+		result = moderateLocal(config, text)
+		result.Fallback = true
+	}
+	return result, nil
+}
+
+// moderationConfig returns effective moderation configuration
+func (client *Client) moderationConfig() ModerationConfig {
+	var config ModerationConfig
+	if client.config.Moderation != nil {
+		config = *client.config.Moderation
+	}
+	if config.Backend == "" {
+		config.Backend = ModerationLocal
+		if client.Provider == ProviderOpenAI {
+			config.Backend = ModerationOpenAI
+		}
+	}
+	if config.Backend == ModerationOpenAI {
+		if config.BaseURL == "" {
+			config.BaseURL = client.BaseURL
+		}
+		if config.APIKey == "" {
+			config.APIKey = client.APIKey
+		}
+		if config.Model == "" {
+			config.Model = DefaultModerationModel
+		}
+	}
+	if config.SeverityThreshold <= 0 {
+		config.SeverityThreshold = defaultAzureSeverityTrigger
+	}
+	return config
+}
+
+// moderateOpenAI calls OpenAI /moderations
+func (client *Client) moderateOpenAI(ctx context.Context, config ModerationConfig, text string) (*ModerationResult, error) {
+	var response struct {
+		Results []struct {
+			Flagged        bool               `json:"flagged"`
+			Categories     map[string]bool    `json:"categories"`
+			CategoryScores map[string]float64 `json:"category_scores"`
+		} `json:"results"`
+	}
+	headers := http.Header{"Authorization": {"Bearer " + config.APIKey}}
+	url := strings.TrimSuffix(config.BaseURL, "/") + "/moderations"
+	if err := client.moderationAPI(ctx, url, headers, map[string]any{"model": config.Model, "input": text}, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) == 0 {
+		return nil, fmt.Errorf("moderation API returned no results")
+	}
+
+	first := response.Results[0]
+	result := &ModerationResult{Flagged: first.Flagged, Scores: first.CategoryScores, Backend: ModerationOpenAI}
+	for category, flagged := range first.Categories {
+		if flagged {
+			result.Categories = append(result.Categories, category)
+		}
+	}
+	sort.Strings(result.Categories)
+	return result, nil
+}
+
+// moderateAzure calls Azure AI Content Safety text:analyze
+func (client *Client) moderateAzure(ctx context.Context, config ModerationConfig, text string) (*ModerationResult, error) {
+	var response struct {
+		CategoriesAnalysis []struct {
+			Category string `json:"category"`
+			Severity int    `json:"severity"`
+		} `json:"categoriesAnalysis"`
+	}
+	headers := http.Header{"Ocp-Apim-Subscription-Key": {config.APIKey}}
+	url := fmt.Sprintf("%s/contentsafety/text:analyze?api-version=%s", strings.TrimSuffix(config.BaseURL, "/"), defaultAzureSafetyVersion)
+	if err := client.moderationAPI(ctx, url, headers, map[string]any{"text": text}, &response); err != nil {
+		return nil, err
+	}
+
+	result := &ModerationResult{Scores: make(map[string]float64), Backend: ModerationAzure}
+	for _, analysis := range response.CategoriesAnalysis {
+		category := strings.ToLower(analysis.Category)
+		result.Scores[category] = float64(analysis.Severity)
+		if analysis.Severity >= config.SeverityThreshold {
+			result.Flagged = true
+			result.Categories = append(result.Categories, category)
+		}
+	}
+	sort.Strings(result.Categories)
+	return result, nil
+}
+
+// moderationAPI posts JSON payload to a moderation endpoint and decodes the response
+func (client *Client) moderationAPI(ctx context.Context, url string, headers http.Header, payload any, out any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize moderation request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("fail to build request: %w", err)
+	}
+	req.Header = headers
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(limitBody(resp.Body, client.config.MaxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("moderation API returned error (status %d): %s", resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse moderation response: %w", err)
+	}
+	return nil
+}
+
+// localModerationTerms keyword lists of the local fallback by category
+var localModerationTerms = map[string][]string{
+	"violence":  {"kill yourself", "bomb making", "how to make a bomb", "mass shooting"},
+	"self-harm": {"suicide method", "how to kill myself", "self harm"},
+	"hate":      {"subhuman", "ethnic cleansing"},
+	"illicit":   {"money laundering scheme", "pump and dump", "wash trading", "insider trading tip"},
+}
+
+// moderateLocal flags text containing known harmful terms (whole words, case-insensitive)
+func moderateLocal(config ModerationConfig, text string) *ModerationResult {
+	result := &ModerationResult{Scores: make(map[string]float64), Backend: ModerationLocal}
+	lower := strings.ToLower(text)
+
+	terms := localModerationTerms
+	if len(config.Keywords) > 0 {
+		terms = make(map[string][]string, len(localModerationTerms)+1)
+		for category, words := range localModerationTerms {
+			terms[category] = words
+		}
+		terms["custom"] = config.Keywords
+	}
+
+	for category, words := range terms {
+		for _, word := range words {
+			pattern := `\b` + regexp.QuoteMeta(strings.ToLower(word)) + `\b`
+			if matched, _ := regexp.MatchString(pattern, lower); matched {
+				result.Flagged = true
+				result.Categories = append(result.Categories, category)
+				result.Scores[category] = 1
+				break
+			}
+		}
+	}
+	sort.Strings(result.Categories)
+	return result
+}
+
+// outputModerationGuardrail blocks responses flagged by Client.Moderate
+type outputModerationGuardrail struct {
+	client *Client
+}
+
+func (g *outputModerationGuardrail) Name() string { return "moderation" }
+
+func (g *outputModerationGuardrail) Check(ctx context.Context, stage GuardrailStage, text string) (GuardrailResult, error) {
+	verdict, err := g.client.Moderate(ctx, text)
+	if err != nil {
+		return GuardrailResult{}, err
+	}
+	if !verdict.Flagged {
+		return GuardrailResult{}, nil
+	}
+	return GuardrailResult{Violated: true, Reason: "flagged: " + strings.Join(verdict.Categories, ", ")}, nil
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestModerate_OpenAI(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/moderations" || r.Header.Get("Authorization") != "Bearer sk-test-key" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"results":[{"flagged":true,"categories":{"violence":true,"hate":false,"harassment":true},"category_scores":{"violence":0.91,"hate":0.01,"harassment":0.7}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClientWithOptions(WithBaseURL(server.URL), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger())).(*OpenAIClient)
+
+	result, err := client.Moderate(context.Background(), "some text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Flagged || result.Backend != ModerationOpenAI || len(result.Categories) != 2 || result.Categories[0] != "harassment" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if got["model"] != DefaultModerationModel || got["input"] != "some text" {
+		t.Errorf("unexpected request body %v", got)
+	}
+}
+
+func TestModerate_Azure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/contentsafety/text:analyze" || r.Header.Get("Ocp-Apim-Subscription-Key") != "azure-key" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"categoriesAnalysis":[{"category":"Hate","severity":0},{"category":"Violence","severity":4}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithModeration(ModerationConfig{Backend: ModerationAzure, BaseURL: server.URL, APIKey: "azure-key"}),
+	).(*Client)
+
+	result, err := client.Moderate(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Flagged || len(result.Categories) != 1 || result.Categories[0] != "violence" || result.Scores["hate"] != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestModerate_LocalFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithModeration(ModerationConfig{Backend: ModerationOpenAI, BaseURL: server.URL, Keywords: []string{"rug pull"}}),
+	).(*Client)
+
+	result, err := client.Moderate(context.Background(), "Coordinate a Rug Pull tonight")
+	if err != nil {
+		t.Fatalf("fallback should not fail: %v", err)
+	}
+	if !result.Fallback || result.Backend != ModerationLocal || !result.Flagged || result.Categories[0] != "custom" {
+		t.Errorf("expected flagged local fallback, got %+v", result)
+	}
+
+	local := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger())).(*Client)
+	if result, _ := local.Moderate(context.Background(), "BTC looks bullish"); result.Flagged || result.Backend != ModerationLocal {
+		t.Errorf("non-OpenAI clients should default to local check, got %+v", result)
+	}
+}
+
+func TestOutputModeration(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"Classic pump and dump: buy then shill"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithOutputModeration(true),
+	)
+
+	_, err := client.CallWithMessages("system", "strategy?")
+	var guardErr *GuardrailError
+	if !errors.As(err, &guardErr) || guardErr.Guardrail != "moderation" || guardErr.Stage != GuardOutput {
+		t.Errorf("flagged output should be blocked, got %v", err)
+	}
+}
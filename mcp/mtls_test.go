@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert generates a self-signed client certificate and writes it as PEM files
+func writeClientCert(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "nofx-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ = x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return certFile, keyFile, cert
+}
+
+func TestClientCertificate_MutualTLS(t *testing.T) {
+	certFile, keyFile, clientCert := writeClientCert(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"hello ` + r.TLS.PeerCertificates[0].Subject.CommonName + `"}}]}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	newClient := func(opts ...ClientOption) AIClient {
+		return NewClient(append([]ClientOption{
+			WithProvider(ProviderCustom),
+			WithBaseURL(server.URL),
+			WithAPIKey("sk-test-key"),
+			WithLogger(NewNoopLogger()),
+			WithMaxRetries(1),
+			WithTLSConfig(&tls.Config{RootCAs: rootCAs}),
+		}, opts...)...)
+	}
+
+	result, err := newClient(WithClientCertificate(certFile, keyFile)).CallWithMessages("system", "user")
+	if err != nil || result != "hello nofx-client" {
+		t.Fatalf("mutual TLS call failed: %q, %v", result, err)
+	}
+
+	if _, err := newClient().CallWithMessages("system", "user"); err == nil {
+		t.Error("server requiring client certificate should reject client without one")
+	}
+	if _, err := newClient(WithClientCertificate(certFile, "/missing.key")).CallWithMessages("system", "user"); err == nil {
+		t.Error("unreadable key should fail calls")
+	}
+
+	if shared := sharedTransport(DefaultTransportConfig()); shared.TLSClientConfig != nil && len(shared.TLSClientConfig.Certificates) > 0 {
+		t.Error("shared transport must not receive the client certificate")
+	}
+}
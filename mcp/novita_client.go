@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"strings"
+)
+
+const (
+	ProviderNovita       = "novita"
+	DefaultNovitaBaseURL = "https://api.novita.ai/v3/openai"
+	DefaultNovitaModel   = "deepseek/deepseek-v3-0324"
+)
+
+// novitaModels Novita names of models commonly configured by their vendor names
+//
+// Novita names models "<org>/<model>", all lowercase.
+var novitaModels = map[string]string{
+	"deepseek-chat":     "deepseek/deepseek-v3-0324",
+	"deepseek-reasoner": "deepseek/deepseek-r1-0528",
+	"qwen3-235b-a22b":   "qwen/qwen3-235b-a22b-fp8",
+	"qwen3-32b":         "qwen/qwen3-32b-fp8",
+	"llama-3.3-70b":     "meta-llama/llama-3.3-70b-instruct",
+	"llama-3.1-8b":      "meta-llama/llama-3.1-8b-instruct",
+	"kimi-k2":           "moonshotai/kimi-k2-instruct",
+}
+
+// novitaPrices list prices in USD (override with RegisterModelPrice)
+var novitaPrices = map[string]ModelPrice{
+	"deepseek/deepseek-v3-0324":         {InputPerMillion: 0.28, OutputPerMillion: 1.14},
+	"deepseek/deepseek-r1-0528":         {InputPerMillion: 0.70, OutputPerMillion: 2.50},
+	"qwen/qwen3-235b-a22b-fp8":          {InputPerMillion: 0.20, OutputPerMillion: 0.80},
+	"qwen/qwen3-32b-fp8":                {InputPerMillion: 0.10, OutputPerMillion: 0.45},
+	"meta-llama/llama-3.3-70b-instruct": {InputPerMillion: 0.13, OutputPerMillion: 0.39},
+	"meta-llama/llama-3.1-8b-instruct":  {InputPerMillion: 0.02, OutputPerMillion: 0.05},
+	"moonshotai/kimi-k2-instruct":       {InputPerMillion: 0.57, OutputPerMillion: 2.30},
+}
+
+type NovitaClient struct {
+	*Client
+}
+
+// NewNovitaClient creates Novita AI client (backward compatible)
+func NewNovitaClient() AIClient {
+	return NewNovitaClientWithOptions()
+}
+
+// NewNovitaClientWithOptions creates Novita client (supports options pattern)
+//
+// Novita is an OpenAI-compatible aggregator of open-weight models. Vendor model names
+// such as "deepseek-chat" are translated to Novita's lowercase "<org>/<model>" names,
+// and the cost tracker knows the prices of common models.
+//
+// Usage example:
+//   client := mcp.NewNovitaClientWithOptions(mcp.WithAPIKey(key), mcp.WithModel("llama-3.3-70b"))
+func NewNovitaClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Novita preset options
+	novitaOpts := []ClientOption{
+		WithProvider(ProviderNovita),
+		WithModel(DefaultNovitaModel),
+		WithBaseURL(DefaultNovitaBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(novitaOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+	baseClient.Model = novitaModel(baseClient.Model)
+
+	// 4. Create Novita client
+	novitaClient := &NovitaClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to NovitaClient (implement dynamic dispatch)
+	baseClient.hooks = novitaClient
+
+	return novitaClient
+}
+
+// novitaModel returns the Novita name of model
+func novitaModel(model string) string {
+	if name, ok := novitaModels[model]; ok {
+		return name
+	}
+	return strings.ToLower(model)
+}
+
+func (c *NovitaClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] Novita API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Novita using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Novita using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = novitaModel(customModel)
+		c.logger.Infof("🔧 [MCP] Novita using custom Model: %s", c.Model)
+	} else {
+		c.logger.Infof("🔧 [MCP] Novita using default Model: %s", c.Model)
+	}
+}
+
+// marshalRequestBody translates vendor model names of per-request overrides
+func (c *NovitaClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	if model, ok := requestBody["model"].(string); ok {
+		requestBody["model"] = novitaModel(model)
+	}
+	return c.Client.marshalRequestBody(requestBody)
+}
@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"testing"
+)
+
+// ============================================================
+// Test NovitaClient
+// ============================================================
+
+func TestNovitaClient_ModelNames(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Hold BTC.")
+	client := NewNovitaClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithModel("Meta-Llama/Llama-3.3-70B-Instruct"),
+		WithLogger(NewNoopLogger()),
+	).(*NovitaClient)
+
+	if client.Model != "meta-llama/llama-3.3-70b-instruct" {
+		t.Errorf("expected lowercase Novita model name, got %s", client.Model)
+	}
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if url := mockHTTP.GetLastRequest().URL.String(); url != DefaultNovitaBaseURL+"/chat/completions" {
+		t.Errorf("unexpected URL %s", url)
+	}
+	if _, ok := LookupModelPrice(novitaModel("deepseek-chat")); !ok {
+		t.Error("expected built-in price of Novita DeepSeek V3")
+	}
+}
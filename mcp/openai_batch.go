@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Batch job statuses reported by the OpenAI Batch API
+const (
+	BatchStatusValidating = "validating"
+	BatchStatusInProgress = "in_progress"
+	BatchStatusFinalizing = "finalizing"
+	BatchStatusCompleted  = "completed"
+	BatchStatusFailed     = "failed"
+	BatchStatusExpired    = "expired"
+	BatchStatusCancelled  = "cancelled"
+)
+
+var (
+	// ErrBatchNotSupported is returned when the provider has no asynchronous batch endpoint
+	ErrBatchNotSupported = errors.New("provider does not support batch API")
+	// ErrBatchNotReady is returned when fetching results of a batch that hasn't completed
+	ErrBatchNotReady = errors.New("batch job not completed yet")
+)
+
+// BatchItem one request of an offline batch job
+type BatchItem struct {
+	CustomID string // Caller-chosen ID used to match results (defaults to "req-<index>")
+	Request  *Request
+}
+
+// BatchJob status of an offline batch job
+type BatchJob struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	InputFileID   string `json:"input_file_id"`
+	OutputFileID  string `json:"output_file_id"`
+	ErrorFileID   string `json:"error_file_id"`
+	CreatedAt     int64  `json:"created_at"`
+	CompletedAt   int64  `json:"completed_at"`
+	RequestCounts struct {
+		Total     int `json:"total"`
+		Completed int `json:"completed"`
+		Failed    int `json:"failed"`
+	} `json:"request_counts"`
+}
+
+// Done returns true if the job reached a terminal status
+func (j *BatchJob) Done() bool {
+	switch j.Status {
+	case BatchStatusCompleted, BatchStatusFailed, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// BatchItemResult result of one batch item
+type BatchItemResult struct {
+	CustomID string
+	Text     string
+	Err      error
+}
+
+// supportsBatchAPI reports whether the provider exposes the OpenAI Batch API
+func (client *Client) supportsBatchAPI() bool {
+	return client.Provider == ProviderOpenAI
+}
+
+// SubmitBatch uploads requests and creates an asynchronous batch job (completion window 24h)
+//
+// Batch jobs are billed at roughly half the price of synchronous calls, which suits
+// nightly bulk analysis. Use PollBatch/WaitBatch to track the job and
+// FetchBatchResults to download the answers.
+//
+// Usage example:
+//   jobID, err := client.SubmitBatch(ctx, []mcp.BatchItem{
+//       {CustomID: "BTC", Request: btcRequest},
+//       {CustomID: "ETH", Request: ethRequest},
+//   })
+func (client *Client) SubmitBatch(ctx context.Context, items []BatchItem) (string, error) {
+	if !client.supportsBatchAPI() {
+		return "", fmt.Errorf("%w: %s", ErrBatchNotSupported, client.Provider)
+	}
+	if client.APIKey == "" {
+		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	}
+	if len(items) == 0 {
+		return "", fmt.Errorf("batch must contain at least one request")
+	}
+
+	// Step 1: Build JSONL input file
+	var input bytes.Buffer
+	for i, item := range items {
+		customID := item.CustomID
+		if customID == "" {
+			customID = fmt.Sprintf("req-%d", i)
+		}
+		if item.Request == nil {
+			return "", fmt.Errorf("batch request %s has no Request", customID)
+		}
+		req := *item.Request
+		if req.Model == "" {
+			req.Model = client.Model
+		}
+		// Same body as a synchronous call of this client
+		body := client.buildRequestBodyFromRequest(&req)
+		client.decorateBody(ctx, body)
+		line, err := json.Marshal(map[string]any{
+			"custom_id": customID,
+			"method":    http.MethodPost,
+			"url":       "/v1/chat/completions",
+			"body":      body,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize batch request %s: %w", customID, err)
+		}
+		input.Write(line)
+		input.WriteByte('\n')
+	}
+
+	// Step 2: Upload input file
+	fileID, err := client.uploadBatchFile(ctx, input.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	// Step 3: Create batch job
+	payload, _ := json.Marshal(map[string]any{
+		"input_file_id":     fileID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	})
+	var job BatchJob
+	if err := client.batchAPI(ctx, http.MethodPost, client.endpointURL(EndpointBatches), "application/json", bytes.NewReader(payload), &job); err != nil {
+		return "", fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	client.logger.Infof("📦 [%s] Batch job submitted: %s (%d requests)", client.String(), job.ID, len(items))
+	return job.ID, nil
+}
+
+// PollBatch returns current status of a batch job
+func (client *Client) PollBatch(ctx context.Context, jobID string) (*BatchJob, error) {
+	if !client.supportsBatchAPI() {
+		return nil, fmt.Errorf("%w: %s", ErrBatchNotSupported, client.Provider)
+	}
+
+	var job BatchJob
+	if err := client.batchAPI(ctx, http.MethodGet, client.endpointURL(EndpointBatches)+"/"+jobID, "", nil, &job); err != nil {
+		return nil, fmt.Errorf("failed to poll batch %s: %w", jobID, err)
+	}
+	return &job, nil
+}
+
+// WaitBatch polls a batch job every interval until it reaches a terminal status
+func (client *Client) WaitBatch(ctx context.Context, jobID string, interval time.Duration) (*BatchJob, error) {
+	for {
+		job, err := client.PollBatch(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.Done() {
+			return job, nil
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return job, ctx.Err()
+		}
+	}
+}
+
+// FetchBatchResults downloads and parses results of a completed batch job
+//
+// Per-item failures are reported in BatchItemResult.Err rather than failing the call.
+func (client *Client) FetchBatchResults(ctx context.Context, jobID string) ([]BatchItemResult, error) {
+	job, err := client.PollBatch(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != BatchStatusCompleted {
+		return nil, fmt.Errorf("%w: %s is %s", ErrBatchNotReady, jobID, job.Status)
+	}
+
+	var results []BatchItemResult
+	for _, fileID := range []string{job.OutputFileID, job.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		var content bytes.Buffer
+		if err := client.batchAPI(ctx, http.MethodGet, client.endpointURL(EndpointFiles)+"/"+fileID+"/content", "", nil, &content); err != nil {
+			return nil, fmt.Errorf("failed to download batch file %s: %w", fileID, err)
+		}
+		fileResults, err := client.parseBatchOutput(&content)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fileResults...)
+	}
+	return results, nil
+}
+
+// parseBatchOutput parses a JSONL batch output/error file
+func (client *Client) parseBatchOutput(r io.Reader) ([]BatchItemResult, error) {
+	var results []BatchItemResult
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry struct {
+			CustomID string `json:"custom_id"`
+			Response *struct {
+				StatusCode int             `json:"status_code"`
+				Body       json.RawMessage `json:"body"`
+			} `json:"response"`
+			Error *struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse batch output line: %w", err)
+		}
+
+		result := BatchItemResult{CustomID: entry.CustomID}
+		switch {
+		case entry.Error != nil:
+			result.Err = fmt.Errorf("batch item failed: %s - %s", entry.Error.Code, entry.Error.Message)
+		case entry.Response == nil:
+			result.Err = fmt.Errorf("batch item has no response")
+		case entry.Response.StatusCode != http.StatusOK:
+			result.Err = client.newAPIError(entry.Response.StatusCode, entry.Response.Body)
+		default:
+			result.Text, result.Err = client.hooks.parseMCPResponse(entry.Response.Body)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch output: %w", err)
+	}
+	return results, nil
+}
+
+// uploadBatchFile uploads JSONL input with purpose=batch and returns the file ID
+func (client *Client) uploadBatchFile(ctx context.Context, data []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("purpose", "batch")
+	part, err := writer.CreateFormFile("file", "batch.jsonl")
+	if err != nil {
+		return "", fmt.Errorf("failed to build batch upload: %w", err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := client.batchAPI(ctx, http.MethodPost, client.endpointURL(EndpointFiles), writer.FormDataContentType(), &body, &file); err != nil {
+		return "", fmt.Errorf("failed to upload batch file: %w", err)
+	}
+	return file.ID, nil
+}
+
+// batchAPI performs one batch API request, decoding JSON into out (or copying raw bytes if out is *bytes.Buffer)
+func (client *Client) batchAPI(ctx context.Context, method, url, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("fail to build request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client.hooks.setAuthHeader(req.Header)
+	client.setClientHeaders(req.Header)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return client.newAPIError(resp.StatusCode, respBody)
+	}
+
+	if buf, ok := out.(*bytes.Buffer); ok {
+		buf.Write(respBody)
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
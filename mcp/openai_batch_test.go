@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test OpenAI Batch API
+// ============================================================
+
+// newFakeBatchServer serves /files, /batches and file content like the OpenAI Batch API
+func newFakeBatchServer(t *testing.T) *httptest.Server {
+	var input string
+	polls := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("purpose") != "batch" {
+			t.Errorf("expected purpose=batch, got %q", r.FormValue("purpose"))
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("missing file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		input = string(data)
+		w.Write([]byte(`{"id":"file-in"}`))
+	})
+	mux.HandleFunc("/batches", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["input_file_id"] != "file-in" || body["completion_window"] != "24h" {
+			t.Errorf("unexpected batch body %v", body)
+		}
+		w.Write([]byte(`{"id":"batch_1","status":"validating"}`))
+	})
+	mux.HandleFunc("/batches/batch_1", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 2 {
+			w.Write([]byte(`{"id":"batch_1","status":"in_progress"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"batch_1","status":"completed","output_file_id":"file-out","request_counts":{"total":2,"completed":1,"failed":1}}`))
+	})
+	mux.HandleFunc("/files/file-out/content", func(w http.ResponseWriter, r *http.Request) {
+		// Echo one success and one failure per submitted line
+		lines := strings.Split(strings.TrimSpace(input), "\n")
+		var first map[string]any
+		json.Unmarshal([]byte(lines[0]), &first)
+		w.Write([]byte(`{"custom_id":"` + first["custom_id"].(string) + `","response":{"status_code":200,"body":{"choices":[{"message":{"content":"bullish"}}]}}}` + "\n"))
+		w.Write([]byte(`{"custom_id":"ETH","error":{"code":"server_error","message":"boom"}}` + "\n"))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestBatchAPI_SubmitPollFetch(t *testing.T) {
+	server := newFakeBatchServer(t)
+	defer server.Close()
+
+	client := NewOpenAIClientWithOptions(
+		WithBaseURL(server.URL),
+		WithAPIKey("test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*OpenAIClient)
+
+	ctx := context.Background()
+	jobID, err := client.SubmitBatch(ctx, []BatchItem{
+		{CustomID: "BTC", Request: NewRequestBuilder().WithUserPrompt("BTC?").MustBuild()},
+		{CustomID: "ETH", Request: NewRequestBuilder().WithUserPrompt("ETH?").MustBuild()},
+	})
+	if err != nil {
+		t.Fatalf("submit should not error: %v", err)
+	}
+	if jobID != "batch_1" {
+		t.Errorf("expected batch_1, got %s", jobID)
+	}
+
+	if _, err := client.FetchBatchResults(ctx, jobID); !errors.Is(err, ErrBatchNotReady) {
+		t.Errorf("expected ErrBatchNotReady, got %v", err)
+	}
+
+	job, err := client.WaitBatch(ctx, jobID, time.Millisecond)
+	if err != nil || job.Status != BatchStatusCompleted {
+		t.Fatalf("expected completed job, got %+v / %v", job, err)
+	}
+
+	results, err := client.FetchBatchResults(ctx, jobID)
+	if err != nil {
+		t.Fatalf("fetch should not error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].CustomID != "BTC" || results[0].Text != "bullish" || results[0].Err != nil {
+		t.Errorf("unexpected first result %+v", results[0])
+	}
+	if results[1].CustomID != "ETH" || results[1].Err == nil {
+		t.Errorf("second result should carry item error, got %+v", results[1])
+	}
+}
+
+func TestBatchAPI_NotSupported(t *testing.T) {
+	client := NewDeepSeekClientWithOptions(WithAPIKey("test-key"), WithLogger(NewNoopLogger())).(*DeepSeekClient)
+
+	if _, err := client.SubmitBatch(context.Background(), nil); !errors.Is(err, ErrBatchNotSupported) {
+		t.Errorf("expected ErrBatchNotSupported, got %v", err)
+	}
+}
+
+func TestBatchAPI_DecoratesBodies(t *testing.T) {
+	var input string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files", func(w http.ResponseWriter, r *http.Request) {
+		file, _, _ := r.FormFile("file")
+		data, _ := io.ReadAll(file)
+		input = string(data)
+		w.Write([]byte(`{"id":"file-in"}`))
+	})
+	mux.HandleFunc("/batches", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"batch_1","status":"validating"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewOpenAIClientWithOptions(
+		WithBaseURL(server.URL),
+		WithAPIKey("test-key"),
+		WithLogger(NewNoopLogger()),
+		WithSeed(42),
+		WithExtraBody(map[string]any{"service_tier": "flex"}),
+	).(*OpenAIClient)
+
+	ctx := ContextWithTenant(context.Background(), "desk-a")
+	if _, err := client.SubmitBatch(ctx, []BatchItem{{CustomID: "BTC"}}); err == nil {
+		t.Error("expected error for item without Request")
+	}
+
+	req := NewRequestBuilder().WithUserPrompt("BTC?").MustBuild()
+	if _, err := client.SubmitBatch(ctx, []BatchItem{{CustomID: "BTC", Request: req}}); err != nil {
+		t.Fatalf("submit should not error: %v", err)
+	}
+	var line struct {
+		Body map[string]any `json:"body"`
+	}
+	json.Unmarshal([]byte(input), &line)
+	if line.Body["seed"] != float64(42) || line.Body["user"] != "desk-a" || line.Body["service_tier"] != "flex" {
+		t.Errorf("batch body should be decorated like a synchronous call, got %v", line.Body)
+	}
+	if req.Model != "" {
+		t.Errorf("caller's request should not be modified, model = %q", req.Model)
+	}
+}
@@ -52,7 +52,7 @@ func (c *OpenAIClient) SetAPIKey(apiKey string, customURL string, customModel st
 		c.logger.Infof("🔧 [MCP] OpenAI API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
 	}
 	if customURL != "" {
-		c.BaseURL = customURL
+		c.setBaseURL(customURL)
 		c.logger.Infof("🔧 [MCP] OpenAI using custom BaseURL: %s", customURL)
 	} else {
 		c.logger.Infof("🔧 [MCP] OpenAI using default BaseURL: %s", c.BaseURL)
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -103,10 +104,26 @@ func WithAPIKey(apiKey string) ClientOption {
 	}
 }
 
-// WithBaseURL sets base URL
+// WithZeroAuth allows calls without API key, for local servers that need none
+//
+// When no key is set, requests are sent without an Authorization header instead of
+// "Bearer " with an empty token. A key set later is still sent.
+func WithZeroAuth() ClientOption {
+	return func(c *Config) {
+		c.ZeroAuth = true
+	}
+}
+
+// WithBaseURL sets base URL (normalized, see NormalizeBaseURL)
+//
+// An invalid URL is reported by NewProviderClient and Validate, and fails every call.
+// A trailing "#" enables WithUseFullURL, like SetAPIKey.
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Config) {
-		c.BaseURL = baseURL
+		if full, ok := strings.CutSuffix(strings.TrimSpace(baseURL), "#"); ok {
+			baseURL, c.UseFullURL = full, true
+		}
+		c.BaseURL, c.baseURLErr = normalizedBaseURL(baseURL, c.UseFullURL)
 	}
 }
 
@@ -128,6 +145,10 @@ func WithProvider(provider string) ClientOption {
 func WithUseFullURL(useFullURL bool) ClientOption {
 	return func(c *Config) {
 		c.UseFullURL = useFullURL
+		if c.BaseURL != "" {
+			// Full endpoint URLs may carry a query, so validate again in the new mode
+			c.BaseURL, c.baseURLErr = normalizedBaseURL(c.BaseURL, useFullURL)
+		}
 	}
 }
 
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// languageMinLetters text shorter than this (in letters) is not classified
+const languageMinLetters = 20
+
+// languageNames names of the languages WithOutputLanguage understands, by ISO 639-1 code
+var languageNames = map[string]string{
+	"en": "English", "zh": "Chinese", "ja": "Japanese", "ko": "Korean", "ru": "Russian",
+	"ar": "Arabic", "th": "Thai", "es": "Spanish", "fr": "French", "de": "German",
+	"pt": "Portuguese", "it": "Italian",
+}
+
+// languageStopwords frequent words telling Latin-script languages apart
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "is", "of", "to", "in", "that", "it", "for", "with", "on", "this", "be", "are"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "las", "por", "una", "con", "para", "es", "del"},
+	"fr": {"le", "la", "les", "de", "et", "est", "des", "une", "que", "pour", "dans", "pas", "du", "sur"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "auf", "sich", "für"},
+	"pt": {"o", "a", "de", "que", "e", "do", "da", "em", "um", "uma", "para", "com", "não", "os"},
+	"it": {"il", "di", "che", "e", "la", "per", "un", "non", "sono", "della", "con", "del", "gli", "una"},
+}
+
+// codeBlockPattern fenced code blocks, ignored by language detection
+var codeBlockPattern = regexp.MustCompile("(?s)```.*?```")
+
+// languageAttemptKey marks contexts of attempts made by the output language check
+type languageAttemptKey struct{}
+
+// WithOutputLanguage makes responses come back in language (ISO 639-1 code, e.g. "en")
+//
+// A directive naming the language is appended to the user prompt and the answer's
+// language is checked with DetectLanguage. On a mismatch the call is re-prompted once;
+// if the second answer is still off it is returned with a warning in Response.Warnings.
+// Models behind Ollama Cloud in particular tend to answer in the prompt's language.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithOutputLanguage("en"))
+func WithOutputLanguage(language string) ClientOption {
+	return func(c *Config) {
+		c.OutputLanguage = strings.ToLower(language)
+	}
+}
+
+// DetectLanguage returns ISO 639-1 code of the language text is written in ("" if unsure)
+//
+// Lightweight: scripts identify CJK, Korean, Cyrillic, Arabic and Thai text; Latin
+// script text is told apart by stopwords (en, es, fr, de, pt, it).
+func DetectLanguage(text string) string {
+	text = codeBlockPattern.ReplaceAllString(text, " ")
+	var letters, han, kana, hangul, cyrillic, arabic, thai, latin int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Thai, r):
+			thai++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if letters < languageMinLetters {
+		return ""
+	}
+
+	// CJK characters carry a word each, so a smaller share already dominates the text
+	switch {
+	case kana*10 >= letters:
+		return "ja"
+	case han*5 >= letters:
+		return "zh"
+	case hangul*5 >= letters:
+		return "ko"
+	case cyrillic*2 >= letters:
+		return "ru"
+	case arabic*2 >= letters:
+		return "ar"
+	case thai*2 >= letters:
+		return "th"
+	case latin*2 < letters:
+		return ""
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	best, bestHits, secondHits := "", 0, 0
+	for _, language := range []string{"en", "es", "fr", "de", "pt", "it"} {
+		hits := 0
+		for _, word := range words {
+			for _, stopword := range languageStopwords[language] {
+				if word == stopword {
+					hits++
+					break
+				}
+			}
+		}
+		switch {
+		case hits > bestHits:
+			best, bestHits, secondHits = language, hits, bestHits
+		case hits > secondHits:
+			secondHits = hits
+		}
+	}
+	if bestHits < 2 || bestHits == secondHits {
+		return ""
+	}
+	return best
+}
+
+// enforcingLanguage reports whether the output language of a call with ctx should be checked
+func (client *Client) enforcingLanguage(ctx context.Context) bool {
+	return client.config.OutputLanguage != "" && ctx.Value(languageAttemptKey{}) == nil
+}
+
+// enforceLanguage runs call with a language directive, re-prompting once on a mismatch
+func (client *Client) enforceLanguage(ctx context.Context, userPrompt string, call func(ctx context.Context, userPrompt string) (*Response, error)) (*Response, error) {
+	ctx = context.WithValue(ctx, languageAttemptKey{}, true)
+	want := client.config.OutputLanguage
+	name := languageName(want)
+
+	response, err := call(ctx, fmt.Sprintf("%s\n\nRespond only in %s, regardless of the language of the input.", userPrompt, name))
+	if err != nil {
+		return nil, err
+	}
+	got := DetectLanguage(response.Text)
+	if got == "" || got == want {
+		return response, nil
+	}
+
+	client.logger.Warnf("⚠️  [%s] Response is in %s instead of %s, re-prompting", client.String(), languageName(got), name)
+	retry, err := call(ctx, fmt.Sprintf("%s\n\nIMPORTANT: your answer MUST be written in %s. Do not answer in %s.", userPrompt, name, languageName(got)))
+	if err != nil {
+		return nil, err
+	}
+	if got = DetectLanguage(retry.Text); got != "" && got != want {
+		retry.Warnings = append(retry.Warnings, fmt.Sprintf("output language %s, expected %s", got, want))
+	}
+	return retry, nil
+}
+
+// languageName English name of language code (the code itself if unknown)
+func languageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
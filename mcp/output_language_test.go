@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The market is bullish and the trend of BTC is up for this week.", "en"},
+		{"比特币价格突破关键阻力位，建议继续持有多头仓位并设置止损。", "zh"},
+		{"ビットコインの価格は上昇しています。ロングを維持してください。", "ja"},
+		{"비트코인 가격이 상승하고 있습니다. 롱 포지션을 유지하세요.", "ko"},
+		{"Рынок растёт, рекомендуется удерживать длинную позицию по биткоину.", "ru"},
+		{"El mercado está alcista y la tendencia de los precios es positiva para la semana.", "es"},
+		{"Der Markt ist bullisch und die Tendenz ist nicht negativ für den Bitcoin.", "de"},
+		{"ok", ""},
+		{"```json\n{\"action\":\"open_long\",\"symbol\":\"BTCUSDT\"}\n```", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectLanguage(tt.text); got != tt.want {
+			t.Errorf("DetectLanguage(%q): expected %q, got %q", tt.text, tt.want, got)
+		}
+	}
+}
+
+func TestOutputLanguage_Reprompt(t *testing.T) {
+	var prompts []string
+	stubborn := false
+	mock := NewMockHTTPClient()
+	mock.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		prompts = append(prompts, string(body))
+		answer := "比特币价格突破关键阻力位，建议继续持有多头仓位并设置止损。"
+		if strings.Contains(string(body), "MUST be written in English") && !stubborn {
+			answer = "The trend of BTC is up and it is safe to hold the long position."
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"choices":[{"message":{"content":"` + answer + `"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":20}}`)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	client := NewClient(WithHTTPClient(mock.ToHTTPClient()), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()),
+		WithOutputLanguage("en")).(*Client)
+
+	response, err := client.Call(context.Background(), NewRequestBuilder().WithUserPrompt("分析比特币").MustBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if DetectLanguage(response.Text) != "en" || len(response.Warnings) != 0 {
+		t.Errorf("expected the re-prompted English answer, got %q (%v)", response.Text, response.Warnings)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[0], "Respond only in English") {
+		t.Errorf("expected directive then one re-prompt, got %d requests", len(prompts))
+	}
+
+	// Still the wrong language after the re-prompt: answer kept, warning attached
+	prompts, stubborn = nil, true
+	response, err = client.CallMessages(context.Background(), "system", "分析比特币")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 2 || len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "output language zh") {
+		t.Errorf("expected mismatch warning after %d requests, got %v", len(prompts), response.Warnings)
+	}
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultPartialMinChars answer characters a failed call must have received to return them
+const DefaultPartialMinChars = 200
+
+// PartialResponseError call failed after substantial output had been received
+//
+// Partial holds the answer as far as it arrived (text, reasoning and completed tool
+// calls), so callers can use it, discard it or resume from it instead of losing a
+// long generation. The cause stays reachable: errors.Is(err, ErrStreamInterrupted)
+// and errors.Is(err, ErrIdleTimeout) keep working.
+//
+// Usage example:
+//   response, err := client.CallWithRequest(request)
+//   var partialErr *mcp.PartialResponseError
+//   if errors.As(err, &partialErr) {
+//       log.Printf("using %d characters received before: %v", len(partialErr.Partial.Text), partialErr.Err)
+//       response = partialErr.Partial
+//   }
+type PartialResponseError struct {
+	Partial *Response // Output received before the failure (PII restored)
+	Err     error     // Cause of the failure
+}
+
+func (e *PartialResponseError) Error() string {
+	return fmt.Sprintf("%v (partial response of %d characters received)", e.Err, len(e.Partial.Text))
+}
+
+func (e *PartialResponseError) Unwrap() error {
+	return e.Err
+}
+
+// WithPartialMinChars sets how much answer text a failed call must have received to return it
+//
+// Failures after at least minChars characters return *PartialResponseError; a negative
+// value always returns the plain error.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithPartialMinChars(1000))
+func WithPartialMinChars(minChars int) ClientOption {
+	return func(c *Config) {
+		c.PartialMinChars = minChars
+	}
+}
+
+// salvagePartial wraps err in *PartialResponseError when partial carries enough answer text
+func (client *Client) salvagePartial(err error, partial *Response) error {
+	minChars := client.config.PartialMinChars
+	if minChars == 0 {
+		minChars = DefaultPartialMinChars
+	}
+	if err == nil || partial == nil || minChars < 0 || len(partial.Text) < minChars {
+		return err
+	}
+	var salvaged *PartialResponseError
+	if errors.As(err, &salvaged) {
+		return err
+	}
+	client.logger.Warnf("⚠️  [%s] Call failed after %d characters, returning partial response: %v", client.String(), len(partial.Text), err)
+	return &PartialResponseError{Partial: partial, Err: err}
+}
+
+// salvageInterrupted returns the output of an interrupted stream in *PartialResponseError
+func (client *Client) salvageInterrupted(err error) error {
+	var interrupted *StreamInterruptedError
+	if !errors.As(err, &interrupted) {
+		return err
+	}
+	return client.salvagePartial(err, interrupted.Partial)
+}
+
+// partialBodyResponse decodes the answer of a non-streamed response body cut off while reading
+func (client *Client) partialBodyResponse(body []byte) *Response {
+	decoded, ok := parsePartialJSON(string(body))
+	if !ok {
+		return nil
+	}
+	object, _ := decoded.(map[string]any)
+	choices, _ := object["choices"].([]any)
+	if len(choices) == 0 {
+		return nil
+	}
+	choice, _ := choices[0].(map[string]any)
+	message, _ := choice["message"].(map[string]any)
+	text, _ := message["content"].(string)
+	reasoning, _ := message["reasoning_content"].(string)
+	model, _ := object["model"].(string)
+	if model == "" {
+		model = client.Model
+	}
+	return &Response{Text: text, Reasoning: reasoning, Model: model, Provider: client.Provider}
+}
+
+// restorePartial puts redacted sensitive data back into the output of a *PartialResponseError
+func (v *piiVault) restorePartial(err error) error {
+	var salvaged *PartialResponseError
+	if errors.As(err, &salvaged) {
+		salvaged.Partial.Text = v.restore(salvaged.Partial.Text)
+		salvaged.Partial.Reasoning = v.restore(salvaged.Partial.Reasoning)
+		salvaged.Partial.ToolCalls = v.restoreToolCalls(salvaged.Partial.ToolCalls)
+	}
+	return err
+}
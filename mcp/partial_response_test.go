@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// ============================================================
+// Test partial response salvage
+// ============================================================
+
+// brokenBodyClient answers with body, then fails reading with a connection reset
+func brokenBodyClient(body string) *http.Client {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		broken := io.MultiReader(strings.NewReader(body), iotest.ErrReader(errors.New("connection reset by peer")))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(broken), Header: make(http.Header)}, nil
+	}
+	return mockHTTP.ToHTTPClient()
+}
+
+func TestPartialResponse_InterruptedStream(t *testing.T) {
+	client := NewClient(
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPartialMinChars(10),
+		WithHTTPClient(brokenBodyClient(sseLines(
+			`{"choices":[{"delta":{"content":"BTC broke out of "}}]}`,
+			`{"choices":[{"delta":{"content":"its range on volume"}}]}`,
+		))),
+	).(*Client)
+
+	_, _, err := streamText(t, client)
+	var partialErr *PartialResponseError
+	if !errors.As(err, &partialErr) || !errors.Is(err, ErrStreamInterrupted) {
+		t.Fatalf("expected PartialResponseError wrapping the interruption, got %v", err)
+	}
+	if partialErr.Partial.Text != "BTC broke out of its range on volume" {
+		t.Errorf("unexpected partial text %q", partialErr.Partial.Text)
+	}
+}
+
+func TestPartialResponse_TruncatedBody(t *testing.T) {
+	newClient := func(minChars int) *Client {
+		return NewClient(
+			WithAPIKey("sk-test-key"),
+			WithLogger(NewNoopLogger()),
+			WithMaxRetries(1),
+			WithPartialMinChars(minChars),
+			WithHTTPClient(brokenBodyClient(`{"model":"deepseek-chat","choices":[{"message":{"role":"assistant","content":"Funding is negative, so sh`)),
+		).(*Client)
+	}
+
+	_, err := newClient(10).CallWithMessages("sys", "outlook?")
+	var partialErr *PartialResponseError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("expected PartialResponseError, got %v", err)
+	}
+	if partialErr.Partial.Text != "Funding is negative, so sh" || partialErr.Partial.Model != "deepseek-chat" {
+		t.Errorf("unexpected partial response %+v", partialErr.Partial)
+	}
+
+	if _, err := newClient(1000).CallWithMessages("sys", "outlook?"); err == nil || errors.As(err, &partialErr) {
+		t.Errorf("short output must fail with the plain error, got %v", err)
+	}
+	if _, err := newClient(-1).CallWithMessages("sys", "outlook?"); err == nil || errors.As(err, &partialErr) {
+		t.Errorf("negative minimum must disable salvage, got %v", err)
+	}
+}
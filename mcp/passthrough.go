@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"net/url"
+)
+
+// WithExtraBody merges provider-specific parameters into every request body
+//
+// Keys override the fields built by the client; nested objects are merged key by key,
+// so {"options": {"num_ctx": 8192}} keeps the other "options" fields. A nil value
+// removes the field. Use for knobs without a first-class option yet.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithExtraBody(map[string]any{
+//       "parallel_tool_calls": false,
+//   }))
+func WithExtraBody(body map[string]any) ClientOption {
+	return func(c *Config) {
+		if c.ExtraBody == nil {
+			c.ExtraBody = make(map[string]any, len(body))
+		}
+		for key, value := range body {
+			c.ExtraBody[key] = value // nil kept: it deletes the field at build time
+		}
+	}
+}
+
+// WithExtraQuery adds query parameters to every request URL
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithExtraQuery(url.Values{"api-version": {"2024-10-21"}}))
+func WithExtraQuery(query url.Values) ClientOption {
+	return func(c *Config) {
+		if c.ExtraQuery == nil {
+			c.ExtraQuery = make(url.Values, len(query))
+		}
+		for key, values := range query {
+			c.ExtraQuery[key] = append([]string(nil), values...)
+		}
+	}
+}
+
+// decorateBody applies sampling, tenant and ExtraBody parameters to a built request body
+// (live, streamed and batch requests alike); passthrough parameters win over built fields
+func (client *Client) decorateBody(ctx context.Context, requestBody map[string]any) {
+	client.applySampling(requestBody)
+	client.applyTenant(ctx, requestBody)
+	mergeExtraBody(requestBody, client.config.ExtraBody)
+}
+
+// mergeExtraBody merges extra into body (nested maps recursively, nil deletes)
+//
+// Nested maps are copied before merging, so maps shared with the configuration
+// (e.g. a response schema) are never modified.
+func mergeExtraBody(body, extra map[string]any) {
+	for key, value := range extra {
+		if value == nil {
+			delete(body, key)
+			continue
+		}
+		nested, ok := value.(map[string]any)
+		if !ok {
+			body[key] = value
+			continue
+		}
+		existing, _ := body[key].(map[string]any)
+		merged := make(map[string]any, len(existing)+len(nested))
+		for k, v := range existing {
+			merged[k] = v
+		}
+		mergeExtraBody(merged, nested)
+		body[key] = merged
+	}
+}
+
+// withExtraQuery returns rawURL with query parameters added (existing keys are replaced)
+func withExtraQuery(rawURL string, query url.Values) string {
+	if len(query) == 0 {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	values := parsed.Query()
+	for key, list := range query {
+		values[key] = list
+	}
+	parsed.RawQuery = values.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestExtraBodyAndQuery(t *testing.T) {
+	var (
+		body     map[string]any
+		rawQuery string
+	)
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		rawQuery = req.URL.RawQuery
+		data, _ := io.ReadAll(req.Body)
+		json.Unmarshal(data, &body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"choices":[{"message":{"content":"ok"}}]}`)),
+			Header:     make(http.Header),
+		}, nil
+	}
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithJSONResponse(nil),
+		WithExtraBody(map[string]any{
+			"parallel_tool_calls": false,
+			"temperature":         0.1,
+			"max_tokens":          nil,
+			"response_format":     map[string]any{"strict": true},
+		}),
+		WithExtraQuery(url.Values{"api-version": {"2024-10-21"}}),
+	)
+
+	// The mocked answer isn't JSON, only the outgoing request matters here
+	client.CallWithMessages("system", "user")
+
+	if body["parallel_tool_calls"] != false {
+		t.Errorf("parallel_tool_calls = %v, want false", body["parallel_tool_calls"])
+	}
+	if body["temperature"] != 0.1 {
+		t.Errorf("temperature = %v, want extra body to override built value", body["temperature"])
+	}
+	if _, ok := body["max_tokens"]; ok {
+		t.Error("nil extra value should remove max_tokens")
+	}
+	format, _ := body["response_format"].(map[string]any)
+	if format["type"] != "json_object" || format["strict"] != true {
+		t.Errorf("response_format = %v, want nested fields merged", format)
+	}
+	if rawQuery != "api-version=2024-10-21" {
+		t.Errorf("query = %q, want api-version=2024-10-21", rawQuery)
+	}
+}
+
+func TestWithExtraQuery_KeepsExistingParameters(t *testing.T) {
+	got := withExtraQuery("https://example.com/v1/chat?deployment=a", url.Values{"api-version": {"1"}})
+	if got != "https://example.com/v1/chat?api-version=1&deployment=a" {
+		t.Errorf("withExtraQuery = %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Endpoint names of path templates (see WithPathTemplate)
+const (
+	EndpointChat       = "chat"
+	EndpointEmbeddings = "embeddings"
+	EndpointModels     = "models"
+	EndpointImages     = "images"
+	EndpointFiles      = "files"
+	EndpointBatches    = "batches"
+	EndpointRerank     = "rerank"
+)
+
+// defaultPathTemplates OpenAI-compatible endpoint paths
+var defaultPathTemplates = map[string]string{
+	EndpointChat:       "{base}/chat/completions",
+	EndpointEmbeddings: "{base}/embeddings",
+	EndpointModels:     "{base}/models",
+	EndpointImages:     "{base}/images/generations",
+	EndpointFiles:      "{base}/files",
+	EndpointBatches:    "{base}/batches",
+	EndpointRerank:     "{base}/rerank",
+}
+
+// providerPathTemplates provider-specific paths, overriding defaultPathTemplates
+var providerPathTemplates = map[string]map[string]string{
+	ProviderClaude: {EndpointChat: "{base}/messages"},
+	ProviderCohere: {EndpointChat: "{base}/chat", EndpointEmbeddings: "{base}/embed"},
+}
+
+// WithPathTemplate overrides the URL template of an endpoint (EndpointChat, EndpointEmbeddings, ...)
+//
+// "{base}" expands to the base URL and "{origin}" to its scheme and host; a template
+// starting with "/" is relative to the origin. Templates without placeholders are used
+// as full URLs.
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithBaseURL("https://gateway.corp.internal"),
+//       mcp.WithPathTemplate(mcp.EndpointEmbeddings, "/llm/proxy/v1/embeddings"),
+//   )
+func WithPathTemplate(endpoint, template string) ClientOption {
+	return func(c *Config) {
+		if c.PathTemplates == nil {
+			c.PathTemplates = make(map[string]string)
+		}
+		c.PathTemplates[endpoint] = template
+	}
+}
+
+// WithChatPath overrides the URL template of chat requests (see WithPathTemplate)
+//
+// Usage example:
+//   mcp.WithChatPath("/llm/proxy/v1/chat/completions") // gateway under a nonstandard prefix
+//   mcp.WithChatPath("{base}/api/chat")                // Ollama native API
+func WithChatPath(template string) ClientOption {
+	return WithPathTemplate(EndpointChat, template)
+}
+
+// endpointURL returns the URL of endpoint, expanded from its path template
+func (client *Client) endpointURL(endpoint string) string {
+	template, ok := client.config.PathTemplates[endpoint]
+	if !ok {
+		template, ok = providerPathTemplates[client.Provider][endpoint]
+	}
+	if !ok {
+		template = defaultPathTemplates[endpoint]
+	}
+	return expandPathTemplate(template, client.BaseURL)
+}
+
+// expandPathTemplate fills the placeholders of template from baseURL
+func expandPathTemplate(template, baseURL string) string {
+	base := strings.TrimSuffix(baseURL, "/")
+	origin := base
+	if parsed, err := url.Parse(base); err == nil && parsed.Host != "" {
+		origin = parsed.Scheme + "://" + parsed.Host
+	}
+	if strings.HasPrefix(template, "/") {
+		template = "{origin}" + template
+	}
+	return strings.NewReplacer("{base}", base, "{origin}", origin).Replace(template)
+}
@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEndpointURL_Templates(t *testing.T) {
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL("https://gateway.corp.internal/v1")).(*Client)
+	if got := client.endpointURL(EndpointChat); got != "https://gateway.corp.internal/v1/chat/completions" {
+		t.Errorf("default chat path: got %q", got)
+	}
+
+	claude, _ := BaseClient(NewClaudeClientWithOptions())
+	if got := claude.buildUrl(); got != DefaultClaudeBaseURL+"/messages" {
+		t.Errorf("Claude chat path: got %q", got)
+	}
+
+	client = NewClient(WithProvider(ProviderCustom), WithBaseURL("https://gateway.corp.internal/v1"),
+		WithChatPath("/llm/proxy/v1/chat/completions"),
+		WithPathTemplate(EndpointModels, "{base}/api/tags"),
+		WithPathTemplate(EndpointEmbeddings, "https://embed.corp.internal/embed")).(*Client)
+	tests := map[string]string{
+		EndpointChat:       "https://gateway.corp.internal/llm/proxy/v1/chat/completions",
+		EndpointModels:     "https://gateway.corp.internal/v1/api/tags",
+		EndpointEmbeddings: "https://embed.corp.internal/embed",
+		EndpointFiles:      "https://gateway.corp.internal/v1/files",
+	}
+	for endpoint, want := range tests {
+		if got := client.endpointURL(endpoint); got != want {
+			t.Errorf("%s: expected %q, got %q", endpoint, want, got)
+		}
+	}
+}
+
+func TestWithChatPath_Request(t *testing.T) {
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+	client := NewClient(WithProvider(ProviderCustom), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()),
+		WithHTTPClient(mock.ToHTTPClient()), WithBaseURL("http://localhost:11434"), WithChatPath("{base}/api/chat"))
+	if _, err := client.(*Client).CallMessages(context.Background(), "system", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if got := mock.GetLastRequest().URL.String(); got != "http://localhost:11434/api/chat" {
+		t.Errorf("expected templated chat URL, got %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PIIPolicy kind of sensitive data detected in outgoing prompts
+type PIIPolicy struct {
+	Name    string         // Placeholder label (e.g. "EMAIL" -> [EMAIL_1])
+	Pattern *regexp.Regexp // Matches the sensitive values
+}
+
+// Built-in PII policies
+var (
+	PIIEmail  = PIIPolicy{Name: "EMAIL", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)}
+	PIIPhone  = PIIPolicy{Name: "PHONE", Pattern: regexp.MustCompile(`\+\d{1,3}[\s-]?\d{4,14}\b|\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b`)}
+	PIIAPIKey = PIIPolicy{Name: "API_KEY", Pattern: regexp.MustCompile(`\b(sk|pk|rk)-[A-Za-z0-9_-]{16,}\b|\bAKIA[0-9A-Z]{16}\b|\b(ghp|gho|xox[bap])[-_][A-Za-z0-9-]{20,}\b`)}
+	PIIWallet = PIIPolicy{Name: "WALLET", Pattern: regexp.MustCompile(`\b0x[a-fA-F0-9]{40}\b|\bbc1[a-z0-9]{25,59}\b|\b[13][a-km-zA-HJ-NP-Z1-9]{25,34}\b`)}
+)
+
+// DefaultPIIPolicies returns emails, phone numbers, API keys and wallet addresses
+func DefaultPIIPolicies() []PIIPolicy {
+	return []PIIPolicy{PIIEmail, PIIAPIKey, PIIWallet, PIIPhone}
+}
+
+// CustomPIIPolicy creates policy from a regular expression (panics on invalid syntax)
+func CustomPIIPolicy(name, pattern string) PIIPolicy {
+	return PIIPolicy{Name: strings.ToUpper(name), Pattern: regexp.MustCompile(pattern)}
+}
+
+// WithPIIRedaction replaces sensitive data in prompts with placeholders before sending
+//
+// Every match of a policy is replaced by a placeholder such as [EMAIL_1] (the same value
+// always gets the same placeholder within a call), so the data never reaches the
+// provider. Placeholders in the response (text, reasoning and tool call arguments) are
+// restored to the original values before the answer is returned; streamed deltas hold
+// back a placeholder split across chunks until it is complete. Policies run in order;
+// no policies = DefaultPIIPolicies.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithPIIRedaction(
+//       mcp.PIIEmail, mcp.PIIWallet,
+//       mcp.CustomPIIPolicy("account", `ACC-\d{8}`),
+//   ))
+func WithPIIRedaction(policies ...PIIPolicy) ClientOption {
+	return func(c *Config) {
+		if len(policies) == 0 {
+			policies = DefaultPIIPolicies()
+		}
+		c.PIIPolicies = policies
+	}
+}
+
+// piiVault placeholder mapping of one call (nil = redaction disabled)
+type piiVault struct {
+	policies     []PIIPolicy
+	placeholders map[string]string // Original value -> placeholder
+	originals    map[string]string // Placeholder -> original value
+	counts       map[string]int    // Placeholders issued per policy
+}
+
+// newPIIVault creates vault for one call (nil when redaction is disabled)
+func (client *Client) newPIIVault() *piiVault {
+	if len(client.config.PIIPolicies) == 0 {
+		return nil
+	}
+	return &piiVault{
+		policies:     client.config.PIIPolicies,
+		placeholders: make(map[string]string),
+		originals:    make(map[string]string),
+		counts:       make(map[string]int),
+	}
+}
+
+// redact replaces sensitive values in text with placeholders
+func (v *piiVault) redact(text string) string {
+	if v == nil {
+		return text
+	}
+	for _, policy := range v.policies {
+		text = policy.Pattern.ReplaceAllStringFunc(text, func(value string) string {
+			if placeholder, ok := v.placeholders[value]; ok {
+				return placeholder
+			}
+			v.counts[policy.Name]++
+			placeholder := fmt.Sprintf("[%s_%d]", policy.Name, v.counts[policy.Name])
+			v.placeholders[value] = placeholder
+			v.originals[placeholder] = value
+			return placeholder
+		})
+	}
+	return text
+}
+
+// redactRequest returns copy of req with redacted messages (content and tool call arguments)
+func (v *piiVault) redactRequest(req *Request) *Request {
+	if v == nil {
+		return req
+	}
+	redacted := *req
+	redacted.Messages = make([]Message, len(req.Messages))
+	for i, msg := range req.Messages {
+		msg.Content = v.redact(msg.Content)
+		if len(msg.ToolCalls) > 0 {
+			calls := make([]ToolCall, len(msg.ToolCalls))
+			for j, call := range msg.ToolCalls {
+				call.Function.Arguments = v.redact(call.Function.Arguments)
+				calls[j] = call
+			}
+			msg.ToolCalls = calls
+		}
+		redacted.Messages[i] = msg
+	}
+	return &redacted
+}
+
+// restore replaces placeholders in text with the original values
+func (v *piiVault) restore(text string) string {
+	return v.replace(text, false)
+}
+
+// restoreJSON replaces placeholders in JSON text with the original values, escaped for JSON strings
+func (v *piiVault) restoreJSON(text string) string {
+	return v.replace(text, true)
+}
+
+func (v *piiVault) replace(text string, escape bool) string {
+	if v == nil || len(v.originals) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(v.originals))
+	for placeholder, original := range v.originals {
+		if escape {
+			quoted, _ := json.Marshal(original)
+			original = string(quoted[1 : len(quoted)-1])
+		}
+		pairs = append(pairs, placeholder, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// restoreDelta restores a streamed delta, holding back in *held a trailing fragment
+// that may be a placeholder split across chunks (sent with the next delta)
+func (v *piiVault) restoreDelta(held *string, delta string, escape bool) string {
+	if v == nil || len(v.originals) == 0 {
+		return delta
+	}
+	text := *held + delta
+	keep := 0
+	for placeholder := range v.originals {
+		keep = max(keep, partialTagSuffix(text, placeholder))
+	}
+	*held = text[len(text)-keep:]
+	return v.replace(text[:len(text)-keep], escape)
+}
+
+// restoreToolCalls returns copy of calls with placeholders in the arguments restored
+func (v *piiVault) restoreToolCalls(calls []ToolCall) []ToolCall {
+	if v == nil || len(calls) == 0 {
+		return calls
+	}
+	restored := make([]ToolCall, len(calls))
+	for i, call := range calls {
+		call.Function.Arguments = v.restoreJSON(call.Function.Arguments)
+		restored[i] = call
+	}
+	return restored
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPIIRedaction_RoundTrip(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"Sent report to [EMAIL_1]; wallet [WALLET_1] holds 2 BTC."}}]}`
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPIIRedaction(),
+	)
+
+	wallet := "0x52908400098527886E0F7030069857D2E4169EE7"
+	result, err := client.CallWithMessages(
+		"Contact trader@example.com for escalations.",
+		"Wallet "+wallet+" owned by trader@example.com, key sk-live-abcdefghijklmnop1234, call +44 7911123456. Timestamp 1700000000.",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := bodies[0]["messages"].([]any)
+	system := sent[0].(map[string]any)["content"].(string)
+	user := sent[1].(map[string]any)["content"].(string)
+	for _, secret := range []string{"trader@example.com", wallet, "sk-live", "7911123456"} {
+		if strings.Contains(system+user, secret) {
+			t.Errorf("%q leaked upstream: %q / %q", secret, system, user)
+		}
+	}
+	if !strings.Contains(system, "[EMAIL_1]") || strings.Count(user, "[EMAIL_1]") != 1 {
+		t.Errorf("same value should get the same placeholder across messages: %q / %q", system, user)
+	}
+	for _, placeholder := range []string{"[WALLET_1]", "[API_KEY_1]", "[PHONE_1]", "1700000000"} {
+		if !strings.Contains(user, placeholder) {
+			t.Errorf("expected %q in redacted prompt %q", placeholder, user)
+		}
+	}
+
+	want := "Sent report to trader@example.com; wallet " + wallet + " holds 2 BTC."
+	if result != want {
+		t.Errorf("placeholders should be restored, got %q", result)
+	}
+}
+
+func TestPIIRedaction_CustomPolicyRequest(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"Account [ACCOUNT_1] approved"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPIIRedaction(CustomPIIPolicy("account", `ACC-\d{8}`)),
+	).(*Client)
+
+	req := NewRequestBuilder().WithUserPrompt("Approve ACC-12345678, contact a@b.io").MustBuild()
+	response, err := client.Call(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := bodies[0]["messages"].([]any)[0].(map[string]any)["content"].(string)
+	if sent != "Approve [ACCOUNT_1], contact a@b.io" {
+		t.Errorf("only custom policy should apply, got %q", sent)
+	}
+	if req.Messages[0].Content != "Approve ACC-12345678, contact a@b.io" {
+		t.Error("caller's request must not be modified")
+	}
+	if response.Text != "Account ACC-12345678 approved" {
+		t.Errorf("unexpected restored text %q", response.Text)
+	}
+}
+
+func TestPIIRedaction_RestoresToolCalls(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"","tool_calls":[{"id":"call_2","type":"function","function":{"name":"send_report","arguments":"{\"to\":\"[EMAIL_1]\"}"}}]},"finish_reason":"tool_calls"}]}`
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPIIRedaction(PIIEmail),
+	).(*Client)
+
+	req := NewRequestBuilder().WithUserPrompt("Send it to trader@example.com").MustBuild()
+	req.Messages = append(req.Messages,
+		NewToolCallsMessage("", []ToolCall{{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "lookup", Arguments: `{"email":"trader@example.com"}`}}}),
+		NewToolMessage("call_1", "found"))
+	response, err := client.Call(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent, _ := json.Marshal(bodies[0]["messages"])
+	if strings.Contains(string(sent), "trader@example.com") {
+		t.Errorf("tool call arguments leaked upstream: %s", sent)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].Function.Arguments != `{"to":"trader@example.com"}` {
+		t.Errorf("tool call arguments should be restored, got %+v", response.ToolCalls)
+	}
+}
+
+func TestPIIRedaction_StreamRestoresSplitPlaceholders(t *testing.T) {
+	stream := sseLines(
+		`{"choices":[{"delta":{"content":"Mail [EMA"}}]}`,
+		`{"choices":[{"delta":{"content":"IL_1] now, then [EMAIL"}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"send","arguments":"{\"to\":\"[EMAIL"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"_1]\"}"}}]}}]}`,
+		`{"choices":[{"delta":{},"finish_reason":"tool_calls"}]}`,
+		`[DONE]`,
+	)
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithProvider(ProviderOpenAI),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPIIRedaction(PIIEmail),
+	).(*Client)
+
+	var text, arguments string
+	var complete *ToolCall
+	response, err := client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("Mail trader@example.com").MustBuild(), func(event StreamEvent) error {
+		switch event.Type {
+		case StreamTextDelta:
+			text += event.Text
+		case StreamToolCallDelta:
+			arguments += event.Text
+		case StreamToolCallComplete:
+			complete = event.ToolCall
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CallStream failed: %v", err)
+	}
+	if text != "Mail trader@example.com now, then [EMAIL" {
+		t.Errorf("split placeholder should be restored and the held fragment flushed, got %q", text)
+	}
+	if arguments != `{"to":"trader@example.com"}` || complete == nil || complete.Function.Arguments != arguments {
+		t.Errorf("streamed arguments should be restored, got %q / %+v", arguments, complete)
+	}
+	if response.ToolCalls[0].Function.Arguments != `{"to":"trader@example.com"}` {
+		t.Errorf("response tool calls should be restored, got %+v", response.ToolCalls)
+	}
+}
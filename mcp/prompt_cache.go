@@ -0,0 +1,123 @@
+package mcp
+
+import "encoding/json"
+
+// maxCacheBreakpoints Anthropic accepts at most this many cache_control blocks per request
+const maxCacheBreakpoints = 4
+
+// WithPromptCaching marks stable prompt prefixes as Anthropic cache breakpoints
+//
+// The system prompt, and every message added with RequestBuilder.AddCachedContext
+// (e.g. long RAG context), is sent with cache_control {"type": "ephemeral"} so
+// repeated calls read it from the provider cache at a fraction of the input price.
+// OpenAI and DeepSeek cache long prefixes automatically; in every case the cached
+// tokens are reported in TokenUsage.CacheReadTokens / CacheWriteTokens.
+//
+// Usage example:
+//   client := mcp.NewClaudeClientWithOptions(mcp.WithPromptCaching(true))
+//   request, _ := mcp.NewRequestBuilder().
+//       WithSystemPrompt(tradingRules).
+//       AddCachedContext(marketResearch).
+//       WithUserPrompt(snapshot).
+//       Build()
+func WithPromptCaching(enabled bool) ClientOption {
+	return func(c *Config) {
+		c.PromptCaching = enabled
+	}
+}
+
+// WithPromptCacheKey sets OpenAI prompt_cache_key to improve cache hit rates
+//
+// Requests sharing a long prefix should use the same key so OpenAI routes them to
+// the same cache. Ignored by other providers.
+//
+// Usage example:
+//   client := mcp.NewOpenAIClientWithOptions(mcp.WithPromptCacheKey("strategy-42"))
+func WithPromptCacheKey(key string) ClientOption {
+	return func(c *Config) {
+		c.PromptCacheKey = key
+	}
+}
+
+// applyPromptCaching adds provider cache hints to a request body
+//
+// messages are the request messages (nil for system/user prompt calls, where the
+// system prompt is already in requestBody["system"] for Anthropic).
+func (client *Client) applyPromptCaching(requestBody map[string]any, messages []Message) {
+	if client.Provider == ProviderOpenAI && client.config.PromptCacheKey != "" {
+		requestBody["prompt_cache_key"] = client.config.PromptCacheKey
+	}
+	if client.Provider != ProviderClaude || !client.config.PromptCaching {
+		return
+	}
+
+	if messages == nil {
+		if system, ok := requestBody["system"].(string); ok && system != "" {
+			requestBody["system"] = []map[string]any{cachedTextBlock(system)}
+		}
+		return
+	}
+
+	// Anthropic takes system prompts outside of messages; cache the whole system prefix
+	var system []map[string]any
+	converted := make([]map[string]any, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			system = append(system, map[string]any{"type": "text", "text": msg.Content})
+			continue
+		}
+		converted = append(converted, anthropicMessage(msg))
+	}
+
+	breakpoints := 0
+	if len(system) > 0 {
+		system[len(system)-1]["cache_control"] = ephemeralCache()
+		requestBody["system"] = system
+		breakpoints++
+	}
+	i := 0
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			continue
+		}
+		if msg.Cache && msg.ToolCallID == "" && len(msg.ToolCalls) == 0 && breakpoints < maxCacheBreakpoints {
+			converted[i]["content"] = []map[string]any{cachedTextBlock(msg.Content)}
+			breakpoints++
+		}
+		i++
+	}
+	requestBody["messages"] = converted
+}
+
+// anthropicMessage converts message to Anthropic format (tool calls and results become content blocks)
+func anthropicMessage(msg Message) map[string]any {
+	switch {
+	case msg.ToolCallID != "":
+		return map[string]any{"role": "user", "content": []map[string]any{
+			{"type": "tool_result", "tool_use_id": msg.ToolCallID, "content": msg.Content},
+		}}
+	case len(msg.ToolCalls) > 0:
+		var blocks []map[string]any
+		if msg.Content != "" {
+			blocks = append(blocks, map[string]any{"type": "text", "text": msg.Content})
+		}
+		for _, call := range msg.ToolCalls {
+			input := json.RawMessage(call.Function.Arguments)
+			if !json.Valid(input) {
+				input = json.RawMessage("{}")
+			}
+			blocks = append(blocks, map[string]any{"type": "tool_use", "id": call.ID, "name": call.Function.Name, "input": input})
+		}
+		return map[string]any{"role": msg.Role, "content": blocks}
+	}
+	return map[string]any{"role": msg.Role, "content": msg.Content}
+}
+
+// cachedTextBlock Anthropic text content block marked as cache breakpoint
+func cachedTextBlock(text string) map[string]any {
+	return map[string]any{"type": "text", "text": text, "cache_control": ephemeralCache()}
+}
+
+func ephemeralCache() map[string]any {
+	return map[string]any{"type": "ephemeral"}
+}
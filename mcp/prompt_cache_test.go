@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPromptCaching_ClaudeSystemPrompt(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"content":[{"type":"text","text":"hold"}],"usage":{"input_tokens":20,"output_tokens":5,"cache_read_input_tokens":1800,"cache_creation_input_tokens":0}}`
+
+	var reported TokenUsage
+	TokenUsageCallback = func(usage TokenUsage) { reported = usage }
+	defer func() { TokenUsageCallback = nil }()
+
+	client := NewClaudeClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPromptCaching(true),
+	)
+
+	if _, err := client.CallWithMessages("trading rules", "decide"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	system, _ := bodies[0]["system"].([]any)
+	if len(system) != 1 {
+		t.Fatalf("expected system prompt as one content block, got %v", bodies[0]["system"])
+	}
+	block := system[0].(map[string]any)
+	cache, _ := block["cache_control"].(map[string]any)
+	if block["text"] != "trading rules" || cache["type"] != "ephemeral" {
+		t.Errorf("system block should be cached, got %v", block)
+	}
+
+	if reported.CacheReadTokens != 1800 || reported.PromptTokens != 1820 {
+		t.Errorf("expected cache reads reported in usage, got %+v", reported)
+	}
+}
+
+func TestPromptCaching_ClaudeCachedContext(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"content":[{"type":"text","text":"hold"}]}`
+
+	client := NewClaudeClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPromptCaching(true),
+	)
+
+	request := NewRequestBuilder().
+		WithSystemPrompt("trading rules").
+		AddCachedContext("long research report").
+		WithUserPrompt("decide").
+		MustBuild()
+	if _, err := client.CallWithRequest(request); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	if _, ok := bodies[0]["system"].([]any); !ok {
+		t.Fatalf("system messages should be moved to system blocks, got %v", bodies[0]["system"])
+	}
+	messages, _ := bodies[0]["messages"].([]any)
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 non-system messages, got %v", messages)
+	}
+	context := messages[0].(map[string]any)
+	blocks, ok := context["content"].([]any)
+	if !ok || blocks[0].(map[string]any)["cache_control"] == nil {
+		t.Errorf("cached context should carry cache_control, got %v", context)
+	}
+	if question := messages[1].(map[string]any); question["content"] != "decide" {
+		t.Errorf("question should stay plain text, got %v", question)
+	}
+}
+
+func TestPromptCaching_DisabledAndOpenAIKey(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"hold"}}]}`
+
+	claude := NewClaudeClientWithOptions(
+		WithHTTPClient(captureBodyClient(`{"content":[{"type":"text","text":"hold"}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	)
+	claude.CallWithMessages("trading rules", "decide")
+	if bodies[0]["system"] != "trading rules" {
+		t.Errorf("without caching system prompt should stay a string, got %v", bodies[0]["system"])
+	}
+
+	openai := NewOpenAIClientWithOptions(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithPromptCacheKey("strategy-42"),
+	)
+	openai.CallWithMessages("trading rules", "decide")
+	if bodies[1]["prompt_cache_key"] != "strategy-42" {
+		t.Errorf("expected prompt_cache_key, got %v", bodies[1]["prompt_cache_key"])
+	}
+}
+
+func TestParseUsage_CacheTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		prompt     int
+		cacheRead  int
+		cacheWrite int
+	}{
+		{"openai", `{"usage":{"prompt_tokens":2000,"completion_tokens":10,"prompt_tokens_details":{"cached_tokens":1536}}}`, 2000, 1536, 0},
+		{"deepseek", `{"usage":{"prompt_tokens":900,"completion_tokens":10,"prompt_cache_hit_tokens":640,"prompt_cache_miss_tokens":260}}`, 900, 640, 0},
+		{"anthropic", `{"usage":{"input_tokens":50,"output_tokens":10,"cache_read_input_tokens":100,"cache_creation_input_tokens":1000}}`, 1150, 100, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := parseUsage([]byte(tt.body))
+			if usage.PromptTokens != tt.prompt || usage.CacheReadTokens != tt.cacheRead || usage.CacheWriteTokens != tt.cacheWrite {
+				t.Errorf("unexpected usage %+v", usage)
+			}
+		})
+	}
+}
+
+func TestEstimateCost_CachedTokens(t *testing.T) {
+	RegisterModelPrice("cache-test-model", ModelPrice{
+		InputPerMillion:       3,
+		OutputPerMillion:      15,
+		CachedInputPerMillion: 0.3,
+		CacheWritePerMillion:  3.75,
+	})
+
+	usage := TokenUsage{Model: "cache-test-model", PromptTokens: 2_000_000, CacheReadTokens: 1_000_000}
+	if cost := EstimateCost(usage); math.Abs(cost-3.3) > 1e-9 {
+		t.Errorf("expected cached reads at cache price, got %v", cost)
+	}
+	if saved := CacheSavings(usage); math.Abs(saved-2.7) > 1e-9 {
+		t.Errorf("expected $2.70 saved, got %v", saved)
+	}
+
+	write := TokenUsage{Model: "cache-test-model", PromptTokens: 1_000_000, CacheWriteTokens: 1_000_000}
+	if saved := CacheSavings(write); math.Abs(saved+0.75) > 1e-9 {
+		t.Errorf("cache writes should cost a premium, got %v", saved)
+	}
+}
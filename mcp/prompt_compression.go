@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultCompressionMinTokens messages shorter than this are not compressed by default
+const DefaultCompressionMinTokens = 500
+
+// compressionStopwords filler words pruned first (carry little information)
+var compressionStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true, "of": true, "to": true,
+	"in": true, "on": true, "at": true, "for": true, "with": true, "by": true, "from": true, "as": true,
+	"is": true, "are": true, "was": true, "were": true, "be": true, "been": true, "being": true,
+	"it": true, "its": true, "this": true, "that": true, "these": true, "those": true, "there": true,
+	"which": true, "who": true, "whom": true, "what": true, "also": true, "very": true, "just": true,
+	"so": true, "then": true, "than": true, "has": true, "have": true, "had": true, "will": true,
+	"would": true, "can": true, "could": true, "may": true, "might": true, "about": true, "into": true,
+	"over": true, "such": true, "some": true, "any": true, "all": true, "more": true, "most": true,
+	"really": true, "quite": true, "rather": true, "however": true, "moreover": true, "furthermore": true,
+}
+
+// CompressionStats token counts of a compressed prompt
+type CompressionStats struct {
+	OriginalTokens   int // Estimated tokens before compression
+	CompressedTokens int // Estimated tokens sent
+}
+
+// Saved returns the number of tokens removed
+func (s CompressionStats) Saved() int {
+	return s.OriginalTokens - s.CompressedTokens
+}
+
+// WithPromptCompression prunes low-information words from long user messages before sending
+//
+// User messages of at least minTokens (0 = DefaultCompressionMinTokens) are compressed
+// with CompressPrompt to about ratio of their tokens (e.g. 0.6 keeps 60%). Meant for
+// context-heavy calls (market data, news, RAG results); system prompts and short
+// messages are sent as is. Token counts before/after are reported in Response.Compression.
+//
+// Usage example:
+//   client := mcp.NewDeepSeekClientWithOptions(mcp.WithPromptCompression(0.6, 1000))
+func WithPromptCompression(ratio float64, minTokens int) ClientOption {
+	return func(c *Config) {
+		c.CompressionRatio = ratio
+		c.CompressionMinTokens = minTokens
+	}
+}
+
+// CompressPrompt removes low-information words from text until about ratio of its tokens remain
+//
+// LLMLingua-style pruning without a scoring model: words are ranked by self-information
+// (rare words in the text score high, repeated ones and stopwords low) and the lowest
+// are dropped first. Numbers, symbols, capitalized words, punctuation-only tokens,
+// line breaks and fenced code blocks are always kept, so the result may stay above
+// ratio when little prunable text is left.
+func CompressPrompt(text string, ratio float64) (string, CompressionStats) {
+	stats := CompressionStats{OriginalTokens: EstimateTokens(text), CompressedTokens: EstimateTokens(text)}
+	if ratio <= 0 || ratio >= 1 || text == "" {
+		return text, stats
+	}
+
+	// Split into lines of words, keeping code blocks untouched
+	type word struct {
+		line, index int
+		text        string
+		score       float64
+		cost        float64
+	}
+	lines := strings.Split(text, "\n")
+	kept := make([][]string, len(lines))
+	inCode := false
+	counts := map[string]int{}
+	var words []word
+	var total int
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		}
+		if inCode || strings.HasPrefix(strings.TrimSpace(line), "```") {
+			continue
+		}
+		fields := strings.Fields(line)
+		kept[i] = fields
+		for j, field := range fields {
+			core := compressionCore(field)
+			if core == "" || compressionProtected(field) {
+				continue
+			}
+			counts[core]++
+			total++
+			words = append(words, word{line: i, index: j, text: core, cost: float64(EstimateTokens(field + " "))})
+		}
+	}
+	if len(words) == 0 {
+		return text, stats
+	}
+
+	// Self-information -log p(word), stopwords carry none
+	for i := range words {
+		if compressionStopwords[words[i].text] {
+			continue
+		}
+		words[i].score = -math.Log(float64(counts[words[i].text]) / float64(total))
+	}
+	order := make([]int, len(words))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return words[order[a]].score < words[order[b]].score })
+
+	toRemove := float64(stats.OriginalTokens) * (1 - ratio)
+	dropped := make(map[[2]int]bool)
+	for _, i := range order {
+		if toRemove <= 0 {
+			break
+		}
+		dropped[[2]int{words[i].line, words[i].index}] = true
+		toRemove -= words[i].cost
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if kept[i] == nil {
+			b.WriteString(line)
+			continue
+		}
+		first := true
+		for j, field := range kept[i] {
+			if dropped[[2]int{i, j}] {
+				continue
+			}
+			if !first {
+				b.WriteByte(' ')
+			}
+			b.WriteString(field)
+			first = false
+		}
+	}
+	compressed := b.String()
+	stats.CompressedTokens = EstimateTokens(compressed)
+	return compressed, stats
+}
+
+// compressionCore lowercased word without surrounding punctuation
+func compressionCore(field string) string {
+	return strings.ToLower(strings.TrimFunc(field, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }))
+}
+
+// compressionProtected reports whether a word is never pruned (numbers, tickers, names, symbols)
+func compressionProtected(field string) bool {
+	for i, r := range field {
+		if unicode.IsDigit(r) || unicode.IsSymbol(r) || (i > 0 && unicode.IsUpper(r)) {
+			return true
+		}
+	}
+	r := []rune(field)[0]
+	return unicode.IsUpper(r) && len(field) > 1 && !compressionStopwords[strings.ToLower(field)]
+}
+
+// compressPrompt compresses userPrompt if prompt compression is enabled and it is long enough
+func (client *Client) compressPrompt(userPrompt string) (string, *CompressionStats) {
+	ratio := client.config.CompressionRatio
+	if ratio <= 0 || ratio >= 1 {
+		return userPrompt, nil
+	}
+	minTokens := client.config.CompressionMinTokens
+	if minTokens <= 0 {
+		minTokens = DefaultCompressionMinTokens
+	}
+	if EstimateTokens(userPrompt) < minTokens {
+		return userPrompt, nil
+	}
+	compressed, stats := CompressPrompt(userPrompt, ratio)
+	client.logger.Infof("🗜️  [%s] Prompt compressed: %d → %d tokens", client.String(), stats.OriginalTokens, stats.CompressedTokens)
+	return compressed, &stats
+}
+
+// compressRequest compresses the long user messages of req (a copy, req is unchanged)
+func (client *Client) compressRequest(req *Request) (*Request, *CompressionStats) {
+	var total *CompressionStats
+	var compressedReq *Request
+	for i, msg := range req.Messages {
+		if msg.Role != "user" {
+			continue
+		}
+		compressed, stats := client.compressPrompt(msg.Content)
+		if stats == nil {
+			continue
+		}
+		if compressedReq == nil {
+			compressedReq = cloneRequest(req)
+			total = &CompressionStats{}
+		}
+		compressedReq.Messages[i].Content = compressed
+		total.OriginalTokens += stats.OriginalTokens
+		total.CompressedTokens += stats.CompressedTokens
+	}
+	if compressedReq == nil {
+		return req, nil
+	}
+	return compressedReq, total
+}
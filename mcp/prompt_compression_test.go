@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompressPrompt(t *testing.T) {
+	text := strings.Repeat("The market is really very volatile and the funding rate of BTC is 0.01% which is quite high for this time of the week.\n", 5) +
+		"```json\n{\"the\": \"is a\"}\n```"
+	compressed, stats := CompressPrompt(text, 0.6)
+	if stats.OriginalTokens != EstimateTokens(text) || stats.CompressedTokens != EstimateTokens(compressed) {
+		t.Fatalf("stats don't match texts: %+v", stats)
+	}
+	if stats.Saved() <= 0 || float64(stats.CompressedTokens) > 0.7*float64(stats.OriginalTokens) {
+		t.Errorf("expected about 60%% of tokens kept, got %+v", stats)
+	}
+	for _, keep := range []string{"BTC", "0.01%", "volatile", "```json\n{\"the\": \"is a\"}\n```"} {
+		if !strings.Contains(compressed, keep) {
+			t.Errorf("%q should be kept, got:\n%s", keep, compressed)
+		}
+	}
+	if strings.Count(compressed, "\n") != strings.Count(text, "\n") {
+		t.Errorf("line structure should be kept, got:\n%s", compressed)
+	}
+
+	if same, _ := CompressPrompt(text, 1); same != text {
+		t.Error("ratio 1 should leave the text unchanged")
+	}
+}
+
+func TestPromptCompression_Client(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`, &bodies)),
+		WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithPromptCompression(0.5, 50)).(*Client)
+
+	context := strings.Repeat("the news about the market is that the price of ETH is going up and it is a good sign. ", 10)
+	response, err := client.CallMessages(t.Context(), "Be concise and answer in JSON.", context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Compression == nil || response.Compression.CompressedTokens >= response.Compression.OriginalTokens {
+		t.Fatalf("expected compression stats, got %+v", response.Compression)
+	}
+	messages := bodies[0]["messages"].([]any)
+	if system := messages[0].(map[string]any)["content"]; system != "Be concise and answer in JSON." {
+		t.Errorf("system prompt should be sent as is, got %v", system)
+	}
+	if user := messages[1].(map[string]any)["content"].(string); EstimateTokens(user) != response.Compression.CompressedTokens {
+		t.Errorf("compressed prompt should be sent, got %q", user)
+	}
+
+	response, err = client.CallMessages(t.Context(), "system", "short question about ETH")
+	if err != nil || response.Compression != nil {
+		t.Errorf("short prompts should not be compressed, got %+v (%v)", response.Compression, err)
+	}
+}
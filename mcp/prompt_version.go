@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// Experiment arms
+const (
+	ArmControl   = "control"
+	ArmCandidate = "candidate"
+)
+
+// PromptTemplate named prompt text with a content-derived version
+type PromptTemplate struct {
+	Name string
+	Text string // May contain {placeholders} filled by Render
+}
+
+// NewPromptTemplate creates prompt template
+func NewPromptTemplate(name, text string) *PromptTemplate {
+	return &PromptTemplate{Name: name, Text: text}
+}
+
+// Version returns short hash of the template text (changes whenever the text does)
+func (t *PromptTemplate) Version() string {
+	sum := sha256.Sum256([]byte(t.Text))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Render fills {name} placeholders with vars (unknown placeholders are kept)
+func (t *PromptTemplate) Render(vars map[string]string) string {
+	pairs := make([]string, 0, 2*len(vars))
+	for key, value := range vars {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(t.Text)
+}
+
+// Context returns ctx recording this template's name and version with calls made with it
+//
+// Usage example:
+//   ctx = decisionPrompt.Context(ctx)
+//   answer, err := client.CallMessages(ctx, decisionPrompt.Render(vars), marketData)
+func (t *PromptTemplate) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, promptVersionKey{}, promptVersion{name: t.Name, version: t.Version()})
+}
+
+// promptVersionKey context key of the prompt template used by a call
+type promptVersionKey struct{}
+
+// promptVersion template recorded with a call
+type promptVersion struct {
+	name, version string
+}
+
+// promptVersionFromContext returns template name and version recorded in ctx
+func promptVersionFromContext(ctx context.Context) (name, version string) {
+	v, _ := ctx.Value(promptVersionKey{}).(promptVersion)
+	return v.name, v.version
+}
+
+// Experiment pins a share of traffic to a candidate prompt template
+//
+// Assignment is deterministic by key (trader ID, symbol, ...), so a unit keeps its
+// arm across calls; outcomes recorded per arm are compared with Results.
+//
+// Usage example:
+//   exp := mcp.NewExperiment("decision-v2", current, candidate, 0.1)
+//   variant := exp.Assign(traderID)
+//   answer, err := client.CallMessages(variant.Context(ctx), variant.Template.Render(vars), data)
+//   exp.RecordOutcome(variant, realizedPnL)
+type Experiment struct {
+	Name           string
+	Control        *PromptTemplate
+	Candidate      *PromptTemplate
+	CandidateShare float64 // Fraction of keys assigned to the candidate (0-1)
+
+	mu      sync.Mutex
+	results map[string]*ExperimentResult
+}
+
+// ExperimentVariant arm assigned to a unit of traffic
+type ExperimentVariant struct {
+	Experiment string
+	Arm        string // ArmControl or ArmCandidate
+	Template   *PromptTemplate
+}
+
+// Context returns ctx recording the template version, plus experiment and arm tags
+func (v ExperimentVariant) Context(ctx context.Context) context.Context {
+	ctx = ContextWithTags(ctx, map[string]string{"experiment": v.Experiment, "experiment_arm": v.Arm})
+	return v.Template.Context(ctx)
+}
+
+// ExperimentResult outcome counters of one arm
+type ExperimentResult struct {
+	Arm      string
+	Version  string  // Template version of the arm
+	Assigned int     // Assignments made
+	Outcomes int     // Outcomes recorded
+	Total    float64 // Sum of outcome values
+}
+
+// Mean returns mean outcome value (0 without outcomes)
+func (r ExperimentResult) Mean() float64 {
+	if r.Outcomes == 0 {
+		return 0
+	}
+	return r.Total / float64(r.Outcomes)
+}
+
+// NewExperiment creates experiment sending candidateShare of traffic to candidate
+func NewExperiment(name string, control, candidate *PromptTemplate, candidateShare float64) *Experiment {
+	return &Experiment{
+		Name:           name,
+		Control:        control,
+		Candidate:      candidate,
+		CandidateShare: candidateShare,
+		results:        make(map[string]*ExperimentResult),
+	}
+}
+
+// Assign returns the arm of key ("" = random assignment)
+func (e *Experiment) Assign(key string) ExperimentVariant {
+	var point float64
+	if key == "" {
+		point = rand.Float64()
+	} else {
+		sum := sha256.Sum256([]byte(e.Name + "\x00" + key))
+		point = float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+	}
+	variant := ExperimentVariant{Experiment: e.Name, Arm: ArmControl, Template: e.Control}
+	if point < e.CandidateShare {
+		variant.Arm, variant.Template = ArmCandidate, e.Candidate
+	}
+	e.mu.Lock()
+	e.result(variant).Assigned++
+	e.mu.Unlock()
+	return variant
+}
+
+// RecordOutcome records the outcome value (PnL, accuracy, judge score, ...) of a variant's call
+func (e *Experiment) RecordOutcome(variant ExperimentVariant, value float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	result := e.result(variant)
+	result.Outcomes++
+	result.Total += value
+}
+
+// Results returns counters of both arms (control first)
+func (e *Experiment) Results() []ExperimentResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	results := []ExperimentResult{}
+	for _, variant := range []ExperimentVariant{{Arm: ArmControl, Template: e.Control}, {Arm: ArmCandidate, Template: e.Candidate}} {
+		results = append(results, *e.result(variant))
+	}
+	return results
+}
+
+// result returns counters of a variant's arm (caller holds mu)
+func (e *Experiment) result(variant ExperimentVariant) *ExperimentResult {
+	if e.results == nil {
+		e.results = make(map[string]*ExperimentResult)
+	}
+	result, ok := e.results[variant.Arm]
+	if !ok {
+		result = &ExperimentResult{Arm: variant.Arm, Version: variant.Template.Version()}
+		e.results[variant.Arm] = result
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPromptTemplate_VersionAndRender(t *testing.T) {
+	a := NewPromptTemplate("decision", "Trade {symbol} with max leverage {leverage}.")
+	b := NewPromptTemplate("decision", "Trade {symbol} with max leverage {leverage}!")
+	if a.Version() == b.Version() || len(a.Version()) != 12 || a.Version() != NewPromptTemplate("other", a.Text).Version() {
+		t.Errorf("version should follow the text: %s vs %s", a.Version(), b.Version())
+	}
+	if got := a.Render(map[string]string{"symbol": "BTCUSDT"}); got != "Trade BTCUSDT with max leverage {leverage}." {
+		t.Errorf("unexpected render %q", got)
+	}
+}
+
+func TestExperiment_AssignmentAndAudit(t *testing.T) {
+	control := NewPromptTemplate("decision", "v1")
+	candidate := NewPromptTemplate("decision", "v2")
+	exp := NewExperiment("decision-v2", control, candidate, 0.2)
+
+	candidates := 0
+	for i := 0; i < 1000; i++ {
+		variant := exp.Assign(fmt.Sprintf("trader-%d", i))
+		if variant != exp.Assign(fmt.Sprintf("trader-%d", i)) {
+			t.Fatal("assignment must be sticky per key")
+		}
+		if variant.Arm == ArmCandidate {
+			candidates++
+			exp.RecordOutcome(variant, 2)
+		} else {
+			exp.RecordOutcome(variant, 1)
+		}
+	}
+	if math.Abs(float64(candidates)/1000-0.2) > 0.05 {
+		t.Errorf("expected about 20%% candidates, got %d/1000", candidates)
+	}
+	results := exp.Results()
+	if results[0].Arm != ArmControl || results[0].Version != control.Version() || results[1].Assigned != 2*candidates || results[1].Mean() != 2 {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	sink := &memoryAuditSink{}
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(mock.ToHTTPClient()), WithAuditSink(sink)).(*Client)
+	variant := ExperimentVariant{Experiment: exp.Name, Arm: ArmCandidate, Template: candidate}
+	if _, err := client.CallMessages(variant.Context(context.Background()), candidate.Render(nil), "data"); err != nil {
+		t.Fatal(err)
+	}
+	record := sink.Records()[0]
+	if record.PromptName != "decision" || record.PromptVersion != candidate.Version() || record.Tags["experiment_arm"] != ArmCandidate {
+		t.Errorf("audit record should carry prompt version and arm, got %+v", record)
+	}
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Common provider failures, matched (errors.Is) by *APIError
+var (
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrModelNotFound  = errors.New("model not found")
+	ErrQuotaExhausted = errors.New("quota exhausted")
+	ErrRegionBlocked  = errors.New("region not supported")
+)
+
+// APIError non-200 response of a provider API
+//
+// Well-known failures are classified (Kind) and carry a remediation hint, so users see
+// "invalid API key ... hint: ..." instead of the provider's raw JSON body.
+type APIError struct {
+	Provider   string
+	Model      string
+	StatusCode int
+	Kind       error  // ErrInvalidAPIKey, ErrModelNotFound, ErrQuotaExhausted, ErrRegionBlocked or nil
+	Message    string // Provider's error message (raw body when it has none)
+	Hint       string // What to do about it ("" for unclassified errors)
+	Body       string // Raw response body
+}
+
+func (e *APIError) Error() string {
+	if e.Kind == nil {
+		return fmt.Sprintf("API returned error (status %d): %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("API returned error (status %d): %v: %s (hint: %s)", e.StatusCode, e.Kind, e.Message, e.Hint)
+}
+
+func (e *APIError) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+// apiErrorRule classifies provider errors by status code and message
+type apiErrorRule struct {
+	kind     error
+	statuses []int          // Matching status codes (nil = any)
+	pattern  *regexp.Regexp // Matched against the error body, case-insensitively
+}
+
+// apiErrorRules checked in order: region and quota before the generic 401/403/404 rules
+var apiErrorRules = []apiErrorRule{
+	{ErrRegionBlocked, nil, regexp.MustCompile(`(?i)unsupported_country_region_territory|location is not supported|not available in your (country|region)|region is not supported|country, region, or territory not supported`)},
+	{ErrQuotaExhausted, nil, regexp.MustCompile(`(?i)insufficient_quota|exceeded your current quota|insufficient (balance|credits?)|credit balance is too low|arrearage|billing_not_active|payment required`)},
+	{ErrQuotaExhausted, []int{402}, nil},
+	{ErrInvalidAPIKey, nil, regexp.MustCompile(`(?i)invalid[_ ]api[_ ]?key|incorrect api key|api key not valid|invalid x-api-key|invalid authentication|authentication_error|authentication fails|invalidapikey`)},
+	{ErrInvalidAPIKey, []int{401}, nil},
+	{ErrModelNotFound, nil, regexp.MustCompile(`(?i)model_not_found|model[^.]{0,80}(does not exist|not found|not exist|is not supported|not available)|(unknown|invalid) model|no such model`)},
+}
+
+// newAPIError builds error of a non-200 response of client's provider
+func (client *Client) newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{
+		Provider:   client.Provider,
+		Model:      client.Model,
+		StatusCode: statusCode,
+		Message:    providerErrorMessage(body),
+		Body:       string(body),
+	}
+	for _, rule := range apiErrorRules {
+		if rule.statuses != nil && !slices.Contains(rule.statuses, statusCode) {
+			continue
+		}
+		if rule.pattern != nil && !rule.pattern.Match(body) {
+			continue
+		}
+		e.Kind = rule.kind
+		break
+	}
+	e.Hint = apiErrorHint(e)
+	return e
+}
+
+// apiErrorHint remediation hint of a classified error
+func apiErrorHint(e *APIError) string {
+	provider := e.Provider
+	if provider == "" {
+		provider = "the provider"
+	}
+	switch e.Kind {
+	case ErrInvalidAPIKey:
+		return fmt.Sprintf("check the %s API key passed to WithAPIKey/SetAPIKey; keys are provider-specific and may have been revoked or rotated", provider)
+	case ErrModelNotFound:
+		return fmt.Sprintf("model %q is not available on %s; check the name set with WithModel (ListModels shows what the key can use)", e.Model, provider)
+	case ErrQuotaExhausted:
+		return fmt.Sprintf("top up the %s account or raise its spending limit; calls fail until the balance is restored", provider)
+	case ErrRegionBlocked:
+		return fmt.Sprintf("%s does not serve this server's region; route through a supported region (WithBaseURL to a gateway) or use another provider", provider)
+	}
+	return ""
+}
+
+// providerErrorMessage extracts the error message from a provider error body
+//
+// Handles OpenAI-style {"error":{"message":...}}, Anthropic {"type":"error","error":{...}},
+// Gemini's array-wrapped errors and DashScope {"code":...,"message":...}.
+func providerErrorMessage(body []byte) string {
+	type errorBody struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	var parsed errorBody
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		var list []errorBody
+		if json.Unmarshal(body, &list) != nil || len(list) == 0 {
+			return strings.TrimSpace(string(body))
+		}
+		parsed = list[0]
+	}
+
+	var nested struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(parsed.Error, &nested) == nil && nested.Message != "" {
+		return nested.Message
+	}
+	var text string
+	if json.Unmarshal(parsed.Error, &text) == nil && text != "" {
+		return text
+	}
+	if parsed.Message != "" {
+		return parsed.Message
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIError_Classification(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		status   int
+		body     string
+		kind     error
+		message  string
+	}{
+		{"openai key", ProviderOpenAI, 401, `{"error":{"message":"Incorrect API key provided: sk-abc***","type":"invalid_request_error","code":"invalid_api_key"}}`, ErrInvalidAPIKey, "Incorrect API key provided: sk-abc***"},
+		{"claude key", ProviderClaude, 401, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, ErrInvalidAPIKey, "invalid x-api-key"},
+		{"gemini key", ProviderGemini, 400, `[{"error":{"code":400,"message":"API key not valid. Please pass a valid API key.","status":"INVALID_ARGUMENT"}}]`, ErrInvalidAPIKey, "API key not valid. Please pass a valid API key."},
+		{"qwen key", ProviderQwen, 401, `{"code":"InvalidApiKey","message":"Invalid API-key provided."}`, ErrInvalidAPIKey, "Invalid API-key provided."},
+		{"model", ProviderOpenAI, 404, `{"error":{"message":"The model gpt-5-turbo does not exist or you do not have access to it.","code":"model_not_found"}}`, ErrModelNotFound, "The model gpt-5-turbo does not exist or you do not have access to it."},
+		{"deepseek balance", ProviderDeepSeek, 402, `{"error":{"message":"Insufficient Balance","type":"unknown_error"}}`, ErrQuotaExhausted, "Insufficient Balance"},
+		{"openai quota", ProviderOpenAI, 429, `{"error":{"message":"You exceeded your current quota, please check your plan and billing details.","code":"insufficient_quota"}}`, ErrQuotaExhausted, "You exceeded your current quota, please check your plan and billing details."},
+		{"region", ProviderOpenAI, 403, `{"error":{"code":"unsupported_country_region_territory","message":"Country, region, or territory not supported"}}`, ErrRegionBlocked, "Country, region, or territory not supported"},
+		{"gemini region", ProviderGemini, 400, `{"error":{"code":400,"message":"User location is not supported for the API use.","status":"FAILED_PRECONDITION"}}`, ErrRegionBlocked, "User location is not supported for the API use."},
+		{"rate limit", ProviderOpenAI, 429, `{"error":{"message":"Rate limit reached for requests"}}`, nil, "Rate limit reached for requests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{Provider: tt.provider, Model: "test-model"}
+			err := client.newAPIError(tt.status, []byte(tt.body))
+			if err.Kind != tt.kind || err.Message != tt.message {
+				t.Errorf("kind = %v, message = %q", err.Kind, err.Message)
+			}
+			if tt.kind != nil && (!errors.Is(err, tt.kind) || err.Hint == "" || strings.Contains(err.Error(), "{")) {
+				t.Errorf("expected readable hinted error, got %q", err.Error())
+			}
+			if tt.kind == nil && err.Error() != "API returned error (status 429): "+tt.body {
+				t.Errorf("unclassified errors keep the raw body, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestAPIError_FromCall(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body := `{"error":{"message":"Authentication Fails (no such user)","type":"authentication_error"}}`
+		return &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(bytes.NewBufferString(body)), Header: make(http.Header)}, nil
+	}
+	client := NewClient(WithHTTPClient(mockHTTP.ToHTTPClient()), WithAPIKey("sk-wrong"), WithLogger(NewNoopLogger()), WithMaxRetries(1))
+
+	_, err := client.CallWithMessages("sys", "hi")
+	var apiErr *APIError
+	if !errors.Is(err, ErrInvalidAPIKey) || !errors.As(err, &apiErr) || apiErr.Provider != ProviderDeepSeek {
+		t.Fatalf("expected invalid key error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "hint: check the deepseek API key") {
+		t.Errorf("missing hint in %q", err.Error())
+	}
+}
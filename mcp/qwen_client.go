@@ -65,7 +65,7 @@ func (qwenClient *QwenClient) SetAPIKey(apiKey string, customURL string, customM
 		qwenClient.logger.Infof("🔧 [MCP] Qwen API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
 	}
 	if customURL != "" {
-		qwenClient.BaseURL = customURL
+		qwenClient.setBaseURL(customURL)
 		qwenClient.logger.Infof("🔧 [MCP] Qwen using custom BaseURL: %s", customURL)
 	} else {
 		qwenClient.logger.Infof("🔧 [MCP] Qwen using default BaseURL: %s", qwenClient.BaseURL)
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter token bucket limiting outgoing requests
+//
+// A nil *RateLimiter never blocks, so callers don't need to check if limiting is enabled.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens added per second
+	burst  float64 // Bucket capacity
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates limiter allowing rps requests per second with bursts up to burst
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimit limits outgoing requests to rps per second (bursts up to burst)
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithRateLimit(5, 10))
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Config) {
+		c.RateLimiter = NewRateLimiter(rps, burst)
+	}
+}
+
+// Allow takes a token if one is available, without waiting
+func (l *RateLimiter) Allow() bool {
+	if l == nil || l.rate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	if l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+	return false
+}
+
+// refill adds tokens accrued since the last update (caller holds mu)
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	for {
+		l.mu.Lock()
+		l.refill(time.Now())
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
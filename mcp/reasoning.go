@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Reasoning effort levels for reasoning models
+const (
+	ReasoningEffortLow    = "low"
+	ReasoningEffortMedium = "medium"
+	ReasoningEffortHigh   = "high"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// WithRetainReasoning keeps inline <think> blocks in Response.Text
+//
+// By default reasoning models' <think>...</think> blocks are moved out of the
+// answer into Response.Reasoning so they never leak into parsed decisions or
+// downstream prompts.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithRetainReasoning(true))
+func WithRetainReasoning(retain bool) ClientOption {
+	return func(c *Config) {
+		c.RetainReasoning = retain
+	}
+}
+
+// applyReasoningParams adds reasoning request parameters in the provider's format
+func (client *Client) applyReasoningParams(requestBody map[string]any, req *Request) {
+	switch client.Provider {
+	case ProviderClaude:
+		if req.ThinkingBudget > 0 {
+			requestBody["thinking"] = map[string]any{
+				"type":          "enabled",
+				"budget_tokens": req.ThinkingBudget,
+			}
+			// Extended thinking doesn't allow modified temperature
+			delete(requestBody, "temperature")
+		}
+	case ProviderQwen:
+		if req.ThinkingBudget > 0 {
+			requestBody["enable_thinking"] = true
+			requestBody["thinking_budget"] = req.ThinkingBudget
+		}
+		if req.ReasoningEffort != "" {
+			requestBody["reasoning_effort"] = req.ReasoningEffort
+		}
+	default:
+		if req.ReasoningEffort != "" {
+			requestBody["reasoning_effort"] = req.ReasoningEffort
+		}
+	}
+}
+
+// parseReasoning extracts reasoning from provider response body
+//
+// Supports reasoning_content (DeepSeek, Qwen), reasoning (OpenRouter, vLLM) and
+// Anthropic thinking content blocks.
+func parseReasoning(body []byte) string {
+	var response struct {
+		Choices []struct {
+			Message struct {
+				ReasoningContent string `json:"reasoning_content"`
+				Reasoning        string `json:"reasoning"`
+			} `json:"message"`
+		} `json:"choices"`
+		Content []struct {
+			Type     string `json:"type"`
+			Thinking string `json:"thinking"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+
+	if len(response.Choices) > 0 {
+		message := response.Choices[0].Message
+		if message.ReasoningContent != "" {
+			return message.ReasoningContent
+		}
+		return message.Reasoning
+	}
+
+	var thinking []string
+	for _, content := range response.Content {
+		if content.Type == "thinking" && content.Thinking != "" {
+			thinking = append(thinking, content.Thinking)
+		}
+	}
+	return strings.Join(thinking, "\n\n")
+}
+
+// splitThinkBlocks removes <think>...</think> blocks from text, returning the
+// remaining answer and the joined block contents
+func splitThinkBlocks(text string) (answer, reasoning string) {
+	if !strings.Contains(text, thinkOpenTag) {
+		return text, ""
+	}
+
+	var (
+		rest     = text
+		kept     strings.Builder
+		thoughts []string
+	)
+	for {
+		start := strings.Index(rest, thinkOpenTag)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], thinkCloseTag)
+		if end < 0 {
+			// Unterminated block (e.g. truncated output): leave as-is
+			break
+		}
+		end += start
+		kept.WriteString(rest[:start])
+		thoughts = append(thoughts, strings.TrimSpace(rest[start+len(thinkOpenTag):end]))
+		rest = rest[end+len(thinkCloseTag):]
+	}
+	kept.WriteString(rest)
+
+	return strings.TrimSpace(kept.String()), strings.Join(thoughts, "\n\n")
+}
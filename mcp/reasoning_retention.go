@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReasoningRetention controls how model reasoning is kept in audit records
+//
+// Reasoning (reasoning fields or <think> blocks) is always kept out of Response.Text,
+// so it never flows into downstream prompts; this only governs the audit trail.
+type ReasoningRetention int
+
+const (
+	// ReasoningDiscard doesn't record reasoning (default)
+	ReasoningDiscard ReasoningRetention = iota
+	// ReasoningHashOnly records only the SHA-256 hash of the reasoning
+	ReasoningHashOnly
+	// ReasoningRetain records full reasoning, expiring after the retention period
+	ReasoningRetain
+)
+
+// WithReasoningRetention sets how model reasoning is recorded in audit records
+//
+// With ReasoningRetain the record carries ReasoningExpiresAt (retainFor 0 = forever);
+// SQLAuditSink.PurgeExpiredReasoning removes expired reasoning, file sinks should be
+// rotated accordingly.
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithAuditSink(sink),
+//       mcp.WithReasoningRetention(mcp.ReasoningRetain, 30*24*time.Hour),
+//   )
+func WithReasoningRetention(mode ReasoningRetention, retainFor time.Duration) ClientOption {
+	return func(c *Config) {
+		c.ReasoningRetention = mode
+		c.ReasoningRetainFor = retainFor
+	}
+}
+
+// applyReasoningRetention records reasoning in record according to the retention policy
+func (client *Client) applyReasoningRetention(record *AuditRecord, reasoning string) {
+	if reasoning == "" || client.config.ReasoningRetention == ReasoningDiscard {
+		return
+	}
+	record.ReasoningHash = hashText(reasoning)
+	if client.config.ReasoningRetention != ReasoningRetain {
+		return
+	}
+	record.Reasoning = reasoning
+	if retainFor := client.config.ReasoningRetainFor; retainFor > 0 {
+		expiresAt := record.Timestamp.Add(retainFor)
+		record.ReasoningExpiresAt = &expiresAt
+	}
+}
+
+// PurgeExpiredReasoning clears reasoning whose retention ended before now (hashes are kept)
+//
+// Usage example:
+//   removed, err := sink.PurgeExpiredReasoning(time.Now())
+func (s *SQLAuditSink) PurgeExpiredReasoning(now time.Time) (int64, error) {
+	result, err := s.db.Exec(`UPDATE ai_audit_log SET reasoning = NULL, reasoning_expires_at = NULL
+		WHERE reasoning_expires_at IS NOT NULL AND reasoning_expires_at < ?`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired reasoning: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// auditReasoning calls a client answering with a <think> block and returns its audit record
+func auditReasoning(t *testing.T, opts ...ClientOption) (AuditRecord, string) {
+	t.Helper()
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.Response = `{"choices":[{"message":{"content":"<think>funding is negative</think>buy"}}]}`
+
+	sink := &memoryAuditSink{}
+	client := NewClient(append([]ClientOption{
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithAuditSink(sink),
+	}, opts...)...)
+
+	answer, err := client.CallWithMessages("system", "should I buy?")
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	return sink.Records()[0], answer
+}
+
+func TestReasoningRetention_Modes(t *testing.T) {
+	record, answer := auditReasoning(t)
+	if answer != "buy" || record.Reasoning != "" || record.ReasoningHash != "" {
+		t.Errorf("reasoning should be discarded by default: %q %+v", answer, record)
+	}
+
+	record, _ = auditReasoning(t, WithReasoningRetention(ReasoningHashOnly, 0))
+	if record.Reasoning != "" || record.ReasoningHash != hashText("funding is negative") {
+		t.Errorf("expected hash only, got %+v", record)
+	}
+
+	record, _ = auditReasoning(t, WithReasoningRetention(ReasoningRetain, 24*time.Hour))
+	if record.Reasoning != "funding is negative" || record.ReasoningExpiresAt == nil ||
+		record.ReasoningExpiresAt.Sub(record.Timestamp) != 24*time.Hour {
+		t.Errorf("expected retained reasoning expiring in 24h, got %+v", record)
+	}
+}
+
+func TestSQLAuditSink_PurgeExpiredReasoning(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	sink, err := NewSQLAuditSink(db)
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	now := time.Now()
+	expired, live := now.Add(-time.Hour), now.Add(time.Hour)
+	for _, expiresAt := range []*time.Time{&expired, &live, nil} {
+		sink.Write(AuditRecord{Timestamp: now, Provider: "deepseek", Model: "m", Outcome: AuditOutcomeSuccess,
+			Reasoning: "thoughts", ReasoningHash: hashText("thoughts"), ReasoningExpiresAt: expiresAt})
+	}
+
+	removed, err := sink.PurgeExpiredReasoning(now)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected 1 purged record, got %d (err %v)", removed, err)
+	}
+	var kept, hashes int
+	db.QueryRow(`SELECT COUNT(reasoning), COUNT(reasoning_hash) FROM ai_audit_log`).Scan(&kept, &hashes)
+	if kept != 2 || hashes != 3 {
+		t.Errorf("expected 2 reasoning texts and 3 hashes kept, got %d / %d", kept, hashes)
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+// ============================================================
+// Test Reasoning / Thinking Support
+// ============================================================
+
+func TestSplitThinkBlocks(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		wantAnswer    string
+		wantReasoning string
+	}{
+		{"no block", "buy BTC", "buy BTC", ""},
+		{"leading block", "<think>trend is up</think>\n\nbuy BTC", "buy BTC", "trend is up"},
+		{"multiple blocks", "<think>a</think>x <think>b</think>y", "x y", "a\n\nb"},
+		{"unterminated", "<think>still thinking", "<think>still thinking", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer, reasoning := splitThinkBlocks(tt.text)
+			if answer != tt.wantAnswer || reasoning != tt.wantReasoning {
+				t.Errorf("got (%q, %q), want (%q, %q)", answer, reasoning, tt.wantAnswer, tt.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestParseReasoning(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"deepseek reasoning_content", `{"choices":[{"message":{"content":"hold","reasoning_content":"volume is low"}}]}`, "volume is low"},
+		{"openrouter reasoning", `{"choices":[{"message":{"content":"hold","reasoning":"spread is wide"}}]}`, "spread is wide"},
+		{"anthropic thinking", `{"content":[{"type":"thinking","thinking":"funding negative"},{"type":"text","text":"hold"}]}`, "funding negative"},
+		{"none", `{"choices":[{"message":{"content":"hold"}}]}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseReasoning([]byte(tt.body)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCall_SeparatesReasoning(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse(`<think>RSI oversold</think>buy`)
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	response, err := client.Call(context.Background(), NewRequestBuilder().WithUserPrompt("decide").MustBuild())
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if response.Text != "buy" || response.Reasoning != "RSI oversold" {
+		t.Errorf("expected text 'buy' and reasoning 'RSI oversold', got %+v", response)
+	}
+
+	// String API returns the answer without reasoning
+	text, _ := client.CallWithMessages("system", "decide")
+	if text != "buy" {
+		t.Errorf("CallWithMessages should strip reasoning, got %q", text)
+	}
+}
+
+func TestCall_RetainReasoning(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse(`<think>RSI oversold</think>buy`)
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithRetainReasoning(true),
+	)
+
+	text, _ := client.CallWithMessages("system", "decide")
+	if text != `<think>RSI oversold</think>buy` {
+		t.Errorf("reasoning should be retained, got %q", text)
+	}
+}
+
+func TestReasoningParams_PerProvider(t *testing.T) {
+	request := NewRequestBuilder().
+		WithUserPrompt("decide").
+		WithReasoningEffort(ReasoningEffortHigh).
+		WithThinkingBudget(2048).
+		MustBuild()
+
+	openai := NewOpenAIClientWithOptions(WithLogger(NewNoopLogger())).(*OpenAIClient)
+	body := openai.buildRequestBodyFromRequest(request)
+	if body["reasoning_effort"] != ReasoningEffortHigh {
+		t.Errorf("OpenAI should receive reasoning_effort, got %v", body["reasoning_effort"])
+	}
+
+	claude := NewClaudeClientWithOptions(WithLogger(NewNoopLogger())).(*ClaudeClient)
+	body = claude.buildRequestBodyFromRequest(request)
+	thinking, _ := body["thinking"].(map[string]any)
+	if thinking["budget_tokens"] != 2048 {
+		t.Errorf("Claude should receive thinking budget, got %v", body["thinking"])
+	}
+	if _, ok := body["temperature"]; ok {
+		t.Error("temperature must be omitted with extended thinking")
+	}
+
+	qwen := NewQwenClientWithOptions(WithLogger(NewNoopLogger())).(*QwenClient)
+	body = qwen.buildRequestBodyFromRequest(request)
+	if body["enable_thinking"] != true || body["thinking_budget"] != 2048 {
+		t.Errorf("Qwen should receive enable_thinking/thinking_budget, got %v", body)
+	}
+}
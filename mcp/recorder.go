@@ -0,0 +1,333 @@
+package mcp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RecorderMode recorder working mode
+type RecorderMode int
+
+const (
+	// RecorderModeRecord sends requests upstream and saves every pair to a cassette
+	RecorderModeRecord RecorderMode = iota
+	// RecorderModeReplay serves responses from cassettes only (no network access)
+	RecorderModeReplay
+	// RecorderModeAuto replays when a cassette exists, otherwise records it
+	RecorderModeAuto
+)
+
+// redactedValue placeholder written instead of secrets
+const redactedValue = "[REDACTED]"
+
+var (
+	// ErrCassetteNotFound is returned in replay mode when no cassette matches the request
+	ErrCassetteNotFound = errors.New("cassette not found")
+
+	// secretHeaders headers stripped before a cassette is written
+	secretHeaders = []string{
+		"Authorization",
+		"X-Api-Key",
+		"Api-Key",
+		"Cookie",
+		"Set-Cookie",
+		"Proxy-Authorization",
+	}
+
+	// secretQueryParams query parameters stripped before a cassette is written
+	secretQueryParams = []string{"key", "api_key", "apikey", "access_token", "token"}
+)
+
+// Cassette one recorded request/response pair (secrets stripped)
+type Cassette struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest recorded request
+type CassetteRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	Body    string      `json:"body"`
+}
+
+// CassetteResponse recorded response
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers"`
+	Body       string      `json:"body"`
+}
+
+// Recorder records request/response pairs to cassette files and replays them
+//
+// Cassettes are stored as one JSON file per distinct request, named by a hash of
+// method, redacted URL and body, so identical requests always map to the same file
+// and replay is deterministic. Response bodies are teed to the cassette while the
+// caller reads them, so streams are not delayed; a cassette is written once the body
+// has been read to the end (interrupted streams are not recorded).
+//
+// Credentials are stripped, but prompts and completions are stored in plain text:
+// set RedactPrompts to keep only a hash of request bodies (replay still matches, since
+// cassettes are named by the full body hash), and use WithContentEncryption to
+// encrypt the stored bodies.
+type Recorder struct {
+	Dir           string
+	Mode          RecorderMode
+	RedactPrompts bool // Store request bodies as a hash instead of the prompt
+
+	mu sync.Mutex
+}
+
+// NewRecorder creates recorder storing cassettes in dir
+func NewRecorder(dir string, mode RecorderMode) *Recorder {
+	return &Recorder{Dir: dir, Mode: mode}
+}
+
+// WithRecorder records every request/response pair to cassette files in dir
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithRecorder("testdata/cassettes"))
+func WithRecorder(dir string) ClientOption {
+	return func(c *Config) {
+		c.Recorder = NewRecorder(dir, RecorderModeRecord)
+	}
+}
+
+// WithCassetteRecorder records or replays with a configured recorder
+//
+// Usage example:
+//   recorder := mcp.NewRecorder("testdata/cassettes", mcp.RecorderModeAuto)
+//   recorder.RedactPrompts = true
+//   client := mcp.NewClient(mcp.WithCassetteRecorder(recorder))
+func WithCassetteRecorder(recorder *Recorder) ClientOption {
+	return func(c *Config) {
+		c.Recorder = recorder
+	}
+}
+
+// WithReplay serves responses from cassette files in dir instead of the network
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithReplay("testdata/cassettes"))
+func WithReplay(dir string) ClientOption {
+	return func(c *Config) {
+		c.Recorder = NewRecorder(dir, RecorderModeReplay)
+	}
+}
+
+// wrap wraps next transport with recording/replaying behaviour (cassette bodies encrypted with cipher if set)
+func (r *Recorder) wrap(next http.RoundTripper, cipher ContentCipher) http.RoundTripper {
+	return &recorderTransport{recorder: r, next: next, cipher: cipher}
+}
+
+// cassettePath returns cassette file path for request
+func (r *Recorder) cassettePath(method, redactedURL string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(redactedURL))
+	h.Write([]byte{0})
+	h.Write(body)
+	return filepath.Join(r.Dir, hex.EncodeToString(h.Sum(nil))[:16]+".json")
+}
+
+// load reads cassette from file
+func (r *Recorder) load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// save writes cassette to file
+func (r *Recorder) save(path string, cassette *Cassette) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette dir: %w", err)
+	}
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize cassette: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// recorderTransport http.RoundTripper implementing record/replay
+type recorderTransport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+	cipher   ContentCipher
+}
+
+func (t *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("recorder failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	redactedURL := redactURL(req.URL)
+	path := t.recorder.cassettePath(req.Method, redactedURL, body)
+
+	if t.recorder.Mode != RecorderModeRecord {
+		cassette, err := t.recorder.load(path)
+		if err == nil {
+			if err := cassette.Decrypt(t.cipher); err != nil {
+				return nil, fmt.Errorf("cassette %s: %w", path, err)
+			}
+			return cassette.toResponse(req), nil
+		}
+		if t.recorder.Mode == RecorderModeReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrCassetteNotFound, req.Method, redactedURL)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBody := string(body)
+	if t.recorder.RedactPrompts && len(body) > 0 {
+		sum := sha256.Sum256(body)
+		requestBody = redactedValue + " sha256:" + hex.EncodeToString(sum[:])
+	}
+	cassette := &Cassette{
+		Request: CassetteRequest{
+			Method:  req.Method,
+			URL:     redactedURL,
+			Headers: redactHeaders(req.Header),
+			Body:    requestBody,
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+		},
+	}
+	resp.Body = &recordingBody{ReadCloser: resp.Body, save: func(respBody []byte) error {
+		cassette.Response.Body = string(respBody)
+		if err := cassette.encrypt(t.cipher); err != nil {
+			return err
+		}
+		return t.recorder.save(path, cassette)
+	}}
+	return resp, nil
+}
+
+// recordingBody response body copying what the caller reads, saved at EOF
+type recordingBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	save  func(body []byte) error
+	saved bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if errors.Is(err, io.EOF) && !b.saved {
+		b.saved = true
+		// A cassette that can't be saved fails the call, like any recording error
+		if saveErr := b.save(b.buf.Bytes()); saveErr != nil {
+			return n, saveErr
+		}
+	}
+	return n, err
+}
+
+// encrypt encrypts request and response bodies of cassette (nil cipher = unchanged)
+func (c *Cassette) encrypt(cipher ContentCipher) error {
+	for _, body := range []*string{&c.Request.Body, &c.Response.Body} {
+		value, err := encryptString(cipher, *body)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt cassette: %w", err)
+		}
+		*body = value
+	}
+	return nil
+}
+
+// Decrypt decrypts bodies of a cassette recorded with WithContentEncryption
+//
+// Cassettes recorded without encryption are left unchanged.
+func (c *Cassette) Decrypt(cipher ContentCipher) error {
+	for _, body := range []*string{&c.Request.Body, &c.Response.Body} {
+		value, err := decryptString(cipher, *body)
+		if err != nil {
+			return err
+		}
+		*body = value
+	}
+	return nil
+}
+
+// toResponse converts cassette to http.Response
+func (c *Cassette) toResponse(req *http.Request) *http.Response {
+	headers := c.Response.Headers
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode: c.Response.StatusCode,
+		Status:     fmt.Sprintf("%d %s", c.Response.StatusCode, http.StatusText(c.Response.StatusCode)),
+		Header:     headers.Clone(),
+		Body:       io.NopCloser(strings.NewReader(c.Response.Body)),
+		Request:    req,
+	}
+}
+
+// redactHeaders returns a copy of headers with secrets replaced
+func redactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	if redacted == nil {
+		return nil
+	}
+	for _, name := range secretHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// redactURL returns URL string with secret query parameters replaced
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	changed := false
+	for _, name := range secretQueryParams {
+		if query.Has(name) {
+			query.Set(name, redactedValue)
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	redacted.User = nil
+	return redacted.String()
+}
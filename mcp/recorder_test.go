@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test Recorder (VCR mode)
+// ============================================================
+
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("recorded answer")
+
+	recordClient := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("sk-secret-key-123456"),
+		WithBaseURL("https://api.test.com"),
+		WithRecorder(dir),
+	)
+
+	result, err := recordClient.CallWithMessages("system", "user")
+	if err != nil {
+		t.Fatalf("record call should not error: %v", err)
+	}
+	if result != "recorded answer" {
+		t.Errorf("expected 'recorded answer', got '%s'", result)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 cassette, got %d", len(files))
+	}
+
+	data, _ := os.ReadFile(dir + "/" + files[0].Name())
+	if strings.Contains(string(data), "sk-secret-key-123456") {
+		t.Error("cassette should not contain API key")
+	}
+	if !strings.Contains(string(data), redactedValue) {
+		t.Error("cassette should contain redacted placeholder")
+	}
+
+	// Replay with a transport that always fails: response must come from cassette
+	offline := NewMockHTTPClient()
+	offline.SetNetworkError(errors.New("network disabled"))
+
+	replayClient := NewClient(
+		WithHTTPClient(offline.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("another-key-000000"),
+		WithBaseURL("https://api.test.com"),
+		WithReplay(dir),
+	)
+
+	result, err = replayClient.CallWithMessages("system", "user")
+	if err != nil {
+		t.Fatalf("replay call should not error: %v", err)
+	}
+	if result != "recorded answer" {
+		t.Errorf("expected 'recorded answer', got '%s'", result)
+	}
+	if len(offline.GetRequests()) != 0 {
+		t.Error("replay mode should not hit the network")
+	}
+}
+
+func TestRecorder_ReplayMissingCassette(t *testing.T) {
+	client := NewClient(
+		WithHTTPClient(NewMockHTTPClient().ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+		WithAPIKey("test-key"),
+		WithMaxRetries(1),
+		WithReplay(t.TempDir()),
+	)
+
+	_, err := client.CallWithMessages("system", "user")
+	if err == nil || !errors.Is(err, ErrCassetteNotFound) {
+		t.Errorf("expected ErrCassetteNotFound, got %v", err)
+	}
+}
+
+func TestRecorder_NoRecorderKeepsHTTPClient(t *testing.T) {
+	httpClient := NewMockHTTPClient().ToHTTPClient()
+	client := NewClient(WithHTTPClient(httpClient)).(*Client)
+
+	if client.httpClient != httpClient {
+		t.Error("httpClient should be unchanged when no transport feature is enabled")
+	}
+}
+
+func TestRecorder_TeesStreamsAndRedactsPrompts(t *testing.T) {
+	dir := t.TempDir()
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: {\"choices\":[{\"delta\":{\"content\":\"buy \"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-release
+		io.WriteString(w, "data: {\"choices\":[{\"delta\":{\"content\":\"now\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(dir, RecorderModeAuto)
+	recorder.RedactPrompts = true
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL(server.URL),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithCassetteRecorder(recorder),
+	).(*Client)
+	req := NewRequestBuilder().WithUserPrompt("secret strategy").MustBuild()
+
+	// The first delta arrives while the server still holds the stream open
+	response, err := client.CallStream(context.Background(), req, func(event StreamEvent) error {
+		if event.Type == StreamTextDelta && event.Text == "buy " {
+			close(release)
+		}
+		return nil
+	})
+	if err != nil || response.Text != "buy now" {
+		t.Fatalf("expected 'buy now', got %v (%v)", response, err)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 cassette, got %d", len(files))
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if strings.Contains(string(data), "secret strategy") || !strings.Contains(string(data), "[DONE]") {
+		t.Errorf("cassette should hold the full stream without the prompt: %s", data)
+	}
+
+	// Replay still matches the redacted cassette
+	server.Close()
+	response, err = client.CallStream(context.Background(), req, func(StreamEvent) error { return nil })
+	if err != nil || response.Text != "buy now" {
+		t.Errorf("replay: expected 'buy now', got %v (%v)", response, err)
+	}
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIsRefusal(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *Response
+		want     bool
+	}{
+		{"answer", &Response{Text: `{"action":"wait"}`, FinishReason: FinishReasonStop}, false},
+		{"content filter", &Response{FinishReason: FinishReasonContentFilter}, true},
+		{"refusal field", &Response{Raw: []byte(`{"choices":[{"message":{"content":null,"refusal":"I can't do that."}}]}`)}, true},
+		{"refusal text", &Response{Text: "I’m sorry, but I can’t provide trading advice."}, true},
+		{"chinese refusal", &Response{Text: "抱歉，我无法提供投资建议。"}, true},
+		{"pattern late in answer", &Response{Text: strings.Repeat("Analysis of BTC trend. ", 20) + "I cannot provide guarantees."}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRefusal(tt.response); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// refusingClient answers only prompts containing marker, refusing the others
+func refusingClient(marker string, fallback AIClient) *Client {
+	mock := NewMockHTTPClient()
+	mock.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		answer := "I'm sorry, but I can't help with that."
+		if strings.Contains(string(body), marker) {
+			answer = "open_long"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"choices":[{"message":{"content":"` + answer + `"},"finish_reason":"stop"}]}`)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	return NewClient(WithHTTPClient(mock.ToHTTPClient()), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()),
+		WithRefusalRetry("Research only: {prompt}", fallback)).(*Client)
+}
+
+func TestRefusalRetry_Rephrase(t *testing.T) {
+	client := refusingClient("Research only:", nil)
+	answer, err := client.CallWithMessages("system", "decide")
+	if err != nil || answer != "open_long" {
+		t.Fatalf("rephrased request should be answered, got %q (%v)", answer, err)
+	}
+
+	response, err := client.Call(context.Background(), NewRequestBuilder().WithSystemPrompt("system").WithUserPrompt("decide").MustBuild())
+	if err != nil || response.Text != "open_long" {
+		t.Fatalf("rephrased request should be answered, got %v (%v)", response, err)
+	}
+}
+
+func TestRefusalRetry_FallbackAndError(t *testing.T) {
+	fallback := &stubClient{answer: "close_long"}
+	client := refusingClient("never", fallback)
+	answer, err := client.CallWithMessages("system", "decide")
+	if err != nil || answer != "close_long" {
+		t.Fatalf("fallback should answer, got %q (%v)", answer, err)
+	}
+	if len(fallback.prompts) != 1 || fallback.prompts[0] != "decide" {
+		t.Errorf("fallback should get the original prompt, got %v", fallback.prompts)
+	}
+
+	client = refusingClient("never", &stubClient{answer: "I cannot assist with this request."})
+	_, err = client.CallWithMessages("system", "decide")
+	var refusal *RefusalError
+	if !errors.Is(err, ErrRefusal) || !errors.As(err, &refusal) {
+		t.Fatalf("expected RefusalError, got %v", err)
+	}
+	if refusal.Attempts != 3 || refusal.Text != "I'm sorry, but I can't help with that." {
+		t.Errorf("unexpected refusal %+v", refusal)
+	}
+}
@@ -0,0 +1,416 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// replHelp slash-commands understood by RunREPL
+const replHelp = `Commands:
+  /model <name>        switch model ("" = client default)
+  /temperature <0-2>   set temperature ("off" = client default)
+  /max_tokens <n>      set max output tokens (0 = client default)
+  /top_p <0-1>         set top-p ("off" = client default)
+  /system <prompt>     replace system prompt ("" = none)
+  /stream on|off       toggle streaming output
+  /reasoning on|off    toggle printing model reasoning
+  /params              show current settings
+  /history             show conversation
+  /undo                drop the last exchange
+  /clear               start a new conversation (system prompt kept)
+  /save <path>         export transcript (.json = JSON, otherwise Markdown)
+  /exit                leave (also Ctrl-D)
+End a line with \ to continue the message on the next line.`
+
+// REPLOptions terminal chat loop settings (zero values use defaults)
+type REPLOptions struct {
+	Context        context.Context // Cancels the session (nil = context.Background())
+	In             io.Reader       // Input (nil = os.Stdin)
+	Out            io.Writer       // Output (nil = os.Stdout)
+	SystemPrompt   string          // Initial system prompt ("" = none)
+	Prompt         string          // Input prompt ("" = "> ")
+	NoStream       bool            // Print whole answers instead of streaming them
+	ShowReasoning  bool            // Print model reasoning before the answer
+	TranscriptPath string          // Transcript written when the session ends ("" = only /save)
+}
+
+// replSession state of a RunREPL session
+type replSession struct {
+	client AIClient
+	opts   REPLOptions
+	out    io.Writer
+
+	system      string
+	history     []Message
+	model       string
+	temperature *float64
+	maxTokens   *int
+	topP        *float64
+	stream      bool
+	reasoning   bool
+}
+
+// RunREPL runs an interactive chat with client in the terminal, for debugging prompts
+//
+// Every line is sent as a user message with the conversation so far; answers are
+// streamed as they arrive when the client supports streaming. Slash-commands switch
+// model and sampling parameters mid-session, edit the history and export the
+// transcript (see /help). Returns nil when input ends or /exit is entered; failed
+// calls are reported and the session continues.
+//
+// Usage example:
+//   client := mcp.NewDeepSeekClientWithOptions(mcp.WithAPIKey(os.Getenv("DEEPSEEK_API_KEY")))
+//   err := mcp.RunREPL(client, mcp.REPLOptions{
+//       SystemPrompt:   "You are a crypto trading assistant.",
+//       TranscriptPath: "debug/session.md",
+//   })
+func RunREPL(client AIClient, opts REPLOptions) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	in := opts.In
+	if in == nil {
+		in = os.Stdin
+	}
+	s := &replSession{
+		client:    client,
+		opts:      opts,
+		out:       opts.Out,
+		system:    opts.SystemPrompt,
+		stream:    !opts.NoStream,
+		reasoning: opts.ShowReasoning,
+	}
+	if s.out == nil {
+		s.out = os.Stdout
+	}
+	prompt := opts.Prompt
+	if prompt == "" {
+		prompt = "> "
+	}
+
+	fmt.Fprintf(s.out, "Chatting with %s. Type /help for commands.\n", s.modelName())
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64<<10), 4<<20)
+	var input strings.Builder
+	for {
+		if input.Len() == 0 {
+			fmt.Fprint(s.out, prompt)
+		} else {
+			fmt.Fprint(s.out, "… ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := scanner.Text()
+		if strings.HasSuffix(line, `\`) {
+			input.WriteString(strings.TrimSuffix(line, `\`) + "\n")
+			continue
+		}
+		input.WriteString(line)
+		text := strings.TrimSpace(input.String())
+		input.Reset()
+
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(text, "/"):
+			if s.command(text) {
+				return s.finish()
+			}
+		default:
+			s.send(ctx, text)
+		}
+		if ctx.Err() != nil {
+			return errors.Join(ctx.Err(), s.finish())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Join(fmt.Errorf("failed to read input: %w", err), s.finish())
+	}
+	fmt.Fprintln(s.out)
+	return s.finish()
+}
+
+// finish writes the transcript configured in REPLOptions
+func (s *replSession) finish() error {
+	if s.opts.TranscriptPath == "" || len(s.history) == 0 {
+		return nil
+	}
+	if err := s.save(s.opts.TranscriptPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Transcript saved to %s\n", s.opts.TranscriptPath)
+	return nil
+}
+
+// command handles a slash-command, reporting whether the session should end
+func (s *replSession) command(line string) bool {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	var err error
+	switch name {
+	case "/exit", "/quit":
+		return true
+	case "/help":
+		fmt.Fprintln(s.out, replHelp)
+	case "/model":
+		s.model = arg
+		fmt.Fprintf(s.out, "Model: %s\n", s.modelName())
+	case "/temperature":
+		var value *float64
+		if value, err = parseOptionalFloat(arg, 0, 2); err == nil {
+			s.temperature = value
+		}
+	case "/top_p":
+		var value *float64
+		if value, err = parseOptionalFloat(arg, 0, 1); err == nil {
+			s.topP = value
+		}
+	case "/max_tokens":
+		n, convErr := strconv.Atoi(arg)
+		switch {
+		case convErr != nil || n < 0:
+			err = fmt.Errorf("expected a token count, got %q", arg)
+		case n == 0:
+			s.maxTokens = nil
+		default:
+			s.maxTokens = &n
+		}
+	case "/system":
+		s.system = arg
+	case "/stream":
+		var on bool
+		if on, err = parseOnOff(arg); err == nil {
+			s.stream = on
+		}
+	case "/reasoning":
+		var on bool
+		if on, err = parseOnOff(arg); err == nil {
+			s.reasoning = on
+		}
+	case "/params":
+		s.printParams()
+	case "/history":
+		for _, msg := range s.messages() {
+			fmt.Fprintf(s.out, "[%s] %s\n", msg.Role, msg.Content)
+		}
+	case "/undo":
+		// Drop back to before the last user message
+		for i := len(s.history) - 1; i >= 0; i-- {
+			if s.history[i].Role == "user" {
+				s.history = s.history[:i]
+				break
+			}
+		}
+		fmt.Fprintf(s.out, "%d messages in history\n", len(s.history))
+	case "/clear":
+		s.history = nil
+		fmt.Fprintln(s.out, "Conversation cleared")
+	case "/save":
+		if arg == "" {
+			err = errors.New("usage: /save <path>")
+		} else if err = s.save(arg); err == nil {
+			fmt.Fprintf(s.out, "Transcript saved to %s\n", arg)
+		}
+	default:
+		err = fmt.Errorf("unknown command %s (see /help)", name)
+	}
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+	}
+	return false
+}
+
+// send sends text with the conversation so far and prints the answer
+func (s *replSession) send(ctx context.Context, text string) {
+	s.history = append(s.history, NewUserMessage(text))
+	req := &Request{
+		Model:       s.model,
+		Messages:    s.messages(),
+		Temperature: s.temperature,
+		MaxTokens:   s.maxTokens,
+		TopP:        s.topP,
+	}
+
+	start := time.Now()
+	response, err := s.call(ctx, req)
+	if err != nil {
+		// The failed message is dropped so it can be edited and sent again
+		s.history = s.history[:len(s.history)-1]
+		fmt.Fprintf(s.out, "error: %v\n", err)
+		return
+	}
+	s.history = append(s.history, NewAssistantMessage(response.Text))
+
+	usage := ""
+	if response.Usage.TotalTokens > 0 {
+		usage = fmt.Sprintf(", %d+%d tokens", response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	}
+	fmt.Fprintf(s.out, "[%s, %s%s]\n", response.Model, time.Since(start).Round(time.Millisecond), usage)
+}
+
+// call performs the request, streaming output when enabled and supported
+func (s *replSession) call(ctx context.Context, req *Request) (*Response, error) {
+	streamer, canStream := s.client.(interface {
+		CallStream(ctx context.Context, req *Request, onEvent func(StreamEvent) error) (*Response, error)
+	})
+	if s.stream && canStream {
+		inReasoning := false
+		// Printed output can't be taken back, so streams resume rather than restart
+		response, err := streamer.CallStream(withAppendOnlyStream(ctx), req, func(event StreamEvent) error {
+			switch {
+			case event.Type == StreamRestarted:
+				return ErrStreamNotRestartable
+			case event.Type == StreamReasoningDelta && s.reasoning:
+				if !inReasoning {
+					fmt.Fprint(s.out, "(thinking) ")
+					inReasoning = true
+				}
+				fmt.Fprint(s.out, event.Text)
+			case event.Type == StreamTextDelta:
+				if inReasoning {
+					fmt.Fprint(s.out, "\n\n")
+					inReasoning = false
+				}
+				fmt.Fprint(s.out, event.Text)
+			case event.Type == StreamToolCallComplete:
+				fmt.Fprintf(s.out, "\n(tool call) %s(%s)", event.ToolCall.Function.Name, event.ToolCall.Function.Arguments)
+			}
+			return nil
+		})
+		fmt.Fprintln(s.out)
+		return response, err
+	}
+
+	var response *Response
+	var err error
+	if caller, ok := s.client.(interface {
+		Call(ctx context.Context, req *Request) (*Response, error)
+	}); ok {
+		response, err = caller.Call(ctx, req)
+	} else {
+		var text string
+		if text, err = callRequest(ctx, s.client, req); err == nil {
+			response = &Response{Text: text, Model: s.modelName()}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.reasoning && response.Reasoning != "" {
+		fmt.Fprintf(s.out, "(thinking) %s\n\n", response.Reasoning)
+	}
+	fmt.Fprintln(s.out, response.Text)
+	return response, nil
+}
+
+// messages returns the conversation with the system prompt first
+func (s *replSession) messages() []Message {
+	messages := make([]Message, 0, len(s.history)+1)
+	if s.system != "" {
+		messages = append(messages, NewSystemMessage(s.system))
+	}
+	return append(messages, s.history...)
+}
+
+// modelName model requests go to
+func (s *replSession) modelName() string {
+	if s.model != "" {
+		return s.model
+	}
+	if c, ok := s.client.(interface{ base() *Client }); ok {
+		return c.base().Model
+	}
+	return "default model"
+}
+
+func (s *replSession) printParams() {
+	optional := func(value any, isSet bool) string {
+		if !isSet {
+			return "client default"
+		}
+		return fmt.Sprint(value)
+	}
+	fmt.Fprintf(s.out, "model: %s\n", s.modelName())
+	fmt.Fprintf(s.out, "temperature: %s\n", optional(deref(s.temperature), s.temperature != nil))
+	fmt.Fprintf(s.out, "max_tokens: %s\n", optional(deref(s.maxTokens), s.maxTokens != nil))
+	fmt.Fprintf(s.out, "top_p: %s\n", optional(deref(s.topP), s.topP != nil))
+	fmt.Fprintf(s.out, "stream: %v, reasoning: %v\n", s.stream, s.reasoning)
+	fmt.Fprintf(s.out, "system: %q\n", s.system)
+}
+
+// save writes the transcript to path: JSON for .json files, Markdown otherwise
+func (s *replSession) save(path string) error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var err error
+		data, err = json.MarshalIndent(map[string]any{
+			"model":       s.modelName(),
+			"temperature": s.temperature,
+			"max_tokens":  s.maxTokens,
+			"top_p":       s.topP,
+			"saved_at":    time.Now().UTC().Format(time.RFC3339),
+			"messages":    s.messages(),
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# AI session transcript\n\n- Model: %s\n- Saved: %s\n", s.modelName(), time.Now().UTC().Format(time.RFC3339))
+		for _, msg := range s.messages() {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", strings.ToUpper(msg.Role[:1])+msg.Role[1:], msg.Content)
+		}
+		data = []byte(b.String())
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create transcript directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return nil
+}
+
+// parseOptionalFloat parses a value in [lo, hi]; "off" or "" clears it
+func parseOptionalFloat(arg string, lo, hi float64) (*float64, error) {
+	if arg == "" || arg == "off" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil || value < lo || value > hi {
+		return nil, fmt.Errorf("expected a number between %g and %g, got %q", lo, hi, arg)
+	}
+	return &value, nil
+}
+
+func parseOnOff(arg string) (bool, error) {
+	switch arg {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected on or off, got %q", arg)
+}
+
+// deref value of p (zero value for nil)
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test RunREPL
+// ============================================================
+
+func TestRunREPL_CommandsAndTranscript(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithHTTPClient(captureBodyClient(`{"model":"deepseek-reasoner","choices":[{"message":{"content":"Hold."},"finish_reason":"stop"}]}`, &bodies)),
+	)
+	transcript := filepath.Join(t.TempDir(), "session.json")
+	input := strings.Join([]string{
+		"/model deepseek-reasoner",
+		"/temperature 0.3",
+		"/temperature 7",
+		"/bogus",
+		`BTC \`,
+		"outlook?",
+		"/exit",
+		"never sent",
+	}, "\n")
+	var out strings.Builder
+
+	err := RunREPL(client, REPLOptions{
+		In:             strings.NewReader(input),
+		Out:            &out,
+		SystemPrompt:   "You are a trader.",
+		NoStream:       true,
+		TranscriptPath: transcript,
+	})
+	if err != nil {
+		t.Fatalf("RunREPL: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("expected one request, got %d", len(bodies))
+	}
+	if bodies[0]["model"] != "deepseek-reasoner" || bodies[0]["temperature"] != 0.3 {
+		t.Errorf("slash-commands must apply to requests, got %v", bodies[0])
+	}
+	messages := bodies[0]["messages"].([]any)
+	if user := messages[1].(map[string]any)["content"]; user != "BTC \noutlook?" {
+		t.Errorf("continued lines must form one message, got %q", user)
+	}
+	for _, want := range []string{"Hold.", "expected a number between 0 and 2", "unknown command /bogus"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	data, err := os.ReadFile(transcript)
+	if err != nil {
+		t.Fatalf("transcript not written: %v", err)
+	}
+	var saved struct {
+		Model    string    `json:"model"`
+		Messages []Message `json:"messages"`
+	}
+	json.Unmarshal(data, &saved)
+	if saved.Model != "deepseek-reasoner" || len(saved.Messages) != 3 || saved.Messages[2].Content != "Hold." {
+		t.Errorf("unexpected transcript %s", data)
+	}
+}
+
+func TestRunREPL_StreamsAndUndo(t *testing.T) {
+	client := NewClient(
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithHTTPClient(sseClient(sseLines(`{"choices":[{"delta":{"content":"Buy "}}]}`, `{"choices":[{"delta":{"content":"dips."},"finish_reason":"stop"}]}`)+"data: [DONE]\n\n", nil)),
+	)
+	markdown := filepath.Join(t.TempDir(), "session.md")
+	var out strings.Builder
+
+	err := RunREPL(client, REPLOptions{
+		In:  strings.NewReader("first\nsecond\n/undo\n/save " + markdown + "\n"),
+		Out: &out,
+	})
+	if err != nil {
+		t.Fatalf("RunREPL: %v", err)
+	}
+	if strings.Count(out.String(), "Buy dips.") != 2 || !strings.Contains(out.String(), "2 messages in history") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+	data, _ := os.ReadFile(markdown)
+	if !strings.Contains(string(data), "## User\n\nfirst") || strings.Contains(string(data), "second") {
+		t.Errorf("unexpected Markdown transcript:\n%s", data)
+	}
+}
+
+func TestRunREPL_StreamRetryDoesNotRepeatOutput(t *testing.T) {
+	var sent []map[string]any
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithMaxRetries(3), WithRetryWaitBase(time.Millisecond),
+		WithStreamRetry(StreamRetryRestart),
+		WithHTTPClient(sequenceSSEClient(&sent,
+			sseLines(`{"choices":[{"delta":{"content":"The trend is "}}]}`),
+			sseLines(`{"choices":[{"delta":{"content":"bullish."},"finish_reason":"stop"}]}`)+"data: [DONE]\n\n",
+		)))
+	var out strings.Builder
+	if err := RunREPL(client, REPLOptions{In: strings.NewReader("outlook?\n"), Out: &out}); err != nil {
+		t.Fatalf("RunREPL: %v", err)
+	}
+	if strings.Count(out.String(), "The trend is") != 1 || !strings.Contains(out.String(), "The trend is bullish.") {
+		t.Errorf("printed output must not repeat:\n%s", out.String())
+	}
+}
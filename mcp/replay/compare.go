@@ -0,0 +1,75 @@
+package replay
+
+import (
+	"strconv"
+	"strings"
+
+	"nofx/mcp"
+)
+
+// FieldDiff one field that differs between baseline and candidate answers
+type FieldDiff struct {
+	Field     string `json:"field"`
+	Baseline  string `json:"baseline"`
+	Candidate string `json:"candidate"`
+}
+
+// Comparison result of comparing a candidate answer with the baseline
+type Comparison struct {
+	Agree bool        // Answers lead to the same decision
+	Diffs []FieldDiff // Differing fields (may be non-empty when Agree, e.g. size changes)
+}
+
+// Comparator compares candidate answer with baseline answer
+type Comparator func(baseline, candidate string) Comparison
+
+// CompareText agrees when answers are equal ignoring surrounding whitespace and case
+func CompareText(baseline, candidate string) Comparison {
+	if strings.EqualFold(strings.TrimSpace(baseline), strings.TrimSpace(candidate)) {
+		return Comparison{Agree: true}
+	}
+	return Comparison{Diffs: []FieldDiff{{Field: "text", Baseline: baseline, Candidate: candidate}}}
+}
+
+// CompareTradeDecisions compares answers parsed as mcp.TradeDecision
+//
+// Decisions agree when action and symbol match; differing size, leverage, stops,
+// confidence and risk flags are reported as diffs without breaking agreement.
+func CompareTradeDecisions(baseline, candidate string) Comparison {
+	base, baseErr := mcp.ParseTradeDecision(baseline)
+	cand, candErr := mcp.ParseTradeDecision(candidate)
+	if baseErr != nil || candErr != nil {
+		comparison := Comparison{}
+		if baseErr != nil && candErr != nil {
+			// Neither is a decision: fall back to text comparison
+			return CompareText(baseline, candidate)
+		}
+		comparison.Diffs = []FieldDiff{{Field: "decision", Baseline: errorOr(baseErr, "valid"), Candidate: errorOr(candErr, "valid")}}
+		return comparison
+	}
+
+	var diffs []FieldDiff
+	add := func(field, b, c string) {
+		if b != c {
+			diffs = append(diffs, FieldDiff{Field: field, Baseline: b, Candidate: c})
+		}
+	}
+	number := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	add("action", base.Action, cand.Action)
+	add("symbol", base.Symbol, cand.Symbol)
+	add("size_usd", number(base.SizeUSD), number(cand.SizeUSD))
+	add("leverage", strconv.Itoa(base.Leverage), strconv.Itoa(cand.Leverage))
+	add("stop_loss", number(base.StopLoss), number(cand.StopLoss))
+	add("take_profit", number(base.TakeProfit), number(cand.TakeProfit))
+	add("confidence", strconv.Itoa(base.Confidence), strconv.Itoa(cand.Confidence))
+	add("risk_flags", strings.Join(base.RiskFlags, ","), strings.Join(cand.RiskFlags, ","))
+
+	return Comparison{Agree: base.Action == cand.Action && base.Symbol == cand.Symbol, Diffs: diffs}
+}
+
+func errorOr(err error, fallback string) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fallback
+}
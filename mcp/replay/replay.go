@@ -0,0 +1,194 @@
+// Package replay re-runs historical AI prompts against a new model or prompt version
+//
+// Records are loaded from the audit log (written with mcp.AuditContentFull) or from
+// recorder cassettes. Runner sends every recorded prompt to the candidate client,
+// compares the new answer with the recorded one and produces a Report with the
+// agreement rate and the divergent decisions, so model migrations can be checked
+// against real traffic before they go live.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nofx/mcp"
+)
+
+// Record one historical prompt with the answer it got
+type Record struct {
+	ID       string        `json:"id"`
+	Model    string        `json:"model,omitempty"`
+	Messages []mcp.Message `json:"messages"`
+	Baseline string        `json:"baseline"` // Recorded answer
+}
+
+// LoadAuditLog reads records from a JSONL audit log written by mcp.FileAuditSink
+//
+// Only successful interactions recorded with full content (mcp.AuditContentFull)
+// can be replayed; other lines are skipped.
+//
+// Usage example:
+//
+//	records, err := replay.LoadAuditLog("logs/ai_audit.jsonl")
+func LoadAuditLog(path string) ([]Record, error) {
+	return LoadEncryptedAuditLog(path, nil)
+}
+
+// LoadEncryptedAuditLog reads records from an audit log written with mcp.WithContentEncryption
+func LoadEncryptedAuditLog(path string, cipher mcp.ContentCipher) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var audit mcp.AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &audit); err != nil {
+			return nil, fmt.Errorf("audit log line %d: %w", line, err)
+		}
+		if err := mcp.DecryptAuditRecord(&audit, cipher); err != nil {
+			return nil, fmt.Errorf("audit log line %d: %w", line, err)
+		}
+		if audit.Outcome != mcp.AuditOutcomeSuccess || len(audit.Prompt) == 0 || audit.Response == "" {
+			continue
+		}
+		id := audit.RequestID
+		if id == "" {
+			id = fmt.Sprintf("audit-%d", line)
+		}
+		records = append(records, Record{ID: id, Model: audit.Model, Messages: audit.Prompt, Baseline: audit.Response})
+	}
+	return records, scanner.Err()
+}
+
+// LoadCassettes reads records from recorder cassette files in dir (sorted by file name)
+//
+// Cassettes whose request has no chat messages (health checks, embeddings) or whose
+// response has no text are skipped.
+func LoadCassettes(dir string) ([]Record, error) {
+	return LoadEncryptedCassettes(dir, nil)
+}
+
+// LoadEncryptedCassettes reads records from cassettes recorded with mcp.WithContentEncryption
+func LoadEncryptedCassettes(dir string, cipher mcp.ContentCipher) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var records []Record
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		var cassette mcp.Cassette
+		if err := json.Unmarshal(data, &cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		if err := cassette.Decrypt(cipher); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", path, err)
+		}
+		if cassette.Response.StatusCode != 200 {
+			continue
+		}
+		model, messages := requestMessages(cassette.Request.Body)
+		baseline := responseText(cassette.Response.Body)
+		if len(messages) == 0 || baseline == "" {
+			continue
+		}
+		id := strings.TrimSuffix(filepath.Base(path), ".json")
+		records = append(records, Record{ID: id, Model: model, Messages: messages, Baseline: baseline})
+	}
+	return records, nil
+}
+
+// requestMessages extracts model and chat messages of a recorded request body
+// (OpenAI-compatible messages, Anthropic top-level system and content blocks)
+func requestMessages(body string) (string, []mcp.Message) {
+	var request struct {
+		Model    string          `json:"model"`
+		System   json.RawMessage `json:"system"`
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	if json.Unmarshal([]byte(body), &request) != nil {
+		return "", nil
+	}
+
+	var messages []mcp.Message
+	if system := contentText(request.System); system != "" {
+		messages = append(messages, mcp.NewSystemMessage(system))
+	}
+	for _, m := range request.Messages {
+		messages = append(messages, mcp.Message{Role: m.Role, Content: contentText(m.Content)})
+	}
+	return request.Model, messages
+}
+
+// responseText extracts answer text of a recorded response body
+func responseText(body string) string {
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+	}
+	if json.Unmarshal([]byte(body), &response) != nil {
+		return ""
+	}
+	if len(response.Choices) > 0 {
+		return response.Choices[0].Message.Content
+	}
+	var text strings.Builder
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			// Forced JSON output arrives as tool input
+			text.Write(block.Input)
+		}
+	}
+	return text.String()
+}
+
+// contentText returns text of a string or content-block array value
+func contentText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var blocks []struct {
+		Text string `json:"text"`
+	}
+	json.Unmarshal(raw, &blocks)
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		parts = append(parts, block.Text)
+	}
+	return strings.Join(parts, "\n")
+}
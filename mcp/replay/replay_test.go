@@ -0,0 +1,111 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nofx/mcp"
+	"nofx/mcp/mcptest"
+)
+
+const (
+	longBTC  = `{"action":"open_long","symbol":"BTCUSDT","size_usd":1000,"confidence":70,"rationale":"breakout","risk_flags":[]}`
+	longBTC2 = `{"action":"open_long","symbol":"BTCUSDT","size_usd":500,"confidence":70,"rationale":"breakout, smaller","risk_flags":[]}`
+	holdBTC  = `{"action":"hold","symbol":"BTCUSDT","size_usd":0,"confidence":40,"rationale":"no edge","risk_flags":[]}`
+)
+
+func writeAuditLog(t *testing.T, records ...mcp.AuditRecord) string {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, _ := json.Marshal(record)
+		buf.Write(append(line, '\n'))
+	}
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAuditLog_SkipsUnreplayable(t *testing.T) {
+	prompt := []mcp.Message{mcp.NewSystemMessage("sys"), mcp.NewUserMessage("BTC 66k")}
+	path := writeAuditLog(t,
+		mcp.AuditRecord{RequestID: "r1", Prompt: prompt, Response: longBTC, Outcome: mcp.AuditOutcomeSuccess},
+		mcp.AuditRecord{RequestID: "r2", Outcome: mcp.AuditOutcomeSuccess}, // Hash-only content
+		mcp.AuditRecord{RequestID: "r3", Prompt: prompt, Outcome: mcp.AuditOutcomeError},
+	)
+
+	records, err := LoadAuditLog(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "r1" || len(records[0].Messages) != 2 {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
+
+func TestLoadCassettes_AnthropicAndOpenAI(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, cassette mcp.Cassette) {
+		data, _ := json.Marshal(cassette)
+		os.WriteFile(filepath.Join(dir, name), data, 0o600)
+	}
+	write("a.json", mcp.Cassette{
+		Request:  mcp.CassetteRequest{Body: `{"model":"claude","system":"sys","messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`},
+		Response: mcp.CassetteResponse{StatusCode: 200, Body: `{"content":[{"type":"text","text":"hello"}]}`},
+	})
+	write("b.json", mcp.Cassette{
+		Request:  mcp.CassetteRequest{Body: `{"model":"gpt","messages":[{"role":"user","content":"hi"}]}`},
+		Response: mcp.CassetteResponse{StatusCode: 200, Body: `{"choices":[{"message":{"content":"hey"}}]}`},
+	})
+	write("c.json", mcp.Cassette{Response: mcp.CassetteResponse{StatusCode: 200, Body: `{"data":[]}`}})
+
+	records, err := LoadCassettes(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", records)
+	}
+	if records[0].Messages[0].Content != "sys" || records[0].Messages[1].Content != "hi" || records[0].Baseline != "hello" {
+		t.Errorf("unexpected anthropic record %+v", records[0])
+	}
+	if records[1].Model != "gpt" || records[1].Baseline != "hey" {
+		t.Errorf("unexpected openai record %+v", records[1])
+	}
+}
+
+func TestRunner_AgreementReport(t *testing.T) {
+	user := []mcp.Message{mcp.NewSystemMessage("old prompt"), mcp.NewUserMessage("BTC 66k")}
+	records := []Record{
+		{ID: "same", Messages: user, Baseline: longBTC},
+		{ID: "resized", Messages: user, Baseline: longBTC},
+		{ID: "flipped", Messages: user, Baseline: longBTC},
+		{ID: "failed", Messages: user, Baseline: longBTC},
+	}
+	candidate := mcptest.NewMockClient(longBTC, longBTC2, holdBTC).QueueError(errors.New("timeout"))
+
+	report := (&Runner{SystemPrompt: "new prompt"}).Run(context.Background(), records, candidate)
+	if report.Agreed != 2 || report.Errors != 1 || report.AgreementRate < 0.66 || report.AgreementRate > 0.67 {
+		t.Errorf("unexpected summary agreed=%d errors=%d rate=%v", report.Agreed, report.Errors, report.AgreementRate)
+	}
+	if report.FieldChanges["action"] != 1 || report.FieldChanges["size_usd"] != 2 {
+		t.Errorf("unexpected field changes %v", report.FieldChanges)
+	}
+	if call := candidate.Calls()[0]; call.Request.Messages[0].Content != "new prompt" {
+		t.Errorf("system prompt not replaced: %+v", call.Request.Messages)
+	}
+
+	var out bytes.Buffer
+	report.WriteText(&out)
+	if !strings.Contains(out.String(), "DIVERGED flipped: action") || !strings.Contains(out.String(), "DIVERGED failed: timeout") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+}
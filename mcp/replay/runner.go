@@ -0,0 +1,193 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"nofx/mcp"
+)
+
+// Runner replays records against a candidate client
+type Runner struct {
+	Compare      Comparator // Default: CompareTradeDecisions
+	SystemPrompt string     // Replaces recorded system prompts (new prompt version, "" = keep)
+	Concurrency  int        // Records replayed in parallel (0 = 1)
+}
+
+// Result outcome of replaying one record
+type Result struct {
+	ID        string        `json:"id"`
+	Baseline  string        `json:"baseline"`
+	Candidate string        `json:"candidate"`
+	Agree     bool          `json:"agree"`
+	Diffs     []FieldDiff   `json:"diffs,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	Latency   time.Duration `json:"latency"`
+}
+
+// Report results of a replay run
+type Report struct {
+	Results       []Result       `json:"results"`
+	Agreed        int            `json:"agreed"`
+	Errors        int            `json:"errors"`
+	AgreementRate float64        `json:"agreement_rate"` // Agreed / replayed without error
+	FieldChanges  map[string]int `json:"field_changes"`  // Records with a diff per field
+	Duration      time.Duration  `json:"duration"`
+}
+
+// Run replays records against client
+//
+// Usage example:
+//
+//	records, _ := replay.LoadAuditLog("logs/ai_audit.jsonl")
+//	report := (&replay.Runner{Concurrency: 4}).Run(ctx, records, candidate)
+//	report.WriteText(os.Stdout)
+func (r *Runner) Run(ctx context.Context, records []Record, client mcp.AIClient) *Report {
+	compare := r.Compare
+	if compare == nil {
+		compare = CompareTradeDecisions
+	}
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	start := time.Now()
+	results := make([]Result, len(records))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, record := range records {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, record Record) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = r.replay(ctx, record, client, compare)
+		}(i, record)
+	}
+	wg.Wait()
+
+	report := &Report{Results: results, FieldChanges: make(map[string]int), Duration: time.Since(start)}
+	for _, result := range results {
+		if result.Error != "" {
+			report.Errors++
+			continue
+		}
+		if result.Agree {
+			report.Agreed++
+		}
+		for _, diff := range result.Diffs {
+			report.FieldChanges[diff.Field]++
+		}
+	}
+	if replayed := len(results) - report.Errors; replayed > 0 {
+		report.AgreementRate = float64(report.Agreed) / float64(replayed)
+	}
+	return report
+}
+
+// replay sends one record to client and compares the answer
+func (r *Runner) replay(ctx context.Context, record Record, client mcp.AIClient, compare Comparator) Result {
+	result := Result{ID: record.ID, Baseline: record.Baseline}
+
+	messages := record.Messages
+	if r.SystemPrompt != "" {
+		messages = []mcp.Message{mcp.NewSystemMessage(r.SystemPrompt)}
+		for _, m := range record.Messages {
+			if m.Role != "system" {
+				messages = append(messages, m)
+			}
+		}
+	}
+	req, err := mcp.NewRequestBuilder().AddMessages(messages...).Build()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	start := time.Now()
+	candidate, err := call(ctx, client, req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Candidate = candidate
+
+	comparison := compare(record.Baseline, candidate)
+	result.Agree = comparison.Agree
+	result.Diffs = comparison.Diffs
+	return result
+}
+
+// call sends req with ctx when the client supports it
+func call(ctx context.Context, client mcp.AIClient, req *mcp.Request) (string, error) {
+	if caller, ok := client.(interface {
+		CallWithRequestContext(ctx context.Context, req *mcp.Request) (string, error)
+	}); ok {
+		return caller.CallWithRequestContext(ctx, req)
+	}
+	return client.CallWithRequest(req)
+}
+
+// Divergences returns results that disagree with the baseline or failed
+func (r *Report) Divergences() []Result {
+	var divergences []Result
+	for _, result := range r.Results {
+		if !result.Agree {
+			divergences = append(divergences, result)
+		}
+	}
+	return divergences
+}
+
+// WriteText writes agreement summary, field change counts and the divergent records
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RECORDS\tAGREED\tERRORS\tAGREEMENT")
+	fmt.Fprintf(tw, "%d\t%d\t%d\t%.1f%%\n", len(r.Results), r.Agreed, r.Errors, r.AgreementRate*100)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, field := range sortedFields(r.FieldChanges) {
+		if _, err := fmt.Fprintf(w, "changed %s: %d\n", field, r.FieldChanges[field]); err != nil {
+			return err
+		}
+	}
+	for _, result := range r.Divergences() {
+		reason := result.Error
+		if reason == "" {
+			parts := make([]string, 0, len(result.Diffs))
+			for _, diff := range result.Diffs {
+				parts = append(parts, fmt.Sprintf("%s %q -> %q", diff.Field, diff.Baseline, diff.Candidate))
+			}
+			reason = strings.Join(parts, ", ")
+		}
+		if _, err := fmt.Fprintf(w, "DIVERGED %s: %s\n", result.ID, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedFields returns field names by descending change count, then name
+func sortedFields(counts map[string]int) []string {
+	fields := make([]string, 0, len(counts))
+	for field := range counts {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if counts[fields[i]] != counts[fields[j]] {
+			return counts[fields[i]] > counts[fields[j]]
+		}
+		return fields[i] < fields[j]
+	})
+	return fields
+}
@@ -4,6 +4,13 @@ package mcp
 type Message struct {
 	Role    string `json:"role"`    // "system", "user", "assistant"
 	Content string `json:"content"` // Message content
+	Cache   bool   `json:"-"`       // Cache breakpoint: provider may cache the prompt up to this message
+
+	Files []*FileRef `json:"-"` // Attached files (see UploadFile)
+
+	// Tool calling (agent loops)
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // Assistant message: tool calls the model requested
+	ToolCallID string     `json:"tool_call_id,omitempty"` // Tool message: ID of the call this is the result of
 }
 
 // Tool represents a tool/function that AI can call
@@ -37,6 +44,17 @@ type Request struct {
 	// Advanced features
 	Tools      []Tool `json:"tools,omitempty"`       // Available tools list
 	ToolChoice string `json:"tool_choice,omitempty"` // Tool choice strategy ("auto", "none", {"type": "function", "function": {"name": "xxx"}})
+
+	// Reasoning models (translated per provider)
+	ReasoningEffort string `json:"-"` // "low", "medium", "high" (OpenAI-compatible reasoning_effort)
+	ThinkingBudget  int    `json:"-"` // Max thinking tokens (Anthropic extended thinking, Qwen thinking_budget)
+
+	// Token probabilities (OpenAI-compatible providers)
+	Logprobs    bool `json:"-"` // Return log probabilities of generated tokens
+	TopLogprobs int  `json:"-"` // Number of most likely alternatives per token (0-20, implies Logprobs)
+
+	// Structured output (translated per provider, overrides client-level WithJSONResponse)
+	ResponseFormat *ResponseFormat `json:"-"`
 }
 
 // NewMessage creates a message
@@ -70,3 +88,23 @@ func NewAssistantMessage(content string) Message {
 		Content: content,
 	}
 }
+
+// NewToolCallsMessage creates an assistant message carrying the tool calls of a response
+//
+// Send it back before the tool results, so the provider can match them to the calls.
+func NewToolCallsMessage(content string, calls []ToolCall) Message {
+	return Message{
+		Role:      "assistant",
+		Content:   content,
+		ToolCalls: calls,
+	}
+}
+
+// NewToolMessage creates a tool result message answering the tool call callID
+func NewToolMessage(callID, content string) Message {
+	return Message{
+		Role:       "tool",
+		Content:    content,
+		ToolCallID: callID,
+	}
+}
@@ -17,6 +17,11 @@ type RequestBuilder struct {
 	stop             []string
 	tools            []Tool
 	toolChoice       string
+	responseFormat   *ResponseFormat
+	reasoningEffort  string
+	thinkingBudget   int
+	logprobs         bool
+	topLogprobs      int
 }
 
 // NewRequestBuilder creates request builder
@@ -96,6 +101,18 @@ func (b *RequestBuilder) AddMessage(role, content string) *RequestBuilder {
 	return b
 }
 
+// AddCachedContext adds user message marked as cache breakpoint (see WithPromptCaching)
+//
+// Use it for large, stable context such as RAG documents that precede the actual question.
+func (b *RequestBuilder) AddCachedContext(content string) *RequestBuilder {
+	if content != "" {
+		msg := NewUserMessage(content)
+		msg.Cache = true
+		b.messages = append(b.messages, msg)
+	}
+	return b
+}
+
 // AddMessages adds messages in batch
 func (b *RequestBuilder) AddMessages(messages ...Message) *RequestBuilder {
 	b.messages = append(b.messages, messages...)
@@ -217,6 +234,56 @@ func (b *RequestBuilder) WithToolChoice(choice string) *RequestBuilder {
 	return b
 }
 
+// ============================================================
+// Reasoning Related
+// ============================================================
+
+// WithReasoningEffort sets reasoning effort for reasoning models ("low", "medium", "high")
+func (b *RequestBuilder) WithReasoningEffort(effort string) *RequestBuilder {
+	b.reasoningEffort = effort
+	return b
+}
+
+// WithThinkingBudget enables extended thinking with up to tokens thinking tokens
+func (b *RequestBuilder) WithThinkingBudget(tokens int) *RequestBuilder {
+	if tokens > 0 {
+		b.thinkingBudget = tokens
+	}
+	return b
+}
+
+// ============================================================
+// Token Probability Related
+// ============================================================
+
+// WithLogprobs requests log probabilities of generated tokens with topN alternatives each (0-20)
+//
+// Parsed probabilities are available in Response.Logprobs; Response.Confidence()
+// summarizes them into one score.
+func (b *RequestBuilder) WithLogprobs(topN int) *RequestBuilder {
+	if topN < 0 {
+		topN = 0
+	}
+	if topN > 20 {
+		topN = 20
+	}
+	b.logprobs = true
+	b.topLogprobs = topN
+	return b
+}
+
+// ============================================================
+// Structured Output Related
+// ============================================================
+
+// WithJSONResponse requests JSON output conforming to schema (nil = any JSON object)
+//
+// The response is validated against schema; see mcp.WithJSONResponse for details.
+func (b *RequestBuilder) WithJSONResponse(schema map[string]any) *RequestBuilder {
+	b.responseFormat = &ResponseFormat{Schema: schema}
+	return b
+}
+
 // ============================================================
 // Build Methods
 // ============================================================
@@ -236,6 +303,12 @@ func (b *RequestBuilder) Build() (*Request, error) {
 		Stop:       b.stop,
 		Tools:      b.tools,
 		ToolChoice: b.toolChoice,
+
+		ReasoningEffort: b.reasoningEffort,
+		ThinkingBudget:  b.thinkingBudget,
+		Logprobs:        b.logprobs,
+		TopLogprobs:     b.topLogprobs,
+		ResponseFormat:  b.responseFormat,
 	}
 
 	// Only set non-nil optional parameters (avoid sending 0 values that override server defaults)
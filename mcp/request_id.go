@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	headerRequestID      = "X-Request-Id"
+	headerIdempotencyKey = "Idempotency-Key"
+)
+
+// requestIDKey context key of the request ID
+type requestIDKey struct{}
+
+// ContextWithRequestID returns ctx carrying caller-supplied request ID
+//
+// The ID is sent with every attempt of the call (retries reuse it), appears in logs,
+// errors and audit records, so it can be used for end-to-end correlation.
+//
+// Usage example:
+//   ctx := mcp.ContextWithRequestID(ctx, traceID)
+//   result, err := client.CallWithRequestContext(ctx, request)
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns request ID carried by ctx ("" if none)
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithIdempotencyKeys also sends the request ID as Idempotency-Key header
+//
+// Only enable for endpoints/gateways that honor idempotency keys; the key stays the
+// same across retries of one call so a retried request isn't billed twice.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithIdempotencyKeys(true))
+func WithIdempotencyKeys(enabled bool) ClientOption {
+	return func(c *Config) {
+		c.IdempotencyKeys = enabled
+	}
+}
+
+// RequestError error of a call annotated with its request ID
+type RequestError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%v [request_id: %s]", e.Err, e.RequestID)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// LastRequestID returns request ID of the most recent call
+func (client *Client) LastRequestID() string {
+	client.lastMu.Lock()
+	defer client.lastMu.Unlock()
+	return client.lastRequestID
+}
+
+// ensureRequestID returns ctx carrying a request ID (generated if missing) and the ID
+func (client *Client) ensureRequestID(ctx context.Context) (context.Context, string) {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		id = uuid.NewString()
+		ctx = ContextWithRequestID(ctx, id)
+	}
+
+	client.lastMu.Lock()
+	client.lastRequestID = id
+	client.lastMu.Unlock()
+	return ctx, id
+}
+
+// setRequestIDHeaders sends request ID of ctx (and idempotency key if enabled) with req
+func (client *Client) setRequestIDHeaders(ctx context.Context, req *http.Request) {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(headerRequestID, requestID)
+		if client.config.IdempotencyKeys {
+			req.Header.Set(headerIdempotencyKey, requestID)
+		}
+	}
+}
+
+// withRequestID annotates err with request ID (nil stays nil)
+func withRequestID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	return &RequestError{RequestID: id, Err: err}
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test Request IDs / Idempotency Keys
+// ============================================================
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestID_GeneratedAndSentAsHeader(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("ok")
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	if _, err := client.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	requests := mockHTTP.GetRequests()
+	id := requests[0].Header.Get(headerRequestID)
+	if !uuidPattern.MatchString(id) {
+		t.Errorf("expected UUID v4 request ID, got %q", id)
+	}
+	if requests[0].Header.Get(headerIdempotencyKey) != "" {
+		t.Error("Idempotency-Key should only be sent when enabled")
+	}
+	if client.LastRequestID() != id {
+		t.Errorf("LastRequestID should be %q, got %q", id, client.LastRequestID())
+	}
+}
+
+func TestRequestID_CallerSuppliedAndReusedAcrossRetries(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetNetworkError(errors.New("connection reset by peer"))
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(2),
+		WithRetryWaitBase(0),
+		WithIdempotencyKeys(true),
+	)
+
+	ctx := ContextWithRequestID(context.Background(), "trace-123")
+	request := NewRequestBuilder().WithUserPrompt("hello").MustBuild()
+	_, err := client.(*Client).CallWithRequestContext(ctx, request)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.RequestID != "trace-123" {
+		t.Errorf("error should carry request ID, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "trace-123") {
+		t.Errorf("error message should mention request ID, got %v", err)
+	}
+
+	requests := mockHTTP.GetRequests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(requests))
+	}
+	for i, r := range requests {
+		if r.Header.Get(headerRequestID) != "trace-123" || r.Header.Get(headerIdempotencyKey) != "trace-123" {
+			t.Errorf("attempt %d should reuse request ID and idempotency key, got %q / %q",
+				i+1, r.Header.Get(headerRequestID), r.Header.Get(headerIdempotencyKey))
+		}
+	}
+}
+
+func TestRequestID_RecordedInAudit(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("ok")
+	sink := &memoryAuditSink{}
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithAuditSink(sink),
+	).(*Client)
+
+	client.CallWithMessages("system", "user")
+
+	records := sink.Records()
+	if len(records) != 1 || records[0].RequestID != client.LastRequestID() {
+		t.Errorf("audit record should carry request ID %q, got %+v", client.LastRequestID(), records)
+	}
+}
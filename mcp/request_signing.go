@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Request signature headers set by WithRequestSigner
+const (
+	HeaderRequestSignature = "X-MCP-Signature"      // "<algorithm>=<signature>"
+	HeaderRequestTimestamp = "X-MCP-Timestamp"      // Unix seconds
+	HeaderRequestKeyID     = "X-MCP-Key-Id"         // Key the gateway verifies with
+	HeaderContentSHA256    = "X-MCP-Content-SHA256" // Hex SHA-256 of the (uncompressed) body
+)
+
+// maxSignedBodyBytes largest request body VerifySignedRequest reads
+const maxSignedBodyBytes = 8 << 20
+
+var (
+	// ErrInvalidSignature is returned by VerifySignedRequest for missing, stale or wrong signatures
+	ErrInvalidSignature = errors.New("invalid request signature")
+	// ErrRequestBodyTooLarge is returned by VerifySignedRequest for bodies over 8 MiB
+	ErrRequestBodyTooLarge = errors.New("request body too large")
+)
+
+// RequestSigner signs canonical requests (see CanonicalRequest)
+type RequestSigner interface {
+	KeyID() string
+	// Sign returns the signature header value of canonical, e.g. "hmac-sha256=<hex>"
+	Sign(canonical []byte) (string, error)
+}
+
+// RequestVerifier checks signatures made by a RequestSigner
+type RequestVerifier interface {
+	Verify(keyID string, canonical []byte, signature string) error
+}
+
+// CanonicalRequest returns the bytes signed for a request
+//
+// Lines: method, path with query, timestamp (unix seconds), hex SHA-256 of the body.
+func CanonicalRequest(method, pathAndQuery string, timestamp int64, bodySHA256 string) []byte {
+	return []byte(strings.Join([]string{strings.ToUpper(method), pathAndQuery, strconv.FormatInt(timestamp, 10), bodySHA256}, "\n"))
+}
+
+// WithRequestSigner signs every request so an internal gateway can verify the caller
+//
+// The signature covers method, path, a timestamp and the body hash, and is sent in
+// the X-MCP-* headers along with the key ID. Gateways verify it with
+// VerifySignedRequest (Gateway.WithSignatureVerifier for the built-in gateway).
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithBaseURL("https://llm-gateway.internal/v1"),
+//       mcp.WithRequestSigner(mcp.NewHMACSigner("trader-1", secret)),
+//   )
+func WithRequestSigner(signer RequestSigner) ClientOption {
+	return func(c *Config) {
+		c.RequestSigner = signer
+	}
+}
+
+// HMACSigner shared-secret signer (HMAC-SHA256), also verifies its own signatures
+type HMACSigner struct {
+	keyID  string
+	secret []byte
+}
+
+// NewHMACSigner creates HMAC-SHA256 signer
+func NewHMACSigner(keyID string, secret []byte) *HMACSigner {
+	return &HMACSigner{keyID: keyID, secret: secret}
+}
+
+func (s *HMACSigner) KeyID() string { return s.keyID }
+
+func (s *HMACSigner) Sign(canonical []byte) (string, error) {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(canonical)
+	return "hmac-sha256=" + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (s *HMACSigner) Verify(keyID string, canonical []byte, signature string) error {
+	expected, _ := s.Sign(canonical)
+	if keyID != s.keyID || !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Ed25519Signer asymmetric signer: the gateway only needs the public key
+type Ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewEd25519Signer creates Ed25519 signer
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{keyID: keyID, key: key}
+}
+
+func (s *Ed25519Signer) KeyID() string { return s.keyID }
+
+func (s *Ed25519Signer) Sign(canonical []byte) (string, error) {
+	return "ed25519=" + base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, canonical)), nil
+}
+
+// Ed25519Verifier public keys of callers by key ID
+type Ed25519Verifier map[string]ed25519.PublicKey
+
+func (v Ed25519Verifier) Verify(keyID string, canonical []byte, signature string) error {
+	key, ok := v[keyID]
+	encoded, found := strings.CutPrefix(signature, "ed25519=")
+	if !ok || !found {
+		return ErrInvalidSignature
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || !ed25519.Verify(key, canonical, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifySignedRequest checks signature headers of r, returning the caller's key ID
+//
+// The body is read and restored, so handlers can still decode it; bodies over 8 MiB
+// are rejected with ErrRequestBodyTooLarge. Requests whose timestamp is more than
+// maxSkew away from now are rejected (replay protection).
+func VerifySignedRequest(r *http.Request, verifier RequestVerifier, maxSkew time.Duration) (string, error) {
+	keyID := r.Header.Get(HeaderRequestKeyID)
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderRequestTimestamp), 10, 64)
+	if keyID == "" || err != nil {
+		return "", fmt.Errorf("%w: missing key ID or timestamp", ErrInvalidSignature)
+	}
+	if skew := time.Since(time.Unix(timestamp, 0)); skew > maxSkew || skew < -maxSkew {
+		return "", fmt.Errorf("%w: timestamp outside allowed skew", ErrInvalidSignature)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(r.Body, maxSignedBodyBytes+1))
+		r.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body: %w", err)
+		}
+		if len(body) > maxSignedBodyBytes {
+			return "", fmt.Errorf("%w: more than %d bytes", ErrRequestBodyTooLarge, maxSignedBodyBytes)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	canonical := CanonicalRequest(r.Method, r.URL.RequestURI(), timestamp, sha256Hex(body))
+	if err := verifier.Verify(keyID, canonical, r.Header.Get(HeaderRequestSignature)); err != nil {
+		return "", err
+	}
+	return keyID, nil
+}
+
+// signingTransport http.RoundTripper adding signature headers to every request
+type signingTransport struct {
+	next   http.RoundTripper
+	signer RequestSigner
+	now    func() time.Time
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	timestamp := t.now().Unix()
+	digest := sha256Hex(body)
+	signature, err := t.signer.Sign(CanonicalRequest(req.Method, req.URL.RequestURI(), timestamp, digest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	out.Header.Set(HeaderRequestKeyID, t.signer.KeyID())
+	out.Header.Set(HeaderRequestTimestamp, strconv.FormatInt(timestamp, 10))
+	out.Header.Set(HeaderContentSHA256, digest)
+	out.Header.Set(HeaderRequestSignature, signature)
+	return t.next.RoundTrip(out)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestSigning_HMACRoundTrip(t *testing.T) {
+	signer := NewHMACSigner("trader-1", []byte("s3cret"))
+	var captured *http.Request
+	mock := NewMockHTTPClient()
+	mock.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		captured = req
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}
+	transport := &signingTransport{next: mock, signer: signer, now: time.Now}
+	req, _ := http.NewRequest(http.MethodPost, "https://gw.internal/v1/chat/completions?tenant=a", strings.NewReader(`{"model":"x"}`))
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if !strings.HasPrefix(captured.Header.Get(HeaderRequestSignature), "hmac-sha256=") {
+		t.Fatalf("unexpected signature header %q", captured.Header.Get(HeaderRequestSignature))
+	}
+
+	keyID, err := VerifySignedRequest(captured, signer, time.Minute)
+	if err != nil || keyID != "trader-1" {
+		t.Fatalf("verify = %q, %v", keyID, err)
+	}
+
+	// Tampered body
+	tampered := captured.Clone(captured.Context())
+	tampered.Body = http.NoBody
+	if _, err := VerifySignedRequest(tampered, signer, time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered body verified, err = %v", err)
+	}
+
+	// Stale timestamp
+	transport.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	req, _ = http.NewRequest(http.MethodPost, "https://gw.internal/v1/chat/completions", strings.NewReader(`{}`))
+	transport.RoundTrip(req)
+	if _, err := VerifySignedRequest(captured, signer, time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("stale request verified, err = %v", err)
+	}
+}
+
+func TestRequestSigning_Ed25519ThroughGateway(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(nil)
+	_, otherPrivate, _ := ed25519.GenerateKey(nil)
+
+	upstream := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"hold"}}]}`, new([]map[string]any))),
+		WithAPIKey("sk-upstream"),
+		WithLogger(NewNoopLogger()),
+	)
+	gateway := NewGateway(upstream).WithSignatureVerifier(Ed25519Verifier{"trader-1": public}, time.Minute)
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	newCaller := func(key ed25519.PrivateKey) AIClient {
+		return NewClient(
+			WithProvider(ProviderCustom),
+			WithBaseURL(server.URL+"/v1"),
+			WithAPIKey("unused"),
+			WithModel("gpt-4o"),
+			WithMaxRetries(1),
+			WithLogger(NewNoopLogger()),
+			WithRequestSigner(NewEd25519Signer("trader-1", key)),
+		)
+	}
+
+	answer, err := newCaller(private).CallWithMessages("sys", "BTC?")
+	if err != nil || answer != "hold" {
+		t.Fatalf("signed call = %q, %v", answer, err)
+	}
+	if _, err := newCaller(otherPrivate).CallWithMessages("sys", "BTC?"); err == nil {
+		t.Error("expected call signed with an unknown key to be rejected")
+	}
+
+	// Compressed bodies are verified after the gateway decompresses them
+	compressed := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL(server.URL+"/v1"),
+		WithAPIKey("unused"),
+		WithModel("gpt-4o"),
+		WithMaxRetries(1),
+		WithLogger(NewNoopLogger()),
+		WithRequestSigner(NewEd25519Signer("trader-1", private)),
+		WithCompression(true),
+	)
+	if answer, err := compressed.CallWithMessages("sys", strings.Repeat("BTC candles ", 2000)); err != nil || answer != "hold" {
+		t.Fatalf("signed compressed call = %q, %v", answer, err)
+	}
+}
+
+func TestVerifySignedRequest_BodyLimit(t *testing.T) {
+	signer := NewHMACSigner("trader-1", []byte("s3cret"))
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(strings.Repeat("x", maxSignedBodyBytes+1)))
+	req.Header.Set(HeaderRequestKeyID, "trader-1")
+	req.Header.Set(HeaderRequestTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+	if _, err := VerifySignedRequest(req, signer, time.Minute); !errors.Is(err, ErrRequestBodyTooLarge) {
+		t.Fatalf("expected ErrRequestBodyTooLarge, got %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	req.Body = io.NopCloser(strings.NewReader(strings.Repeat("x", maxSignedBodyBytes+1)))
+	NewGateway(NewClient(WithLogger(NewNoopLogger()))).WithSignatureVerifier(signer, time.Minute).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrRerankUnsupported is returned by Rerank for providers without a rerank API
+var ErrRerankUnsupported = errors.New("provider has no rerank API")
+
+// defaultRerankModels rerank model used per provider when none is configured
+var defaultRerankModels = map[string]string{
+	ProviderCohere: "rerank-v3.5",
+	ProviderVoyage: "rerank-2.5",
+}
+
+// RerankResult one document ranked by relevance to the query
+type RerankResult struct {
+	Index    int     // Position of the document in the input
+	Document string  // The document text
+	Score    float64 // Relevance score, higher is more relevant
+}
+
+// RerankResponse documents ordered by relevance, most relevant first
+type RerankResponse struct {
+	Results []RerankResult
+	Model   string
+	Usage   TokenUsage // Voyage reports tokens; Cohere bills search units, reported as TotalTokens
+}
+
+// WithRerankModel sets model used by Rerank (default: provider's standard rerank model)
+//
+// Usage example:
+//   client := mcp.NewCohereClientWithOptions(mcp.WithRerankModel("rerank-multilingual-v3.0"))
+func WithRerankModel(model string) ClientOption {
+	return func(c *Config) {
+		c.RerankModel = model
+	}
+}
+
+// Rerank orders documents by relevance to query and returns the topN best (0 = all)
+//
+// Supported by Cohere and Voyage. Reranking retrieval candidates before putting them
+// into a prompt keeps the context small and on topic.
+//
+// Usage example:
+//   ranked, err := client.Rerank(ctx, "why did BTC drop today?", newsHeadlines, 5)
+//   for _, result := range ranked.Results {
+//       fmt.Printf("%.2f %s\n", result.Score, result.Document)
+//   }
+func (client *Client) Rerank(ctx context.Context, query string, documents []string, topN int) (*RerankResponse, error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+	if client.Provider != ProviderCohere && client.Provider != ProviderVoyage {
+		return nil, ErrRerankUnsupported
+	}
+	model := client.config.RerankModel
+	if model == "" {
+		model = defaultRerankModels[client.Provider]
+	}
+	if len(documents) == 0 {
+		return &RerankResponse{Model: model}, nil
+	}
+
+	payload := map[string]any{"model": model, "query": query, "documents": documents}
+	if topN > 0 {
+		if client.Provider == ProviderVoyage {
+			payload["top_k"] = topN
+		} else {
+			payload["top_n"] = topN
+		}
+	}
+	body, requestID, err := client.postAPI(ctx, EndpointRerank, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	type ranked struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	}
+	var result struct {
+		Results []ranked `json:"results"` // Cohere
+		Data    []ranked `json:"data"`    // Voyage
+		Model   string   `json:"model"`
+		Meta    struct {
+			BilledUnits struct {
+				SearchUnits int `json:"search_units"`
+			} `json:"billed_units"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, withRequestID(fmt.Errorf("failed to parse rerank response: %w", err), requestID)
+	}
+
+	response := &RerankResponse{Model: result.Model, Usage: parseUsage(body)}
+	if response.Model == "" {
+		response.Model = model
+	}
+	if response.Usage.TotalTokens == 0 {
+		response.Usage.TotalTokens = result.Meta.BilledUnits.SearchUnits
+	}
+	response.Usage.Provider = client.Provider
+	response.Usage.Model = response.Model
+	for _, item := range append(result.Results, result.Data...) {
+		if item.Index < 0 || item.Index >= len(documents) {
+			return nil, withRequestID(fmt.Errorf("rerank result index %d out of range", item.Index), requestID)
+		}
+		response.Results = append(response.Results, RerankResult{Index: item.Index, Document: documents[item.Index], Score: item.RelevanceScore})
+	}
+	sort.SliceStable(response.Results, func(i, j int) bool { return response.Results[i].Score > response.Results[j].Score })
+	return response, nil
+}
@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Response AI response with metadata
+type Response struct {
+	Text         string     // Final answer text
+	Reasoning    string     // Model reasoning/thinking, kept separate from Text ("" if none)
+	ToolCalls    []ToolCall // Tool calls requested by the model (nil if none)
+	FinishReason string     // Normalized finish reason (FinishReasonStop, FinishReasonLength, ...)
+
+	Logprobs []TokenLogprob // Token log probabilities (only when requested)
+
+	Usage TokenUsage // Token usage reported by the provider
+
+	Model     string          // Model that answered (as reported by the provider, else the requested one)
+	Provider  string          // Provider of the client that made the call
+	RequestID string          // Request ID shared by all attempts of the call
+	Latency   time.Duration   // Wall time of the whole call, retries included
+	Raw       json.RawMessage // Raw provider response body of the final attempt
+
+	SystemFingerprint string // Provider backend configuration that answered ("" if not reported)
+
+	StatusCode int         // HTTP status of the final attempt
+	Header     http.Header // Provider response headers (rate limits, request IDs, ...), secrets redacted
+
+	Violations []GuardrailViolation // Guardrail findings that didn't block the call
+	Warnings   []string             // Response problems tolerated by lenient parsing
+
+	Compression   *CompressionStats // Prompt compression applied to the request (nil if none)
+	StreamMetrics *StreamMetrics    // First-token latency and tokens/sec (streamed calls only)
+}
+
+// ToolCall tool invocation requested by the model
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"` // "function"
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction function name and JSON-encoded arguments of a tool call
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// buildResponse builds response from raw provider body and the text parsed by hooks
+func (client *Client) buildResponse(body []byte, text string) *Response {
+	response := &Response{
+		Text:         text,
+		ToolCalls:    parseToolCalls(body),
+		FinishReason: parseFinishReason(body),
+		Logprobs:     parseLogprobs(body),
+		Usage:        parseUsage(body),
+		Model:        parseResponseModel(body),
+		Provider:     client.Provider,
+		Raw:          json.RawMessage(body),
+	}
+	if response.Model == "" {
+		response.Model = client.Model
+	}
+	response.SystemFingerprint = parseSystemFingerprint(body)
+	response.Usage.Provider = client.Provider
+	response.Usage.Model = client.Model
+
+	reasoning := parseReasoning(body)
+	if !client.config.RetainReasoning {
+		var inline string
+		response.Text, inline = splitThinkBlocks(text)
+		if reasoning == "" {
+			reasoning = inline
+		}
+	}
+	response.Reasoning = reasoning
+	return response
+}
+
+// finishResponse completes a successful call: response processors, output guardrails,
+// PII restoration, call-level metadata and shadow mirroring
+func (client *Client) finishResponse(ctx context.Context, response *Response, start time.Time, vault *piiVault, violations []GuardrailViolation, shadow func(context.Context, AIClient) (*Response, error)) (*Response, error) {
+	requestID := RequestIDFromContext(ctx)
+	text, err := client.processResponse(ctx, response.Text)
+	if err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+	response.Text = text
+	outputViolations, err := client.runGuardrails(ctx, GuardOutput, &response.Text)
+	if err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+	response.Violations = append(violations, outputViolations...)
+	response.Text = vault.restore(response.Text)
+	response.Reasoning = vault.restore(response.Reasoning)
+	response.ToolCalls = vault.restoreToolCalls(response.ToolCalls)
+	response.RequestID = requestID
+	response.Latency = time.Since(start)
+
+	// Only calls that passed guardrails are mirrored, paired with the text returned
+	client.mirror(requestID, ShadowResult{
+		Text:    response.Text,
+		Latency: response.Latency,
+		Usage:   response.Usage,
+		Cost:    EstimateCost(response.Usage),
+	}, shadow)
+	return response, nil
+}
+
+// parseResponseModel returns the model name reported in a provider response body
+func parseResponseModel(body []byte) string {
+	var response struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	return response.Model
+}
+
+// parseToolCalls extracts tool calls from OpenAI-compatible or Anthropic response body
+//
+// The internal tool used to force JSON output on Anthropic is not reported.
+func parseToolCalls(body []byte) []ToolCall {
+	type wireToolCall struct {
+		ID       string `json:"id"`
+		Type     string `json:"type"`
+		Function struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		} `json:"function"`
+	}
+	var response struct {
+		Choices []struct {
+			Message struct {
+				ToolCalls []wireToolCall `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+		Message struct { // Cohere v2
+			ToolCalls []wireToolCall `json:"tool_calls"`
+		} `json:"message"`
+		Content []struct {
+			Type  string          `json:"type"`
+			ID    string          `json:"id"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+
+	var calls []ToolCall
+	wireCalls := response.Message.ToolCalls
+	if len(response.Choices) > 0 {
+		wireCalls = response.Choices[0].Message.ToolCalls
+	}
+	for _, call := range wireCalls {
+		calls = append(calls, ToolCall{
+			ID:       call.ID,
+			Type:     call.Type,
+			Function: ToolCallFunction{Name: call.Function.Name, Arguments: call.Function.Arguments},
+		})
+	}
+	for _, block := range response.Content {
+		if block.Type != "tool_use" || block.Name == jsonResponseTool {
+			continue
+		}
+		calls = append(calls, ToolCall{
+			ID:       block.ID,
+			Type:     "function",
+			Function: ToolCallFunction{Name: block.Name, Arguments: string(block.Input)},
+		})
+	}
+	return calls
+}
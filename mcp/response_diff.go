@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Similarity methods of ResponseDiff
+const (
+	SimilarityTokenOverlap = "token_overlap" // Jaccard overlap of word sets
+	SimilarityEmbedding    = "embedding"     // Cosine similarity of embedding vectors
+)
+
+// Embedder returns embedding vectors of inputs (implemented by *Client)
+type Embedder interface {
+	Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error)
+}
+
+// ResponseDiff structured difference between two responses
+type ResponseDiff struct {
+	TextEqual        bool    // Texts equal ignoring surrounding whitespace
+	Similarity       float64 // Text similarity in [0,1]
+	SimilarityMethod string  // SimilarityTokenOverlap or SimilarityEmbedding
+
+	ToolCalls []ToolCallDiff  // Tool calls that differ, by position
+	Fields    []JSONFieldDiff // Differing fields when both texts contain a JSON object
+	JSON      bool            // Both texts contained a JSON object (Fields was compared)
+}
+
+// ToolCallDiff tool call at Index that differs ("" = no call at that position)
+type ToolCallDiff struct {
+	Index int
+	A     string // name(arguments)
+	B     string
+}
+
+// JSONFieldDiff JSON field that differs (values JSON-encoded, "" = missing)
+type JSONFieldDiff struct {
+	Path string // e.g. "$.positions[0].size"
+	A    string
+	B    string
+}
+
+// Equal reports whether responses have the same text, tool calls and fields
+func (d ResponseDiff) Equal() bool {
+	return (d.TextEqual || (d.JSON && len(d.Fields) == 0)) && len(d.ToolCalls) == 0
+}
+
+// CompareResponses returns structured diff of two responses
+//
+// Text similarity is the word overlap of the texts; use CompareResponsesWith for
+// semantic similarity. JSON objects in the texts (e.g. trade decisions) are compared
+// field by field, and tool calls by name and arguments.
+//
+// Usage example:
+//   diff := mcp.CompareResponses(*production, *candidate)
+//   for _, field := range diff.Fields {
+//       log.Printf("%s: %s -> %s", field.Path, field.A, field.B)
+//   }
+func CompareResponses(a, b Response) ResponseDiff {
+	diff := ResponseDiff{
+		TextEqual:        strings.TrimSpace(a.Text) == strings.TrimSpace(b.Text),
+		Similarity:       tokenOverlap(a.Text, b.Text),
+		SimilarityMethod: SimilarityTokenOverlap,
+		ToolCalls:        diffToolCalls(a.ToolCalls, b.ToolCalls),
+	}
+	diff.Fields, diff.JSON = diffJSONText(a.Text, b.Text)
+	return diff
+}
+
+// CompareResponsesWith is CompareResponses with semantic text similarity from embedder
+func CompareResponsesWith(ctx context.Context, embedder Embedder, a, b Response) (ResponseDiff, error) {
+	diff := CompareResponses(a, b)
+	if diff.TextEqual {
+		diff.Similarity, diff.SimilarityMethod = 1, SimilarityEmbedding
+		return diff, nil
+	}
+	result, err := embedder.Embed(ctx, []string{a.Text, b.Text})
+	if err != nil {
+		return diff, fmt.Errorf("embed responses: %w", err)
+	}
+	if len(result.Vectors) != 2 {
+		return diff, fmt.Errorf("embed responses: expected 2 vectors, got %d", len(result.Vectors))
+	}
+	diff.Similarity = max(cosineSimilarity(result.Vectors[0], result.Vectors[1]), 0)
+	diff.SimilarityMethod = SimilarityEmbedding
+	return diff, nil
+}
+
+// tokenOverlap returns Jaccard similarity of the lowercase word sets of a and b
+func tokenOverlap(a, b string) float64 {
+	words := func(text string) map[string]bool {
+		set := map[string]bool{}
+		for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+		}) {
+			set[strings.Trim(word, ".")] = true
+		}
+		delete(set, "")
+		return set
+	}
+	setA, setB := words(a), words(b)
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+	shared := 0
+	for word := range setA {
+		if setB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+// cosineSimilarity returns cosine of the angle between vectors (0 for empty or mismatched)
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// diffToolCalls compares tool calls position by position
+func diffToolCalls(a, b []ToolCall) []ToolCallDiff {
+	describe := func(calls []ToolCall, i int) string {
+		if i >= len(calls) {
+			return ""
+		}
+		return calls[i].Function.Name + "(" + canonicalJSON(calls[i].Function.Arguments) + ")"
+	}
+	var diffs []ToolCallDiff
+	for i := range max(len(a), len(b)) {
+		if da, db := describe(a, i), describe(b, i); da != db {
+			diffs = append(diffs, ToolCallDiff{Index: i, A: da, B: db})
+		}
+	}
+	return diffs
+}
+
+// diffJSONText compares JSON objects embedded in texts field by field
+func diffJSONText(a, b string) ([]JSONFieldDiff, bool) {
+	var objA, objB map[string]any
+	if json.Unmarshal([]byte(extractJSONObject(a)), &objA) != nil || json.Unmarshal([]byte(extractJSONObject(b)), &objB) != nil {
+		return nil, false
+	}
+	fieldsA, fieldsB := map[string]string{}, map[string]string{}
+	flattenJSON("$", objA, fieldsA)
+	flattenJSON("$", objB, fieldsB)
+
+	paths := make([]string, 0, len(fieldsA)+len(fieldsB))
+	for path := range fieldsA {
+		paths = append(paths, path)
+	}
+	for path := range fieldsB {
+		if _, ok := fieldsA[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var diffs []JSONFieldDiff
+	for _, path := range paths {
+		if fieldsA[path] != fieldsB[path] {
+			diffs = append(diffs, JSONFieldDiff{Path: path, A: fieldsA[path], B: fieldsB[path]})
+		}
+	}
+	return diffs, true
+}
+
+// flattenJSON adds leaf values of v to out by path
+func flattenJSON(path string, v any, out map[string]string) {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, child := range value {
+			flattenJSON(path+"."+key, child, out)
+		}
+	case []any:
+		for i, child := range value {
+			flattenJSON(fmt.Sprintf("%s[%d]", path, i), child, out)
+		}
+	default:
+		encoded, _ := json.Marshal(value)
+		out[path] = string(encoded)
+	}
+}
+
+// canonicalJSON re-encodes JSON text with sorted keys (text unchanged when not JSON)
+func canonicalJSON(text string) string {
+	var v any
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return text
+	}
+	encoded, _ := json.Marshal(v)
+	return string(encoded)
+}
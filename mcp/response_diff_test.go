@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// vectorEmbedder returns fixed vectors by input text
+type vectorEmbedder map[string][]float64
+
+func (e vectorEmbedder) Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error) {
+	result := &EmbeddingResponse{}
+	for _, input := range inputs {
+		vector, ok := e[input]
+		if !ok {
+			return nil, errors.New("unknown input")
+		}
+		result.Vectors = append(result.Vectors, vector)
+	}
+	return result, nil
+}
+
+func TestCompareResponses_JSONFields(t *testing.T) {
+	a := Response{Text: "```json\n{\"action\":\"open_long\",\"symbol\":\"BTCUSDT\",\"leverage\":3,\"risk_flags\":[\"high_volatility\"]}\n```"}
+	b := Response{Text: `{"action":"open_long","symbol":"BTCUSDT","leverage":5,"stop_loss":60000}`}
+
+	diff := CompareResponses(a, b)
+	if !diff.JSON || diff.TextEqual || diff.Equal() {
+		t.Fatalf("expected differing JSON responses, got %+v", diff)
+	}
+	want := []JSONFieldDiff{
+		{Path: "$.leverage", A: "3", B: "5"},
+		{Path: "$.risk_flags[0]", A: `"high_volatility"`, B: ""},
+		{Path: "$.stop_loss", A: "", B: "60000"},
+	}
+	if len(diff.Fields) != len(want) {
+		t.Fatalf("expected fields %+v, got %+v", want, diff.Fields)
+	}
+	for i := range want {
+		if diff.Fields[i] != want[i] {
+			t.Errorf("field %d: expected %+v, got %+v", i, want[i], diff.Fields[i])
+		}
+	}
+
+	same := CompareResponses(Response{Text: `{"a":1,"b":2}`}, Response{Text: ` {"b":2, "a":1}`})
+	if !same.Equal() {
+		t.Errorf("reordered JSON should be equal, got %+v", same)
+	}
+}
+
+func TestCompareResponses_TextAndToolCalls(t *testing.T) {
+	call := func(name, args string) ToolCall {
+		return ToolCall{Type: "function", Function: ToolCallFunction{Name: name, Arguments: args}}
+	}
+	a := Response{Text: "Buy BTC now", ToolCalls: []ToolCall{call("get_price", `{"symbol":"BTC","tf":"1h"}`)}}
+	b := Response{Text: "buy BTC later", ToolCalls: []ToolCall{call("get_price", `{"tf":"1h","symbol":"BTC"}`), call("get_funding", `{}`)}}
+
+	diff := CompareResponses(a, b)
+	if diff.JSON || diff.SimilarityMethod != SimilarityTokenOverlap || diff.Similarity != 0.5 {
+		t.Errorf("expected word overlap 0.5, got %+v", diff)
+	}
+	if len(diff.ToolCalls) != 1 || diff.ToolCalls[0].Index != 1 || diff.ToolCalls[0].A != "" || diff.ToolCalls[0].B != "get_funding({})" {
+		t.Errorf("expected only the extra tool call, got %+v", diff.ToolCalls)
+	}
+}
+
+func TestCompareResponsesWith_Embeddings(t *testing.T) {
+	embedder := vectorEmbedder{"bullish": {1, 0}, "very bullish": {1, 1}}
+	diff, err := CompareResponsesWith(context.Background(), embedder, Response{Text: "bullish"}, Response{Text: "very bullish"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.SimilarityMethod != SimilarityEmbedding || diff.Similarity < 0.707 || diff.Similarity > 0.708 {
+		t.Errorf("expected cosine similarity 0.707, got %+v", diff)
+	}
+	if _, err := CompareResponsesWith(context.Background(), embedder, Response{Text: "x"}, Response{Text: "y"}); err == nil {
+		t.Error("expected embedder error")
+	}
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrResponseTooLarge is matched (errors.Is) by ResponseTooLargeError
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ResponseTooLargeError response body exceeded the configured limit
+type ResponseTooLargeError struct {
+	Limit int64 // Configured maximum in bytes
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds limit of %d bytes", e.Limit)
+}
+
+func (e *ResponseTooLargeError) Is(target error) bool {
+	return target == ErrResponseTooLarge
+}
+
+// WithMaxResponseBytes caps the size of response bodies read from the provider
+//
+// Reading stops with a *ResponseTooLargeError once more than n bytes arrive, instead of
+// buffering whatever a misbehaving endpoint sends. Applies to streamed bodies too,
+// since the limit is enforced on the reader. 0 = unlimited (default).
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithMaxResponseBytes(4 << 20))
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Config) {
+		c.MaxResponseBytes = n
+	}
+}
+
+// limitBody wraps r so that reading more than limit bytes fails (limit <= 0 = unlimited)
+func limitBody(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+	return &limitedReader{r: io.LimitReader(r, limit+1), limit: limit}
+}
+
+// limitedReader fails with ResponseTooLargeError once more than limit bytes were read
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		// Drop the byte past the limit so callers never see more than limit bytes
+		n -= int(l.read - l.limit)
+		l.read = l.limit
+		return n, &ResponseTooLargeError{Limit: l.limit}
+	}
+	return n, err
+}
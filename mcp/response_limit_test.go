@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test Response Size Limits
+// ============================================================
+
+func TestMaxResponseBytes_RejectsOversizedResponse(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse(strings.Repeat("x", 4096))
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(1),
+		WithMaxResponseBytes(1024),
+	)
+
+	_, err := client.CallWithMessages("system", "user")
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 1024 {
+		t.Errorf("expected *ResponseTooLargeError with limit 1024, got %v", err)
+	}
+}
+
+func TestMaxResponseBytes_AllowsResponseWithinLimit(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("small answer")
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxResponseBytes(1024),
+	)
+
+	result, err := client.CallWithMessages("system", "user")
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != "small answer" {
+		t.Errorf("expected 'small answer', got %q", result)
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		limit   int64
+		wantErr bool
+	}{
+		{"unlimited", strings.Repeat("a", 100), 0, false},
+		{"exactly at limit", strings.Repeat("a", 10), 10, false},
+		{"one byte over", strings.Repeat("a", 11), 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := io.ReadAll(limitBody(strings.NewReader(tt.input), tt.limit))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+			if tt.limit > 0 && int64(len(data)) > tt.limit {
+				t.Errorf("read %d bytes, more than limit %d", len(data), tt.limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ResponseProcessor transforms response text before it is returned
+type ResponseProcessor interface {
+	Process(ctx context.Context, text string) (string, error)
+}
+
+// ProcessorFunc adapts a function to ResponseProcessor
+type ProcessorFunc func(ctx context.Context, text string) (string, error)
+
+func (f ProcessorFunc) Process(ctx context.Context, text string) (string, error) {
+	return f(ctx, text)
+}
+
+// processorsKey context key of per-call response processors
+type processorsKey struct{}
+
+var (
+	// fencePattern a markdown code fence line, with optional language
+	fencePattern = regexp.MustCompile("(?m)^[ \t]*```[a-zA-Z0-9_+-]*[ \t]*$\n?")
+	// blankLinesPattern runs of blank lines
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// StripCodeFences removes markdown code fence lines, keeping their content
+func StripCodeFences() ResponseProcessor {
+	return ProcessorFunc(func(ctx context.Context, text string) (string, error) {
+		return strings.TrimSpace(fencePattern.ReplaceAllString(text, "")), nil
+	})
+}
+
+// NormalizeWhitespace trims lines and collapses runs of blank lines and spaces
+func NormalizeWhitespace() ResponseProcessor {
+	return ProcessorFunc(func(ctx context.Context, text string) (string, error) {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.Join(strings.Fields(line), " ")
+		}
+		return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")), nil
+	})
+}
+
+// ExtractJSON keeps only the outermost JSON object of the text (unchanged when there is none)
+func ExtractJSON() ResponseProcessor {
+	return ProcessorFunc(func(ctx context.Context, text string) (string, error) {
+		return extractJSONObject(text), nil
+	})
+}
+
+// TranslateTo translates the text into language using translator (e.g. a cheap model)
+func TranslateTo(translator AIClient, language string) ResponseProcessor {
+	return ProcessorFunc(func(ctx context.Context, text string) (string, error) {
+		if DetectLanguage(text) == strings.ToLower(language) {
+			return text, nil
+		}
+		request := NewRequestBuilder().
+			WithSystemPrompt(fmt.Sprintf("Translate the user's text into %s. Keep numbers, symbols, JSON keys and formatting unchanged. Reply with the translation only.", languageName(strings.ToLower(language)))).
+			WithUserPrompt(text).
+			MustBuild()
+		return callRequest(ctx, translator, request)
+	})
+}
+
+// WithResponseProcessors sets processors applied in order to the text of every response
+//
+// Processors run before output guardrails, so guardrails see the final text. A processor
+// error fails the call.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithResponseProcessors(mcp.StripCodeFences(), mcp.ExtractJSON()))
+func WithResponseProcessors(processors ...ResponseProcessor) ClientOption {
+	return func(c *Config) {
+		c.ResponseProcessors = processors
+	}
+}
+
+// ContextWithProcessors returns ctx whose calls use processors instead of the client's
+//
+// Usage example:
+//   ctx := mcp.ContextWithProcessors(ctx, mcp.NormalizeWhitespace())
+//   response, err := client.Call(ctx, request)
+func ContextWithProcessors(ctx context.Context, processors ...ResponseProcessor) context.Context {
+	return context.WithValue(ctx, processorsKey{}, processors)
+}
+
+// processResponse runs the response processors of the call on text
+func (client *Client) processResponse(ctx context.Context, text string) (string, error) {
+	processors, ok := ctx.Value(processorsKey{}).([]ResponseProcessor)
+	if !ok {
+		processors = client.config.ResponseProcessors
+	}
+	for i, processor := range processors {
+		var err error
+		if text, err = processor.Process(ctx, text); err != nil {
+			return "", fmt.Errorf("response processor %d: %w", i+1, err)
+		}
+	}
+	return text, nil
+}
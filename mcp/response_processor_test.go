@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResponseProcessors_BuiltIn(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		processor ResponseProcessor
+		in, want  string
+	}{
+		{"fences", StripCodeFences(), "```json\n{\"action\":\"wait\"}\n```", `{"action":"wait"}`},
+		{"whitespace", NormalizeWhitespace(), "  Hold   BTC.\r\n\n\n\n  Watch  ETH.  ", "Hold BTC.\n\nWatch ETH."},
+		{"json", ExtractJSON(), "Decision: {\"action\":\"open_long\"} done", `{"action":"open_long"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := tt.processor.Process(ctx, tt.in); err != nil || got != tt.want {
+				t.Errorf("expected %q, got %q (%v)", tt.want, got, err)
+			}
+		})
+	}
+
+	translator := &stubClient{answer: "Hold the long position."}
+	got, err := TranslateTo(translator, "en").Process(ctx, "继续持有多头仓位，比特币的趋势仍然向上。")
+	if err != nil || got != "Hold the long position." || len(translator.prompts) != 1 {
+		t.Errorf("expected translated text, got %q (%v)", got, err)
+	}
+}
+
+func TestResponseProcessors_ClientAndCall(t *testing.T) {
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("```json\\n{\\\"action\\\":\\\"wait\\\"}\\n```")
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(mock.ToHTTPClient()),
+		WithResponseProcessors(StripCodeFences(), ProcessorFunc(func(ctx context.Context, text string) (string, error) {
+			return strings.ToUpper(text), nil
+		}))).(*Client)
+
+	response, err := client.CallMessages(context.Background(), "system", "user")
+	if err != nil || response.Text != `{"ACTION":"WAIT"}` {
+		t.Errorf("expected client processors in order, got %q (%v)", response.Text, err)
+	}
+
+	ctx := ContextWithProcessors(context.Background(), ExtractJSON())
+	if response, err = client.CallMessages(ctx, "system", "user"); err != nil || response.Text != `{"action":"wait"}` {
+		t.Errorf("per-call processors should replace the client's, got %q (%v)", response.Text, err)
+	}
+
+	failing := ContextWithProcessors(context.Background(), ProcessorFunc(func(ctx context.Context, text string) (string, error) {
+		return "", errors.New("boom")
+	}))
+	if _, err := client.CallMessages(failing, "system", "user"); err == nil || !strings.Contains(err.Error(), "response processor 1: boom") {
+		t.Errorf("expected processor error, got %v", err)
+	}
+}
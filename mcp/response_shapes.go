@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// responseShape alternative response body layout tried when the OpenAI-compatible one doesn't match
+type responseShape struct {
+	name  string
+	parse func(client *Client, body []byte) (string, error) // errNoShapeMatch (wrapped) when the body isn't this shape
+}
+
+// errNoShapeMatch the body doesn't have the layout of a response shape
+var errNoShapeMatch = errors.New("no match")
+
+// responseShapes tried in order by parseAlternativeShapes
+var responseShapes = []responseShape{
+	{"ollama", parseOllamaShape},
+	{"anthropic", parseAnthropicShape},
+	{"text", parseTextShape},
+	{"error", parseErrorShape},
+}
+
+// parseAlternativeShapes extracts answer text from a response without OpenAI choices
+//
+// Gateways and self-hosted servers behind a custom BaseURL don't always answer in the
+// OpenAI format: Ollama's native API, Anthropic-style proxies and simple wrappers
+// returning a bare text field are recognized. An error envelope in a 200 response is
+// returned as *APIError. When nothing matches, the error lists every shape tried.
+func (client *Client) parseAlternativeShapes(body []byte) (string, error) {
+	attempts := []string{"openai: empty response"}
+	for _, shape := range responseShapes {
+		text, err := shape.parse(client, body)
+		if err == nil {
+			client.logger.Debugf("[%s] Response parsed as %s shape", client.String(), shape.name)
+			return text, nil
+		}
+		if !errors.Is(err, errNoShapeMatch) {
+			return "", err
+		}
+		attempts = append(attempts, shape.name+": "+strings.TrimPrefix(err.Error(), errNoShapeMatch.Error()+": "))
+	}
+	return "", fmt.Errorf("unrecognized response shape (tried %s)", strings.Join(attempts, "; "))
+}
+
+// parseOllamaShape reads Ollama's native chat ({"message":{...}}) or generate ({"response":...}) answer
+func parseOllamaShape(client *Client, body []byte) (string, error) {
+	var result struct {
+		Message *struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Response *string `json:"response"`
+		Done     *bool   `json:"done"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.Done == nil {
+		return "", fmt.Errorf("%w: no done flag", errNoShapeMatch)
+	}
+	switch {
+	case result.Message != nil:
+		return result.Message.Content, nil
+	case result.Response != nil:
+		return *result.Response, nil
+	}
+	return "", fmt.Errorf("%w: no message or response", errNoShapeMatch)
+}
+
+// parseAnthropicShape reads text blocks of an Anthropic Messages answer
+func parseAnthropicShape(client *Client, body []byte) (string, error) {
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || len(result.Content) == 0 || result.Content[0].Type == "" {
+		return "", fmt.Errorf("%w: no content blocks", errNoShapeMatch)
+	}
+	var text strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+// parseTextShape reads a bare top-level text field
+func parseTextShape(client *Client, body []byte) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", fmt.Errorf("%w: not an object", errNoShapeMatch)
+	}
+	for _, name := range []string{"text", "output_text", "generated_text", "output", "result"} {
+		var text string
+		if json.Unmarshal(fields[name], &text) == nil && text != "" {
+			return text, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no text field", errNoShapeMatch)
+}
+
+// parseErrorShape turns an error envelope returned with status 200 into *APIError
+func parseErrorShape(client *Client, body []byte) (string, error) {
+	var result struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || len(result.Error) == 0 || string(result.Error) == "null" {
+		return "", fmt.Errorf("%w: no error field", errNoShapeMatch)
+	}
+	return "", client.newAPIError(http.StatusOK, body)
+}
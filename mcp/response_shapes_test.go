@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ============================================================
+// Test fallback response shapes
+// ============================================================
+
+func TestClient_ParseAlternativeShapes(t *testing.T) {
+	client := NewClient(WithLogger(NewNoopLogger())).(*Client)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"ollama chat", `{"model":"llama3","message":{"role":"assistant","content":"hold"},"done":true}`, "hold"},
+		{"ollama generate", `{"model":"llama3","response":"buy","done":true}`, "buy"},
+		{"anthropic", `{"type":"message","content":[{"type":"thinking","thinking":"..."},{"type":"text","text":"sell"}]}`, "sell"},
+		{"raw text", `{"generated_text":"wait"}`, "wait"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := client.parseMCPResponse([]byte(tt.body))
+			if err != nil || text != tt.want {
+				t.Errorf("got %q, %v; want %q", text, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ParseAlternativeShapesErrors(t *testing.T) {
+	client := NewClient(WithLogger(NewNoopLogger())).(*Client)
+
+	_, err := client.parseMCPResponse([]byte(`{"error":{"message":"Incorrect API key provided"}}`))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("expected classified APIError from error envelope, got %v", err)
+	}
+
+	_, err = client.parseMCPResponse([]byte(`{"id":"x","data":{}}`))
+	if err == nil {
+		t.Fatal("expected error for unknown shape")
+	}
+	for _, shape := range []string{"openai", "ollama", "anthropic", "text", "error"} {
+		if !strings.Contains(err.Error(), shape+":") {
+			t.Errorf("error must list attempted shape %s, got %v", shape, err)
+		}
+	}
+}
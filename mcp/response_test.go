@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestCallMessages_ReturnsMetadata(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"model":"deepseek-chat-v3","choices":[{"message":{"content":"hold","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_price","arguments":"{\"symbol\":\"BTC\"}"}}]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`
+
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	ctx := ContextWithRequestID(context.Background(), "req-349")
+	response, err := client.CallMessages(ctx, "system", "user")
+	if err != nil {
+		t.Fatalf("CallMessages failed: %v", err)
+	}
+
+	if response.Text != "hold" {
+		t.Errorf("Text = %q, want hold", response.Text)
+	}
+	if response.Model != "deepseek-chat-v3" {
+		t.Errorf("Model = %q, want model reported by provider", response.Model)
+	}
+	if response.Provider != ProviderDeepSeek {
+		t.Errorf("Provider = %q, want %q", response.Provider, ProviderDeepSeek)
+	}
+	if response.RequestID != "req-349" {
+		t.Errorf("RequestID = %q, want req-349", response.RequestID)
+	}
+	if response.FinishReason != FinishReasonToolCalls {
+		t.Errorf("FinishReason = %q, want %q", response.FinishReason, FinishReasonToolCalls)
+	}
+	if response.Usage.TotalTokens != 15 {
+		t.Errorf("Usage.TotalTokens = %d, want 15", response.Usage.TotalTokens)
+	}
+	if response.Latency <= 0 {
+		t.Error("Latency should be set")
+	}
+	if string(response.Raw) != answer {
+		t.Errorf("Raw = %s, want provider body", response.Raw)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].Function.Name != "get_price" || response.ToolCalls[0].Function.Arguments != `{"symbol":"BTC"}` {
+		t.Errorf("ToolCalls = %+v", response.ToolCalls)
+	}
+
+	// Compat wrapper keeps returning the bare text
+	text, err := client.CallWithMessages("system", "user")
+	if err != nil || text != "hold" {
+		t.Errorf("CallWithMessages = %q, %v", text, err)
+	}
+}
+
+func TestParseToolCalls_Anthropic(t *testing.T) {
+	body := []byte(`{"content":[{"type":"text","text":"Checking"},{"type":"tool_use","id":"toolu_1","name":"get_price","input":{"symbol":"ETH"}},{"type":"tool_use","id":"toolu_2","name":"json_response","input":{}}]}`)
+
+	calls := parseToolCalls(body)
+	if len(calls) != 1 {
+		t.Fatalf("got %d tool calls, want 1 (json_response excluded): %+v", len(calls), calls)
+	}
+	if calls[0].ID != "toolu_1" || calls[0].Type != "function" || calls[0].Function.Arguments != `{"symbol":"ETH"}` {
+		t.Errorf("unexpected tool call %+v", calls[0])
+	}
+}
+
+func TestResponse_ExposesRedactedHeaders(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		header := make(http.Header)
+		header.Set("X-Ratelimit-Remaining-Requests", "42")
+		header.Set("Set-Cookie", "session=secret")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"choices":[{"message":{"content":"ok"}}]}`)),
+			Header:     header,
+		}, nil
+	}
+
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	response, err := client.Call(context.Background(), NewRequestBuilder().WithUserPrompt("hi").MustBuild())
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", response.StatusCode)
+	}
+	if got := response.Header.Get("X-Ratelimit-Remaining-Requests"); got != "42" {
+		t.Errorf("rate limit header = %q, want 42", got)
+	}
+	if got := response.Header.Get("Set-Cookie"); got != redactedValue {
+		t.Errorf("Set-Cookie = %q, want redacted", got)
+	}
+}
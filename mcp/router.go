@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Route route of a RouterClient call
+type Route string
+
+const (
+	RouteSimple  Route = "simple"  // Cheap/fast model
+	RouteComplex Route = "complex" // Premium model
+)
+
+// ComplexityClassifier decides the route of a request
+type ComplexityClassifier func(ctx context.Context, req *Request) (Route, error)
+
+// RouterConfig router configuration
+type RouterConfig struct {
+	Simple  AIClient // Cheap/fast model
+	Complex AIClient // Premium model, also used when classification fails
+
+	// Classifier decides the route (nil = HeuristicClassifier(DefaultComplexityTokens))
+	Classifier ComplexityClassifier
+	// OnRoute is called with the route of every call (optional)
+	OnRoute func(route Route, req *Request)
+
+	// DataResidency regions routes must be served from (nil = any): a route outside
+	// them is replaced by the other one, and calls fail with ErrNoCompliantEndpoint
+	// when neither is compliant. The classifier model of ModelClassifier is not checked.
+	DataResidency []string
+}
+
+// DefaultComplexityTokens prompt size from which HeuristicClassifier routes to the premium model
+const DefaultComplexityTokens = 1500
+
+// complexityMarkers phrases of prompts asking for multi-step reasoning
+var complexityMarkers = []string{
+	"step by step", "analyze", "analyse", "compare", "evaluate", "explain why",
+	"strategy", "trade-off", "tradeoff", "multi-timeframe", "reason about", "prove",
+	"分析", "比较", "策略", "推理",
+}
+
+// RouteMetrics usage of one route
+type RouteMetrics struct {
+	Calls            int
+	Errors           int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64       // Estimated USD
+	Latency          time.Duration // Total wall time
+}
+
+// RouterMetrics usage per route and estimated savings
+type RouterMetrics struct {
+	Routes map[Route]RouteMetrics
+	// Savings estimated USD saved by simple calls compared to sending them to the
+	// premium model (needs registered prices of both models)
+	Savings float64
+}
+
+// RouterClient sends simple prompts to a cheap model and complex ones to a premium model
+//
+// RouterClient implements AIClient. Token usage and cost are recorded when the route
+// clients are (provider) *Client values; other clients only count calls and latency.
+type RouterClient struct {
+	config RouterConfig
+
+	mu      sync.Mutex
+	metrics RouterMetrics
+}
+
+// NewRouterClient creates router between a cheap and a premium model
+//
+// Usage example:
+//   router := mcp.NewRouterClient(mcp.RouterConfig{
+//       Simple:  mcp.NewQwenClientWithOptions(mcp.WithModel("qwen-turbo")),
+//       Complex: mcp.NewClaudeClientWithOptions(),
+//   })
+//   answer, err := router.CallWithMessages(systemPrompt, userPrompt)
+//   log.Printf("saved $%.4f", router.Metrics().Savings)
+func NewRouterClient(config RouterConfig) *RouterClient {
+	if config.Classifier == nil {
+		config.Classifier = HeuristicClassifier(DefaultComplexityTokens)
+	}
+	return &RouterClient{config: config, metrics: RouterMetrics{Routes: map[Route]RouteMetrics{}}}
+}
+
+// HeuristicClassifier routes to the premium model when the prompt is long (at least
+// maxSimpleTokens), uses tools or a response schema, or asks for multi-step reasoning
+func HeuristicClassifier(maxSimpleTokens int) ComplexityClassifier {
+	return func(ctx context.Context, req *Request) (Route, error) {
+		if len(req.Tools) > 0 || (req.ResponseFormat != nil && req.ResponseFormat.Schema != nil) {
+			return RouteComplex, nil
+		}
+		var prompt strings.Builder
+		for _, message := range req.Messages {
+			prompt.WriteString(message.Content)
+			prompt.WriteByte('\n')
+		}
+		text := prompt.String()
+		if EstimateTokens(text) >= maxSimpleTokens {
+			return RouteComplex, nil
+		}
+		lower := strings.ToLower(text)
+		for _, marker := range complexityMarkers {
+			if strings.Contains(lower, marker) {
+				return RouteComplex, nil
+			}
+		}
+		return RouteSimple, nil
+	}
+}
+
+// ModelClassifier asks a (tiny) model whether the request is simple or complex
+func ModelClassifier(classifier AIClient) ComplexityClassifier {
+	return func(ctx context.Context, req *Request) (Route, error) {
+		var prompt strings.Builder
+		for _, message := range req.Messages {
+			fmt.Fprintf(&prompt, "[%s]\n%s\n", message.Role, message.Content)
+		}
+		answer, err := callRequest(ctx, classifier, &Request{Messages: []Message{
+			NewSystemMessage("Classify whether answering the following request needs a premium model. " +
+				"Reply with exactly one word: SIMPLE for lookups, formatting and short factual answers, " +
+				"COMPLEX for multi-step reasoning, analysis or planning."),
+			NewUserMessage(prompt.String()),
+		}})
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(strings.ToUpper(answer), "SIMPLE") {
+			return RouteSimple, nil
+		}
+		return RouteComplex, nil
+	}
+}
+
+// SetAPIKey is a no-op: route clients are configured individually
+func (r *RouterClient) SetAPIKey(apiKey string, customURL string, customModel string) {}
+
+// SetTimeout sets timeout of both route clients
+func (r *RouterClient) SetTimeout(timeout time.Duration) {
+	r.config.Simple.SetTimeout(timeout)
+	r.config.Complex.SetTimeout(timeout)
+}
+
+// CallWithMessages routes system/user prompt
+func (r *RouterClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	var messages []Message
+	if systemPrompt != "" {
+		messages = append(messages, NewSystemMessage(systemPrompt))
+	}
+	messages = append(messages, NewUserMessage(userPrompt))
+	return r.CallWithRequestContext(context.Background(), &Request{Messages: messages})
+}
+
+// CallWithRequest routes req
+func (r *RouterClient) CallWithRequest(req *Request) (string, error) {
+	return r.CallWithRequestContext(context.Background(), req)
+}
+
+// CallWithRequestContext routes req, honoring ctx cancellation
+func (r *RouterClient) CallWithRequestContext(ctx context.Context, req *Request) (string, error) {
+	route, err := r.config.Classifier(ctx, req)
+	if err != nil || (route != RouteSimple && route != RouteComplex) {
+		route = RouteComplex
+	}
+
+	client := r.config.Complex
+	if route == RouteSimple {
+		client = r.config.Simple
+	}
+	if regions := r.config.DataResidency; len(regions) > 0 && !residencyCompliant(client, regions) {
+		other, otherRoute := r.config.Simple, RouteSimple
+		if route == RouteSimple {
+			other, otherRoute = r.config.Complex, RouteComplex
+		}
+		if !residencyCompliant(other, regions) {
+			err = fmt.Errorf("%w: no route is in regions %s", ErrNoCompliantEndpoint, strings.Join(regions, ", "))
+			r.record(route, TokenUsage{}, 0, err)
+			return "", err
+		}
+		client, route = other, otherRoute
+	}
+	if r.config.OnRoute != nil {
+		r.config.OnRoute(route, req)
+	}
+
+	// Each route fills in its own model
+	routed := *req
+
+	start := time.Now()
+	var usage TokenUsage
+	var text string
+	if base, ok := BaseClient(client); ok {
+		var response *Response
+		if response, err = base.Call(ctx, &routed); err == nil {
+			text, usage = response.Text, response.Usage
+			if usage.Model == "" {
+				usage.Model = response.Model
+			}
+		}
+	} else {
+		text, err = callRequest(ctx, client, &routed)
+	}
+	r.record(route, usage, time.Since(start), err)
+	return text, err
+}
+
+// Metrics returns usage per route
+func (r *RouterClient) Metrics() RouterMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	metrics := RouterMetrics{Routes: make(map[Route]RouteMetrics, len(r.metrics.Routes)), Savings: r.metrics.Savings}
+	for route, m := range r.metrics.Routes {
+		metrics.Routes[route] = m
+	}
+	return metrics
+}
+
+// record adds a call to the metrics of route
+func (r *RouterClient) record(route Route, usage TokenUsage, latency time.Duration, err error) {
+	cost := EstimateCost(usage)
+	var savings float64
+	if route == RouteSimple && err == nil {
+		if premium, ok := BaseClient(r.config.Complex); ok {
+			premiumUsage := usage
+			premiumUsage.Model = premium.Model
+			premiumUsage.CacheReadTokens, premiumUsage.CacheWriteTokens = 0, 0
+			if premiumCost := EstimateCost(premiumUsage); premiumCost > 0 {
+				savings = premiumCost - cost
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.metrics.Routes[route]
+	m.Calls++
+	if err != nil {
+		m.Errors++
+	}
+	m.PromptTokens += usage.PromptTokens
+	m.CompletionTokens += usage.CompletionTokens
+	m.Cost += cost
+	m.Latency += latency
+	r.metrics.Routes[route] = m
+	r.metrics.Savings += savings
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHeuristicClassifier(t *testing.T) {
+	classify := HeuristicClassifier(50)
+	tests := []struct {
+		name string
+		req  *Request
+		want Route
+	}{
+		{"short lookup", NewRequestBuilder().WithUserPrompt("BTC price?").MustBuild(), RouteSimple},
+		{"long prompt", NewRequestBuilder().WithUserPrompt(strings.Repeat("candle data ", 40)).MustBuild(), RouteComplex},
+		{"reasoning marker", NewRequestBuilder().WithUserPrompt("Analyze the funding rate").MustBuild(), RouteComplex},
+		{"schema", &Request{Messages: []Message{NewUserMessage("hi")}, ResponseFormat: &ResponseFormat{Schema: TradeDecisionSchema}}, RouteComplex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := classify(context.Background(), tt.req); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRouterClient_RoutesAndMeasuresSavings(t *testing.T) {
+	RegisterModelPrice("router-cheap", ModelPrice{InputPerMillion: 1000, OutputPerMillion: 1000})
+	RegisterModelPrice("router-premium", ModelPrice{InputPerMillion: 10000, OutputPerMillion: 10000})
+	response := `{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":100,"completion_tokens":100,"total_tokens":200}}`
+	var cheapBodies, premiumBodies []map[string]any
+	newRoute := func(model string, bodies *[]map[string]any) AIClient {
+		return NewClient(WithProvider(ProviderCustom), WithBaseURL("https://api.example.com/v1"), WithAPIKey("k"),
+			WithModel(model), WithHTTPClient(captureBodyClient(response, bodies)))
+	}
+	router := NewRouterClient(RouterConfig{
+		Simple:  newRoute("router-cheap", &cheapBodies),
+		Complex: newRoute("router-premium", &premiumBodies),
+	})
+
+	if _, err := router.CallWithMessages("", "BTC price?"); err != nil {
+		t.Fatalf("simple call: %v", err)
+	}
+	if _, err := router.CallWithMessages("", "Compare the two strategies step by step"); err != nil {
+		t.Fatalf("complex call: %v", err)
+	}
+	if len(cheapBodies) != 1 || cheapBodies[0]["model"] != "router-cheap" || len(premiumBodies) != 1 || premiumBodies[0]["model"] != "router-premium" {
+		t.Fatalf("unexpected routing: cheap %v premium %v", cheapBodies, premiumBodies)
+	}
+
+	metrics := router.Metrics()
+	if metrics.Routes[RouteSimple].Calls != 1 || metrics.Routes[RouteSimple].PromptTokens != 100 {
+		t.Errorf("unexpected simple metrics %+v", metrics.Routes[RouteSimple])
+	}
+	// 200 tokens: $2.00 premium vs $0.20 cheap
+	if diff := metrics.Savings - 1.8; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected savings 1.8, got %g", metrics.Savings)
+	}
+}
+
+func TestRouterClient_ClassifierErrorUsesPremium(t *testing.T) {
+	simple, complex := &stubClient{answer: "cheap"}, &stubClient{answer: "premium"}
+	var routed []Route
+	router := NewRouterClient(RouterConfig{
+		Simple:     simple,
+		Complex:    complex,
+		Classifier: ModelClassifier(&stubClient{err: errors.New("classifier down")}),
+		OnRoute:    func(route Route, req *Request) { routed = append(routed, route) },
+	})
+	answer, err := router.CallWithMessages("sys", "hi")
+	if err != nil || answer != "premium" {
+		t.Fatalf("expected premium answer, got %q (%v)", answer, err)
+	}
+	if len(routed) != 1 || routed[0] != RouteComplex || router.Metrics().Routes[RouteComplex].Calls != 1 {
+		t.Errorf("unexpected routes %v", routed)
+	}
+}
+
+func TestModelClassifier(t *testing.T) {
+	route, err := ModelClassifier(&stubClient{answer: " simple."})(context.Background(), NewRequestBuilder().WithUserPrompt("hi").MustBuild())
+	if err != nil || route != RouteSimple {
+		t.Fatalf("expected simple route, got %s (%v)", route, err)
+	}
+}
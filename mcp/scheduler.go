@@ -0,0 +1,258 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Priority scheduling class of an AI request
+type Priority int
+
+const (
+	// PriorityCritical trading decisions that must not wait behind background work
+	PriorityCritical Priority = iota
+	// PriorityNormal regular requests
+	PriorityNormal
+	// PriorityBackground enrichment, summaries and other deferrable work
+	PriorityBackground
+
+	numPriorities = 3
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityNormal:
+		return "normal"
+	case PriorityBackground:
+		return "background"
+	}
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
+// ErrQueueFull is returned when the queue of a priority class is at capacity
+var ErrQueueFull = errors.New("AI request queue is full")
+
+// ClassLimits limits of one priority class
+type ClassLimits struct {
+	MaxConcurrent int // Max requests of this class in flight (0 = only bounded by scheduler)
+	QueueDepth    int // Max requests of this class waiting (0 = no waiting, reject when busy)
+}
+
+// SchedulerConfig scheduler configuration
+type SchedulerConfig struct {
+	MaxConcurrent int                      // Max requests in flight across all classes
+	Classes       map[Priority]ClassLimits // Per-class limits (missing class = no class limit, no queue)
+}
+
+// DefaultSchedulerConfig returns configuration reserving capacity for critical requests
+//
+// Normal and background requests together hold at most 6 of the 8 slots, so at least
+// 2 stay free for critical requests however saturated the other classes are.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		MaxConcurrent: 8,
+		Classes: map[Priority]ClassLimits{
+			PriorityCritical:   {MaxConcurrent: 8, QueueDepth: 32},
+			PriorityNormal:     {MaxConcurrent: 5, QueueDepth: 32},
+			PriorityBackground: {MaxConcurrent: 1, QueueDepth: 16},
+		},
+	}
+}
+
+// SchedulerStats snapshot of scheduler state
+type SchedulerStats struct {
+	Running  [numPriorities]int
+	Queued   [numPriorities]int
+	Rejected [numPriorities]int
+}
+
+// Scheduler queues AI requests by priority with bounded depth and concurrency
+//
+// When a slot frees up, the highest-priority waiting request whose class is below its
+// concurrency limit runs next. Requests that cannot run or queue fail fast with
+// ErrQueueFull, so goroutines don't pile up when the provider slows down.
+type Scheduler struct {
+	client AIClient
+	config SchedulerConfig
+
+	mu      sync.Mutex
+	running int
+	stats   SchedulerStats
+	waiting [numPriorities][]*schedulerTicket
+}
+
+// schedulerTicket one waiting request
+type schedulerTicket struct {
+	ready chan struct{}
+}
+
+// NewScheduler creates scheduler in front of client
+//
+// Usage example:
+//   scheduler := mcp.NewScheduler(client, mcp.DefaultSchedulerConfig())
+//   decision, err := scheduler.CallWithRequest(ctx, mcp.PriorityCritical, request)
+//   if errors.Is(err, mcp.ErrQueueFull) {
+//       // shed load
+//   }
+func NewScheduler(client AIClient, config SchedulerConfig) *Scheduler {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 1
+	}
+	return &Scheduler{client: client, config: config}
+}
+
+// CallWithRequest runs request through the scheduler with the given priority
+func (s *Scheduler) CallWithRequest(ctx context.Context, priority Priority, req *Request) (string, error) {
+	if err := s.acquire(ctx, priority); err != nil {
+		return "", err
+	}
+	defer s.release(priority)
+
+	return callRequest(ctx, s.client, req)
+}
+
+// CallWithMessages runs a system/user prompt call through the scheduler
+func (s *Scheduler) CallWithMessages(ctx context.Context, priority Priority, systemPrompt, userPrompt string) (string, error) {
+	if err := s.acquire(ctx, priority); err != nil {
+		return "", err
+	}
+	defer s.release(priority)
+
+	response, err := delegateMessages(systemPrompt, userPrompt)(ctx, s.client)
+	if err != nil {
+		return "", err
+	}
+	return response.Text, nil
+}
+
+// ForPriority returns an AIClient whose calls go through the scheduler with priority
+//
+// Useful to hand existing AIClient consumers a scheduled client without code changes.
+func (s *Scheduler) ForPriority(priority Priority) AIClient {
+	return &scheduledClient{scheduler: s, priority: priority}
+}
+
+// Stats returns snapshot of running, queued and rejected requests per class
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	for p := range s.waiting {
+		stats.Queued[p] = len(s.waiting[p])
+	}
+	return stats
+}
+
+// acquire waits for a slot for priority class
+func (s *Scheduler) acquire(ctx context.Context, priority Priority) error {
+	if priority < 0 || priority >= numPriorities {
+		return fmt.Errorf("invalid priority %d", int(priority))
+	}
+
+	s.mu.Lock()
+	if s.canRun(priority) {
+		s.start(priority)
+		s.mu.Unlock()
+		return nil
+	}
+	if len(s.waiting[priority]) >= s.config.Classes[priority].QueueDepth {
+		s.stats.Rejected[priority]++
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s class has %d waiting", ErrQueueFull, priority, len(s.waiting[priority]))
+	}
+	ticket := &schedulerTicket{ready: make(chan struct{})}
+	s.waiting[priority] = append(s.waiting[priority], ticket)
+	s.mu.Unlock()
+
+	select {
+	case <-ticket.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-ticket.ready:
+			// Slot was granted while cancelling: hand it to the next waiter
+			s.running--
+			s.stats.Running[priority]--
+			s.dispatch()
+		default:
+			s.removeWaiting(priority, ticket)
+		}
+		return ctx.Err()
+	}
+}
+
+// release frees slot of priority class and wakes the next waiter
+func (s *Scheduler) release(priority Priority) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	s.stats.Running[priority]--
+	s.dispatch()
+}
+
+// dispatch grants free slots to waiting requests, highest priority first (caller holds mu)
+func (s *Scheduler) dispatch() {
+	for p := Priority(0); p < numPriorities; p++ {
+		for len(s.waiting[p]) > 0 && s.canRun(p) {
+			ticket := s.waiting[p][0]
+			s.waiting[p] = s.waiting[p][1:]
+			s.start(p)
+			close(ticket.ready)
+		}
+	}
+}
+
+// canRun reports whether a request of priority may start now (caller holds mu)
+func (s *Scheduler) canRun(priority Priority) bool {
+	if s.running >= s.config.MaxConcurrent {
+		return false
+	}
+	limit := s.config.Classes[priority].MaxConcurrent
+	return limit <= 0 || s.stats.Running[priority] < limit
+}
+
+// start marks a request of priority as running (caller holds mu)
+func (s *Scheduler) start(priority Priority) {
+	s.running++
+	s.stats.Running[priority]++
+}
+
+// removeWaiting removes ticket from the queue of priority (caller holds mu)
+func (s *Scheduler) removeWaiting(priority Priority, ticket *schedulerTicket) {
+	queue := s.waiting[priority]
+	for i, t := range queue {
+		if t == ticket {
+			s.waiting[priority] = append(queue[:i:i], queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// scheduledClient AIClient adapter routing calls through a Scheduler
+type scheduledClient struct {
+	scheduler *Scheduler
+	priority  Priority
+}
+
+func (c *scheduledClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.scheduler.client.SetAPIKey(apiKey, customURL, customModel)
+}
+
+func (c *scheduledClient) SetTimeout(timeout time.Duration) {
+	c.scheduler.client.SetTimeout(timeout)
+}
+
+func (c *scheduledClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	return c.scheduler.CallWithMessages(context.Background(), c.priority, systemPrompt, userPrompt)
+}
+
+func (c *scheduledClient) CallWithRequest(req *Request) (string, error) {
+	return c.scheduler.CallWithRequest(context.Background(), c.priority, req)
+}
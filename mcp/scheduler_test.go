@@ -0,0 +1,245 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Priority Scheduler
+// ============================================================
+
+// blockingClient AIClient whose calls block until released
+type blockingClient struct {
+	mu      sync.Mutex
+	release chan struct{}
+	order   []string
+}
+
+func newBlockingClient() *blockingClient {
+	return &blockingClient{release: make(chan struct{})}
+}
+
+func (c *blockingClient) SetAPIKey(apiKey string, customURL string, customModel string) {}
+func (c *blockingClient) SetTimeout(timeout time.Duration)                              {}
+
+func (c *blockingClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	c.mu.Lock()
+	c.order = append(c.order, userPrompt)
+	c.mu.Unlock()
+	<-c.release
+	return "ok", nil
+}
+
+func (c *blockingClient) CallWithRequest(req *Request) (string, error) {
+	return c.CallWithMessages("", "request")
+}
+
+// waitFor polls cond until true or fails the test
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestScheduler_QueueFull(t *testing.T) {
+	client := newBlockingClient()
+	scheduler := NewScheduler(client, SchedulerConfig{
+		MaxConcurrent: 1,
+		Classes: map[Priority]ClassLimits{
+			PriorityBackground: {QueueDepth: 1},
+		},
+	})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.CallWithMessages(ctx, PriorityBackground, "", "bg")
+		}()
+	}
+	waitFor(t, func() bool {
+		stats := scheduler.Stats()
+		return stats.Running[PriorityBackground] == 1 && stats.Queued[PriorityBackground] == 1
+	})
+
+	_, err := scheduler.CallWithMessages(ctx, PriorityBackground, "", "bg")
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+	if scheduler.Stats().Rejected[PriorityBackground] != 1 {
+		t.Error("rejection should be counted")
+	}
+
+	close(client.release)
+	wg.Wait()
+}
+
+func TestScheduler_CriticalRunsFirst(t *testing.T) {
+	client := newBlockingClient()
+	scheduler := NewScheduler(client, SchedulerConfig{
+		MaxConcurrent: 1,
+		Classes: map[Priority]ClassLimits{
+			PriorityCritical:   {QueueDepth: 4},
+			PriorityBackground: {QueueDepth: 4},
+		},
+	})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	call := func(priority Priority, prompt string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.CallWithMessages(ctx, priority, "", prompt)
+		}()
+	}
+
+	call(PriorityBackground, "first")
+	waitFor(t, func() bool { return scheduler.Stats().Running[PriorityBackground] == 1 })
+	call(PriorityBackground, "background")
+	waitFor(t, func() bool { return scheduler.Stats().Queued[PriorityBackground] == 1 })
+	call(PriorityCritical, "critical")
+	waitFor(t, func() bool { return scheduler.Stats().Queued[PriorityCritical] == 1 })
+
+	close(client.release)
+	wg.Wait()
+
+	want := []string{"first", "critical", "background"}
+	for i, prompt := range want {
+		if client.order[i] != prompt {
+			t.Fatalf("expected order %v, got %v", want, client.order)
+		}
+	}
+}
+
+func TestScheduler_ClassConcurrencyLimit(t *testing.T) {
+	client := newBlockingClient()
+	scheduler := NewScheduler(client, SchedulerConfig{
+		MaxConcurrent: 4,
+		Classes: map[Priority]ClassLimits{
+			PriorityBackground: {MaxConcurrent: 1, QueueDepth: 4},
+		},
+	})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.CallWithMessages(ctx, PriorityBackground, "", "bg")
+		}()
+	}
+	waitFor(t, func() bool { return scheduler.Stats().Queued[PriorityBackground] == 2 })
+
+	// Critical class still has free capacity
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scheduler.ForPriority(PriorityCritical).CallWithMessages("", "critical")
+	}()
+	waitFor(t, func() bool { return scheduler.Stats().Running[PriorityCritical] == 1 })
+
+	if running := scheduler.Stats().Running[PriorityBackground]; running != 1 {
+		t.Errorf("background concurrency should be capped at 1, got %d", running)
+	}
+
+	close(client.release)
+	wg.Wait()
+}
+
+func TestScheduler_CancelWhileQueued(t *testing.T) {
+	client := newBlockingClient()
+	scheduler := NewScheduler(client, SchedulerConfig{
+		MaxConcurrent: 1,
+		Classes:       map[Priority]ClassLimits{PriorityNormal: {QueueDepth: 1}},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		scheduler.CallWithMessages(context.Background(), PriorityNormal, "", "running")
+		close(done)
+	}()
+	waitFor(t, func() bool { return scheduler.Stats().Running[PriorityNormal] == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := scheduler.CallWithMessages(ctx, PriorityNormal, "", "queued"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if queued := scheduler.Stats().Queued[PriorityNormal]; queued != 0 {
+		t.Errorf("cancelled request should leave the queue, %d still queued", queued)
+	}
+
+	close(client.release)
+	<-done
+}
+
+func TestScheduler_DefaultConfigReservesCritical(t *testing.T) {
+	client := newBlockingClient()
+	scheduler := NewScheduler(client, DefaultSchedulerConfig())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for _, priority := range []Priority{PriorityNormal, PriorityBackground} {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				scheduler.CallWithMessages(ctx, priority, "", priority.String())
+			}()
+		}
+	}
+	waitFor(t, func() bool {
+		stats := scheduler.Stats()
+		return stats.Queued[PriorityNormal]+stats.Running[PriorityNormal] == 10 &&
+			stats.Queued[PriorityBackground]+stats.Running[PriorityBackground] == 10
+	})
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.CallWithMessages(ctx, PriorityCritical, "", "critical")
+		}()
+	}
+	waitFor(t, func() bool { return scheduler.Stats().Running[PriorityCritical] == 2 })
+
+	close(client.release)
+	wg.Wait()
+}
+
+// messagesContextClient records the context passed to CallMessages
+type messagesContextClient struct {
+	blockingClient
+	ctx context.Context
+}
+
+func (c *messagesContextClient) CallMessages(ctx context.Context, systemPrompt, userPrompt string) (*Response, error) {
+	c.ctx = ctx
+	return &Response{Text: "ok"}, ctx.Err()
+}
+
+func TestScheduler_MessagesUseContext(t *testing.T) {
+	client := &messagesContextClient{}
+	scheduler := NewScheduler(client, DefaultSchedulerConfig())
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	if got, err := scheduler.CallWithMessages(ctx, PriorityNormal, "", "hi"); err != nil || got != "ok" {
+		t.Fatalf("expected ok, got %q (%v)", got, err)
+	}
+	if RequestIDFromContext(client.ctx) != "req-1" {
+		t.Error("CallWithMessages should pass ctx to the client")
+	}
+}
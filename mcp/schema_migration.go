@@ -0,0 +1,192 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultSchemaVersionField field carrying the schema version of structured output
+const DefaultSchemaVersionField = "schema_version"
+
+// ErrSchemaMigration is returned when a structured response cannot be upgraded
+var ErrSchemaMigration = errors.New("schema migration failed")
+
+// SchemaMigration upgrades a decoded object by one schema version in place
+type SchemaMigration func(obj map[string]any) error
+
+// SchemaMigrations registry upgrading structured output of older schema versions
+//
+// Each registered migration upgrades version N to N+1; Upgrade chains them from the
+// version found in the object up to Current and stamps the new version, so cached
+// or replayed responses and answers of models prompted by older releases keep
+// parsing after the schema changes. Objects without a version field are treated as
+// Unversioned.
+//
+// Usage example:
+//   migrations := mcp.NewSchemaMigrations(3).
+//       Register(1, mcp.RenameField("size", "size_usd")).
+//       Register(2, mcp.DefaultField("risk_flags", []any{}))
+//   client := mcp.NewClient(mcp.WithJSONResponse(schema), mcp.WithSchemaMigrations(migrations))
+//   upgraded, err := migrations.Upgrade(cachedText)
+type SchemaMigrations struct {
+	Current      int    // Schema version responses are upgraded to
+	VersionField string // Field holding the version ("" = DefaultSchemaVersionField)
+	Unversioned  int    // Version assumed when the field is missing (0 = 1)
+
+	steps map[int]SchemaMigration
+}
+
+// NewSchemaMigrations creates empty registry upgrading to version current
+func NewSchemaMigrations(current int) *SchemaMigrations {
+	return &SchemaMigrations{Current: current, steps: make(map[int]SchemaMigration)}
+}
+
+// Register adds migration upgrading version from to from+1
+func (m *SchemaMigrations) Register(from int, migration SchemaMigration) *SchemaMigrations {
+	if m.steps == nil {
+		m.steps = make(map[int]SchemaMigration)
+	}
+	m.steps[from] = migration
+	return m
+}
+
+// WithSchemaMigrations upgrades every structured (JSON mode) response before schema validation
+func WithSchemaMigrations(migrations *SchemaMigrations) ClientOption {
+	return func(c *Config) {
+		c.SchemaMigrations = migrations
+	}
+}
+
+// Upgrade parses a JSON object of any registered version and returns it at Current
+func (m *SchemaMigrations) Upgrade(text string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var obj map[string]any
+	if err := decoder.Decode(&obj); err != nil {
+		return "", &JSONValidationError{Path: "$", Reason: err.Error()}
+	}
+	if err := m.UpgradeObject(obj); err != nil {
+		return "", err
+	}
+	upgraded, err := json.Marshal(obj)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSchemaMigration, err)
+	}
+	return string(upgraded), nil
+}
+
+// UpgradeObject upgrades a decoded object to Current in place
+func (m *SchemaMigrations) UpgradeObject(obj map[string]any) error {
+	version, err := m.Version(obj)
+	if err != nil {
+		return err
+	}
+	if version > m.Current {
+		return fmt.Errorf("%w: schema version %d is newer than supported version %d", ErrSchemaMigration, version, m.Current)
+	}
+	for ; version < m.Current; version++ {
+		migrate, ok := m.steps[version]
+		if !ok {
+			return fmt.Errorf("%w: no migration from schema version %d", ErrSchemaMigration, version)
+		}
+		if err := migrate(obj); err != nil {
+			return fmt.Errorf("%w: version %d to %d: %v", ErrSchemaMigration, version, version+1, err)
+		}
+	}
+	obj[m.versionField()] = m.Current
+	return nil
+}
+
+// Version returns schema version of a decoded object
+func (m *SchemaMigrations) Version(obj map[string]any) (int, error) {
+	value, ok := obj[m.versionField()]
+	if !ok {
+		return max(m.Unversioned, 1), nil
+	}
+	var version int
+	var err error
+	switch v := value.(type) {
+	case json.Number:
+		version, err = strconv.Atoi(v.String())
+	case float64:
+		version = int(v)
+	case int:
+		version = v
+	case string:
+		version, err = strconv.Atoi(strings.TrimPrefix(v, "v"))
+	default:
+		err = fmt.Errorf("unexpected type %T", value)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %v: %v", ErrSchemaMigration, m.versionField(), value, err)
+	}
+	return version, nil
+}
+
+func (m *SchemaMigrations) versionField() string {
+	if m.VersionField == "" {
+		return DefaultSchemaVersionField
+	}
+	return m.VersionField
+}
+
+// migrateJSONResponse upgrades response text with the client's schema migrations
+func (client *Client) migrateJSONResponse(response *Response) error {
+	if client.config.SchemaMigrations == nil {
+		return nil
+	}
+	upgraded, err := client.config.SchemaMigrations.Upgrade(response.Text)
+	if err != nil {
+		return err
+	}
+	response.Text = upgraded
+	return nil
+}
+
+// ============================================================
+// Common Migrations
+// ============================================================
+
+// RenameField migration moving field from to field to (no-op when from is missing)
+func RenameField(from, to string) SchemaMigration {
+	return func(obj map[string]any) error {
+		if value, ok := obj[from]; ok {
+			obj[to] = value
+			delete(obj, from)
+		}
+		return nil
+	}
+}
+
+// DefaultField migration setting field to value when it is missing
+func DefaultField(field string, value any) SchemaMigration {
+	return func(obj map[string]any) error {
+		if _, ok := obj[field]; !ok {
+			obj[field] = value
+		}
+		return nil
+	}
+}
+
+// RemoveField migration deleting field
+func RemoveField(field string) SchemaMigration {
+	return func(obj map[string]any) error {
+		delete(obj, field)
+		return nil
+	}
+}
+
+// ChainMigrations combines migrations into one step, run in order
+func ChainMigrations(migrations ...SchemaMigration) SchemaMigration {
+	return func(obj map[string]any) error {
+		for _, migrate := range migrations {
+			if err := migrate(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
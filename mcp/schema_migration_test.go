@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+func testMigrations() *SchemaMigrations {
+	return NewSchemaMigrations(3).
+		Register(1, RenameField("decision", "action")).
+		Register(2, ChainMigrations(RenameField("conf", "confidence"), DefaultField("risk_flags", []any{})))
+}
+
+func TestSchemaMigrations_UpgradeChain(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"unversioned", `{"decision":"buy","conf":0.8}`},
+		{"version 2", `{"schema_version":2,"action":"buy","conf":0.8}`},
+		{"string version", `{"schema_version":"v2","action":"buy","conf":0.8}`},
+		{"current", `{"schema_version":3,"action":"buy","confidence":0.8,"risk_flags":[]}`},
+	}
+	want := `{"action":"buy","confidence":0.8,"risk_flags":[],"schema_version":3}`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upgraded, err := testMigrations().Upgrade(tt.text)
+			if err != nil {
+				t.Fatalf("Upgrade failed: %v", err)
+			}
+			if upgraded != want {
+				t.Errorf("expected %s, got %s", want, upgraded)
+			}
+		})
+	}
+}
+
+func TestSchemaMigrations_Errors(t *testing.T) {
+	migrations := NewSchemaMigrations(3).Register(2, RemoveField("legacy"))
+
+	if _, err := migrations.Upgrade(`{"schema_version":4}`); !errors.Is(err, ErrSchemaMigration) {
+		t.Errorf("newer version should fail with ErrSchemaMigration, got %v", err)
+	}
+	if _, err := migrations.Upgrade(`{"action":"buy"}`); !errors.Is(err, ErrSchemaMigration) {
+		t.Errorf("missing step should fail with ErrSchemaMigration, got %v", err)
+	}
+	if _, err := migrations.Upgrade(`not json`); !errors.Is(err, ErrInvalidJSONResponse) {
+		t.Errorf("invalid JSON should fail with ErrInvalidJSONResponse, got %v", err)
+	}
+}
+
+func TestSchemaMigrations_AppliedBeforeValidation(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"{\"schema_version\":1,\"decision\":\"buy\",\"conf\":0.8}"}}]}`
+
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithJSONResponse(decisionSchema),
+		WithSchemaMigrations(testMigrations()),
+	)
+
+	result, err := client.CallWithMessages("system", "decide")
+	if err != nil {
+		t.Fatalf("migrated response should pass validation: %v", err)
+	}
+	if result != `{"action":"buy","confidence":0.8,"risk_flags":[],"schema_version":3}` {
+		t.Errorf("unexpected result %s", result)
+	}
+}
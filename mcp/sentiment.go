@@ -0,0 +1,278 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sentiment polarities
+const (
+	PolarityPositive = "positive"
+	PolarityNegative = "negative"
+	PolarityNeutral  = "neutral"
+)
+
+// SentimentScore sentiment of one text
+type SentimentScore struct {
+	Score    float64  `json:"score"`    // -1 (very bearish) to 1 (very bullish)
+	Polarity string   `json:"polarity"` // PolarityPositive, PolarityNegative or PolarityNeutral
+	Entities []string `json:"entities"` // Assets, companies and people mentioned
+}
+
+// sentimentSchema JSON Schema of a batch sentiment answer
+var sentimentSchema = map[string]any{
+	"type":     "object",
+	"required": []string{"results"},
+	"properties": map[string]any{
+		"results": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type":     "object",
+				"required": []string{"index", "score", "polarity", "entities"},
+				"properties": map[string]any{
+					"index":    map[string]any{"type": "integer"},
+					"score":    map[string]any{"type": "number"},
+					"polarity": map[string]any{"type": "string", "enum": []string{PolarityPositive, PolarityNegative, PolarityNeutral}},
+					"entities": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+			},
+		},
+	},
+}
+
+// sentimentPrompt system prompt of sentiment batches
+const sentimentPrompt = `You score the market sentiment of crypto and finance texts (news headlines, social posts).
+For every numbered text return its index, a score from -1 (very bearish) to 1 (very bullish),
+its polarity (positive, negative or neutral) and the assets, companies or people it mentions (tickers for assets).
+Reply only with JSON: {"results": [{"index": 0, "score": 0.4, "polarity": "positive", "entities": ["BTC"]}, ...]}`
+
+// Sentiment cache defaults
+const (
+	DefaultSentimentCacheEntries = 10000          // Scores kept by the default cache
+	DefaultSentimentCacheTTL     = 24 * time.Hour // Lifetime of a cached score
+)
+
+// SentimentCache stores sentiment scores by model and text
+type SentimentCache interface {
+	Get(key string) (SentimentScore, bool)
+	Put(key string, score SentimentScore)
+}
+
+// MemorySentimentCache in-process SentimentCache bounded by entry count and age (default)
+//
+// When full, the oldest scores are evicted first.
+type MemorySentimentCache struct {
+	maxEntries int
+	ttl        time.Duration
+
+	mu     sync.Mutex
+	scores map[string]cachedSentiment
+	order  []string // Keys by insertion, oldest first
+}
+
+// cachedSentiment cached score with its insertion time
+type cachedSentiment struct {
+	score    SentimentScore
+	storedAt time.Time
+}
+
+// NewMemorySentimentCache creates cache holding at most maxEntries scores for ttl
+// (maxEntries <= 0 = DefaultSentimentCacheEntries, ttl <= 0 = no expiry)
+func NewMemorySentimentCache(maxEntries int, ttl time.Duration) *MemorySentimentCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultSentimentCacheEntries
+	}
+	return &MemorySentimentCache{maxEntries: maxEntries, ttl: ttl, scores: make(map[string]cachedSentiment)}
+}
+
+func (c *MemorySentimentCache) Get(key string) (SentimentScore, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.scores[key]
+	if !ok || c.expired(cached) {
+		return SentimentScore{}, false
+	}
+	return cached.score, true
+}
+
+func (c *MemorySentimentCache) Put(key string, score SentimentScore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.scores[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.scores[key] = cachedSentiment{score: score, storedAt: time.Now()}
+
+	// Drop expired scores and the oldest ones beyond capacity
+	for len(c.order) > 0 {
+		oldest := c.order[0]
+		if len(c.order) <= c.maxEntries && !c.expired(c.scores[oldest]) {
+			break
+		}
+		delete(c.scores, oldest)
+		c.order = c.order[1:]
+	}
+}
+
+// expired reports whether cached is older than the TTL (caller holds mu)
+func (c *MemorySentimentCache) expired(cached cachedSentiment) bool {
+	return c.ttl > 0 && time.Since(cached.storedAt) > c.ttl
+}
+
+// SentimentAnalyzer scores texts in batches with caching
+//
+// Identical texts are scored once: results are cached per model (by default up to
+// DefaultSentimentCacheEntries scores for DefaultSentimentCacheTTL), so re-scoring a
+// rolling news window only pays for new headlines.
+type SentimentAnalyzer struct {
+	Model       string         // Model to use, ideally a cheap one ("" = client default)
+	BatchSize   int            // Texts per call (default 20)
+	Concurrency int            // Batches in flight (default 4)
+	Cache       SentimentCache // Scores by model and text (default: MemorySentimentCache)
+
+	client AIClient
+}
+
+// NewSentimentAnalyzer creates analyzer calling client
+//
+// Usage example:
+//   analyzer := mcp.NewSentimentAnalyzer(client)
+//   analyzer.Model = "qwen-turbo"
+//   scores, err := analyzer.AnalyzeSentiment(ctx, headlines)
+func NewSentimentAnalyzer(client AIClient) *SentimentAnalyzer {
+	return &SentimentAnalyzer{
+		BatchSize:   20,
+		Concurrency: 4,
+		Cache:       NewMemorySentimentCache(DefaultSentimentCacheEntries, DefaultSentimentCacheTTL),
+		client:      client,
+	}
+}
+
+// AnalyzeSentiment returns one score per text, in input order
+//
+// Failed batches are reported together (errors.Join); scores of successful batches
+// are cached even when another batch fails.
+func (a *SentimentAnalyzer) AnalyzeSentiment(ctx context.Context, texts []string) ([]SentimentScore, error) {
+	scores := make([]SentimentScore, len(texts))
+
+	// Cache hits are answered directly, the rest is deduplicated
+	results := make(map[string]SentimentScore)
+	var pending []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		key := a.cacheKey(text)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if score, ok := a.Cache.Get(key); ok {
+			results[key] = score
+		} else {
+			pending = append(pending, strings.TrimSpace(text))
+		}
+	}
+
+	batchSize := max(a.BatchSize, 1)
+	var batches [][]string
+	for start := 0; start < len(pending); start += batchSize {
+		batches = append(batches, pending[start:min(start+batchSize, len(pending))])
+	}
+
+	var mu sync.Mutex
+	errs := make([]error, len(batches))
+	slots := make(chan struct{}, max(a.Concurrency, 1))
+	var wg sync.WaitGroup
+dispatch:
+	for i, batch := range batches {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			break dispatch
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			batchScores, err := a.scoreBatch(ctx, batch)
+			errs[i] = err
+			mu.Lock()
+			defer mu.Unlock()
+			for key, score := range batchScores {
+				results[key] = score
+			}
+		}()
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	for i, text := range texts {
+		score, ok := results[a.cacheKey(text)]
+		if !ok && err == nil {
+			err = fmt.Errorf("no sentiment score for text %d", i)
+		}
+		scores[i] = score
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// scoreBatch scores texts with one call, caching and returning the results by cache key
+func (a *SentimentAnalyzer) scoreBatch(ctx context.Context, texts []string) (map[string]SentimentScore, error) {
+	var prompt strings.Builder
+	for i, text := range texts {
+		fmt.Fprintf(&prompt, "%d. %s\n", i, strings.Join(strings.Fields(text), " "))
+	}
+	builder := NewRequestBuilder().
+		WithSystemPrompt(sentimentPrompt).
+		WithUserPrompt(prompt.String()).
+		WithTemperature(0).
+		WithJSONResponse(sentimentSchema)
+	if a.Model != "" {
+		builder.WithModel(a.Model)
+	}
+	req, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := callRequest(ctx, a.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("sentiment call failed: %w", err)
+	}
+	var answer struct {
+		Results []struct {
+			Index int `json:"index"`
+			SentimentScore
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(reply)), &answer); err != nil {
+		return nil, fmt.Errorf("invalid sentiment answer: %w", err)
+	}
+
+	scores := make(map[string]SentimentScore, len(answer.Results))
+	for _, result := range answer.Results {
+		if result.Index < 0 || result.Index >= len(texts) {
+			continue
+		}
+		score := result.SentimentScore
+		score.Score = max(-1, min(1, score.Score))
+		score.Polarity = strings.ToLower(score.Polarity)
+		key := a.cacheKey(texts[result.Index])
+		scores[key] = score
+		a.Cache.Put(key, score)
+	}
+	return scores, nil
+}
+
+// cacheKey key of text scored by the analyzer's model
+func (a *SentimentAnalyzer) cacheKey(text string) string {
+	return a.Model + "\x00" + strings.TrimSpace(text)
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sentimentStub AIClient scoring every numbered text by keyword
+type sentimentStub struct {
+	mu    sync.Mutex
+	calls int
+	texts int
+}
+
+func (c *sentimentStub) SetAPIKey(apiKey string, customURL string, customModel string) {}
+func (c *sentimentStub) SetTimeout(timeout time.Duration)                              {}
+func (c *sentimentStub) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	return "", nil
+}
+
+func (c *sentimentStub) CallWithRequest(req *Request) (string, error) {
+	lines := strings.Split(strings.TrimSpace(req.Messages[len(req.Messages)-1].Content), "\n")
+	c.mu.Lock()
+	c.calls++
+	c.texts += len(lines)
+	c.mu.Unlock()
+
+	var results []map[string]any
+	for i, line := range lines {
+		score, polarity := 0.0, PolarityNeutral
+		if strings.Contains(line, "surge") {
+			score, polarity = 0.8, PolarityPositive
+		} else if strings.Contains(line, "hack") {
+			score, polarity = -1.5, PolarityNegative // Out of range on purpose
+		}
+		results = append(results, map[string]any{"index": i, "score": score, "polarity": polarity, "entities": []string{"BTC"}})
+	}
+	reply, _ := json.Marshal(map[string]any{"results": results})
+	return string(reply), nil
+}
+
+func TestSentimentAnalyzer_BatchesAndCaches(t *testing.T) {
+	stub := &sentimentStub{}
+	analyzer := NewSentimentAnalyzer(stub)
+	analyzer.BatchSize = 2
+
+	texts := []string{"BTC surge on ETF news", "Exchange hack drains wallets", "Fed holds rates", "BTC surge on ETF news"}
+	scores, err := analyzer.AnalyzeSentiment(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scores) != 4 || scores[0].Polarity != PolarityPositive || scores[1].Score != -1 || scores[2].Polarity != PolarityNeutral {
+		t.Errorf("unexpected scores %+v", scores)
+	}
+	if scores[3].Score != scores[0].Score {
+		t.Error("duplicate text should get the same score")
+	}
+	if stub.calls != 2 || stub.texts != 3 {
+		t.Errorf("expected 3 unique texts in 2 batches, got %d texts in %d calls", stub.texts, stub.calls)
+	}
+
+	if _, err := analyzer.AnalyzeSentiment(context.Background(), texts[:2]); err != nil || stub.calls != 2 {
+		t.Errorf("expected cache hit without calls, got %d calls (err %v)", stub.calls, err)
+	}
+}
+
+func TestSentimentAnalyzer_ReportsMissingScores(t *testing.T) {
+	analyzer := NewSentimentAnalyzer(&stubClient{answer: `{"results":[]}`})
+	_, err := analyzer.AnalyzeSentiment(context.Background(), []string{"a", "b"})
+	if err == nil || !strings.Contains(err.Error(), "text 0") {
+		t.Errorf("expected missing score error, got %v", err)
+	}
+}
+
+func TestMemorySentimentCache_Bounds(t *testing.T) {
+	cache := NewMemorySentimentCache(2, 0)
+	cache.Put("a", SentimentScore{Score: 0.1})
+	cache.Put("b", SentimentScore{Score: 0.2})
+	cache.Put("c", SentimentScore{Score: 0.3})
+	if _, ok := cache.Get("a"); ok {
+		t.Error("oldest score should be evicted beyond capacity")
+	}
+	if score, ok := cache.Get("c"); !ok || score.Score != 0.3 {
+		t.Errorf("newest score should be kept, got %+v (%v)", score, ok)
+	}
+
+	cache = NewMemorySentimentCache(10, 10*time.Millisecond)
+	cache.Put("a", SentimentScore{Score: 0.1})
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expired score should not be returned")
+	}
+}
+
+// blockingSentimentStub sentimentStub whose calls block until release is closed
+type blockingSentimentStub struct {
+	sentimentStub
+	release chan struct{}
+}
+
+func (c *blockingSentimentStub) CallWithRequest(req *Request) (string, error) {
+	<-c.release
+	return c.sentimentStub.CallWithRequest(req)
+}
+
+func TestSentimentAnalyzer_CancelStopsDispatch(t *testing.T) {
+	stub := &blockingSentimentStub{release: make(chan struct{})}
+	analyzer := NewSentimentAnalyzer(stub)
+	analyzer.BatchSize = 1
+	analyzer.Concurrency = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		close(stub.release)
+	}()
+	if _, err := analyzer.AnalyzeSentiment(ctx, []string{"BTC surge", "ETH surge", "SOL surge"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if stub.calls != 1 {
+		t.Errorf("no batch should start after cancellation, got %d calls", stub.calls)
+	}
+}
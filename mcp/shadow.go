@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// shadowHistory number of most recent shadow pairs kept in memory
+const shadowHistory = 500
+
+// shadowTimeout max duration of one shadow call, so a hung secondary can't hold up Close
+const shadowTimeout = 2 * time.Minute
+
+// ShadowResult outcome of one side of a shadowed call
+type ShadowResult struct {
+	Text    string
+	Err     error
+	Latency time.Duration
+	Usage   TokenUsage // Empty when the client doesn't report usage
+	Cost    float64    // USD (0 when usage or model price is unknown)
+}
+
+// ShadowPair production and shadow outputs of the same prompt
+type ShadowPair struct {
+	Time      time.Time
+	RequestID string
+	Primary   ShadowResult
+	Shadow    ShadowResult
+	Diff      *ResponseDiff // Difference of the answers (nil when the shadow call failed)
+}
+
+// shadowState shadow-traffic configuration and recorded pairs
+type shadowState struct {
+	client     AIClient
+	sampleRate float64
+	onPair     func(ShadowPair)
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	pairs  []ShadowPair
+	ctx    context.Context // Parent of shadow calls, cancelled by abort
+	cancel context.CancelFunc
+}
+
+// WithShadow mirrors a fraction of calls to a secondary model for offline comparison
+//
+// sampleRate (0-1) of successful production calls are replayed against secondary in
+// the background, once response processors and output guardrails have passed. The
+// shadow call never affects the returned result, its errors are only recorded. Paired
+// outputs, latency and cost are kept in memory (ShadowPairs) and passed to the
+// WithShadowRecorder callback. Close waits for pending shadow calls, each bounded to
+// 2 minutes, and cancels them when its context expires.
+//
+// Usage example:
+//   candidate := mcp.NewOpenAIClientWithOptions(mcp.WithAPIKey(key), mcp.WithModel("gpt-5.2"))
+//   client := mcp.NewDeepSeekClientWithOptions(
+//       mcp.WithShadow(candidate, 0.1),
+//       mcp.WithShadowRecorder(func(pair mcp.ShadowPair) { store.Save(pair) }),
+//   )
+func WithShadow(secondary AIClient, sampleRate float64) ClientOption {
+	return func(c *Config) {
+		c.Shadow = secondary
+		c.ShadowSampleRate = sampleRate
+	}
+}
+
+// WithShadowRecorder sets callback receiving every recorded shadow pair
+func WithShadowRecorder(fn func(ShadowPair)) ClientOption {
+	return func(c *Config) {
+		c.OnShadowPair = fn
+	}
+}
+
+// ShadowPairs returns the most recent shadow pairs, oldest first
+func (client *Client) ShadowPairs() []ShadowPair {
+	if client.shadow == nil {
+		return nil
+	}
+	client.shadow.mu.Lock()
+	defer client.shadow.mu.Unlock()
+	return append([]ShadowPair(nil), client.shadow.pairs...)
+}
+
+// newShadowState creates shadow state from config (nil when shadowing is disabled)
+func newShadowState(config *Config) *shadowState {
+	if config.Shadow == nil || config.ShadowSampleRate <= 0 {
+		return nil
+	}
+	s := &shadowState{
+		client:     config.Shadow,
+		sampleRate: config.ShadowSampleRate,
+		onPair:     config.OnShadowPair,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// sampled reports whether the current call should be mirrored
+func (s *shadowState) sampled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < s.sampleRate
+}
+
+// callContext returns context bounding one shadow call
+func (s *shadowState) callContext() (context.Context, context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return context.WithTimeout(s.ctx, shadowTimeout)
+}
+
+// abort cancels pending shadow calls (later calls get a fresh context, clones may share the state)
+func (s *shadowState) abort() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel()
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+// mirror runs call against the shadow client in the background and records the pair
+func (client *Client) mirror(requestID string, primary ShadowResult, call func(context.Context, AIClient) (*Response, error)) {
+	if client.shadow == nil || primary.Err != nil || !client.shadow.sampled() {
+		return
+	}
+	if err := client.lifecycle.begin(); err != nil {
+		return
+	}
+
+	ctx, cancel := client.shadow.callContext()
+	go func() {
+		defer client.lifecycle.end()
+		defer cancel()
+
+		start := time.Now()
+		response, err := call(ctx, client.shadow.client)
+		shadow := ShadowResult{Err: err, Latency: time.Since(start)}
+		if response != nil {
+			shadow.Text = response.Text
+			shadow.Usage = response.Usage
+			shadow.Cost = EstimateCost(response.Usage)
+		}
+		pair := ShadowPair{Time: start, RequestID: requestID, Primary: primary, Shadow: shadow}
+		if err == nil && response != nil {
+			diff := CompareResponses(Response{Text: primary.Text}, *response)
+			pair.Diff = &diff
+		}
+
+		client.shadow.mu.Lock()
+		client.shadow.pairs = append(client.shadow.pairs, pair)
+		if len(client.shadow.pairs) > shadowHistory {
+			client.shadow.pairs = client.shadow.pairs[len(client.shadow.pairs)-shadowHistory:]
+		}
+		client.shadow.mu.Unlock()
+
+		if client.shadow.onPair != nil {
+			client.shadow.onPair(pair)
+		}
+	}()
+}
+
+// shadowCall calls req on a shadow client, keeping usage when the client reports it
+func shadowCall(req *Request) func(context.Context, AIClient) (*Response, error) {
+	shadowReq := *req
+	shadowReq.Model = "" // Use the shadow client's model
+	return func(ctx context.Context, secondary AIClient) (*Response, error) {
+		if c, ok := secondary.(interface {
+			Call(ctx context.Context, req *Request) (*Response, error)
+		}); ok {
+			return c.Call(ctx, &shadowReq)
+		}
+		text, err := callRequest(ctx, secondary, &shadowReq)
+		return &Response{Text: text}, err
+	}
+}
+
+// shadowMessages calls system/user prompts on a shadow client
+func shadowMessages(systemPrompt, userPrompt string) func(context.Context, AIClient) (*Response, error) {
+	return func(ctx context.Context, secondary AIClient) (*Response, error) {
+		if c, ok := secondary.(interface {
+			CallMessages(ctx context.Context, systemPrompt, userPrompt string) (*Response, error)
+		}); ok {
+			return c.CallMessages(ctx, systemPrompt, userPrompt)
+		}
+		text, err := secondary.CallWithMessages(systemPrompt, userPrompt)
+		return &Response{Text: text}, err
+	}
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShadow_MirrorsAndRecordsPairs(t *testing.T) {
+	var bodies, shadowBodies []map[string]any
+	RegisterModelPrice("shadow-model", ModelPrice{InputPerMillion: 1e6, OutputPerMillion: 1e6})
+
+	secondary := NewClient(
+		WithProvider(ProviderCustom),
+		WithModel("shadow-model"),
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"sell"}}],"usage":{"prompt_tokens":3,"completion_tokens":1}}`, &shadowBodies)),
+		WithAPIKey("sk-shadow"),
+		WithLogger(NewNoopLogger()),
+	)
+
+	var recorded []ShadowPair
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"buy"}}],"usage":{"prompt_tokens":3,"completion_tokens":1}}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithShadow(secondary, 1),
+		WithShadowRecorder(func(pair ShadowPair) { recorded = append(recorded, pair) }),
+	).(*Client)
+
+	result, err := client.CallWithRequest(NewRequestBuilder().WithUserPrompt("decide").MustBuild())
+	if err != nil || result != "buy" {
+		t.Fatalf("primary result must be returned, got %q, %v", result, err)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("close should drain shadow call: %v", err)
+	}
+
+	pairs := client.ShadowPairs()
+	if len(pairs) != 1 || len(recorded) != 1 {
+		t.Fatalf("expected one recorded pair, got %d / %d", len(pairs), len(recorded))
+	}
+	pair := pairs[0]
+	if pair.Primary.Text != "buy" || pair.Shadow.Text != "sell" {
+		t.Errorf("unexpected paired outputs %+v", pair)
+	}
+	if pair.Shadow.Cost != 4 || pair.Shadow.Usage.TotalTokens != 4 {
+		t.Errorf("expected shadow cost from usage, got %+v", pair.Shadow)
+	}
+	if pair.RequestID == "" {
+		t.Error("pair should carry primary request ID")
+	}
+	if pair.Diff == nil || pair.Diff.TextEqual || pair.Diff.Similarity != 0 {
+		t.Errorf("expected diff of differing answers, got %+v", pair.Diff)
+	}
+	if shadowBodies[0]["model"] != "shadow-model" {
+		t.Errorf("shadow should use its own model, got %v", shadowBodies[0]["model"])
+	}
+}
+
+func TestShadow_FailureAndSampling(t *testing.T) {
+	var bodies []map[string]any
+	failing := &stubClient{err: errors.New("shadow down")}
+
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"buy"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithShadow(failing, 1),
+	).(*Client)
+
+	result, err := client.CallWithMessages("system", "decide")
+	if err != nil || result != "buy" {
+		t.Fatalf("shadow failure must not affect result, got %q, %v", result, err)
+	}
+	client.Close(context.Background())
+	if pairs := client.ShadowPairs(); len(pairs) != 1 || pairs[0].Shadow.Err == nil || pairs[0].Diff != nil {
+		t.Errorf("shadow error should be recorded, got %+v", pairs)
+	}
+
+	unsampled := &stubClient{answer: "sell"}
+	client = NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"buy"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithShadow(unsampled, 0),
+	).(*Client)
+	client.CallWithMessages("system", "decide")
+	client.Close(context.Background())
+	if len(unsampled.prompts) != 0 || client.ShadowPairs() != nil {
+		t.Error("sample rate 0 should disable shadowing")
+	}
+}
+
+func TestShadow_BlockedCallsNotMirrored(t *testing.T) {
+	var bodies, shadowBodies []map[string]any
+	secondary := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"hold"}}]}`, &shadowBodies)),
+		WithAPIKey("sk-shadow"),
+		WithLogger(NewNoopLogger()),
+	)
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"Use 100x leverage"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithShadow(secondary, 1),
+		WithGuardrails(GuardrailRule{
+			Guardrail: GuardrailFunc("max_leverage", func(ctx context.Context, stage GuardrailStage, text string) error {
+				if strings.Contains(text, "100x") {
+					return errors.New("leverage above policy")
+				}
+				return nil
+			}),
+			Stage:  GuardOutput,
+			Action: GuardrailBlock,
+		}),
+	).(*Client)
+
+	if _, err := client.CallWithMessages("system", "decide"); !errors.Is(err, ErrGuardrailBlocked) {
+		t.Fatalf("expected output to be blocked, got %v", err)
+	}
+	client.Close(context.Background())
+	if len(shadowBodies) != 0 || len(client.ShadowPairs()) != 0 {
+		t.Errorf("blocked call must not be mirrored, got %d shadow calls", len(shadowBodies))
+	}
+}
+
+func TestShadow_CloseCancelsHungShadowCall(t *testing.T) {
+	hung := NewMockHTTPClient()
+	hung.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	secondary := NewClient(WithHTTPClient(hung.ToHTTPClient()), WithAPIKey("sk-shadow"), WithLogger(NewNoopLogger()), WithMaxRetries(1))
+
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"buy"}}]}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithShadow(secondary, 1),
+	).(*Client)
+	if _, err := client.CallWithRequest(NewRequestBuilder().WithUserPrompt("decide").MustBuild()); err != nil {
+		t.Fatalf("primary call failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Close to report the undrained shadow call, got %v", err)
+	}
+	waitFor(t, func() bool { return len(client.ShadowPairs()) == 1 })
+	if pair := client.ShadowPairs()[0]; pair.Shadow.Err == nil {
+		t.Errorf("expected cancelled shadow call to be recorded as failed, got %+v", pair.Shadow)
+	}
+}
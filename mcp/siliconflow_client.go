@@ -0,0 +1,116 @@
+package mcp
+
+const (
+	ProviderSiliconFlow          = "siliconflow"
+	DefaultSiliconFlowBaseURL    = "https://api.siliconflow.cn/v1" // China endpoint (use api.siliconflow.com for international)
+	DefaultSiliconFlowModel      = "deepseek-ai/DeepSeek-V3"
+	DefaultSiliconFlowEmbedModel = "BAAI/bge-m3"
+)
+
+// siliconFlowModels SiliconFlow names of models commonly configured by their vendor names
+//
+// SiliconFlow names models "<org>/<Model>" with the vendor's capitalization; a "Pro/"
+// prefix selects the paid-only deployment of the same model.
+var siliconFlowModels = map[string]string{
+	"deepseek-chat":     "deepseek-ai/DeepSeek-V3",
+	"deepseek-reasoner": "deepseek-ai/DeepSeek-R1",
+	"qwen3-235b-a22b":   "Qwen/Qwen3-235B-A22B",
+	"qwen3-32b":         "Qwen/Qwen3-32B",
+	"qwen3-8b":          "Qwen/Qwen3-8B",
+	"qwen2.5-72b":       "Qwen/Qwen2.5-72B-Instruct",
+	"glm-4-9b":          "THUDM/GLM-4-9B-0414",
+	"kimi-k2":           "moonshotai/Kimi-K2-Instruct",
+}
+
+// siliconFlowPrices list prices converted from CNY at ~7.2 (override with RegisterModelPrice)
+var siliconFlowPrices = map[string]ModelPrice{
+	"deepseek-ai/DeepSeek-V3":     {InputPerMillion: 0.28, OutputPerMillion: 1.11},
+	"Pro/deepseek-ai/DeepSeek-V3": {InputPerMillion: 0.28, OutputPerMillion: 1.11},
+	"deepseek-ai/DeepSeek-R1":     {InputPerMillion: 0.56, OutputPerMillion: 2.22},
+	"Pro/deepseek-ai/DeepSeek-R1": {InputPerMillion: 0.56, OutputPerMillion: 2.22},
+	"Qwen/Qwen3-235B-A22B":        {InputPerMillion: 0.35, OutputPerMillion: 1.39},
+	"Qwen/Qwen3-32B":              {InputPerMillion: 0.14, OutputPerMillion: 0.56},
+	"Qwen/Qwen3-8B":               {InputPerMillion: 0, OutputPerMillion: 0},
+	"Qwen/Qwen2.5-72B-Instruct":   {InputPerMillion: 0.57, OutputPerMillion: 0.57},
+	"THUDM/GLM-4-9B-0414":         {InputPerMillion: 0, OutputPerMillion: 0},
+	"moonshotai/Kimi-K2-Instruct": {InputPerMillion: 0.56, OutputPerMillion: 2.22},
+}
+
+type SiliconFlowClient struct {
+	*Client
+}
+
+// NewSiliconFlowClient creates SiliconFlow client (backward compatible)
+func NewSiliconFlowClient() AIClient {
+	return NewSiliconFlowClientWithOptions()
+}
+
+// NewSiliconFlowClientWithOptions creates SiliconFlow client (supports options pattern)
+//
+// SiliconFlow is an OpenAI-compatible aggregator of open-weight models. Vendor model
+// names such as "deepseek-chat" or "qwen3-235b-a22b" are translated to SiliconFlow's
+// "<org>/<Model>" names, and the cost tracker knows the prices of common models.
+//
+// Usage example:
+//   client := mcp.NewSiliconFlowClientWithOptions(mcp.WithAPIKey(key), mcp.WithModel("deepseek-reasoner"))
+func NewSiliconFlowClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create SiliconFlow preset options
+	siliconFlowOpts := []ClientOption{
+		WithProvider(ProviderSiliconFlow),
+		WithModel(DefaultSiliconFlowModel),
+		WithBaseURL(DefaultSiliconFlowBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(siliconFlowOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+	baseClient.Model = siliconFlowModel(baseClient.Model)
+
+	// 4. Create SiliconFlow client
+	siliconFlowClient := &SiliconFlowClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to SiliconFlowClient (implement dynamic dispatch)
+	baseClient.hooks = siliconFlowClient
+
+	return siliconFlowClient
+}
+
+// siliconFlowModel returns the SiliconFlow name of model
+func siliconFlowModel(model string) string {
+	if name, ok := siliconFlowModels[model]; ok {
+		return name
+	}
+	return model
+}
+
+func (c *SiliconFlowClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] SiliconFlow API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] SiliconFlow using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] SiliconFlow using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = siliconFlowModel(customModel)
+		c.logger.Infof("🔧 [MCP] SiliconFlow using custom Model: %s", c.Model)
+	} else {
+		c.logger.Infof("🔧 [MCP] SiliconFlow using default Model: %s", c.Model)
+	}
+}
+
+// marshalRequestBody translates vendor model names of per-request overrides
+func (c *SiliconFlowClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	if model, ok := requestBody["model"].(string); ok {
+		requestBody["model"] = siliconFlowModel(model)
+	}
+	return c.Client.marshalRequestBody(requestBody)
+}
@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"math"
+	"testing"
+)
+
+// ============================================================
+// Test SiliconFlowClient
+// ============================================================
+
+func TestSiliconFlowClient_ModelNames(t *testing.T) {
+	var bodies []map[string]any
+	client := NewSiliconFlowClientWithOptions(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"Hold BTC."}}],"usage":{"prompt_tokens":1000000,"completion_tokens":1000000}}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithModel("deepseek-reasoner"),
+		WithLogger(NewNoopLogger()),
+	).(*SiliconFlowClient)
+
+	if client.Model != "deepseek-ai/DeepSeek-R1" {
+		t.Errorf("expected SiliconFlow model name, got %s", client.Model)
+	}
+	response, err := client.Call(t.Context(), NewRequestBuilder().WithUserPrompt("BTC?").WithModel("qwen3-8b").MustBuild())
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if bodies[0]["model"] != "Qwen/Qwen3-8B" {
+		t.Errorf("per-request model not translated: %v", bodies[0]["model"])
+	}
+	if cost := EstimateCost(response.Usage); math.Abs(cost-2.78) > 1e-9 {
+		t.Errorf("expected built-in DeepSeek-R1 price, got cost %v", cost)
+	}
+}
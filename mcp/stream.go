@@ -0,0 +1,996 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StreamEventType kind of streaming event
+type StreamEventType int
+
+const (
+	// StreamTextDelta new answer text
+	StreamTextDelta StreamEventType = iota
+	// StreamReasoningDelta new reasoning/thinking text
+	StreamReasoningDelta
+	// StreamToolCallDelta new argument fragment of a tool call
+	StreamToolCallDelta
+	// StreamToolCallComplete tool call fully received, arguments are final
+	StreamToolCallComplete
+	// StreamDone stream finished, Response holds the assembled result
+	StreamDone
+	// StreamError stream failed, Err holds the error (channel API only)
+	StreamError
+	// StreamRestarted stream is sent again from scratch, discard the events received so far
+	StreamRestarted
+)
+
+func (t StreamEventType) String() string {
+	switch t {
+	case StreamTextDelta:
+		return "text_delta"
+	case StreamReasoningDelta:
+		return "reasoning_delta"
+	case StreamToolCallDelta:
+		return "tool_call_delta"
+	case StreamToolCallComplete:
+		return "tool_call_complete"
+	case StreamDone:
+		return "done"
+	case StreamError:
+		return "error"
+	case StreamRestarted:
+		return "restarted"
+	}
+	return fmt.Sprintf("stream_event(%d)", int(t))
+}
+
+// ErrEmptyStream is returned when the provider closes a stream without sending any data
+var ErrEmptyStream = errors.New("AI returned empty stream")
+
+// StreamEvent one typed event of a streamed response
+type StreamEvent struct {
+	Type     StreamEventType
+	Text     string    // Text or reasoning delta; argument fragment for StreamToolCallDelta
+	ToolCall *ToolCall // Tool call assembled so far (tool call events)
+	Index    int       // Position of the tool call in the response (tool call events)
+	Partial  any       // Best-effort decode of the JSON received so far (structured output, tool arguments)
+	Response *Response // Final response (StreamDone)
+	Err      error     // Failure (StreamError)
+}
+
+// CallStream calls AI API with streaming, delivering typed events as they arrive
+//
+// Text, reasoning and tool-call argument deltas are assembled incrementally: a
+// StreamToolCallComplete event is sent as soon as a tool call is fully received, so
+// agents can start executing tools before the stream finishes. When JSON output is
+// requested, text events carry the partial object decoded so far in Partial.
+// Returning an error from onEvent aborts the stream with that error.
+//
+// The assembled response goes through output guardrails before StreamDone is sent;
+// deltas already delivered cannot be withdrawn.
+//
+// Usage example:
+//   response, err := client.CallStream(ctx, request, func(event mcp.StreamEvent) error {
+//       switch event.Type {
+//       case mcp.StreamTextDelta:
+//           fmt.Print(event.Text)
+//       case mcp.StreamToolCallComplete:
+//           go executeTool(event.ToolCall)
+//       }
+//       return nil
+//   })
+func (client *Client) CallStream(ctx context.Context, req *Request, onEvent func(StreamEvent) error) (response *Response, err error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+	if err := client.lifecycle.begin(); err != nil {
+		return nil, err
+	}
+	defer client.lifecycle.end()
+
+	if req.Model == "" {
+		req.Model = client.Model
+	}
+
+	ctx, requestID := client.ensureRequestID(ctx)
+	ctx, finishWebhook := client.startWebhookCall(ctx)
+	defer func() { finishWebhook(err) }()
+	start := time.Now()
+
+	original := req // Failover clients apply their own redaction and guardrails
+
+	// Sensitive data is replaced by placeholders, then input guardrails may block or redact
+	vault := client.newPIIVault()
+	req, violations, err := client.guardRequest(ctx, vault.redactRequest(req))
+	if err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+
+	// Deltas are timed for stream metrics; a stream without a first token in time may fail over
+	meter := newStreamMeter(start)
+	streamCtx, stopWatch := client.watchFirstToken(ctx, meter)
+	response, err = client.streamResumable(streamCtx, req, vault, meter.wrap(onEvent))
+	abandoned := errors.Is(context.Cause(streamCtx), ErrFirstTokenSLO)
+	stopWatch()
+	if abandoned && ctx.Err() == nil {
+		return client.failoverStream(ctx, original, onEvent)
+	}
+	if err != nil {
+		return nil, withRequestID(vault.restorePartial(client.salvageInterrupted(err)), requestID)
+	}
+	client.checkStreamSLO(response, meter)
+	if response, err = client.finishResponse(ctx, response, start, vault, violations, shadowCall(req)); err != nil {
+		return nil, err
+	}
+	if err := onEvent(StreamEvent{Type: StreamDone, Response: response}); err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+	return response, nil
+}
+
+// CallStreamChan streams response events over a channel
+//
+// The channel is closed after the final StreamDone or StreamError event. A consumer
+// that stops reading must cancel ctx: the request is then aborted and the channel
+// closed, so no goroutine is left blocked.
+//
+// Usage example:
+//   ctx, cancel := context.WithCancel(ctx)
+//   defer cancel()
+//   events, err := client.CallStreamChan(ctx, request)
+//   for event := range events {
+//       if event.Type == mcp.StreamTextDelta {
+//           conn.WriteMessage(websocket.TextMessage, []byte(event.Text))
+//       }
+//   }
+func (client *Client) CallStreamChan(ctx context.Context, req *Request) (<-chan StreamEvent, error) {
+	if err := client.checkAPIKey(); err != nil {
+		return nil, err
+	}
+
+	events := make(chan StreamEvent)
+	go func() {
+		defer close(events)
+		send := func(event StreamEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if _, err := client.CallStream(ctx, req, send); err != nil && ctx.Err() == nil {
+			send(StreamEvent{Type: StreamError, Err: err})
+		}
+	}()
+	return events, nil
+}
+
+// CallStreamTo streams answer text to w as it arrives and returns the final response
+//
+// w is flushed after every write when it implements http.Flusher (SSE relays); a
+// failed write (e.g. client disconnected) aborts the request. Written text can't be
+// taken back, so failed streams are resumed rather than restarted (see WithStreamRetry).
+//
+// Usage example:
+//   response, err := client.CallStreamTo(r.Context(), request, w) // inside an http.Handler
+//   response, err := client.CallStreamTo(ctx, request, os.Stdout)  // terminal
+func (client *Client) CallStreamTo(ctx context.Context, req *Request, w io.Writer) (*Response, error) {
+	flusher, _ := w.(http.Flusher)
+	return client.CallStream(withAppendOnlyStream(ctx), req, func(event StreamEvent) error {
+		if event.Type != StreamTextDelta {
+			return nil
+		}
+		if _, err := io.WriteString(w, event.Text); err != nil {
+			return fmt.Errorf("failed to write stream: %w", err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+}
+
+// stream performs one streaming request and assembles the response
+func (client *Client) stream(ctx context.Context, req *Request, vault *piiVault, onEvent func(StreamEvent) error) (response *Response, err error) {
+	if err := client.checkBudgets(ctx); err != nil {
+		return nil, err
+	}
+	client.logger.Infof("📡 [%s] Streaming request to AI Server: BaseURL: %s", client.String(), client.BaseURL)
+
+	requestBody := client.buildRequestBodyFromRequest(req)
+	capabilities, err := client.gateCapabilities(ctx, requestBody)
+	if err != nil {
+		return nil, err
+	}
+	if capabilities != nil && !capabilities.Streaming {
+		if req.ResponseFormat != nil {
+			ctx = context.WithValue(ctx, responseFormatKey{}, req.ResponseFormat)
+		}
+		return client.emulateStream(ctx, requestBody, vault, onEvent)
+	}
+	requestBody["stream"] = true
+	if client.Provider == ProviderOpenAI {
+		// Usage is only reported in streams when asked for
+		requestBody["stream_options"] = map[string]any{"include_usage": true}
+	}
+	format := req.ResponseFormat
+	if format == nil {
+		format = client.config.ResponseFormat
+	}
+	client.decorateBody(ctx, requestBody)
+
+	var (
+		url        string
+		statusCode int
+	)
+	attempt := client.webhookAttempt(ctx, bodyModel(requestBody, client.Model))
+	tracker := newLatencyTracker()
+	defer func() {
+		var result, reasoning string
+		var usage TokenUsage
+		if response != nil {
+			result, reasoning, usage = response.Text, response.Reasoning, response.Usage
+		}
+		client.audit(ctx, url, requestBody, usage, statusCode, result, reasoning, time.Since(tracker.start), err)
+		attempt(usage, err)
+		client.fireResult(ctx, bodyModel(requestBody, client.Model), response, time.Since(tracker.start), err)
+		client.spendBudgets(ctx, bodyModel(requestBody, client.Model), usage, err)
+	}()
+	client.fireRequest(ctx, requestBody)
+
+	jsonData, err := client.hooks.marshalRequestBody(requestBody)
+	if err != nil {
+		return nil, err
+	}
+	url = withExtraQuery(client.hooks.buildUrl(), client.config.ExtraQuery)
+
+	httpReq, err := client.hooks.buildRequest(url, jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if err := client.config.RateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter: %w", err)
+	}
+	client.setRequestIDHeaders(ctx, httpReq)
+	client.setTenantHeader(ctx, httpReq)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	httpReq = tracker.attach(httpReq.WithContext(ctx))
+	resp, err := client.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	resp.Body = withIdleTimeout(ctx, resp.Body, client.config.IdleTimeout, cancel)
+	defer resp.Body.Close()
+	statusCode = resp.StatusCode
+
+	body := limitBody(resp.Body, client.config.MaxResponseBytes)
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(body)
+		return nil, client.newAPIError(resp.StatusCode, data)
+	}
+
+	assembler := newStreamAssembler(format != nil, !client.config.RetainReasoning, vault, onEvent)
+	done, err := readStream(body, assembler.handle)
+	if errors.Is(err, ErrStreamInterrupted) {
+		return nil, &StreamInterruptedError{Partial: assembler.response(client), Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	client.observeLatency(tracker.finish())
+	if !assembler.received {
+		return nil, ErrEmptyStream
+	}
+	if !done && assembler.stop == "" && (client.config.StreamResumes > 0 || client.config.StreamRetry != StreamRetryOff) {
+		// Closed without a completion marker, e.g. by a proxy dropping an idle connection
+		return nil, &StreamInterruptedError{Partial: assembler.response(client), Err: ErrStreamInterrupted}
+	}
+	if err := assembler.finish(); err != nil {
+		return nil, err
+	}
+
+	response = assembler.response(client)
+	response.StatusCode = resp.StatusCode
+	response.Header = redactHeaders(resp.Header)
+
+	if TokenUsageCallback != nil && response.Usage.TotalTokens > 0 {
+		TokenUsageCallback(response.Usage)
+	}
+	if format != nil {
+		if err := client.migrateJSONResponse(response); err != nil {
+			return nil, err
+		}
+		if err := validateJSONResponse(response.Text, format); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// readStream reads SSE "data:" lines (or NDJSON lines) and passes each payload to handle
+//
+// Keepalive comments (": ping") and "event:" lines are skipped; done reports whether
+// the [DONE] marker was received. Read failures wrap ErrStreamInterrupted.
+func readStream(r io.Reader, handle func(data []byte) error) (done bool, err error) {
+	reader := bufio.NewReader(r)
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+
+		var data string
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		case strings.HasPrefix(line, "{"):
+			data = line // NDJSON (Ollama native)
+		}
+		if data == "[DONE]" {
+			return true, nil
+		}
+		if data != "" {
+			if err := handle([]byte(data)); err != nil {
+				return false, err
+			}
+		}
+
+		if readErr == io.EOF {
+			return false, nil
+		}
+		if readErr != nil {
+			return false, fmt.Errorf("%w: failed to read stream: %w", ErrStreamInterrupted, readErr)
+		}
+	}
+}
+
+// streamChunk one streamed payload in OpenAI-compatible, Anthropic, Ollama or TGI format
+// (Cohere events, typed with dashes, are decoded by handleCohere)
+type streamChunk struct {
+	// OpenAI-compatible
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+			Reasoning        string `json:"reasoning"`
+			ToolCalls        []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason        string `json:"finish_reason"`
+		HunyuanFinishReason string `json:"FinishReason"` // Tencent Cloud API 3.0
+	} `json:"choices"`
+
+	// Anthropic
+	Type    string `json:"type"`
+	Index   int    `json:"index"`
+	Message *struct {
+		Model string          `json:"model"`
+		Usage json.RawMessage `json:"usage"`
+	} `json:"message"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
+	Delta struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		Thinking    string `json:"thinking"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+
+	// Ollama native (sent with every chunk)
+	Done *bool `json:"done"`
+
+	// TGI generate_stream
+	Token json.RawMessage `json:"token"`
+
+	// ERNIE workshop API
+	IsEnd     *bool  `json:"is_end"`
+	ErrorCode int    `json:"error_code"`
+	ErrorMsg  string `json:"error_msg"`
+
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// streamToolCall tool call being assembled
+type streamToolCall struct {
+	call     ToolCall
+	index    int // Position in the response
+	complete bool
+	held     string // Argument fragment held back as a possible PII placeholder
+}
+
+// streamAssembler assembles streamed chunks into events and the final response
+type streamAssembler struct {
+	structured bool // JSON output requested: text events carry Partial
+	think      *thinkFilter
+	vault      *piiVault
+	onEvent    func(StreamEvent) error
+
+	received  bool
+	raw       strings.Builder // Answer text as streamed (inline <think> blocks included)
+	text      strings.Builder // Answer text without inline reasoning
+	reasoning strings.Builder // Native reasoning deltas
+	calls     []*streamToolCall
+	byIndex   map[int]*streamToolCall
+	jsonBlock map[int]bool // Anthropic blocks of the forced JSON tool (streamed as text)
+	stop      string       // Raw finish/stop reason
+	model     string
+	usage     TokenUsage
+
+	heldAnswer    string // Answer fragment held back as a possible PII placeholder
+	heldReasoning string // Reasoning fragment held back as a possible PII placeholder
+}
+
+func newStreamAssembler(structured, splitThink bool, vault *piiVault, onEvent func(StreamEvent) error) *streamAssembler {
+	a := &streamAssembler{
+		structured: structured,
+		vault:      vault,
+		onEvent:    onEvent,
+		byIndex:    make(map[int]*streamToolCall),
+		jsonBlock:  make(map[int]bool),
+	}
+	if splitThink {
+		a.think = &thinkFilter{}
+	}
+	return a
+}
+
+// handle processes one streamed payload
+func (a *streamAssembler) handle(data []byte) error {
+	var chunk streamChunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return fmt.Errorf("failed to parse stream chunk: %w, data: %s", err, string(data))
+	}
+	if chunk.Error != nil {
+		return fmt.Errorf("stream error: %s - %s", chunk.Error.Type, chunk.Error.Message)
+	}
+	if chunk.ErrorCode != 0 {
+		return fmt.Errorf("stream error: %d - %s", chunk.ErrorCode, chunk.ErrorMsg)
+	}
+	a.received = true
+	if chunk.Model != "" {
+		a.model = chunk.Model
+	}
+
+	switch {
+	case strings.Contains(chunk.Type, "-"):
+		return a.handleCohere(data)
+	case chunk.Type != "":
+		return a.handleAnthropic(&chunk, data)
+	case chunk.Done != nil:
+		return a.handleOllama(data)
+	case chunk.Token != nil:
+		return a.handleTGI(data)
+	case chunk.IsEnd != nil:
+		return a.handleErnie(data)
+	default:
+		return a.handleOpenAI(&chunk, data)
+	}
+}
+
+func (a *streamAssembler) handleOpenAI(chunk *streamChunk, data []byte) error {
+	if usage := parseUsage(data); usage.TotalTokens > 0 {
+		a.usage = usage
+	}
+	if len(chunk.Choices) == 0 {
+		return nil // Usage-only chunk
+	}
+	choice := chunk.Choices[0]
+	if reasoning := choice.Delta.ReasoningContent + choice.Delta.Reasoning; reasoning != "" {
+		if err := a.emitReasoning(reasoning); err != nil {
+			return err
+		}
+	}
+	if err := a.emitText(choice.Delta.Content); err != nil {
+		return err
+	}
+	for _, delta := range choice.Delta.ToolCalls {
+		call := a.toolCall(delta.Index, delta.ID, delta.Function.Name)
+		if delta.Type != "" {
+			call.call.Type = delta.Type
+		}
+		if err := a.emitArguments(call, delta.Function.Arguments); err != nil {
+			return err
+		}
+	}
+	if reason := choice.FinishReason + choice.HunyuanFinishReason; reason != "" {
+		a.stop = reason
+		return a.completeToolCalls()
+	}
+	return nil
+}
+
+func (a *streamAssembler) handleAnthropic(chunk *streamChunk, data []byte) error {
+	switch chunk.Type {
+	case "message_start":
+		if chunk.Message != nil {
+			a.model = chunk.Message.Model
+			a.usage = parseUsage(append(append([]byte(`{"usage":`), chunk.Message.Usage...), '}'))
+		}
+	case "content_block_start":
+		if chunk.ContentBlock.Type == "tool_use" {
+			if chunk.ContentBlock.Name == jsonResponseTool {
+				a.jsonBlock[chunk.Index] = true
+			} else {
+				a.toolCall(chunk.Index, chunk.ContentBlock.ID, chunk.ContentBlock.Name)
+			}
+		}
+	case "content_block_delta":
+		switch chunk.Delta.Type {
+		case "text_delta":
+			return a.emitText(chunk.Delta.Text)
+		case "thinking_delta":
+			return a.emitReasoning(chunk.Delta.Thinking)
+		case "input_json_delta":
+			if a.jsonBlock[chunk.Index] {
+				return a.emitText(chunk.Delta.PartialJSON)
+			}
+			if call, ok := a.byIndex[chunk.Index]; ok {
+				return a.emitArguments(call, chunk.Delta.PartialJSON)
+			}
+		}
+	case "content_block_stop":
+		if call, ok := a.byIndex[chunk.Index]; ok {
+			return a.complete(call)
+		}
+	case "message_delta":
+		if chunk.Delta.StopReason != "" {
+			a.stop = chunk.Delta.StopReason
+		}
+		// Output tokens are reported cumulatively at the end
+		if usage := parseUsage(data); usage.CompletionTokens > 0 {
+			a.usage.CompletionTokens = usage.CompletionTokens
+			a.usage.TotalTokens = a.usage.PromptTokens + usage.CompletionTokens
+		}
+	}
+	return nil
+}
+
+func (a *streamAssembler) handleCohere(data []byte) error {
+	var chunk struct {
+		Type  string `json:"type"`
+		Index int    `json:"index"`
+		Delta struct {
+			Message struct {
+				Content   json.RawMessage `json:"content"` // Array on message-start, object on deltas
+				ToolCalls struct {
+					ID       string `json:"id"`
+					Function struct {
+						Name      string `json:"name"`
+						Arguments string `json:"arguments"`
+					} `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+			FinishReason string          `json:"finish_reason"`
+			Usage        json.RawMessage `json:"usage"`
+		} `json:"delta"`
+	}
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return nil
+	}
+	switch chunk.Type {
+	case "content-delta":
+		var content struct {
+			Text string `json:"text"`
+		}
+		json.Unmarshal(chunk.Delta.Message.Content, &content)
+		return a.emitText(content.Text)
+	case "tool-call-start", "tool-call-delta":
+		delta := chunk.Delta.Message.ToolCalls
+		return a.emitArguments(a.toolCall(chunk.Index, delta.ID, delta.Function.Name), delta.Function.Arguments)
+	case "tool-call-end":
+		if call, ok := a.byIndex[chunk.Index]; ok {
+			return a.complete(call)
+		}
+	case "message-end":
+		a.stop = chunk.Delta.FinishReason
+		if chunk.Delta.Usage != nil {
+			a.usage = parseUsage(append(append([]byte(`{"usage":`), chunk.Delta.Usage...), '}'))
+		}
+		return a.completeToolCalls()
+	}
+	return nil
+}
+
+func (a *streamAssembler) handleTGI(data []byte) error {
+	var chunk struct {
+		Token struct {
+			Text    string `json:"text"`
+			Special bool   `json:"special"` // End-of-sequence and other control tokens
+		} `json:"token"`
+		Details *struct {
+			FinishReason    string `json:"finish_reason"`
+			GeneratedTokens int    `json:"generated_tokens"`
+		} `json:"details"` // Final chunk only
+	}
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return nil
+	}
+	if !chunk.Token.Special {
+		if err := a.emitText(chunk.Token.Text); err != nil {
+			return err
+		}
+	}
+	if chunk.Details != nil {
+		a.stop = chunk.Details.FinishReason
+		a.usage = TokenUsage{CompletionTokens: chunk.Details.GeneratedTokens, TotalTokens: chunk.Details.GeneratedTokens}
+	}
+	return nil
+}
+
+func (a *streamAssembler) handleErnie(data []byte) error {
+	var chunk struct {
+		Result       string `json:"result"`
+		IsEnd        bool   `json:"is_end"`
+		FinishReason string `json:"finish_reason"`
+	}
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return nil
+	}
+	if err := a.emitText(chunk.Result); err != nil {
+		return err
+	}
+	if usage := parseUsage(data); usage.TotalTokens > 0 {
+		a.usage = usage
+	}
+	if chunk.IsEnd {
+		a.stop = chunk.FinishReason
+	}
+	return nil
+}
+
+func (a *streamAssembler) handleOllama(data []byte) error {
+	var chunk struct {
+		Message *struct {
+			Content   string `json:"content"`
+			Thinking  string `json:"thinking"`
+			ToolCalls []struct {
+				Function struct {
+					Name      string          `json:"name"`
+					Arguments json.RawMessage `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"message"`
+		Done       bool   `json:"done"`
+		DoneReason string `json:"done_reason"`
+	}
+	if err := json.Unmarshal(data, &chunk); err != nil || chunk.Message == nil {
+		return nil
+	}
+	if err := a.emitReasoning(chunk.Message.Thinking); err != nil {
+		return err
+	}
+	if err := a.emitText(chunk.Message.Content); err != nil {
+		return err
+	}
+	// Ollama sends tool calls whole
+	for _, tc := range chunk.Message.ToolCalls {
+		call := a.toolCall(len(a.calls), "", tc.Function.Name)
+		if err := a.emitArguments(call, string(tc.Function.Arguments)); err != nil {
+			return err
+		}
+		if err := a.complete(call); err != nil {
+			return err
+		}
+	}
+	if chunk.Done {
+		a.stop = chunk.DoneReason
+		a.usage = parseUsage(data)
+	}
+	return nil
+}
+
+// toolCall returns tool call at index, creating it on first sight
+func (a *streamAssembler) toolCall(index int, id, name string) *streamToolCall {
+	call, ok := a.byIndex[index]
+	if !ok {
+		call = &streamToolCall{call: ToolCall{Type: "function"}, index: len(a.calls)}
+		a.byIndex[index] = call
+		a.calls = append(a.calls, call)
+	}
+	if id != "" {
+		call.call.ID = id
+	}
+	if name != "" {
+		call.call.Function.Name = name
+	}
+	return call
+}
+
+// emitText sends an answer text delta (inline <think> blocks routed to reasoning)
+func (a *streamAssembler) emitText(delta string) error {
+	if delta == "" {
+		return nil
+	}
+	a.raw.WriteString(delta)
+	answer, reasoning := delta, ""
+	if a.think != nil {
+		answer, reasoning = a.think.push(delta)
+	}
+	if err := a.emitReasoningText(reasoning); err != nil {
+		return err
+	}
+	return a.emitAnswer(answer)
+}
+
+// emitAnswer sends answer text without inline reasoning
+func (a *streamAssembler) emitAnswer(answer string) error {
+	if answer == "" {
+		return nil
+	}
+	a.text.WriteString(answer)
+	return a.sendAnswer(a.vault.restoreDelta(&a.heldAnswer, answer, false))
+}
+
+// sendAnswer sends restored answer text
+func (a *streamAssembler) sendAnswer(text string) error {
+	if text == "" {
+		return nil
+	}
+	event := StreamEvent{Type: StreamTextDelta, Text: text}
+	if a.structured {
+		event.Partial, _ = parsePartialJSON(a.vault.restoreJSON(a.text.String()))
+	}
+	return a.onEvent(event)
+}
+
+// emitReasoning sends a native reasoning delta
+func (a *streamAssembler) emitReasoning(delta string) error {
+	a.reasoning.WriteString(delta)
+	return a.emitReasoningText(delta)
+}
+
+func (a *streamAssembler) emitReasoningText(delta string) error {
+	if delta == "" {
+		return nil
+	}
+	if text := a.vault.restoreDelta(&a.heldReasoning, delta, false); text != "" {
+		return a.onEvent(StreamEvent{Type: StreamReasoningDelta, Text: text})
+	}
+	return nil
+}
+
+// emitArguments appends an argument fragment to call and sends a tool call delta
+func (a *streamAssembler) emitArguments(call *streamToolCall, delta string) error {
+	if delta == "" {
+		return nil
+	}
+	call.call.Function.Arguments += delta
+	return a.sendArguments(call, a.vault.restoreDelta(&call.held, delta, true))
+}
+
+// sendArguments sends a restored argument fragment with a restored snapshot of call
+func (a *streamAssembler) sendArguments(call *streamToolCall, text string) error {
+	if text == "" {
+		return nil
+	}
+	snapshot := call.call
+	snapshot.Function.Arguments = a.vault.restoreJSON(snapshot.Function.Arguments)
+	partial, _ := parsePartialJSON(snapshot.Function.Arguments)
+	return a.onEvent(StreamEvent{Type: StreamToolCallDelta, Text: text, ToolCall: &snapshot, Index: call.index, Partial: partial})
+}
+
+// complete marks call complete and sends StreamToolCallComplete (once)
+func (a *streamAssembler) complete(call *streamToolCall) error {
+	if call.complete {
+		return nil
+	}
+	call.complete = true
+	held := call.held
+	call.held = ""
+	if err := a.sendArguments(call, held); err != nil {
+		return err
+	}
+	if call.call.Function.Arguments == "" {
+		call.call.Function.Arguments = "{}"
+	}
+	snapshot := call.call
+	snapshot.Function.Arguments = a.vault.restoreJSON(snapshot.Function.Arguments)
+	var arguments any
+	json.Unmarshal([]byte(snapshot.Function.Arguments), &arguments)
+	return a.onEvent(StreamEvent{Type: StreamToolCallComplete, ToolCall: &snapshot, Index: call.index, Partial: arguments})
+}
+
+// completeToolCalls completes all open tool calls in order
+func (a *streamAssembler) completeToolCalls() error {
+	for _, call := range a.calls {
+		if err := a.complete(call); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish flushes text held back by the think filter and PII restoration and completes open tool calls
+func (a *streamAssembler) finish() error {
+	if a.think != nil {
+		answer, reasoning := a.think.flush()
+		if err := a.emitReasoningText(reasoning); err != nil {
+			return err
+		}
+		if err := a.emitAnswer(answer); err != nil {
+			return err
+		}
+	}
+	if a.heldReasoning != "" {
+		held := a.heldReasoning
+		a.heldReasoning = ""
+		if err := a.onEvent(StreamEvent{Type: StreamReasoningDelta, Text: held}); err != nil {
+			return err
+		}
+	}
+	held := a.heldAnswer
+	a.heldAnswer = ""
+	if err := a.sendAnswer(held); err != nil {
+		return err
+	}
+	return a.completeToolCalls()
+}
+
+// response builds the final response from everything received
+func (a *streamAssembler) response(client *Client) *Response {
+	response := &Response{
+		Text:         a.raw.String(),
+		Reasoning:    a.reasoning.String(),
+		FinishReason: parseFinishReason([]byte(fmt.Sprintf(`{"stop_reason":%q}`, a.stop))),
+		Usage:        a.usage,
+		Model:        a.model,
+		Provider:     client.Provider,
+	}
+	if response.Model == "" {
+		response.Model = client.Model
+	}
+	response.Usage.Provider = client.Provider
+	response.Usage.Model = client.Model
+	for _, call := range a.calls {
+		response.ToolCalls = append(response.ToolCalls, call.call)
+	}
+
+	if a.think != nil {
+		var inline string
+		response.Text, inline = splitThinkBlocks(response.Text)
+		if response.Reasoning == "" {
+			response.Reasoning = inline
+		}
+	}
+	return response
+}
+
+// thinkFilter splits streamed text into answer and inline <think> reasoning,
+// holding back fragments that may be the start of a tag split across chunks
+type thinkFilter struct {
+	inThink bool
+	pending string
+}
+
+// push returns the answer and reasoning parts of delta
+func (f *thinkFilter) push(delta string) (answer, reasoning string) {
+	text := f.pending + delta
+	f.pending = ""
+
+	var kept, thought strings.Builder
+	write := func(s string) {
+		if f.inThink {
+			thought.WriteString(s)
+		} else {
+			kept.WriteString(s)
+		}
+	}
+	for text != "" {
+		tag := thinkOpenTag
+		if f.inThink {
+			tag = thinkCloseTag
+		}
+		if i := strings.Index(text, tag); i >= 0 {
+			write(text[:i])
+			text = text[i+len(tag):]
+			f.inThink = !f.inThink
+			continue
+		}
+		keep := partialTagSuffix(text, tag)
+		write(text[:len(text)-keep])
+		f.pending = text[len(text)-keep:]
+		break
+	}
+	return kept.String(), thought.String()
+}
+
+// flush returns text held back at the end of the stream
+func (f *thinkFilter) flush() (answer, reasoning string) {
+	pending := f.pending
+	f.pending = ""
+	if f.inThink {
+		return "", pending
+	}
+	return pending, ""
+}
+
+// partialTagSuffix length of the longest suffix of text that is a proper prefix of tag
+func partialTagSuffix(text, tag string) int {
+	for k := min(len(text), len(tag)-1); k > 0; k-- {
+		if strings.HasSuffix(text, tag[:k]) {
+			return k
+		}
+	}
+	return 0
+}
+
+// maxPartialBacktrack bytes dropped from the end at most when repairing partial JSON
+const maxPartialBacktrack = 64
+
+// parsePartialJSON best-effort decodes a JSON prefix by closing open strings, arrays
+// and objects, dropping an incomplete trailing token if needed
+func parsePartialJSON(text string) (any, bool) {
+	text = strings.TrimSpace(text)
+	for drop := 0; drop <= maxPartialBacktrack && drop < len(text); drop++ {
+		var value any
+		if err := json.Unmarshal([]byte(closeJSON(text[:len(text)-drop])), &value); err == nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// closeJSON appends the closing quotes and brackets a JSON prefix is missing
+func closeJSON(prefix string) string {
+	var (
+		stack    []byte
+		inString bool
+		escaped  bool
+	)
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		switch {
+		case inString && escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			stack = append(stack, '}')
+		case c == '[':
+			stack = append(stack, ']')
+		case (c == '}' || c == ']') && len(stack) > 0:
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	var closed strings.Builder
+	closed.WriteString(prefix)
+	if inString {
+		if escaped {
+			return "" // Dangling escape can't be closed
+		}
+		closed.WriteByte('"')
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		closed.WriteByte(stack[i])
+	}
+	return closed.String()
+}
@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrStreamInterrupted is matched (errors.Is) by StreamInterruptedError
+var ErrStreamInterrupted = errors.New("AI stream interrupted")
+
+// StreamInterruptedError stream broke off before the provider finished the answer
+type StreamInterruptedError struct {
+	Partial *Response // Output received before the interruption (stitched if resumed)
+	Err     error     // Cause (read error, idle timeout, missing completion marker)
+}
+
+func (e *StreamInterruptedError) Error() string {
+	return fmt.Sprintf("%v after %d characters", e.Err, len(e.Partial.Text))
+}
+
+func (e *StreamInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *StreamInterruptedError) Is(target error) bool {
+	return target == ErrStreamInterrupted
+}
+
+// resumeOverlap answer text held back after a resume to drop repeated output
+const resumeOverlap = 200
+
+// ErrStreamNotRestartable is returned when a stream written to a consumer that can't
+// discard output (CallStreamTo, Gateway, REPL) fails after output and can't be resumed
+var ErrStreamNotRestartable = errors.New("stream failed after output was written and cannot be restarted")
+
+// appendOnlyStreamKey context key marking streams whose consumer can't take output back
+type appendOnlyStreamKey struct{}
+
+// withAppendOnlyStream marks streams of ctx as written where output can't be taken
+// back: StreamRetryRestart resumes instead, and a restart after output fails the call
+func withAppendOnlyStream(ctx context.Context) context.Context {
+	return context.WithValue(ctx, appendOnlyStreamKey{}, true)
+}
+
+// StreamRetryMode how WithStreamRetry recovers a failed stream
+type StreamRetryMode int
+
+const (
+	// StreamRetryOff only WithStreamResume applies
+	StreamRetryOff StreamRetryMode = iota
+	// StreamRetryResume continues from the text received so far, dropping repeated output
+	StreamRetryResume
+	// StreamRetryRestart resends the request from scratch after a StreamRestarted event
+	StreamRetryRestart
+)
+
+// WithStreamResume resumes interrupted streams up to maxResumes times
+//
+// A stream counts as interrupted when reading fails (connection reset, idle timeout)
+// or when it ends without a completion marker, as happens when a proxy drops an idle
+// connection. The request is then resent with the partial answer: Claude continues it
+// as an assistant prefill, other providers get the continue prompt. Text the model
+// repeats at the seam is dropped, so events and the final response read as one
+// answer. When resumes are exhausted the call fails with *StreamInterruptedError.
+//
+// Usage example:
+//   client := mcp.NewClaudeClientWithOptions(
+//       mcp.WithTimeouts(mcp.Timeouts{Idle: 30 * time.Second}),
+//       mcp.WithStreamResume(2),
+//   )
+func WithStreamResume(maxResumes int) ClientOption {
+	return func(c *Config) {
+		c.StreamResumes = maxResumes
+	}
+}
+
+// WithStreamRetry recovers failed streams under the same retry policy as other calls
+//
+// Interrupted streams and retryable errors (Config.RetryableErrors) are retried up to
+// WithMaxRetries attempts, waiting WithRetryWaitBase times the attempt in between, with
+// OnRetry hooks fired. StreamRetryResume continues from the received text like
+// WithStreamResume; StreamRetryRestart sends a StreamRestarted event, so consumers
+// discard what they got, then resends the request. Errors returned by onEvent are never
+// retried. Overrides WithStreamResume. CallStreamTo, Gateway and RunREPL can't take back
+// written output, so they always resume, and fail with ErrStreamNotRestartable when only
+// a restart would recover.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithMaxRetries(3), mcp.WithStreamRetry(mcp.StreamRetryRestart))
+func WithStreamRetry(mode StreamRetryMode) ClientOption {
+	return func(c *Config) {
+		c.StreamRetry = mode
+	}
+}
+
+// streamResumable streams req, recovering failures per WithStreamRetry or WithStreamResume
+func (client *Client) streamResumable(ctx context.Context, req *Request, vault *piiVault, onEvent func(StreamEvent) error) (*Response, error) {
+	mode, recoveries, retryPolicy := StreamRetryResume, client.config.StreamResumes, false
+	if client.config.StreamRetry != StreamRetryOff {
+		mode, recoveries, retryPolicy = client.config.StreamRetry, client.config.MaxRetries-1, true
+	}
+	appendOnly, _ := ctx.Value(appendOnlyStreamKey{}).(bool)
+	if appendOnly {
+		mode = StreamRetryResume
+	}
+
+	// Consumer errors end the call, and restarts are announced only after output
+	var consumerErr error
+	delivered := false
+	deliver := func(event StreamEvent) error {
+		delivered = true
+		if err := onEvent(event); err != nil {
+			consumerErr = err
+			return err
+		}
+		return nil
+	}
+
+	response, err := client.stream(ctx, req, vault, deliver)
+	var partial *Response
+	for attempt := 1; attempt <= recoveries; attempt++ {
+		if err == nil || ctx.Err() != nil || consumerErr != nil {
+			break
+		}
+		var interrupted *StreamInterruptedError
+		isInterrupted := errors.As(err, &interrupted)
+		if !isInterrupted && !(retryPolicy && client.hooks.isRetryableError(err)) {
+			break
+		}
+		if isInterrupted && mode == StreamRetryResume {
+			if partial == nil {
+				partial = interrupted.Partial
+			} else {
+				partial = stitchResponses(partial, interrupted.Partial)
+			}
+		}
+		if retryPolicy {
+			wait := client.config.RetryWaitBase * time.Duration(attempt)
+			client.fireRetry(ctx, req.Model, attempt, client.config.MaxRetries, wait, err)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		switch {
+		case partial != nil:
+			client.logger.Warnf("⚠️  [%s] Stream failed after %d characters (%v), resuming (%d/%d)",
+				client.String(), len(partial.Text), err, attempt, recoveries)
+			filter := &resumeFilter{previous: vault.restore(partial.Text), onEvent: deliver}
+			response, err = client.stream(ctx, client.resumeRequest(req, partial.Text), vault, filter.handle)
+			if flushErr := filter.flush(); err == nil {
+				err = flushErr
+			}
+		default:
+			client.logger.Warnf("⚠️  [%s] Stream failed (%v), restarting (%d/%d)", client.String(), err, attempt, recoveries)
+			if delivered {
+				if appendOnly {
+					return nil, fmt.Errorf("%w: %w", ErrStreamNotRestartable, err)
+				}
+				if err := deliver(StreamEvent{Type: StreamRestarted}); err != nil {
+					return nil, err
+				}
+				delivered = false
+			}
+			response, err = client.stream(ctx, req, vault, deliver)
+		}
+	}
+
+	if partial == nil {
+		return response, err
+	}
+	var interrupted *StreamInterruptedError
+	if errors.As(err, &interrupted) {
+		interrupted.Partial = stitchResponses(partial, interrupted.Partial)
+		return nil, interrupted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stitchResponses(partial, response), nil
+}
+
+// resumeRequest returns req continued after partial answer text
+func (client *Client) resumeRequest(req *Request, partial string) *Request {
+	if partial == "" {
+		return req
+	}
+	resumed := cloneRequest(req)
+	if client.Provider == ProviderClaude {
+		// Assistant prefill: the model continues the message itself (no trailing whitespace allowed)
+		resumed.Messages = append(resumed.Messages, Message{Role: "assistant", Content: strings.TrimRight(partial, " \t\n")})
+		return resumed
+	}
+	prompt := client.config.ContinuePrompt
+	if prompt == "" {
+		prompt = DefaultContinuePrompt
+	}
+	resumed.Messages = append(resumed.Messages, Message{Role: "assistant", Content: partial}, NewUserMessage(prompt))
+	return resumed
+}
+
+// resumeFilter holds back the start of a resumed answer until repeated text can be dropped
+type resumeFilter struct {
+	previous string // Answer text delivered before the resume
+	onEvent  func(StreamEvent) error
+
+	held    strings.Builder
+	flushed bool
+}
+
+func (f *resumeFilter) handle(event StreamEvent) error {
+	if event.Type != StreamTextDelta || f.flushed {
+		return f.onEvent(event)
+	}
+	f.held.WriteString(event.Text)
+	if f.held.Len() < resumeOverlap {
+		return nil
+	}
+	return f.flush()
+}
+
+// flush sends held text without the part repeating the previous answer
+func (f *resumeFilter) flush() error {
+	if f.flushed {
+		return nil
+	}
+	f.flushed = true
+	text := stitchSegments(f.previous, f.held.String())[len(f.previous):]
+	if text == "" {
+		return nil
+	}
+	return f.onEvent(StreamEvent{Type: StreamTextDelta, Text: text})
+}
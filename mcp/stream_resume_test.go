@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sequenceSSEClient answers the n-th streaming request with bodies[n] (the last one repeats)
+func sequenceSSEClient(sent *[]map[string]any, bodies ...string) *http.Client {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		var body map[string]any
+		json.Unmarshal(data, &body)
+		*sent = append(*sent, body)
+		stream := bodies[min(len(*sent), len(bodies))-1]
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(stream)), Header: make(http.Header)}, nil
+	}
+	return mockHTTP.ToHTTPClient()
+}
+
+func streamText(t *testing.T, client *Client) (string, *Response, error) {
+	t.Helper()
+	var text strings.Builder
+	response, err := client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("outlook?").MustBuild(), func(event StreamEvent) error {
+		if event.Type == StreamTextDelta {
+			text.WriteString(event.Text)
+		}
+		return nil
+	})
+	return text.String(), response, err
+}
+
+func TestStreamResume_DeduplicatesSeam(t *testing.T) {
+	var sent []map[string]any
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithStreamResume(2), WithHTTPClient(sequenceSSEClient(&sent,
+		// Proxy drops the connection: no finish_reason, no [DONE]
+		sseLines(`{"choices":[{"delta":{"content":"The trend is "}}]}`, `{"choices":[{"delta":{"content":"bullish because funding"}}]}`),
+		": keepalive\n\n"+sseLines(`{"choices":[{"delta":{"content":"because funding is negative."},"finish_reason":"stop"}]}`)+"data: [DONE]\n\n",
+	))).(*Client)
+
+	text, response, err := streamText(t, client)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	const want = "The trend is bullish because funding is negative."
+	if text != want || response.Text != want {
+		t.Errorf("expected %q, got events %q, response %q", want, text, response.Text)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("expected one resume request, got %d requests", len(sent))
+	}
+	messages := sent[1]["messages"].([]any)
+	assistant := messages[len(messages)-2].(map[string]any)
+	if assistant["role"] != "assistant" || assistant["content"] != "The trend is bullish because funding" {
+		t.Errorf("resume should carry partial answer, got %v", messages)
+	}
+}
+
+func TestStreamResume_Exhausted(t *testing.T) {
+	var sent []map[string]any
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithStreamResume(1), WithHTTPClient(sequenceSSEClient(&sent,
+		sseLines(`{"choices":[{"delta":{"content":"Part one, "}}]}`),
+		sseLines(`{"choices":[{"delta":{"content":"part two"}}]}`),
+	))).(*Client)
+
+	_, _, err := streamText(t, client)
+	var interrupted *StreamInterruptedError
+	if !errors.Is(err, ErrStreamInterrupted) || !errors.As(err, &interrupted) {
+		t.Fatalf("expected StreamInterruptedError, got %v", err)
+	}
+	if interrupted.Partial.Text != "Part one, part two" || len(sent) != 2 {
+		t.Errorf("unexpected partial %q after %d requests", interrupted.Partial.Text, len(sent))
+	}
+}
+
+func TestResumeRequest_ClaudePrefill(t *testing.T) {
+	client := NewClaudeClientWithOptions(WithAPIKey("k")).(*ClaudeClient)
+	resumed := client.resumeRequest(NewRequestBuilder().WithUserPrompt("outlook?").MustBuild(), "Bullish, because \n")
+	last := resumed.Messages[len(resumed.Messages)-1]
+	if len(resumed.Messages) != 2 || last.Role != "assistant" || last.Content != "Bullish, because" {
+		t.Errorf("expected assistant prefill, got %+v", resumed.Messages)
+	}
+}
+
+func TestStreamRetry_Restart(t *testing.T) {
+	var sent []map[string]any
+	var retries []RetryEvent
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithMaxRetries(3), WithRetryWaitBase(time.Millisecond),
+		WithStreamRetry(StreamRetryRestart), WithHooks(Hooks{OnRetry: func(e RetryEvent) { retries = append(retries, e) }}),
+		WithHTTPClient(sequenceSSEClient(&sent,
+			sseLines(`{"choices":[{"delta":{"content":"Half an ans"}}]}`),
+			sseLines(`{"choices":[{"delta":{"content":"Complete answer."},"finish_reason":"stop"}]}`)+"data: [DONE]\n\n",
+		))).(*Client)
+
+	var events []string
+	response, err := client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("outlook?").MustBuild(), func(event StreamEvent) error {
+		switch event.Type {
+		case StreamTextDelta:
+			events = append(events, event.Text)
+		case StreamRestarted:
+			events = nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(events, "") != "Complete answer." || response.Text != "Complete answer." {
+		t.Errorf("expected a clean restart, got events %q, response %q", events, response.Text)
+	}
+	if len(sent) != 2 || len(sent[1]["messages"].([]any)) != 1 {
+		t.Errorf("restart should resend the original request, got %v", sent)
+	}
+	if len(retries) != 1 || retries[0].Attempt != 1 || !errors.Is(retries[0].Err, ErrStreamInterrupted) {
+		t.Errorf("expected one retry event, got %+v", retries)
+	}
+}
+
+func TestStreamRetry_RetryableErrorBeforeOutput(t *testing.T) {
+	mock := NewMockHTTPClient()
+	requests := 0
+	mock.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		requests++
+		if requests < 3 {
+			return nil, errors.New("connection refused")
+		}
+		stream := sseLines(`{"choices":[{"delta":{"content":"ok"},"finish_reason":"stop"}]}`) + "data: [DONE]\n\n"
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(stream)), Header: make(http.Header)}, nil
+	}
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithMaxRetries(3), WithRetryWaitBase(time.Millisecond),
+		WithStreamRetry(StreamRetryResume), WithHTTPClient(mock.ToHTTPClient())).(*Client)
+
+	text, _, err := streamText(t, client)
+	if err != nil || text != "ok" || requests != 3 {
+		t.Errorf("expected success on the third attempt, got %q (%v) after %d requests", text, err, requests)
+	}
+
+	requests = -10
+	consumerErr := errors.New("consumer gave up")
+	mock.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		requests++
+		stream := sseLines(`{"choices":[{"delta":{"content":"partial"}}]}`)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(stream)), Header: make(http.Header)}, nil
+	}
+	_, err = client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("outlook?").MustBuild(), func(event StreamEvent) error {
+		return consumerErr
+	})
+	if !errors.Is(err, consumerErr) || requests != -9 {
+		t.Errorf("consumer errors must not be retried, got %v after %d requests", err, requests+10)
+	}
+}
+
+func TestCallStreamTo_ResumesInsteadOfRestarting(t *testing.T) {
+	var sent []map[string]any
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithMaxRetries(3), WithRetryWaitBase(time.Millisecond),
+		WithStreamRetry(StreamRetryRestart),
+		WithHTTPClient(sequenceSSEClient(&sent,
+			sseLines(`{"choices":[{"delta":{"content":"The trend is "}}]}`),
+			sseLines(`{"choices":[{"delta":{"content":"bullish."},"finish_reason":"stop"}]}`)+"data: [DONE]\n\n",
+			sseLines(`{"choices":[{"delta":{"content":"Half"}}]}`, `{"error":{"type":"overloaded_error","message":"busy"}}`),
+		))).(*Client)
+
+	var out strings.Builder
+	response, err := client.CallStreamTo(context.Background(), NewRequestBuilder().WithUserPrompt("outlook?").MustBuild(), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "The trend is bullish." || response.Text != "The trend is bullish." {
+		t.Errorf("written output must not repeat, got %q, response %q", out.String(), response.Text)
+	}
+	if len(sent) != 2 || len(sent[1]["messages"].([]any)) == 1 {
+		t.Errorf("interrupted stream should be resumed, got %v", sent)
+	}
+
+	out.Reset()
+	_, err = client.CallStreamTo(context.Background(), NewRequestBuilder().WithUserPrompt("outlook?").MustBuild(), &out)
+	if !errors.Is(err, ErrStreamNotRestartable) || out.String() != "Half" || len(sent) != 3 {
+		t.Errorf("expected ErrStreamNotRestartable without a restart, got %v, output %q after %d requests", err, out.String(), len(sent))
+	}
+}
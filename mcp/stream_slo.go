@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrFirstTokenSLO is the cancellation cause of a stream abandoned for a slow first token
+var ErrFirstTokenSLO = errors.New("first token latency SLO exceeded")
+
+// StreamMetrics token-level timing of a streamed response
+type StreamMetrics struct {
+	FirstToken      time.Duration // Call start until the first text, reasoning or tool call delta
+	Generation      time.Duration // First delta until the stream finished
+	Tokens          int           // Output tokens (reported usage, else estimated from deltas)
+	TokensPerSecond float64       // Sustained output rate over Generation
+}
+
+// StreamSLO latency objectives of streamed calls
+type StreamSLO struct {
+	MaxFirstToken      time.Duration // First delta later than this violates the SLO (0 = no limit)
+	MinTokensPerSecond float64       // Sustained rate below this violates the SLO (0 = no limit)
+	MinTokens          int           // Rate is only judged on streams at least this long (0 = 20 tokens)
+
+	// Failover receives the call when no token arrived within MaxFirstToken (nil = warn only).
+	// It must support CallStream, as all provider clients do. Rate violations are only
+	// reported: deltas already delivered cannot be withdrawn.
+	Failover AIClient
+
+	OnViolation func(StreamSLOViolation) // Optional callback for every violation
+}
+
+// StreamSLOViolation stream that missed its SLO
+type StreamSLOViolation struct {
+	Provider   string
+	Model      string
+	Reason     string        // "first_token" or "tokens_per_second"
+	Metrics    StreamMetrics // Measured values (FirstToken = MaxFirstToken for abandoned streams)
+	FailedOver bool          // Call was handed to StreamSLO.Failover
+}
+
+// WithStreamSLO sets first-token latency and throughput objectives of streamed calls
+//
+// Every streamed response carries its StreamMetrics. Missed objectives are added to
+// Response.Warnings, logged and passed to OnViolation; a stream that produced no
+// token within MaxFirstToken is abandoned and sent to Failover when one is set.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithStreamSLO(mcp.StreamSLO{
+//       MaxFirstToken:      2 * time.Second,
+//       MinTokensPerSecond: 20,
+//       Failover:           fastClient,
+//   }))
+func WithStreamSLO(slo StreamSLO) ClientOption {
+	return func(c *Config) {
+		c.StreamSLO = &slo
+	}
+}
+
+// streamMeter measures delta timing of one streamed call
+type streamMeter struct {
+	start time.Time
+
+	mu        sync.Mutex
+	first     time.Time
+	last      time.Time
+	tokens    int
+	abandoned bool // First token SLO fired, further deltas are dropped
+}
+
+func newStreamMeter(start time.Time) *streamMeter {
+	return &streamMeter{start: start}
+}
+
+// wrap records deltas before passing events to onEvent
+func (m *streamMeter) wrap(onEvent func(StreamEvent) error) func(StreamEvent) error {
+	return func(event StreamEvent) error {
+		switch event.Type {
+		case StreamTextDelta, StreamReasoningDelta, StreamToolCallDelta:
+			m.mu.Lock()
+			if m.abandoned {
+				m.mu.Unlock()
+				return ErrFirstTokenSLO
+			}
+			now := time.Now()
+			if m.first.IsZero() {
+				m.first = now
+			}
+			m.last = now
+			m.tokens += EstimateTokens(event.Text)
+			m.mu.Unlock()
+		case StreamRestarted:
+			m.mu.Lock()
+			m.tokens = 0
+			m.mu.Unlock()
+		}
+		return onEvent(event)
+	}
+}
+
+// abandon marks the stream abandoned if no delta arrived yet
+func (m *streamMeter) abandon() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.first.IsZero() {
+		m.abandoned = true
+	}
+	return m.abandoned
+}
+
+// metrics returns measured values, preferring reported output tokens
+func (m *streamMeter) metrics(usage TokenUsage) StreamMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics := StreamMetrics{Tokens: m.tokens}
+	if usage.CompletionTokens > 0 {
+		metrics.Tokens = usage.CompletionTokens
+	}
+	if m.first.IsZero() {
+		return metrics
+	}
+	metrics.FirstToken = m.first.Sub(m.start)
+	metrics.Generation = m.last.Sub(m.first)
+	if seconds := metrics.Generation.Seconds(); seconds > 0 {
+		metrics.TokensPerSecond = float64(metrics.Tokens) / seconds
+	}
+	return metrics
+}
+
+// watchFirstToken cancels ctx with ErrFirstTokenSLO when no delta arrives within the SLO
+//
+// Only armed when a failover client can take over; otherwise a slow stream is
+// simply reported once it finishes.
+func (client *Client) watchFirstToken(ctx context.Context, meter *streamMeter) (context.Context, func()) {
+	slo := client.config.StreamSLO
+	if slo == nil || slo.MaxFirstToken <= 0 || slo.Failover == nil {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(slo.MaxFirstToken, func() {
+		if meter.abandon() {
+			cancel(ErrFirstTokenSLO)
+		}
+	})
+	return ctx, func() {
+		timer.Stop()
+		cancel(nil)
+	}
+}
+
+// failoverStream sends a call abandoned for a slow first token to the SLO failover client
+func (client *Client) failoverStream(ctx context.Context, req *Request, onEvent func(StreamEvent) error) (*Response, error) {
+	slo := client.config.StreamSLO
+	streamer, ok := slo.Failover.(interface {
+		CallStream(ctx context.Context, req *Request, onEvent func(StreamEvent) error) (*Response, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("%w: failover client does not support streaming", ErrFirstTokenSLO)
+	}
+	violation := StreamSLOViolation{
+		Provider:   client.Provider,
+		Model:      req.Model,
+		Reason:     "first_token",
+		Metrics:    StreamMetrics{FirstToken: slo.MaxFirstToken},
+		FailedOver: true,
+	}
+	client.reportSLOViolation(violation)
+
+	failover := *req
+	failover.Model = "" // Failover answers with its own model
+	response, err := streamer.CallStream(ctx, &failover, onEvent)
+	if err != nil {
+		return nil, fmt.Errorf("stream failover after %w: %w", ErrFirstTokenSLO, err)
+	}
+	response.Warnings = append(response.Warnings, fmt.Sprintf("no token from %s within %s, failed over", client.Provider, slo.MaxFirstToken))
+	return response, nil
+}
+
+// checkStreamSLO records stream metrics on response and reports missed objectives
+func (client *Client) checkStreamSLO(response *Response, meter *streamMeter) {
+	metrics := meter.metrics(response.Usage)
+	response.StreamMetrics = &metrics
+
+	slo := client.config.StreamSLO
+	if slo == nil {
+		return
+	}
+	violation := StreamSLOViolation{Provider: client.Provider, Model: response.Model, Metrics: metrics}
+	if slo.MaxFirstToken > 0 && metrics.FirstToken > slo.MaxFirstToken {
+		violation.Reason = "first_token"
+		response.Warnings = append(response.Warnings, fmt.Sprintf("first token after %s, SLO %s", metrics.FirstToken.Round(time.Millisecond), slo.MaxFirstToken))
+		client.reportSLOViolation(violation)
+	}
+	minTokens := slo.MinTokens
+	if minTokens <= 0 {
+		minTokens = 20
+	}
+	if slo.MinTokensPerSecond > 0 && metrics.Tokens >= minTokens && metrics.TokensPerSecond < slo.MinTokensPerSecond {
+		violation.Reason = "tokens_per_second"
+		response.Warnings = append(response.Warnings, fmt.Sprintf("%.1f tokens/s, SLO %.1f", metrics.TokensPerSecond, slo.MinTokensPerSecond))
+		client.reportSLOViolation(violation)
+	}
+}
+
+func (client *Client) reportSLOViolation(violation StreamSLOViolation) {
+	client.logger.Warnf("⚠️  [%s] Stream SLO missed (%s): first token %s, %.1f tokens/s, failed over: %v",
+		client.String(), violation.Reason, violation.Metrics.FirstToken, violation.Metrics.TokensPerSecond, violation.FailedOver)
+	if slo := client.config.StreamSLO; slo != nil && slo.OnViolation != nil {
+		slo.OnViolation(violation)
+	}
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stalledSSEClient answers with a stream that sends nothing until the request is canceled
+func stalledSSEClient() *http.Client {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		reader, writer := io.Pipe()
+		go func() {
+			<-req.Context().Done()
+			writer.CloseWithError(req.Context().Err())
+		}()
+		return &http.Response{StatusCode: http.StatusOK, Body: reader, Header: make(http.Header)}, nil
+	}
+	return mockHTTP.ToHTTPClient()
+}
+
+func TestStreamSLO_FailoverOnSlowFirstToken(t *testing.T) {
+	var sent []map[string]any
+	fast := NewClient(WithProvider(ProviderOpenAI), WithAPIKey("sk-fast"), WithModel("fast-model"), WithLogger(NewNoopLogger()), WithHTTPClient(sequenceSSEClient(&sent,
+		sseLines(`{"choices":[{"delta":{"content":"Hold."},"finish_reason":"stop"}]}`)+"data: [DONE]\n\n",
+	)))
+	var violations []StreamSLOViolation
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(stalledSSEClient()),
+		WithStreamSLO(StreamSLO{
+			MaxFirstToken: 50 * time.Millisecond,
+			Failover:      fast,
+			OnViolation:   func(v StreamSLOViolation) { violations = append(violations, v) },
+		})).(*Client)
+
+	text, response, err := streamText(t, client)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if text != "Hold." || response.Text != "Hold." {
+		t.Errorf("expected failover answer, got events %q, response %q", text, response.Text)
+	}
+	if len(sent) != 1 || sent[0]["model"] != "fast-model" {
+		t.Errorf("failover should use its own model, got %v", sent)
+	}
+	if len(violations) != 1 || violations[0].Reason != "first_token" || !violations[0].FailedOver {
+		t.Errorf("unexpected violations %+v", violations)
+	}
+	if len(response.Warnings) == 0 || !strings.Contains(response.Warnings[len(response.Warnings)-1], "failed over") {
+		t.Errorf("expected failover warning, got %v", response.Warnings)
+	}
+}
+
+func TestStreamSLO_NoFailoverWithoutClient(t *testing.T) {
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(stalledSSEClient()),
+		WithStreamSLO(StreamSLO{MaxFirstToken: 10 * time.Millisecond}), WithTimeouts(Timeouts{Idle: 100 * time.Millisecond})).(*Client)
+
+	_, _, err := streamText(t, client)
+	if err == nil || errors.Is(err, ErrFirstTokenSLO) {
+		t.Errorf("stream should run until its own timeout, got %v", err)
+	}
+}
+
+func TestStreamSLO_MetricsAndThroughputWarning(t *testing.T) {
+	var sent []map[string]any
+	var violations []StreamSLOViolation
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(sequenceSSEClient(&sent,
+		sseLines(
+			`{"choices":[{"delta":{"content":"Funding is negative "}}]}`,
+			`{"choices":[{"delta":{"content":"and open interest rising."},"finish_reason":"stop"}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":10,"completion_tokens":12,"total_tokens":22}}`,
+		)+"data: [DONE]\n\n",
+	)), WithStreamSLO(StreamSLO{
+		MinTokensPerSecond: 1e15,
+		MinTokens:          1,
+		OnViolation:        func(v StreamSLOViolation) { violations = append(violations, v) },
+	})).(*Client)
+
+	_, response, err := streamText(t, client)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	metrics := response.StreamMetrics
+	if metrics == nil || metrics.Tokens != 12 || metrics.FirstToken <= 0 {
+		t.Fatalf("unexpected stream metrics %+v", metrics)
+	}
+	if len(violations) != 1 || violations[0].Reason != "tokens_per_second" {
+		t.Errorf("expected throughput violation, got %+v", violations)
+	}
+}
@@ -0,0 +1,318 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sseClient returns HTTP client answering every request with body as an event stream
+func sseClient(body string, bodies *[]map[string]any) *http.Client {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		if bodies != nil {
+			data, _ := io.ReadAll(req.Body)
+			var sent map[string]any
+			json.Unmarshal(data, &sent)
+			*bodies = append(*bodies, sent)
+		}
+		header := make(http.Header)
+		header.Set("Content-Type", "text/event-stream")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     header,
+		}, nil
+	}
+	return mockHTTP.ToHTTPClient()
+}
+
+func sseLines(payloads ...string) string {
+	var b strings.Builder
+	for _, p := range payloads {
+		b.WriteString("data: " + p + "\n\n")
+	}
+	return b.String()
+}
+
+func TestCallStream_OpenAIToolCallDeltas(t *testing.T) {
+	var bodies []map[string]any
+	stream := sseLines(
+		`{"model":"gpt-test","choices":[{"delta":{"content":"Checking "}}]}`,
+		`{"choices":[{"delta":{"content":"price"}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_price","arguments":"{\"sym"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"bol\":\"BTC\"}"}}]}}]}`,
+		`{"choices":[{"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+		`[DONE]`,
+	)
+
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, &bodies)),
+		WithProvider(ProviderOpenAI),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	var events []StreamEvent
+	response, err := client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("BTC?").MustBuild(), func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CallStream failed: %v", err)
+	}
+
+	if bodies[0]["stream"] != true {
+		t.Error("request should ask for a stream")
+	}
+	var types []StreamEventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	want := []StreamEventType{StreamTextDelta, StreamTextDelta, StreamToolCallDelta, StreamToolCallDelta, StreamToolCallComplete, StreamDone}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+
+	complete := events[4]
+	if complete.ToolCall.ID != "call_1" || complete.ToolCall.Function.Arguments != `{"symbol":"BTC"}` {
+		t.Errorf("completed tool call = %+v", complete.ToolCall)
+	}
+	if args, _ := complete.Partial.(map[string]any); args["symbol"] != "BTC" {
+		t.Errorf("completed arguments = %v", complete.Partial)
+	}
+
+	if response.Text != "Checking price" || response.Model != "gpt-test" || response.FinishReason != FinishReasonToolCalls {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if len(response.ToolCalls) != 1 || response.Usage.TotalTokens != 15 {
+		t.Errorf("tool calls = %+v, usage = %+v", response.ToolCalls, response.Usage)
+	}
+	if events[5].Response != response {
+		t.Error("Done event should carry the final response")
+	}
+}
+
+func TestCallStream_AnthropicEvents(t *testing.T) {
+	stream := strings.Join([]string{
+		"event: message_start",
+		`data: {"type":"message_start","message":{"model":"claude-test","usage":{"input_tokens":20,"output_tokens":1}}}`,
+		"",
+		`data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking"}}`,
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Hmm."}}`,
+		`data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+		`data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Buying."}}`,
+		`data: {"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"place_order"}}`,
+		`data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"side\":"}}`,
+		`data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"buy\"}"}}`,
+		`data: {"type":"content_block_stop","index":2}`,
+		`data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":12}}`,
+		`data: {"type":"message_stop"}`,
+	}, "\n")
+
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithProvider(ProviderClaude),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	var (
+		reasoning string
+		partials  []any
+		completed *ToolCall
+	)
+	response, err := client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("trade?").MustBuild(), func(event StreamEvent) error {
+		switch event.Type {
+		case StreamReasoningDelta:
+			reasoning += event.Text
+		case StreamToolCallDelta:
+			partials = append(partials, event.Partial)
+		case StreamToolCallComplete:
+			completed = event.ToolCall
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CallStream failed: %v", err)
+	}
+
+	if reasoning != "Hmm." || response.Reasoning != "Hmm." {
+		t.Errorf("reasoning = %q / %q", reasoning, response.Reasoning)
+	}
+	if len(partials) != 2 || !reflect.DeepEqual(partials[0], map[string]any{}) {
+		t.Errorf("partial arguments = %v, want repaired prefix first", partials)
+	}
+	if completed == nil || completed.Function.Name != "place_order" || completed.Function.Arguments != `{"side":"buy"}` {
+		t.Errorf("completed tool call = %+v", completed)
+	}
+	if response.Usage.PromptTokens != 20 || response.Usage.CompletionTokens != 12 || response.FinishReason != FinishReasonToolCalls {
+		t.Errorf("usage = %+v, finish = %q", response.Usage, response.FinishReason)
+	}
+}
+
+func TestCallStream_StructuredPartialsAndThinkBlocks(t *testing.T) {
+	stream := sseLines(
+		`{"choices":[{"delta":{"content":"<thi"}}]}`,
+		`{"choices":[{"delta":{"content":"nk>plan</think>{\"action\":"}}]}`,
+		`{"choices":[{"delta":{"content":"\"buy\",\"size\":"}}]}`,
+		`{"choices":[{"delta":{"content":"2}"},"finish_reason":"stop"}]}`,
+	)
+
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithJSONResponse(nil),
+	).(*Client)
+
+	var (
+		text     string
+		thoughts string
+		partials []any
+	)
+	response, err := client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("decide").MustBuild(), func(event StreamEvent) error {
+		switch event.Type {
+		case StreamTextDelta:
+			text += event.Text
+			partials = append(partials, event.Partial)
+		case StreamReasoningDelta:
+			thoughts += event.Text
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CallStream failed: %v", err)
+	}
+
+	if text != `{"action":"buy","size":2}` || response.Text != text {
+		t.Errorf("text = %q, response = %q", text, response.Text)
+	}
+	if thoughts != "plan" || response.Reasoning != "plan" {
+		t.Errorf("reasoning = %q / %q", thoughts, response.Reasoning)
+	}
+	if last, _ := partials[len(partials)-2].(map[string]any); last["action"] != "buy" {
+		t.Errorf("partial object = %v, want action decoded before the stream ends", partials[len(partials)-2])
+	}
+}
+
+func TestCallStream_CallbackErrorAborts(t *testing.T) {
+	stream := sseLines(`{"choices":[{"delta":{"content":"a"}}]}`, `{"choices":[{"delta":{"content":"b"}}]}`)
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	stop := errors.New("consumer gone")
+	calls := 0
+	_, err := client.CallStream(context.Background(), NewRequestBuilder().WithUserPrompt("hi").MustBuild(), func(StreamEvent) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("err = %v after %d events, want callback error after first event", err, calls)
+	}
+}
+
+func TestParsePartialJSON(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   any
+	}{
+		{`{"a":1,"b":"te`, map[string]any{"a": 1.0, "b": "te"}},
+		{`{"a":[1,2`, map[string]any{"a": []any{1.0, 2.0}}},
+		{`{"a":1,"b"`, map[string]any{"a": 1.0}},
+		{`{"a":tr`, map[string]any{}},
+		{`[{"x":"y"},`, []any{map[string]any{"x": "y"}}},
+	}
+	for _, tt := range tests {
+		got, ok := parsePartialJSON(tt.prefix)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePartialJSON(%q) = %v, %v; want %v", tt.prefix, got, ok, tt.want)
+		}
+	}
+}
+
+func TestCallStreamChan(t *testing.T) {
+	stream := sseLines(`{"choices":[{"delta":{"content":"a "}}]}`, `{"choices":[{"delta":{"content":"b"},"finish_reason":"stop"}]}`)
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	events, err := client.CallStreamChan(context.Background(), NewRequestBuilder().WithUserPrompt("hi").MustBuild())
+	if err != nil {
+		t.Fatalf("CallStreamChan failed: %v", err)
+	}
+	var (
+		text string
+		last StreamEvent
+	)
+	for event := range events {
+		if event.Type == StreamTextDelta {
+			text += event.Text
+		}
+		last = event
+	}
+	if text != "a b" || last.Type != StreamDone || last.Response.Text != "a b" {
+		t.Errorf("text = %q, last event = %v", text, last.Type)
+	}
+}
+
+func TestCallStreamChan_ConsumerCancels(t *testing.T) {
+	stream := sseLines(`{"choices":[{"delta":{"content":"a"}}]}`, `{"choices":[{"delta":{"content":"b"}}]}`, `{"choices":[{"delta":{"content":"c"}}]}`)
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.CallStreamChan(ctx, NewRequestBuilder().WithUserPrompt("hi").MustBuild())
+	if err != nil {
+		t.Fatalf("CallStreamChan failed: %v", err)
+	}
+	<-events
+	cancel()
+
+	// Channel must be closed without the consumer draining the rest of the stream
+	waitFor(t, func() bool {
+		select {
+		case _, ok := <-events:
+			return !ok
+		default:
+			return false
+		}
+	})
+}
+
+func TestCallStreamTo_WritesAndFlushes(t *testing.T) {
+	stream := sseLines(`{"choices":[{"delta":{"content":"hello "}}]}`, `{"choices":[{"delta":{"content":"world"},"finish_reason":"stop"}]}`)
+	client := NewClient(
+		WithHTTPClient(sseClient(stream, nil)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	recorder := httptest.NewRecorder()
+	response, err := client.CallStreamTo(context.Background(), NewRequestBuilder().WithUserPrompt("hi").MustBuild(), recorder)
+	if err != nil {
+		t.Fatalf("CallStreamTo failed: %v", err)
+	}
+	if recorder.Body.String() != "hello world" || response.Text != "hello world" {
+		t.Errorf("written %q, response %q", recorder.Body.String(), response.Text)
+	}
+	if !recorder.Flushed {
+		t.Error("writer implementing http.Flusher should be flushed")
+	}
+}
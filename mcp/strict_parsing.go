@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidResponse is matched (errors.Is) by ResponseParseError
+var ErrInvalidResponse = errors.New("invalid AI response")
+
+// knownFinishReasons normalized finish reasons (see parseFinishReason)
+var knownFinishReasons = map[string]bool{
+	FinishReasonStop:          true,
+	FinishReasonLength:        true,
+	FinishReasonToolCalls:     true,
+	FinishReasonContentFilter: true,
+}
+
+// ResponseParseError response body that could not be parsed, or failed strict checks
+//
+// Body is the raw provider response, kept for debugging.
+type ResponseParseError struct {
+	Reason string
+	Body   []byte
+	Err    error // Underlying parse error (nil for strict check failures)
+}
+
+func (e *ResponseParseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Reason, e.Err)
+	}
+	return e.Reason
+}
+
+func (e *ResponseParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ResponseParseError) Is(target error) bool {
+	return target == ErrInvalidResponse
+}
+
+// WithStrictParsing fails responses with unknown or missing finish reasons, or missing usage
+//
+// In lenient mode (default) such responses are returned with the problems listed in
+// Response.Warnings (and logged). Either way, unparseable bodies fail with a
+// *ResponseParseError carrying the raw body.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithStrictParsing(true))
+//   _, err := client.CallWithMessages(system, user)
+//   var parseErr *mcp.ResponseParseError
+//   if errors.As(err, &parseErr) {
+//       log.Printf("raw body: %s", parseErr.Body)
+//   }
+func WithStrictParsing(strict bool) ClientOption {
+	return func(c *Config) {
+		c.StrictParsing = strict
+	}
+}
+
+// checkResponse applies the parsing mode to the problems of response with raw body
+func (client *Client) checkResponse(response *Response, body []byte) error {
+	var problems []string
+	switch {
+	case response.FinishReason == "":
+		problems = append(problems, "missing finish reason")
+	case !knownFinishReasons[response.FinishReason]:
+		problems = append(problems, fmt.Sprintf("unknown finish reason %q", response.FinishReason))
+	}
+	if usage := response.Usage; usage.PromptTokens == 0 && usage.CompletionTokens == 0 && usage.TotalTokens == 0 {
+		problems = append(problems, "missing usage")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if client.config.StrictParsing {
+		return &ResponseParseError{Reason: "strict parsing: " + strings.Join(problems, ", "), Body: body}
+	}
+	response.Warnings = append(response.Warnings, problems...)
+	client.logger.Warnf("⚠️  [%s] Lenient parsing: %s", client.String(), strings.Join(problems, ", "))
+	return nil
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStrictParsing_Modes(t *testing.T) {
+	odd := `{"choices":[{"message":{"content":"hold"},"finish_reason":"recitation"}]}`
+	newClient := func(strict bool, answer string, logger Logger) *Client {
+		return NewClient(
+			WithHTTPClient(captureBodyClient(answer, new([]map[string]any))),
+			WithAPIKey("sk-test-key"),
+			WithLogger(logger),
+			WithStrictParsing(strict),
+		).(*Client)
+	}
+
+	logger := NewMockLogger()
+	full, err := newClient(false, odd, logger).Call(t.Context(), NewRequestBuilder().WithUserPrompt("hi").MustBuild())
+	if err != nil || len(full.Warnings) != 2 || !strings.Contains(full.Warnings[0], `"recitation"`) || full.Warnings[1] != "missing usage" {
+		t.Errorf("unexpected lenient warnings %v, %v", full.Warnings, err)
+	}
+	if len(logger.GetLogsByLevel("WARN")) == 0 {
+		t.Error("expected lenient parsing to log a warning")
+	}
+
+	_, err = newClient(true, odd, NewNoopLogger()).CallWithMessages("sys", "hi")
+	var parseErr *ResponseParseError
+	if !errors.Is(err, ErrInvalidResponse) || !errors.As(err, &parseErr) || string(parseErr.Body) != odd {
+		t.Fatalf("expected strict parse error with raw body, got %v", err)
+	}
+
+	valid := `{"choices":[{"message":{"content":"hold"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`
+	if _, err := newClient(true, valid, NewNoopLogger()).CallWithMessages("sys", "hi"); err != nil {
+		t.Errorf("strict mode rejected valid response: %v", err)
+	}
+}
+
+func TestStrictParsing_UnparseableBodyKept(t *testing.T) {
+	_, err := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[]}`, new([]map[string]any))),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(1),
+	).CallWithMessages("sys", "hi")
+	var parseErr *ResponseParseError
+	if !errors.As(err, &parseErr) || string(parseErr.Body) != `{"choices":[]}` || !strings.Contains(err.Error(), "empty response") {
+		t.Errorf("expected parse error with raw body, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	summarizeChunkTokens  = 3000 // Max tokens of document text sent in one call
+	summarizeConcurrency  = 4    // Chunks summarized in parallel
+	summarizeMinTarget    = 100  // Min tokens of a chunk summary
+	summarizeMaxReduction = 8    // Max reduce rounds (guards against non-shrinking summaries)
+)
+
+// summarizePrompt system prompt of summary calls
+const summarizePrompt = `You summarize documents for traders. Keep facts, figures, dates, names and cause-effect relations; drop filler.
+Write plain prose without preamble. Stay within the requested length.`
+
+// Summarize condenses doc to about targetTokens tokens using map-reduce
+//
+// See SummarizeWith.
+//
+// Usage example:
+//   summary, err := client.Summarize(ctx, filing, 300)
+func (client *Client) Summarize(ctx context.Context, doc string, targetTokens int) (string, error) {
+	return SummarizeWith(ctx, client, doc, targetTokens)
+}
+
+// SummarizeWith condenses doc to about targetTokens tokens using client
+//
+// Documents that fit in one call are summarized directly. Longer ones are split on
+// paragraph (then line, then word) boundaries into chunks, the chunks are summarized
+// in parallel and the partial summaries are reduced, in further rounds if they are
+// still too long, into the final summary.
+func SummarizeWith(ctx context.Context, client AIClient, doc string, targetTokens int) (string, error) {
+	if targetTokens <= 0 {
+		return "", fmt.Errorf("target tokens must be positive, got %d", targetTokens)
+	}
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return "", nil
+	}
+
+	for round := 0; EstimateTokens(doc) > summarizeChunkTokens; round++ {
+		if round == summarizeMaxReduction {
+			return "", errors.New("summaries did not shrink below the chunk size")
+		}
+		chunks := splitByTokens(doc, summarizeChunkTokens)
+		chunkTarget := max(targetTokens*2/len(chunks), summarizeMinTarget)
+		summaries, err := summarizeChunks(ctx, client, chunks, chunkTarget)
+		if err != nil {
+			return "", err
+		}
+		doc = strings.Join(summaries, "\n\n")
+	}
+	return summarizeOnce(ctx, client, doc, targetTokens)
+}
+
+// summarizeChunks summarizes chunks in parallel, keeping their order
+func summarizeChunks(ctx context.Context, client AIClient, chunks []string, targetTokens int) ([]string, error) {
+	summaries := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	slots := make(chan struct{}, summarizeConcurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			summaries[i], errs[i] = summarizeOnce(ctx, client, chunk, targetTokens)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("chunk %d: %w", i, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// summarizeOnce summarizes text with one call
+func summarizeOnce(ctx context.Context, client AIClient, text string, targetTokens int) (string, error) {
+	req, err := NewRequestBuilder().
+		WithSystemPrompt(summarizePrompt).
+		WithUserPrompt(fmt.Sprintf("Summarize in at most %d words:\n\n%s", max(targetTokens*3/4, 1), text)).
+		WithMaxTokens(targetTokens * 2).
+		Build()
+	if err != nil {
+		return "", err
+	}
+	summary, err := callRequest(ctx, client, req)
+	if err != nil {
+		return "", fmt.Errorf("summarize call failed: %w", err)
+	}
+	return strings.TrimSpace(summary), nil
+}
+
+// splitByTokens splits text into chunks of at most maxTokens tokens
+//
+// Paragraphs are kept whole when possible; oversized ones are split by lines, then words.
+func splitByTokens(text string, maxTokens int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentTokens := 0
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentTokens = 0
+		}
+	}
+	add := func(piece, separator string) {
+		tokens := EstimateTokens(piece)
+		if currentTokens > 0 && currentTokens+tokens > maxTokens {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString(separator)
+		}
+		current.WriteString(piece)
+		currentTokens += tokens
+	}
+
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		switch {
+		case paragraph == "":
+		case EstimateTokens(paragraph) <= maxTokens:
+			add(paragraph, "\n\n")
+		default:
+			for _, line := range strings.Split(paragraph, "\n") {
+				if EstimateTokens(line) <= maxTokens {
+					add(line, "\n")
+					continue
+				}
+				for _, word := range strings.Fields(line) {
+					add(word, " ")
+				}
+			}
+		}
+	}
+	flush()
+	return chunks
+}
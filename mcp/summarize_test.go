@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// summaryStub AIClient answering every summary request with a short numbered summary
+type summaryStub struct {
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (c *summaryStub) SetAPIKey(apiKey string, customURL string, customModel string) {}
+func (c *summaryStub) SetTimeout(timeout time.Duration)                              {}
+func (c *summaryStub) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	return "", nil
+}
+
+func (c *summaryStub) CallWithRequest(req *Request) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prompts = append(c.prompts, req.Messages[len(req.Messages)-1].Content)
+	return fmt.Sprintf("summary %d", len(c.prompts)), nil
+}
+
+func TestSummarize_MapReduce(t *testing.T) {
+	paragraph := strings.Repeat("word ", 2000) // ~2500 tokens
+	doc := strings.Join([]string{paragraph, paragraph, paragraph}, "\n\n")
+
+	stub := &summaryStub{}
+	summary, err := SummarizeWith(context.Background(), stub, doc, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 3 chunk summaries + 1 reduce
+	if len(stub.prompts) != 4 || summary != "summary 4" {
+		t.Fatalf("expected 3 map calls and 1 reduce, got %d calls, summary %q", len(stub.prompts), summary)
+	}
+	final := stub.prompts[3]
+	if !strings.Contains(final, "summary 1") || !strings.Contains(final, "summary 3") || !strings.Contains(final, "at most 150 words") {
+		t.Errorf("unexpected reduce prompt %q", final)
+	}
+}
+
+func TestSummarize_ShortDocumentSingleCall(t *testing.T) {
+	stub := &summaryStub{}
+	if _, err := SummarizeWith(context.Background(), stub, "BTC rallied 5% after CPI.", 50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stub.prompts) != 1 {
+		t.Errorf("expected one call, got %d", len(stub.prompts))
+	}
+}
+
+func TestSplitByTokens(t *testing.T) {
+	chunks := splitByTokens("a b c d e f g h\n\nshort", 2)
+	for _, chunk := range chunks {
+		if EstimateTokens(chunk) > 2 {
+			t.Errorf("chunk %q exceeds budget", chunk)
+		}
+	}
+	if strings.Join(chunks, " ") != "a b c d e f g h short" {
+		t.Errorf("chunks lost text: %q", chunks)
+	}
+}
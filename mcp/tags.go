@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// tagsKey context key of per-call tags
+type tagsKey struct{}
+
+// WithTags sets metadata tags (strategy, environment, ...) attached to every call
+//
+// Tags appear in log lines, audit records, webhook events and lifecycle hook events
+// (for metric labels). Per-call tags from ContextWithTags override them key by key.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithTags(map[string]string{"strategy": "scalper", "env": "prod"}))
+func WithTags(tags map[string]string) ClientOption {
+	return func(c *Config) {
+		c.Tags = maps.Clone(tags)
+	}
+}
+
+// ContextWithTags returns ctx tagging calls made with it (merged over tags already in ctx)
+//
+// Usage example:
+//   ctx := mcp.ContextWithTags(ctx, map[string]string{"symbol": "BTCUSDT", "run_id": runID})
+//   response, err := client.Call(ctx, request)
+func ContextWithTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := maps.Clone(TagsFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(tags))
+	}
+	maps.Copy(merged, tags)
+	return context.WithValue(ctx, tagsKey{}, merged)
+}
+
+// TagsFromContext returns per-call tags carried by ctx (nil if none)
+func TagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsKey{}).(map[string]string)
+	return tags
+}
+
+// tagsFor returns the tags of a call with ctx: audit tags, client tags, then per-call tags
+func (client *Client) tagsFor(ctx context.Context) map[string]string {
+	callTags := TagsFromContext(ctx)
+	if len(client.config.AuditTags)+len(client.config.Tags)+len(callTags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(client.config.AuditTags)+len(client.config.Tags)+len(callTags))
+	maps.Copy(tags, client.config.AuditTags)
+	maps.Copy(tags, client.config.Tags)
+	maps.Copy(tags, callTags)
+	return tags
+}
+
+// formatTags renders tags as sorted "key=value" pairs
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
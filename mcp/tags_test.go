@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTags_Propagation(t *testing.T) {
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+	sink := &memoryAuditSink{}
+	logger := NewMockLogger()
+	var requestTags map[string]string
+	client := NewClient(WithAPIKey("sk-test-key"), WithLogger(logger), WithHTTPClient(mock.ToHTTPClient()),
+		WithAuditSink(sink), WithAuditTags(map[string]string{"team": "quant", "env": "staging"}),
+		WithTags(map[string]string{"env": "prod", "strategy": "scalper"}),
+		WithHooks(Hooks{OnRequest: func(e RequestEvent) { requestTags = e.Tags }})).(*Client)
+
+	ctx := ContextWithTags(context.Background(), map[string]string{"symbol": "ETHUSDT"})
+	ctx = ContextWithTags(ctx, map[string]string{"symbol": "BTCUSDT", "run_id": "r-42"})
+	if _, err := client.CallMessages(ctx, "system", "user"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "env=prod run_id=r-42 strategy=scalper symbol=BTCUSDT team=quant"
+	records := sink.Records()
+	if len(records) != 1 || formatTags(records[0].Tags) != want {
+		t.Errorf("expected audit tags %q, got %v", want, records)
+	}
+	if formatTags(requestTags) != want {
+		t.Errorf("expected hook tags %q, got %v", want, requestTags)
+	}
+	if !strings.Contains(client.String(), "env=prod strategy=scalper") {
+		t.Errorf("client string should carry its tags, got %s", client.String())
+	}
+	found := false
+	for _, line := range logger.GetLogsByLevel("INFO") {
+		if strings.Contains(line.Message, "tags: run_id=r-42 symbol=BTCUSDT") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("request log line should carry per-call tags")
+	}
+}
@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const headerTenantID = "X-Tenant-Id"
+
+// tenantKey context key of the per-call tenant
+type tenantKey struct{}
+
+// TenantUsage AI usage accounted to one tenant
+type TenantUsage struct {
+	Calls            int
+	Errors           int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64 // Estimated USD
+	BudgetRemaining  float64 // USD left in the tenant's budget (0 when unlimited)
+}
+
+// WithTenant attributes every call to tenant id (customer, user or desk)
+//
+// The tenant is sent as the end-user field of the request body ("user", or Claude's
+// metadata.user_id) and the X-Tenant-Id header, and appears in audit records and
+// webhook payloads. ContextWithTenant overrides it per call.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithTenant("customer-42"))
+func WithTenant(id string) ClientOption {
+	return func(c *Config) {
+		c.Tenant = id
+	}
+}
+
+// ContextWithTenant returns ctx attributing calls made with it to tenant id
+//
+// Usage example:
+//   ctx := mcp.ContextWithTenant(ctx, account.ID)
+//   result, err := client.CallWithRequestContext(ctx, request)
+func ContextWithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// TenantFromContext returns tenant carried by ctx ("" if none)
+func TenantFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// WithTenantAccounts accounts usage per tenant and enforces tenant budgets
+//
+// Calls of a tenant whose budget is spent fail with ErrBudgetExceeded. Calls without
+// a tenant are not accounted. One TenantAccounts can be shared by several clients.
+//
+// Usage example:
+//   accounts := mcp.NewTenantAccounts(1) // $1 per tenant by default
+//   accounts.SetLimit("enterprise-7", 50)
+//   client := mcp.NewClient(mcp.WithTenantAccounts(accounts))
+//   client.CallWithRequestContext(mcp.ContextWithTenant(ctx, "enterprise-7"), request)
+//   usage := accounts.Usage("enterprise-7")
+func WithTenantAccounts(accounts *TenantAccounts) ClientOption {
+	return func(c *Config) {
+		c.TenantAccounts = accounts
+	}
+}
+
+// TenantAccounts per-tenant usage counters and budget buckets
+type TenantAccounts struct {
+	DefaultLimit float64 // USD budget of tenants without their own limit (0 = unlimited)
+
+	mu       sync.Mutex
+	accounts map[string]*tenantAccount
+}
+
+// tenantAccount usage and budget of one tenant
+type tenantAccount struct {
+	usage  TenantUsage
+	budget *Budget // nil = unlimited
+}
+
+// NewTenantAccounts creates accounts with defaultLimit USD per tenant (0 = unlimited)
+func NewTenantAccounts(defaultLimit float64) *TenantAccounts {
+	return &TenantAccounts{DefaultLimit: defaultLimit, accounts: make(map[string]*tenantAccount)}
+}
+
+// SetLimit sets budget of tenant to limit USD (0 = unlimited), keeping what was spent
+func (a *TenantAccounts) SetLimit(tenant string, limit float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	account := a.account(tenant)
+	if limit <= 0 {
+		account.budget = nil
+		return
+	}
+	if account.budget == nil {
+		account.budget = NewBudget(limit)
+		account.budget.spent = account.usage.Cost
+		return
+	}
+	account.budget.mu.Lock()
+	account.budget.Limit = limit
+	account.budget.mu.Unlock()
+}
+
+// Usage returns usage of tenant
+func (a *TenantAccounts) Usage(tenant string) TenantUsage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	account, ok := a.accounts[tenant]
+	if !ok {
+		return TenantUsage{BudgetRemaining: a.DefaultLimit}
+	}
+	return account.snapshot()
+}
+
+// All returns usage of every tenant that made calls or has a limit
+func (a *TenantAccounts) All() map[string]TenantUsage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	all := make(map[string]TenantUsage, len(a.accounts))
+	for tenant, account := range a.accounts {
+		all[tenant] = account.snapshot()
+	}
+	return all
+}
+
+// Reset clears usage and spend of every tenant (limits are kept)
+func (a *TenantAccounts) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, account := range a.accounts {
+		account.usage = TenantUsage{}
+		if account.budget != nil {
+			account.budget.Reset()
+		}
+	}
+}
+
+// check fails when tenant's budget is used up
+func (a *TenantAccounts) check(tenant string) error {
+	if a == nil || tenant == "" {
+		return nil
+	}
+	a.mu.Lock()
+	budget := a.account(tenant).budget
+	a.mu.Unlock()
+	if err := budget.check(); err != nil {
+		return fmt.Errorf("tenant %s: %w", tenant, err)
+	}
+	return nil
+}
+
+// record accounts a finished call of tenant
+func (a *TenantAccounts) record(tenant, model string, usage TokenUsage, callErr error) {
+	if a == nil || tenant == "" {
+		return
+	}
+	usage.Model = model
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	account := a.account(tenant)
+	account.usage.Calls++
+	if callErr != nil {
+		account.usage.Errors++
+	}
+	account.usage.PromptTokens += usage.PromptTokens
+	account.usage.CompletionTokens += usage.CompletionTokens
+	account.usage.Cost += EstimateCost(usage)
+	account.budget.spend(model, usage)
+}
+
+// account returns account of tenant, creating it with the default limit (a.mu held)
+func (a *TenantAccounts) account(tenant string) *tenantAccount {
+	account, ok := a.accounts[tenant]
+	if !ok {
+		account = &tenantAccount{}
+		if a.DefaultLimit > 0 {
+			account.budget = NewBudget(a.DefaultLimit)
+		}
+		a.accounts[tenant] = account
+	}
+	return account
+}
+
+func (t *tenantAccount) snapshot() TenantUsage {
+	usage := t.usage
+	usage.BudgetRemaining = t.budget.Remaining()
+	return usage
+}
+
+// tenantFor returns tenant of a call made with ctx (per-call override, then client default)
+func (client *Client) tenantFor(ctx context.Context) string {
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		return tenant
+	}
+	return client.config.Tenant
+}
+
+// applyTenant sets the end-user field of requestBody to the call's tenant
+func (client *Client) applyTenant(ctx context.Context, requestBody map[string]any) {
+	tenant := client.tenantFor(ctx)
+	if tenant == "" {
+		return
+	}
+	if client.Provider == ProviderClaude {
+		requestBody["metadata"] = map[string]any{"user_id": tenant}
+		return
+	}
+	requestBody["user"] = tenant
+}
+
+// setTenantHeader sends the call's tenant with req
+func (client *Client) setTenantHeader(ctx context.Context, req *http.Request) {
+	if tenant := client.tenantFor(ctx); tenant != "" {
+		req.Header.Set(headerTenantID, tenant)
+	}
+}
+
+// checkBudgets fails when the client budget or the call's tenant budget is used up
+func (client *Client) checkBudgets(ctx context.Context) error {
+	if err := client.config.Budget.check(); err != nil {
+		return err
+	}
+	return client.config.TenantAccounts.check(client.tenantFor(ctx))
+}
+
+// spendBudgets charges a finished call to the client budget and the call's tenant
+func (client *Client) spendBudgets(ctx context.Context, model string, usage TokenUsage, callErr error) {
+	client.config.Budget.spend(model, usage)
+	client.config.TenantAccounts.record(client.tenantFor(ctx), model, usage, callErr)
+}
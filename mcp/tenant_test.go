@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTenant_AttributionAndOverride(t *testing.T) {
+	var bodies []map[string]any
+	mockHTTP := captureBodyClient(`{"choices":[{"message":{"content":"ok"}}]}`, &bodies)
+	sink := &memoryAuditSink{}
+	client := NewClient(
+		WithHTTPClient(mockHTTP),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithAuditSink(sink),
+		WithTenant("desk-a"),
+	).(*Client)
+
+	req := NewRequestBuilder().WithUserPrompt("hi").MustBuild()
+	if _, err := client.CallWithRequestContext(context.Background(), req); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if _, err := client.CallWithRequestContext(ContextWithTenant(context.Background(), "customer-42"), req); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	if bodies[0]["user"] != "desk-a" || bodies[1]["user"] != "customer-42" {
+		t.Errorf("unexpected user fields %v, %v", bodies[0]["user"], bodies[1]["user"])
+	}
+	records := sink.Records()
+	if len(records) != 2 || records[0].Tenant != "desk-a" || records[1].Tenant != "customer-42" {
+		t.Errorf("unexpected audit tenants %+v", records)
+	}
+	if header := mockHTTP.Transport.(*MockHTTPClient).GetLastRequest().Header.Get(headerTenantID); header != "customer-42" {
+		t.Errorf("tenant header = %q", header)
+	}
+}
+
+func TestTenant_ClaudeMetadata(t *testing.T) {
+	client, _ := BaseClient(NewClaudeClientWithOptions(WithLogger(NewNoopLogger())))
+	body := map[string]any{}
+	client.applyTenant(ContextWithTenant(context.Background(), "customer-42"), body)
+	if metadata, _ := body["metadata"].(map[string]any); metadata["user_id"] != "customer-42" {
+		t.Errorf("unexpected Claude body %v", body)
+	}
+}
+
+func TestTenantAccounts_UsageAndBudgets(t *testing.T) {
+	RegisterModelPrice("tenant-test-model", ModelPrice{InputPerMillion: 1, OutputPerMillion: 1})
+	answer := `{"model":"tenant-test-model","choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":600000,"completion_tokens":400000,"total_tokens":1000000}}`
+	accounts := NewTenantAccounts(1.5)
+	accounts.SetLimit("enterprise", 0)
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, new([]map[string]any))),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithTenantAccounts(accounts),
+	).(*Client)
+
+	call := func(tenant string) error {
+		_, err := client.CallWithRequestContext(ContextWithTenant(context.Background(), tenant), NewRequestBuilder().WithModel("tenant-test-model").WithUserPrompt("hi").MustBuild())
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if err := call("retail"); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+	}
+	if err := call("retail"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected retail budget exceeded, got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := call("enterprise"); err != nil {
+			t.Fatalf("unlimited tenant call %d failed: %v", i, err)
+		}
+	}
+
+	retail := accounts.Usage("retail")
+	if retail.Calls != 2 || retail.PromptTokens != 1_200_000 || retail.Cost != 2 || retail.BudgetRemaining != 0 {
+		t.Errorf("unexpected retail usage %+v", retail)
+	}
+	if all := accounts.All(); len(all) != 2 || all["enterprise"].Calls != 3 {
+		t.Errorf("unexpected accounts %+v", all)
+	}
+	if fresh := accounts.Usage("new-tenant"); fresh.BudgetRemaining != 1.5 {
+		t.Errorf("unexpected fresh tenant usage %+v", fresh)
+	}
+}
@@ -0,0 +1,73 @@
+package mcp
+
+const (
+	ProviderTextGenWebUI       = "textgen-webui"
+	DefaultTextGenWebUIBaseURL = "http://127.0.0.1:5000/v1"
+)
+
+type TextGenWebUIClient struct {
+	*Client
+}
+
+// NewTextGenWebUIClient creates text-generation-webui client (backward compatible)
+func NewTextGenWebUIClient() AIClient {
+	return NewTextGenWebUIClientWithOptions()
+}
+
+// NewTextGenWebUIClientWithOptions creates text-generation-webui client (supports options pattern)
+//
+// Targets the OpenAI-compatible API of text-generation-webui (started with --api, port
+// 5000). No API key is needed unless the server runs with --api-key; the loaded model
+// answers, so none has to be named.
+//
+// Usage example:
+//   client := mcp.NewTextGenWebUIClientWithOptions(mcp.WithBaseURL("http://gpu-box:5000/v1"))
+//   answer, err := client.CallWithMessages(systemPrompt, userPrompt)
+func NewTextGenWebUIClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create text-generation-webui preset options
+	textGenOpts := []ClientOption{
+		WithProvider(ProviderTextGenWebUI),
+		WithModel(""),
+		WithBaseURL(DefaultTextGenWebUIBaseURL),
+		WithZeroAuth(),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(textGenOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create text-generation-webui client
+	textGenClient := &TextGenWebUIClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to TextGenWebUIClient (implement dynamic dispatch)
+	baseClient.hooks = textGenClient
+
+	return textGenClient
+}
+
+func (c *TextGenWebUIClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] text-generation-webui using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] text-generation-webui using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] text-generation-webui using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] text-generation-webui using the loaded model")
+	}
+}
+
+// marshalRequestBody leaves the model out when none is named, so the loaded one answers
+func (c *TextGenWebUIClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	omitEmptyModel(requestBody)
+	return c.Client.marshalRequestBody(requestBody)
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"testing"
+)
+
+// ============================================================
+// Test TextGenWebUIClient
+// ============================================================
+
+func TestTextGenWebUIClient_Preset(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("Hold BTC.")
+	client, err := NewProviderClient(ProviderTextGenWebUI,
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewNoopLogger()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	req := mockHTTP.GetLastRequest()
+	if req.URL.String() != "http://127.0.0.1:5000/v1/chat/completions" || req.Header.Get("Authorization") != "" {
+		t.Errorf("unexpected request %s %v", req.URL, req.Header)
+	}
+}
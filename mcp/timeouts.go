@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrIdleTimeout is returned when the response body stops delivering data for longer
+// than the idle timeout
+var ErrIdleTimeout = errors.New("response idle timeout: no data received")
+
+// Timeouts per-phase timeouts of a call (0 = no limit for that phase)
+//
+// A single overall timeout either kills long streamed answers or lets dead
+// connections hang; phase timeouts catch each failure mode separately.
+type Timeouts struct {
+	Dial           time.Duration // Establishing the TCP connection
+	ResponseHeader time.Duration // From request sent to response headers (TTFB)
+	Total          time.Duration // Whole call including reading the body
+	Idle           time.Duration // Max gap between body chunks (streaming idle)
+}
+
+// WithTimeouts sets per-phase timeouts
+//
+// Dial and ResponseHeader configure the pooled transport, so they don't apply to an
+// injected HTTPClient. Total applies to the client's own copy of HTTPClient, so an
+// injected (possibly shared) client is left unchanged. Note that for non-streaming calls the provider only sends
+// headers once the whole answer is generated, so keep ResponseHeader generous.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithTimeouts(mcp.Timeouts{
+//       Dial:           5 * time.Second,
+//       ResponseHeader: 90 * time.Second,
+//       Total:          5 * time.Minute,
+//       Idle:           30 * time.Second,
+//   }))
+func WithTimeouts(t Timeouts) ClientOption {
+	return func(c *Config) {
+		c.Transport.DialTimeout = t.Dial
+		c.Transport.ResponseHeaderTimeout = t.ResponseHeader
+		c.Timeout = t.Total
+		c.totalTimeout = true
+		c.IdleTimeout = t.Idle
+	}
+}
+
+// idleTimeoutBody cancels the request when no data arrives for timeout
+type idleTimeoutBody struct {
+	body    io.ReadCloser
+	ctx     context.Context
+	timer   *time.Timer
+	timeout time.Duration
+
+	mu      sync.Mutex
+	expired bool
+}
+
+// withIdleTimeout wraps body so that a gap longer than timeout between reads cancels
+// the request through cancel (timeout <= 0 = body returned as-is)
+func withIdleTimeout(ctx context.Context, body io.ReadCloser, timeout time.Duration, cancel context.CancelFunc) io.ReadCloser {
+	if timeout <= 0 {
+		return body
+	}
+	b := &idleTimeoutBody{body: body, ctx: ctx, timeout: timeout}
+	b.timer = time.AfterFunc(timeout, func() {
+		b.mu.Lock()
+		b.expired = true
+		b.mu.Unlock()
+		cancel()
+	})
+	return b
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	if err != nil && err != io.EOF {
+		b.mu.Lock()
+		expired := b.expired
+		b.mu.Unlock()
+		if expired {
+			return n, ErrIdleTimeout
+		}
+	}
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	return b.body.Close()
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Per-Phase Timeouts
+// ============================================================
+
+func TestTimeouts_AppliedToTransportAndConfig(t *testing.T) {
+	c := NewClient(
+		WithLogger(NewNoopLogger()),
+		WithTimeouts(Timeouts{
+			Dial:           2 * time.Second,
+			ResponseHeader: 45 * time.Second,
+			Total:          3 * time.Minute,
+			Idle:           20 * time.Second,
+		}),
+	).(*Client)
+
+	transport := transportOf(t, c)
+	if transport.ResponseHeaderTimeout != 45*time.Second {
+		t.Errorf("expected ResponseHeaderTimeout 45s, got %v", transport.ResponseHeaderTimeout)
+	}
+	if c.httpClient.Timeout != 3*time.Minute {
+		t.Errorf("expected total timeout 3m, got %v", c.httpClient.Timeout)
+	}
+	if c.config.IdleTimeout != 20*time.Second {
+		t.Errorf("expected idle timeout 20s, got %v", c.config.IdleTimeout)
+	}
+}
+
+func TestTimeouts_InjectedClientUnchanged(t *testing.T) {
+	shared := &http.Client{Timeout: time.Minute}
+	c := NewClient(
+		WithLogger(NewNoopLogger()),
+		WithHTTPClient(shared),
+		WithTimeouts(Timeouts{Total: 5 * time.Second}),
+	).(*Client)
+	if shared.Timeout != time.Minute {
+		t.Errorf("expected injected client to keep its timeout, got %v", shared.Timeout)
+	}
+	if c.httpClient.Timeout != 5*time.Second {
+		t.Errorf("expected total timeout 5s, got %v", c.httpClient.Timeout)
+	}
+
+	// Without a client to mutate, the option must not panic
+	c = NewClient(WithHTTPClient(nil), WithTimeouts(Timeouts{Total: 0})).(*Client)
+	if c.httpClient.Timeout != 0 {
+		t.Errorf("expected unlimited total timeout, got %v", c.httpClient.Timeout)
+	}
+	if reviewer := c.With(WithTimeouts(Timeouts{Total: time.Second})).(*Client); reviewer.httpClient.Timeout != time.Second || c.httpClient.Timeout != 0 {
+		t.Errorf("expected clone-only timeout, got %v and %v", reviewer.httpClient.Timeout, c.httpClient.Timeout)
+	}
+}
+
+func TestTimeouts_ResponseHeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte(`{"choices":[{"message":{"content":"late"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL(server.URL),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(1),
+		WithTimeouts(Timeouts{ResponseHeader: 50 * time.Millisecond, Total: 5 * time.Second}),
+	)
+
+	if _, err := client.CallWithMessages("system", "user"); err == nil {
+		t.Error("expected response header timeout")
+	}
+}
+
+func TestTimeouts_IdleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"content":"stalled"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithProvider(ProviderCustom),
+		WithBaseURL(server.URL),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(1),
+		WithTimeouts(Timeouts{Total: 5 * time.Second, Idle: 50 * time.Millisecond}),
+	)
+
+	start := time.Now()
+	_, err := client.CallWithMessages("system", "user")
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Fatalf("expected ErrIdleTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("idle timeout should abort quickly, took %v", elapsed)
+	}
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenSourceAPIKey placeholder API key of clients authenticating with a TokenSource
+//
+// It satisfies the "API key not set" checks; the Authorization header is replaced
+// by a fresh access token on every request.
+const tokenSourceAPIKey = "token-source"
+
+// tokenRefreshMargin tokens closer than this to expiry are refreshed before use
+const tokenRefreshMargin = time.Minute
+
+// OAuthToken short-lived access token
+type OAuthToken struct {
+	AccessToken string
+	Expiry      time.Time // Zero = never expires
+}
+
+// TokenSource supplies access tokens for providers authenticating with OAuth
+type TokenSource interface {
+	Token(ctx context.Context) (*OAuthToken, error)
+}
+
+// TokenSourceFunc adapts a function to TokenSource
+type TokenSourceFunc func(ctx context.Context) (*OAuthToken, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (*OAuthToken, error) {
+	return f(ctx)
+}
+
+// WithTokenSource authenticates every request with a Bearer access token from source
+//
+// Tokens are cached until shortly before they expire and fetched per request, so
+// long-running clients and retries always send a valid one. Use it for providers
+// whose credentials are not a static API key (Vertex AI, gateways behind OAuth).
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithBaseURL("https://llm.corp.internal/v1"),
+//       mcp.WithTokenSource(mcp.TokenSourceFunc(func(ctx context.Context) (*mcp.OAuthToken, error) {
+//           return idp.ClientCredentials(ctx)
+//       })),
+//   )
+func WithTokenSource(source TokenSource) ClientOption {
+	return func(c *Config) {
+		c.TokenSource = source
+		if c.APIKey == "" {
+			c.APIKey = tokenSourceAPIKey
+		}
+	}
+}
+
+// cachingTokenSource reuses a token until it is about to expire
+type cachingTokenSource struct {
+	source TokenSource
+	now    func() time.Time
+
+	mu    sync.Mutex
+	token *OAuthToken
+}
+
+// newCachingTokenSource wraps source with a token cache (sources already cached are returned as is)
+func newCachingTokenSource(source TokenSource) TokenSource {
+	if cached, ok := source.(*cachingTokenSource); ok {
+		return cached
+	}
+	return &cachingTokenSource{source: source, now: time.Now}
+}
+
+func (s *cachingTokenSource) Token(ctx context.Context) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && (s.token.Expiry.IsZero() || s.now().Add(tokenRefreshMargin).Before(s.token.Expiry)) {
+		return s.token, nil
+	}
+	token, err := s.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// tokenTransport http.RoundTripper setting the Authorization header from a token source
+type tokenTransport struct {
+	next       http.RoundTripper
+	source     TokenSource
+	queryParam string // Token goes in this query parameter ("" = Authorization header)
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	out := req.Clone(req.Context())
+	if t.queryParam != "" {
+		query := out.URL.Query()
+		query.Set(t.queryParam, token.AccessToken)
+		out.URL.RawQuery = query.Encode()
+		out.Header.Del("Authorization")
+		return t.next.RoundTrip(out)
+	}
+	out.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return t.next.RoundTrip(out)
+}
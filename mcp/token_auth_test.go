@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test TokenSource
+// ============================================================
+
+func TestCachingTokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	fetches := 0
+	source := newCachingTokenSource(TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+		fetches++
+		return &OAuthToken{AccessToken: fmt.Sprintf("token-%d", fetches), Expiry: now.Add(10 * time.Minute)}, nil
+	})).(*cachingTokenSource)
+	source.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		token, err := source.Token(context.Background())
+		if err != nil || token.AccessToken != "token-1" {
+			t.Fatalf("expected cached token-1, got %v, %v", token, err)
+		}
+	}
+
+	// Within the refresh margin of expiry
+	now = now.Add(9*time.Minute + 30*time.Second)
+	token, _ := source.Token(context.Background())
+	if token.AccessToken != "token-2" || fetches != 2 {
+		t.Errorf("expected refreshed token-2 after %d fetches, got %s", fetches, token.AccessToken)
+	}
+}
+
+func TestWithTokenSource_SetsBearerPerRequest(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("ok")
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+			return &OAuthToken{AccessToken: "ya29.access"}, nil
+		})),
+		WithLogger(NewNoopLogger()),
+	)
+
+	if _, err := client.CallWithMessages("", "ping"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if got := mockHTTP.GetLastRequest().Header.Get("Authorization"); got != "Bearer ya29.access" {
+		t.Errorf("unexpected Authorization %q", got)
+	}
+}
+
+func TestWithTokenSource_TokenError(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("ok")
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+			return nil, ErrNoGoogleCredentials
+		})),
+		WithMaxRetries(1),
+		WithLogger(NewNoopLogger()),
+	)
+
+	_, err := client.CallWithMessages("", "ping")
+	if !errors.Is(err, ErrNoGoogleCredentials) {
+		t.Fatalf("expected token error, got %v", err)
+	}
+	if len(mockHTTP.Requests) != 0 {
+		t.Errorf("no request should be sent without a token, got %d", len(mockHTTP.Requests))
+	}
+}
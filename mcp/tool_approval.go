@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultApprovalTimeout time a human has to answer an approval request
+const DefaultApprovalTimeout = 2 * time.Minute
+
+// ErrApprovalDenied is matched (errors.Is) by ApprovalDeniedError
+var ErrApprovalDenied = errors.New("tool call not approved")
+
+// ApprovalDeniedError high-risk tool call denied (or not answered in time)
+type ApprovalDeniedError struct {
+	Tool   string
+	Reason string
+}
+
+func (e *ApprovalDeniedError) Error() string {
+	return fmt.Sprintf("tool %s was not approved: %s", e.Tool, e.Reason)
+}
+
+func (e *ApprovalDeniedError) Is(target error) bool {
+	return target == ErrApprovalDenied
+}
+
+// ApprovalRequest tool call awaiting approval
+type ApprovalRequest struct {
+	Agent     string
+	CallID    string
+	Tool      string
+	Arguments string // JSON-encoded arguments requested by the model
+}
+
+// Approval answer to an ApprovalRequest
+type Approval struct {
+	Approved  bool
+	Arguments string // Replacement arguments, e.g. a reduced order size ("" = as requested)
+	Reason    string // Why it was denied or modified (shown to the model)
+}
+
+// ApprovalHandler decides on calls of tools that require approval
+//
+// Approve should return when ctx is done; an unanswered request is denied anyway once
+// the timeout passes. Returning an error denies the call.
+type ApprovalHandler interface {
+	Approve(ctx context.Context, request ApprovalRequest) (Approval, error)
+}
+
+// ApprovalFunc adapts a function to ApprovalHandler
+type ApprovalFunc func(ctx context.Context, request ApprovalRequest) (Approval, error)
+
+func (f ApprovalFunc) Approve(ctx context.Context, request ApprovalRequest) (Approval, error) {
+	return f(ctx, request)
+}
+
+// WithApprovalHandler consults handler before running tools registered with RequiresApproval
+//
+// Requests not answered within timeout (0 = DefaultApprovalTimeout) are denied, as are
+// all calls of such tools while no handler is set.
+//
+// Usage example:
+//   sandbox.WithApprovalHandler(mcp.ApprovalFunc(func(ctx context.Context, r mcp.ApprovalRequest) (mcp.Approval, error) {
+//       return telegramBot.AskOperator(ctx, r) // "Approve place_order {...}?"
+//   }), 5*time.Minute)
+func (s *ToolSandbox) WithApprovalHandler(handler ApprovalHandler, timeout time.Duration) *ToolSandbox {
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.approver = handler
+	s.approvalTimeout = timeout
+	return s
+}
+
+// approve asks the approval handler about request, returning the arguments to run with
+func (s *ToolSandbox) approve(ctx context.Context, handler ApprovalHandler, timeout time.Duration, request ApprovalRequest) (string, error) {
+	if handler == nil {
+		return "", &ApprovalDeniedError{Tool: request.Tool, Reason: "no approval handler configured"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type answer struct {
+		approval Approval
+		err      error
+	}
+	answers := make(chan answer, 1)
+	go func() {
+		approval, err := handler.Approve(ctx, request)
+		answers <- answer{approval, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", &ApprovalDeniedError{Tool: request.Tool, Reason: fmt.Sprintf("no answer within %v", timeout)}
+	case a := <-answers:
+		switch {
+		case a.err != nil:
+			return "", &ApprovalDeniedError{Tool: request.Tool, Reason: a.err.Error()}
+		case !a.approval.Approved:
+			reason := a.approval.Reason
+			if reason == "" {
+				reason = "denied by approver"
+			}
+			return "", &ApprovalDeniedError{Tool: request.Tool, Reason: reason}
+		case a.approval.Arguments != "":
+			return a.approval.Arguments, nil
+		}
+		return request.Arguments, nil
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolApproval_Gate(t *testing.T) {
+	var executed []string
+	sandbox := NewToolSandbox().
+		Register(RegisteredTool{
+			Definition:       Tool{Type: "function", Function: FunctionDef{Name: "place_order"}},
+			Permissions:      []ToolPermission{ToolPermissionTrade},
+			RequiresApproval: true,
+			Handler: func(ctx context.Context, arguments string) (string, error) {
+				executed = append(executed, arguments)
+				return "order placed", nil
+			},
+		}).
+		Allow("executor", ToolPolicy{Tools: []string{"place_order"}})
+	ctx := context.Background()
+	call := ToolCall{ID: "call-1", Function: ToolCallFunction{Name: "place_order", Arguments: `{"qty":10}`}}
+
+	if result := sandbox.Execute(ctx, "executor", call); !errors.Is(result.Err, ErrApprovalDenied) {
+		t.Errorf("expected default deny without handler, got %+v", result)
+	}
+
+	var requests []ApprovalRequest
+	sandbox.WithApprovalHandler(ApprovalFunc(func(ctx context.Context, r ApprovalRequest) (Approval, error) {
+		requests = append(requests, r)
+		if len(requests) == 1 {
+			return Approval{Approved: true, Arguments: `{"qty":2}`}, nil
+		}
+		return Approval{Reason: "position limit reached"}, nil
+	}), time.Second)
+
+	if result := sandbox.Execute(ctx, "executor", call); result.Err != nil || result.Content != "order placed" {
+		t.Fatalf("approved call = %+v", result)
+	}
+	if len(executed) != 1 || executed[0] != `{"qty":2}` {
+		t.Errorf("expected modified arguments to run, got %v", executed)
+	}
+	if requests[0].Agent != "executor" || requests[0].Arguments != `{"qty":10}` {
+		t.Errorf("unexpected approval request %+v", requests[0])
+	}
+
+	result := sandbox.Execute(ctx, "executor", call)
+	if !errors.Is(result.Err, ErrApprovalDenied) || !strings.Contains(result.Content, "position limit reached") {
+		t.Errorf("expected denial with reason, got %+v", result)
+	}
+	if len(executed) != 1 {
+		t.Errorf("denied call was executed: %v", executed)
+	}
+}
+
+func TestToolApproval_Timeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	sandbox := NewToolSandbox().
+		Register(RegisteredTool{
+			Definition:       Tool{Function: FunctionDef{Name: "withdraw"}},
+			RequiresApproval: true,
+			Handler:          func(ctx context.Context, arguments string) (string, error) { return "done", nil },
+		}).
+		Allow("agent", ToolPolicy{Tools: []string{"withdraw"}}).
+		WithApprovalHandler(ApprovalFunc(func(ctx context.Context, r ApprovalRequest) (Approval, error) {
+			<-block // Never answers, ignoring ctx
+			return Approval{Approved: true}, nil
+		}), 20*time.Millisecond)
+
+	start := time.Now()
+	result := sandbox.Execute(context.Background(), "agent", ToolCall{Function: ToolCallFunction{Name: "withdraw"}})
+	if !errors.Is(result.Err, ErrApprovalDenied) || !strings.Contains(result.Content, "no answer within") {
+		t.Errorf("expected timeout denial, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("timeout took %v", elapsed)
+	}
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultToolResultBytes size limit of results of tools without their own limit
+const DefaultToolResultBytes = 32 * 1024
+
+// ToolOverflow what to do with a tool result over its size limit
+type ToolOverflow string
+
+const (
+	ToolOverflowTruncate  ToolOverflow = "truncate"  // Cut the result, noting how much was dropped (default)
+	ToolOverflowSummarize ToolOverflow = "summarize" // Summarize the result with the guard's summarizer
+	ToolOverflowReject    ToolOverflow = "reject"    // Fail with ErrInvalidToolResult
+)
+
+// ErrInvalidToolResult is matched (errors.Is) by ToolResultError
+var ErrInvalidToolResult = errors.New("invalid tool result")
+
+// ToolResultError tool result failed schema validation or its size limit
+type ToolResultError struct {
+	Tool string
+	Err  error
+}
+
+func (e *ToolResultError) Error() string {
+	return fmt.Sprintf("tool %s returned an invalid result: %v", e.Tool, e.Err)
+}
+
+func (e *ToolResultError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ToolResultError) Is(target error) bool {
+	return target == ErrInvalidToolResult
+}
+
+// ToolOutputSpec expected output of one tool
+type ToolOutputSpec struct {
+	Schema        map[string]any // JSON Schema the result must match (nil = any text)
+	MaxBytes      int            // Size limit (0 = DefaultToolResultBytes, < 0 = unlimited)
+	Overflow      ToolOverflow   // Handling of results over MaxBytes ("" = ToolOverflowTruncate)
+	SummaryTokens int            // Summary length for ToolOverflowSummarize (0 = about MaxBytes/8 tokens)
+}
+
+// ToolResultGuard validates and bounds tool results before they are fed back to the model
+//
+// A single oversized tool result (a 500KB order book dump, a full web page) would
+// otherwise blow the context window of the next agent step. Results are validated
+// against the tool's output schema first, then truncated, summarized or rejected when
+// over the size limit. Tools without a spec only get DefaultToolResultBytes truncation.
+type ToolResultGuard struct {
+	summarizer AIClient // Used by ToolOverflowSummarize (nil = truncate instead)
+
+	mu    sync.RWMutex
+	specs map[string]ToolOutputSpec
+}
+
+// NewToolResultGuard creates guard summarizing oversized results with summarizer (may be nil)
+//
+// Usage example:
+//   guard := mcp.NewToolResultGuard(cheapClient).
+//       WithTool("get_klines", mcp.ToolOutputSpec{Schema: klinesSchema, MaxBytes: 16 << 10}).
+//       WithTool("fetch_news", mcp.ToolOutputSpec{MaxBytes: 8 << 10, Overflow: mcp.ToolOverflowSummarize})
+//   output, err := guard.Prepare(ctx, call.Function.Name, rawOutput)
+//   if err != nil {
+//       output = err.Error() // Let the model see what went wrong
+//   }
+//   messages = append(messages, mcp.NewMessage("tool", output))
+func NewToolResultGuard(summarizer AIClient) *ToolResultGuard {
+	return &ToolResultGuard{summarizer: summarizer, specs: make(map[string]ToolOutputSpec)}
+}
+
+// WithTool sets output spec of tool
+func (g *ToolResultGuard) WithTool(tool string, spec ToolOutputSpec) *ToolResultGuard {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.specs[tool] = spec
+	return g
+}
+
+// Prepare returns result of tool ready to be fed back to the model
+//
+// Fails with a *ToolResultError when the result doesn't match the tool's schema or is
+// over the limit of a ToolOverflowReject tool.
+func (g *ToolResultGuard) Prepare(ctx context.Context, tool, result string) (string, error) {
+	g.mu.RLock()
+	spec := g.specs[tool]
+	g.mu.RUnlock()
+
+	if spec.Schema != nil {
+		if err := validateJSONResponse(result, &ResponseFormat{Schema: spec.Schema}); err != nil {
+			return "", &ToolResultError{Tool: tool, Err: err}
+		}
+	}
+
+	limit := spec.MaxBytes
+	if limit == 0 {
+		limit = DefaultToolResultBytes
+	}
+	if limit < 0 || len(result) <= limit {
+		return result, nil
+	}
+
+	switch spec.Overflow {
+	case ToolOverflowReject:
+		return "", &ToolResultError{Tool: tool, Err: fmt.Errorf("result of %d bytes exceeds limit of %d", len(result), limit)}
+	case ToolOverflowSummarize:
+		if g.summarizer != nil {
+			tokens := spec.SummaryTokens
+			if tokens <= 0 {
+				tokens = max(limit/8, summarizeMinTarget)
+			}
+			summary, err := SummarizeWith(ctx, g.summarizer, result, tokens)
+			if err == nil {
+				return fmt.Sprintf("[summary of %d-byte %s result]\n%s", len(result), tool, summary), nil
+			}
+			// Fall back to truncation, the agent step shouldn't fail because the summarizer did
+		}
+	}
+	return truncateBytes(result, limit), nil
+}
+
+// truncateBytes cuts text to at most limit bytes (on a rune boundary), noting what was dropped
+func truncateBytes(text string, limit int) string {
+	// Reserve room for the longest possible marker
+	reserved := len(fmt.Sprintf("\n...[truncated %d of %d bytes]", len(text), len(text)))
+	cut := max(limit-reserved, 0)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + fmt.Sprintf("\n...[truncated %d of %d bytes]", len(text)-cut, len(text))
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var quoteSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"symbol", "price"},
+	"properties": map[string]any{
+		"symbol": map[string]any{"type": "string"},
+		"price":  map[string]any{"type": "number"},
+	},
+}
+
+func TestToolResultGuard_SchemaValidation(t *testing.T) {
+	guard := NewToolResultGuard(nil).WithTool("get_quote", ToolOutputSpec{Schema: quoteSchema})
+	ctx := context.Background()
+
+	if out, err := guard.Prepare(ctx, "get_quote", `{"symbol":"BTCUSDT","price":64000.5}`); err != nil || !strings.Contains(out, "BTCUSDT") {
+		t.Fatalf("valid result = %q, %v", out, err)
+	}
+	_, err := guard.Prepare(ctx, "get_quote", `{"symbol":"BTCUSDT"}`)
+	var resultErr *ToolResultError
+	if !errors.Is(err, ErrInvalidToolResult) || !errors.As(err, &resultErr) || resultErr.Tool != "get_quote" {
+		t.Errorf("expected ToolResultError, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidJSONResponse) {
+		t.Errorf("expected schema violation to be wrapped, got %v", err)
+	}
+}
+
+func TestToolResultGuard_Overflow(t *testing.T) {
+	summarizer := &stubClient{answer: "order book is bid-heavy"}
+	guard := NewToolResultGuard(summarizer).
+		WithTool("order_book", ToolOutputSpec{MaxBytes: 1000, Overflow: ToolOverflowSummarize}).
+		WithTool("raw_dump", ToolOutputSpec{MaxBytes: 1000, Overflow: ToolOverflowReject})
+	ctx := context.Background()
+	big := strings.Repeat("价格 64000 ", 50_000)
+
+	out, err := guard.Prepare(ctx, "unknown_tool", big)
+	if err != nil || len(out) > DefaultToolResultBytes || !strings.Contains(out, "[truncated") {
+		t.Errorf("expected default truncation, got %d bytes, %v", len(out), err)
+	}
+	if !strings.HasPrefix(out, "价格") || !strings.Contains(out, "\n...[truncated") {
+		t.Errorf("unexpected truncated result %q", out[len(out)-60:])
+	}
+
+	out, err = guard.Prepare(ctx, "order_book", big)
+	if err != nil || !strings.Contains(out, "order book is bid-heavy") {
+		t.Errorf("expected summary, got %q, %v", out, err)
+	}
+
+	if _, err := guard.Prepare(ctx, "raw_dump", big); !errors.Is(err, ErrInvalidToolResult) {
+		t.Errorf("expected rejection, got %v", err)
+	}
+	if out, _ := guard.Prepare(ctx, "raw_dump", "small"); out != "small" {
+		t.Errorf("small result changed to %q", out)
+	}
+}
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ToolPermission capability a tool needs
+type ToolPermission string
+
+const (
+	ToolPermissionReadOnly ToolPermission = "read-only"       // Reads data, no side effects
+	ToolPermissionNetwork  ToolPermission = "network"         // Reaches external services
+	ToolPermissionTrade    ToolPermission = "trade-execution" // Places, changes or cancels orders
+)
+
+// ErrToolNotPermitted is matched (errors.Is) by ToolPolicyError
+var ErrToolNotPermitted = errors.New("tool call not permitted")
+
+// ToolPolicyError tool call refused by the agent's execution policy
+type ToolPolicyError struct {
+	Agent  string
+	Tool   string
+	Reason string
+}
+
+func (e *ToolPolicyError) Error() string {
+	return fmt.Sprintf("tool %s is not permitted for agent %s: %s", e.Tool, e.Agent, e.Reason)
+}
+
+func (e *ToolPolicyError) Is(target error) bool {
+	return target == ErrToolNotPermitted
+}
+
+// ToolHandler executes a tool call with its JSON-encoded arguments
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// RegisteredTool tool definition, the permissions it needs and its implementation
+type RegisteredTool struct {
+	Definition  Tool
+	Permissions []ToolPermission // Every permission must be granted (nil = read-only)
+	Handler     ToolHandler
+
+	RequiresApproval bool // Every call needs the sandbox's ApprovalHandler to approve it
+}
+
+// ToolPolicy explicit allow rules of one agent
+type ToolPolicy struct {
+	Tools       []string         // Tools allowed by name
+	Permissions []ToolPermission // Tools needing only these permissions are allowed
+}
+
+// ToolExecution outcome of one tool call, ready to be fed back to the model
+type ToolExecution struct {
+	CallID  string
+	Tool    string
+	Content string // Tool output, or the error shown to the model
+	Err     error  // Policy, handler or result validation error (nil = success)
+}
+
+// Message returns the execution as a "tool" message answering CallID
+func (e ToolExecution) Message() Message {
+	return NewToolMessage(e.CallID, e.Content)
+}
+
+// ToolSandbox registry of tools executed under per-agent permission policies
+//
+// Agents are denied every tool until allowed by a policy. Calls of tools the agent is
+// not permitted to use are not executed; their error is returned to the model as the
+// tool output so it can pick another course. Results pass through the result guard
+// (DefaultToolResultBytes truncation unless WithResultGuard is set). Tools registered
+// with RequiresApproval additionally need the approval handler's consent.
+type ToolSandbox struct {
+	mu       sync.RWMutex
+	tools    map[string]RegisteredTool
+	policies map[string]ToolPolicy
+	guard    *ToolResultGuard
+
+	approver        ApprovalHandler
+	approvalTimeout time.Duration
+}
+
+// NewToolSandbox creates empty sandbox
+//
+// Usage example:
+//   sandbox := mcp.NewToolSandbox().
+//       Register(mcp.RegisteredTool{Definition: klinesTool, Permissions: []mcp.ToolPermission{mcp.ToolPermissionNetwork}, Handler: getKlines}).
+//       Register(mcp.RegisteredTool{Definition: orderTool, Permissions: []mcp.ToolPermission{mcp.ToolPermissionTrade}, Handler: placeOrder}).
+//       Allow("analyst", mcp.ToolPolicy{Permissions: []mcp.ToolPermission{mcp.ToolPermissionReadOnly, mcp.ToolPermissionNetwork}}).
+//       Allow("executor", mcp.ToolPolicy{Tools: []string{"place_order"}})
+//   request.Tools = sandbox.Tools("analyst")
+//   messages = append(messages, mcp.NewToolCallsMessage(response.Text, response.ToolCalls))
+//   for _, call := range response.ToolCalls {
+//       messages = append(messages, sandbox.Execute(ctx, "analyst", call).Message())
+//   }
+func NewToolSandbox() *ToolSandbox {
+	return &ToolSandbox{
+		tools:    make(map[string]RegisteredTool),
+		policies: make(map[string]ToolPolicy),
+		guard:    NewToolResultGuard(nil),
+	}
+}
+
+// Register adds tool (a tool of the same name is replaced)
+func (s *ToolSandbox) Register(tool RegisteredTool) *ToolSandbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[tool.Definition.Function.Name] = tool
+	return s
+}
+
+// Allow sets execution policy of agent
+func (s *ToolSandbox) Allow(agent string, policy ToolPolicy) *ToolSandbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies[agent] = policy
+	return s
+}
+
+// WithResultGuard validates and bounds tool results with guard
+func (s *ToolSandbox) WithResultGuard(guard *ToolResultGuard) *ToolSandbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guard = guard
+	return s
+}
+
+// Tools returns definitions of the tools agent may call, sorted by name
+func (s *ToolSandbox) Tools(agent string) []Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var tools []Tool
+	for name, tool := range s.tools {
+		if s.permitted(agent, name, tool) == "" {
+			tools = append(tools, tool.Definition)
+		}
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Function.Name < tools[j].Function.Name })
+	return tools
+}
+
+// Execute runs call for agent if its policy permits the tool
+func (s *ToolSandbox) Execute(ctx context.Context, agent string, call ToolCall) ToolExecution {
+	name := call.Function.Name
+	execution := ToolExecution{CallID: call.ID, Tool: name}
+
+	s.mu.RLock()
+	tool, ok := s.tools[name]
+	reason := "unknown tool"
+	if ok {
+		reason = s.permitted(agent, name, tool)
+	}
+	guard := s.guard
+	approver, approvalTimeout := s.approver, s.approvalTimeout
+	s.mu.RUnlock()
+
+	if reason != "" {
+		return execution.failed(&ToolPolicyError{Agent: agent, Tool: name, Reason: reason})
+	}
+
+	arguments := call.Function.Arguments
+	if tool.RequiresApproval {
+		request := ApprovalRequest{Agent: agent, CallID: call.ID, Tool: name, Arguments: arguments}
+		var err error
+		if arguments, err = s.approve(ctx, approver, approvalTimeout, request); err != nil {
+			return execution.failed(err)
+		}
+	}
+
+	output, err := tool.Handler(ctx, arguments)
+	if err != nil {
+		return execution.failed(fmt.Errorf("tool %s failed: %w", name, err))
+	}
+	if output, err = guard.Prepare(ctx, name, output); err != nil {
+		return execution.failed(err)
+	}
+	execution.Content = output
+	return execution
+}
+
+// permitted returns why agent may not call tool ("" = permitted, s.mu held)
+func (s *ToolSandbox) permitted(agent, name string, tool RegisteredTool) string {
+	policy, ok := s.policies[agent]
+	if !ok {
+		return "agent has no tool policy"
+	}
+	if slices.Contains(policy.Tools, name) {
+		return ""
+	}
+	needed := tool.Permissions
+	if len(needed) == 0 {
+		needed = []ToolPermission{ToolPermissionReadOnly}
+	}
+	for _, permission := range needed {
+		if !slices.Contains(policy.Permissions, permission) {
+			return fmt.Sprintf("requires %s permission", permission)
+		}
+	}
+	return ""
+}
+
+// failed returns execution reporting err to the model
+func (e ToolExecution) failed(err error) ToolExecution {
+	e.Content = "Error: " + err.Error()
+	e.Err = err
+	return e
+}
@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newTestSandbox(executed *[]string) *ToolSandbox {
+	handler := func(name string) ToolHandler {
+		return func(ctx context.Context, arguments string) (string, error) {
+			*executed = append(*executed, name)
+			return name + " ok " + arguments, nil
+		}
+	}
+	tool := func(name string, permissions ...ToolPermission) RegisteredTool {
+		return RegisteredTool{
+			Definition:  Tool{Type: "function", Function: FunctionDef{Name: name}},
+			Permissions: permissions,
+			Handler:     handler(name),
+		}
+	}
+	return NewToolSandbox().
+		Register(tool("get_position")).
+		Register(tool("get_klines", ToolPermissionNetwork)).
+		Register(tool("place_order", ToolPermissionNetwork, ToolPermissionTrade)).
+		Allow("analyst", ToolPolicy{Permissions: []ToolPermission{ToolPermissionReadOnly, ToolPermissionNetwork}}).
+		Allow("executor", ToolPolicy{Tools: []string{"place_order"}})
+}
+
+func TestToolSandbox_Policies(t *testing.T) {
+	var executed []string
+	sandbox := newTestSandbox(&executed)
+	ctx := context.Background()
+	call := func(agent, tool string) ToolExecution {
+		return sandbox.Execute(ctx, agent, ToolCall{ID: "call-1", Function: ToolCallFunction{Name: tool, Arguments: `{}`}})
+	}
+
+	if result := call("analyst", "get_klines"); result.Err != nil || result.Content != "get_klines ok {}" || result.CallID != "call-1" {
+		t.Errorf("permitted call = %+v", result)
+	}
+	result := call("analyst", "place_order")
+	var policyErr *ToolPolicyError
+	if !errors.Is(result.Err, ErrToolNotPermitted) || !errors.As(result.Err, &policyErr) || !strings.Contains(policyErr.Reason, string(ToolPermissionTrade)) {
+		t.Errorf("expected trade permission error, got %+v", result)
+	}
+	if msg := result.Message(); msg.Role != "tool" || !strings.HasPrefix(msg.Content, "Error: tool place_order is not permitted") {
+		t.Errorf("unexpected policy message %+v", msg)
+	}
+	if result := call("executor", "place_order"); result.Err != nil {
+		t.Errorf("explicitly allowed call failed: %v", result.Err)
+	}
+	if result := call("executor", "get_position"); !errors.Is(result.Err, ErrToolNotPermitted) {
+		t.Errorf("executor may only place orders, got %+v", result)
+	}
+	if result := call("stranger", "get_position"); !errors.Is(result.Err, ErrToolNotPermitted) {
+		t.Errorf("agents without policy must be denied, got %+v", result)
+	}
+	if len(executed) != 2 || executed[0] != "get_klines" || executed[1] != "place_order" {
+		t.Errorf("unexpected executions %v", executed)
+	}
+}
+
+func TestToolSandbox_ToolsAndGuard(t *testing.T) {
+	var executed []string
+	sandbox := newTestSandbox(&executed).WithResultGuard(NewToolResultGuard(nil).WithTool("get_klines", ToolOutputSpec{MaxBytes: 5, Overflow: ToolOverflowReject}))
+
+	var names []string
+	for _, tool := range sandbox.Tools("analyst") {
+		names = append(names, tool.Function.Name)
+	}
+	if strings.Join(names, ",") != "get_klines,get_position" {
+		t.Errorf("analyst tools = %v", names)
+	}
+
+	result := sandbox.Execute(context.Background(), "analyst", ToolCall{Function: ToolCallFunction{Name: "get_klines"}})
+	if !errors.Is(result.Err, ErrInvalidToolResult) {
+		t.Errorf("expected guarded result to be rejected, got %+v", result)
+	}
+}
+
+func TestToolSandbox_AgentLoopRoundTrip(t *testing.T) {
+	responses := []string{
+		`{"choices":[{"message":{"content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_position","arguments":"{\"symbol\":\"BTCUSDT\"}"}}]},"finish_reason":"tool_calls"}]}`,
+		`{"choices":[{"message":{"content":"flat"},"finish_reason":"stop"}]}`,
+	}
+	var bodies []map[string]any
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		var body map[string]any
+		json.Unmarshal(data, &body)
+		bodies = append(bodies, body)
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header),
+			Body: io.NopCloser(strings.NewReader(responses[len(bodies)-1]))}, nil
+	}
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL("https://api.example.com/v1"), WithAPIKey("k"),
+		WithModel("m"), WithHTTPClient(mockHTTP.ToHTTPClient()), WithLogger(NewNoopLogger())).(*Client)
+
+	var executed []string
+	sandbox := newTestSandbox(&executed)
+	messages := []Message{NewUserMessage("What is my BTC position?")}
+	response, err := client.Call(context.Background(), &Request{Messages: messages, Tools: sandbox.Tools("analyst")})
+	if err != nil || len(response.ToolCalls) != 1 {
+		t.Fatalf("expected one tool call, got %+v (%v)", response, err)
+	}
+	messages = append(messages, NewToolCallsMessage(response.Text, response.ToolCalls))
+	for _, call := range response.ToolCalls {
+		messages = append(messages, sandbox.Execute(context.Background(), "analyst", call).Message())
+	}
+	if response, err = client.Call(context.Background(), &Request{Messages: messages}); err != nil || response.Text != "flat" {
+		t.Fatalf("second call: %+v (%v)", response, err)
+	}
+
+	sent := bodies[1]["messages"].([]any)
+	assistant, tool := sent[1].(map[string]any), sent[2].(map[string]any)
+	calls, _ := assistant["tool_calls"].([]any)
+	if len(calls) != 1 {
+		t.Fatalf("assistant message lost its tool calls: %v", assistant)
+	}
+	call := calls[0].(map[string]any)
+	function := call["function"].(map[string]any)
+	if call["id"] != "call_1" || call["type"] != "function" || function["name"] != "get_position" || function["arguments"] != `{"symbol":"BTCUSDT"}` {
+		t.Errorf("unexpected tool call %v", call)
+	}
+	if tool["role"] != "tool" || tool["tool_call_id"] != "call_1" || !strings.HasPrefix(tool["content"].(string), "get_position ok") {
+		t.Errorf("unexpected tool message %v", tool)
+	}
+}
+
+func TestAnthropicMessage_ToolBlocks(t *testing.T) {
+	call := ToolCall{ID: "toolu_1", Type: "function", Function: ToolCallFunction{Name: "get_position", Arguments: `{"symbol":"BTCUSDT"}`}}
+	data, _ := json.Marshal([]map[string]any{
+		anthropicMessage(NewToolCallsMessage("checking", []ToolCall{call})),
+		anthropicMessage(NewToolMessage("toolu_1", "flat")),
+	})
+	want := `[{"content":[{"text":"checking","type":"text"},{"id":"toolu_1","input":{"symbol":"BTCUSDT"},"name":"get_position","type":"tool_use"}],"role":"assistant"},` +
+		`{"content":[{"content":"flat","tool_use_id":"toolu_1","type":"tool_result"}],"role":"user"}]`
+	if string(data) != want {
+		t.Errorf("unexpected Anthropic messages\n got %s\nwant %s", data, want)
+	}
+}
@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Trade actions of a TradeDecision (same vocabulary as the kernel decision engine)
+const (
+	TradeOpenLong   = "open_long"
+	TradeOpenShort  = "open_short"
+	TradeCloseLong  = "close_long"
+	TradeCloseShort = "close_short"
+	TradeHold       = "hold"
+	TradeWait       = "wait"
+)
+
+// Risk flags a TradeDecision may carry
+const (
+	RiskHighVolatility     = "high_volatility"
+	RiskLowLiquidity       = "low_liquidity"
+	RiskNewsEvent          = "news_event"
+	RiskCounterTrend       = "counter_trend"
+	RiskHighLeverage       = "high_leverage"
+	RiskCorrelatedExposure = "correlated_exposure"
+)
+
+var (
+	tradeActions   = []string{TradeOpenLong, TradeOpenShort, TradeCloseLong, TradeCloseShort, TradeHold, TradeWait}
+	tradeRiskFlags = []string{RiskHighVolatility, RiskLowLiquidity, RiskNewsEvent, RiskCounterTrend, RiskHighLeverage, RiskCorrelatedExposure}
+)
+
+// ErrInvalidTradeDecision is matched (errors.Is) by every TradeDecision validation failure
+var ErrInvalidTradeDecision = errors.New("invalid trade decision")
+
+// TradeDecision structured trading decision returned by CallTradeDecision
+type TradeDecision struct {
+	Action     string   `json:"action"`
+	Symbol     string   `json:"symbol"`
+	SizeUSD    float64  `json:"size_usd"` // Position size (required > 0 for open actions)
+	Leverage   int      `json:"leverage,omitempty"`
+	StopLoss   float64  `json:"stop_loss,omitempty"`
+	TakeProfit float64  `json:"take_profit,omitempty"`
+	Confidence int      `json:"confidence"` // 0-100
+	Rationale  string   `json:"rationale"`
+	RiskFlags  []string `json:"risk_flags"`
+}
+
+// TradeDecisionSchema JSON Schema enforced on CallTradeDecision output
+var TradeDecisionSchema = map[string]any{
+	"type":                 "object",
+	"required":             []string{"action", "symbol", "size_usd", "confidence", "rationale", "risk_flags"},
+	"additionalProperties": false,
+	"properties": map[string]any{
+		"action":      map[string]any{"type": "string", "enum": tradeActions},
+		"symbol":      map[string]any{"type": "string"},
+		"size_usd":    map[string]any{"type": "number"},
+		"leverage":    map[string]any{"type": "integer"},
+		"stop_loss":   map[string]any{"type": "number"},
+		"take_profit": map[string]any{"type": "number"},
+		"confidence":  map[string]any{"type": "integer"},
+		"rationale":   map[string]any{"type": "string"},
+		"risk_flags":  map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": tradeRiskFlags}},
+	},
+}
+
+// tradeDecisionPrompt system prompt describing the decision format
+var tradeDecisionPrompt = `You are a disciplined crypto trading analyst. Decide on one trade for the market context provided.
+Answer with a single JSON object:
+- action: one of ` + strings.Join(tradeActions, ", ") + `
+- symbol: trading pair, e.g. BTCUSDT
+- size_usd: position size in USD (0 unless opening a position)
+- leverage, stop_loss, take_profit: optional, for opening actions
+- confidence: integer 0-100
+- rationale: the reasoning behind the decision (required, never empty)
+- risk_flags: zero or more of ` + strings.Join(tradeRiskFlags, ", ")
+
+// Validate checks enum fields, ranges and that a rationale is given
+func (d *TradeDecision) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidTradeDecision, fmt.Sprintf(format, args...))
+	}
+	if !slices.Contains(tradeActions, d.Action) {
+		return invalid("unknown action %q", d.Action)
+	}
+	if strings.TrimSpace(d.Symbol) == "" {
+		return invalid("symbol is missing")
+	}
+	if strings.TrimSpace(d.Rationale) == "" {
+		return invalid("rationale is missing")
+	}
+	if d.Confidence < 0 || d.Confidence > 100 {
+		return invalid("confidence %d out of range 0-100", d.Confidence)
+	}
+	if d.SizeUSD < 0 || d.Leverage < 0 {
+		return invalid("size and leverage must not be negative")
+	}
+	if (d.Action == TradeOpenLong || d.Action == TradeOpenShort) && d.SizeUSD == 0 {
+		return invalid("%s requires size_usd", d.Action)
+	}
+	for _, flag := range d.RiskFlags {
+		if !slices.Contains(tradeRiskFlags, flag) {
+			return invalid("unknown risk flag %q", flag)
+		}
+	}
+	return nil
+}
+
+// ParseTradeDecision parses and validates a TradeDecision from model output
+//
+// Code fences and text around the JSON object are tolerated, so it also works on
+// answers of calls made without JSON mode.
+func ParseTradeDecision(text string) (*TradeDecision, error) {
+	var decision TradeDecision
+	if err := json.Unmarshal([]byte(extractJSONObject(text)), &decision); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTradeDecision, err)
+	}
+	decision.Action = strings.ToLower(strings.TrimSpace(decision.Action))
+	decision.Symbol = strings.ToUpper(strings.TrimSpace(decision.Symbol))
+	if err := decision.Validate(); err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// CallTradeDecision asks for a trade decision on marketContext with schema-enforced JSON output
+//
+// Usage example:
+//   decision, err := client.CallTradeDecision(ctx, marketSnapshot)
+//   if errors.Is(err, mcp.ErrInvalidTradeDecision) {
+//       // model answered, but not with a usable decision
+//   }
+func (client *Client) CallTradeDecision(ctx context.Context, marketContext string) (*TradeDecision, error) {
+	req, err := NewRequestBuilder().
+		WithSystemPrompt(tradeDecisionPrompt).
+		WithUserPrompt(marketContext).
+		WithJSONResponse(TradeDecisionSchema).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTradeDecision(response.Text)
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseTradeDecision(t *testing.T) {
+	decision, err := ParseTradeDecision("```json\n" + `{"action":"Open_Long","symbol":"btcusdt","size_usd":500,"leverage":3,"confidence":72,
+		"rationale":"Breakout above range high on rising OI","risk_flags":["high_volatility"]}` + "\n```")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Action != TradeOpenLong || decision.Symbol != "BTCUSDT" || decision.SizeUSD != 500 {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+
+	tests := []struct {
+		name, text, want string
+	}{
+		{"missing rationale", `{"action":"hold","symbol":"BTCUSDT","size_usd":0,"confidence":50,"rationale":"  ","risk_flags":[]}`, "rationale"},
+		{"unknown action", `{"action":"yolo","symbol":"BTCUSDT","size_usd":0,"confidence":50,"rationale":"x","risk_flags":[]}`, "action"},
+		{"open without size", `{"action":"open_short","symbol":"ETHUSDT","size_usd":0,"confidence":50,"rationale":"x","risk_flags":[]}`, "size_usd"},
+		{"unknown risk flag", `{"action":"wait","symbol":"ETHUSDT","size_usd":0,"confidence":50,"rationale":"x","risk_flags":["moon"]}`, "risk flag"},
+		{"confidence range", `{"action":"wait","symbol":"ETHUSDT","size_usd":0,"confidence":150,"rationale":"x","risk_flags":[]}`, "confidence"},
+		{"not json", `I would buy`, "invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTradeDecision(tt.text)
+			if !errors.Is(err, ErrInvalidTradeDecision) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected ErrInvalidTradeDecision mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCallTradeDecision_EnforcesSchema(t *testing.T) {
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"{\"action\":\"hold\",\"symbol\":\"BTCUSDT\",\"size_usd\":0,\"confidence\":40,\"rationale\":\"Range-bound, no edge\",\"risk_flags\":[]}"}}]}`
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	decision, err := client.CallTradeDecision(context.Background(), "BTC 67k, funding flat")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Action != TradeHold || decision.Rationale == "" {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+	format, _ := bodies[0]["response_format"].(map[string]any)
+	if format["type"] != "json_schema" {
+		t.Errorf("expected json_schema response_format, got %v", bodies[0]["response_format"])
+	}
+}
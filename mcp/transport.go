@@ -0,0 +1,210 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TransportConfig connection pool and protocol settings of the underlying transport
+//
+// Clients with equal TransportConfig share one *http.Transport (and its connection
+// pool), so many provider clients don't each open their own set of connections.
+type TransportConfig struct {
+	MaxIdleConns          int           // Max idle connections across all hosts
+	MaxIdleConnsPerHost   int           // Max idle connections kept per host
+	MaxConnsPerHost       int           // Max connections per host including active (0 = unlimited)
+	DialTimeout           time.Duration // Max time to establish TCP connection
+	ResponseHeaderTimeout time.Duration // Max wait for response headers after sending request (0 = unlimited)
+	IdleConnTimeout       time.Duration // How long idle connections are kept
+	TLSHandshakeTimeout   time.Duration // Max time for TLS handshake
+	ForceHTTP2            bool          // Attempt HTTP/2 (false = HTTP/1.1 only)
+	DisableKeepAlives     bool          // Close connection after each request
+}
+
+// DefaultTransportConfig returns pool settings sized for concurrent AI/embedding traffic
+//
+// net/http's default of 2 idle connections per host makes high-QPS workloads open and
+// close connections constantly, exhausting ephemeral ports.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        256,
+		MaxIdleConnsPerHost: 64,
+		DialTimeout:         30 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		ForceHTTP2:          true,
+	}
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = make(map[TransportConfig]*http.Transport)
+)
+
+// sharedTransport returns the transport for tc, creating it on first use
+func sharedTransport(tc TransportConfig) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if t, ok := transports[tc]; ok {
+		return t
+	}
+
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   tc.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          tc.MaxIdleConns,
+		MaxIdleConnsPerHost:   tc.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       tc.MaxConnsPerHost,
+		IdleConnTimeout:       tc.IdleConnTimeout,
+		TLSHandshakeTimeout:   tc.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: tc.ResponseHeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     tc.ForceHTTP2,
+		DisableKeepAlives:     tc.DisableKeepAlives,
+	}
+	if !tc.ForceHTTP2 {
+		// A non-nil empty map disables HTTP/2 upgrade over TLS
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	transports[tc] = t
+	return t
+}
+
+// WithMaxIdleConnsPerHost sets max idle connections kept per host
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithMaxIdleConnsPerHost(128))
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *Config) {
+		c.Transport.MaxIdleConnsPerHost = n
+		if c.Transport.MaxIdleConns < n {
+			c.Transport.MaxIdleConns = n
+		}
+	}
+}
+
+// WithIdleConnTimeout sets how long idle connections are kept in the pool
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return func(c *Config) {
+		c.Transport.IdleConnTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout sets max time for TLS handshake
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientOption {
+	return func(c *Config) {
+		c.Transport.TLSHandshakeTimeout = timeout
+	}
+}
+
+// WithForceHTTP2 enables (default) or disables HTTP/2
+//
+// Some proxies and self-hosted gateways misbehave with HTTP/2; pass false to use HTTP/1.1.
+func WithForceHTTP2(enabled bool) ClientOption {
+	return func(c *Config) {
+		c.Transport.ForceHTTP2 = enabled
+	}
+}
+
+// WithKeepAlives enables (default) or disables connection reuse
+func WithKeepAlives(enabled bool) ClientOption {
+	return func(c *Config) {
+		c.Transport.DisableKeepAlives = !enabled
+	}
+}
+
+// newHTTPClient builds the HTTP client used by Client from the configuration
+//
+// The default HTTPClient uses the shared pooled transport for cfg.Transport; an
+// injected HTTPClient keeps its own transport. When no transport-level feature is
+// enabled the configured HTTPClient is returned unchanged (so injected mock clients
+// keep their identity). Otherwise a shallow copy is made whose Transport is wrapped
+// by the enabled features, leaving the caller's http.Client untouched.
+// The returned transport is non-nil when one was created for this client alone (TLS
+// customization); the shared pool and injected transports are never returned.
+func newHTTPClient(cfg *Config) (*http.Client, *http.Transport) {
+	base := cfg.HTTPClient
+	if base == nil {
+		base = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	// The client created by DefaultConfig is ours: give it the shared pooled transport
+	if base == cfg.defaultHTTPClient && base.Transport == nil {
+		base.Transport = sharedTransport(cfg.Transport)
+	}
+
+	transport := base.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	wrapped, changed := customizeTLS(transport, cfg)
+	var owned *http.Transport
+	if changed {
+		owned, _ = wrapped.(*http.Transport)
+	}
+
+	// Provider signatures must cover the exact bytes sent, so they sit below compression
+	if cfg.CloudSigner != nil {
+		wrapped = &cloudSigningTransport{next: wrapped, signer: cfg.CloudSigner, now: time.Now}
+		changed = true
+	}
+	// Compression comes next so every other feature sees plain payloads
+	if cfg.Compression {
+		wrapped = newCompressionTransport(wrapped)
+		changed = true
+	}
+	// Signatures cover the plain body, so gateways (Gateway included) verify after decompressing
+	if cfg.RequestSigner != nil {
+		wrapped = &signingTransport{next: wrapped, signer: cfg.RequestSigner, now: time.Now}
+		changed = true
+	}
+	// OAuth tokens replace the static API key header (recordings and dumps redact it)
+	if cfg.TokenSource != nil {
+		wrapped = &tokenTransport{next: wrapped, source: newCachingTokenSource(cfg.TokenSource), queryParam: cfg.TokenQueryParam}
+		changed = true
+	}
+	// Recorder sits above signing, compression and tokens: cassettes hold plain, unsigned
+	// requests that still match on replay, and replay needs neither credentials nor network
+	if cfg.Recorder != nil {
+		wrapped = cfg.Recorder.wrap(wrapped, cfg.ContentCipher)
+		changed = true
+	}
+	// Injected faults are never recorded but do show up in debug dumps
+	if cfg.FaultInjector != nil {
+		wrapped = cfg.FaultInjector.wrap(wrapped)
+		changed = true
+	}
+	if cfg.DebugDump != nil {
+		wrapped = cfg.DebugDump.wrap(wrapped)
+		changed = true
+	}
+	// Allowlist and residency checks are outermost: refused requests go nowhere, not even to debug dumps
+	if len(cfg.AllowedHosts) > 0 {
+		wrapped = &allowlistTransport{next: wrapped, allowed: cfg.AllowedHosts}
+		changed = true
+	}
+	if len(cfg.DataResidency) > 0 {
+		wrapped = &residencyTransport{next: wrapped, regions: cfg.DataResidency}
+		changed = true
+	}
+
+	// WithTimeouts applies to our copy: an injected client may be shared
+	retimed := cfg.totalTimeout && base.Timeout != cfg.Timeout
+	if !changed && !retimed {
+		return base, nil
+	}
+
+	httpClient := *base
+	httpClient.Transport = wrapped
+	if retimed {
+		httpClient.Timeout = cfg.Timeout
+	}
+	return &httpClient, owned
+}
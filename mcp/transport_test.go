@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// Test Transport / Connection Pool
+// ============================================================
+
+// transportOf returns the *http.Transport used by an AIClient built by NewClient
+func transportOf(t *testing.T, c AIClient) *http.Transport {
+	t.Helper()
+	transport, ok := c.(*Client).httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", c.(*Client).httpClient.Transport)
+	}
+	return transport
+}
+
+func TestTransport_SharedByDefault(t *testing.T) {
+	a := NewClient(WithLogger(NewNoopLogger()))
+	b := NewClient(WithLogger(NewNoopLogger()), WithProvider(ProviderCustom))
+
+	ta, tb := transportOf(t, a), transportOf(t, b)
+	if ta != tb {
+		t.Error("clients with default settings should share one transport")
+	}
+	if ta.MaxIdleConnsPerHost != DefaultTransportConfig().MaxIdleConnsPerHost {
+		t.Errorf("expected MaxIdleConnsPerHost %d, got %d", DefaultTransportConfig().MaxIdleConnsPerHost, ta.MaxIdleConnsPerHost)
+	}
+}
+
+func TestTransport_TuningOptions(t *testing.T) {
+	c := NewClient(
+		WithLogger(NewNoopLogger()),
+		WithMaxIdleConnsPerHost(512),
+		WithIdleConnTimeout(30*time.Second),
+		WithTLSHandshakeTimeout(3*time.Second),
+		WithForceHTTP2(false),
+		WithKeepAlives(false),
+	)
+
+	transport := transportOf(t, c)
+	if transport == transportOf(t, NewClient(WithLogger(NewNoopLogger()))) {
+		t.Error("tuned client should not share the default transport")
+	}
+	if transport.MaxIdleConnsPerHost != 512 || transport.MaxIdleConns < 512 {
+		t.Errorf("unexpected pool sizes: per host %d, total %d", transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+	}
+	if transport.IdleConnTimeout != 30*time.Second || transport.TLSHandshakeTimeout != 3*time.Second {
+		t.Error("timeouts not applied")
+	}
+	if transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Error("HTTP/2 should be disabled")
+	}
+	if !transport.DisableKeepAlives {
+		t.Error("keep-alives should be disabled")
+	}
+}
+
+func TestTransport_CustomTransportUntouched(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	httpClient := mockHTTP.ToHTTPClient()
+
+	c := NewClient(WithHTTPClient(httpClient), WithMaxIdleConnsPerHost(512))
+
+	if c.(*Client).httpClient != httpClient {
+		t.Error("injected HTTP client with its own transport should be used as-is")
+	}
+}
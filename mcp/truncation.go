@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Normalized finish reasons
+const (
+	FinishReasonStop          = "stop"           // Natural end or stop sequence
+	FinishReasonLength        = "length"         // Hit max output tokens (answer truncated)
+	FinishReasonToolCalls     = "tool_calls"     // Model requested tool calls
+	FinishReasonContentFilter = "content_filter" // Output withheld by provider filter
+)
+
+// TruncationPolicy what to do when output is cut off by the token limit
+type TruncationPolicy int
+
+const (
+	// TruncationIgnore returns truncated output as-is (Response.FinishReason tells) (default)
+	TruncationIgnore TruncationPolicy = iota
+	// TruncationError fails with *TruncatedOutputError carrying the partial response
+	TruncationError
+	// TruncationContinue re-prompts the model to continue and stitches the segments
+	TruncationContinue
+)
+
+// DefaultContinuePrompt prompt used to ask the model to continue truncated output
+const DefaultContinuePrompt = "Continue exactly where you stopped. Do not repeat anything you already wrote."
+
+// ErrTruncatedOutput is matched (errors.Is) by TruncatedOutputError
+var ErrTruncatedOutput = errors.New("AI output truncated by token limit")
+
+// TruncatedOutputError output was cut off by the token limit
+type TruncatedOutputError struct {
+	Partial *Response // Output received before truncation (stitched if continued)
+}
+
+func (e *TruncatedOutputError) Error() string {
+	return fmt.Sprintf("AI output truncated by token limit after %d characters", len(e.Partial.Text))
+}
+
+func (e *TruncatedOutputError) Is(target error) bool {
+	return target == ErrTruncatedOutput
+}
+
+// Truncated reports whether the output was cut off by the token limit
+func (r *Response) Truncated() bool {
+	return r.FinishReason == FinishReasonLength
+}
+
+// WithTruncationPolicy sets what to do with output cut off by the token limit
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithTruncationPolicy(mcp.TruncationError))
+func WithTruncationPolicy(policy TruncationPolicy) ClientOption {
+	return func(c *Config) {
+		c.TruncationPolicy = policy
+	}
+}
+
+// WithAutoContinue continues truncated output up to maxContinuations times
+//
+// The conversation is resent with the partial answer as assistant message plus a
+// "continue" prompt; segments are stitched (overlap removed). If output is still
+// truncated after maxContinuations, the call fails with *TruncatedOutputError.
+//
+// Usage example:
+//   client := mcp.NewClient(mcp.WithAutoContinue(2))
+func WithAutoContinue(maxContinuations int) ClientOption {
+	return func(c *Config) {
+		c.TruncationPolicy = TruncationContinue
+		c.MaxContinuations = maxContinuations
+	}
+}
+
+// continuationKey context key marking requests that fetch a continuation segment
+type continuationKey struct{}
+
+// isContinuation reports whether ctx belongs to a continuation segment request
+func isContinuation(ctx context.Context) bool {
+	return ctx.Value(continuationKey{}) != nil
+}
+
+// handleTruncation applies the truncation policy to response of requestBody
+func (client *Client) handleTruncation(ctx context.Context, requestBody map[string]any, response *Response) (*Response, error) {
+	if !response.Truncated() || client.config.TruncationPolicy == TruncationIgnore {
+		return response, nil
+	}
+	if client.config.TruncationPolicy == TruncationError {
+		return nil, &TruncatedOutputError{Partial: response}
+	}
+
+	prompt := client.config.ContinuePrompt
+	if prompt == "" {
+		prompt = DefaultContinuePrompt
+	}
+	ctx = context.WithValue(ctx, continuationKey{}, true)
+
+	for i := 0; i < client.config.MaxContinuations && response.Truncated(); i++ {
+		client.logger.Infof("✂️  [%s] Output truncated, requesting continuation (%d/%d)", client.String(), i+1, client.config.MaxContinuations)
+
+		segment, err := client.send(ctx, continuationBody(requestBody, response.Text, prompt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to continue truncated output: %w", err)
+		}
+		response = stitchResponses(response, segment)
+	}
+
+	if response.Truncated() {
+		return nil, &TruncatedOutputError{Partial: response}
+	}
+	return response, nil
+}
+
+// continuationBody copies requestBody appending the partial answer and a continue prompt
+//
+// Works for both OpenAI-compatible and Anthropic bodies, which share the
+// role/content "messages" layout.
+func continuationBody(requestBody map[string]any, partial, prompt string) map[string]any {
+	body := make(map[string]any, len(requestBody))
+	for k, v := range requestBody {
+		body[k] = v
+	}
+
+	var messages []map[string]any
+	raw, _ := json.Marshal(requestBody["messages"])
+	json.Unmarshal(raw, &messages)
+	messages = append(messages,
+		map[string]any{"role": "assistant", "content": partial},
+		map[string]any{"role": "user", "content": prompt},
+	)
+	body["messages"] = messages
+	return body
+}
+
+// stitchResponses joins continuation segment to response
+//
+// Metadata comes from the last segment, usage covers all of them.
+func stitchResponses(response, segment *Response) *Response {
+	stitched := *segment
+	stitched.Text = stitchSegments(response.Text, segment.Text)
+	stitched.Reasoning = strings.TrimSpace(response.Reasoning + "\n\n" + segment.Reasoning)
+	stitched.Logprobs = append(response.Logprobs, segment.Logprobs...)
+	stitched.ToolCalls = append(response.ToolCalls, segment.ToolCalls...)
+	stitched.Usage.PromptTokens += response.Usage.PromptTokens
+	stitched.Usage.CompletionTokens += response.Usage.CompletionTokens
+	stitched.Usage.TotalTokens += response.Usage.TotalTokens
+	stitched.Usage.CacheReadTokens += response.Usage.CacheReadTokens
+	stitched.Usage.CacheWriteTokens += response.Usage.CacheWriteTokens
+	return &stitched
+}
+
+// stitchSegments joins continuation segment to text, dropping repeated overlap
+func stitchSegments(text, segment string) string {
+	const minOverlap, maxOverlap = 8, 200
+
+	limit := min(len(text), len(segment), maxOverlap)
+	for k := limit; k >= minOverlap; k-- {
+		if strings.HasSuffix(text, segment[:k]) {
+			return text + segment[k:]
+		}
+	}
+	return text + segment
+}
+
+// parseFinishReason extracts normalized finish reason from response body
+//
+// Supports OpenAI-compatible finish_reason, Anthropic stop_reason, Ollama done_reason,
+// Cohere and ERNIE top-level finish_reason, TGI details.finish_reason and Tencent Cloud
+// Response.Choices[].FinishReason.
+func parseFinishReason(body []byte) string {
+	var response struct {
+		Choices []struct {
+			FinishReason        string `json:"finish_reason"`
+			HunyuanFinishReason string `json:"FinishReason"`
+		} `json:"choices"`
+		StopReason   string `json:"stop_reason"`
+		DoneReason   string `json:"done_reason"`
+		FinishReason string `json:"finish_reason"`
+		Details      struct {
+			FinishReason string `json:"finish_reason"`
+		} `json:"details"` // TGI generate
+		Response json.RawMessage `json:"Response"` // Tencent Cloud API 3.0 envelope
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	if len(response.Response) > 0 && response.Response[0] == '{' {
+		return parseFinishReason(response.Response)
+	}
+
+	reason := response.StopReason
+	if len(response.Choices) > 0 {
+		reason = response.Choices[0].FinishReason + response.Choices[0].HunyuanFinishReason
+	}
+	if reason == "" {
+		reason = response.DoneReason
+	}
+	if reason == "" {
+		reason = response.FinishReason
+	}
+	if reason == "" {
+		reason = response.Details.FinishReason
+	}
+
+	switch strings.ToLower(reason) {
+	case "stop", "end_turn", "stop_sequence", "eos", "eos_token", "complete", "normal":
+		return FinishReasonStop
+	case "length", "max_tokens", "model_length":
+		return FinishReasonLength
+	case "tool_calls", "tool_use", "function_call", "tool_call":
+		return FinishReasonToolCalls
+	case "content_filter", "refusal", "safety":
+		return FinishReasonContentFilter
+	}
+	return strings.ToLower(reason)
+}
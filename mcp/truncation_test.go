@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// ============================================================
+// Test Finish Reason / Truncation Handling
+// ============================================================
+
+// sequenceClient returns HTTP client answering with responses in order (last one repeats)
+func sequenceClient(responses []string, bodies *[]map[string]any) *http.Client {
+	mockHTTP := NewMockHTTPClient()
+	i := 0
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		var body map[string]any
+		json.Unmarshal(data, &body)
+		*bodies = append(*bodies, body)
+
+		response := responses[min(i, len(responses)-1)]
+		i++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(response)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	return mockHTTP.ToHTTPClient()
+}
+
+func openAIAnswer(content, finishReason string) string {
+	data, _ := json.Marshal(map[string]any{
+		"choices": []map[string]any{{
+			"message":       map[string]any{"content": content},
+			"finish_reason": finishReason,
+		}},
+	})
+	return string(data)
+}
+
+func TestParseFinishReason(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"choices":[{"finish_reason":"stop"}]}`, FinishReasonStop},
+		{`{"choices":[{"finish_reason":"length"}]}`, FinishReasonLength},
+		{`{"stop_reason":"max_tokens"}`, FinishReasonLength},
+		{`{"stop_reason":"end_turn"}`, FinishReasonStop},
+		{`{"stop_reason":"tool_use"}`, FinishReasonToolCalls},
+		{`{"done":true,"done_reason":"length"}`, FinishReasonLength},
+		{`{}`, ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseFinishReason([]byte(tt.body)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestTruncation_IgnoredByDefault(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(sequenceClient([]string{openAIAnswer("partial", "length")}, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*Client)
+
+	response, err := client.Call(context.Background(), NewRequestBuilder().WithUserPrompt("analyze").MustBuild())
+	if err != nil {
+		t.Fatalf("default policy should not error: %v", err)
+	}
+	if !response.Truncated() || response.FinishReason != FinishReasonLength {
+		t.Errorf("response should report truncation, got %+v", response)
+	}
+}
+
+func TestTruncation_ErrorPolicy(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(sequenceClient([]string{openAIAnswer("partial", "length")}, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithTruncationPolicy(TruncationError),
+	)
+
+	_, err := client.CallWithMessages("system", "analyze")
+	if !errors.Is(err, ErrTruncatedOutput) {
+		t.Fatalf("expected ErrTruncatedOutput, got %v", err)
+	}
+	var truncated *TruncatedOutputError
+	if !errors.As(err, &truncated) || truncated.Partial.Text != "partial" {
+		t.Errorf("error should carry partial output, got %v", err)
+	}
+}
+
+func TestTruncation_AutoContinueStitches(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(sequenceClient([]string{
+			openAIAnswer("BTC shows a bullish divergence on the", "length"),
+			openAIAnswer("divergence on the 4h chart, buy.", "stop"),
+		}, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithAutoContinue(2),
+	)
+
+	result, err := client.CallWithMessages("system", "analyze")
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != "BTC shows a bullish divergence on the 4h chart, buy." {
+		t.Errorf("segments should be stitched without overlap, got %q", result)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	messages, _ := bodies[1]["messages"].([]any)
+	if len(messages) != 4 {
+		t.Fatalf("continuation should append assistant + user messages, got %d messages", len(messages))
+	}
+	assistant, _ := messages[2].(map[string]any)
+	user, _ := messages[3].(map[string]any)
+	if assistant["role"] != "assistant" || assistant["content"] != "BTC shows a bullish divergence on the" {
+		t.Errorf("unexpected assistant message %v", assistant)
+	}
+	if user["content"] != DefaultContinuePrompt {
+		t.Errorf("unexpected continue prompt %v", user)
+	}
+}
+
+func TestTruncation_AutoContinueExhausted(t *testing.T) {
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(sequenceClient([]string{openAIAnswer("more text", "length")}, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithAutoContinue(2),
+	)
+
+	_, err := client.CallWithMessages("system", "analyze")
+	if !errors.Is(err, ErrTruncatedOutput) {
+		t.Errorf("expected ErrTruncatedOutput after continuations exhausted, got %v", err)
+	}
+	if len(bodies) != 3 {
+		t.Errorf("expected 1 request + 2 continuations, got %d", len(bodies))
+	}
+}
+
+func TestStitchSegments(t *testing.T) {
+	if got := stitchSegments("abc", "def"); got != "abcdef" {
+		t.Errorf("no overlap: got %q", got)
+	}
+	if got := stitchSegments("the quick brown fox", "brown fox jumps"); got != "the quick brown fox jumps" {
+		t.Errorf("overlap: got %q", got)
+	}
+}
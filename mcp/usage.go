@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"encoding/json"
+	"maps"
+	"sync"
+)
+
+// ModelPrice price of a model in USD per million tokens
+type ModelPrice struct {
+	InputPerMillion       float64
+	OutputPerMillion      float64
+	CachedInputPerMillion float64 // Price of cache reads (0 = same as input)
+	CacheWritePerMillion  float64 // Price of cache writes (0 = same as input)
+}
+
+var (
+	priceMu    sync.RWMutex
+	priceTable = builtinModelPrices()
+)
+
+// builtinModelPrices prices known without registration (aggregator presets)
+func builtinModelPrices() map[string]ModelPrice {
+	table := map[string]ModelPrice{}
+	maps.Copy(table, siliconFlowPrices)
+	maps.Copy(table, novitaPrices)
+	return table
+}
+
+// RegisterModelPrice registers price of a model (used for cost accounting), replacing any built-in price
+//
+// Usage example:
+//   mcp.RegisterModelPrice("deepseek-chat", mcp.ModelPrice{InputPerMillion: 0.27, OutputPerMillion: 1.10})
+func RegisterModelPrice(model string, price ModelPrice) {
+	priceMu.Lock()
+	defer priceMu.Unlock()
+	priceTable[model] = price
+}
+
+// LookupModelPrice returns registered price of a model
+func LookupModelPrice(model string) (ModelPrice, bool) {
+	priceMu.RLock()
+	defer priceMu.RUnlock()
+	price, ok := priceTable[model]
+	return price, ok
+}
+
+// EstimateCost returns USD cost of token usage (0 when model price is unknown)
+//
+// Cached input tokens are charged at the cache read/write prices when registered.
+func EstimateCost(usage TokenUsage) float64 {
+	price, ok := LookupModelPrice(usage.Model)
+	if !ok {
+		return 0
+	}
+	uncached := usage.PromptTokens - usage.CacheReadTokens - usage.CacheWriteTokens
+	if uncached < 0 {
+		uncached = 0
+	}
+	return float64(uncached)/1e6*price.InputPerMillion +
+		float64(usage.CacheReadTokens)/1e6*price.cacheReadPrice() +
+		float64(usage.CacheWriteTokens)/1e6*price.cacheWritePrice() +
+		float64(usage.CompletionTokens)/1e6*price.OutputPerMillion
+}
+
+// CacheSavings returns USD saved by prompt caching compared to uncached input
+//
+// Negative when cache writes (Anthropic charges a premium) cost more than reads saved.
+//
+// Usage example:
+//   mcp.TokenUsageCallback = func(usage mcp.TokenUsage) {
+//       log.Printf("cache hit %d tokens, saved $%.4f", usage.CacheReadTokens, mcp.CacheSavings(usage))
+//   }
+func CacheSavings(usage TokenUsage) float64 {
+	price, ok := LookupModelPrice(usage.Model)
+	if !ok {
+		return 0
+	}
+	return float64(usage.CacheReadTokens)/1e6*(price.InputPerMillion-price.cacheReadPrice()) -
+		float64(usage.CacheWriteTokens)/1e6*(price.cacheWritePrice()-price.InputPerMillion)
+}
+
+func (p ModelPrice) cacheReadPrice() float64 {
+	if p.CachedInputPerMillion > 0 {
+		return p.CachedInputPerMillion
+	}
+	return p.InputPerMillion
+}
+
+func (p ModelPrice) cacheWritePrice() float64 {
+	if p.CacheWritePerMillion > 0 {
+		return p.CacheWritePerMillion
+	}
+	return p.InputPerMillion
+}
+
+// parseUsage extracts token usage from a raw response body
+//
+// Understands OpenAI-compatible (prompt_tokens/completion_tokens), Anthropic
+// (input_tokens/output_tokens) and Ollama native (prompt_eval_count/eval_count) shapes,
+// plus prompt cache counters of OpenAI (prompt_tokens_details.cached_tokens), DeepSeek
+// (prompt_cache_hit_tokens) and Anthropic (cache_read/cache_creation_input_tokens).
+// PromptTokens always includes cached tokens.
+func parseUsage(body []byte) TokenUsage {
+	var response struct {
+		Usage struct {
+			PromptTokens        int `json:"prompt_tokens"`
+			CompletionTokens    int `json:"completion_tokens"`
+			TotalTokens         int `json:"total_tokens"`
+			PromptTokensDetails struct {
+				CachedTokens int `json:"cached_tokens"`
+			} `json:"prompt_tokens_details"`
+			PromptCacheHitTokens     int `json:"prompt_cache_hit_tokens"`
+			InputTokens              int `json:"input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			Tokens                   struct {
+				InputTokens  float64 `json:"input_tokens"`
+				OutputTokens float64 `json:"output_tokens"`
+			} `json:"tokens"` // Cohere v2
+			HunyuanPromptTokens     int `json:"PromptTokens"` // Tencent Cloud API 3.0
+			HunyuanCompletionTokens int `json:"CompletionTokens"`
+			HunyuanTotalTokens      int `json:"TotalTokens"`
+		} `json:"usage"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+		Details         struct {
+			GeneratedTokens int `json:"generated_tokens"`
+		} `json:"details"` // TGI generate (prompt tokens are not reported)
+		Response json.RawMessage `json:"Response"` // Tencent Cloud API 3.0 envelope
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return TokenUsage{}
+	}
+	if len(response.Response) > 0 && response.Response[0] == '{' {
+		return parseUsage(response.Response)
+	}
+
+	usage := TokenUsage{
+		PromptTokens:     response.Usage.PromptTokens,
+		CompletionTokens: response.Usage.CompletionTokens,
+		TotalTokens:      response.Usage.TotalTokens,
+		CacheReadTokens:  response.Usage.PromptTokensDetails.CachedTokens,
+	}
+	if usage.CacheReadTokens == 0 {
+		usage.CacheReadTokens = response.Usage.PromptCacheHitTokens
+	}
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 {
+		// Anthropic input_tokens excludes cache reads and writes
+		usage.CacheReadTokens = response.Usage.CacheReadInputTokens
+		usage.CacheWriteTokens = response.Usage.CacheCreationInputTokens
+		usage.PromptTokens = response.Usage.InputTokens + usage.CacheReadTokens + usage.CacheWriteTokens
+		usage.CompletionTokens = response.Usage.OutputTokens
+	}
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 {
+		usage.PromptTokens = int(response.Usage.Tokens.InputTokens)
+		usage.CompletionTokens = int(response.Usage.Tokens.OutputTokens)
+	}
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 {
+		usage.PromptTokens = response.PromptEvalCount
+		usage.CompletionTokens = response.EvalCount
+	}
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 && usage.TotalTokens == 0 {
+		usage.PromptTokens = response.Usage.HunyuanPromptTokens
+		usage.CompletionTokens = response.Usage.HunyuanCompletionTokens
+		usage.TotalTokens = response.Usage.HunyuanTotalTokens
+	}
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 {
+		usage.CompletionTokens = response.Details.GeneratedTokens
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
+}
@@ -0,0 +1,616 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Usage export defaults
+const (
+	DefaultUsageExportInterval = time.Minute
+	DefaultUsageRetention      = 31 * 24 * time.Hour // Aggregates kept for Report
+)
+
+// UsageRecord accounting row of one AI call
+type UsageRecord struct {
+	Timestamp        time.Time
+	RequestID        string
+	Tenant           string
+	Provider         string
+	Model            string
+	Outcome          string
+	PromptTokens     int
+	CompletionTokens int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	Cost             float64
+	Latency          time.Duration
+	Tags             map[string]string
+}
+
+// UsageEncoder writes usage records in a file format
+//
+// CSVUsageEncoder and ParquetUsageEncoder are built in; other formats plug in through
+// this interface.
+type UsageEncoder interface {
+	Extension() string                                                  // File extension, e.g. ".csv"
+	Encode(w io.Writer, records []UsageRecord, continuation bool) error // continuation: w already holds earlier records
+}
+
+// CSVUsageEncoder writes usage records as CSV with a header row
+type CSVUsageEncoder struct{}
+
+// usageCSVHeader columns written by CSVUsageEncoder
+var usageCSVHeader = []string{"timestamp", "request_id", "tenant", "provider", "model", "outcome",
+	"prompt_tokens", "completion_tokens", "cache_read_tokens", "cache_write_tokens", "cost_usd", "latency_ms", "tags"}
+
+func (CSVUsageEncoder) Extension() string { return ".csv" }
+
+func (CSVUsageEncoder) Encode(w io.Writer, records []UsageRecord, continuation bool) error {
+	writer := csv.NewWriter(w)
+	if !continuation {
+		writer.Write(usageCSVHeader)
+	}
+	for _, r := range records {
+		writer.Write([]string{
+			r.Timestamp.UTC().Format(time.RFC3339Nano), r.RequestID, r.Tenant, r.Provider, r.Model, r.Outcome,
+			strconv.Itoa(r.PromptTokens), strconv.Itoa(r.CompletionTokens), strconv.Itoa(r.CacheReadTokens), strconv.Itoa(r.CacheWriteTokens),
+			strconv.FormatFloat(r.Cost, 'f', -1, 64), strconv.FormatInt(r.Latency.Milliseconds(), 10), usageTags(r),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// usageTags returns tags of r as sorted "k=v" pairs joined by ";"
+func usageTags(r UsageRecord) string {
+	tags := make([]string, 0, len(r.Tags))
+	for k, v := range r.Tags {
+		tags = append(tags, k+"="+v)
+	}
+	sort.Strings(tags)
+	return strings.Join(tags, ";")
+}
+
+// ParquetUsageEncoder writes usage records as a Parquet file
+//
+// Columns match the CSV header; timestamp is a UTC TIMESTAMP_MICROS, token counts and
+// latency_ms are INT64, cost_usd is DOUBLE and the rest are UTF8 strings. Every file
+// holds one uncompressed, plain-encoded row group, which DuckDB, Spark, pandas and
+// BigQuery load directly. Parquet files can't be appended to, so the encoder needs
+// UsageExportConfig.Dir.
+type ParquetUsageEncoder struct{}
+
+// Parquet physical types, converted types and encodings used by ParquetUsageEncoder
+const (
+	parquetInt64           = 2
+	parquetDouble          = 5
+	parquetByteArray       = 6
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+	parquetNoConvertedType = -1
+	parquetEncodingPlain   = 0
+	parquetEncodingRLE     = 3
+	parquetRequired        = 0
+	parquetDataPage        = 0
+	parquetUncompressed    = 0
+)
+
+// parquetColumn one column of a Parquet file with its PLAIN-encoded values
+type parquetColumn struct {
+	name      string
+	physical  int32
+	converted int32
+	values    []byte
+}
+
+func (ParquetUsageEncoder) Extension() string { return ".parquet" }
+
+func (ParquetUsageEncoder) Encode(w io.Writer, records []UsageRecord, continuation bool) error {
+	if continuation {
+		return errors.New("parquet files can't be appended to, export to a directory")
+	}
+	columns := usageParquetColumns(records)
+	rows := int64(len(records))
+
+	file := bytes.NewBufferString("PAR1")
+	offsets := make([]int64, len(columns))
+	sizes := make([]int64, len(columns))
+	for i, column := range columns {
+		header := newThriftWriter()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(column.values)))
+		header.i32(3, int32(len(column.values)))
+		header.beginStruct(5)
+		header.i32(1, int32(rows))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.endStruct()
+		header.endStruct()
+
+		offsets[i] = int64(file.Len())
+		sizes[i] = int64(header.buf.Len() + len(column.values))
+		file.Write(header.buf.Bytes())
+		file.Write(column.values)
+	}
+
+	meta := newThriftWriter()
+	meta.i32(1, 1)
+	meta.list(2, thriftStruct, len(columns)+1)
+	meta.element()
+	meta.binary(4, "usage")
+	meta.i32(5, int32(len(columns)))
+	meta.endStruct()
+	for _, column := range columns {
+		meta.element()
+		meta.i32(1, column.physical)
+		meta.i32(3, parquetRequired)
+		meta.binary(4, column.name)
+		if column.converted != parquetNoConvertedType {
+			meta.i32(6, column.converted)
+		}
+		meta.endStruct()
+	}
+	meta.i64(3, rows)
+	meta.list(4, thriftStruct, 1)
+	meta.element()
+	meta.list(1, thriftStruct, len(columns))
+	var total int64
+	for i, column := range columns {
+		meta.element()
+		meta.i64(2, offsets[i])
+		meta.beginStruct(3)
+		meta.i32(1, column.physical)
+		meta.list(2, thriftI32, 2)
+		meta.listI32(parquetEncodingPlain)
+		meta.listI32(parquetEncodingRLE)
+		meta.list(3, thriftBinary, 1)
+		meta.listString(column.name)
+		meta.i32(4, parquetUncompressed)
+		meta.i64(5, rows)
+		meta.i64(6, sizes[i])
+		meta.i64(7, sizes[i])
+		meta.i64(9, offsets[i])
+		meta.endStruct()
+		meta.endStruct()
+		total += sizes[i]
+	}
+	meta.i64(2, total)
+	meta.i64(3, rows)
+	meta.endStruct()
+	meta.binary(6, "nofx mcp usage exporter")
+	meta.endStruct()
+
+	file.Write(meta.buf.Bytes())
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(meta.buf.Len())))
+	file.WriteString("PAR1")
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// usageParquetColumns returns the columns of records, named like the CSV header
+func usageParquetColumns(records []UsageRecord) []parquetColumn {
+	columns := []parquetColumn{
+		{name: "timestamp", physical: parquetInt64, converted: parquetTimestampMicros},
+		{name: "request_id", physical: parquetByteArray, converted: parquetUTF8},
+		{name: "tenant", physical: parquetByteArray, converted: parquetUTF8},
+		{name: "provider", physical: parquetByteArray, converted: parquetUTF8},
+		{name: "model", physical: parquetByteArray, converted: parquetUTF8},
+		{name: "outcome", physical: parquetByteArray, converted: parquetUTF8},
+		{name: "prompt_tokens", physical: parquetInt64, converted: parquetNoConvertedType},
+		{name: "completion_tokens", physical: parquetInt64, converted: parquetNoConvertedType},
+		{name: "cache_read_tokens", physical: parquetInt64, converted: parquetNoConvertedType},
+		{name: "cache_write_tokens", physical: parquetInt64, converted: parquetNoConvertedType},
+		{name: "cost_usd", physical: parquetDouble, converted: parquetNoConvertedType},
+		{name: "latency_ms", physical: parquetInt64, converted: parquetNoConvertedType},
+		{name: "tags", physical: parquetByteArray, converted: parquetUTF8},
+	}
+	appendInt := func(column int, v int64) {
+		columns[column].values = binary.LittleEndian.AppendUint64(columns[column].values, uint64(v))
+	}
+	appendString := func(column int, v string) {
+		columns[column].values = binary.LittleEndian.AppendUint32(columns[column].values, uint32(len(v)))
+		columns[column].values = append(columns[column].values, v...)
+	}
+	for _, r := range records {
+		appendInt(0, r.Timestamp.UnixMicro())
+		appendString(1, r.RequestID)
+		appendString(2, r.Tenant)
+		appendString(3, r.Provider)
+		appendString(4, r.Model)
+		appendString(5, r.Outcome)
+		appendInt(6, int64(r.PromptTokens))
+		appendInt(7, int64(r.CompletionTokens))
+		appendInt(8, int64(r.CacheReadTokens))
+		appendInt(9, int64(r.CacheWriteTokens))
+		appendInt(10, int64(math.Float64bits(r.Cost)))
+		appendInt(11, r.Latency.Milliseconds())
+		appendString(12, usageTags(r))
+	}
+	return columns
+}
+
+// Thrift compact protocol types used in Parquet metadata
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter minimal Thrift compact protocol encoder for Parquet metadata
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // Last field ID of every open struct
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{last: []int16{0}}
+}
+
+// field writes header of field id (short form when the ID delta fits in 4 bits)
+func (t *thriftWriter) field(id int16, typ byte) {
+	top := len(t.last) - 1
+	if delta := id - t.last[top]; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.last[top] = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, v string) {
+	t.field(id, thriftBinary)
+	t.listString(v)
+}
+
+// list writes header of list field id holding size elements of typ
+func (t *thriftWriter) list(id int16, typ byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | typ)
+		return
+	}
+	t.buf.WriteByte(0xf0 | typ)
+	t.uvarint(uint64(size))
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) listString(v string) {
+	t.uvarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+// beginStruct opens struct field id
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.last = append(t.last, 0)
+}
+
+// element opens a struct list element
+func (t *thriftWriter) element() {
+	t.last = append(t.last, 0)
+}
+
+// endStruct closes the innermost struct (the outermost one ends the message)
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+// varint writes v zigzag-encoded
+func (t *thriftWriter) varint(v int64) {
+	t.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	t.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+// UsageExportConfig usage exporter configuration (Dir or Writer is required)
+type UsageExportConfig struct {
+	Dir       string        // Every flush writes a new file usage-<UTC time><ext> here
+	Writer    io.Writer     // Or: every flush is appended to Writer
+	Encoder   UsageEncoder  // File format (default CSVUsageEncoder; ParquetUsageEncoder needs Dir)
+	Interval  time.Duration // Flush interval (default 1 minute)
+	Retention time.Duration // Aggregates kept for Report (default 31 days)
+	Next      AuditSink     // Records are forwarded here too (optional)
+	OnError   func(error)   // Called when a background flush fails (records are kept for the next one)
+}
+
+// UsageTotals aggregated usage
+type UsageTotals struct {
+	Calls            int
+	Errors           int
+	PromptTokens     int
+	CompletionTokens int
+	CacheReadTokens  int
+	Cost             float64
+}
+
+// UsageReport usage of a period, broken down for cost dashboards
+type UsageReport struct {
+	From, To   time.Time
+	Totals     UsageTotals
+	ByProvider map[string]UsageTotals
+	ByModel    map[string]UsageTotals
+	ByTenant   map[string]UsageTotals // Calls without tenant are under ""
+}
+
+// usageBucketKey hourly aggregation bucket
+type usageBucketKey struct {
+	hour     time.Time
+	provider string
+	model    string
+	tenant   string
+}
+
+// UsageExporter AuditSink exporting per-call usage records on an interval
+//
+// Records are buffered and flushed every Interval (and on Flush/Close). Hourly
+// aggregates are kept for Retention and answer Report.
+type UsageExporter struct {
+	config UsageExportConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending []UsageRecord
+	written bool // Writer already holds records
+	buckets map[usageBucketKey]*UsageTotals
+
+	flushMu sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+// NewUsageExporter creates exporter and starts its flush loop
+//
+// Usage example:
+//   exporter, err := mcp.NewUsageExporter(mcp.UsageExportConfig{Dir: "data/usage", Interval: 5 * time.Minute, Next: auditSink})
+//   client := mcp.NewClient(mcp.WithAuditSink(exporter))
+//   report := exporter.Report(24 * time.Hour)
+//   log.Printf("AI cost today: $%.2f", report.Totals.Cost)
+func NewUsageExporter(config UsageExportConfig) (*UsageExporter, error) {
+	if config.Dir == "" && config.Writer == nil {
+		return nil, errors.New("usage exporter needs Dir or Writer")
+	}
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create usage export dir: %w", err)
+		}
+	}
+	if config.Encoder == nil {
+		config.Encoder = CSVUsageEncoder{}
+	}
+	if _, ok := config.Encoder.(ParquetUsageEncoder); ok && config.Dir == "" {
+		return nil, errors.New("parquet usage export needs Dir: Parquet files can't be appended to")
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultUsageExportInterval
+	}
+	if config.Retention <= 0 {
+		config.Retention = DefaultUsageRetention
+	}
+	e := &UsageExporter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[usageBucketKey]*UsageTotals),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+func (e *UsageExporter) Write(record AuditRecord) error {
+	usage := UsageRecord{
+		Timestamp:        record.Timestamp,
+		RequestID:        record.RequestID,
+		Tenant:           record.Tenant,
+		Provider:         record.Provider,
+		Model:            record.Model,
+		Outcome:          record.Outcome,
+		PromptTokens:     record.Usage.PromptTokens,
+		CompletionTokens: record.Usage.CompletionTokens,
+		CacheReadTokens:  record.Usage.CacheReadTokens,
+		CacheWriteTokens: record.Usage.CacheWriteTokens,
+		Cost:             record.Cost,
+		Latency:          record.Latency,
+		Tags:             record.Tags,
+	}
+	if usage.Timestamp.IsZero() {
+		usage.Timestamp = e.now()
+	}
+
+	e.mu.Lock()
+	e.pending = append(e.pending, usage)
+	e.aggregate(usage)
+	e.mu.Unlock()
+
+	if e.config.Next != nil {
+		return e.config.Next.Write(record)
+	}
+	return nil
+}
+
+// Flush exports buffered records (kept for the next flush if exporting fails)
+func (e *UsageExporter) Flush() error {
+	e.flushMu.Lock()
+	defer e.flushMu.Unlock()
+
+	e.mu.Lock()
+	records, continuation := e.pending, e.written
+	e.pending = nil
+	e.mu.Unlock()
+
+	err := e.export(records, continuation)
+	e.mu.Lock()
+	if err != nil {
+		e.pending = append(records, e.pending...)
+	} else if len(records) > 0 {
+		e.written = true
+	}
+	e.mu.Unlock()
+
+	if flusher, ok := e.config.Next.(interface{ Flush() error }); ok {
+		err = errors.Join(err, flusher.Flush())
+	}
+	return err
+}
+
+// Close stops the flush loop and exports remaining records (Next is not closed)
+func (e *UsageExporter) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	e.mu.Unlock()
+
+	close(e.stop)
+	<-e.done
+	return e.Flush()
+}
+
+// Report aggregates usage of the last period (hour granularity)
+func (e *UsageExporter) Report(period time.Duration) UsageReport {
+	now := e.now()
+	report := UsageReport{
+		From:       now.Add(-period).Truncate(time.Hour),
+		To:         now,
+		ByProvider: map[string]UsageTotals{},
+		ByModel:    map[string]UsageTotals{},
+		ByTenant:   map[string]UsageTotals{},
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key, totals := range e.buckets {
+		if key.hour.Before(report.From) {
+			continue
+		}
+		report.Totals.add(*totals)
+		addUsage(report.ByProvider, key.provider, *totals)
+		addUsage(report.ByModel, key.model, *totals)
+		addUsage(report.ByTenant, key.tenant, *totals)
+	}
+	return report
+}
+
+// run flushes every interval until Close
+func (e *UsageExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.Flush(); err != nil && e.config.OnError != nil {
+				e.config.OnError(err)
+			}
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// export writes records to a new file in Dir or appends them to Writer
+func (e *UsageExporter) export(records []UsageRecord, continuation bool) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if e.config.Dir == "" {
+		if err := e.config.Encoder.Encode(e.config.Writer, records, continuation); err != nil {
+			return fmt.Errorf("failed to export usage: %w", err)
+		}
+		return nil
+	}
+
+	name := "usage-" + e.now().UTC().Format("20060102T150405.000000000") + e.config.Encoder.Extension()
+	path := filepath.Join(e.config.Dir, name)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create usage export file: %w", err)
+	}
+	if err := e.config.Encoder.Encode(file, records, false); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to export usage: %w", err)
+	}
+	return file.Close()
+}
+
+// aggregate adds record to its hourly bucket and drops buckets past retention (e.mu held)
+func (e *UsageExporter) aggregate(record UsageRecord) {
+	key := usageBucketKey{
+		hour:     record.Timestamp.Truncate(time.Hour),
+		provider: record.Provider,
+		model:    record.Model,
+		tenant:   record.Tenant,
+	}
+	totals, ok := e.buckets[key]
+	if !ok {
+		totals = &UsageTotals{}
+		e.buckets[key] = totals
+		cutoff := e.now().Add(-e.config.Retention)
+		for k := range e.buckets {
+			if k.hour.Before(cutoff) {
+				delete(e.buckets, k)
+			}
+		}
+	}
+	totals.Calls++
+	if record.Outcome == AuditOutcomeError {
+		totals.Errors++
+	}
+	totals.PromptTokens += record.PromptTokens
+	totals.CompletionTokens += record.CompletionTokens
+	totals.CacheReadTokens += record.CacheReadTokens
+	totals.Cost += record.Cost
+}
+
+func (t *UsageTotals) add(other UsageTotals) {
+	t.Calls += other.Calls
+	t.Errors += other.Errors
+	t.PromptTokens += other.PromptTokens
+	t.CompletionTokens += other.CompletionTokens
+	t.CacheReadTokens += other.CacheReadTokens
+	t.Cost += other.Cost
+}
+
+// addUsage adds totals to breakdown[name]
+func addUsage(breakdown map[string]UsageTotals, name string, totals UsageTotals) {
+	sum := breakdown[name]
+	sum.add(totals)
+	breakdown[name] = sum
+}
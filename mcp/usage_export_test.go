@@ -0,0 +1,233 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func usageAuditRecord(at time.Time, tenant, model string, cost float64, outcome string) AuditRecord {
+	return AuditRecord{
+		Timestamp: at,
+		Tenant:    tenant,
+		Provider:  ProviderOpenAI,
+		Model:     model,
+		Usage:     TokenUsage{PromptTokens: 100, CompletionTokens: 20},
+		Cost:      cost,
+		Latency:   1500 * time.Millisecond,
+		Outcome:   outcome,
+		Tags:      map[string]string{"strategy": "grid", "env": "prod"},
+	}
+}
+
+func TestUsageExporter_WriterAndReport(t *testing.T) {
+	var out bytes.Buffer
+	next := &memoryAuditSink{}
+	exporter, err := NewUsageExporter(UsageExportConfig{Writer: &out, Interval: time.Hour, Next: next})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	now := time.Now()
+	exporter.Write(usageAuditRecord(now, "acme", "gpt-4o", 0.5, AuditOutcomeSuccess))
+	exporter.Write(usageAuditRecord(now, "globex", "gpt-4o-mini", 0.25, AuditOutcomeError))
+	if err := exporter.Flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	exporter.Write(usageAuditRecord(now.Add(-48*time.Hour), "acme", "gpt-4o", 2, AuditOutcomeSuccess))
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(rows) != 4 {
+		t.Fatalf("expected header and 3 rows, got %d, %v", len(rows), err)
+	}
+	if rows[0][0] != "timestamp" || rows[1][2] != "acme" || rows[1][10] != "0.5" || rows[1][11] != "1500" || rows[1][12] != "env=prod;strategy=grid" {
+		t.Errorf("unexpected CSV %v", rows[:2])
+	}
+	if len(next.Records()) != 3 {
+		t.Errorf("records not forwarded to next sink")
+	}
+
+	day := exporter.Report(24 * time.Hour)
+	if day.Totals.Calls != 2 || day.Totals.Errors != 1 || day.Totals.Cost != 0.75 {
+		t.Errorf("unexpected day totals %+v", day.Totals)
+	}
+	if day.ByTenant["acme"].Cost != 0.5 || day.ByModel["gpt-4o-mini"].Calls != 1 || day.ByProvider[ProviderOpenAI].PromptTokens != 200 {
+		t.Errorf("unexpected breakdowns %+v", day)
+	}
+	if week := exporter.Report(7 * 24 * time.Hour); week.ByTenant["acme"].Cost != 2.5 {
+		t.Errorf("unexpected week totals %+v", week.ByTenant)
+	}
+}
+
+// failingWriter fails the first write
+type failingWriter struct {
+	failed bool
+	buf    bytes.Buffer
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if !w.failed {
+		w.failed = true
+		return 0, errors.New("disk full")
+	}
+	return w.buf.Write(p)
+}
+
+func TestUsageExporter_DirAndRetry(t *testing.T) {
+	dir := t.TempDir()
+	exporter, err := NewUsageExporter(UsageExportConfig{Dir: dir, Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	exporter.Write(usageAuditRecord(time.Now(), "", "gpt-4o", 0.1, AuditOutcomeSuccess))
+	exporter.Flush()
+	exporter.Write(usageAuditRecord(time.Now(), "", "gpt-4o", 0.1, AuditOutcomeSuccess))
+	exporter.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "usage-*.csv"))
+	if len(files) != 2 {
+		t.Fatalf("expected one file per flush, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("expected header and one row, got %q", data)
+	}
+
+	writer := &failingWriter{}
+	retrying, _ := NewUsageExporter(UsageExportConfig{Writer: writer, Interval: time.Hour})
+	retrying.Write(usageAuditRecord(time.Now(), "", "gpt-4o", 0.1, AuditOutcomeSuccess))
+	if err := retrying.Flush(); err == nil {
+		t.Error("expected first flush to fail")
+	}
+	retrying.Close()
+	if !strings.Contains(writer.buf.String(), "gpt-4o") {
+		t.Errorf("failed records not retried: %q", writer.buf.String())
+	}
+}
+
+// thriftReader minimal Thrift compact protocol decoder (structs as field ID -> value)
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		r.pos += n
+		return string(r.data[r.pos-n : r.pos])
+	case thriftList:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		fields := map[int16]any{}
+		var last int16
+		for {
+			header := r.data[r.pos]
+			r.pos++
+			if header == 0 {
+				return fields
+			}
+			id := last + int16(header>>4)
+			if header>>4 == 0 {
+				id = int16(r.varint())
+			}
+			fields[id] = r.value(header & 0x0f)
+			last = id
+		}
+	}
+	panic(fmt.Sprintf("unexpected thrift type %d", typ))
+}
+
+func TestParquetUsageEncoder(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := []UsageRecord{
+		{Timestamp: at, Tenant: "acme", Model: "gpt-4o", PromptTokens: 100, Cost: 0.5, Latency: 1500 * time.Millisecond, Tags: map[string]string{"env": "prod"}},
+		{Timestamp: at.Add(time.Second), Tenant: "globex", Model: "gpt-4o-mini", PromptTokens: 7, Cost: 0.25},
+	}
+	var out bytes.Buffer
+	if err := (ParquetUsageEncoder{}).Encode(&out, records, false); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	data := out.Bytes()
+	if string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatal("missing Parquet magic")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &thriftReader{data: data[:len(data)-8], pos: len(data) - 8 - size}
+	meta := footer.value(thriftStruct).(map[int16]any)
+	if meta[3] != int64(2) || len(meta[2].([]any)) != len(usageCSVHeader)+1 {
+		t.Fatalf("unexpected file metadata %v", meta)
+	}
+
+	// Reads the PLAIN values of every column chunk
+	columns := map[string][]byte{}
+	for _, chunk := range meta[4].([]any)[0].(map[int16]any)[1].([]any) {
+		chunkMeta := chunk.(map[int16]any)[3].(map[int16]any)
+		page := &thriftReader{data: data, pos: int(chunkMeta[9].(int64))}
+		header := page.value(thriftStruct).(map[int16]any)
+		if header[5].(map[int16]any)[1] != int64(2) {
+			t.Errorf("data page should hold 2 values, got %v", header)
+		}
+		columns[chunkMeta[3].([]any)[0].(string)] = data[page.pos : page.pos+int(header[3].(int64))]
+	}
+	if got := time.UnixMicro(int64(binary.LittleEndian.Uint64(columns["timestamp"]))).UTC(); !got.Equal(at) {
+		t.Errorf("timestamp = %v, want %v", got, at)
+	}
+	if got := math.Float64frombits(binary.LittleEndian.Uint64(columns["cost_usd"][8:])); got != 0.25 {
+		t.Errorf("second cost = %v, want 0.25", got)
+	}
+	if got := binary.LittleEndian.Uint64(columns["latency_ms"]); got != 1500 {
+		t.Errorf("latency = %d, want 1500", got)
+	}
+	if tenants := columns["tenant"]; string(tenants[4:8]) != "acme" || string(tenants[12:18]) != "globex" {
+		t.Errorf("unexpected tenant column %q", tenants)
+	}
+	if tags := columns["tags"]; string(tags[4:12]) != "env=prod" {
+		t.Errorf("unexpected tags column %q", tags)
+	}
+
+	if _, err := NewUsageExporter(UsageExportConfig{Writer: &out, Encoder: ParquetUsageEncoder{}}); err == nil {
+		t.Error("Parquet export to an appended Writer should be rejected")
+	}
+	dir := t.TempDir()
+	exporter, _ := NewUsageExporter(UsageExportConfig{Dir: dir, Encoder: ParquetUsageEncoder{}, Interval: time.Hour})
+	exporter.Write(usageAuditRecord(time.Now(), "acme", "gpt-4o", 0.1, AuditOutcomeSuccess))
+	exporter.Close()
+	if files, _ := filepath.Glob(filepath.Join(dir, "usage-*.parquet")); len(files) != 1 {
+		t.Errorf("expected one Parquet file, got %v", files)
+	}
+}
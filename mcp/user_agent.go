@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+)
+
+// HeaderClientVersion header carrying the package version (see WithClientVersionHeader)
+const HeaderClientVersion = "X-Client-Version"
+
+// Version of the nofx build making requests ("dev" for local builds)
+//
+// Taken from the module version in the build info; set with
+// -ldflags "-X nofx/mcp.Version=v1.2.3" for builds without one.
+var Version = buildVersion()
+
+// buildVersion returns the main module version from the build info
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// WithUserAgent replaces the default User-Agent header
+//
+// The default identifies nofx traffic to provider dashboards and gateways:
+//   nofx-mcp/<Version> (go1.23.2; provider=deepseek)
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithClientVersionHeader also sends the package version in the X-Client-Version header
+func WithClientVersionHeader() ClientOption {
+	return func(c *Config) {
+		c.ClientVersionHeader = true
+	}
+}
+
+// userAgent returns the User-Agent header value of client requests
+func (client *Client) userAgent() string {
+	if client.config.UserAgent != "" {
+		return client.config.UserAgent
+	}
+	return fmt.Sprintf("nofx-mcp/%s (%s; provider=%s)", Version, runtime.Version(), client.Provider)
+}
+
+// setClientHeaders sets identification headers (User-Agent, optional X-Client-Version)
+func (client *Client) setClientHeaders(header http.Header) {
+	header.Set("User-Agent", client.userAgent())
+	if client.config.ClientVersionHeader {
+		header.Set(HeaderClientVersion, Version)
+	}
+}
@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUserAgent_Headers(t *testing.T) {
+	mock := NewMockHTTPClient()
+	mock.SetSuccessResponse("ok")
+	client := NewQwenClientWithOptions(WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()), WithHTTPClient(mock.ToHTTPClient()))
+	if _, err := client.CallWithMessages("system", "user"); err != nil {
+		t.Fatal(err)
+	}
+	req := mock.GetLastRequest()
+	if ua := req.Header.Get("User-Agent"); !strings.HasPrefix(ua, "nofx-mcp/"+Version) || !strings.Contains(ua, runtime.Version()) || !strings.Contains(ua, "provider=qwen") {
+		t.Errorf("unexpected default User-Agent %q", ua)
+	}
+	if req.Header.Get(HeaderClientVersion) != "" {
+		t.Error("X-Client-Version should be opt-in")
+	}
+
+	base := NewClient(WithProvider(ProviderCustom), WithBaseURL("https://gateway.local/v1"), WithAPIKey("sk-test-key"), WithLogger(NewNoopLogger()),
+		WithHTTPClient(mock.ToHTTPClient()), WithUserAgent("trader-bot/2.0"), WithClientVersionHeader()).(*Client)
+	if _, err := base.ListModels(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	req = mock.GetLastRequest()
+	if req.Header.Get("User-Agent") != "trader-bot/2.0" || req.Header.Get(HeaderClientVersion) != Version {
+		t.Errorf("expected overridden headers, got %v", req.Header)
+	}
+}
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Risk review verdicts
+const (
+	VerdictApprove = "approve"
+	VerdictReject  = "reject"
+	VerdictModify  = "modify"
+)
+
+// DefaultRiskRules risk rules applied by VerifiedCall when none are configured
+var DefaultRiskRules = []string{
+	"Opening a position requires a stop loss on the protective side of the entry",
+	"Leverage must not exceed 10x",
+	"Positions must not be opened with confidence below 60",
+	"The rationale must support the action and must not contradict the market context",
+}
+
+// riskReviewPrompt system prompt of the verifier model
+const riskReviewPrompt = `You are the risk officer of a trading desk. Review the proposed trade decision against the risk rules and the market context.
+Reply only with JSON:
+{"verdict": "approve" | "reject" | "modify", "reasons": ["<reason>", ...], "decision": <corrected decision, only for modify>}
+Use "modify" when the trade is acceptable after a correction (e.g. smaller size, tighter stop loss); the corrected decision keeps the same JSON fields.`
+
+// ErrDecisionRejected is matched (errors.Is) by DecisionRejectedError
+var ErrDecisionRejected = errors.New("trade decision rejected by risk review")
+
+// DecisionRejectedError decision rejected by the verifier model
+type DecisionRejectedError struct {
+	Decision *TradeDecision // Rejected decision of the primary model
+	Reasons  []string
+}
+
+func (e *DecisionRejectedError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrDecisionRejected, strings.Join(e.Reasons, "; "))
+}
+
+func (e *DecisionRejectedError) Is(target error) bool {
+	return target == ErrDecisionRejected
+}
+
+// RiskReview verdict of the verifier model
+type RiskReview struct {
+	Verdict  string
+	Reasons  []string
+	Modified *TradeDecision // Corrected decision (VerdictModify only)
+	Raw      string         // Raw verifier reply
+}
+
+// VerifiedDecision decision that passed risk review
+type VerifiedDecision struct {
+	Decision *TradeDecision // Decision to execute (the modified one for VerdictModify)
+	Original *TradeDecision // Decision proposed by the primary model
+	Review   RiskReview
+}
+
+// WithDecisionVerifier sets model reviewing VerifiedCall decisions and its risk rules
+//
+// Usage example:
+//   client := mcp.NewDeepSeekClientWithOptions(
+//       mcp.WithDecisionVerifier(mcp.NewClaudeClientWithOptions(mcp.WithAPIKey(key)), mcp.DefaultRiskRules...),
+//   )
+func WithDecisionVerifier(verifier AIClient, rules ...string) ClientOption {
+	return func(c *Config) {
+		c.DecisionVerifier = verifier
+		c.RiskRules = rules
+	}
+}
+
+// VerifiedCall gets a trade decision and has it reviewed by the verifier model
+//
+// Rejected decisions fail with *DecisionRejectedError so they can't be executed by
+// accident; modified decisions are returned in place of the original. Without a
+// configured verifier the client reviews its own decision in a separate call.
+//
+// Usage example:
+//   verified, err := client.VerifiedCall(ctx, marketSnapshot)
+//   if errors.Is(err, mcp.ErrDecisionRejected) {
+//       log.Printf("risk review: %v", err)
+//       return
+//   }
+//   execute(verified.Decision)
+func (client *Client) VerifiedCall(ctx context.Context, marketContext string) (*VerifiedDecision, error) {
+	decision, err := client.CallTradeDecision(ctx, marketContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifier AIClient = client
+	if client.config.DecisionVerifier != nil {
+		verifier = client.config.DecisionVerifier
+	}
+	rules := client.config.RiskRules
+	if len(rules) == 0 {
+		rules = DefaultRiskRules
+	}
+
+	review, err := ReviewTradeDecision(ctx, verifier, decision, marketContext, rules)
+	if err != nil {
+		return nil, err
+	}
+	verified := &VerifiedDecision{Decision: decision, Original: decision, Review: *review}
+	switch review.Verdict {
+	case VerdictReject:
+		return nil, &DecisionRejectedError{Decision: decision, Reasons: review.Reasons}
+	case VerdictModify:
+		verified.Decision = review.Modified
+	}
+	return verified, nil
+}
+
+// ReviewTradeDecision asks verifier to approve, reject or modify decision under rules
+func ReviewTradeDecision(ctx context.Context, verifier AIClient, decision *TradeDecision, marketContext string, rules []string) (*RiskReview, error) {
+	decisionJSON, err := json.Marshal(decision)
+	if err != nil {
+		return nil, err
+	}
+	var prompt strings.Builder
+	prompt.WriteString("Risk rules:\n")
+	for _, rule := range rules {
+		prompt.WriteString("- " + rule + "\n")
+	}
+	prompt.WriteString("\nMarket context:\n" + marketContext + "\n\nProposed decision:\n" + string(decisionJSON))
+
+	req, err := NewRequestBuilder().
+		WithSystemPrompt(riskReviewPrompt).
+		WithUserPrompt(prompt.String()).
+		WithTemperature(0).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	reply, err := callRequest(ctx, verifier, req)
+	if err != nil {
+		return nil, fmt.Errorf("risk review call failed: %w", err)
+	}
+	return parseRiskReview(reply)
+}
+
+// parseRiskReview parses verifier JSON verdict (tolerating code fences and prose around it)
+func parseRiskReview(reply string) (*RiskReview, error) {
+	var verdict struct {
+		Verdict  string          `json:"verdict"`
+		Reasons  []string        `json:"reasons"`
+		Decision json.RawMessage `json:"decision"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(reply)), &verdict); err != nil {
+		return nil, fmt.Errorf("verifier returned invalid review: %w, reply: %s", err, reply)
+	}
+
+	review := &RiskReview{
+		Verdict: strings.ToLower(strings.TrimSpace(verdict.Verdict)),
+		Reasons: verdict.Reasons,
+		Raw:     reply,
+	}
+	switch review.Verdict {
+	case VerdictApprove, VerdictReject:
+	case VerdictModify:
+		modified, err := ParseTradeDecision(string(verdict.Decision))
+		if err != nil {
+			return nil, fmt.Errorf("verifier returned invalid modified decision: %w", err)
+		}
+		review.Modified = modified
+	default:
+		return nil, fmt.Errorf("verifier returned unknown verdict %q", verdict.Verdict)
+	}
+	return review, nil
+}
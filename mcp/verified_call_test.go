@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// decisionClient client whose primary model proposes opening a BTC long
+func decisionClient(t *testing.T, verifier AIClient) *Client {
+	t.Helper()
+	var bodies []map[string]any
+	answer := `{"choices":[{"message":{"content":"{\"action\":\"open_long\",\"symbol\":\"BTCUSDT\",\"size_usd\":5000,\"leverage\":20,\"stop_loss\":64000,\"confidence\":80,\"rationale\":\"Breakout\",\"risk_flags\":[]}"}}]}`
+	return NewClient(
+		WithHTTPClient(captureBodyClient(answer, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithDecisionVerifier(verifier, "Leverage must not exceed 10x"),
+	).(*Client)
+}
+
+func TestVerifiedCall_Approve(t *testing.T) {
+	verifier := &stubClient{answer: `{"verdict":"approve","reasons":["within limits"]}`}
+	verified, err := decisionClient(t, verifier).VerifiedCall(context.Background(), "BTC 66k")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verified.Decision != verified.Original || verified.Review.Verdict != VerdictApprove {
+		t.Errorf("unexpected verified decision %+v", verified)
+	}
+	if !strings.Contains(verifier.prompts[0], "Leverage must not exceed 10x") || !strings.Contains(verifier.prompts[0], `"leverage":20`) {
+		t.Errorf("verifier prompt lacks rules or decision: %s", verifier.prompts[0])
+	}
+}
+
+func TestVerifiedCall_RejectIsTypedError(t *testing.T) {
+	verifier := &stubClient{answer: "```json\n{\"verdict\":\"reject\",\"reasons\":[\"leverage 20x exceeds 10x\"]}\n```"}
+	_, err := decisionClient(t, verifier).VerifiedCall(context.Background(), "BTC 66k")
+
+	var rejected *DecisionRejectedError
+	if !errors.Is(err, ErrDecisionRejected) || !errors.As(err, &rejected) {
+		t.Fatalf("expected DecisionRejectedError, got %v", err)
+	}
+	if rejected.Decision.Leverage != 20 || rejected.Reasons[0] != "leverage 20x exceeds 10x" {
+		t.Errorf("unexpected rejection %+v", rejected)
+	}
+}
+
+func TestVerifiedCall_Modify(t *testing.T) {
+	verifier := &stubClient{answer: `{"verdict":"modify","reasons":["cap leverage"],"decision":{"action":"open_long","symbol":"BTCUSDT","size_usd":2500,"leverage":10,"stop_loss":64000,"confidence":80,"rationale":"Breakout, reduced risk","risk_flags":["high_leverage"]}}`}
+	verified, err := decisionClient(t, verifier).VerifiedCall(context.Background(), "BTC 66k")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verified.Decision.Leverage != 10 || verified.Original.Leverage != 20 {
+		t.Errorf("expected modified decision to replace original, got %+v / %+v", verified.Decision, verified.Original)
+	}
+
+	bad := &stubClient{answer: `{"verdict":"modify","decision":{"action":"open_long"}}`}
+	if _, err := decisionClient(t, bad).VerifiedCall(context.Background(), "BTC 66k"); err == nil {
+		t.Error("expected error for invalid modified decision")
+	}
+}
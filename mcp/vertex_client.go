@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ProviderVertex        = "vertex"
+	DefaultVertexLocation = "us-central1"
+	DefaultVertexModel    = "google/gemini-2.5-flash"
+)
+
+type VertexClient struct {
+	*Client
+}
+
+// NewVertexClient creates Google Vertex AI client (backward compatible)
+func NewVertexClient() AIClient {
+	return NewVertexClientWithOptions()
+}
+
+// NewVertexClientWithOptions creates Vertex AI client (supports options pattern)
+//
+// Requests go to Vertex's OpenAI-compatible endpoint of the project and region
+// (GOOGLE_CLOUD_PROJECT and GOOGLE_CLOUD_LOCATION unless WithVertexProject is given)
+// and are authenticated with OAuth access tokens from Application Default Credentials.
+// Model names without a publisher ("gemini-2.5-pro") are sent as google/<model>.
+//
+// Usage example:
+//   client := mcp.NewVertexClientWithOptions(
+//       mcp.WithVertexProject("my-project", "europe-west4"),
+//       mcp.WithModel("gemini-2.5-pro"),
+//   )
+func NewVertexClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Vertex preset options
+	vertexOpts := []ClientOption{
+		WithProvider(ProviderVertex),
+		WithModel(DefaultVertexModel),
+		WithVertexProject(getEnvString("GOOGLE_CLOUD_PROJECT", ""), getEnvString("GOOGLE_CLOUD_LOCATION", DefaultVertexLocation)),
+		WithTokenSource(NewGoogleADCTokenSource()),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(vertexOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	if strings.Contains(baseClient.BaseURL, "/projects//") {
+		baseClient.logger.Warnf("⚠️  [%s] Vertex project not set, use WithVertexProject or GOOGLE_CLOUD_PROJECT", baseClient.String())
+	}
+
+	// 4. Create Vertex client
+	vertexClient := &VertexClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to VertexClient (implement dynamic dispatch)
+	baseClient.hooks = vertexClient
+
+	return vertexClient
+}
+
+// WithVertexProject sets the Google Cloud project and region of Vertex AI requests
+//
+// Region "global" uses the global endpoint.
+func WithVertexProject(project, location string) ClientOption {
+	return WithBaseURL(vertexBaseURL(project, location))
+}
+
+// vertexBaseURL OpenAI-compatible endpoint of a project and region
+func vertexBaseURL(project, location string) string {
+	if location == "" {
+		location = DefaultVertexLocation
+	}
+	host := location + "-aiplatform.googleapis.com"
+	if location == "global" {
+		host = "aiplatform.googleapis.com"
+	}
+	return fmt.Sprintf("https://%s/v1/projects/%s/locations/%s/endpoints/openapi", host, project, location)
+}
+
+// vertexModel returns the publisher model path Vertex expects ("gemini-2.5-pro" -> "google/gemini-2.5-pro")
+func vertexModel(model string) string {
+	if model == "" || strings.Contains(model, "/") {
+		return model
+	}
+	return "google/" + model
+}
+
+func (c *VertexClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	// Vertex authenticates with OAuth access tokens from the token source
+	if apiKey != "" && apiKey != tokenSourceAPIKey {
+		c.logger.Infof("🔧 [MCP] Vertex ignores API keys, using OAuth access tokens (see WithTokenSource)")
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Vertex using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Vertex using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] Vertex using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] Vertex using default Model: %s", c.Model)
+	}
+}
+
+// marshalRequestBody sends models by publisher path
+func (c *VertexClient) marshalRequestBody(requestBody map[string]any) ([]byte, error) {
+	if model, ok := requestBody["model"].(string); ok {
+		requestBody["model"] = vertexModel(model)
+	}
+	return c.Client.marshalRequestBody(requestBody)
+}
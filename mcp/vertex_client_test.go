@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+// ============================================================
+// Test VertexClient
+// ============================================================
+
+func TestVertexClient_ProjectEndpointAndModel(t *testing.T) {
+	var bodies []map[string]any
+	client := NewVertexClientWithOptions(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"Hold BTC."},"finish_reason":"stop"}]}`, &bodies)),
+		WithVertexProject("trading-prod", "europe-west4"),
+		WithModel("gemini-2.5-pro"),
+		WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*OAuthToken, error) {
+			return &OAuthToken{AccessToken: "ya29.vertex"}, nil
+		})),
+		WithLogger(NewNoopLogger()),
+	).(*VertexClient)
+
+	want := "https://europe-west4-aiplatform.googleapis.com/v1/projects/trading-prod/locations/europe-west4/endpoints/openapi/chat/completions"
+	if got := client.buildUrl(); got != want {
+		t.Errorf("unexpected URL %s", got)
+	}
+	if _, err := client.CallWithMessages("", "BTC?"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if bodies[0]["model"] != "google/gemini-2.5-pro" {
+		t.Errorf("expected publisher model path, got %v", bodies[0]["model"])
+	}
+}
+
+func TestVertexBaseURL(t *testing.T) {
+	if got := vertexBaseURL("p", "global"); got != "https://aiplatform.googleapis.com/v1/projects/p/locations/global/endpoints/openapi" {
+		t.Errorf("unexpected global URL %s", got)
+	}
+	if got := vertexModel("meta/llama-3.3-70b-instruct-maas"); got != "meta/llama-3.3-70b-instruct-maas" {
+		t.Errorf("publisher models must be kept, got %s", got)
+	}
+}
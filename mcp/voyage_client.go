@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"errors"
+	"net/http"
+)
+
+const (
+	ProviderVoyage          = "voyage"
+	DefaultVoyageBaseURL    = "https://api.voyageai.com/v1"
+	DefaultVoyageEmbedModel = "voyage-3.5"
+)
+
+// ErrChatUnsupported is returned by chat calls of providers that only serve embeddings and reranking
+var ErrChatUnsupported = errors.New("provider has no chat API")
+
+type VoyageClient struct {
+	*Client
+}
+
+// NewVoyageClient creates Voyage AI client (backward compatible)
+func NewVoyageClient() AIClient {
+	return NewVoyageClientWithOptions()
+}
+
+// NewVoyageClientWithOptions creates Voyage AI client (supports options pattern)
+//
+// Voyage serves embeddings and reranking only: Embed and Rerank work, chat calls
+// fail with ErrChatUnsupported.
+//
+// Usage example:
+//   voyage := mcp.NewVoyageClientWithOptions(mcp.WithAPIKey(key)).(*mcp.VoyageClient)
+//   vectors, err := voyage.Embed(ctx, headlines)
+//   ranked, err := voyage.Rerank(ctx, "BTC ETF inflows", headlines, 5)
+func NewVoyageClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Voyage preset options
+	voyageOpts := []ClientOption{
+		WithProvider(ProviderVoyage),
+		WithModel(DefaultVoyageEmbedModel),
+		WithBaseURL(DefaultVoyageBaseURL),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(voyageOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create Voyage client
+	voyageClient := &VoyageClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to VoyageClient (implement dynamic dispatch)
+	baseClient.hooks = voyageClient
+
+	return voyageClient
+}
+
+func (c *VoyageClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if len(apiKey) > 8 {
+		c.logger.Infof("🔧 [MCP] Voyage API Key: %s...%s", apiKey[:4], apiKey[len(apiKey)-4:])
+	}
+	if customURL != "" {
+		c.setBaseURL(customURL)
+		c.logger.Infof("🔧 [MCP] Voyage using custom BaseURL: %s", customURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Voyage using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.config.EmbeddingModel = customModel
+		c.logger.Infof("🔧 [MCP] Voyage using custom embedding Model: %s", customModel)
+	}
+}
+
+// buildRequest Voyage has no chat endpoint, so every chat request fails before sending
+func (c *VoyageClient) buildRequest(url string, jsonData []byte) (*http.Request, error) {
+	return nil, ErrChatUnsupported
+}
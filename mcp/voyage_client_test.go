@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// ============================================================
+// Test VoyageClient
+// ============================================================
+
+func TestVoyageClient_EmbedAndRerank(t *testing.T) {
+	var bodies []map[string]any
+	client := NewVoyageClientWithOptions(
+		WithHTTPClient(captureBodyClient(`{"data":[{"index":0,"embedding":[0.5,0.5]}],"model":"voyage-3.5","usage":{"total_tokens":3}}`, &bodies)),
+		WithAPIKey("pa-test-key"),
+		WithLogger(NewNoopLogger()),
+	).(*VoyageClient)
+
+	result, err := client.Embed(context.Background(), []string{"BTC up"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(result.Vectors) != 1 || result.Usage.TotalTokens != 3 || bodies[0]["model"] != DefaultVoyageEmbedModel {
+		t.Errorf("unexpected embedding %+v request %v", result, bodies[0])
+	}
+
+	bodies = nil
+	client.httpClient = captureBodyClient(`{"data":[{"index":1,"relevance_score":0.8},{"index":0,"relevance_score":0.1}],"model":"rerank-2.5","usage":{"total_tokens":20}}`, &bodies)
+	ranked, err := client.Rerank(context.Background(), "BTC", []string{"ETH news", "BTC news"}, 1)
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if ranked.Results[0].Document != "BTC news" || ranked.Usage.TotalTokens != 20 {
+		t.Errorf("unexpected rerank %+v", ranked)
+	}
+	if bodies[0]["top_k"] != float64(1) {
+		t.Errorf("Voyage expects top_k, got %v", bodies[0])
+	}
+}
+
+func TestVoyageClient_ChatUnsupported(t *testing.T) {
+	client := NewVoyageClientWithOptions(WithAPIKey("pa-test-key"), WithLogger(NewNoopLogger()), WithMaxRetries(1))
+
+	_, err := client.CallWithMessages("system", "hello")
+	if !errors.Is(err, ErrChatUnsupported) {
+		t.Errorf("expected ErrChatUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// WarmupReport what a warm-up did and how long each connection step took
+type WarmupReport struct {
+	DNS           time.Duration // Name resolution (0 if cached or an IP address)
+	Connect       time.Duration // TCP connect
+	TLS           time.Duration // TLS handshake (0 for plain HTTP)
+	Total         time.Duration
+	Reused        bool // A pooled connection was already open
+	StatusCode    int  // HTTP status of the warm-up request
+	Authenticated bool // API key accepted (only checked with WithWarmupAuthCheck)
+}
+
+// warmupState coalesces concurrent warm-ups of a client
+type warmupState struct {
+	mu      sync.Mutex
+	running *warmupCall
+}
+
+// warmupCall one in-flight warm-up shared by concurrent callers
+type warmupCall struct {
+	done   chan struct{}
+	report WarmupReport
+	err    error
+}
+
+// WithWarmupAuthCheck makes Warmup also verify the API key (authenticated GET of the models list)
+func WithWarmupAuthCheck() ClientOption {
+	return func(c *Config) {
+		c.WarmupAuthCheck = true
+	}
+}
+
+// Warmup opens a pooled connection to the provider ahead of the first call
+//
+// Resolves DNS, connects and completes the TLS handshake with one lightweight request
+// to the models endpoint, so the first real decision doesn't pay cold-start latency.
+// Any HTTP answer counts as warm; with WithWarmupAuthCheck the request carries the
+// API key and a rejected key fails with ErrInvalidAPIKey. Safe for concurrent use:
+// callers arriving during a warm-up share its result.
+//
+// Usage example:
+//   if report, err := client.Warmup(ctx); err == nil {
+//       log.Printf("AI connection ready (TLS %v)", report.TLS)
+//   }
+func (client *Client) Warmup(ctx context.Context) (WarmupReport, error) {
+	w := &client.warm
+	w.mu.Lock()
+	if call := w.running; call != nil {
+		w.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.report, call.err
+		case <-ctx.Done():
+			return WarmupReport{}, ctx.Err()
+		}
+	}
+	call := &warmupCall{done: make(chan struct{})}
+	w.running = call
+	w.mu.Unlock()
+
+	call.report, call.err = client.warmup(ctx)
+	w.mu.Lock()
+	w.running = nil
+	w.mu.Unlock()
+	close(call.done)
+	return call.report, call.err
+}
+
+// warmup performs one warm-up request, timing its connection steps
+func (client *Client) warmup(ctx context.Context) (WarmupReport, error) {
+	if client.baseURLErr != nil {
+		return WarmupReport{}, client.baseURLErr
+	}
+	var report WarmupReport
+	var dnsStart, connectStart, tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { report.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { report.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { report.TLS = time.Since(tlsStart) },
+		GotConn:           func(info httptrace.GotConnInfo) { report.Reused = info.Reused },
+	}
+
+	method := http.MethodHead
+	if client.config.WarmupAuthCheck {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), method, client.endpointURL(EndpointModels), nil)
+	if err != nil {
+		return report, fmt.Errorf("fail to build warm-up request: %w", err)
+	}
+	client.setClientHeaders(req.Header)
+	if client.config.WarmupAuthCheck {
+		client.hooks.setAuthHeader(req.Header)
+	}
+
+	start := time.Now()
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return report, fmt.Errorf("warm-up failed: %w", err)
+	}
+	// The body is drained so the connection goes back to the pool
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	report.Total = time.Since(start)
+	report.StatusCode = resp.StatusCode
+
+	if client.config.WarmupAuthCheck {
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return report, client.newAPIError(resp.StatusCode, body)
+		}
+		report.Authenticated = resp.StatusCode == http.StatusOK
+	}
+	client.logger.Infof("🔥 [%s] Connection warmed up in %v (DNS %v, connect %v, TLS %v)", client.String(), report.Total, report.DNS, report.Connect, report.TLS)
+	return report, nil
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmup_ConnectionReused(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		if r.Method != http.MethodHead || r.Header.Get("Authorization") != "" {
+			t.Errorf("expected unauthenticated HEAD, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+	client := NewClient(WithProvider(ProviderCustom), WithBaseURL(server.URL), WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()), WithHTTPClient(server.Client())).(*Client)
+
+	var wg sync.WaitGroup
+	reports := make([]WarmupReport, 4)
+	for i := range reports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report, err := client.Warmup(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+			reports[i] = report
+		}()
+	}
+	wg.Wait()
+	if hits.Load() >= 4 {
+		t.Errorf("concurrent warm-ups should be coalesced, got %d requests", hits.Load())
+	}
+	if reports[0].TLS <= 0 || reports[0].Reused || reports[0].StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected a fresh TLS connection, got %+v", reports[0])
+	}
+
+	report, err := client.Warmup(context.Background())
+	if err != nil || !report.Reused || report.TLS != 0 {
+		t.Errorf("expected the pooled connection to be reused, got %+v (%v)", report, err)
+	}
+}
+
+func TestWarmup_AuthCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"Incorrect API key provided"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"m"}]}`))
+	}))
+	defer server.Close()
+	newClient := func(key string) *Client {
+		return NewClient(WithProvider(ProviderCustom), WithBaseURL(server.URL), WithAPIKey(key),
+			WithLogger(NewNoopLogger()), WithWarmupAuthCheck()).(*Client)
+	}
+
+	if report, err := newClient("sk-good-key").Warmup(context.Background()); err != nil || !report.Authenticated {
+		t.Errorf("expected authenticated warm-up, got %+v (%v)", report, err)
+	}
+	if _, err := newClient("sk-bad-key").Warmup(context.Background()); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("expected ErrInvalidAPIKey, got %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WebhookEvent type of a lifecycle event sent to the webhook
+type WebhookEvent string
+
+const (
+	WebhookCallStarted     WebhookEvent = "call.started"
+	WebhookCallSucceeded   WebhookEvent = "call.succeeded"
+	WebhookCallFailed      WebhookEvent = "call.failed"
+	WebhookBudgetThreshold WebhookEvent = "budget.threshold_crossed" // Cumulative cost reached the configured budget
+)
+
+// Webhook signature headers: HMAC-SHA256 of "<timestamp>.<body>" with the secret, hex encoded
+const (
+	headerWebhookSignature = "X-MCP-Signature"
+	headerWebhookTimestamp = "X-MCP-Timestamp"
+)
+
+// WebhookPayload JSON body of a webhook event
+type WebhookPayload struct {
+	Event     WebhookEvent      `json:"event"`
+	Timestamp time.Time         `json:"timestamp"`
+	RequestID string            `json:"request_id,omitempty"`
+	Tenant    string            `json:"tenant,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Provider  string            `json:"provider"`
+	Model     string            `json:"model"`
+	Latency   time.Duration     `json:"latency,omitempty"`
+	Usage     *TokenUsage       `json:"usage,omitempty"` // Summed over all attempts of the call
+	Cost      float64           `json:"cost,omitempty"`  // Cost of the call, or total spent for budget events
+	Error     string            `json:"error,omitempty"`
+}
+
+// WebhookNotifier delivers lifecycle events to a URL in the background
+type WebhookNotifier struct {
+	URL        string
+	Secret     string  // Signs payloads when set
+	Budget     float64 // USD; crossing it sends WebhookBudgetThreshold once (0 = disabled)
+	HTTPClient *http.Client
+
+	events map[WebhookEvent]bool // nil = all events
+
+	mu      sync.Mutex
+	spent   float64
+	running bool // Delivery goroutine active (it exits once the queue is empty)
+	queue   chan WebhookPayload
+	pending sync.WaitGroup
+}
+
+// WithWebhook POSTs JSON lifecycle events to url (no events = all events)
+//
+// One call.started and one call.succeeded or call.failed event is sent per call,
+// after its retries, with the usage and cost of all attempts. Delivery is asynchronous
+// and best effort, so a slow monitoring endpoint never delays calls; Close waits for
+// queued events. WithWebhookSecret and WithWebhookBudget may be given in any order.
+//
+// Usage example:
+//   client := mcp.NewClient(
+//       mcp.WithWebhook("https://ops.example.com/ai-events", mcp.WebhookCallFailed, mcp.WebhookBudgetThreshold),
+//       mcp.WithWebhookSecret(os.Getenv("AI_WEBHOOK_SECRET")),
+//   )
+func WithWebhook(url string, events ...WebhookEvent) ClientOption {
+	return func(c *Config) {
+		c.WebhookURL = url
+		c.WebhookEvents = events
+	}
+}
+
+// WithWebhookSecret signs webhook payloads with secret (X-MCP-Signature header)
+func WithWebhookSecret(secret string) ClientOption {
+	return func(c *Config) {
+		c.WebhookSecret = secret
+	}
+}
+
+// WithWebhookBudget sends WebhookBudgetThreshold once cumulative estimated cost reaches usd
+func WithWebhookBudget(usd float64) ClientOption {
+	return func(c *Config) {
+		c.WebhookBudget = usd
+	}
+}
+
+// newWebhookNotifier creates notifier from config (nil when no webhook is configured)
+func newWebhookNotifier(config *Config) *WebhookNotifier {
+	if config.WebhookURL == "" {
+		return nil
+	}
+	n := &WebhookNotifier{
+		URL:        config.WebhookURL,
+		Secret:     config.WebhookSecret,
+		Budget:     config.WebhookBudget,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		queue:      make(chan WebhookPayload, 256),
+	}
+	if len(config.WebhookEvents) > 0 {
+		n.events = make(map[WebhookEvent]bool, len(config.WebhookEvents))
+		for _, event := range config.WebhookEvents {
+			n.events[event] = true
+		}
+	}
+	return n
+}
+
+// webhookChanged reports whether webhook settings differ between two configs
+func webhookChanged(a, b *Config) bool {
+	return a.WebhookURL != b.WebhookURL || a.WebhookSecret != b.WebhookSecret ||
+		a.WebhookBudget != b.WebhookBudget || !slices.Equal(a.WebhookEvents, b.WebhookEvents)
+}
+
+// Sign returns signature of body sent at timestamp (for receivers verifying payloads)
+func (n *WebhookNotifier) Sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(n.Secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// emit queues payload if its event is subscribed (dropped when the queue is full)
+func (n *WebhookNotifier) emit(payload WebhookPayload) {
+	if n.events != nil && !n.events[payload.Event] {
+		return
+	}
+	payload.Timestamp = time.Now()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pending.Add(1)
+	select {
+	case n.queue <- payload:
+	default:
+		n.pending.Done()
+		return
+	}
+	if !n.running {
+		n.running = true
+		go n.run()
+	}
+}
+
+// run delivers queued payloads in order, exiting once the queue is empty
+func (n *WebhookNotifier) run() {
+	for {
+		n.mu.Lock()
+		var payload WebhookPayload
+		select {
+		case payload = <-n.queue:
+		default:
+			n.running = false
+			n.mu.Unlock()
+			return
+		}
+		n.mu.Unlock()
+		n.deliver(payload)
+		n.pending.Done()
+	}
+}
+
+// deliver POSTs one payload (errors are ignored: webhooks are best effort)
+func (n *WebhookNotifier) deliver(payload WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Secret != "" {
+		timestamp := strconv.FormatInt(payload.Timestamp.Unix(), 10)
+		req.Header.Set(headerWebhookTimestamp, timestamp)
+		req.Header.Set(headerWebhookSignature, n.Sign(timestamp, body))
+	}
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// flush waits until queued events are delivered (and the delivery goroutine exited) or ctx expires
+func (n *WebhookNotifier) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		n.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// webhookCall state of one call reported to the webhook: started with its first attempt,
+// finished once retries are over with the usage of all attempts
+type webhookCall struct {
+	client  *Client
+	start   time.Time
+	payload WebhookPayload
+	started sync.Once
+
+	mu    sync.Mutex
+	usage TokenUsage
+	cost  float64
+}
+
+// webhookCallKey context key of the call's *webhookCall
+type webhookCallKey struct{}
+
+// startWebhookCall returns ctx tracking a call for the webhook, and a function sending
+// its outcome (nothing when no request was attempted)
+func (client *Client) startWebhookCall(ctx context.Context) (context.Context, func(err error)) {
+	if client.webhook == nil {
+		return ctx, func(error) {}
+	}
+	call := &webhookCall{client: client, start: time.Now()}
+	return context.WithValue(ctx, webhookCallKey{}, call), call.finish
+}
+
+// webhookAttempt reports a request about to be sent; the returned function adds its usage.
+// Requests sent outside a tracked call are reported as calls of their own.
+func (client *Client) webhookAttempt(ctx context.Context, model string) func(usage TokenUsage, err error) {
+	if client.webhook == nil {
+		return func(TokenUsage, error) {}
+	}
+	call, ok := ctx.Value(webhookCallKey{}).(*webhookCall)
+	if !ok || call.client.webhook != client.webhook {
+		var finish func(error)
+		ctx, finish = client.startWebhookCall(ctx)
+		attempt := client.webhookAttempt(ctx, model)
+		return func(usage TokenUsage, err error) {
+			attempt(usage, err)
+			finish(err)
+		}
+	}
+	call.started.Do(func() {
+		call.payload = WebhookPayload{
+			Event:     WebhookCallStarted,
+			RequestID: RequestIDFromContext(ctx),
+			Tenant:    client.tenantFor(ctx),
+			Tags:      client.tagsFor(ctx),
+			Provider:  client.Provider,
+			Model:     model,
+		}
+		client.webhook.emit(call.payload)
+	})
+	return func(usage TokenUsage, err error) {
+		usage.Model = model
+		call.mu.Lock()
+		defer call.mu.Unlock()
+		call.usage.PromptTokens += usage.PromptTokens
+		call.usage.CompletionTokens += usage.CompletionTokens
+		call.usage.TotalTokens += usage.TotalTokens
+		call.usage.CacheReadTokens += usage.CacheReadTokens
+		call.usage.CacheWriteTokens += usage.CacheWriteTokens
+		call.cost += EstimateCost(usage)
+	}
+}
+
+// finish sends the outcome of the call, plus the budget event it triggers
+func (call *webhookCall) finish(err error) {
+	call.started.Do(func() {}) // Waits for a concurrent first attempt
+	if call.payload.Event == "" {
+		return // No request was attempted, so nothing was reported
+	}
+	n, payload := call.client.webhook, call.payload
+	call.mu.Lock()
+	usage, cost := call.usage, call.cost
+	call.mu.Unlock()
+
+	usage.Provider, usage.Model = payload.Provider, payload.Model
+	payload.Event, payload.Latency, payload.Usage, payload.Cost = WebhookCallSucceeded, time.Since(call.start), &usage, cost
+	if err != nil {
+		payload.Event, payload.Error = WebhookCallFailed, err.Error()
+	}
+	n.emit(payload)
+
+	n.mu.Lock()
+	before := n.spent
+	n.spent += cost
+	spent := n.spent
+	n.mu.Unlock()
+
+	if n.Budget > 0 && before < n.Budget && spent >= n.Budget {
+		n.emit(WebhookPayload{Event: WebhookBudgetThreshold, Tags: payload.Tags, Provider: payload.Provider, Model: payload.Model, Cost: spent})
+	}
+}
+
+// bodyModel returns model named in request body, or fallback
+func bodyModel(requestBody map[string]any, fallback string) string {
+	if model, ok := requestBody["model"].(string); ok && model != "" {
+		return model
+	}
+	return fallback
+}
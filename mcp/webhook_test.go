@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookRecorder test server collecting webhook payloads
+type webhookRecorder struct {
+	mu       sync.Mutex
+	payloads []WebhookPayload
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	var payload WebhookPayload
+	json.Unmarshal(body, &payload)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payloads = append(r.payloads, payload)
+	r.headers = append(r.headers, req.Header.Clone())
+	r.bodies = append(r.bodies, body)
+}
+
+func (r *webhookRecorder) events() []WebhookEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []WebhookEvent
+	for _, p := range r.payloads {
+		events = append(events, p.Event)
+	}
+	return events
+}
+
+func TestWebhook_SignedCallEvents(t *testing.T) {
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithWebhookSecret("s3cret"), // Before WithWebhook: options apply in any order
+		WithWebhook(server.URL),
+	).(*Client)
+
+	if _, err := client.CallWithMessages("sys", "hi"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	client.webhook.mu.Lock()
+	running := client.webhook.running
+	client.webhook.mu.Unlock()
+	if running {
+		t.Error("expected delivery goroutine to stop on Close")
+	}
+
+	events := recorder.events()
+	if len(events) != 2 || events[0] != WebhookCallStarted || events[1] != WebhookCallSucceeded {
+		t.Fatalf("unexpected events %v", events)
+	}
+	if recorder.payloads[1].Usage == nil || recorder.payloads[1].Usage.TotalTokens != 15 {
+		t.Errorf("expected usage in succeeded event, got %+v", recorder.payloads[1])
+	}
+	header := recorder.headers[1]
+	want := client.webhook.Sign(header.Get(headerWebhookTimestamp), recorder.bodies[1])
+	if header.Get(headerWebhookSignature) != want {
+		t.Errorf("signature %q, want %q", header.Get(headerWebhookSignature), want)
+	}
+}
+
+func TestWebhook_OneEventPairPerCall(t *testing.T) {
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	// Two retryable failures, then success: retries belong to the same call
+	mockHTTP := NewMockHTTPClient()
+	attempts := 0
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("connection reset")
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)),
+		}, nil
+	}
+	client := NewClient(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithMaxRetries(3),
+		WithRetryWaitBase(time.Millisecond),
+		WithWebhook(server.URL),
+	).(*Client)
+
+	if _, err := client.CallWithMessages("sys", "hi"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	client.Close(context.Background())
+
+	events := recorder.events()
+	if attempts != 3 || len(events) != 2 || events[0] != WebhookCallStarted || events[1] != WebhookCallSucceeded {
+		t.Fatalf("expected one started and one succeeded event for %d attempts, got %v", attempts, events)
+	}
+	if recorder.payloads[0].RequestID == "" || recorder.payloads[0].RequestID != recorder.payloads[1].RequestID {
+		t.Errorf("expected both events to carry the call's request ID, got %+v", recorder.payloads)
+	}
+}
+
+func TestWebhook_BudgetEvent(t *testing.T) {
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	RegisterModelPrice("webhook-test-model", ModelPrice{InputPerMillion: 0.008})
+	var bodies []map[string]any
+	client := NewClient(
+		WithHTTPClient(captureBodyClient(`{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1000000,"total_tokens":1000000}}`, &bodies)),
+		WithAPIKey("sk-test-key"),
+		WithLogger(NewNoopLogger()),
+		WithWebhookBudget(0.01), // Before WithWebhook: options apply in any order
+		WithWebhook(server.URL, WebhookBudgetThreshold),
+	).(*Client)
+	for i := 0; i < 3; i++ {
+		req := &Request{Model: "webhook-test-model", Messages: []Message{NewUserMessage("hi")}}
+		if _, err := client.Call(context.Background(), req); err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+	}
+	client.Close(context.Background())
+
+	events := recorder.events()
+	if len(events) != 1 || events[0] != WebhookBudgetThreshold {
+		t.Fatalf("expected one budget event, got %v", events)
+	}
+	if cost := recorder.payloads[0].Cost; cost < 0.0159 || cost > 0.0161 {
+		t.Errorf("expected spent total of two calls, got %v", cost)
+	}
+}